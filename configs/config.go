@@ -63,6 +63,40 @@ type YooConfig struct {
 	AccountNumber string // номер кошелька для автоматических выплат
 }
 
+type BillingConfig struct {
+	TakeRate  string // комиссия площадки по умолчанию, доля от цены показа, например "0.15"
+	Gateway   string // платёжный шлюз: yookassa (по умолчанию) или fake для тестов и стенда
+	RatesFile string // JSON с курсами валют к рублю, загружается новым снимком при старте
+}
+
+// PayoutConfig — ограничения на вывод средств; пустые значения отключают ограничение
+type PayoutConfig struct {
+	MinAmount         string // минимальная сумма заявки, по умолчанию 10.00
+	DailyLimit        string
+	MonthlyLimit      string
+	MinAccountAge     string // например "168h"
+	ApprovalThreshold string // заявки от этой суммы ждут ручного одобрения
+}
+
+// StatementConfig — хранение и оформление выписок
+type StatementConfig struct {
+	Bucket   string // бакет MinIO, по умолчанию "statements"
+	FontPath string // TTF-шрифт с кириллицей для PDF
+}
+
+// OAuthConfig — вход через внешних провайдеров. Провайдер без client id выключен
+type OAuthConfig struct {
+	RedirectURL        string // страница фронтенда, куда провайдер вернёт code; к адресу добавляется /<провайдер>
+	VKClientID         string
+	VKClientSecret     string
+	YandexClientID     string
+	YandexClientSecret string
+	OIDCName           string // имя произвольного OpenID Connect провайдера в URL, по умолчанию "oidc"
+	OIDCIssuer         string // адреса провайдера берутся из <issuer>/.well-known/openid-configuration
+	OIDCClientID       string
+	OIDCClientSecret   string
+}
+
 type GigaChatConfig struct {
 	AuthKey  string
 	ClientID string
@@ -77,6 +111,10 @@ type Config struct {
 	Scylla       ScyllaConfig
 	Yoo          YooConfig
 	GigaChat     GigaChatConfig
+	Billing      BillingConfig
+	Payout       PayoutConfig
+	Statement    StatementConfig
+	OAuth        OAuthConfig
 }
 
 func LoadConfigs() (*Config, error) {
@@ -139,6 +177,33 @@ func LoadConfigs() (*Config, error) {
 			AuthKey:  os.Getenv("GIGACHAT_AUTH_KEY"),
 			ClientID: os.Getenv("GIGACHAT_CLIENT_ID"),
 		},
+		Billing: BillingConfig{
+			TakeRate:  os.Getenv("PLATFORM_TAKE_RATE"),
+			Gateway:   os.Getenv("PAYMENT_GATEWAY"),
+			RatesFile: os.Getenv("EXCHANGE_RATES_FILE"),
+		},
+		Payout: PayoutConfig{
+			MinAmount:         os.Getenv("PAYOUT_MIN_AMOUNT"),
+			DailyLimit:        os.Getenv("PAYOUT_DAILY_LIMIT"),
+			MonthlyLimit:      os.Getenv("PAYOUT_MONTHLY_LIMIT"),
+			MinAccountAge:     os.Getenv("PAYOUT_MIN_ACCOUNT_AGE"),
+			ApprovalThreshold: os.Getenv("PAYOUT_APPROVAL_THRESHOLD"),
+		},
+		Statement: StatementConfig{
+			Bucket:   os.Getenv("STATEMENT_BUCKET"),
+			FontPath: os.Getenv("STATEMENT_FONT_PATH"),
+		},
+		OAuth: OAuthConfig{
+			RedirectURL:        os.Getenv("OAUTH_REDIRECT_URL"),
+			VKClientID:         os.Getenv("VKID_CLIENT_ID"),
+			VKClientSecret:     os.Getenv("VKID_CLIENT_SECRET"),
+			YandexClientID:     os.Getenv("YANDEX_CLIENT_ID"),
+			YandexClientSecret: os.Getenv("YANDEX_CLIENT_SECRET"),
+			OIDCName:           os.Getenv("OIDC_NAME"),
+			OIDCIssuer:         os.Getenv("OIDC_ISSUER"),
+			OIDCClientID:       os.Getenv("OIDC_CLIENT_ID"),
+			OIDCClientSecret:   os.Getenv("OIDC_CLIENT_SECRET"),
+		},
 	}
 	return &config, nil
 }
@@ -4,17 +4,117 @@ CREATE TABLE IF NOT EXISTS auth_user (
     email TEXT NOT NULL UNIQUE CHECK (email ~* '^[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}$'),
     password BYTEA NOT NULL,
     description TEXT,
-    balance INT NOT NULL DEFAULT 0,
+    balance DECIMAL(14, 2) NOT NULL DEFAULT 0.00, -- точность до копейки, как в журнале
+    promo_balance DECIMAL(14, 2) NOT NULL DEFAULT 0.00 CHECK (promo_balance >= 0), -- промо-кредит, не выводится
+    email_verified BOOLEAN NOT NULL DEFAULT FALSE, -- почта подтверждена кодом из письма
     created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC'),
     updated_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC'),
-    role SMALLINT NOT NULL -- role: 1=advertiser, 2=platform
+    role SMALLINT NOT NULL -- role: 1=advertiser, 2=platform, 3=support, 4=admin (служебные роли назначаются вручную)
 );
 
+-- раньше баланс хранился целым числом и дробные списания округлялись при каждом UPDATE
+ALTER TABLE auth_user ALTER COLUMN balance TYPE DECIMAL(14, 2);
+ALTER TABLE auth_user ADD COLUMN IF NOT EXISTS promo_balance DECIMAL(14, 2) NOT NULL DEFAULT 0.00 CHECK (promo_balance >= 0);
+-- пользователи, зарегистрированные до подтверждения почты, считаются подтверждёнными
+ALTER TABLE auth_user ADD COLUMN IF NOT EXISTS email_verified BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE auth_user ALTER COLUMN email_verified SET DEFAULT FALSE;
+
 CREATE INDEX IF NOT EXISTS idx_user_id ON auth_user(id);
 CREATE INDEX IF NOT EXISTS idx_user_balance ON auth_user(balance);
 CREATE UNIQUE INDEX IF NOT EXISTS auth_user_username_key ON auth_user (username);
 CREATE UNIQUE INDEX IF NOT EXISTS auth_user_email_key ON auth_user (email);
 
+-- журнал чувствительных изменений аккаунта (смена почты и т.п.)
+CREATE TABLE IF NOT EXISTS auth_audit_log (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES auth_user(id) ON DELETE CASCADE,
+    event TEXT NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',
+    request_id TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC')
+);
+CREATE INDEX IF NOT EXISTS idx_auth_audit_log_user_id ON auth_audit_log(user_id, created_at);
+
+-- секрет TOTP; enabled становится TRUE после ввода первого кода
+CREATE TABLE IF NOT EXISTS auth_totp (
+    user_id INT PRIMARY KEY REFERENCES auth_user(id) ON DELETE CASCADE,
+    secret TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC'),
+    enabled_at TIMESTAMP
+);
+
+-- одноразовые коды восстановления второго фактора, хранятся только sha256
+CREATE TABLE IF NOT EXISTS auth_recovery_code (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES auth_user(id) ON DELETE CASCADE,
+    code_hash TEXT NOT NULL,
+    used_at TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_auth_recovery_code_user_hash ON auth_recovery_code(user_id, code_hash);
+
+-- внешние аккаунты (VK ID, Яндекс ID, OpenID Connect), через которые можно войти;
+-- subject — постоянный идентификатор пользователя у провайдера
+CREATE TABLE IF NOT EXISTS auth_identity (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES auth_user(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    email TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC'),
+    UNIQUE (provider, subject),
+    UNIQUE (user_id, provider)
+);
+
+-- API-ключи для скриптов; сам ключ не хранится, только sha256 от него.
+-- Действующие ключи auth-service дублирует в Redis, где их проверяют сервисы
+CREATE TABLE IF NOT EXISTS auth_api_key (
+    id INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES auth_user(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL, -- начало ключа, чтобы узнать его в списке
+    key_hash TEXT NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC'),
+    revoked_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_auth_api_key_user ON auth_api_key(user_id) WHERE revoked_at IS NULL;
+
+-- организация — аккаунт auth_user без входа: ей принадлежат баннеры, слоты и
+-- баланс, а участники действуют от её имени из своих сессий
+ALTER TABLE auth_user ADD COLUMN IF NOT EXISTS is_organization BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS organization (
+    account_id INT PRIMARY KEY REFERENCES auth_user(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    created_by INT REFERENCES auth_user(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC')
+);
+
+CREATE TABLE IF NOT EXISTS organization_member (
+    org_id INT NOT NULL REFERENCES organization(account_id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES auth_user(id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('owner', 'manager', 'analyst', 'billing')),
+    created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC'),
+    PRIMARY KEY (org_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_organization_member_user ON organization_member(user_id);
+
+-- приглашения по почте; токен хранится только sha256
+CREATE TABLE IF NOT EXISTS organization_invite (
+    id INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    org_id INT NOT NULL REFERENCES organization(account_id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    role TEXT NOT NULL CHECK (role IN ('owner', 'manager', 'analyst', 'billing')),
+    token_hash TEXT NOT NULL UNIQUE,
+    invited_by INT REFERENCES auth_user(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC'),
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_organization_invite_org ON organization_invite(org_id) WHERE accepted_at IS NULL;
+
 CREATE TABLE IF NOT EXISTS banner (
     id INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
     owner_id INT NOT NULL REFERENCES auth_user(id) ON DELETE CASCADE,
@@ -24,6 +124,7 @@ CREATE TABLE IF NOT EXISTS banner (
     content TEXT NOT NULL,
     link TEXT NOT NULL,
     max_price DECIMAL(12,2) NOT NULL DEFAULT 0.00,
+    currency CHAR(3) NOT NULL DEFAULT 'RUB', -- валюта ставки max_price
     deleted BOOLEAN NOT NULL DEFAULT FALSE,
     status SMALLINT
 );
@@ -50,9 +151,310 @@ CREATE TABLE IF NOT EXISTS transaction (
     amount DECIMAL(12,2) NOT NULL,
     type TEXT NOT NULL,
     status TEXT NOT NULL,
+    currency CHAR(3) NOT NULL DEFAULT 'RUB',
+    swept_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC')
+);
+-- swept_at — когда фоновая сверка последний раз спрашивала статус у шлюза
+ALTER TABLE transaction ADD COLUMN IF NOT EXISTS swept_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_transaction_user_id ON transaction(user_id, created_at);
+DROP INDEX IF EXISTS idx_transaction_pending;
+CREATE INDEX IF NOT EXISTS idx_transaction_pending_sweep ON transaction(swept_at NULLS FIRST, created_at) WHERE status = '0';
+
+-- ответы на запросы с заголовком Idempotency-Key; status_code пустой, пока запрос выполняется
+-- actor_id — участник организации, отправивший запрос; 0 — запрос от своего имени
+CREATE TABLE IF NOT EXISTS idempotency_key (
+    user_id INT NOT NULL REFERENCES auth_user(id) ON DELETE CASCADE,
+    actor_id INT NOT NULL DEFAULT 0,
+    key TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    status_code SMALLINT,
+    content_type TEXT,
+    response_body BYTEA,
+    created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC'),
+    PRIMARY KEY (user_id, actor_id, key)
+);
+ALTER TABLE idempotency_key ADD COLUMN IF NOT EXISTS actor_id INT NOT NULL DEFAULT 0;
+ALTER TABLE idempotency_key DROP CONSTRAINT IF EXISTS idempotency_key_pkey, ADD PRIMARY KEY (user_id, actor_id, key);
+CREATE INDEX IF NOT EXISTS idx_idempotency_key_created_at ON idempotency_key(created_at);
+
+-- пачки списаний из бюджетного кэша Redis, уже перенесённые в auth_user.balance
+CREATE TABLE IF NOT EXISTS budget_batch (
+    batch_id TEXT PRIMARY KEY,
+    applied_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC')
+);
+
+-- журнал двойной записи: у системных счетов (комиссия, шлюз) owner_id пустой
+CREATE TABLE IF NOT EXISTS ledger_account (
+    id SERIAL PRIMARY KEY,
+    owner_id INT REFERENCES auth_user(id) ON DELETE RESTRICT,
+    type TEXT NOT NULL CHECK (type IN ('advertiser', 'publisher', 'platform_fee', 'gateway', 'payout_hold', 'exchange', 'promo', 'marketing', 'adjustment')),
+    currency CHAR(3) NOT NULL DEFAULT 'RUB',
+    created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC'),
+    UNIQUE NULLS NOT DISTINCT (owner_id, type, currency)
+);
+
+CREATE TABLE IF NOT EXISTS ledger_entry (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    kind TEXT NOT NULL CHECK (kind IN ('topup', 'impression_charge', 'payout', 'refund', 'payout_hold', 'payout_release', 'conversion', 'promo_credit', 'adjustment')),
+    reference TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC')
+);
+
+CREATE TABLE IF NOT EXISTS ledger_posting (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    entry_id BIGINT NOT NULL REFERENCES ledger_entry(id) ON DELETE RESTRICT,
+    account_id INT NOT NULL REFERENCES ledger_account(id) ON DELETE RESTRICT,
+    amount DECIMAL(14, 2) NOT NULL CHECK (amount <> 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_posting_entry_id ON ledger_posting(entry_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_posting_account_id ON ledger_posting(account_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entry_reference ON ledger_entry(reference);
+CREATE INDEX IF NOT EXISTS idx_ledger_entry_created_at ON ledger_entry(created_at);
+
+-- баннер и слот списания за показ, по ним строятся разбивки в выписках
+CREATE TABLE IF NOT EXISTS ledger_entry_placement (
+    entry_id BIGINT PRIMARY KEY REFERENCES ledger_entry(id) ON DELETE RESTRICT,
+    banner_id BIGINT NOT NULL DEFAULT 0,
+    slot_link TEXT NOT NULL DEFAULT ''
+);
+
+-- снимки таблицы курсов: курс — сколько базовой валюты (RUB) стоит единица currency;
+-- действует последний снимок, старые нужны, чтобы восстановить прошлые пересчёты
+CREATE TABLE IF NOT EXISTS currency_rate_snapshot (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    source TEXT NOT NULL,
+    created_by INT REFERENCES auth_user(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC')
+);
+
+CREATE TABLE IF NOT EXISTS currency_rate (
+    snapshot_id BIGINT NOT NULL REFERENCES currency_rate_snapshot(id) ON DELETE RESTRICT,
+    currency CHAR(3) NOT NULL,
+    rate DECIMAL(18, 6) NOT NULL CHECK (rate > 0),
+    PRIMARY KEY (snapshot_id, currency)
+);
+
+-- пересчёт, по которому получена сумма проводки: ставка в другой валюте или обмен
+CREATE TABLE IF NOT EXISTS ledger_entry_conversion (
+    entry_id BIGINT PRIMARY KEY REFERENCES ledger_entry(id) ON DELETE RESTRICT,
+    snapshot_id BIGINT NOT NULL REFERENCES currency_rate_snapshot(id) ON DELETE RESTRICT,
+    from_currency CHAR(3) NOT NULL,
+    original_amount DECIMAL(14, 2) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    amount DECIMAL(14, 2) NOT NULL,
+    rate DECIMAL(18, 6) NOT NULL
+);
+
+-- индивидуальная комиссия площадки для владельца слотов; без записи действует общая
+CREATE TABLE IF NOT EXISTS publisher_take_rate (
+    user_id INT PRIMARY KEY REFERENCES auth_user(id) ON DELETE CASCADE,
+    take_rate DECIMAL(5, 4) NOT NULL CHECK (take_rate >= 0 AND take_rate < 1),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC')
+);
+
+-- заявки на вывод средств; сумма заявки удерживается с баланса до отправки или отказа
+CREATE TABLE IF NOT EXISTS payout_request (
+    id INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES auth_user(id) ON DELETE RESTRICT,
+    amount DECIMAL(12,2) NOT NULL CHECK (amount > 0),
+    destination_type TEXT NOT NULL,
+    destination TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL CHECK (status IN ('requested', 'approved', 'sent', 'failed', 'returned')),
+    gateway_id TEXT,
+    failure_reason TEXT NOT NULL DEFAULT '',
+    reviewed_by INT REFERENCES auth_user(id) ON DELETE SET NULL,
+    approved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC'),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC')
+);
+-- approved_at ограничивает окно повторной отправки одобренных выплат
+ALTER TABLE payout_request ADD COLUMN IF NOT EXISTS approved_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_payout_request_user_id ON payout_request(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_payout_request_status ON payout_request(status);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payout_request_gateway_id ON payout_request(gateway_id);
+
+-- возвраты пополнений и отмены списаний за показы; original_id — transaction_id
+-- пополнения или ID проводки ledger_entry
+CREATE TABLE IF NOT EXISTS refund (
+    id INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    kind TEXT NOT NULL CHECK (kind IN ('topup', 'impression')),
+    original_id TEXT NOT NULL,
+    user_id INT NOT NULL REFERENCES auth_user(id) ON DELETE RESTRICT,
+    publisher_id INT REFERENCES auth_user(id) ON DELETE RESTRICT,
+    amount DECIMAL(12,2) NOT NULL CHECK (amount > 0),
+    status TEXT NOT NULL CHECK (status IN ('pending', 'succeeded', 'canceled')),
+    gateway_id TEXT,
+    reason TEXT NOT NULL DEFAULT '',
+    created_by INT NOT NULL REFERENCES auth_user(id) ON DELETE RESTRICT,
+    created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC')
+);
+CREATE INDEX IF NOT EXISTS idx_refund_original ON refund(kind, original_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refund_impression_once ON refund(original_id) WHERE kind = 'impression';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refund_gateway_id ON refund(gateway_id);
+
+-- выписки за период; сами файлы (JSON, CSV, PDF) лежат в MinIO под object_prefix
+CREATE TABLE IF NOT EXISTS statement (
+    id INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES auth_user(id) ON DELETE CASCADE,
+    period_start TIMESTAMP NOT NULL,
+    period_end TIMESTAMP NOT NULL,
+    opening_balance DECIMAL(14, 2) NOT NULL,
+    closing_balance DECIMAL(14, 2) NOT NULL,
+    object_prefix TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC'),
+    UNIQUE (user_id, period_start)
+);
+
+-- промокоды: фиксированный кредит или процент от пополнения; нулевые
+-- max_redemptions и per_user_limit означают «без ограничений»
+CREATE TABLE IF NOT EXISTS promo_code (
+    id INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    code TEXT NOT NULL UNIQUE,
+    kind TEXT NOT NULL CHECK (kind IN ('fixed', 'percent')),
+    value DECIMAL(14, 2) NOT NULL CHECK (value > 0),
+    max_bonus DECIMAL(14, 2) CHECK (max_bonus > 0),
+    max_redemptions INT NOT NULL DEFAULT 0 CHECK (max_redemptions >= 0),
+    per_user_limit INT NOT NULL DEFAULT 1 CHECK (per_user_limit >= 0),
+    redemptions INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by INT REFERENCES auth_user(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC')
+);
+
+-- аудит погашений: каждая строка — начисленный кредит и его проводка в журнале
+CREATE TABLE IF NOT EXISTS promo_redemption (
+    id INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    promo_code_id INT NOT NULL REFERENCES promo_code(id) ON DELETE RESTRICT,
+    user_id INT NOT NULL REFERENCES auth_user(id) ON DELETE RESTRICT,
+    amount DECIMAL(14, 2) NOT NULL CHECK (amount > 0),
+    top_up_amount DECIMAL(14, 2),
+    transaction_id TEXT,
+    entry_id BIGINT NOT NULL REFERENCES ledger_entry(id) ON DELETE RESTRICT,
+    created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC')
+);
+CREATE INDEX IF NOT EXISTS idx_promo_redemption_code_user ON promo_redemption(promo_code_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_promo_redemption_user ON promo_redemption(user_id, created_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_promo_redemption_transaction ON promo_redemption(transaction_id);
+
+-- код, указанный при создании платежа; бонус начисляется при зачислении пополнения
+CREATE TABLE IF NOT EXISTS promo_topup (
+    transaction_id TEXT PRIMARY KEY,
+    code TEXT NOT NULL,
+    user_id INT NOT NULL REFERENCES auth_user(id) ON DELETE CASCADE,
     created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC')
 );
-CREATE INDEX IF NOT EXISTS idx_transaction_user_id ON transaction(user_id);
+
+-- пороги уведомлений о низком балансе и автопауза баннеров; без строки
+-- действуют настройки по умолчанию (порог 100.00, пауза ниже 10.00)
+CREATE TABLE IF NOT EXISTS balance_alert_settings (
+    user_id INT PRIMARY KEY REFERENCES auth_user(id) ON DELETE CASCADE,
+    thresholds DECIMAL(14, 2)[] NOT NULL DEFAULT '{}',
+    auto_pause BOOLEAN NOT NULL DEFAULT TRUE,
+    pause_floor DECIMAL(14, 2) NOT NULL DEFAULT 10.00 CHECK (pause_floor >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC')
+);
+
+-- пороги, о которых пользователь уже уведомлён; строки удаляются пополнением
+CREATE TABLE IF NOT EXISTS balance_alert_crossing (
+    user_id INT NOT NULL REFERENCES auth_user(id) ON DELETE CASCADE,
+    threshold DECIMAL(14, 2) NOT NULL,
+    crossed_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC'),
+    PRIMARY KEY (user_id, threshold)
+);
+
+-- способы оплаты, сохранённые в YooKassa; списывать ими можно после того,
+-- как прошёл платёж transaction_id, при котором способ был сохранён
+CREATE TABLE IF NOT EXISTS payment_method (
+    id TEXT PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES auth_user(id) ON DELETE CASCADE,
+    title TEXT NOT NULL DEFAULT '',
+    transaction_id TEXT NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC')
+);
+CREATE INDEX IF NOT EXISTS idx_payment_method_user ON payment_method(user_id);
+CREATE INDEX IF NOT EXISTS idx_payment_method_transaction ON payment_method(transaction_id);
+
+-- автопополнение: когда баланс ниже threshold, списать amount сохранённым
+-- способом, но не больше monthly_cap за календарный месяц. pending_transaction_id
+-- не пуст, пока списание не завершилось
+CREATE TABLE IF NOT EXISTS auto_recharge (
+    user_id INT PRIMARY KEY REFERENCES auth_user(id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    threshold DECIMAL(14, 2) NOT NULL CHECK (threshold > 0),
+    amount DECIMAL(14, 2) NOT NULL CHECK (amount > 0),
+    monthly_cap DECIMAL(14, 2) NOT NULL CHECK (monthly_cap >= amount),
+    payment_method_id TEXT REFERENCES payment_method(id) ON DELETE SET NULL,
+    failures INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP,
+    pending_transaction_id TEXT,
+    last_error TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC')
+);
+
+-- расхождения между auth_user.balance и журналом, найденные фоновой сверкой
+CREATE TABLE IF NOT EXISTS ledger_drift (
+    id SERIAL PRIMARY KEY,
+    owner_id INT NOT NULL REFERENCES auth_user(id) ON DELETE CASCADE,
+    ledger_balance DECIMAL(14, 2) NOT NULL,
+    user_balance DECIMAL(14, 2) NOT NULL,
+    detected_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC')
+);
+
+-- записи журнала не редактируются и не удаляются, исправления идут новой проводкой
+CREATE OR REPLACE FUNCTION ledger_forbid_change()
+RETURNS TRIGGER AS $function$
+BEGIN
+    RAISE EXCEPTION 'ledger is append-only: % on % is not allowed', TG_OP, TG_TABLE_NAME;
+END;
+$function$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ledger_entry_immutable
+BEFORE UPDATE OR DELETE ON ledger_entry
+FOR EACH ROW
+EXECUTE FUNCTION ledger_forbid_change();
+
+CREATE TRIGGER ledger_posting_immutable
+BEFORE UPDATE OR DELETE ON ledger_posting
+FOR EACH ROW
+EXECUTE FUNCTION ledger_forbid_change();
+
+-- сумма проводки проверяется при коммите, когда записаны все её стороны
+CREATE OR REPLACE FUNCTION ledger_check_balanced()
+RETURNS TRIGGER AS $function$
+DECLARE
+    unbalanced TEXT;
+    postings INT;
+BEGIN
+    SELECT COUNT(*) INTO postings
+    FROM ledger_posting
+    WHERE entry_id = NEW.entry_id;
+
+    -- каждая валюта проводки сходится отдельно
+    SELECT a.currency INTO unbalanced
+    FROM ledger_posting p
+    JOIN ledger_account a ON a.id = p.account_id
+    WHERE p.entry_id = NEW.entry_id
+    GROUP BY a.currency
+    HAVING SUM(p.amount) <> 0
+    LIMIT 1;
+
+    IF postings < 2 OR unbalanced IS NOT NULL THEN
+        RAISE EXCEPTION 'ledger entry % is not balanced: % postings, currency %', NEW.entry_id, postings, unbalanced;
+    END IF;
+    RETURN NULL;
+END;
+$function$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER ledger_posting_balanced
+AFTER INSERT ON ledger_posting
+DEFERRABLE INITIALLY DEFERRED
+FOR EACH ROW
+EXECUTE FUNCTION ledger_check_balanced();
 
 DO $$
 BEGIN
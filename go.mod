@@ -8,6 +8,7 @@ require (
 	github.com/alicebob/miniredis/v2 v2.34.0
 	github.com/aymerick/raymond v2.0.2+incompatible
 	github.com/cenkalti/backoff v2.2.1+incompatible
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/go-playground/validator/v10 v10.26.0
 	github.com/go-redis/redismock/v9 v9.2.0
 	github.com/gocql/gocql v1.7.0
@@ -14,8 +14,8 @@ import (
 
 // AdvUsecaseInterface определяет интерфейс для случая использования рекламы
 type AdvUsecaseInterface interface {
-	WriteMetric(bannerID int, slotLink string, action string) error
-	GetIframe(secretLink string) (*pb.Banner, error)
+	WriteMetric(bannerID int, slotLink string, action string, reservationID string) error
+	GetIframe(secretLink string) (*pb.Banner, string, error)
 	GetSlotMetric(slotID string, activity string, userID int, from, to time.Time) (interface{}, error)
 	GetSlotCTR(slotID string, activity string, userID int, from, to time.Time) (interface{}, error)
 	GetSlotRevenue(slotID string, activity string, userID int, from, to time.Time) (interface{}, error)
@@ -41,7 +41,7 @@ func SetupAdvRoutes(authenticator *authenticate.Authenticator, advUsecase *useca
 
 	muxRouter.Handle("/api/v1/adv/iframe/{link}", advMiddleware(http.HandlerFunc(advController.IframeHandler))).Methods("GET")
 	muxRouter.Handle("/api/v1/adv/metrics/", http.HandlerFunc(advController.MetricsHandler)).Methods("GET")
-	muxRouter.Handle("/api/v1/adv/my-metrics", authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(advController.MyMetricsHandler))).Methods("GET")
+	muxRouter.Handle("/api/v1/adv/my-metrics", authenticate.ScopedAuthMiddleware(authenticator, authenticate.ScopeMetricsRead)(authenticate.Require(authenticate.PermMetrics)(http.HandlerFunc(advController.MyMetricsHandler)))).Methods("GET")
 
 	return muxRouter
 }
@@ -0,0 +1,19 @@
+package adv
+
+import (
+	"net/http"
+	"testing"
+
+	usecaseAdv "retarget/internal/adv-service/usecase/adv"
+	usecaseSlot "retarget/internal/adv-service/usecase/slot"
+	authenticate "retarget/pkg/middleware/auth"
+	"retarget/pkg/middleware/auth/authtest"
+)
+
+func TestSetupAdvRoutes_AccessMatrix(t *testing.T) {
+	router := SetupAdvRoutes(authtest.Authenticator(t), &usecaseAdv.AdvUsecase{}, &usecaseSlot.SlotUsecase{})
+
+	authtest.AssertRoutes(t, router, []authtest.Route{
+		{Method: http.MethodGet, Path: "/api/v1/adv/my-metrics", Roles: []int{authenticate.RoleAdvertiser, authenticate.RolePlatform}},
+	})
+}
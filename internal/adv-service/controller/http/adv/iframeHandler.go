@@ -17,7 +17,7 @@ func (c *AdvController) IframeHandler(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
 	debug := query.Get("debug")
 	secret_link := vars["link"]
-	banner, err := c.advUsecase.GetIframe(secret_link)
+	banner, reservation, err := c.advUsecase.GetIframe(secret_link)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		if encodeErr := json.NewEncoder(w).Encode(entity.NewResponse(true, err.Error())); encodeErr != nil {
@@ -31,6 +31,7 @@ func (c *AdvController) IframeHandler(w http.ResponseWriter, r *http.Request) {
 	if debug != "" {
 		secret_link = ""
 		bannerID = -1
+		reservation = ""
 	}
 	data := model.IFrame{
 		ImageSrc:    "https://re-target.ru/api/v1/banner/image/" + banner.Content,
@@ -39,6 +40,7 @@ func (c *AdvController) IframeHandler(w http.ResponseWriter, r *http.Request) {
 		Description: banner.Description,
 		Banner:      bannerID,
 		Slot:        secret_link,
+		Reservation: reservation,
 	}
 	if err := tmpl.Execute(w, data); err != nil {
 		log.Println("template execute error:", err)
@@ -18,6 +18,7 @@ func (c *AdvController) MetricsHandler(w http.ResponseWriter, r *http.Request) {
 	action := query.Get("action")
 	bannerIDstr := query.Get("banner")
 	slot := query.Get("slot")
+	reservation := query.Get("reservation")
 
 	bannerID, err := strconv.Atoi(bannerIDstr)
 	if err != nil {
@@ -27,7 +28,7 @@ func (c *AdvController) MetricsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err = c.advUsecase.WriteMetric(bannerID, slot, action); err != nil {
+	if err = c.advUsecase.WriteMetric(bannerID, slot, action, reservation); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		//nolint:errcheck
 		json.NewEncoder(w).Encode(entity.NewResponse(true, err.Error()))
@@ -3,10 +3,12 @@ package slot
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"retarget/internal/adv-service/dto"
 	model "retarget/internal/adv-service/easyjsonModels"
+	"retarget/internal/adv-service/usecase/slot"
 	"retarget/pkg/entity"
 	response "retarget/pkg/entity"
 
@@ -35,7 +37,11 @@ func (c *SlotController) CreateSlotHandler(w http.ResponseWriter, r *http.Reques
 	if err != nil {
 		//nolint:errcheck
 		response := entity.NewResponseWithBody(true, err.Error(), nil)
-		w.WriteHeader(http.StatusInternalServerError)
+		if errors.Is(err, slot.ErrValidation) {
+			w.WriteHeader(http.StatusBadRequest)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
 		//nolint:errcheck
 		json.NewEncoder(w).Encode(response)
 		return
@@ -46,6 +52,7 @@ func (c *SlotController) CreateSlotHandler(w http.ResponseWriter, r *http.Reques
 		SlotName:   createdSlot.SlotName,
 		FormatCode: createdSlot.FormatCode,
 		MinPrice:   createdSlot.MinPrice.String(),
+		Currency:   createdSlot.Currency,
 		IsActive:   createdSlot.IsActive,
 		CreatedAt:  createdSlot.CreatedAt,
 	}
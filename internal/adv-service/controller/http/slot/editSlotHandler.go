@@ -47,6 +47,7 @@ func (c *SlotController) EditSlotHandler(w http.ResponseWriter, r *http.Request)
 		SlotName:   updatedSlot.SlotName,
 		FormatCode: updatedSlot.FormatCode,
 		MinPrice:   updatedSlot.MinPrice.String(),
+		Currency:   updatedSlot.Currency,
 		IsActive:   updatedSlot.IsActive,
 	}
 
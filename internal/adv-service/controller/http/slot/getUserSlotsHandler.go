@@ -39,6 +39,7 @@ func (c *SlotController) GetUserSlotsHandler(w http.ResponseWriter, r *http.Requ
 			SlotName:   s.SlotName,
 			FormatCode: s.FormatCode,
 			MinPrice:   s.MinPrice,
+			Currency:   s.Currency,
 			IsActive:   s.IsActive,
 			CreatedAt:  s.CreatedAt,
 		}
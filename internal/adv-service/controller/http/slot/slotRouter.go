@@ -20,13 +20,20 @@ func SetupSlotRoutes(authenticator *authenticate.Authenticator, slotUsecase *use
 	muxRouter := mux.NewRouter()
 	slotController := NewSlotController(slotUsecase)
 
-	muxRouter.Use(authenticate.AuthMiddleware(authenticator))
-
-	muxRouter.Handle("/api/v1/slot/create", http.HandlerFunc(slotController.CreateSlotHandler)).Methods("POST")
-	muxRouter.Handle("/api/v1/slot/edit", http.HandlerFunc(slotController.EditSlotHandler)).Methods("PUT")
-	muxRouter.Handle("/api/v1/slot/delete", http.HandlerFunc(slotController.DeleteSlotHandler)).Methods("DELETE")
-	muxRouter.Handle("/api/v1/slot/my", http.HandlerFunc(slotController.GetUserSlotsHandler)).Methods("GET")
-	muxRouter.Handle("/api/v1/slot/formats", http.HandlerFunc(slotController.GetFormatsHandler)).Methods("GET")
+	// рекламные места ведут площадки
+	platforms := authenticate.Require(authenticate.PermSlots)
+	read := func(h http.Handler) http.Handler {
+		return authenticate.ScopedAuthMiddleware(authenticator, authenticate.ScopeSlotsRead)(platforms(h))
+	}
+	write := func(h http.Handler) http.Handler {
+		return authenticate.ScopedAuthMiddleware(authenticator, authenticate.ScopeSlotsWrite)(platforms(h))
+	}
+
+	muxRouter.Handle("/api/v1/slot/create", write(authenticate.RequireVerified(http.HandlerFunc(slotController.CreateSlotHandler)))).Methods("POST")
+	muxRouter.Handle("/api/v1/slot/edit", write(http.HandlerFunc(slotController.EditSlotHandler))).Methods("PUT")
+	muxRouter.Handle("/api/v1/slot/delete", write(http.HandlerFunc(slotController.DeleteSlotHandler))).Methods("DELETE")
+	muxRouter.Handle("/api/v1/slot/my", read(http.HandlerFunc(slotController.GetUserSlotsHandler))).Methods("GET")
+	muxRouter.Handle("/api/v1/slot/formats", read(http.HandlerFunc(slotController.GetFormatsHandler))).Methods("GET")
 
 	return muxRouter
 }
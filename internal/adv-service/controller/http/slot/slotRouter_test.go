@@ -0,0 +1,23 @@
+package slot
+
+import (
+	"net/http"
+	"testing"
+
+	usecaseSlot "retarget/internal/adv-service/usecase/slot"
+	authenticate "retarget/pkg/middleware/auth"
+	"retarget/pkg/middleware/auth/authtest"
+)
+
+func TestSetupSlotRoutes_AccessMatrix(t *testing.T) {
+	router := SetupSlotRoutes(authtest.Authenticator(t), &usecaseSlot.SlotUsecase{})
+	platform := []int{authenticate.RolePlatform}
+
+	authtest.AssertRoutes(t, router, []authtest.Route{
+		{Method: http.MethodPost, Path: "/api/v1/slot/create", Roles: platform},
+		{Method: http.MethodPut, Path: "/api/v1/slot/edit", Roles: platform},
+		{Method: http.MethodDelete, Path: "/api/v1/slot/delete", Roles: platform},
+		{Method: http.MethodGet, Path: "/api/v1/slot/my", Roles: platform},
+		{Method: http.MethodGet, Path: "/api/v1/slot/formats", Roles: platform},
+	})
+}
@@ -9,6 +9,7 @@ type CreateRequest struct {
 	SlotName   string  `json:"slot_name" validate:"required,min=1,max=100"`
 	FormatCode int     `json:"format_code" validate:"required,min=1"`
 	MinPrice   inf.Dec `json:"min_price" validate:"required"`
+	Currency   string  `json:"currency"`
 	IsActive   bool    `json:"is_active" validate:"required"`
 }
 
@@ -17,5 +18,6 @@ type UpdateRequest struct {
 	SlotName   string    `json:"slot_name" validate:"required,min=1,max=100"`
 	FormatCode int       `json:"format_code" validate:"required"`
 	MinPrice   inf.Dec   `json:"min_price" validate:"required"`
+	Currency   string    `json:"currency"`
 	IsActive   bool      `json:"is_active" validate:"required"`
 }
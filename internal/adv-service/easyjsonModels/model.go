@@ -16,6 +16,7 @@ type IFrame struct {
 	Description string
 	Banner      int64
 	Slot        string
+	Reservation string
 }
 
 type CreateSlotResponse struct {
@@ -23,6 +24,7 @@ type CreateSlotResponse struct {
 	SlotName   string    `json:"slot_name"`
 	FormatCode int       `json:"format_code"`
 	MinPrice   string    `json:"min_price"`
+	Currency   string    `json:"currency"`
 	IsActive   bool      `json:"is_active"`
 	CreatedAt  time.Time `json:"created_at"`
 }
@@ -32,6 +34,7 @@ type GetSlotResponse struct {
 	SlotName   string    `json:"slot_name"`
 	FormatCode int       `json:"format_code"`
 	MinPrice   inf.Dec   `json:"min_price"`
+	Currency   string    `json:"currency"`
 	IsActive   bool      `json:"is_active"`
 	CreatedAt  time.Time `json:"created_at"`
 }
@@ -41,6 +44,7 @@ type EditSlotResponse struct {
 	SlotName   string `json:"slot_name"`
 	FormatCode int    `json:"format_code"`
 	MinPrice   string `json:"min_price"`
+	Currency   string `json:"currency"`
 	IsActive   bool   `json:"is_active"`
 }
 
@@ -224,6 +224,8 @@ func easyjsonC80ae7adDecodeRetargetInternalAdvServiceEasyjsonModels2(in *jlexer.
 			out.Banner = int64(in.Int64())
 		case "Slot":
 			out.Slot = string(in.String())
+		case "Reservation":
+			out.Reservation = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -268,6 +270,11 @@ func easyjsonC80ae7adEncodeRetargetInternalAdvServiceEasyjsonModels2(out *jwrite
 		out.RawString(prefix)
 		out.String(string(in.Slot))
 	}
+	{
+		const prefix string = ",\"Reservation\":"
+		out.RawString(prefix)
+		out.String(string(in.Reservation))
+	}
 	out.RawByte('}')
 }
 
@@ -323,6 +330,8 @@ func easyjsonC80ae7adDecodeRetargetInternalAdvServiceEasyjsonModels3(in *jlexer.
 			if data := in.UnsafeBytes(); in.Ok() {
 				in.AddError((out.MinPrice).UnmarshalText(data))
 			}
+		case "currency":
+			out.Currency = string(in.String())
 		case "is_active":
 			out.IsActive = bool(in.Bool())
 		case "created_at":
@@ -363,6 +372,11 @@ func easyjsonC80ae7adEncodeRetargetInternalAdvServiceEasyjsonModels3(out *jwrite
 		out.RawString(prefix)
 		out.RawText((in.MinPrice).MarshalText())
 	}
+	{
+		const prefix string = ",\"currency\":"
+		out.RawString(prefix)
+		out.String(string(in.Currency))
+	}
 	{
 		const prefix string = ",\"is_active\":"
 		out.RawString(prefix)
@@ -426,6 +440,8 @@ func easyjsonC80ae7adDecodeRetargetInternalAdvServiceEasyjsonModels4(in *jlexer.
 			out.FormatCode = int(in.Int())
 		case "min_price":
 			out.MinPrice = string(in.String())
+		case "currency":
+			out.Currency = string(in.String())
 		case "is_active":
 			out.IsActive = bool(in.Bool())
 		default:
@@ -462,6 +478,11 @@ func easyjsonC80ae7adEncodeRetargetInternalAdvServiceEasyjsonModels4(out *jwrite
 		out.RawString(prefix)
 		out.String(string(in.MinPrice))
 	}
+	{
+		const prefix string = ",\"currency\":"
+		out.RawString(prefix)
+		out.String(string(in.Currency))
+	}
 	{
 		const prefix string = ",\"is_active\":"
 		out.RawString(prefix)
@@ -520,6 +541,8 @@ func easyjsonC80ae7adDecodeRetargetInternalAdvServiceEasyjsonModels5(in *jlexer.
 			out.FormatCode = int(in.Int())
 		case "min_price":
 			out.MinPrice = string(in.String())
+		case "currency":
+			out.Currency = string(in.String())
 		case "is_active":
 			out.IsActive = bool(in.Bool())
 		case "created_at":
@@ -560,6 +583,11 @@ func easyjsonC80ae7adEncodeRetargetInternalAdvServiceEasyjsonModels5(out *jwrite
 		out.RawString(prefix)
 		out.String(string(in.MinPrice))
 	}
+	{
+		const prefix string = ",\"currency\":"
+		out.RawString(prefix)
+		out.String(string(in.Currency))
+	}
 	{
 		const prefix string = ",\"is_active\":"
 		out.RawString(prefix)
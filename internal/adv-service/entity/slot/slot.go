@@ -12,6 +12,7 @@ type Slot struct {
 	SlotName   string    `json:"slot_name" validate:"required,min=1,max=100"`
 	FormatCode int       `json:"format_code" validate:"required,min=1"`
 	MinPrice   inf.Dec   `json:"min_price" validate:"required,min=0"`
+	Currency   string    `json:"currency"` // валюта MinPrice, пустая у старых слотов — рубли
 	IsActive   bool      `json:"is_active" validate:"required"`
 	CreatedAt  time.Time `json:"created_at"`
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log"
 	"retarget/internal/adv-service/entity/slot"
+	"retarget/pkg/entity"
 	"strings"
 	"time"
 
@@ -95,9 +96,9 @@ func NewSlotRepository(host string, port int, keyspace, username, password strin
 func (r *SlotRepository) CreateSlot(ctx context.Context, userID int, s slot.Slot) (slot.Slot, error) {
 	batch := r.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
 	batch.Query(
-		`INSERT INTO slots (link, user_id, slot_name, format_code, min_price, is_active, created_at) 
-		VALUES (?, ?, ?, ?, ?, ?, ?)`,
-		s.Link, userID, s.SlotName, s.FormatCode, s.MinPrice, s.IsActive, s.CreatedAt,
+		`INSERT INTO slots (link, user_id, slot_name, format_code, min_price, currency, is_active, created_at) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		s.Link, userID, s.SlotName, s.FormatCode, s.MinPrice, s.Currency, s.IsActive, s.CreatedAt,
 	)
 	batch.Query(
 		`INSERT INTO user_links (user_id, created_at, link) VALUES (?, ?, ?)`,
@@ -119,9 +120,10 @@ func (r *SlotRepository) UpdateSlot(ctx context.Context, userID int, s slot.Slot
 			slot_name = ?, 
 			format_code = ?, 
 			min_price = ?, 
+			currency = ?, 
 			is_active = ?
 		WHERE link = ? IF user_id = ?`,
-		s.SlotName, s.FormatCode, s.MinPrice, s.IsActive, s.Link, userID,
+		s.SlotName, s.FormatCode, s.MinPrice, s.Currency, s.IsActive, s.Link, userID,
 	).WithContext(ctx).Scan(&applied, &current_user)
 
 	if err != nil {
@@ -170,7 +172,7 @@ func (r *SlotRepository) GetSlotsByUser(ctx context.Context, userID int) ([]slot
 	}
 
 	iter = r.session.Query(
-		`SELECT link, slot_name, format_code, min_price, is_active, created_at 
+		`SELECT link, slot_name, format_code, min_price, currency, is_active, created_at 
 		FROM slots WHERE link IN ?`,
 		links,
 	).WithContext(ctx).Iter()
@@ -178,7 +180,8 @@ func (r *SlotRepository) GetSlotsByUser(ctx context.Context, userID int) ([]slot
 
 	var slots []slot.Slot
 	var s slot.Slot
-	for iter.Scan(&s.Link, &s.SlotName, &s.FormatCode, &s.MinPrice, &s.IsActive, &s.CreatedAt) {
+	for iter.Scan(&s.Link, &s.SlotName, &s.FormatCode, &s.MinPrice, &s.Currency, &s.IsActive, &s.CreatedAt) {
+		s.Currency = slotCurrency(s.Currency)
 		slots = append(slots, s)
 	}
 
@@ -220,7 +223,7 @@ func (r *SlotRepository) GetSlotInfoByLink(ctx context.Context, link string) (sl
 	var s slot.Slot
 
 	err := r.session.Query(
-		`SELECT link, user_id, slot_name, format_code, min_price, is_active, created_at 
+		`SELECT link, user_id, slot_name, format_code, min_price, currency, is_active, created_at 
 		FROM slots WHERE link = ? LIMIT 1`,
 		link,
 	).WithContext(ctx).Scan(
@@ -229,6 +232,7 @@ func (r *SlotRepository) GetSlotInfoByLink(ctx context.Context, link string) (sl
 		&s.SlotName,
 		&s.FormatCode,
 		&s.MinPrice,
+		&s.Currency,
 		&s.IsActive,
 		&s.CreatedAt,
 	)
@@ -240,9 +244,18 @@ func (r *SlotRepository) GetSlotInfoByLink(ctx context.Context, link string) (sl
 		return slot.Slot{}, err
 	}
 
+	s.Currency = slotCurrency(s.Currency)
 	return s, nil
 }
 
+// slotCurrency — у слотов, созданных до появления валют, колонка пустая
+func slotCurrency(currency string) string {
+	if currency == "" {
+		return entity.BaseCurrency
+	}
+	return currency
+}
+
 func (r *SlotRepository) HealthCheck(ctx context.Context) error {
 	return r.session.Query("SELECT now() FROM system.local").WithContext(ctx).Exec()
 }
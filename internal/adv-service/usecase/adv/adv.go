@@ -24,7 +24,7 @@ type AdvUsecaseInterface interface {
 	CheckLink(link string) error
 	PutLink(userID int, height, width int) (adv.Link, bool, error)
 	generateLink(userID int, height, width int) adv.Link
-	WriteMetric(bannerID int, slotLink string, metric string) error
+	WriteMetric(bannerID int, slotLink string, metric string, reservationID string) error
 	GetBannerMetric(bannerID int, activity string, userID int, from, to time.Time) (map[string]entity.Decimal, error)
 	GetSlotMetric(slotLink, activity string, userID int, from, to time.Time) (map[string]int, error)
 	GetSlotCTR(slotLink, activity string, userID int, from, to time.Time) (map[string]entity.Decimal, error)
@@ -70,7 +70,7 @@ func (a *AdvUsecase) GetLinks(userID int) ([]adv.Link, error) {
 	return links, nil
 }
 
-func (a *AdvUsecase) GetIframe(key string) (*pb.Banner, error) {
+func (a *AdvUsecase) GetIframe(key string) (*pb.Banner, string, error) {
 	slot, err := a.SlotsRepository.GetSlotInfoByLink(context.Background(), key)
 	ownerID := strconv.Itoa(entity.DefaultBanner.OwnerID)
 	defaultBanner := &pb.Banner{
@@ -83,28 +83,57 @@ func (a *AdvUsecase) GetIframe(key string) (*pb.Banner, error) {
 		Id:          int64(entity.DefaultBanner.ID),
 	}
 	if err != nil {
-		return defaultBanner, nil
+		return defaultBanner, "", nil
 	}
-	req := &pb.BannerWithMinPrice{MinPrice: slot.MinPrice.String(), Code: 1} // Однажды тут будут поддерживаться размеры
-	ctx := context.Background()                                              // Однажды мы прокинем нормально контекст, но не сегодня
+	req := &pb.BannerWithMinPrice{MinPrice: slot.MinPrice.String(), Code: 1, Currency: slot.Currency} // Однажды тут будут поддерживаться размеры
+	ctx := context.Background()                                                                       // Однажды мы прокинем нормально контекст, но не сегодня
 	bannerIDs, err := a.bannerClient.GetSuitableBanners(ctx, req)
 	if err != nil || len(bannerIDs.BannerId) <= 1 {
 		if len(bannerIDs.BannerId) == 1 && bannerIDs.BannerId[0] == -1 {
-			return defaultBanner, nil
+			return defaultBanner, "", nil
 		}
-		fmt.Errorf("Banners is nil")
-		return defaultBanner, nil
+		log.Println("Banners is nil")
+		return defaultBanner, "", nil
 	}
 	recomendReq := &protoRecommend.RecommendationRequest{PlatformId: int64(slot.UserID), SlotName: slot.SlotName, BannerId: bannerIDs.BannerId}
 	banner, err := a.RecommendClient.GetBannerByMetaData(ctx, recomendReq)
 	if err != nil || banner == nil {
-		banner, err := a.bannerClient.GetRandomBanner(ctx, req)
+		banner, err = a.bannerClient.GetRandomBanner(ctx, req)
 		if err != nil {
-			return defaultBanner, nil
+			return defaultBanner, "", nil
 		}
-		return banner, nil
 	}
-	return banner, nil
+
+	reservationID, err := a.reserveSpend(ctx, banner, slot.UserID, key)
+	if err != nil {
+		log.Printf("Failed to reserve spend for banner %d: %v", banner.Id, err)
+		return defaultBanner, "", nil
+	}
+	return banner, reservationID, nil
+}
+
+// reserveSpend резервирует цену показа на балансе владельца баннера,
+// чтобы не отдавать баннер, который рекламодатель уже не может оплатить
+func (a *AdvUsecase) reserveSpend(ctx context.Context, banner *pb.Banner, slotOwnerID int, slotLink string) (string, error) {
+	bannerOwnerID, err := strconv.Atoi(banner.OwnerID)
+	if err != nil {
+		return "", fmt.Errorf("invalid banner owner: %w", err)
+	}
+	resp, err := a.PaymentClient.ReserveSpend(ctx, &protoPayment.PaymentRequest{
+		FromUserId: int32(bannerOwnerID),
+		ToUserId:   int32(slotOwnerID),
+		Amount:     banner.MaxPrice,
+		Currency:   banner.Currency,
+		BannerId:   banner.Id,
+		SlotLink:   slotLink,
+	})
+	if err != nil {
+		return "", err
+	}
+	if !resp.GetReserved() {
+		return "", errors.New("insufficient funds")
+	}
+	return resp.GetReservationId(), nil
 }
 
 func (a *AdvUsecase) CheckLink(link string) error {
@@ -155,7 +184,7 @@ func (a *AdvUsecase) generateLink(userID int, height, width int) adv.Link {
 	}
 }
 
-func (a *AdvUsecase) WriteMetric(bannerID int, slotLink string, action string) error {
+func (a *AdvUsecase) WriteMetric(bannerID int, slotLink string, action string, reservationID string) error {
 
 	ownerSlotID, _, err := a.SlotsRepository.GetUserByLink(context.Background(), slotLink)
 	if err != nil {
@@ -172,9 +201,13 @@ func (a *AdvUsecase) WriteMetric(bannerID int, slotLink string, action string) e
 		return fmt.Errorf("get banner error")
 	}
 	req := &protoPayment.PaymentRequest{
-		FromUserId: int32(bannerOwnerID),
-		ToUserId:   int32(ownerSlotID),
-		Amount:     string(banner.MaxPrice),
+		FromUserId:    int32(bannerOwnerID),
+		ToUserId:      int32(ownerSlotID),
+		Amount:        string(banner.MaxPrice),
+		Currency:      banner.Currency,
+		ReservationId: reservationID,
+		BannerId:      int64(bannerID),
+		SlotLink:      slotLink,
 	}
 	if err := a.advRepository.WriteMetric(bannerID, slotLink, action, banner.MaxPrice); err != nil {
 		log.Printf("Failed to write metric: %v", err)
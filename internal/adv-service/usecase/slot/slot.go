@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"retarget/internal/adv-service/dto"
 	"retarget/internal/adv-service/entity/slot"
+	"retarget/pkg/entity"
 	"time"
 
 	repoSlot "retarget/internal/adv-service/repo/slot"
@@ -43,11 +44,16 @@ func NewSlotUsecase(repo repoSlot.SlotRepositoryInterface) *SlotUsecase {
 }
 
 func (uc *SlotUsecase) CreateSlot(ctx context.Context, req dto.CreateRequest, userID int) (slot.Slot, error) {
+	currency, err := entity.NormalizeCurrency(req.Currency)
+	if err != nil {
+		return slot.Slot{}, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
 	s := slot.Slot{
 		Link:       uuid.New().String(),
 		SlotName:   req.SlotName,
 		FormatCode: req.FormatCode,
 		MinPrice:   req.MinPrice,
+		Currency:   currency,
 		IsActive:   req.IsActive,
 		CreatedAt:  time.Now().UTC(),
 	}
@@ -68,12 +74,17 @@ func (uc *SlotUsecase) UpdateSlot(ctx context.Context, req dto.UpdateRequest, us
 	if user_id != userID {
 		return slot.Slot{}, ErrNotThisUserSlot
 	}
+	currency, err := entity.NormalizeCurrency(req.Currency)
+	if err != nil {
+		return slot.Slot{}, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
 
 	s := slot.Slot{
 		Link:       req.Link.String(),
 		SlotName:   req.SlotName,
 		FormatCode: req.FormatCode,
 		MinPrice:   req.MinPrice,
+		Currency:   currency,
 		IsActive:   req.IsActive,
 		CreatedAt:  created_at,
 	}
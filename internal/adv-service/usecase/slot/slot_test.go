@@ -106,6 +106,7 @@ func TestSlotUsecase_UpdateSlot(t *testing.T) {
 		SlotName:   req.SlotName,
 		FormatCode: req.FormatCode,
 		MinPrice:   req.MinPrice,
+		Currency:   "RUB",
 		IsActive:   req.IsActive,
 		CreatedAt:  createdAt,
 	}
@@ -307,3 +308,21 @@ func TestSlotUsecase_GetFormats(t *testing.T) {
 	assert.Equal(t, expectedFormats, result)
 	repoMock.AssertExpectations(t)
 }
+
+func TestSlotUsecase_CreateSlot_InvalidCurrency(t *testing.T) {
+	repoMock := new(mocks.SlotRepositoryInterface)
+	uc := NewSlotUsecase(repoMock)
+
+	req := dto.CreateRequest{
+		SlotName:   "Test Slot",
+		FormatCode: 1,
+		MinPrice:   *inf.NewDec(100, 0),
+		Currency:   "dollars",
+		IsActive:   true,
+	}
+
+	_, err := uc.CreateSlot(context.Background(), req, 1)
+
+	assert.ErrorIs(t, err, ErrValidation)
+	repoMock.AssertNotCalled(t, "CreateSlot", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -1,16 +1,20 @@
 package authApp
 
 import (
+	"context"
 	"log"
 	"net/http"
 	configs "retarget/configs"
 	authAppHttp "retarget/internal/auth-service/controller/http"
 	authMiddleware "retarget/internal/auth-service/controller/http/middleware"
+	entityAuth "retarget/internal/auth-service/entity/auth"
 	repoAuth "retarget/internal/auth-service/repo/auth"
 	repoSession "retarget/internal/auth-service/repo/auth"
 	usecaseAuth "retarget/internal/auth-service/usecase/auth"
 	authenticate "retarget/pkg/middleware/auth"
 	optiLog "retarget/pkg/utils/optiLog"
+	"retarget/pkg/utils/throttle"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -35,6 +39,31 @@ func Run(cfg *configs.Config, logger *zap.SugaredLogger) {
 		}
 	}()
 
+	codeRepository := repoAuth.NewCodeRepository(
+		cfg.AuthRedis.EndPoint,
+		cfg.AuthRedis.Password,
+		cfg.AuthRedis.Database,
+		entityAuth.CodeTTL,
+		entityAuth.CodeResendInterval,
+	)
+	defer func() {
+		if err := codeRepository.CloseConnection(); err != nil {
+			log.Printf("error closing code repository: %v", err)
+		}
+	}()
+
+	mailRepository := repoAuth.NewMailRepository(repoAuth.MailServiceEndpoint, nil)
+
+	limiter, err := throttle.NewLimiter(cfg.AuthRedis.EndPoint, cfg.AuthRedis.Password, cfg.AuthRedis.Database)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	defer func() {
+		if err := limiter.CloseConnection(); err != nil {
+			log.Printf("error closing login limiter: %v", err)
+		}
+	}()
+
 	userRepository := repoAuth.NewAuthRepository(cfg.Database.ConnectionString("d"), logger)
 	defer func() {
 		if err := userRepository.CloseConnection(); err != nil {
@@ -42,9 +71,51 @@ func Run(cfg *configs.Config, logger *zap.SugaredLogger) {
 		}
 	}()
 
-	authUsecase := usecaseAuth.NewAuthUsecase(userRepository, sessionRepository, asyncLogger)
+	oauthProviders := setupOAuthProviders(cfg.OAuth, logger)
+
+	authUsecase := usecaseAuth.NewAuthUsecase(userRepository, sessionRepository, codeRepository, mailRepository, limiter, oauthProviders, asyncLogger)
+
+	// после перезапуска Redis без данных ключи иначе перестали бы проходить проверку
+	if err := authUsecase.SyncAPIKeys(context.Background(), "startup"); err != nil {
+		logger.Errorf("API keys were not restored in Redis: %v", err)
+	}
 
 	mux := authAppHttp.SetupRoutes(authenticator, authUsecase)
 
 	log.Fatal(http.ListenAndServe(":8025", authMiddleware.CORS(mux)))
 }
+
+// setupOAuthProviders включает провайдеров, для которых задан client id.
+// Недоступный OIDC-провайдер не мешает запуску: вход через него просто выключен
+func setupOAuthProviders(cfg configs.OAuthConfig, logger *zap.SugaredLogger) []repoAuth.OAuthProviderInterface {
+	redirect := func(name string) string {
+		return strings.TrimSuffix(cfg.RedirectURL, "/") + "/" + name
+	}
+
+	var providers []repoAuth.OAuthProviderInterface
+	if cfg.VKClientID != "" {
+		providers = append(providers, repoAuth.NewVKIDProvider(cfg.VKClientID, cfg.VKClientSecret, redirect("vk"), nil))
+	}
+	if cfg.YandexClientID != "" {
+		providers = append(providers, repoAuth.NewYandexIDProvider(cfg.YandexClientID, cfg.YandexClientSecret, redirect("yandex"), nil))
+	}
+	if cfg.OIDCClientID != "" && cfg.OIDCIssuer != "" {
+		name := cfg.OIDCName
+		if name == "" {
+			name = "oidc"
+		}
+		provider, err := repoAuth.DiscoverOIDCProvider(entityAuth.OAuthProviderConfig{
+			Name:         name,
+			ClientID:     cfg.OIDCClientID,
+			ClientSecret: cfg.OIDCClientSecret,
+			Issuer:       cfg.OIDCIssuer,
+			RedirectURL:  redirect(name),
+		}, nil)
+		if err != nil {
+			logger.Errorf("OIDC provider %s disabled: %v", name, err)
+		} else {
+			providers = append(providers, provider)
+		}
+	}
+	return providers
+}
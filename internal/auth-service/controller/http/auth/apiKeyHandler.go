@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"net/http"
+	"strconv"
+
+	model "retarget/internal/auth-service/easyjsonModels"
+	entityAuth "retarget/internal/auth-service/entity/auth"
+	entity "retarget/pkg/entity"
+
+	"github.com/gorilla/mux"
+	"github.com/mailru/easyjson"
+)
+
+func apiKeyInfo(key entityAuth.APIKey) model.APIKeyInfo {
+	return model.APIKeyInfo{
+		ID:        key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    key.Scopes,
+		ExpiresAt: key.ExpiresAt,
+		CreatedAt: key.CreatedAt,
+	}
+}
+
+// CreateAPIKeyHandler выпускает API-ключ. Ключ виден только в ответе
+func (c *AuthController) CreateAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		resp := entity.NewResponse(true, "Error of authenticator")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	var req model.APIKeyCreateRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	key, secret, err := c.authUsecase.CreateAPIKey(r.Context(), userSession.UserID, userSession.Role, req.Name, req.Scopes, req.ExpiresAt, requestID)
+	if err != nil {
+		writeCodeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	resp := model.APIKeyCreatedWithErr{
+		Service: entity.NewResponse(false, "Save the key, it will not be shown again"),
+		Body:    model.APIKeyCreated{APIKeyInfo: apiKeyInfo(*key), Key: secret},
+	}
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
+
+// APIKeysHandler выводит неотозванные ключи пользователя
+func (c *AuthController) APIKeysHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		resp := entity.NewResponse(true, "Error of authenticator")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	keys, err := c.authUsecase.ListAPIKeys(r.Context(), userSession.UserID, requestID)
+	if err != nil {
+		writeCodeError(w, err)
+		return
+	}
+
+	body := model.APIKeysResponse{Keys: make([]model.APIKeyInfo, 0, len(keys))}
+	for _, key := range keys {
+		body.Keys = append(body.Keys, apiKeyInfo(key))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := model.APIKeysResponseWithErr{
+		Service: entity.NewResponse(false, "Sent"),
+		Body:    body,
+	}
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
+
+// RevokeAPIKeyHandler отзывает ключ пользователя
+func (c *AuthController) RevokeAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		resp := entity.NewResponse(true, "Error of authenticator")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	keyID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeCodeError(w, entityAuth.ErrAPIKeyNotFound)
+		return
+	}
+	if err := c.authUsecase.RevokeAPIKey(r.Context(), userSession.UserID, keyID, requestID); err != nil {
+		writeCodeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := entity.NewResponse(false, "API key revoked")
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
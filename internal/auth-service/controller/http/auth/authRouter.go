@@ -3,20 +3,52 @@ package auth
 import (
 	"context"
 	"net/http"
+	"net/url"
 	entityAuth "retarget/internal/auth-service/entity/auth"
 	usecaseAuth "retarget/internal/auth-service/usecase/auth"
 	logger "retarget/pkg/middleware"
 	authenticate "retarget/pkg/middleware/auth"
+	"time"
 
 	"github.com/gorilla/mux"
 )
 
 type AuthUsecase interface {
-	Login(ctx context.Context, email, pass string, role int, reqID string) (*entityAuth.User, error)
-	AddSession(userID, role int) (*entityAuth.Session, error)
+	Login(ctx context.Context, email, pass string, role int, ip, reqID string) (*entityAuth.User, *entityAuth.LoginChallenge, error)
+	AddSession(userID, role int, verified, twoFactor bool, client entityAuth.SessionClient) (*entityAuth.Session, error)
+	ListSessions(ctx context.Context, userID int, reqID string) ([]entityAuth.Session, error)
+	RevokeSession(ctx context.Context, userID int, handle, reqID string) error
+	RevokeAllSessions(ctx context.Context, userID int, reqID string) error
 	GetUser(ctx context.Context, id int, reqID string) (*entityAuth.User, error)
 	Logout(sessionID string) error
 	Register(ctx context.Context, username, email, password string, role int, reqID string) (*entityAuth.User, error)
+	SendVerificationCode(ctx context.Context, userID int, reqID string) error
+	ConfirmEmail(ctx context.Context, userID, code int, sessionID, reqID string) error
+	RequestPasswordReset(ctx context.Context, email, reqID string) error
+	ResetPassword(ctx context.Context, token, password, reqID string) error
+	RequestPasswordChange(ctx context.Context, userID int, oldPassword, reqID string) error
+	ChangePassword(ctx context.Context, userID, code int, password, sessionID, reqID string) error
+	RequestEmailChange(ctx context.Context, userID int, newEmail, reqID string) error
+	ConfirmEmailChange(ctx context.Context, userID, oldCode, newCode int, sessionID, reqID string) error
+	VerifyLoginChallenge(ctx context.Context, challengeID, code string, client entityAuth.SessionClient, reqID string) (*entityAuth.Session, error)
+	EnrollTOTP(ctx context.Context, userID int, reqID string) (string, string, error)
+	ConfirmTOTP(ctx context.Context, userID int, code, sessionID, reqID string) ([]string, error)
+	DisableTOTP(ctx context.Context, userID int, password, code, reqID string) error
+	StartOAuthLogin(ctx context.Context, provider string, role int, reqID string) (string, error)
+	StartOAuthLink(ctx context.Context, userID int, provider, reqID string) (string, error)
+	CompleteOAuthLogin(ctx context.Context, provider, code, state string, extra url.Values, reqID string) (*entityAuth.User, *entityAuth.LoginChallenge, error)
+	CompleteOAuthLink(ctx context.Context, userID int, provider, code, state string, extra url.Values, reqID string) error
+	CreateAPIKey(ctx context.Context, userID, role int, name string, scopes []string, expiresAt *time.Time, reqID string) (*entityAuth.APIKey, string, error)
+	ListAPIKeys(ctx context.Context, userID int, reqID string) ([]entityAuth.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID, keyID int, reqID string) error
+	CreateOrganization(ctx context.Context, userID int, name, reqID string) (*entityAuth.Membership, error)
+	ListOrganizations(ctx context.Context, userID int, reqID string) ([]entityAuth.Membership, error)
+	ListOrgMembers(ctx context.Context, userID, orgID int, reqID string) ([]entityAuth.OrgMember, error)
+	InviteToOrganization(ctx context.Context, userID, orgID int, email, role, reqID string) error
+	AcceptOrgInvite(ctx context.Context, userID int, token, reqID string) (*entityAuth.Membership, error)
+	ChangeOrgMemberRole(ctx context.Context, userID, orgID, memberID int, role, reqID string) error
+	RemoveOrgMember(ctx context.Context, userID, orgID, memberID int, reqID string) error
+	SwitchOrganization(ctx context.Context, userID, orgID int, sessionID, reqID string) (*entityAuth.Membership, error)
 }
 
 type AuthController struct {
@@ -34,20 +66,51 @@ func SetupAuthRoutes(authenticator *authenticate.Authenticator, authUsecase *use
 	muxRouter.Handle("/api/v1/auth/me", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(authController.GetCurrentUserHandler)))).Methods("GET")
 	muxRouter.Handle("/api/v1/auth/logout", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(authController.LogoutHandler)))).Methods("POST")
 
+	muxRouter.Handle("/api/v1/auth/sessions", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(authController.SessionsHandler)))).Methods("GET")
+	muxRouter.Handle("/api/v1/auth/sessions", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(authController.RevokeAllSessionsHandler)))).Methods("DELETE")
+	muxRouter.Handle("/api/v1/auth/sessions/{id}", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(authController.RevokeSessionHandler)))).Methods("DELETE")
+
+	// ключами управляют только из браузерной сессии, по самим ключам эти ручки недоступны.
+	// Ключи принадлежат аккаунту: в организации ими управляет владелец
+	ownerOnly := authenticate.RequireOrgRole(authenticate.OrgRoleOwner)
+	muxRouter.Handle("/api/v1/auth/api-keys", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(ownerOnly(authenticate.RequireVerified(http.HandlerFunc(authController.CreateAPIKeyHandler)))))).Methods("POST")
+	muxRouter.Handle("/api/v1/auth/api-keys", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(ownerOnly(http.HandlerFunc(authController.APIKeysHandler))))).Methods("GET")
+	muxRouter.Handle("/api/v1/auth/api-keys/{id:[0-9]+}", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(ownerOnly(http.HandlerFunc(authController.RevokeAPIKeyHandler))))).Methods("DELETE")
+
+	muxRouter.Handle("/api/v1/auth/orgs", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(authenticate.RequireVerified(http.HandlerFunc(authController.CreateOrganizationHandler))))).Methods("POST")
+	muxRouter.Handle("/api/v1/auth/orgs", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(authController.OrganizationsHandler)))).Methods("GET")
+	muxRouter.Handle("/api/v1/auth/orgs/switch", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(authController.SwitchOrganizationHandler)))).Methods("POST")
+	muxRouter.Handle("/api/v1/auth/orgs/invites/accept", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(authenticate.RequireVerified(http.HandlerFunc(authController.AcceptOrgInviteHandler))))).Methods("POST")
+	muxRouter.Handle("/api/v1/auth/orgs/{id:[0-9]+}/members", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(authController.OrgMembersHandler)))).Methods("GET")
+	muxRouter.Handle("/api/v1/auth/orgs/{id:[0-9]+}/invites", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(authController.OrgInviteHandler)))).Methods("POST")
+	muxRouter.Handle("/api/v1/auth/orgs/{id:[0-9]+}/members/{user_id:[0-9]+}", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(authController.OrgMemberRoleHandler)))).Methods("PUT")
+	muxRouter.Handle("/api/v1/auth/orgs/{id:[0-9]+}/members/{user_id:[0-9]+}", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(authController.RemoveOrgMemberHandler)))).Methods("DELETE")
+
 	muxRouter.Handle("/api/v1/auth/login", logger.LogMiddleware(http.HandlerFunc(authController.LoginHandler)))
 	// muxRouter.HandleFunc("/api/v1/auth/login/mail", authController.LoginConfirmHandler)
+	muxRouter.Handle("/api/v1/auth/login/2fa", logger.LogMiddleware(http.HandlerFunc(authController.LoginTwoFactorHandler))).Methods("POST")
+
+	muxRouter.Handle("/api/v1/auth/oauth/{provider}", logger.LogMiddleware(http.HandlerFunc(authController.OAuthStartHandler))).Methods("POST")
+	muxRouter.Handle("/api/v1/auth/oauth/{provider}/callback", logger.LogMiddleware(http.HandlerFunc(authController.OAuthCallbackHandler))).Methods("POST")
+	muxRouter.Handle("/api/v1/auth/oauth/{provider}/link", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(authController.OAuthLinkHandler)))).Methods("POST")
+	muxRouter.Handle("/api/v1/auth/oauth/{provider}/link/callback", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(authController.OAuthLinkCallbackHandler)))).Methods("POST")
+
+	muxRouter.Handle("/api/v1/auth/2fa/enroll", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(authController.TwoFactorEnrollHandler)))).Methods("POST")
+	muxRouter.Handle("/api/v1/auth/2fa/confirm", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(authController.TwoFactorConfirmHandler)))).Methods("POST")
+	muxRouter.Handle("/api/v1/auth/2fa/disable", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(authController.TwoFactorDisableHandler)))).Methods("POST")
 
 	muxRouter.Handle("/api/v1/auth/signup", logger.LogMiddleware(http.HandlerFunc(authController.RegisterHandler)))
-	muxRouter.Handle("/api/v1/auth/signup/mail", logger.LogMiddleware(http.HandlerFunc(authController.RegisterConfirmHandler)))
+	muxRouter.Handle("/api/v1/auth/signup/mail", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(authController.RegisterConfirmHandler)))).Methods("POST")
+	muxRouter.Handle("/api/v1/auth/signup/mail/resend", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(authController.RegisterResendHandler)))).Methods("POST")
 
-	// muxRouter.HandleFunc("/api/v1/auth/regain", authController.RegainHandler)
-	// muxRouter.HandleFunc("/api/v1/auth/regain/mail", authController.RegainConfirmHandler)
+	muxRouter.Handle("/api/v1/auth/regain", logger.LogMiddleware(http.HandlerFunc(authController.RegainHandler))).Methods("POST")
+	muxRouter.Handle("/api/v1/auth/regain/mail", logger.LogMiddleware(http.HandlerFunc(authController.RegainConfirmHandler))).Methods("POST")
 
-	// muxRouter.HandleFunc("/api/v1/auth/edit/password", authController.EditPasswordHandler)
-	// muxRouter.HandleFunc("/api/v1/auth/edit/password/mail", authController.EditPasswordConfirmHandler)
+	muxRouter.Handle("/api/v1/auth/edit/password", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(authController.EditPasswordHandler)))).Methods("POST")
+	muxRouter.Handle("/api/v1/auth/edit/password/mail", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(authController.EditPasswordConfirmHandler)))).Methods("POST")
 
-	// muxRouter.HandleFunc("/api/v1/auth/edit/email", authController.EditMailHandler)
-	// muxRouter.HandleFunc("/api/v1/auth/edit/email/mail", authController.EditMailConfirmHandler)
+	muxRouter.Handle("/api/v1/auth/edit/email", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(authController.EditMailHandler)))).Methods("POST")
+	muxRouter.Handle("/api/v1/auth/edit/email/mail", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(authController.EditMailConfirmHandler)))).Methods("POST")
 
 	return muxRouter
 }
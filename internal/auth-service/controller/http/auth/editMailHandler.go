@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"net/http"
+	"strconv"
+
+	model "retarget/internal/auth-service/easyjsonModels"
+	entity "retarget/pkg/entity"
+
+	"github.com/mailru/easyjson"
+)
+
+// EditMailHandler отправляет коды подтверждения смены почты на старый и новый адреса
+func (c *AuthController) EditMailHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	var req model.EditEmailRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		resp := entity.NewResponse(true, "Error of authenticator")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	if err := c.authUsecase.RequestEmailChange(r.Context(), userSession.Actor(), req.Email, requestID); err != nil {
+		writeCodeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := entity.NewResponse(false, "Sent")
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
+
+// EditMailConfirmHandler меняет почту, когда введены коды с обоих адресов
+func (c *AuthController) EditMailConfirmHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	var req model.EditEmailConfirmRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		resp := entity.NewResponse(true, "Error of authenticator")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+	cookie, err := r.Cookie("session_id")
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		resp := entity.NewResponse(true, "Invalid Cookie")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	oldCode, _ := strconv.Atoi(req.OldCode)
+	newCode, _ := strconv.Atoi(req.NewCode)
+	if err := c.authUsecase.ConfirmEmailChange(r.Context(), userSession.Actor(), oldCode, newCode, cookie.Value, requestID); err != nil {
+		writeCodeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := entity.NewResponse(false, "email changed")
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
@@ -33,7 +33,7 @@ func (c *AuthController) GetCurrentUserHandler(w http.ResponseWriter, r *http.Re
 		//nolint:errcheck
 		easyjson.MarshalToWriter(&resp, w)
 	}
-	userID := userSession.UserID
+	userID := userSession.Actor()
 
 	user, err := c.authUsecase.GetUser(r.Context(), userID, requestID)
 	if err != nil {
@@ -47,10 +47,15 @@ func (c *AuthController) GetCurrentUserHandler(w http.ResponseWriter, r *http.Re
 	}
 
 	userResponse := &model.UserResponse{
-		Username: user.Username,
-		Email:    user.Email,
-		Balance:  *user.Balance.Dec,
-		Role:     user.Role,
+		Username:      user.Username,
+		Email:         user.Email,
+		Balance:       user.Balance,
+		Role:          user.Role,
+		EmailVerified: user.EmailVerified,
+	}
+	if userSession.ActorID != 0 {
+		userResponse.OrgID = userSession.UserID
+		userResponse.OrgRole = userSession.OrgRole
 	}
 
 	response := model.UserResponseWithErr{
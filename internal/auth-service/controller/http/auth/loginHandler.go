@@ -4,8 +4,11 @@ import (
 	"io"
 	"net/http"
 	model "retarget/internal/auth-service/easyjsonModels"
+	entityAuth "retarget/internal/auth-service/entity/auth"
 	entity "retarget/pkg/entity"
+	"retarget/pkg/utils/throttle"
 	"retarget/pkg/utils/validator"
+	"time"
 
 	"github.com/mailru/easyjson"
 )
@@ -50,7 +53,11 @@ func (c *AuthController) LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := c.authUsecase.Login(r.Context(), req.Email, req.Password, req.Role, requestID)
+	user, challenge, err := c.authUsecase.Login(r.Context(), req.Email, req.Password, req.Role, sessionClient(r).IP, requestID)
+	if _, limited := throttle.IsThrottled(err); limited {
+		writeCodeError(w, err)
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		//nolint:errcheck
@@ -61,7 +68,19 @@ func (c *AuthController) LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	session, err := c.authUsecase.AddSession(user.ID, user.Role)
+	if challenge != nil {
+		// сессию откроет LoginTwoFactorHandler после ввода второго фактора
+		w.WriteHeader(http.StatusAccepted)
+		resp := model.TwoFactorChallengeWithErr{
+			Service: entity.NewResponse(false, "Two-factor code required"),
+			Body:    model.TwoFactorChallenge{Challenge: challenge.ID, ExpiresAt: challenge.ExpiresAt},
+		}
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	session, err := c.authUsecase.AddSession(user.ID, user.Role, user.EmailVerified, false, sessionClient(r))
 	if err != nil {
 		w.WriteHeader(http.StatusUnprocessableEntity)
 		//nolint:errcheck
@@ -72,6 +91,17 @@ func (c *AuthController) LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setSessionCookie(w, session)
+
+	w.WriteHeader(http.StatusOK)
+	//nolint:errcheck
+	// json.NewEncoder(w).Encode(entity.NewResponse(false, "Login Successful"))
+	resp := entity.NewResponse(false, "Login Succesful")
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
+
+func setSessionCookie(w http.ResponseWriter, session *entityAuth.Session) {
 	cookie := &http.Cookie{
 		Name:     "session_id",
 		Value:    session.ID,
@@ -82,11 +112,27 @@ func (c *AuthController) LoginHandler(w http.ResponseWriter, r *http.Request) {
 		Path:     "/",
 	}
 	http.SetCookie(w, cookie)
+}
 
-	w.WriteHeader(http.StatusOK)
-	//nolint:errcheck
-	// json.NewEncoder(w).Encode(entity.NewResponse(false, "Login Successful"))
-	resp := entity.NewResponse(false, "Login Succesful")
-	//nolint:errcheck
-	easyjson.MarshalToWriter(&resp, w)
+func clearSessionCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     "session_id",
+		Value:    "",
+		Expires:  time.Unix(0, 0),
+		HttpOnly: true,
+		Secure:   false,
+		SameSite: http.SameSiteLaxMode,
+		Path:     "/",
+		MaxAge:   -1,
+	})
+}
+
+// sessionClient описывает клиента для списка сессий и ограничения попыток
+// входа. Адрес берётся с учётом доверенного прокси, см. throttle.ClientIP
+func sessionClient(r *http.Request) entityAuth.SessionClient {
+	client := entityAuth.SessionClient{IP: r.RemoteAddr, UserAgent: r.UserAgent()}
+	if ip := throttle.ClientIP(r); ip != nil {
+		client.IP = ip.String()
+	}
+	return client
 }
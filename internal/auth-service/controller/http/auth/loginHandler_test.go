@@ -70,8 +70,8 @@ func TestLoginHandler_Success(t *testing.T) {
 	}
 
 	// Настраиваем mock для успешного входа
-	mockCtrl.mock.On("Login", mock.Anything, "test@example.com", "password123", 1, requestID).
-		Return(mockUser, nil)
+	mockCtrl.mock.On("Login", mock.Anything, "test@example.com", "password123", 1, mock.Anything, requestID).
+		Return(mockUser, (*authEntity.LoginChallenge)(nil), nil)
 
 	// Также необходимо настроить mock для создания сессии
 	mockSession := &authEntity.Session{
@@ -79,7 +79,7 @@ func TestLoginHandler_Success(t *testing.T) {
 		UserID: 1,
 		Role:   1,
 	}
-	mockCtrl.mock.On("AddSession", 1, 1).Return(mockSession, nil)
+	mockCtrl.mock.On("AddSession", 1, 1, false, false, mock.Anything).Return(mockSession, nil)
 
 	mockCtrl.LoginHandler(w, req)
 
@@ -122,8 +122,8 @@ func TestLoginHandler_LoginError(t *testing.T) {
 	mockError := errors.New("invalid credentials")
 
 	// Настраиваем mock для ошибки входа
-	mockCtrl.mock.On("Login", mock.Anything, "test@example.com", "wrong_password", 1, requestID).
-		Return((*authEntity.User)(nil), mockError)
+	mockCtrl.mock.On("Login", mock.Anything, "test@example.com", "wrong_password", 1, mock.Anything, requestID).
+		Return((*authEntity.User)(nil), (*authEntity.LoginChallenge)(nil), mockError)
 
 	mockCtrl.LoginHandler(w, req)
 
@@ -153,12 +153,12 @@ func TestLoginHandler_SessionError(t *testing.T) {
 		Email:    "test@example.com",
 		Role:     1,
 	}
-	mockCtrl.mock.On("Login", mock.Anything, "test@example.com", "password123", 1, requestID).
-		Return(mockUser, nil)
+	mockCtrl.mock.On("Login", mock.Anything, "test@example.com", "password123", 1, mock.Anything, requestID).
+		Return(mockUser, (*authEntity.LoginChallenge)(nil), nil)
 
 	// Но ошибку при создании сессии
 	sessionError := errors.New("session creation failed")
-	mockCtrl.mock.On("AddSession", 1, 1).Return((*authEntity.Session)(nil), sessionError)
+	mockCtrl.mock.On("AddSession", 1, 1, false, false, mock.Anything).Return((*authEntity.Session)(nil), sessionError)
 
 	mockCtrl.LoginHandler(w, req)
 
@@ -216,7 +216,7 @@ func (m *MockController) LoginHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Используем мок для входа в систему
-	user, err := m.mock.Login(r.Context(), loginRequest.Email, loginRequest.Password, loginRequest.Role, requestID)
+	user, _, err := m.mock.Login(r.Context(), loginRequest.Email, loginRequest.Password, loginRequest.Role, "", requestID)
 	if err != nil {
 		w.WriteHeader(http.StatusUnauthorized)
 		json.NewEncoder(w).Encode(map[string]interface{}{
@@ -227,7 +227,7 @@ func (m *MockController) LoginHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Создаем сессию
-	session, err := m.mock.AddSession(user.ID, user.Role)
+	session, err := m.mock.AddSession(user.ID, user.Role, user.EmailVerified, false, authEntity.SessionClient{})
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]interface{}{
@@ -3,7 +3,6 @@ package auth
 import (
 	"net/http"
 	entity "retarget/pkg/entity"
-	"time"
 
 	"github.com/mailru/easyjson"
 )
@@ -31,17 +30,7 @@ func (c *AuthController) LogoutHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	expires := time.Unix(0, 0)
-	http.SetCookie(w, &http.Cookie{
-		Name:     "session_id",
-		Value:    "",
-		Expires:  expires,
-		HttpOnly: true,
-		Secure:   false,
-		SameSite: http.SameSiteLaxMode,
-		Path:     "/",
-		MaxAge:   -1,
-	})
+	clearSessionCookie(w)
 
 	err = c.authUsecase.Logout(cookie.Value)
 	if err != nil {
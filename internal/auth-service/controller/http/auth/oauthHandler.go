@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"net/http"
+	"net/url"
+
+	model "retarget/internal/auth-service/easyjsonModels"
+	entity "retarget/pkg/entity"
+
+	"github.com/gorilla/mux"
+	"github.com/mailru/easyjson"
+)
+
+func oauthExtra(req model.OAuthCallbackRequest) url.Values {
+	extra := url.Values{}
+	if req.DeviceID != "" {
+		extra.Set("device_id", req.DeviceID)
+	}
+	return extra
+}
+
+func writeOAuthURL(w http.ResponseWriter, authURL string) {
+	w.WriteHeader(http.StatusOK)
+	resp := model.OAuthStartResponseWithErr{
+		Service: entity.NewResponse(false, "Redirect to provider"),
+		Body:    model.OAuthStartResponse{URL: authURL},
+	}
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
+
+// OAuthStartHandler выдаёт адрес входа у провайдера. role — роль нового
+// пользователя, если через этот аккаунт ещё не входили
+func (c *AuthController) OAuthStartHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	var req model.OAuthStartRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	authURL, err := c.authUsecase.StartOAuthLogin(r.Context(), mux.Vars(r)["provider"], req.Role, requestID)
+	if err != nil {
+		writeCodeError(w, err)
+		return
+	}
+	writeOAuthURL(w, authURL)
+}
+
+// OAuthCallbackHandler завершает вход через провайдера так же, как LoginHandler:
+// сессия в cookie или challenge второго фактора
+func (c *AuthController) OAuthCallbackHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	var req model.OAuthCallbackRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, challenge, err := c.authUsecase.CompleteOAuthLogin(r.Context(), mux.Vars(r)["provider"], req.Code, req.State, oauthExtra(req), requestID)
+	if err != nil {
+		writeCodeError(w, err)
+		return
+	}
+
+	if challenge != nil {
+		w.WriteHeader(http.StatusAccepted)
+		resp := model.TwoFactorChallengeWithErr{
+			Service: entity.NewResponse(false, "Two-factor code required"),
+			Body:    model.TwoFactorChallenge{Challenge: challenge.ID, ExpiresAt: challenge.ExpiresAt},
+		}
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	session, err := c.authUsecase.AddSession(user.ID, user.Role, user.EmailVerified, false, sessionClient(r))
+	if err != nil {
+		writeCodeError(w, err)
+		return
+	}
+	setSessionCookie(w, session)
+
+	w.WriteHeader(http.StatusOK)
+	resp := entity.NewResponse(false, "Login Succesful")
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
+
+// OAuthLinkHandler выдаёт адрес провайдера для привязки аккаунта к текущему пользователю
+func (c *AuthController) OAuthLinkHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		resp := entity.NewResponse(true, "Error of authenticator")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	authURL, err := c.authUsecase.StartOAuthLink(r.Context(), userSession.Actor(), mux.Vars(r)["provider"], requestID)
+	if err != nil {
+		writeCodeError(w, err)
+		return
+	}
+	writeOAuthURL(w, authURL)
+}
+
+// OAuthLinkCallbackHandler привязывает аккаунт, с которым провайдер вернул пользователя
+func (c *AuthController) OAuthLinkCallbackHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		resp := entity.NewResponse(true, "Error of authenticator")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	var req model.OAuthCallbackRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if err := c.authUsecase.CompleteOAuthLink(r.Context(), userSession.Actor(), mux.Vars(r)["provider"], req.Code, req.State, oauthExtra(req), requestID); err != nil {
+		writeCodeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := entity.NewResponse(false, "Account linked")
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
@@ -0,0 +1,288 @@
+package auth
+
+import (
+	"net/http"
+	"strconv"
+
+	model "retarget/internal/auth-service/easyjsonModels"
+	entityAuth "retarget/internal/auth-service/entity/auth"
+	entity "retarget/pkg/entity"
+
+	"github.com/gorilla/mux"
+	"github.com/mailru/easyjson"
+)
+
+func orgInfo(m entityAuth.Membership) model.OrgInfo {
+	return model.OrgInfo{
+		ID:         m.ID,
+		Name:       m.Name,
+		Role:       m.Role,
+		MemberRole: m.MemberRole,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+// userFrom достаёт пользователя из контекста. Организациями управляют от
+// своего имени, поэтому дальше используется userSession.Actor()
+func userFrom(w http.ResponseWriter, r *http.Request) (entity.UserContext, bool) {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		resp := entity.NewResponse(true, "Error of authenticator")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+	}
+	return userSession, ok
+}
+
+// pathIDs разбирает числовые параметры маршрута. Маршруты ограничивают их
+// регулярным выражением, ошибка означает переполнение int
+func pathIDs(w http.ResponseWriter, r *http.Request, names ...string) ([]int, bool) {
+	ids := make([]int, 0, len(names))
+	for _, name := range names {
+		id, err := strconv.Atoi(mux.Vars(r)[name])
+		if err != nil {
+			writeCodeError(w, entityAuth.ErrOrgNotFound)
+			return nil, false
+		}
+		ids = append(ids, id)
+	}
+	return ids, true
+}
+
+func writeOrg(w http.ResponseWriter, status int, message string, m entityAuth.Membership) {
+	w.WriteHeader(status)
+	resp := model.OrgInfoWithErr{
+		Service: entity.NewResponse(false, message),
+		Body:    orgInfo(m),
+	}
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
+
+// CreateOrganizationHandler заводит организацию, создатель становится владельцем
+func (c *AuthController) CreateOrganizationHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	userSession, ok := userFrom(w, r)
+	if !ok {
+		return
+	}
+	var req model.OrgCreateRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	membership, err := c.authUsecase.CreateOrganization(r.Context(), userSession.Actor(), req.Name, requestID)
+	if err != nil {
+		writeCodeError(w, err)
+		return
+	}
+	writeOrg(w, http.StatusCreated, "Organization created", *membership)
+}
+
+// OrganizationsHandler выводит организации, в которых состоит пользователь
+func (c *AuthController) OrganizationsHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	userSession, ok := userFrom(w, r)
+	if !ok {
+		return
+	}
+
+	memberships, err := c.authUsecase.ListOrganizations(r.Context(), userSession.Actor(), requestID)
+	if err != nil {
+		writeCodeError(w, err)
+		return
+	}
+
+	body := model.OrgsResponse{Organizations: make([]model.OrgInfo, 0, len(memberships))}
+	for _, m := range memberships {
+		body.Organizations = append(body.Organizations, orgInfo(m))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := model.OrgsResponseWithErr{
+		Service: entity.NewResponse(false, "Sent"),
+		Body:    body,
+	}
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
+
+// OrgMembersHandler выводит участников организации
+func (c *AuthController) OrgMembersHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	userSession, ok := userFrom(w, r)
+	if !ok {
+		return
+	}
+	ids, ok := pathIDs(w, r, "id")
+	if !ok {
+		return
+	}
+
+	members, err := c.authUsecase.ListOrgMembers(r.Context(), userSession.Actor(), ids[0], requestID)
+	if err != nil {
+		writeCodeError(w, err)
+		return
+	}
+
+	body := model.OrgMembersResponse{Members: make([]model.OrgMemberInfo, 0, len(members))}
+	for _, m := range members {
+		body.Members = append(body.Members, model.OrgMemberInfo{
+			UserID:   m.UserID,
+			Username: m.Username,
+			Email:    m.Email,
+			Role:     m.Role,
+			JoinedAt: m.JoinedAt,
+		})
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := model.OrgMembersResponseWithErr{
+		Service: entity.NewResponse(false, "Sent"),
+		Body:    body,
+	}
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
+
+// OrgInviteHandler отправляет приглашение в организацию на почту
+func (c *AuthController) OrgInviteHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	userSession, ok := userFrom(w, r)
+	if !ok {
+		return
+	}
+	ids, ok := pathIDs(w, r, "id")
+	if !ok {
+		return
+	}
+	var req model.OrgInviteRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if err := c.authUsecase.InviteToOrganization(r.Context(), userSession.Actor(), ids[0], req.Email, req.Role, requestID); err != nil {
+		writeCodeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := entity.NewResponse(false, "Invitation sent")
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
+
+// AcceptOrgInviteHandler принимает приглашение токеном из письма
+func (c *AuthController) AcceptOrgInviteHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	userSession, ok := userFrom(w, r)
+	if !ok {
+		return
+	}
+	var req model.OrgInviteAcceptRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	membership, err := c.authUsecase.AcceptOrgInvite(r.Context(), userSession.Actor(), req.Token, requestID)
+	if err != nil {
+		writeCodeError(w, err)
+		return
+	}
+	writeOrg(w, http.StatusOK, "Invitation accepted", *membership)
+}
+
+// OrgMemberRoleHandler меняет роль участника
+func (c *AuthController) OrgMemberRoleHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	userSession, ok := userFrom(w, r)
+	if !ok {
+		return
+	}
+	ids, ok := pathIDs(w, r, "id", "user_id")
+	if !ok {
+		return
+	}
+	var req model.OrgMemberRoleRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if err := c.authUsecase.ChangeOrgMemberRole(r.Context(), userSession.Actor(), ids[0], ids[1], req.Role, requestID); err != nil {
+		writeCodeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := entity.NewResponse(false, "Role changed")
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
+
+// RemoveOrgMemberHandler исключает участника или выводит из организации себя
+func (c *AuthController) RemoveOrgMemberHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	userSession, ok := userFrom(w, r)
+	if !ok {
+		return
+	}
+	ids, ok := pathIDs(w, r, "id", "user_id")
+	if !ok {
+		return
+	}
+
+	if err := c.authUsecase.RemoveOrgMember(r.Context(), userSession.Actor(), ids[0], ids[1], requestID); err != nil {
+		writeCodeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := entity.NewResponse(false, "Member removed")
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
+
+// SwitchOrganizationHandler переводит текущую сессию в организацию или обратно
+// в личный аккаунт
+func (c *AuthController) SwitchOrganizationHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	userSession, ok := userFrom(w, r)
+	if !ok {
+		return
+	}
+	cookie, err := r.Cookie("session_id")
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		resp := entity.NewResponse(true, "Invalid Cookie")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+	var req model.OrgSwitchRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	membership, err := c.authUsecase.SwitchOrganization(r.Context(), userSession.Actor(), req.OrgID, cookie.Value, requestID)
+	if err != nil {
+		writeCodeError(w, err)
+		return
+	}
+	if membership == nil {
+		w.WriteHeader(http.StatusOK)
+		resp := entity.NewResponse(false, "Switched to personal account")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+	writeOrg(w, http.StatusOK, "Switched to organization", *membership)
+}
@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	model "retarget/internal/auth-service/easyjsonModels"
+	entity "retarget/pkg/entity"
+	"retarget/pkg/utils/validator"
+
+	"github.com/mailru/easyjson"
+)
+
+func requestIDFrom(r *http.Request) string {
+	if v := r.Context().Value(entity.СtxKeyRequestID{}); v != nil {
+		if id, ok := v.(string); ok {
+			return id
+		}
+	}
+	return ""
+}
+
+// decodeRequest читает и валидирует тело запроса; при ошибке ответ уже записан
+func decodeRequest(w http.ResponseWriter, r *http.Request, req easyjson.Unmarshaler) bool {
+	data, _ := io.ReadAll(r.Body)
+	if err := easyjson.Unmarshal(data, req); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		resp := entity.NewResponse(true, err.Error())
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return false
+	}
+
+	validate_errors, err := validator.ValidateStruct(req)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		resp := entity.NewResponse(true, validate_errors)
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return false
+	}
+	return true
+}
+
+// RegainHandler отправляет на почту токен сброса забытого пароля
+func (c *AuthController) RegainHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	var req model.RegainRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if err := c.authUsecase.RequestPasswordReset(r.Context(), req.Email, requestID); err != nil {
+		writeCodeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := entity.NewResponse(false, "Sent")
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
+
+// RegainConfirmHandler задаёт новый пароль по токену из письма
+func (c *AuthController) RegainConfirmHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	var req model.RegainConfirmRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if err := c.authUsecase.ResetPassword(r.Context(), req.Token, req.Password, requestID); err != nil {
+		writeCodeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := entity.NewResponse(false, "password changed")
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
+
+// EditPasswordHandler проверяет текущий пароль и отправляет код подтверждения смены
+func (c *AuthController) EditPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	var req model.EditPasswordRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		resp := entity.NewResponse(true, "Error of authenticator")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	if err := c.authUsecase.RequestPasswordChange(r.Context(), userSession.Actor(), req.OldPassword, requestID); err != nil {
+		writeCodeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := entity.NewResponse(false, "Sent")
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
+
+// EditPasswordConfirmHandler меняет пароль по коду из письма; остальные сессии завершаются
+func (c *AuthController) EditPasswordConfirmHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	var req model.EditPasswordConfirmRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		resp := entity.NewResponse(true, "Error of authenticator")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+	cookie, err := r.Cookie("session_id")
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		resp := entity.NewResponse(true, "Invalid Cookie")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	code, _ := strconv.Atoi(req.Code)
+	if err := c.authUsecase.ChangePassword(r.Context(), userSession.Actor(), code, req.Password, cookie.Value, requestID); err != nil {
+		writeCodeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := entity.NewResponse(false, "password changed")
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
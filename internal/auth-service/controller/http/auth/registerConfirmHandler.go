@@ -1,29 +1,111 @@
 package auth
 
 import (
-	"encoding/json"
+	"errors"
+	"io"
+	"math"
 	"net/http"
+	"strconv"
+
 	model "retarget/internal/auth-service/easyjsonModels"
+	entityAuth "retarget/internal/auth-service/entity/auth"
 	entity "retarget/pkg/entity"
+	"retarget/pkg/utils/throttle"
 	"retarget/pkg/utils/validator"
 
 	"github.com/mailru/easyjson"
 )
 
+func codeErrorStatus(err error) int {
+	if _, limited := throttle.IsThrottled(err); limited {
+		return http.StatusTooManyRequests
+	}
+	switch {
+	case errors.Is(err, entityAuth.ErrCodeInvalid),
+		errors.Is(err, entityAuth.ErrPasswordTooShort),
+		errors.Is(err, entityAuth.ErrTOTPInvalid),
+		errors.Is(err, entityAuth.ErrIdentityNoEmail),
+		errors.Is(err, entityAuth.ErrAPIKeyScope),
+		errors.Is(err, entityAuth.ErrAPIKeyExpiry),
+		errors.Is(err, entityAuth.ErrOrgName),
+		errors.Is(err, entityAuth.ErrOrgRole):
+		return http.StatusBadRequest
+	case errors.Is(err, entityAuth.ErrCodeExpired),
+		errors.Is(err, entityAuth.ErrTokenInvalid),
+		errors.Is(err, entityAuth.ErrChallengeNotFound),
+		errors.Is(err, entityAuth.ErrOAuthStateInvalid),
+		errors.Is(err, entityAuth.ErrOrgInviteInvalid):
+		return http.StatusGone
+	case errors.Is(err, entityAuth.ErrCodeAttempts),
+		errors.Is(err, entityAuth.ErrCodeCooldown):
+		return http.StatusTooManyRequests
+	case errors.Is(err, entityAuth.ErrWrongPassword),
+		errors.Is(err, entityAuth.ErrOrgForbidden),
+		errors.Is(err, entityAuth.ErrOrgInviteEmail):
+		return http.StatusForbidden
+	case errors.Is(err, entityAuth.ErrEmailVerified),
+		errors.Is(err, entityAuth.ErrEmailTaken),
+		errors.Is(err, entityAuth.ErrEmailUnchanged),
+		errors.Is(err, entityAuth.ErrTOTPEnabled),
+		errors.Is(err, entityAuth.ErrIdentityTaken),
+		errors.Is(err, entityAuth.ErrIdentityLinked),
+		errors.Is(err, entityAuth.ErrIdentityEmailTaken),
+		errors.Is(err, entityAuth.ErrAPIKeyLimit),
+		errors.Is(err, entityAuth.ErrOrgLimit),
+		errors.Is(err, entityAuth.ErrOrgAlreadyMember),
+		errors.Is(err, entityAuth.ErrOrgLastOwner):
+		return http.StatusConflict
+	case errors.Is(err, entityAuth.ErrTOTPNotFound),
+		errors.Is(err, entityAuth.ErrSessionNotFound),
+		errors.Is(err, entityAuth.ErrOAuthProviderUnknown),
+		errors.Is(err, entityAuth.ErrAPIKeyNotFound),
+		errors.Is(err, entityAuth.ErrOrgNotFound),
+		errors.Is(err, entityAuth.ErrOrgMemberNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entityAuth.ErrEmailNotDelivered):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, entityAuth.ErrOAuthFailed):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeCodeError(w http.ResponseWriter, err error) {
+	status := codeErrorStatus(err)
+	message := err.Error()
+	if status == http.StatusInternalServerError {
+		message = "Internal Server Error"
+	}
+	if limited, ok := throttle.IsThrottled(err); ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(limited.RetryAfter.Seconds()))))
+	}
+	w.WriteHeader(status)
+	resp := entity.NewResponse(true, message)
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
+
+// RegisterConfirmHandler подтверждает почту кодом из письма, отправленного при регистрации
 func (c *AuthController) RegisterConfirmHandler(w http.ResponseWriter, r *http.Request) {
+	var requestID string
+	if v := r.Context().Value(entity.СtxKeyRequestID{}); v != nil {
+		if id, ok := v.(string); ok {
+			requestID = id
+		}
+	}
 	if r.Method != http.MethodPost {
 		w.WriteHeader(http.StatusMethodNotAllowed)
+		resp := entity.NewResponse(true, "Method Not Allowed")
 		//nolint:errcheck
-		json.NewEncoder(w).Encode(entity.NewResponse(true, "Method Not Allowed"))
+		easyjson.MarshalToWriter(&resp, w)
 		return
 	}
 
-	var req model.RegisterRequest
-	err := json.NewDecoder(r.Body).Decode(&req)
-	if err != nil {
+	var req model.RegisterConfirmRequest
+	data, _ := io.ReadAll(r.Body)
+	if err := req.UnmarshalJSON(data); err != nil {
 		w.WriteHeader(http.StatusUnprocessableEntity)
-		//nolint:errcheck
-		// json.NewEncoder(w).Encode(entity.NewResponse(true, err.Error()))
 		resp := entity.NewResponse(true, err.Error())
 		//nolint:errcheck
 		easyjson.MarshalToWriter(&resp, w)
@@ -33,12 +115,66 @@ func (c *AuthController) RegisterConfirmHandler(w http.ResponseWriter, r *http.R
 	validate_errors, err := validator.ValidateStruct(req)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		//nolint:errcheck
-		// json.NewEncoder(w).Encode(entity.NewResponse(true, validate_errors))
 		resp := entity.NewResponse(true, validate_errors)
 		//nolint:errcheck
 		easyjson.MarshalToWriter(&resp, w)
 		return
 	}
-	// GetUserByEmail, если нет то отправляем код
+
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		resp := entity.NewResponse(true, "Error of authenticator")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+	cookie, err := r.Cookie("session_id")
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		resp := entity.NewResponse(true, "Invalid Cookie")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	code, _ := strconv.Atoi(req.Code)
+	if err := c.authUsecase.ConfirmEmail(r.Context(), userSession.Actor(), code, cookie.Value, requestID); err != nil {
+		writeCodeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := entity.NewResponse(false, "email verified")
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
+
+// RegisterResendHandler отправляет новый код подтверждения почты
+func (c *AuthController) RegisterResendHandler(w http.ResponseWriter, r *http.Request) {
+	var requestID string
+	if v := r.Context().Value(entity.СtxKeyRequestID{}); v != nil {
+		if id, ok := v.(string); ok {
+			requestID = id
+		}
+	}
+
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		resp := entity.NewResponse(true, "Error of authenticator")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	if err := c.authUsecase.SendVerificationCode(r.Context(), userSession.Actor(), requestID); err != nil {
+		writeCodeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := entity.NewResponse(false, "Sent")
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
 }
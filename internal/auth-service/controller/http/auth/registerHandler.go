@@ -64,7 +64,7 @@ func (c *AuthController) RegisterHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	session, err := c.authUsecase.AddSession(user.ID, user.Role)
+	session, err := c.authUsecase.AddSession(user.ID, user.Role, user.EmailVerified, false, sessionClient(r))
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		//nolint:errcheck
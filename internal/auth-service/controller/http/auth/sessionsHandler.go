@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"net/http"
+
+	model "retarget/internal/auth-service/easyjsonModels"
+	entityAuth "retarget/internal/auth-service/entity/auth"
+	entity "retarget/pkg/entity"
+
+	"github.com/gorilla/mux"
+	"github.com/mailru/easyjson"
+)
+
+// SessionsHandler выводит активные сессии пользователя, текущая отмечена current
+func (c *AuthController) SessionsHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		resp := entity.NewResponse(true, "Error of authenticator")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+	var current string
+	if cookie, err := r.Cookie("session_id"); err == nil {
+		current = entityAuth.SessionHandle(cookie.Value)
+	}
+
+	sessions, err := c.authUsecase.ListSessions(r.Context(), userSession.Actor(), requestID)
+	if err != nil {
+		writeCodeError(w, err)
+		return
+	}
+
+	body := model.SessionsResponse{Sessions: make([]model.SessionInfo, 0, len(sessions))}
+	for _, s := range sessions {
+		handle := entityAuth.SessionHandle(s.ID)
+		body.Sessions = append(body.Sessions, model.SessionInfo{
+			ID:        handle,
+			CreatedAt: s.CreatedAt,
+			LastSeen:  s.LastSeen,
+			ExpiresAt: s.Expires,
+			IP:        s.IP,
+			UserAgent: s.UserAgent,
+			Current:   handle == current,
+		})
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := model.SessionsResponseWithErr{
+		Service: entity.NewResponse(false, "Sent"),
+		Body:    body,
+	}
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
+
+// RevokeSessionHandler завершает одну из сессий пользователя. Если это
+// текущая сессия, cookie тоже сбрасывается
+func (c *AuthController) RevokeSessionHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		resp := entity.NewResponse(true, "Error of authenticator")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	handle := mux.Vars(r)["id"]
+	if err := c.authUsecase.RevokeSession(r.Context(), userSession.Actor(), handle, requestID); err != nil {
+		writeCodeError(w, err)
+		return
+	}
+	if cookie, err := r.Cookie("session_id"); err == nil && entityAuth.SessionHandle(cookie.Value) == handle {
+		clearSessionCookie(w)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := entity.NewResponse(false, "Session revoked")
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
+
+// RevokeAllSessionsHandler выходит из аккаунта на всех устройствах, включая текущее
+func (c *AuthController) RevokeAllSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		resp := entity.NewResponse(true, "Error of authenticator")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	if err := c.authUsecase.RevokeAllSessions(r.Context(), userSession.Actor(), requestID); err != nil {
+		writeCodeError(w, err)
+		return
+	}
+	clearSessionCookie(w)
+
+	w.WriteHeader(http.StatusOK)
+	resp := entity.NewResponse(false, "Logout Successful")
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
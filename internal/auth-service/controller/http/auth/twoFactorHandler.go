@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"net/http"
+
+	model "retarget/internal/auth-service/easyjsonModels"
+	entity "retarget/pkg/entity"
+
+	"github.com/mailru/easyjson"
+)
+
+// LoginTwoFactorHandler завершает вход кодом приложения или кодом восстановления
+func (c *AuthController) LoginTwoFactorHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	var req model.LoginTwoFactorRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	session, err := c.authUsecase.VerifyLoginChallenge(r.Context(), req.Challenge, req.Code, sessionClient(r), requestID)
+	if err != nil {
+		writeCodeError(w, err)
+		return
+	}
+	setSessionCookie(w, session)
+
+	w.WriteHeader(http.StatusOK)
+	resp := entity.NewResponse(false, "Login Succesful")
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
+
+// TwoFactorEnrollHandler выдаёт секрет и otpauth-ссылку для приложения-аутентификатора
+func (c *AuthController) TwoFactorEnrollHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		resp := entity.NewResponse(true, "Error of authenticator")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	secret, uri, err := c.authUsecase.EnrollTOTP(r.Context(), userSession.Actor(), requestID)
+	if err != nil {
+		writeCodeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := model.TOTPEnrollResponseWithErr{
+		Service: entity.NewResponse(false, "Confirm with the first code"),
+		Body:    model.TOTPEnrollResponse{Secret: secret, URI: uri},
+	}
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
+
+// TwoFactorConfirmHandler включает второй фактор первым кодом и отдаёт коды восстановления
+func (c *AuthController) TwoFactorConfirmHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	var req model.TOTPConfirmRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		resp := entity.NewResponse(true, "Error of authenticator")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+	cookie, err := r.Cookie("session_id")
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		resp := entity.NewResponse(true, "Invalid Cookie")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	codes, err := c.authUsecase.ConfirmTOTP(r.Context(), userSession.Actor(), req.Code, cookie.Value, requestID)
+	if err != nil {
+		writeCodeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := model.RecoveryCodesResponseWithErr{
+		Service: entity.NewResponse(false, "Two-factor authentication enabled"),
+		Body:    model.RecoveryCodesResponse{RecoveryCodes: codes},
+	}
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
+
+// TwoFactorDisableHandler выключает второй фактор по паролю и коду
+func (c *AuthController) TwoFactorDisableHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFrom(r)
+
+	var req model.TOTPDisableRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		resp := entity.NewResponse(true, "Error of authenticator")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	if err := c.authUsecase.DisableTOTP(r.Context(), userSession.Actor(), req.Password, req.Code, requestID); err != nil {
+		writeCodeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := entity.NewResponse(false, "Two-factor authentication disabled")
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
@@ -3,9 +3,9 @@
 package model
 
 import (
-	"retarget/pkg/entity"
+	"time"
 
-	"gopkg.in/inf.v0"
+	"retarget/pkg/entity"
 )
 
 //easyjson:json
@@ -19,7 +19,40 @@ type RegisterRequest struct {
 
 //easyjson:json
 type RegisterConfirmRequest struct {
-	Email string `json:"email" validate:"email,required"`
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+//easyjson:json
+type RegainRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+//easyjson:json
+type RegainConfirmRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+//easyjson:json
+type EditPasswordRequest struct {
+	OldPassword string `json:"old_password" validate:"required"`
+}
+
+//easyjson:json
+type EditEmailRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+//easyjson:json
+type EditEmailConfirmRequest struct {
+	OldCode string `json:"old_code" validate:"required,len=6,numeric"`
+	NewCode string `json:"new_code" validate:"required,len=6,numeric"`
+}
+
+//easyjson:json
+type EditPasswordConfirmRequest struct {
+	Code     string `json:"code" validate:"required,len=6,numeric"`
+	Password string `json:"password" validate:"required"`
 }
 
 //easyjson:json
@@ -31,10 +64,15 @@ type LoginRequest struct {
 
 //easyjson:json
 type UserResponse struct {
-	Username string  `json:"username"`
-	Email    string  `json:"email"`
-	Balance  inf.Dec `json:"balance"`
-	Role     int     `json:"role"`
+	Username string         `json:"username"`
+	Email    string         `json:"email"`
+	Balance  entity.Decimal `json:"balance"`
+	Role     int            `json:"role"`
+	// EmailVerified — почта подтверждена; до этого нельзя заводить баннеры и слоты
+	EmailVerified bool `json:"email_verified"`
+	// OrgID и OrgRole — организация, от имени которой действует сессия
+	OrgID   int    `json:"org_id,omitempty"`
+	OrgRole string `json:"org_role,omitempty"`
 }
 
 //easyjson:json
@@ -43,6 +81,229 @@ type UserResponseWithErr struct {
 	Body    UserResponse    `json:"body"`
 }
 
+//easyjson:json
+type LoginTwoFactorRequest struct {
+	Challenge string `json:"challenge" validate:"required"`
+	Code      string `json:"code" validate:"required,max=32"`
+}
+
+// TwoFactorChallenge — пароль верный, для входа нужен второй фактор
+//
+//easyjson:json
+type TwoFactorChallenge struct {
+	Challenge string    `json:"challenge"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+//easyjson:json
+type TwoFactorChallengeWithErr struct {
+	Service entity.Response    `json:"service"`
+	Body    TwoFactorChallenge `json:"body"`
+}
+
+//easyjson:json
+type TOTPEnrollResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+//easyjson:json
+type TOTPEnrollResponseWithErr struct {
+	Service entity.Response    `json:"service"`
+	Body    TOTPEnrollResponse `json:"body"`
+}
+
+//easyjson:json
+type TOTPConfirmRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+//easyjson:json
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+//easyjson:json
+type RecoveryCodesResponseWithErr struct {
+	Service entity.Response       `json:"service"`
+	Body    RecoveryCodesResponse `json:"body"`
+}
+
+//easyjson:json
+type TOTPDisableRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required,max=32"`
+}
+
+// SessionInfo — сессия в списке активных сессий; ID — публичный
+// идентификатор, а не значение cookie
+//
+//easyjson:json
+type SessionInfo struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	LastSeen  time.Time `json:"last_seen"`
+	ExpiresAt time.Time `json:"expires_at"`
+	IP        string    `json:"ip,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	Current   bool      `json:"current"`
+}
+
+//easyjson:json
+type SessionsResponse struct {
+	Sessions []SessionInfo `json:"sessions"`
+}
+
+//easyjson:json
+type SessionsResponseWithErr struct {
+	Service entity.Response  `json:"service"`
+	Body    SessionsResponse `json:"body"`
+}
+
+//easyjson:json
+type OAuthStartRequest struct {
+	Role int `json:"role" validate:"required,gte=1,lte=2"`
+}
+
+// OAuthCallbackRequest — параметры, с которыми провайдер вернул пользователя на фронтенд.
+// device_id присылает только VK ID
+//
+//easyjson:json
+type OAuthCallbackRequest struct {
+	Code     string `json:"code" validate:"required,max=2048"`
+	State    string `json:"state" validate:"required,max=128"`
+	DeviceID string `json:"device_id" validate:"max=256"`
+}
+
+//easyjson:json
+type OAuthStartResponse struct {
+	URL string `json:"url"`
+}
+
+//easyjson:json
+type OAuthStartResponseWithErr struct {
+	Service entity.Response    `json:"service"`
+	Body    OAuthStartResponse `json:"body"`
+}
+
+//easyjson:json
+type APIKeyCreateRequest struct {
+	Name   string   `json:"name" validate:"required,max=64"`
+	Scopes []string `json:"scopes" validate:"required"`
+	// ExpiresAt не задан — ключ бессрочный
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+//easyjson:json
+type APIKeyInfo struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// APIKeyCreated — выпущенный ключ. Key показывается только в этом ответе
+//
+//easyjson:json
+type APIKeyCreated struct {
+	APIKeyInfo
+	Key string `json:"key"`
+}
+
+//easyjson:json
+type APIKeyCreatedWithErr struct {
+	Service entity.Response `json:"service"`
+	Body    APIKeyCreated   `json:"body"`
+}
+
+//easyjson:json
+type APIKeysResponse struct {
+	Keys []APIKeyInfo `json:"keys"`
+}
+
+//easyjson:json
+type APIKeysResponseWithErr struct {
+	Service entity.Response `json:"service"`
+	Body    APIKeysResponse `json:"body"`
+}
+
+//easyjson:json
+type OrgCreateRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+//easyjson:json
+type OrgInfo struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Role — роль аккаунта организации: 1=advertiser, 2=platform
+	Role       int       `json:"role"`
+	MemberRole string    `json:"member_role"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+//easyjson:json
+type OrgInfoWithErr struct {
+	Service entity.Response `json:"service"`
+	Body    OrgInfo         `json:"body"`
+}
+
+//easyjson:json
+type OrgsResponse struct {
+	Organizations []OrgInfo `json:"organizations"`
+}
+
+//easyjson:json
+type OrgsResponseWithErr struct {
+	Service entity.Response `json:"service"`
+	Body    OrgsResponse    `json:"body"`
+}
+
+//easyjson:json
+type OrgMemberInfo struct {
+	UserID   int       `json:"user_id"`
+	Username string    `json:"username"`
+	Email    string    `json:"email"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+//easyjson:json
+type OrgMembersResponse struct {
+	Members []OrgMemberInfo `json:"members"`
+}
+
+//easyjson:json
+type OrgMembersResponseWithErr struct {
+	Service entity.Response    `json:"service"`
+	Body    OrgMembersResponse `json:"body"`
+}
+
+//easyjson:json
+type OrgInviteRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required"`
+}
+
+//easyjson:json
+type OrgInviteAcceptRequest struct {
+	Token string `json:"token" validate:"required,max=64"`
+}
+
+//easyjson:json
+type OrgMemberRoleRequest struct {
+	Role string `json:"role" validate:"required"`
+}
+
+// OrgSwitchRequest — OrgID 0 возвращает сессию в личный аккаунт
+//
+//easyjson:json
+type OrgSwitchRequest struct {
+	OrgID int `json:"org_id" validate:"gte=0"`
+}
+
 //easyjson:json
 type ErrorRequest struct {
 	ErrorText string `json:"error"`
@@ -7,6 +7,7 @@ import (
 	easyjson "github.com/mailru/easyjson"
 	jlexer "github.com/mailru/easyjson/jlexer"
 	jwriter "github.com/mailru/easyjson/jwriter"
+	time "time"
 )
 
 // suppress unused package warning
@@ -114,11 +115,17 @@ func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels1(in *jlexer
 		case "email":
 			out.Email = string(in.String())
 		case "balance":
-			if data := in.UnsafeBytes(); in.Ok() {
-				in.AddError((out.Balance).UnmarshalText(data))
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Balance).UnmarshalJSON(data))
 			}
 		case "role":
 			out.Role = int(in.Int())
+		case "email_verified":
+			out.EmailVerified = bool(in.Bool())
+		case "org_id":
+			out.OrgID = int(in.Int())
+		case "org_role":
+			out.OrgRole = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -146,13 +153,28 @@ func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels1(out *jwrit
 	{
 		const prefix string = ",\"balance\":"
 		out.RawString(prefix)
-		out.RawText((in.Balance).MarshalText())
+		out.Raw((in.Balance).MarshalJSON())
 	}
 	{
 		const prefix string = ",\"role\":"
 		out.RawString(prefix)
 		out.Int(int(in.Role))
 	}
+	{
+		const prefix string = ",\"email_verified\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.EmailVerified))
+	}
+	if in.OrgID != 0 {
+		const prefix string = ",\"org_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.OrgID))
+	}
+	if in.OrgRole != "" {
+		const prefix string = ",\"org_role\":"
+		out.RawString(prefix)
+		out.String(string(in.OrgRole))
+	}
 	out.RawByte('}')
 }
 
@@ -179,7 +201,7 @@ func (v *UserResponse) UnmarshalJSON(data []byte) error {
 func (v *UserResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels1(l, v)
 }
-func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels2(in *jlexer.Lexer, out *RegisterRequest) {
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels2(in *jlexer.Lexer, out *TwoFactorChallengeWithErr) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -198,14 +220,10 @@ func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels2(in *jlexer
 			continue
 		}
 		switch key {
-		case "username":
-			out.Username = string(in.String())
-		case "email":
-			out.Email = string(in.String())
-		case "password":
-			out.Password = string(in.String())
-		case "role":
-			out.Role = int(in.Int())
+		case "service":
+			(out.Service).UnmarshalEasyJSON(in)
+		case "body":
+			(out.Body).UnmarshalEasyJSON(in)
 		default:
 			in.SkipRecursive()
 		}
@@ -216,57 +234,47 @@ func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels2(in *jlexer
 		in.Consumed()
 	}
 }
-func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels2(out *jwriter.Writer, in RegisterRequest) {
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels2(out *jwriter.Writer, in TwoFactorChallengeWithErr) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"username\":"
+		const prefix string = ",\"service\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Username))
-	}
-	{
-		const prefix string = ",\"email\":"
-		out.RawString(prefix)
-		out.String(string(in.Email))
-	}
-	{
-		const prefix string = ",\"password\":"
-		out.RawString(prefix)
-		out.String(string(in.Password))
+		(in.Service).MarshalEasyJSON(out)
 	}
 	{
-		const prefix string = ",\"role\":"
+		const prefix string = ",\"body\":"
 		out.RawString(prefix)
-		out.Int(int(in.Role))
+		(in.Body).MarshalEasyJSON(out)
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v RegisterRequest) MarshalJSON() ([]byte, error) {
+func (v TwoFactorChallengeWithErr) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels2(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v RegisterRequest) MarshalEasyJSON(w *jwriter.Writer) {
+func (v TwoFactorChallengeWithErr) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels2(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *RegisterRequest) UnmarshalJSON(data []byte) error {
+func (v *TwoFactorChallengeWithErr) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels2(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *RegisterRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *TwoFactorChallengeWithErr) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels2(l, v)
 }
-func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels3(in *jlexer.Lexer, out *RegisterConfirmRequest) {
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels3(in *jlexer.Lexer, out *TwoFactorChallenge) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -285,8 +293,12 @@ func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels3(in *jlexer
 			continue
 		}
 		switch key {
-		case "email":
-			out.Email = string(in.String())
+		case "challenge":
+			out.Challenge = string(in.String())
+		case "expires_at":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.ExpiresAt).UnmarshalJSON(data))
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -297,42 +309,47 @@ func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels3(in *jlexer
 		in.Consumed()
 	}
 }
-func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels3(out *jwriter.Writer, in RegisterConfirmRequest) {
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels3(out *jwriter.Writer, in TwoFactorChallenge) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"email\":"
+		const prefix string = ",\"challenge\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Email))
+		out.String(string(in.Challenge))
+	}
+	{
+		const prefix string = ",\"expires_at\":"
+		out.RawString(prefix)
+		out.Raw((in.ExpiresAt).MarshalJSON())
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v RegisterConfirmRequest) MarshalJSON() ([]byte, error) {
+func (v TwoFactorChallenge) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels3(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v RegisterConfirmRequest) MarshalEasyJSON(w *jwriter.Writer) {
+func (v TwoFactorChallenge) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels3(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *RegisterConfirmRequest) UnmarshalJSON(data []byte) error {
+func (v *TwoFactorChallenge) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels3(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *RegisterConfirmRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *TwoFactorChallenge) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels3(l, v)
 }
-func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels4(in *jlexer.Lexer, out *LoginRequest) {
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels4(in *jlexer.Lexer, out *TOTPEnrollResponseWithErr) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -351,12 +368,10 @@ func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels4(in *jlexer
 			continue
 		}
 		switch key {
-		case "email":
-			out.Email = string(in.String())
-		case "password":
-			out.Password = string(in.String())
-		case "role":
-			out.Role = int(in.Int())
+		case "service":
+			(out.Service).UnmarshalEasyJSON(in)
+		case "body":
+			(out.Body).UnmarshalEasyJSON(in)
 		default:
 			in.SkipRecursive()
 		}
@@ -367,52 +382,47 @@ func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels4(in *jlexer
 		in.Consumed()
 	}
 }
-func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels4(out *jwriter.Writer, in LoginRequest) {
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels4(out *jwriter.Writer, in TOTPEnrollResponseWithErr) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"email\":"
+		const prefix string = ",\"service\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Email))
-	}
-	{
-		const prefix string = ",\"password\":"
-		out.RawString(prefix)
-		out.String(string(in.Password))
+		(in.Service).MarshalEasyJSON(out)
 	}
 	{
-		const prefix string = ",\"role\":"
+		const prefix string = ",\"body\":"
 		out.RawString(prefix)
-		out.Int(int(in.Role))
+		(in.Body).MarshalEasyJSON(out)
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v LoginRequest) MarshalJSON() ([]byte, error) {
+func (v TOTPEnrollResponseWithErr) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels4(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v LoginRequest) MarshalEasyJSON(w *jwriter.Writer) {
+func (v TOTPEnrollResponseWithErr) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels4(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *LoginRequest) UnmarshalJSON(data []byte) error {
+func (v *TOTPEnrollResponseWithErr) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels4(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *LoginRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *TOTPEnrollResponseWithErr) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels4(l, v)
 }
-func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels5(in *jlexer.Lexer, out *ErrorRequest) {
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels5(in *jlexer.Lexer, out *TOTPEnrollResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -431,8 +441,10 @@ func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels5(in *jlexer
 			continue
 		}
 		switch key {
-		case "error":
-			out.ErrorText = string(in.String())
+		case "secret":
+			out.Secret = string(in.String())
+		case "otpauth_uri":
+			out.URI = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -443,38 +455,3319 @@ func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels5(in *jlexer
 		in.Consumed()
 	}
 }
-func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels5(out *jwriter.Writer, in ErrorRequest) {
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels5(out *jwriter.Writer, in TOTPEnrollResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"error\":"
+		const prefix string = ",\"secret\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.ErrorText))
+		out.String(string(in.Secret))
+	}
+	{
+		const prefix string = ",\"otpauth_uri\":"
+		out.RawString(prefix)
+		out.String(string(in.URI))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ErrorRequest) MarshalJSON() ([]byte, error) {
+func (v TOTPEnrollResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels5(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ErrorRequest) MarshalEasyJSON(w *jwriter.Writer) {
+func (v TOTPEnrollResponse) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels5(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ErrorRequest) UnmarshalJSON(data []byte) error {
+func (v *TOTPEnrollResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels5(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ErrorRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *TOTPEnrollResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels5(l, v)
 }
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels6(in *jlexer.Lexer, out *TOTPDisableRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "password":
+			out.Password = string(in.String())
+		case "code":
+			out.Code = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels6(out *jwriter.Writer, in TOTPDisableRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"password\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Password))
+	}
+	{
+		const prefix string = ",\"code\":"
+		out.RawString(prefix)
+		out.String(string(in.Code))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v TOTPDisableRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels6(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v TOTPDisableRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels6(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *TOTPDisableRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels6(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *TOTPDisableRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels6(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels7(in *jlexer.Lexer, out *TOTPConfirmRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "code":
+			out.Code = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels7(out *jwriter.Writer, in TOTPConfirmRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"code\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Code))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v TOTPConfirmRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels7(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v TOTPConfirmRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels7(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *TOTPConfirmRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels7(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *TOTPConfirmRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels7(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels8(in *jlexer.Lexer, out *SessionsResponseWithErr) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "service":
+			(out.Service).UnmarshalEasyJSON(in)
+		case "body":
+			(out.Body).UnmarshalEasyJSON(in)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels8(out *jwriter.Writer, in SessionsResponseWithErr) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"service\":"
+		out.RawString(prefix[1:])
+		(in.Service).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"body\":"
+		out.RawString(prefix)
+		(in.Body).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SessionsResponseWithErr) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels8(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SessionsResponseWithErr) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels8(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SessionsResponseWithErr) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels8(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SessionsResponseWithErr) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels8(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels9(in *jlexer.Lexer, out *SessionsResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "sessions":
+			if in.IsNull() {
+				in.Skip()
+				out.Sessions = nil
+			} else {
+				in.Delim('[')
+				if out.Sessions == nil {
+					if !in.IsDelim(']') {
+						out.Sessions = make([]SessionInfo, 0, 0)
+					} else {
+						out.Sessions = []SessionInfo{}
+					}
+				} else {
+					out.Sessions = (out.Sessions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 SessionInfo
+					(v1).UnmarshalEasyJSON(in)
+					out.Sessions = append(out.Sessions, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels9(out *jwriter.Writer, in SessionsResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"sessions\":"
+		out.RawString(prefix[1:])
+		if in.Sessions == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.Sessions {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				(v3).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SessionsResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels9(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SessionsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels9(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SessionsResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels9(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SessionsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels9(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels10(in *jlexer.Lexer, out *SessionInfo) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "created_at":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.CreatedAt).UnmarshalJSON(data))
+			}
+		case "last_seen":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.LastSeen).UnmarshalJSON(data))
+			}
+		case "expires_at":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.ExpiresAt).UnmarshalJSON(data))
+			}
+		case "ip":
+			out.IP = string(in.String())
+		case "user_agent":
+			out.UserAgent = string(in.String())
+		case "current":
+			out.Current = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels10(out *jwriter.Writer, in SessionInfo) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"last_seen\":"
+		out.RawString(prefix)
+		out.Raw((in.LastSeen).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"expires_at\":"
+		out.RawString(prefix)
+		out.Raw((in.ExpiresAt).MarshalJSON())
+	}
+	if in.IP != "" {
+		const prefix string = ",\"ip\":"
+		out.RawString(prefix)
+		out.String(string(in.IP))
+	}
+	if in.UserAgent != "" {
+		const prefix string = ",\"user_agent\":"
+		out.RawString(prefix)
+		out.String(string(in.UserAgent))
+	}
+	{
+		const prefix string = ",\"current\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Current))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SessionInfo) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels10(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SessionInfo) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels10(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SessionInfo) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels10(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SessionInfo) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels10(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels11(in *jlexer.Lexer, out *RegisterRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "username":
+			out.Username = string(in.String())
+		case "email":
+			out.Email = string(in.String())
+		case "password":
+			out.Password = string(in.String())
+		case "role":
+			out.Role = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels11(out *jwriter.Writer, in RegisterRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"username\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Username))
+	}
+	{
+		const prefix string = ",\"email\":"
+		out.RawString(prefix)
+		out.String(string(in.Email))
+	}
+	{
+		const prefix string = ",\"password\":"
+		out.RawString(prefix)
+		out.String(string(in.Password))
+	}
+	{
+		const prefix string = ",\"role\":"
+		out.RawString(prefix)
+		out.Int(int(in.Role))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RegisterRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels11(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RegisterRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels11(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RegisterRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels11(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RegisterRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels11(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels12(in *jlexer.Lexer, out *RegisterConfirmRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "code":
+			out.Code = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels12(out *jwriter.Writer, in RegisterConfirmRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"code\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Code))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RegisterConfirmRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels12(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RegisterConfirmRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels12(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RegisterConfirmRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels12(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RegisterConfirmRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels12(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels13(in *jlexer.Lexer, out *RegainRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "email":
+			out.Email = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels13(out *jwriter.Writer, in RegainRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"email\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Email))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RegainRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels13(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RegainRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels13(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RegainRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels13(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RegainRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels13(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels14(in *jlexer.Lexer, out *RegainConfirmRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "token":
+			out.Token = string(in.String())
+		case "password":
+			out.Password = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels14(out *jwriter.Writer, in RegainConfirmRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"token\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Token))
+	}
+	{
+		const prefix string = ",\"password\":"
+		out.RawString(prefix)
+		out.String(string(in.Password))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RegainConfirmRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels14(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RegainConfirmRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels14(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RegainConfirmRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels14(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RegainConfirmRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels14(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels15(in *jlexer.Lexer, out *RecoveryCodesResponseWithErr) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "service":
+			(out.Service).UnmarshalEasyJSON(in)
+		case "body":
+			(out.Body).UnmarshalEasyJSON(in)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels15(out *jwriter.Writer, in RecoveryCodesResponseWithErr) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"service\":"
+		out.RawString(prefix[1:])
+		(in.Service).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"body\":"
+		out.RawString(prefix)
+		(in.Body).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RecoveryCodesResponseWithErr) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels15(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RecoveryCodesResponseWithErr) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels15(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RecoveryCodesResponseWithErr) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels15(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RecoveryCodesResponseWithErr) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels15(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels16(in *jlexer.Lexer, out *RecoveryCodesResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "recovery_codes":
+			if in.IsNull() {
+				in.Skip()
+				out.RecoveryCodes = nil
+			} else {
+				in.Delim('[')
+				if out.RecoveryCodes == nil {
+					if !in.IsDelim(']') {
+						out.RecoveryCodes = make([]string, 0, 4)
+					} else {
+						out.RecoveryCodes = []string{}
+					}
+				} else {
+					out.RecoveryCodes = (out.RecoveryCodes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 string
+					v4 = string(in.String())
+					out.RecoveryCodes = append(out.RecoveryCodes, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels16(out *jwriter.Writer, in RecoveryCodesResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"recovery_codes\":"
+		out.RawString(prefix[1:])
+		if in.RecoveryCodes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v5, v6 := range in.RecoveryCodes {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v6))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RecoveryCodesResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels16(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RecoveryCodesResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels16(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RecoveryCodesResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels16(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RecoveryCodesResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels16(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels17(in *jlexer.Lexer, out *OrgsResponseWithErr) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "service":
+			(out.Service).UnmarshalEasyJSON(in)
+		case "body":
+			(out.Body).UnmarshalEasyJSON(in)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels17(out *jwriter.Writer, in OrgsResponseWithErr) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"service\":"
+		out.RawString(prefix[1:])
+		(in.Service).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"body\":"
+		out.RawString(prefix)
+		(in.Body).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OrgsResponseWithErr) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels17(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OrgsResponseWithErr) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels17(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OrgsResponseWithErr) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels17(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OrgsResponseWithErr) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels17(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels18(in *jlexer.Lexer, out *OrgsResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "organizations":
+			if in.IsNull() {
+				in.Skip()
+				out.Organizations = nil
+			} else {
+				in.Delim('[')
+				if out.Organizations == nil {
+					if !in.IsDelim(']') {
+						out.Organizations = make([]OrgInfo, 0, 0)
+					} else {
+						out.Organizations = []OrgInfo{}
+					}
+				} else {
+					out.Organizations = (out.Organizations)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v7 OrgInfo
+					(v7).UnmarshalEasyJSON(in)
+					out.Organizations = append(out.Organizations, v7)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels18(out *jwriter.Writer, in OrgsResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"organizations\":"
+		out.RawString(prefix[1:])
+		if in.Organizations == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v8, v9 := range in.Organizations {
+				if v8 > 0 {
+					out.RawByte(',')
+				}
+				(v9).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OrgsResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels18(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OrgsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels18(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OrgsResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels18(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OrgsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels18(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels19(in *jlexer.Lexer, out *OrgSwitchRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "org_id":
+			out.OrgID = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels19(out *jwriter.Writer, in OrgSwitchRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"org_id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.OrgID))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OrgSwitchRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels19(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OrgSwitchRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels19(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OrgSwitchRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels19(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OrgSwitchRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels19(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels20(in *jlexer.Lexer, out *OrgMembersResponseWithErr) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "service":
+			(out.Service).UnmarshalEasyJSON(in)
+		case "body":
+			(out.Body).UnmarshalEasyJSON(in)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels20(out *jwriter.Writer, in OrgMembersResponseWithErr) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"service\":"
+		out.RawString(prefix[1:])
+		(in.Service).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"body\":"
+		out.RawString(prefix)
+		(in.Body).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OrgMembersResponseWithErr) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels20(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OrgMembersResponseWithErr) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels20(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OrgMembersResponseWithErr) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels20(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OrgMembersResponseWithErr) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels20(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels21(in *jlexer.Lexer, out *OrgMembersResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "members":
+			if in.IsNull() {
+				in.Skip()
+				out.Members = nil
+			} else {
+				in.Delim('[')
+				if out.Members == nil {
+					if !in.IsDelim(']') {
+						out.Members = make([]OrgMemberInfo, 0, 0)
+					} else {
+						out.Members = []OrgMemberInfo{}
+					}
+				} else {
+					out.Members = (out.Members)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v10 OrgMemberInfo
+					(v10).UnmarshalEasyJSON(in)
+					out.Members = append(out.Members, v10)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels21(out *jwriter.Writer, in OrgMembersResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"members\":"
+		out.RawString(prefix[1:])
+		if in.Members == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v11, v12 := range in.Members {
+				if v11 > 0 {
+					out.RawByte(',')
+				}
+				(v12).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OrgMembersResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels21(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OrgMembersResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels21(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OrgMembersResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels21(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OrgMembersResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels21(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels22(in *jlexer.Lexer, out *OrgMemberRoleRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "role":
+			out.Role = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels22(out *jwriter.Writer, in OrgMemberRoleRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"role\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Role))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OrgMemberRoleRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels22(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OrgMemberRoleRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels22(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OrgMemberRoleRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels22(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OrgMemberRoleRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels22(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels23(in *jlexer.Lexer, out *OrgMemberInfo) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "user_id":
+			out.UserID = int(in.Int())
+		case "username":
+			out.Username = string(in.String())
+		case "email":
+			out.Email = string(in.String())
+		case "role":
+			out.Role = string(in.String())
+		case "joined_at":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.JoinedAt).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels23(out *jwriter.Writer, in OrgMemberInfo) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"user_id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.UserID))
+	}
+	{
+		const prefix string = ",\"username\":"
+		out.RawString(prefix)
+		out.String(string(in.Username))
+	}
+	{
+		const prefix string = ",\"email\":"
+		out.RawString(prefix)
+		out.String(string(in.Email))
+	}
+	{
+		const prefix string = ",\"role\":"
+		out.RawString(prefix)
+		out.String(string(in.Role))
+	}
+	{
+		const prefix string = ",\"joined_at\":"
+		out.RawString(prefix)
+		out.Raw((in.JoinedAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OrgMemberInfo) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels23(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OrgMemberInfo) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels23(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OrgMemberInfo) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels23(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OrgMemberInfo) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels23(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels24(in *jlexer.Lexer, out *OrgInviteRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "email":
+			out.Email = string(in.String())
+		case "role":
+			out.Role = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels24(out *jwriter.Writer, in OrgInviteRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"email\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Email))
+	}
+	{
+		const prefix string = ",\"role\":"
+		out.RawString(prefix)
+		out.String(string(in.Role))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OrgInviteRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels24(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OrgInviteRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels24(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OrgInviteRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels24(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OrgInviteRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels24(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels25(in *jlexer.Lexer, out *OrgInviteAcceptRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "token":
+			out.Token = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels25(out *jwriter.Writer, in OrgInviteAcceptRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"token\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Token))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OrgInviteAcceptRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels25(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OrgInviteAcceptRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels25(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OrgInviteAcceptRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels25(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OrgInviteAcceptRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels25(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels26(in *jlexer.Lexer, out *OrgInfoWithErr) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "service":
+			(out.Service).UnmarshalEasyJSON(in)
+		case "body":
+			(out.Body).UnmarshalEasyJSON(in)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels26(out *jwriter.Writer, in OrgInfoWithErr) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"service\":"
+		out.RawString(prefix[1:])
+		(in.Service).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"body\":"
+		out.RawString(prefix)
+		(in.Body).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OrgInfoWithErr) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels26(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OrgInfoWithErr) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels26(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OrgInfoWithErr) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels26(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OrgInfoWithErr) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels26(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels27(in *jlexer.Lexer, out *OrgInfo) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = int(in.Int())
+		case "name":
+			out.Name = string(in.String())
+		case "role":
+			out.Role = int(in.Int())
+		case "member_role":
+			out.MemberRole = string(in.String())
+		case "created_at":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.CreatedAt).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels27(out *jwriter.Writer, in OrgInfo) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.ID))
+	}
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix)
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"role\":"
+		out.RawString(prefix)
+		out.Int(int(in.Role))
+	}
+	{
+		const prefix string = ",\"member_role\":"
+		out.RawString(prefix)
+		out.String(string(in.MemberRole))
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OrgInfo) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels27(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OrgInfo) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels27(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OrgInfo) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels27(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OrgInfo) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels27(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels28(in *jlexer.Lexer, out *OrgCreateRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "name":
+			out.Name = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels28(out *jwriter.Writer, in OrgCreateRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OrgCreateRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels28(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OrgCreateRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels28(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OrgCreateRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels28(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OrgCreateRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels28(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels29(in *jlexer.Lexer, out *OAuthStartResponseWithErr) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "service":
+			(out.Service).UnmarshalEasyJSON(in)
+		case "body":
+			(out.Body).UnmarshalEasyJSON(in)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels29(out *jwriter.Writer, in OAuthStartResponseWithErr) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"service\":"
+		out.RawString(prefix[1:])
+		(in.Service).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"body\":"
+		out.RawString(prefix)
+		(in.Body).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OAuthStartResponseWithErr) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels29(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OAuthStartResponseWithErr) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels29(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OAuthStartResponseWithErr) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels29(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OAuthStartResponseWithErr) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels29(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels30(in *jlexer.Lexer, out *OAuthStartResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "url":
+			out.URL = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels30(out *jwriter.Writer, in OAuthStartResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"url\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.URL))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OAuthStartResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels30(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OAuthStartResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels30(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OAuthStartResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels30(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OAuthStartResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels30(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels31(in *jlexer.Lexer, out *OAuthStartRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "role":
+			out.Role = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels31(out *jwriter.Writer, in OAuthStartRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"role\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Role))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OAuthStartRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels31(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OAuthStartRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels31(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OAuthStartRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels31(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OAuthStartRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels31(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels32(in *jlexer.Lexer, out *OAuthCallbackRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "code":
+			out.Code = string(in.String())
+		case "state":
+			out.State = string(in.String())
+		case "device_id":
+			out.DeviceID = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels32(out *jwriter.Writer, in OAuthCallbackRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"code\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Code))
+	}
+	{
+		const prefix string = ",\"state\":"
+		out.RawString(prefix)
+		out.String(string(in.State))
+	}
+	{
+		const prefix string = ",\"device_id\":"
+		out.RawString(prefix)
+		out.String(string(in.DeviceID))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OAuthCallbackRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels32(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OAuthCallbackRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels32(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OAuthCallbackRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels32(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OAuthCallbackRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels32(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels33(in *jlexer.Lexer, out *LoginTwoFactorRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "challenge":
+			out.Challenge = string(in.String())
+		case "code":
+			out.Code = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels33(out *jwriter.Writer, in LoginTwoFactorRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"challenge\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Challenge))
+	}
+	{
+		const prefix string = ",\"code\":"
+		out.RawString(prefix)
+		out.String(string(in.Code))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v LoginTwoFactorRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels33(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v LoginTwoFactorRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels33(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *LoginTwoFactorRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels33(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *LoginTwoFactorRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels33(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels34(in *jlexer.Lexer, out *LoginRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "email":
+			out.Email = string(in.String())
+		case "password":
+			out.Password = string(in.String())
+		case "role":
+			out.Role = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels34(out *jwriter.Writer, in LoginRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"email\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Email))
+	}
+	{
+		const prefix string = ",\"password\":"
+		out.RawString(prefix)
+		out.String(string(in.Password))
+	}
+	{
+		const prefix string = ",\"role\":"
+		out.RawString(prefix)
+		out.Int(int(in.Role))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v LoginRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels34(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v LoginRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels34(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *LoginRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels34(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *LoginRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels34(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels35(in *jlexer.Lexer, out *ErrorRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "error":
+			out.ErrorText = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels35(out *jwriter.Writer, in ErrorRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"error\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ErrorText))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ErrorRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels35(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ErrorRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels35(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ErrorRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels35(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ErrorRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels35(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels36(in *jlexer.Lexer, out *EditPasswordRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "old_password":
+			out.OldPassword = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels36(out *jwriter.Writer, in EditPasswordRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"old_password\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.OldPassword))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v EditPasswordRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels36(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v EditPasswordRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels36(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *EditPasswordRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels36(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *EditPasswordRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels36(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels37(in *jlexer.Lexer, out *EditPasswordConfirmRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "code":
+			out.Code = string(in.String())
+		case "password":
+			out.Password = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels37(out *jwriter.Writer, in EditPasswordConfirmRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"code\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Code))
+	}
+	{
+		const prefix string = ",\"password\":"
+		out.RawString(prefix)
+		out.String(string(in.Password))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v EditPasswordConfirmRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels37(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v EditPasswordConfirmRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels37(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *EditPasswordConfirmRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels37(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *EditPasswordConfirmRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels37(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels38(in *jlexer.Lexer, out *EditEmailRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "email":
+			out.Email = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels38(out *jwriter.Writer, in EditEmailRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"email\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Email))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v EditEmailRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels38(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v EditEmailRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels38(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *EditEmailRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels38(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *EditEmailRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels38(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels39(in *jlexer.Lexer, out *EditEmailConfirmRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "old_code":
+			out.OldCode = string(in.String())
+		case "new_code":
+			out.NewCode = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels39(out *jwriter.Writer, in EditEmailConfirmRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"old_code\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.OldCode))
+	}
+	{
+		const prefix string = ",\"new_code\":"
+		out.RawString(prefix)
+		out.String(string(in.NewCode))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v EditEmailConfirmRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels39(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v EditEmailConfirmRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels39(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *EditEmailConfirmRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels39(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *EditEmailConfirmRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels39(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels40(in *jlexer.Lexer, out *APIKeysResponseWithErr) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "service":
+			(out.Service).UnmarshalEasyJSON(in)
+		case "body":
+			(out.Body).UnmarshalEasyJSON(in)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels40(out *jwriter.Writer, in APIKeysResponseWithErr) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"service\":"
+		out.RawString(prefix[1:])
+		(in.Service).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"body\":"
+		out.RawString(prefix)
+		(in.Body).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v APIKeysResponseWithErr) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels40(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v APIKeysResponseWithErr) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels40(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *APIKeysResponseWithErr) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels40(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *APIKeysResponseWithErr) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels40(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels41(in *jlexer.Lexer, out *APIKeysResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "keys":
+			if in.IsNull() {
+				in.Skip()
+				out.Keys = nil
+			} else {
+				in.Delim('[')
+				if out.Keys == nil {
+					if !in.IsDelim(']') {
+						out.Keys = make([]APIKeyInfo, 0, 0)
+					} else {
+						out.Keys = []APIKeyInfo{}
+					}
+				} else {
+					out.Keys = (out.Keys)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v13 APIKeyInfo
+					(v13).UnmarshalEasyJSON(in)
+					out.Keys = append(out.Keys, v13)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels41(out *jwriter.Writer, in APIKeysResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"keys\":"
+		out.RawString(prefix[1:])
+		if in.Keys == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v14, v15 := range in.Keys {
+				if v14 > 0 {
+					out.RawByte(',')
+				}
+				(v15).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v APIKeysResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels41(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v APIKeysResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels41(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *APIKeysResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels41(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *APIKeysResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels41(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels42(in *jlexer.Lexer, out *APIKeyInfo) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = int(in.Int())
+		case "name":
+			out.Name = string(in.String())
+		case "prefix":
+			out.Prefix = string(in.String())
+		case "scopes":
+			if in.IsNull() {
+				in.Skip()
+				out.Scopes = nil
+			} else {
+				in.Delim('[')
+				if out.Scopes == nil {
+					if !in.IsDelim(']') {
+						out.Scopes = make([]string, 0, 4)
+					} else {
+						out.Scopes = []string{}
+					}
+				} else {
+					out.Scopes = (out.Scopes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v16 string
+					v16 = string(in.String())
+					out.Scopes = append(out.Scopes, v16)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "expires_at":
+			if in.IsNull() {
+				in.Skip()
+				out.ExpiresAt = nil
+			} else {
+				if out.ExpiresAt == nil {
+					out.ExpiresAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.ExpiresAt).UnmarshalJSON(data))
+				}
+			}
+		case "created_at":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.CreatedAt).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels42(out *jwriter.Writer, in APIKeyInfo) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.ID))
+	}
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix)
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"prefix\":"
+		out.RawString(prefix)
+		out.String(string(in.Prefix))
+	}
+	{
+		const prefix string = ",\"scopes\":"
+		out.RawString(prefix)
+		if in.Scopes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v17, v18 := range in.Scopes {
+				if v17 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v18))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.ExpiresAt != nil {
+		const prefix string = ",\"expires_at\":"
+		out.RawString(prefix)
+		out.Raw((*in.ExpiresAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v APIKeyInfo) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels42(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v APIKeyInfo) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels42(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *APIKeyInfo) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels42(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *APIKeyInfo) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels42(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels43(in *jlexer.Lexer, out *APIKeyCreatedWithErr) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "service":
+			(out.Service).UnmarshalEasyJSON(in)
+		case "body":
+			(out.Body).UnmarshalEasyJSON(in)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels43(out *jwriter.Writer, in APIKeyCreatedWithErr) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"service\":"
+		out.RawString(prefix[1:])
+		(in.Service).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"body\":"
+		out.RawString(prefix)
+		(in.Body).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v APIKeyCreatedWithErr) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels43(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v APIKeyCreatedWithErr) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels43(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *APIKeyCreatedWithErr) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels43(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *APIKeyCreatedWithErr) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels43(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels44(in *jlexer.Lexer, out *APIKeyCreated) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "key":
+			out.Key = string(in.String())
+		case "id":
+			out.ID = int(in.Int())
+		case "name":
+			out.Name = string(in.String())
+		case "prefix":
+			out.Prefix = string(in.String())
+		case "scopes":
+			if in.IsNull() {
+				in.Skip()
+				out.Scopes = nil
+			} else {
+				in.Delim('[')
+				if out.Scopes == nil {
+					if !in.IsDelim(']') {
+						out.Scopes = make([]string, 0, 4)
+					} else {
+						out.Scopes = []string{}
+					}
+				} else {
+					out.Scopes = (out.Scopes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v19 string
+					v19 = string(in.String())
+					out.Scopes = append(out.Scopes, v19)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "expires_at":
+			if in.IsNull() {
+				in.Skip()
+				out.ExpiresAt = nil
+			} else {
+				if out.ExpiresAt == nil {
+					out.ExpiresAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.ExpiresAt).UnmarshalJSON(data))
+				}
+			}
+		case "created_at":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.CreatedAt).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels44(out *jwriter.Writer, in APIKeyCreated) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"key\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Key))
+	}
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix)
+		out.Int(int(in.ID))
+	}
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix)
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"prefix\":"
+		out.RawString(prefix)
+		out.String(string(in.Prefix))
+	}
+	{
+		const prefix string = ",\"scopes\":"
+		out.RawString(prefix)
+		if in.Scopes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v20, v21 := range in.Scopes {
+				if v20 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v21))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.ExpiresAt != nil {
+		const prefix string = ",\"expires_at\":"
+		out.RawString(prefix)
+		out.Raw((*in.ExpiresAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v APIKeyCreated) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels44(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v APIKeyCreated) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels44(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *APIKeyCreated) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels44(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *APIKeyCreated) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels44(l, v)
+}
+func easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels45(in *jlexer.Lexer, out *APIKeyCreateRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "name":
+			out.Name = string(in.String())
+		case "scopes":
+			if in.IsNull() {
+				in.Skip()
+				out.Scopes = nil
+			} else {
+				in.Delim('[')
+				if out.Scopes == nil {
+					if !in.IsDelim(']') {
+						out.Scopes = make([]string, 0, 4)
+					} else {
+						out.Scopes = []string{}
+					}
+				} else {
+					out.Scopes = (out.Scopes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v22 string
+					v22 = string(in.String())
+					out.Scopes = append(out.Scopes, v22)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "expires_at":
+			if in.IsNull() {
+				in.Skip()
+				out.ExpiresAt = nil
+			} else {
+				if out.ExpiresAt == nil {
+					out.ExpiresAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.ExpiresAt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels45(out *jwriter.Writer, in APIKeyCreateRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"scopes\":"
+		out.RawString(prefix)
+		if in.Scopes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v23, v24 := range in.Scopes {
+				if v23 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v24))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.ExpiresAt != nil {
+		const prefix string = ",\"expires_at\":"
+		out.RawString(prefix)
+		out.Raw((*in.ExpiresAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v APIKeyCreateRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels45(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v APIKeyCreateRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeRetargetInternalAuthServiceEasyjsonModels45(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *APIKeyCreateRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels45(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *APIKeyCreateRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeRetargetInternalAuthServiceEasyjsonModels45(l, v)
+}
@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+const (
+	// APIKeySecretBytes — 256 бит случайности после префикса rtk_
+	APIKeySecretBytes = 32
+	// APIKeyPrefixLength — сколько первых символов ключа показывается в списке
+	APIKeyPrefixLength = 12
+	MaxAPIKeysPerUser  = 20
+	MaxAPIKeyNameLen   = 64
+)
+
+var (
+	ErrAPIKeyNotFound = &Error{"API-ключ не найден"}
+	ErrAPIKeyScope    = &Error{"Неизвестное право API-ключа"}
+	ErrAPIKeyExpiry   = &Error{"Срок действия ключа должен быть в будущем"}
+	ErrAPIKeyLimit    = &Error{"Достигнут предел числа API-ключей, отзовите ненужные"}
+)
+
+// APIKey — выпущенный ключ без секрета. Секрет показывается один раз при выпуске
+type APIKey struct {
+	ID     int
+	UserID int
+	Role   int
+	Name   string
+	Prefix string
+	// KeyHash — sha256 ключа, под ним ключ лежит в Redis
+	KeyHash   string
+	Scopes    []string
+	ExpiresAt *time.Time
+	CreatedAt time.Time
+}
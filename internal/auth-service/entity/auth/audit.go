@@ -0,0 +1,17 @@
+package entity
+
+// События журнала auth_audit_log
+const (
+	AuditEmailChanged = "email_changed"
+	AuditTOTPEnabled  = "totp_enabled"
+	AuditTOTPDisabled = "totp_disabled"
+	// AuditIdentityLinked — к аккаунту привязан вход через внешнего провайдера
+	AuditIdentityLinked = "identity_linked"
+	AuditAPIKeyCreated  = "api_key_created"
+	AuditAPIKeyRevoked  = "api_key_revoked"
+	// события организации пишутся на аккаунт организации, в details — кто их совершил
+	AuditOrgCreated           = "org_created"
+	AuditOrgMemberAdded       = "org_member_added"
+	AuditOrgMemberRoleChanged = "org_member_role_changed"
+	AuditOrgMemberRemoved     = "org_member_removed"
+)
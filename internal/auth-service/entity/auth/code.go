@@ -0,0 +1,44 @@
+package entity
+
+import "time"
+
+// Назначения одноразовых кодов: у каждого свой ключ в Redis
+const (
+	CodeRegister      = "register"
+	CodeEditPassword  = "edit_password"
+	CodeResetPassword = "reset_password" // одноразовый токен, а не цифровой код
+	CodeEditEmailOld  = "edit_email_old" // код на текущий адрес
+	CodeEditEmailNew  = "edit_email_new" // код на новый адрес, несёт сам адрес
+)
+
+const (
+	CodeLength         = 6
+	CodeTTL            = 15 * time.Minute
+	CodeMaxAttempts    = 5           // после стольких неверных вводов код сгорает
+	CodeResendInterval = time.Minute // не чаще одного письма в минуту
+
+	ResetTokenBytes = 32
+	ResetTokenTTL   = 30 * time.Minute
+)
+
+var (
+	ErrCodeInvalid       = &Error{"Неверный код"}
+	ErrCodeExpired       = &Error{"Код истёк, запросите новый"}
+	ErrCodeAttempts      = &Error{"Слишком много неверных попыток, запросите новый код"}
+	ErrCodeCooldown      = &Error{"Код уже отправлен, повторите позже"}
+	ErrEmailVerified     = &Error{"Почта уже подтверждена"}
+	ErrEmailNotDelivered = &Error{"Не удалось отправить письмо, повторите позже"}
+	ErrTokenInvalid      = &Error{"Ссылка для сброса пароля недействительна или устарела"}
+	ErrWrongPassword     = &Error{"Неверный текущий пароль"}
+	ErrPasswordTooShort  = &Error{"пароль должен быть не короче 8 символов"}
+	ErrEmailTaken        = &Error{"пользователь с таким email уже существует"}
+	ErrEmailUnchanged    = &Error{"Новая почта совпадает с текущей"}
+)
+
+// StoredCode — код в Redis: хранится только хэш с солью
+type StoredCode struct {
+	Hash     string
+	Salt     string
+	Attempts int
+	Payload  string // то, что подтверждает код, например новая почта
+}
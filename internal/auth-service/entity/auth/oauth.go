@@ -0,0 +1,66 @@
+package entity
+
+import "time"
+
+const (
+	// OAuthStateTTL — сколько ждём возвращения пользователя от провайдера
+	OAuthStateTTL   = 10 * time.Minute
+	OAuthStateBytes = 32
+	// OAuthVerifierBytes — 43 символа base64url, минимум для PKCE (RFC 7636)
+	OAuthVerifierBytes = 32
+
+	OAuthModeLogin = "login"
+	OAuthModeLink  = "link"
+)
+
+// Виды провайдеров: отличаются обменом кода и форматом данных пользователя
+const (
+	OAuthKindOIDC   = "oidc"
+	OAuthKindVK     = "vk"
+	OAuthKindYandex = "yandex"
+)
+
+var (
+	ErrOAuthProviderUnknown = &Error{"Неизвестный провайдер входа"}
+	ErrOAuthStateInvalid    = &Error{"Вход через провайдера устарел, начните заново"}
+	ErrOAuthFailed          = &Error{"Провайдер не подтвердил вход"}
+	ErrIdentityTaken        = &Error{"Этот внешний аккаунт уже привязан к другому пользователю"}
+	ErrIdentityLinked       = &Error{"К аккаунту уже привязан вход через этого провайдера"}
+	ErrIdentityEmailTaken   = &Error{"Пользователь с такой почтой уже есть: войдите паролем и привяжите внешний аккаунт в настройках"}
+	ErrIdentityNoEmail      = &Error{"Провайдер не сообщил почту, без неё аккаунт не создать"}
+)
+
+// OAuthProviderConfig — настройки клиента у провайдера. Для OIDC с Issuer
+// адреса можно не заполнять: они берутся из discovery
+type OAuthProviderConfig struct {
+	Name         string
+	Kind         string
+	ClientID     string
+	ClientSecret string
+	Issuer       string
+	AuthURL      string
+	TokenURL     string
+	UserInfoURL  string
+	Scopes       []string
+	RedirectURL  string
+}
+
+// ExternalIdentity — пользователь по данным провайдера
+type ExternalIdentity struct {
+	Provider string
+	Subject  string
+	Email    string
+	// EmailVerified — провайдер сам подтвердил почту; иначе её подтверждают кодом, как при регистрации
+	EmailVerified bool
+	Username      string
+}
+
+// OAuthState — начатый вход или привязка, ждущие возвращения от провайдера.
+// Хранится под случайным state, который провайдер вернёт вместе с кодом
+type OAuthState struct {
+	Provider string `json:"provider"`
+	Mode     string `json:"mode"`
+	Verifier string `json:"verifier"`
+	Role     int    `json:"role,omitempty"`
+	UserID   int    `json:"user_id,omitempty"`
+}
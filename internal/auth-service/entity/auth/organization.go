@@ -0,0 +1,63 @@
+package entity
+
+import "time"
+
+const (
+	MaxOrgNameLen = 100
+	// MaxOrgsPerUser — во сколько организаций может входить пользователь
+	MaxOrgsPerUser = 20
+	// OrgInviteTokenBytes — 192 бита случайности в токене приглашения
+	OrgInviteTokenBytes = 24
+	OrgInviteTTL        = 7 * 24 * time.Hour
+	// OrgAccountEmailDomain — домен служебной почты аккаунта организации.
+	// .invalid зарезервирован, письма на такие адреса не уходят
+	OrgAccountEmailDomain = "organizations.retarget.invalid"
+)
+
+var (
+	// ErrOrgNotFound — организации нет или пользователь в ней не состоит
+	ErrOrgNotFound       = &Error{"Организация не найдена"}
+	ErrOrgForbidden      = &Error{"Действие недоступно для вашей роли в организации"}
+	ErrOrgName           = &Error{"Название организации должно быть от 1 до 100 символов"}
+	ErrOrgRole           = &Error{"Неизвестная роль в организации"}
+	ErrOrgLimit          = &Error{"Достигнут предел числа организаций"}
+	ErrOrgMemberNotFound = &Error{"Участник не найден"}
+	ErrOrgAlreadyMember  = &Error{"Пользователь уже состоит в организации"}
+	ErrOrgLastOwner      = &Error{"У организации должен остаться хотя бы один владелец"}
+	ErrOrgInviteInvalid  = &Error{"Приглашение недействительно или истекло"}
+	ErrOrgInviteEmail    = &Error{"Приглашение отправлено на другую почту"}
+)
+
+// Organization — аккаунт организации. ID — её id в auth_user: на него
+// записаны баннеры, слоты и баланс
+type Organization struct {
+	ID        int
+	Name      string
+	Role      int // роль аккаунта: 1=advertiser, 2=platform
+	CreatedAt time.Time
+}
+
+// Membership — организация с ролью в ней текущего пользователя
+type Membership struct {
+	Organization
+	MemberRole string
+}
+
+type OrgMember struct {
+	UserID   int
+	Username string
+	Email    string
+	Role     string
+	JoinedAt time.Time
+}
+
+type OrgInvite struct {
+	ID        int
+	OrgID     int
+	Email     string
+	Role      string
+	TokenHash string
+	InvitedBy int
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
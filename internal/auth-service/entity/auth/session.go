@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"time"
 )
 
@@ -10,6 +12,33 @@ type Session struct {
 	Role      int       `json:"role"`
 	Expires   time.Time `json:"expires"`
 	CreatedAt time.Time `json:"created_at"`
+	// Unverified читают сервисы через pkg/middleware/auth, см. SessionData
+	Unverified bool `json:"unverified,omitempty"`
+	TwoFactor  bool `json:"two_factor,omitempty"`
+	// OrgID и OrgRole — организация, от имени которой действует сессия; Role
+	// у такой сессии — роль аккаунта организации
+	OrgID     int    `json:"org_id,omitempty"`
+	OrgRole   string `json:"org_role,omitempty"`
+	IP        string `json:"ip,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+	// LastSeen хранится отдельным ключом, его обновляет pkg/middleware/auth
+	LastSeen time.Time `json:"-"`
+}
+
+// SessionClient — откуда открыта сессия
+type SessionClient struct {
+	IP        string
+	UserAgent string
+}
+
+// MaxUserAgentLength — сколько символов User-Agent сохраняется в сессии
+const MaxUserAgentLength = 256
+
+// SessionHandle — публичный идентификатор сессии для списка сессий.
+// Сам ID сессии — секрет из cookie, наружу он не отдаётся
+func SessionHandle(sessionID string) string {
+	sum := sha256.Sum256([]byte(sessionID))
+	return hex.EncodeToString(sum[:8])
 }
 
 var ErrSessionNotFound = &Error{"Session not found"}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"retarget/pkg/utils/throttle"
+)
+
+const (
+	ThrottleLoginEmail = "login_email"
+	ThrottleLoginIP    = "login_ip"
+)
+
+// LoginEmailPolicy — неудачные входы в один аккаунт: пароль или второй фактор.
+// На блокировке владельцу уходит письмо
+var LoginEmailPolicy = throttle.Policy{
+	FreeAttempts: 3,
+	BaseDelay:    time.Second,
+	MaxDelay:     time.Minute,
+	LockoutAfter: 10,
+	LockoutFor:   15 * time.Minute,
+	Window:       time.Hour,
+}
+
+// LoginIPPolicy — неудачные входы с одного адреса в любые аккаунты. Порог
+// выше: за одним NAT бывает много пользователей
+var LoginIPPolicy = throttle.Policy{
+	FreeAttempts: 20,
+	BaseDelay:    time.Second,
+	MaxDelay:     5 * time.Minute,
+	LockoutAfter: 100,
+	LockoutFor:   time.Hour,
+	Window:       time.Hour,
+}
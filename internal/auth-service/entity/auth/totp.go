@@ -0,0 +1,40 @@
+package entity
+
+import "time"
+
+const (
+	TOTPIssuer = "ReTarget"
+
+	RecoveryCodeCount = 10
+	RecoveryCodeBytes = 10 // 80 бит, в виде xxxx-xxxx-xxxx-xxxx
+
+	// ChallengeTTL — сколько ждём второй фактор после верного пароля
+	ChallengeTTL         = 5 * time.Minute
+	ChallengeMaxAttempts = 5
+)
+
+var (
+	ErrTOTPNotFound      = &Error{"Двухфакторная аутентификация не включена"}
+	ErrTOTPEnabled       = &Error{"Двухфакторная аутентификация уже включена"}
+	ErrTOTPInvalid       = &Error{"Неверный код двухфакторной аутентификации"}
+	ErrChallengeNotFound = &Error{"Время на ввод кода истекло, войдите заново"}
+)
+
+// TOTP — секрет приложения-аутентификатора. До подтверждения первым кодом
+// Enabled = false и вход его не требует
+type TOTP struct {
+	UserID  int
+	Secret  string
+	Enabled bool
+}
+
+// LoginChallenge — вход, ожидающий второй фактор: пароль уже проверен
+type LoginChallenge struct {
+	ID        string
+	UserID    int
+	Email     string
+	Role      int
+	Verified  bool
+	Attempts  int
+	ExpiresAt time.Time
+}
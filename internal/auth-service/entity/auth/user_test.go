@@ -15,7 +15,7 @@ func TestDecimalValue_Nil(t *testing.T) {
 }
 
 func TestDecimalValue_NonNil(t *testing.T) {
-	d := Decimal{inf.NewDec(123, 0)}
+	d := Decimal{Dec: inf.NewDec(123, 0)}
 	v, err := d.Value()
 	if err != nil || v != "123" {
 		t.Errorf("expected '123', got %v, err %v", v, err)
@@ -40,7 +40,7 @@ func TestDecimalScan_Types(t *testing.T) {
 
 func TestParseFromString_Invalid(t *testing.T) {
 	var d Decimal
-	if err := d.parseFromString("bad"); err == nil {
+	if err := d.ParseFromString("bad"); err == nil {
 		t.Error("expected parse error")
 	}
 }
@@ -13,6 +13,34 @@ type AuthRepositoryInterface struct {
 	mock.Mock
 }
 
+// AcceptOrgInvite provides a mock function with given fields: tokenHash, userID, email, requestID
+func (_m *AuthRepositoryInterface) AcceptOrgInvite(tokenHash string, userID int, email string, requestID string) (entity.Membership, error) {
+	ret := _m.Called(tokenHash, userID, email, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcceptOrgInvite")
+	}
+
+	var r0 entity.Membership
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int, string, string) (entity.Membership, error)); ok {
+		return rf(tokenHash, userID, email, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(string, int, string, string) entity.Membership); ok {
+		r0 = rf(tokenHash, userID, email, requestID)
+	} else {
+		r0 = ret.Get(0).(entity.Membership)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int, string, string) error); ok {
+		r1 = rf(tokenHash, userID, email, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CheckEmailOrUsernameExists provides a mock function with given fields: email, username, requestID
 func (_m *AuthRepositoryInterface) CheckEmailOrUsernameExists(email string, username string, requestID string) (*entity.User, error) {
 	ret := _m.Called(email, username, requestID)
@@ -61,6 +89,24 @@ func (_m *AuthRepositoryInterface) CloseConnection() error {
 	return r0
 }
 
+// CreateAPIKey provides a mock function with given fields: key, requestID
+func (_m *AuthRepositoryInterface) CreateAPIKey(key *entity.APIKey, requestID string) error {
+	ret := _m.Called(key, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAPIKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.APIKey, string) error); ok {
+		r0 = rf(key, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateNewUser provides a mock function with given fields: user, requestID
 func (_m *AuthRepositoryInterface) CreateNewUser(user *entity.User, requestID string) error {
 	ret := _m.Called(user, requestID)
@@ -79,6 +125,152 @@ func (_m *AuthRepositoryInterface) CreateNewUser(user *entity.User, requestID st
 	return r0
 }
 
+// CreateOrgInvite provides a mock function with given fields: invite, requestID
+func (_m *AuthRepositoryInterface) CreateOrgInvite(invite *entity.OrgInvite, requestID string) error {
+	ret := _m.Called(invite, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateOrgInvite")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.OrgInvite, string) error); ok {
+		r0 = rf(invite, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateOrganization provides a mock function with given fields: org, account, ownerID, requestID
+func (_m *AuthRepositoryInterface) CreateOrganization(org *entity.Organization, account *entity.User, ownerID int, requestID string) error {
+	ret := _m.Called(org, account, ownerID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateOrganization")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Organization, *entity.User, int, string) error); ok {
+		r0 = rf(org, account, ownerID, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateUserWithIdentity provides a mock function with given fields: user, identity, requestID
+func (_m *AuthRepositoryInterface) CreateUserWithIdentity(user *entity.User, identity entity.ExternalIdentity, requestID string) error {
+	ret := _m.Called(user, identity, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUserWithIdentity")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.User, entity.ExternalIdentity, string) error); ok {
+		r0 = rf(user, identity, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DisableTOTP provides a mock function with given fields: userID, requestID
+func (_m *AuthRepositoryInterface) DisableTOTP(userID int, requestID string) error {
+	ret := _m.Called(userID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableTOTP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(userID, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnableTOTP provides a mock function with given fields: userID, recoveryHashes, requestID
+func (_m *AuthRepositoryInterface) EnableTOTP(userID int, recoveryHashes []string, requestID string) error {
+	ret := _m.Called(userID, recoveryHashes, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnableTOTP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, []string, string) error); ok {
+		r0 = rf(userID, recoveryHashes, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetMembership provides a mock function with given fields: orgID, userID, requestID
+func (_m *AuthRepositoryInterface) GetMembership(orgID int, userID int, requestID string) (entity.Membership, error) {
+	ret := _m.Called(orgID, userID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMembership")
+	}
+
+	var r0 entity.Membership
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int, string) (entity.Membership, error)); ok {
+		return rf(orgID, userID, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(int, int, string) entity.Membership); ok {
+		r0 = rf(orgID, userID, requestID)
+	} else {
+		r0 = ret.Get(0).(entity.Membership)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int, string) error); ok {
+		r1 = rf(orgID, userID, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTOTP provides a mock function with given fields: userID, requestID
+func (_m *AuthRepositoryInterface) GetTOTP(userID int, requestID string) (entity.TOTP, error) {
+	ret := _m.Called(userID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTOTP")
+	}
+
+	var r0 entity.TOTP
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, string) (entity.TOTP, error)); ok {
+		return rf(userID, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(int, string) entity.TOTP); ok {
+		r0 = rf(userID, requestID)
+	} else {
+		r0 = ret.Get(0).(entity.TOTP)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, string) error); ok {
+		r1 = rf(userID, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUserByEmail provides a mock function with given fields: email, requestID
 func (_m *AuthRepositoryInterface) GetUserByEmail(email string, requestID string) (*entity.User, error) {
 	ret := _m.Called(email, requestID)
@@ -139,6 +331,36 @@ func (_m *AuthRepositoryInterface) GetUserByID(id int, requestID string) (*entit
 	return r0, r1
 }
 
+// GetUserByIdentity provides a mock function with given fields: provider, subject, requestID
+func (_m *AuthRepositoryInterface) GetUserByIdentity(provider string, subject string, requestID string) (*entity.User, error) {
+	ret := _m.Called(provider, subject, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByIdentity")
+	}
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string) (*entity.User, error)); ok {
+		return rf(provider, subject, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) *entity.User); ok {
+		r0 = rf(provider, subject, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(provider, subject, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUserByUsername provides a mock function with given fields: username, requestID
 func (_m *AuthRepositoryInterface) GetUserByUsername(username string, requestID string) (*entity.User, error) {
 	ret := _m.Called(username, requestID)
@@ -169,6 +391,298 @@ func (_m *AuthRepositoryInterface) GetUserByUsername(username string, requestID
 	return r0, r1
 }
 
+// LinkIdentity provides a mock function with given fields: userID, identity, requestID
+func (_m *AuthRepositoryInterface) LinkIdentity(userID int, identity entity.ExternalIdentity, requestID string) error {
+	ret := _m.Called(userID, identity, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LinkIdentity")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, entity.ExternalIdentity, string) error); ok {
+		r0 = rf(userID, identity, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListAPIKeys provides a mock function with given fields: userID, requestID
+func (_m *AuthRepositoryInterface) ListAPIKeys(userID int, requestID string) ([]entity.APIKey, error) {
+	ret := _m.Called(userID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAPIKeys")
+	}
+
+	var r0 []entity.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, string) ([]entity.APIKey, error)); ok {
+		return rf(userID, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(int, string) []entity.APIKey); ok {
+		r0 = rf(userID, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, string) error); ok {
+		r1 = rf(userID, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListActiveAPIKeys provides a mock function with given fields: requestID
+func (_m *AuthRepositoryInterface) ListActiveAPIKeys(requestID string) ([]entity.APIKey, error) {
+	ret := _m.Called(requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListActiveAPIKeys")
+	}
+
+	var r0 []entity.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]entity.APIKey, error)); ok {
+		return rf(requestID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []entity.APIKey); ok {
+		r0 = rf(requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListMemberships provides a mock function with given fields: userID, requestID
+func (_m *AuthRepositoryInterface) ListMemberships(userID int, requestID string) ([]entity.Membership, error) {
+	ret := _m.Called(userID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListMemberships")
+	}
+
+	var r0 []entity.Membership
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, string) ([]entity.Membership, error)); ok {
+		return rf(userID, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(int, string) []entity.Membership); ok {
+		r0 = rf(userID, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Membership)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, string) error); ok {
+		r1 = rf(userID, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListOrgMembers provides a mock function with given fields: orgID, requestID
+func (_m *AuthRepositoryInterface) ListOrgMembers(orgID int, requestID string) ([]entity.OrgMember, error) {
+	ret := _m.Called(orgID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOrgMembers")
+	}
+
+	var r0 []entity.OrgMember
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, string) ([]entity.OrgMember, error)); ok {
+		return rf(orgID, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(int, string) []entity.OrgMember); ok {
+		r0 = rf(orgID, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.OrgMember)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, string) error); ok {
+		r1 = rf(orgID, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveOrgMember provides a mock function with given fields: orgID, userID, actorID, requestID
+func (_m *AuthRepositoryInterface) RemoveOrgMember(orgID int, userID int, actorID int, requestID string) error {
+	ret := _m.Called(orgID, userID, actorID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveOrgMember")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int, int, string) error); ok {
+		r0 = rf(orgID, userID, actorID, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeAPIKey provides a mock function with given fields: userID, keyID, requestID
+func (_m *AuthRepositoryInterface) RevokeAPIKey(userID int, keyID int, requestID string) (entity.APIKey, error) {
+	ret := _m.Called(userID, keyID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAPIKey")
+	}
+
+	var r0 entity.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int, string) (entity.APIKey, error)); ok {
+		return rf(userID, keyID, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(int, int, string) entity.APIKey); ok {
+		r0 = rf(userID, keyID, requestID)
+	} else {
+		r0 = ret.Get(0).(entity.APIKey)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int, string) error); ok {
+		r1 = rf(userID, keyID, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveTOTPSecret provides a mock function with given fields: userID, secret, requestID
+func (_m *AuthRepositoryInterface) SaveTOTPSecret(userID int, secret string, requestID string) error {
+	ret := _m.Called(userID, secret, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveTOTPSecret")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string, string) error); ok {
+		r0 = rf(userID, secret, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetEmailVerified provides a mock function with given fields: userID, requestID
+func (_m *AuthRepositoryInterface) SetEmailVerified(userID int, requestID string) error {
+	ret := _m.Called(userID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetEmailVerified")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(userID, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateEmail provides a mock function with given fields: userID, oldEmail, newEmail, requestID
+func (_m *AuthRepositoryInterface) UpdateEmail(userID int, oldEmail string, newEmail string, requestID string) error {
+	ret := _m.Called(userID, oldEmail, newEmail, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string, string, string) error); ok {
+		r0 = rf(userID, oldEmail, newEmail, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateOrgMemberRole provides a mock function with given fields: orgID, userID, role, actorID, requestID
+func (_m *AuthRepositoryInterface) UpdateOrgMemberRole(orgID int, userID int, role string, actorID int, requestID string) error {
+	ret := _m.Called(orgID, userID, role, actorID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateOrgMemberRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int, string, int, string) error); ok {
+		r0 = rf(orgID, userID, role, actorID, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePassword provides a mock function with given fields: userID, passwordHash, requestID
+func (_m *AuthRepositoryInterface) UpdatePassword(userID int, passwordHash []byte, requestID string) error {
+	ret := _m.Called(userID, passwordHash, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, []byte, string) error); ok {
+		r0 = rf(userID, passwordHash, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseRecoveryCode provides a mock function with given fields: userID, codeHash, requestID
+func (_m *AuthRepositoryInterface) UseRecoveryCode(userID int, codeHash string, requestID string) error {
+	ret := _m.Called(userID, codeHash, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseRecoveryCode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string, string) error); ok {
+		r0 = rf(userID, codeHash, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewAuthRepositoryInterface creates a new instance of AuthRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewAuthRepositoryInterface(t interface {
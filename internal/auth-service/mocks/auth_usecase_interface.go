@@ -7,6 +7,10 @@ import (
 	entity "retarget/internal/auth-service/entity/auth"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	url "net/url"
 )
 
 // AuthUsecaseInterface is an autogenerated mock type for the AuthUsecaseInterface type
@@ -14,9 +18,39 @@ type AuthUsecaseInterface struct {
 	mock.Mock
 }
 
-// AddSession provides a mock function with given fields: userID, role
-func (_m *AuthUsecaseInterface) AddSession(userID int, role int) (*entity.Session, error) {
-	ret := _m.Called(userID, role)
+// AcceptOrgInvite provides a mock function with given fields: ctx, userID, token, requestID
+func (_m *AuthUsecaseInterface) AcceptOrgInvite(ctx context.Context, userID int, token string, requestID string) (*entity.Membership, error) {
+	ret := _m.Called(ctx, userID, token, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcceptOrgInvite")
+	}
+
+	var r0 *entity.Membership
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) (*entity.Membership, error)); ok {
+		return rf(ctx, userID, token, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) *entity.Membership); ok {
+		r0 = rf(ctx, userID, token, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Membership)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, string) error); ok {
+		r1 = rf(ctx, userID, token, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddSession provides a mock function with given fields: userID, role, verified, twoFactor, client
+func (_m *AuthUsecaseInterface) AddSession(userID int, role int, verified bool, twoFactor bool, client entity.SessionClient) (*entity.Session, error) {
+	ret := _m.Called(userID, role, verified, twoFactor, client)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AddSession")
@@ -24,19 +58,19 @@ func (_m *AuthUsecaseInterface) AddSession(userID int, role int) (*entity.Sessio
 
 	var r0 *entity.Session
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, int) (*entity.Session, error)); ok {
-		return rf(userID, role)
+	if rf, ok := ret.Get(0).(func(int, int, bool, bool, entity.SessionClient) (*entity.Session, error)); ok {
+		return rf(userID, role, verified, twoFactor, client)
 	}
-	if rf, ok := ret.Get(0).(func(int, int) *entity.Session); ok {
-		r0 = rf(userID, role)
+	if rf, ok := ret.Get(0).(func(int, int, bool, bool, entity.SessionClient) *entity.Session); ok {
+		r0 = rf(userID, role, verified, twoFactor, client)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.Session)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int, int) error); ok {
-		r1 = rf(userID, role)
+	if rf, ok := ret.Get(1).(func(int, int, bool, bool, entity.SessionClient) error); ok {
+		r1 = rf(userID, role, verified, twoFactor, client)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -44,6 +78,42 @@ func (_m *AuthUsecaseInterface) AddSession(userID int, role int) (*entity.Sessio
 	return r0, r1
 }
 
+// ChangeOrgMemberRole provides a mock function with given fields: ctx, userID, orgID, memberID, role, requestID
+func (_m *AuthUsecaseInterface) ChangeOrgMemberRole(ctx context.Context, userID int, orgID int, memberID int, role string, requestID string) error {
+	ret := _m.Called(ctx, userID, orgID, memberID, role, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeOrgMemberRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, string, string) error); ok {
+		r0 = rf(ctx, userID, orgID, memberID, role, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ChangePassword provides a mock function with given fields: ctx, userID, code, password, sessionID, requestID
+func (_m *AuthUsecaseInterface) ChangePassword(ctx context.Context, userID int, code int, password string, sessionID string, requestID string) error {
+	ret := _m.Called(ctx, userID, code, password, sessionID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string, string, string) error); ok {
+		r0 = rf(ctx, userID, code, password, sessionID, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CheckCode provides a mock function with given fields: code, userId
 func (_m *AuthUsecaseInterface) CheckCode(code int, userId int) error {
 	ret := _m.Called(code, userId)
@@ -62,6 +132,166 @@ func (_m *AuthUsecaseInterface) CheckCode(code int, userId int) error {
 	return r0
 }
 
+// CompleteOAuthLink provides a mock function with given fields: ctx, userID, providerName, code, state, extra, requestID
+func (_m *AuthUsecaseInterface) CompleteOAuthLink(ctx context.Context, userID int, providerName string, code string, state string, extra url.Values, requestID string) error {
+	ret := _m.Called(ctx, userID, providerName, code, state, extra, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteOAuthLink")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string, string, url.Values, string) error); ok {
+		r0 = rf(ctx, userID, providerName, code, state, extra, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CompleteOAuthLogin provides a mock function with given fields: ctx, providerName, code, state, extra, requestID
+func (_m *AuthUsecaseInterface) CompleteOAuthLogin(ctx context.Context, providerName string, code string, state string, extra url.Values, requestID string) (*entity.User, *entity.LoginChallenge, error) {
+	ret := _m.Called(ctx, providerName, code, state, extra, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteOAuthLogin")
+	}
+
+	var r0 *entity.User
+	var r1 *entity.LoginChallenge
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, url.Values, string) (*entity.User, *entity.LoginChallenge, error)); ok {
+		return rf(ctx, providerName, code, state, extra, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, url.Values, string) *entity.User); ok {
+		r0 = rf(ctx, providerName, code, state, extra, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, url.Values, string) *entity.LoginChallenge); ok {
+		r1 = rf(ctx, providerName, code, state, extra, requestID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*entity.LoginChallenge)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, string, url.Values, string) error); ok {
+		r2 = rf(ctx, providerName, code, state, extra, requestID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ConfirmEmail provides a mock function with given fields: ctx, userID, code, sessionID, requestID
+func (_m *AuthUsecaseInterface) ConfirmEmail(ctx context.Context, userID int, code int, sessionID string, requestID string) error {
+	ret := _m.Called(ctx, userID, code, sessionID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string, string) error); ok {
+		r0 = rf(ctx, userID, code, sessionID, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ConfirmEmailChange provides a mock function with given fields: ctx, userID, oldCode, newCode, sessionID, requestID
+func (_m *AuthUsecaseInterface) ConfirmEmailChange(ctx context.Context, userID int, oldCode int, newCode int, sessionID string, requestID string) error {
+	ret := _m.Called(ctx, userID, oldCode, newCode, sessionID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmEmailChange")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, string, string) error); ok {
+		r0 = rf(ctx, userID, oldCode, newCode, sessionID, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ConfirmTOTP provides a mock function with given fields: ctx, userID, code, sessionID, requestID
+func (_m *AuthUsecaseInterface) ConfirmTOTP(ctx context.Context, userID int, code string, sessionID string, requestID string) ([]string, error) {
+	ret := _m.Called(ctx, userID, code, sessionID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmTOTP")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string, string) ([]string, error)); ok {
+		return rf(ctx, userID, code, sessionID, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string, string) []string); ok {
+		r0 = rf(ctx, userID, code, sessionID, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, string, string) error); ok {
+		r1 = rf(ctx, userID, code, sessionID, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAPIKey provides a mock function with given fields: ctx, userID, role, name, scopes, expiresAt, requestID
+func (_m *AuthUsecaseInterface) CreateAPIKey(ctx context.Context, userID int, role int, name string, scopes []string, expiresAt *time.Time, requestID string) (*entity.APIKey, string, error) {
+	ret := _m.Called(ctx, userID, role, name, scopes, expiresAt, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAPIKey")
+	}
+
+	var r0 *entity.APIKey
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string, []string, *time.Time, string) (*entity.APIKey, string, error)); ok {
+		return rf(ctx, userID, role, name, scopes, expiresAt, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string, []string, *time.Time, string) *entity.APIKey); ok {
+		r0 = rf(ctx, userID, role, name, scopes, expiresAt, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, string, []string, *time.Time, string) string); ok {
+		r1 = rf(ctx, userID, role, name, scopes, expiresAt, requestID)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int, string, []string, *time.Time, string) error); ok {
+		r2 = rf(ctx, userID, role, name, scopes, expiresAt, requestID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // CreateCode provides a mock function with given fields: userId
 func (_m *AuthUsecaseInterface) CreateCode(userId int) (int, error) {
 	ret := _m.Called(userId)
@@ -90,6 +320,89 @@ func (_m *AuthUsecaseInterface) CreateCode(userId int) (int, error) {
 	return r0, r1
 }
 
+// CreateOrganization provides a mock function with given fields: ctx, userID, name, requestID
+func (_m *AuthUsecaseInterface) CreateOrganization(ctx context.Context, userID int, name string, requestID string) (*entity.Membership, error) {
+	ret := _m.Called(ctx, userID, name, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateOrganization")
+	}
+
+	var r0 *entity.Membership
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) (*entity.Membership, error)); ok {
+		return rf(ctx, userID, name, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) *entity.Membership); ok {
+		r0 = rf(ctx, userID, name, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Membership)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, string) error); ok {
+		r1 = rf(ctx, userID, name, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DisableTOTP provides a mock function with given fields: ctx, userID, password, code, requestID
+func (_m *AuthUsecaseInterface) DisableTOTP(ctx context.Context, userID int, password string, code string, requestID string) error {
+	ret := _m.Called(ctx, userID, password, code, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableTOTP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string, string) error); ok {
+		r0 = rf(ctx, userID, password, code, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnrollTOTP provides a mock function with given fields: ctx, userID, requestID
+func (_m *AuthUsecaseInterface) EnrollTOTP(ctx context.Context, userID int, requestID string) (string, string, error) {
+	ret := _m.Called(ctx, userID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnrollTOTP")
+	}
+
+	var r0 string
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (string, string, error)); ok {
+		return rf(ctx, userID, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) string); ok {
+		r0 = rf(ctx, userID, requestID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) string); ok {
+		r1 = rf(ctx, userID, requestID)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, string) error); ok {
+		r2 = rf(ctx, userID, requestID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetUser provides a mock function with given fields: ctx, userID, requestID
 func (_m *AuthUsecaseInterface) GetUser(ctx context.Context, userID int, requestID string) (*entity.User, error) {
 	ret := _m.Called(ctx, userID, requestID)
@@ -120,29 +433,47 @@ func (_m *AuthUsecaseInterface) GetUser(ctx context.Context, userID int, request
 	return r0, r1
 }
 
-// Login provides a mock function with given fields: ctx, email, password, role, requestID
-func (_m *AuthUsecaseInterface) Login(ctx context.Context, email string, password string, role int, requestID string) (*entity.User, error) {
-	ret := _m.Called(ctx, email, password, role, requestID)
+// InviteToOrganization provides a mock function with given fields: ctx, userID, orgID, email, role, requestID
+func (_m *AuthUsecaseInterface) InviteToOrganization(ctx context.Context, userID int, orgID int, email string, role string, requestID string) error {
+	ret := _m.Called(ctx, userID, orgID, email, role, requestID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Login")
+		panic("no return value specified for InviteToOrganization")
 	}
 
-	var r0 *entity.User
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string, string, string) error); ok {
+		r0 = rf(ctx, userID, orgID, email, role, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListAPIKeys provides a mock function with given fields: ctx, userID, requestID
+func (_m *AuthUsecaseInterface) ListAPIKeys(ctx context.Context, userID int, requestID string) ([]entity.APIKey, error) {
+	ret := _m.Called(ctx, userID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAPIKeys")
+	}
+
+	var r0 []entity.APIKey
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, string) (*entity.User, error)); ok {
-		return rf(ctx, email, password, role, requestID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) ([]entity.APIKey, error)); ok {
+		return rf(ctx, userID, requestID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, string) *entity.User); ok {
-		r0 = rf(ctx, email, password, role, requestID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) []entity.APIKey); ok {
+		r0 = rf(ctx, userID, requestID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*entity.User)
+			r0 = ret.Get(0).([]entity.APIKey)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, int, string) error); ok {
-		r1 = rf(ctx, email, password, role, requestID)
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, userID, requestID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -150,6 +481,135 @@ func (_m *AuthUsecaseInterface) Login(ctx context.Context, email string, passwor
 	return r0, r1
 }
 
+// ListOrgMembers provides a mock function with given fields: ctx, userID, orgID, requestID
+func (_m *AuthUsecaseInterface) ListOrgMembers(ctx context.Context, userID int, orgID int, requestID string) ([]entity.OrgMember, error) {
+	ret := _m.Called(ctx, userID, orgID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOrgMembers")
+	}
+
+	var r0 []entity.OrgMember
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) ([]entity.OrgMember, error)); ok {
+		return rf(ctx, userID, orgID, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) []entity.OrgMember); ok {
+		r0 = rf(ctx, userID, orgID, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.OrgMember)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, string) error); ok {
+		r1 = rf(ctx, userID, orgID, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListOrganizations provides a mock function with given fields: ctx, userID, requestID
+func (_m *AuthUsecaseInterface) ListOrganizations(ctx context.Context, userID int, requestID string) ([]entity.Membership, error) {
+	ret := _m.Called(ctx, userID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOrganizations")
+	}
+
+	var r0 []entity.Membership
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) ([]entity.Membership, error)); ok {
+		return rf(ctx, userID, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) []entity.Membership); ok {
+		r0 = rf(ctx, userID, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Membership)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, userID, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSessions provides a mock function with given fields: ctx, userID, requestID
+func (_m *AuthUsecaseInterface) ListSessions(ctx context.Context, userID int, requestID string) ([]entity.Session, error) {
+	ret := _m.Called(ctx, userID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSessions")
+	}
+
+	var r0 []entity.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) ([]entity.Session, error)); ok {
+		return rf(ctx, userID, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) []entity.Session); ok {
+		r0 = rf(ctx, userID, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, userID, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Login provides a mock function with given fields: ctx, email, password, role, ip, requestID
+func (_m *AuthUsecaseInterface) Login(ctx context.Context, email string, password string, role int, ip string, requestID string) (*entity.User, *entity.LoginChallenge, error) {
+	ret := _m.Called(ctx, email, password, role, ip, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Login")
+	}
+
+	var r0 *entity.User
+	var r1 *entity.LoginChallenge
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, string, string) (*entity.User, *entity.LoginChallenge, error)); ok {
+		return rf(ctx, email, password, role, ip, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, string, string) *entity.User); ok {
+		r0 = rf(ctx, email, password, role, ip, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int, string, string) *entity.LoginChallenge); ok {
+		r1 = rf(ctx, email, password, role, ip, requestID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*entity.LoginChallenge)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, int, string, string) error); ok {
+		r2 = rf(ctx, email, password, role, ip, requestID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // Logout provides a mock function with given fields: sessionId
 func (_m *AuthUsecaseInterface) Logout(sessionId string) error {
 	ret := _m.Called(sessionId)
@@ -198,6 +658,284 @@ func (_m *AuthUsecaseInterface) Register(ctx context.Context, username string, e
 	return r0, r1
 }
 
+// RemoveOrgMember provides a mock function with given fields: ctx, userID, orgID, memberID, requestID
+func (_m *AuthUsecaseInterface) RemoveOrgMember(ctx context.Context, userID int, orgID int, memberID int, requestID string) error {
+	ret := _m.Called(ctx, userID, orgID, memberID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveOrgMember")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, string) error); ok {
+		r0 = rf(ctx, userID, orgID, memberID, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RequestEmailChange provides a mock function with given fields: ctx, userID, newEmail, requestID
+func (_m *AuthUsecaseInterface) RequestEmailChange(ctx context.Context, userID int, newEmail string, requestID string) error {
+	ret := _m.Called(ctx, userID, newEmail, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestEmailChange")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) error); ok {
+		r0 = rf(ctx, userID, newEmail, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RequestPasswordChange provides a mock function with given fields: ctx, userID, oldPassword, requestID
+func (_m *AuthUsecaseInterface) RequestPasswordChange(ctx context.Context, userID int, oldPassword string, requestID string) error {
+	ret := _m.Called(ctx, userID, oldPassword, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestPasswordChange")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) error); ok {
+		r0 = rf(ctx, userID, oldPassword, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RequestPasswordReset provides a mock function with given fields: ctx, email, requestID
+func (_m *AuthUsecaseInterface) RequestPasswordReset(ctx context.Context, email string, requestID string) error {
+	ret := _m.Called(ctx, email, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestPasswordReset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, email, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResetPassword provides a mock function with given fields: ctx, token, password, requestID
+func (_m *AuthUsecaseInterface) ResetPassword(ctx context.Context, token string, password string, requestID string) error {
+	ret := _m.Called(ctx, token, password, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, token, password, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeAPIKey provides a mock function with given fields: ctx, userID, keyID, requestID
+func (_m *AuthUsecaseInterface) RevokeAPIKey(ctx context.Context, userID int, keyID int, requestID string) error {
+	ret := _m.Called(ctx, userID, keyID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAPIKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) error); ok {
+		r0 = rf(ctx, userID, keyID, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeAllSessions provides a mock function with given fields: ctx, userID, requestID
+func (_m *AuthUsecaseInterface) RevokeAllSessions(ctx context.Context, userID int, requestID string) error {
+	ret := _m.Called(ctx, userID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAllSessions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, userID, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeSession provides a mock function with given fields: ctx, userID, handle, requestID
+func (_m *AuthUsecaseInterface) RevokeSession(ctx context.Context, userID int, handle string, requestID string) error {
+	ret := _m.Called(ctx, userID, handle, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) error); ok {
+		r0 = rf(ctx, userID, handle, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendVerificationCode provides a mock function with given fields: ctx, userID, requestID
+func (_m *AuthUsecaseInterface) SendVerificationCode(ctx context.Context, userID int, requestID string) error {
+	ret := _m.Called(ctx, userID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendVerificationCode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, userID, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StartOAuthLink provides a mock function with given fields: ctx, userID, providerName, requestID
+func (_m *AuthUsecaseInterface) StartOAuthLink(ctx context.Context, userID int, providerName string, requestID string) (string, error) {
+	ret := _m.Called(ctx, userID, providerName, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartOAuthLink")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) (string, error)); ok {
+		return rf(ctx, userID, providerName, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) string); ok {
+		r0 = rf(ctx, userID, providerName, requestID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, string) error); ok {
+		r1 = rf(ctx, userID, providerName, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StartOAuthLogin provides a mock function with given fields: ctx, providerName, role, requestID
+func (_m *AuthUsecaseInterface) StartOAuthLogin(ctx context.Context, providerName string, role int, requestID string) (string, error) {
+	ret := _m.Called(ctx, providerName, role, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartOAuthLogin")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, string) (string, error)); ok {
+		return rf(ctx, providerName, role, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, string) string); ok {
+		r0 = rf(ctx, providerName, role, requestID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, string) error); ok {
+		r1 = rf(ctx, providerName, role, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SwitchOrganization provides a mock function with given fields: ctx, userID, orgID, sessionID, requestID
+func (_m *AuthUsecaseInterface) SwitchOrganization(ctx context.Context, userID int, orgID int, sessionID string, requestID string) (*entity.Membership, error) {
+	ret := _m.Called(ctx, userID, orgID, sessionID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SwitchOrganization")
+	}
+
+	var r0 *entity.Membership
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string, string) (*entity.Membership, error)); ok {
+		return rf(ctx, userID, orgID, sessionID, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string, string) *entity.Membership); ok {
+		r0 = rf(ctx, userID, orgID, sessionID, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Membership)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, string, string) error); ok {
+		r1 = rf(ctx, userID, orgID, sessionID, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyLoginChallenge provides a mock function with given fields: ctx, challengeID, code, client, requestID
+func (_m *AuthUsecaseInterface) VerifyLoginChallenge(ctx context.Context, challengeID string, code string, client entity.SessionClient, requestID string) (*entity.Session, error) {
+	ret := _m.Called(ctx, challengeID, code, client, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyLoginChallenge")
+	}
+
+	var r0 *entity.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, entity.SessionClient, string) (*entity.Session, error)); ok {
+		return rf(ctx, challengeID, code, client, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, entity.SessionClient, string) *entity.Session); ok {
+		r0 = rf(ctx, challengeID, code, client, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, entity.SessionClient, string) error); ok {
+		r1 = rf(ctx, challengeID, code, client, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewAuthUsecaseInterface creates a new instance of AuthUsecaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewAuthUsecaseInterface(t interface {
@@ -13,9 +13,9 @@ type SessionRepositoryInterface struct {
 	mock.Mock
 }
 
-// AddSession provides a mock function with given fields: userId, role
-func (_m *SessionRepositoryInterface) AddSession(userId int, role int) (*entity.Session, error) {
-	ret := _m.Called(userId, role)
+// AddSession provides a mock function with given fields: userId, role, verified, twoFactor, client
+func (_m *SessionRepositoryInterface) AddSession(userId int, role int, verified bool, twoFactor bool, client entity.SessionClient) (*entity.Session, error) {
+	ret := _m.Called(userId, role, verified, twoFactor, client)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AddSession")
@@ -23,19 +23,19 @@ func (_m *SessionRepositoryInterface) AddSession(userId int, role int) (*entity.
 
 	var r0 *entity.Session
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, int) (*entity.Session, error)); ok {
-		return rf(userId, role)
+	if rf, ok := ret.Get(0).(func(int, int, bool, bool, entity.SessionClient) (*entity.Session, error)); ok {
+		return rf(userId, role, verified, twoFactor, client)
 	}
-	if rf, ok := ret.Get(0).(func(int, int) *entity.Session); ok {
-		r0 = rf(userId, role)
+	if rf, ok := ret.Get(0).(func(int, int, bool, bool, entity.SessionClient) *entity.Session); ok {
+		r0 = rf(userId, role, verified, twoFactor, client)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.Session)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int, int) error); ok {
-		r1 = rf(userId, role)
+	if rf, ok := ret.Get(1).(func(int, int, bool, bool, entity.SessionClient) error); ok {
+		r1 = rf(userId, role, verified, twoFactor, client)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -61,6 +61,24 @@ func (_m *SessionRepositoryInterface) CloseConnection() error {
 	return r0
 }
 
+// DelAPIKey provides a mock function with given fields: keyHash
+func (_m *SessionRepositoryInterface) DelAPIKey(keyHash string) error {
+	ret := _m.Called(keyHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DelAPIKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(keyHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DelSession provides a mock function with given fields: sessionId
 func (_m *SessionRepositoryInterface) DelSession(sessionId string) error {
 	ret := _m.Called(sessionId)
@@ -79,6 +97,42 @@ func (_m *SessionRepositoryInterface) DelSession(sessionId string) error {
 	return r0
 }
 
+// DelUserSession provides a mock function with given fields: userId, handle
+func (_m *SessionRepositoryInterface) DelUserSession(userId int, handle string) error {
+	ret := _m.Called(userId, handle)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DelUserSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(userId, handle)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DelUserSessions provides a mock function with given fields: userId, exceptSessionId
+func (_m *SessionRepositoryInterface) DelUserSessions(userId int, exceptSessionId string) error {
+	ret := _m.Called(userId, exceptSessionId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DelUserSessions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(userId, exceptSessionId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetSession provides a mock function with given fields: sessionId
 func (_m *SessionRepositoryInterface) GetSession(sessionId string) (*entity.Session, error) {
 	ret := _m.Called(sessionId)
@@ -109,6 +163,126 @@ func (_m *SessionRepositoryInterface) GetSession(sessionId string) (*entity.Sess
 	return r0, r1
 }
 
+// ListUserSessions provides a mock function with given fields: userId
+func (_m *SessionRepositoryInterface) ListUserSessions(userId int) ([]entity.Session, error) {
+	ret := _m.Called(userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUserSessions")
+	}
+
+	var r0 []entity.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]entity.Session, error)); ok {
+		return rf(userId)
+	}
+	if rf, ok := ret.Get(0).(func(int) []entity.Session); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkSessionTwoFactor provides a mock function with given fields: sessionId
+func (_m *SessionRepositoryInterface) MarkSessionTwoFactor(sessionId string) error {
+	ret := _m.Called(sessionId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkSessionTwoFactor")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(sessionId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MarkSessionVerified provides a mock function with given fields: sessionId
+func (_m *SessionRepositoryInterface) MarkSessionVerified(sessionId string) error {
+	ret := _m.Called(sessionId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkSessionVerified")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(sessionId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveAPIKey provides a mock function with given fields: key
+func (_m *SessionRepositoryInterface) SaveAPIKey(key entity.APIKey) error {
+	ret := _m.Called(key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveAPIKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.APIKey) error); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SwitchSessionOrg provides a mock function with given fields: sessionId, orgID, orgRole, role
+func (_m *SessionRepositoryInterface) SwitchSessionOrg(sessionId string, orgID int, orgRole string, role int) error {
+	ret := _m.Called(sessionId, orgID, orgRole, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SwitchSessionOrg")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int, string, int) error); ok {
+		r0 = rf(sessionId, orgID, orgRole, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUserSessionsOrg provides a mock function with given fields: userId, orgID, orgRole, role
+func (_m *SessionRepositoryInterface) UpdateUserSessionsOrg(userId int, orgID int, orgRole string, role int) error {
+	ret := _m.Called(userId, orgID, orgRole, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserSessionsOrg")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int, string, int) error); ok {
+		r0 = rf(userId, orgID, orgRole, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // generateSessionID provides a mock function with no fields
 func (_m *SessionRepositoryInterface) generateSessionID() (string, error) {
 	ret := _m.Called()
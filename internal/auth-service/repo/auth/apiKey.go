@@ -0,0 +1,170 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	authEntity "retarget/internal/auth-service/entity/auth"
+	authenticate "retarget/pkg/middleware/auth"
+	"retarget/pkg/utils/optiLog"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap/zapcore"
+)
+
+// -----------------------------
+// API-ключи (auth_api_key)
+// -----------------------------
+
+// apiKeyColumns читаются из auth_api_key k, соединённой с auth_user u: роль
+// ключа — текущая роль владельца
+const apiKeyColumns = "k.id, k.user_id, u.role, k.name, k.prefix, k.key_hash, k.scopes, k.expires_at, k.created_at"
+
+func scanAPIKey(row interface{ Scan(...interface{}) error }) (authEntity.APIKey, error) {
+	var key authEntity.APIKey
+	var expires sql.NullTime
+	err := row.Scan(&key.ID, &key.UserID, &key.Role, &key.Name, &key.Prefix, &key.KeyHash, pq.Array(&key.Scopes), &expires, &key.CreatedAt)
+	if expires.Valid {
+		key.ExpiresAt = &expires.Time
+	}
+	return key, err
+}
+
+// CreateAPIKey сохраняет выпущенный ключ. Больше MaxAPIKeysPerUser действующих
+// ключей у пользователя быть не может: authEntity.ErrAPIKeyLimit
+func (r *AuthRepository) CreateAPIKey(key *authEntity.APIKey, requestID string) error {
+	details, err := json.Marshal(map[string]interface{}{"name": key.Name, "prefix": key.Prefix, "scopes": key.Scopes})
+	if err != nil {
+		return err
+	}
+
+	return r.inTx(requestID, "API key creation failed", key.UserID, func(ctx context.Context, tx *sql.Tx) error {
+		// блокировка пользователя не даёт параллельным запросам обойти предел
+		if _, err := tx.ExecContext(ctx, "SELECT 1 FROM auth_user WHERE id = $1 FOR UPDATE", key.UserID); err != nil {
+			return err
+		}
+		var active int
+		err := tx.QueryRowContext(ctx, `SELECT count(*) FROM auth_api_key
+			WHERE user_id = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > (now() AT TIME ZONE 'UTC'))`, key.UserID).Scan(&active)
+		if err != nil {
+			return err
+		}
+		if active >= authEntity.MaxAPIKeysPerUser {
+			return authEntity.ErrAPIKeyLimit
+		}
+
+		var expires sql.NullTime
+		if key.ExpiresAt != nil {
+			expires = sql.NullTime{Time: key.ExpiresAt.UTC(), Valid: true}
+		}
+		err = tx.QueryRowContext(ctx, `INSERT INTO auth_api_key (user_id, name, prefix, key_hash, scopes, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`,
+			key.UserID, key.Name, key.Prefix, key.KeyHash, pq.Array(key.Scopes), expires,
+		).Scan(&key.ID, &key.CreatedAt)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "INSERT INTO auth_audit_log (user_id, event, details, request_id) VALUES ($1, $2, $3, $4)",
+			key.UserID, authEntity.AuditAPIKeyCreated, details, requestID)
+		return err
+	})
+}
+
+// ListAPIKeys возвращает неотозванные ключи пользователя, новые первыми
+func (r *AuthRepository) ListAPIKeys(userID int, requestID string) ([]authEntity.APIKey, error) {
+	return r.queryAPIKeys(requestID, `SELECT `+apiKeyColumns+` FROM auth_api_key k JOIN auth_user u ON u.id = k.user_id
+		WHERE k.user_id = $1 AND k.revoked_at IS NULL ORDER BY k.created_at DESC`, userID)
+}
+
+// ListActiveAPIKeys возвращает все действующие ключи — для восстановления их в Redis
+func (r *AuthRepository) ListActiveAPIKeys(requestID string) ([]authEntity.APIKey, error) {
+	return r.queryAPIKeys(requestID, `SELECT `+apiKeyColumns+` FROM auth_api_key k JOIN auth_user u ON u.id = k.user_id
+		WHERE k.revoked_at IS NULL AND (k.expires_at IS NULL OR k.expires_at > (now() AT TIME ZONE 'UTC'))`)
+}
+
+func (r *AuthRepository) queryAPIKeys(requestID string, query string, args ...interface{}) ([]authEntity.APIKey, error) {
+	startTime := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err == nil {
+		defer rows.Close()
+	}
+
+	keys := []authEntity.APIKey{}
+	for err == nil && rows.Next() {
+		var key authEntity.APIKey
+		if key, err = scanAPIKey(rows); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	if err == nil {
+		err = rows.Err()
+	}
+	if err != nil {
+		r.asyncLogger.Log(zapcore.WarnLevel, requestID, "API keys query failed",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"error": err.Error(),
+			}))
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey отзывает ключ пользователя и возвращает его. Чужой или уже
+// отозванный ключ — authEntity.ErrAPIKeyNotFound
+func (r *AuthRepository) RevokeAPIKey(userID int, keyID int, requestID string) (authEntity.APIKey, error) {
+	var key authEntity.APIKey
+	err := r.inTx(requestID, "API key revocation failed", userID, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		key, err = scanAPIKey(tx.QueryRowContext(ctx, `UPDATE auth_api_key k SET revoked_at = (now() AT TIME ZONE 'UTC')
+			FROM auth_user u WHERE u.id = k.user_id AND k.id = $1 AND k.user_id = $2 AND k.revoked_at IS NULL
+			RETURNING `+apiKeyColumns, keyID, userID))
+		if err == sql.ErrNoRows {
+			return authEntity.ErrAPIKeyNotFound
+		}
+		if err != nil {
+			return err
+		}
+		details, err := json.Marshal(map[string]interface{}{"key_id": key.ID, "prefix": key.Prefix})
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "INSERT INTO auth_audit_log (user_id, event, details, request_id) VALUES ($1, $2, $3, $4)",
+			userID, authEntity.AuditAPIKeyRevoked, details, requestID)
+		return err
+	})
+	return key, err
+}
+
+func apiKeyData(key authEntity.APIKey) authenticate.APIKeyData {
+	data := authenticate.APIKeyData{KeyID: key.ID, UserID: key.UserID, Role: key.Role, Scopes: key.Scopes}
+	if key.ExpiresAt != nil {
+		data.Expires = *key.ExpiresAt
+	}
+	return data
+}
+
+// SaveAPIKey публикует ключ для проверки сервисами. Ключ со сроком Redis
+// удалит сам
+func (r *SessionRepository) SaveAPIKey(key authEntity.APIKey) error {
+	var ttl time.Duration
+	if key.ExpiresAt != nil {
+		if ttl = time.Until(*key.ExpiresAt); ttl <= 0 {
+			return nil
+		}
+	}
+	data, err := json.Marshal(apiKeyData(key))
+	if err != nil {
+		return err
+	}
+	return r.client.Set(context.Background(), authenticate.APIKeyRedisKey(key.KeyHash), data, ttl).Err()
+}
+
+func (r *SessionRepository) DelAPIKey(keyHash string) error {
+	return r.client.Del(context.Background(), authenticate.APIKeyRedisKey(keyHash)).Err()
+}
@@ -0,0 +1,78 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	authEntity "retarget/internal/auth-service/entity/auth"
+	"retarget/pkg/utils/totp"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func challengeKey(id string) string {
+	return "challenge:" + id
+}
+
+// SaveChallenge сохраняет вход, ожидающий второй фактор
+func (r *CodeRepository) SaveChallenge(ch authEntity.LoginChallenge, ttl time.Duration) error {
+	ctx := context.Background()
+
+	key := challengeKey(ch.ID)
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, "user_id", ch.UserID, "email", ch.Email, "role", ch.Role, "verified", ch.Verified, "attempts", 0)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	return err
+}
+
+func (r *CodeRepository) GetChallenge(id string) (authEntity.LoginChallenge, error) {
+	ctx := context.Background()
+
+	values, err := r.client.HGetAll(ctx, challengeKey(id)).Result()
+	if err != nil {
+		return authEntity.LoginChallenge{}, err
+	}
+	if values["user_id"] == "" {
+		return authEntity.LoginChallenge{}, authEntity.ErrChallengeNotFound
+	}
+
+	ch := authEntity.LoginChallenge{ID: id, Email: values["email"]}
+	if ch.UserID, err = strconv.Atoi(values["user_id"]); err != nil {
+		return ch, fmt.Errorf("invalid challenge: %w", err)
+	}
+	if ch.Role, err = strconv.Atoi(values["role"]); err != nil {
+		return ch, fmt.Errorf("invalid challenge: %w", err)
+	}
+	ch.Verified = values["verified"] == "1"
+	ch.Attempts, _ = strconv.Atoi(values["attempts"])
+	return ch, nil
+}
+
+// IncrementChallengeAttempts учитывает неверный второй фактор
+func (r *CodeRepository) IncrementChallengeAttempts(id string) (int, error) {
+	attempts, err := incrementAttemptsScript.Run(context.Background(), r.client, []string{challengeKey(id)}).Int()
+	if err != nil {
+		return 0, err
+	}
+	if attempts < 0 {
+		return 0, authEntity.ErrChallengeNotFound
+	}
+	return attempts, nil
+}
+
+func (r *CodeRepository) DelChallenge(id string) error {
+	return r.client.Del(context.Background(), challengeKey(id)).Err()
+}
+
+// MarkTOTPUsed отмечает шаг TOTP использованным; false — код этого шага уже
+// вводили, и повторно он не принимается
+func (r *CodeRepository) MarkTOTPUsed(userId int, step int64) (bool, error) {
+	key := fmt.Sprintf("totp_used:%d:%d", userId, step)
+	// код шага step принимается, пока идут шаги до step+Skew включительно
+	ttl := time.Duration(2*totp.Skew+1) * totp.Period
+	return r.client.SetNX(context.Background(), key, 1, ttl).Result()
+}
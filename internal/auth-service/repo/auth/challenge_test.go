@@ -0,0 +1,45 @@
+package repo
+
+import (
+	"testing"
+	"time"
+
+	authEntity "retarget/internal/auth-service/entity/auth"
+)
+
+func TestChallenge_Lifecycle(t *testing.T) {
+	repo, s := setupCodeRepo(t)
+	ch := authEntity.LoginChallenge{ID: "c1", UserID: 4, Role: 2, Verified: true}
+	if err := repo.SaveChallenge(ch, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := repo.GetChallenge("c1")
+	if err != nil || got.UserID != 4 || got.Role != 2 || !got.Verified || got.Attempts != 0 {
+		t.Fatalf("unexpected challenge %+v, err %v", got, err)
+	}
+	if n, err := repo.IncrementChallengeAttempts("c1"); err != nil || n != 1 {
+		t.Errorf("expected 1 attempt, got %d, err %v", n, err)
+	}
+
+	s.FastForward(2 * time.Minute)
+	if _, err := repo.GetChallenge("c1"); err != authEntity.ErrChallengeNotFound {
+		t.Errorf("expected expired challenge, got %v", err)
+	}
+	if _, err := repo.IncrementChallengeAttempts("c1"); err != authEntity.ErrChallengeNotFound {
+		t.Errorf("expected expired challenge, got %v", err)
+	}
+}
+
+func TestMarkTOTPUsed(t *testing.T) {
+	repo, _ := setupCodeRepo(t)
+	if fresh, err := repo.MarkTOTPUsed(4, 100); err != nil || !fresh {
+		t.Fatalf("expected first use accepted, got %v, err %v", fresh, err)
+	}
+	if fresh, _ := repo.MarkTOTPUsed(4, 100); fresh {
+		t.Error("expected replay rejected")
+	}
+	if fresh, _ := repo.MarkTOTPUsed(4, 101); !fresh {
+		t.Error("expected next step accepted")
+	}
+}
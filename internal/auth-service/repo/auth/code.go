@@ -0,0 +1,238 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	authEntity "retarget/internal/auth-service/entity/auth"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type CodeRepositoryInterface interface {
+	SaveCode(purpose string, userId int, code authEntity.StoredCode) error
+	VerifyCode(purpose string, userId int, hash func(salt string) string, consume bool) (authEntity.StoredCode, error)
+	DelCode(purpose string, userId int) error
+	SaveToken(purpose string, userId int, tokenHash string, ttl time.Duration) error
+	TakeToken(purpose string, tokenHash string) (int, error)
+	SaveChallenge(ch authEntity.LoginChallenge, ttl time.Duration) error
+	GetChallenge(id string) (authEntity.LoginChallenge, error)
+	IncrementChallengeAttempts(id string) (int, error)
+	DelChallenge(id string) error
+	MarkTOTPUsed(userId int, step int64) (bool, error)
+	SaveOAuthState(state string, st authEntity.OAuthState, ttl time.Duration) error
+	TakeOAuthState(state string) (authEntity.OAuthState, error)
+	CloseConnection() error
+}
+
+// CodeRepository хранит одноразовые коды подтверждения в Redis с TTL
+type CodeRepository struct {
+	client *redis.Client
+	ttl    time.Duration
+	resend time.Duration
+}
+
+func NewCodeRepository(endpoint, password string, db int, ttl, resend time.Duration) *CodeRepository {
+	client := redis.NewClient(&redis.Options{
+		Addr:     endpoint,
+		Password: password,
+		DB:       db,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Fatal("Failed to connect to Redis:", err)
+	}
+
+	return &CodeRepository{
+		client: client,
+		ttl:    ttl,
+		resend: resend,
+	}
+}
+
+func (r *CodeRepository) CloseConnection() error {
+	if r.client != nil {
+		return r.client.Close()
+	}
+	return nil
+}
+
+func codeKey(purpose string, userId int) string {
+	return fmt.Sprintf("code:%s:%d", purpose, userId)
+}
+
+func codeSentKey(purpose string, userId int) string {
+	return codeKey(purpose, userId) + ":sent"
+}
+
+// SaveCode заменяет код пользователя новым. Чаще, чем раз в resend, код не
+// перевыпускается: возвращается authEntity.ErrCodeCooldown
+func (r *CodeRepository) SaveCode(purpose string, userId int, code authEntity.StoredCode) error {
+	ctx := context.Background()
+
+	fresh, err := r.client.SetNX(ctx, codeSentKey(purpose, userId), 1, r.resend).Result()
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return authEntity.ErrCodeCooldown
+	}
+
+	key := codeKey(purpose, userId)
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key, "hash", code.Hash, "salt", code.Salt, "attempts", 0, "payload", code.Payload)
+		pipe.Expire(ctx, key, r.ttl)
+		return nil
+	})
+	return err
+}
+
+// incrementAttemptsScript не воскрешает истёкший ключ: HINCRBY по
+// отсутствующему ключу создал бы его без TTL
+var incrementAttemptsScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return -1
+end
+return redis.call('HINCRBY', KEYS[1], 'attempts', 1)`)
+
+// verifyCodeScript сверяет хэш кода и учитывает неверный ввод одним шагом,
+// чтобы параллельные запросы не получили больше CodeMaxAttempts попыток.
+// Сгоревший код удаляется без ключа повторной отправки: новый код можно
+// запросить не раньше, чем закончится ограничение. С ARGV[4] = 1 верный код
+// гасится тем же шагом, и параллельный запрос с ним же получит статус 3.
+// Ответ: {статус, число неверных попыток, payload}; статус 0 — код верный,
+// 1 — неверный, 2 — попытки исчерпаны, 3 — кода нет или его перевыпустили
+var verifyCodeScript = redis.NewScript(`
+local stored = redis.call('HMGET', KEYS[1], 'hash', 'salt', 'attempts', 'payload')
+if not stored[1] or stored[2] ~= ARGV[2] then
+	return {3, 0, ''}
+end
+local attempts = tonumber(stored[3])
+if attempts >= tonumber(ARGV[3]) then
+	redis.call('DEL', KEYS[1])
+	return {2, attempts, ''}
+end
+if stored[1] == ARGV[1] then
+	if ARGV[4] == '1' then
+		redis.call('DEL', KEYS[1])
+	end
+	return {0, attempts, stored[4]}
+end
+attempts = redis.call('HINCRBY', KEYS[1], 'attempts', 1)
+if attempts >= tonumber(ARGV[3]) then
+	redis.call('DEL', KEYS[1])
+	return {2, attempts, ''}
+end
+return {1, attempts, ''}`)
+
+// VerifyCode сверяет код. hash считает хэш введённого кода с солью
+// сохранённого. consume гасит верный код в том же скрипте и снимает
+// ограничение на повторную отправку, как DelCode. Неверный ввод учитывается,
+// после authEntity.CodeMaxAttempts неверных вводов код удаляется
+func (r *CodeRepository) VerifyCode(purpose string, userId int, hash func(salt string) string, consume bool) (authEntity.StoredCode, error) {
+	ctx := context.Background()
+	key := codeKey(purpose, userId)
+
+	salt, err := r.client.HGet(ctx, key, "salt").Result()
+	if err == redis.Nil {
+		return authEntity.StoredCode{}, authEntity.ErrCodeExpired
+	}
+	if err != nil {
+		return authEntity.StoredCode{}, err
+	}
+
+	consumeArg := 0
+	if consume {
+		consumeArg = 1
+	}
+	res, err := verifyCodeScript.Run(ctx, r.client, []string{key}, hash(salt), salt, authEntity.CodeMaxAttempts, consumeArg).Slice()
+	if err != nil {
+		return authEntity.StoredCode{}, err
+	}
+	if len(res) != 3 {
+		return authEntity.StoredCode{}, fmt.Errorf("unexpected verify code reply: %v", res)
+	}
+	status, _ := res[0].(int64)
+	attempts, _ := res[1].(int64)
+	payload, _ := res[2].(string)
+
+	stored := authEntity.StoredCode{Salt: salt, Attempts: int(attempts), Payload: payload}
+	switch status {
+	case 0:
+		if consume {
+			// код уже погашен скриптом, ключ отправки живёт в другом слоте кластера
+			if err := r.client.Del(ctx, codeSentKey(purpose, userId)).Err(); err != nil {
+				return stored, err
+			}
+		}
+		return stored, nil
+	case 1:
+		return stored, authEntity.ErrCodeInvalid
+	case 2:
+		return stored, authEntity.ErrCodeAttempts
+	default:
+		return stored, authEntity.ErrCodeExpired
+	}
+}
+
+// DelCode удаляет код и снимает ограничение на повторную отправку
+func (r *CodeRepository) DelCode(purpose string, userId int) error {
+	return r.client.Del(context.Background(), codeKey(purpose, userId), codeSentKey(purpose, userId)).Err()
+}
+
+func tokenKey(purpose, tokenHash string) string {
+	return fmt.Sprintf("token:%s:%s", purpose, tokenHash)
+}
+
+// SaveToken выпускает одноразовый токен пользователя, отзывая предыдущий.
+// Ограничение на повторную отправку общее с кодами того же назначения
+func (r *CodeRepository) SaveToken(purpose string, userId int, tokenHash string, ttl time.Duration) error {
+	ctx := context.Background()
+
+	fresh, err := r.client.SetNX(ctx, codeSentKey(purpose, userId), 1, r.resend).Result()
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return authEntity.ErrCodeCooldown
+	}
+
+	// codeKey хранит хеш действующего токена, чтобы его можно было отозвать
+	key := codeKey(purpose, userId)
+	previous, err := r.client.Get(ctx, key).Result()
+	if err != nil && err != redis.Nil {
+		return err
+	}
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if previous != "" {
+			pipe.Del(ctx, tokenKey(purpose, previous))
+		}
+		pipe.Set(ctx, tokenKey(purpose, tokenHash), userId, ttl)
+		pipe.Set(ctx, key, tokenHash, ttl)
+		return nil
+	})
+	return err
+}
+
+// TakeToken гасит токен и возвращает его владельца. Повторно тот же токен
+// не принимается: authEntity.ErrTokenInvalid
+func (r *CodeRepository) TakeToken(purpose string, tokenHash string) (int, error) {
+	ctx := context.Background()
+
+	userId, err := r.client.GetDel(ctx, tokenKey(purpose, tokenHash)).Int()
+	if err == redis.Nil {
+		return 0, authEntity.ErrTokenInvalid
+	}
+	if err != nil {
+		return 0, err
+	}
+	if err := r.DelCode(purpose, userId); err != nil {
+		return 0, err
+	}
+	return userId, nil
+}
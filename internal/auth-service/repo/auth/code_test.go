@@ -0,0 +1,168 @@
+package repo
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	authEntity "retarget/internal/auth-service/entity/auth"
+)
+
+func setupCodeRepo(t *testing.T) (*CodeRepository, *miniredis.Miniredis) {
+	s, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("miniredis start failed: %v", err)
+	}
+	t.Cleanup(s.Close)
+	return NewCodeRepository(s.Addr(), "", 0, time.Minute, 10*time.Second), s
+}
+
+func TestSaveCode_Cooldown(t *testing.T) {
+	repo, s := setupCodeRepo(t)
+	code := authEntity.StoredCode{Hash: "h", Salt: "s"}
+
+	if err := repo.SaveCode(authEntity.CodeRegister, 1, code); err != nil {
+		t.Fatalf("SaveCode error: %v", err)
+	}
+	if err := repo.SaveCode(authEntity.CodeRegister, 1, code); err != authEntity.ErrCodeCooldown {
+		t.Errorf("expected ErrCodeCooldown, got %v", err)
+	}
+
+	s.FastForward(11 * time.Second)
+	if err := repo.SaveCode(authEntity.CodeRegister, 1, code); err != nil {
+		t.Errorf("expected new code after cooldown, got %v", err)
+	}
+	if ttl := s.TTL(codeKey(authEntity.CodeRegister, 1)); ttl != time.Minute {
+		t.Errorf("expected code ttl 1m, got %v", ttl)
+	}
+}
+
+func hashAs(hash string) func(string) string {
+	return func(string) string { return hash }
+}
+
+func TestVerifyCode_Expired(t *testing.T) {
+	repo, s := setupCodeRepo(t)
+	if err := repo.SaveCode(authEntity.CodeRegister, 2, authEntity.StoredCode{Hash: "h", Salt: "s", Payload: "p"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := repo.VerifyCode(authEntity.CodeRegister, 2, hashAs("x"), false); err != authEntity.ErrCodeInvalid || got.Attempts != 1 {
+		t.Fatalf("expected 1 invalid attempt, got %+v, err %v", got, err)
+	}
+	got, err := repo.VerifyCode(authEntity.CodeRegister, 2, hashAs("h"), false)
+	if err != nil || got.Payload != "p" || got.Attempts != 1 {
+		t.Errorf("unexpected code %+v, err %v", got, err)
+	}
+
+	s.FastForward(2 * time.Minute)
+	// неверный ввод по истёкшему коду не создаёт ключ заново
+	if _, err := repo.VerifyCode(authEntity.CodeRegister, 2, hashAs("x"), false); err != authEntity.ErrCodeExpired {
+		t.Errorf("expected ErrCodeExpired, got %v", err)
+	}
+	if s.Exists(codeKey(authEntity.CodeRegister, 2)) {
+		t.Errorf("expired code must not be recreated")
+	}
+}
+
+func TestVerifyCode_BurnKeepsCooldown(t *testing.T) {
+	repo, s := setupCodeRepo(t)
+	if err := repo.SaveCode(authEntity.CodeRegister, 4, authEntity.StoredCode{Hash: "h", Salt: "s"}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < authEntity.CodeMaxAttempts; i++ {
+		if _, err := repo.VerifyCode(authEntity.CodeRegister, 4, hashAs("x"), false); err != authEntity.ErrCodeInvalid {
+			t.Fatalf("attempt %d: expected ErrCodeInvalid, got %v", i, err)
+		}
+	}
+	if _, err := repo.VerifyCode(authEntity.CodeRegister, 4, hashAs("x"), false); err != authEntity.ErrCodeAttempts {
+		t.Fatalf("expected ErrCodeAttempts, got %v", err)
+	}
+	if s.Exists(codeKey(authEntity.CodeRegister, 4)) {
+		t.Errorf("burned code must be deleted")
+	}
+	// сгоревший код не снимает ограничение на повторную отправку
+	if err := repo.SaveCode(authEntity.CodeRegister, 4, authEntity.StoredCode{Hash: "h", Salt: "s"}); err != authEntity.ErrCodeCooldown {
+		t.Errorf("expected ErrCodeCooldown, got %v", err)
+	}
+}
+
+func TestVerifyCode_ConcurrentAttempts(t *testing.T) {
+	repo, _ := setupCodeRepo(t)
+	if err := repo.SaveCode(authEntity.CodeRegister, 5, authEntity.StoredCode{Hash: "h", Salt: "s"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	var invalid atomic.Int32
+	for i := 0; i < 4*authEntity.CodeMaxAttempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := repo.VerifyCode(authEntity.CodeRegister, 5, hashAs("x"), false); err == authEntity.ErrCodeInvalid {
+				invalid.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := invalid.Load(); n != authEntity.CodeMaxAttempts-1 {
+		t.Errorf("expected %d invalid attempts before lockout, got %d", authEntity.CodeMaxAttempts-1, n)
+	}
+}
+
+func TestVerifyCode_ConsumeOnce(t *testing.T) {
+	repo, s := setupCodeRepo(t)
+	if err := repo.SaveCode(authEntity.CodeRegister, 6, authEntity.StoredCode{Hash: "h", Salt: "s", Payload: "p"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// без consume верный код остаётся
+	if got, err := repo.VerifyCode(authEntity.CodeRegister, 6, hashAs("h"), false); err != nil || got.Payload != "p" {
+		t.Fatalf("expected valid code, got %+v, %v", got, err)
+	}
+
+	var wg sync.WaitGroup
+	var accepted, expired atomic.Int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			switch _, err := repo.VerifyCode(authEntity.CodeRegister, 6, hashAs("h"), true); err {
+			case nil:
+				accepted.Add(1)
+			case authEntity.ErrCodeExpired:
+				expired.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if accepted.Load() != 1 || expired.Load() != 19 {
+		t.Errorf("expected one accepted code, got %d accepted and %d expired", accepted.Load(), expired.Load())
+	}
+	if s.Exists(codeKey(authEntity.CodeRegister, 6)) || s.Exists(codeSentKey(authEntity.CodeRegister, 6)) {
+		t.Error("expected consumed code and resend cooldown to be removed")
+	}
+}
+
+func TestTakeToken_SingleUse(t *testing.T) {
+	repo, s := setupCodeRepo(t)
+	if err := repo.SaveToken(authEntity.CodeResetPassword, 3, "first", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	s.FastForward(11 * time.Second)
+	if err := repo.SaveToken(authEntity.CodeResetPassword, 3, "second", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	// новый токен отзывает предыдущий
+	if _, err := repo.TakeToken(authEntity.CodeResetPassword, "first"); err != authEntity.ErrTokenInvalid {
+		t.Errorf("expected revoked token, got %v", err)
+	}
+	if id, err := repo.TakeToken(authEntity.CodeResetPassword, "second"); err != nil || id != 3 {
+		t.Fatalf("expected user 3, got %d, err %v", id, err)
+	}
+	if _, err := repo.TakeToken(authEntity.CodeResetPassword, "second"); err != authEntity.ErrTokenInvalid {
+		t.Errorf("expected used token rejected, got %v", err)
+	}
+}
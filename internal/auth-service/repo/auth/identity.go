@@ -0,0 +1,113 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	authEntity "retarget/internal/auth-service/entity/auth"
+	"retarget/pkg/utils/optiLog"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap/zapcore"
+)
+
+// -----------------------------
+// Внешние аккаунты (auth_identity)
+// -----------------------------
+
+// GetUserByIdentity ищет пользователя по аккаунту у провайдера.
+// Не привязанный аккаунт — sql.ErrNoRows, как у остальных GetUserBy*
+func (r *AuthRepository) GetUserByIdentity(provider, subject string, requestID string) (*authEntity.User, error) {
+	startTime := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	row := r.db.QueryRowContext(ctx, `SELECT u.id, u.username, u.email, u.password, u.description, u.balance, u.role, u.email_verified
+		FROM auth_identity i JOIN auth_user u ON u.id = i.user_id
+		WHERE i.provider = $1 AND i.subject = $2`, provider, subject)
+
+	user := &authEntity.User{}
+	err := row.Scan(&user.ID, &user.Username, &user.Email, &user.Password, &user.Description, &user.Balance, &user.Role, &user.EmailVerified)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		r.asyncLogger.Log(zapcore.WarnLevel, requestID, "Identity lookup failed",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"provider": provider,
+				"error":    err.Error(),
+			}))
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return user, nil
+}
+
+// identityConflict переводит нарушение уникальности auth_identity в ошибку сущности
+func identityConflict(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != "23505" {
+		return err
+	}
+	switch pqErr.Constraint {
+	case "auth_identity_user_id_provider_key":
+		return authEntity.ErrIdentityLinked
+	case "auth_identity_provider_subject_key":
+		return authEntity.ErrIdentityTaken
+	case "auth_user_email_key":
+		return authEntity.ErrIdentityEmailTaken
+	}
+	return err
+}
+
+func insertIdentity(ctx context.Context, tx *sql.Tx, userID int, identity authEntity.ExternalIdentity) error {
+	_, err := tx.ExecContext(ctx, "INSERT INTO auth_identity (user_id, provider, subject, email) VALUES ($1, $2, $3, $4)",
+		userID, identity.Provider, identity.Subject, identity.Email)
+	return identityConflict(err)
+}
+
+// LinkIdentity привязывает внешний аккаунт к пользователю и пишет событие в
+// auth_audit_log. Аккаунт чужого пользователя — authEntity.ErrIdentityTaken,
+// второй аккаунт того же провайдера — authEntity.ErrIdentityLinked
+func (r *AuthRepository) LinkIdentity(userID int, identity authEntity.ExternalIdentity, requestID string) error {
+	details, err := json.Marshal(map[string]string{"provider": identity.Provider, "email": identity.Email})
+	if err != nil {
+		return err
+	}
+
+	return r.inTx(requestID, "Identity link failed", userID, func(ctx context.Context, tx *sql.Tx) error {
+		if err := insertIdentity(ctx, tx, userID, identity); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "INSERT INTO auth_audit_log (user_id, event, details, request_id) VALUES ($1, $2, $3, $4)",
+			userID, authEntity.AuditIdentityLinked, details, requestID)
+		return err
+	})
+}
+
+// CreateUserWithIdentity заводит пользователя, вошедшего через провайдера, вместе
+// с привязкой его внешнего аккаунта
+func (r *AuthRepository) CreateUserWithIdentity(user *authEntity.User, identity authEntity.ExternalIdentity, requestID string) error {
+	if err := authEntity.ValidateUser(user); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	return r.inTx(requestID, "User creation failed", 0, func(ctx context.Context, tx *sql.Tx) error {
+		var id int
+		err := tx.QueryRowContext(ctx, `INSERT INTO auth_user (username, email, password, description, balance, role, email_verified)
+			VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+			user.Username, user.Email, user.Password, user.Description, user.Balance, user.Role, user.EmailVerified,
+		).Scan(&id)
+		if err != nil {
+			return identityConflict(err)
+		}
+		if err := insertIdentity(ctx, tx, id, identity); err != nil {
+			return err
+		}
+		user.ID = id
+		return nil
+	})
+}
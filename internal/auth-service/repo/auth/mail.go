@@ -0,0 +1,92 @@
+package repo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// MailServiceEndpoint — адрес mail-service внутри docker-сети
+const MailServiceEndpoint = "http://ReTargetApiMail:8036"
+
+type MailRepositoryInterface interface {
+	SendRegisterCode(email, code string) error
+	SendResetPasswordToken(email, token string) error
+	SendEditPasswordCode(email, code string) error
+	SendEditEmailCode(email, code string) error
+	SendEmailChanged(email, newEmail string) error
+	SendAccountLocked(email, until string) error
+	SendOrgInvite(email, organization, role, token string) error
+}
+
+// MailRepository отправляет письма с кодами через HTTP API mail-service
+type MailRepository struct {
+	client   *http.Client
+	endpoint string
+}
+
+func NewMailRepository(endpoint string, client *http.Client) *MailRepository {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &MailRepository{client: client, endpoint: endpoint}
+}
+
+// SendRegisterCode отправляет код подтверждения почты при регистрации
+func (r *MailRepository) SendRegisterCode(email, code string) error {
+	return r.send("/api/v1/mail/send-register-code", email, code)
+}
+
+// SendResetPasswordToken отправляет токен восстановления забытого пароля
+func (r *MailRepository) SendResetPasswordToken(email, token string) error {
+	return r.send("/api/v1/mail/send-recovery-code", email, token)
+}
+
+// SendEditPasswordCode отправляет код подтверждения смены пароля
+func (r *MailRepository) SendEditPasswordCode(email, code string) error {
+	return r.send("/api/v1/mail/send-password-reset-code", email, code)
+}
+
+// SendEditEmailCode отправляет код подтверждения смены почты на один из адресов
+func (r *MailRepository) SendEditEmailCode(email, code string) error {
+	return r.send("/api/v1/mail/send-email-change-code", email, code)
+}
+
+// SendEmailChanged предупреждает прежний адрес о смене почты аккаунта
+func (r *MailRepository) SendEmailChanged(email, newEmail string) error {
+	return r.post("/api/v1/mail/send-email-changed", map[string]string{"email": email, "new_email": newEmail})
+}
+
+// SendAccountLocked сообщает владельцу о блокировке входа после серии неверных паролей
+func (r *MailRepository) SendAccountLocked(email, until string) error {
+	return r.post("/api/v1/mail/send-account-locked", map[string]string{"email": email, "until": until})
+}
+
+// SendOrgInvite отправляет приглашение в организацию с токеном для принятия
+func (r *MailRepository) SendOrgInvite(email, organization, role, token string) error {
+	return r.post("/api/v1/mail/send-org-invite", map[string]string{"email": email, "organization": organization, "role": role, "token": token})
+}
+
+func (r *MailRepository) send(path, email, code string) error {
+	return r.post(path, map[string]string{"email": email, "code": code})
+}
+
+func (r *MailRepository) post(path string, payload map[string]string) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.client.Post(r.endpoint+path, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("mail-service request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("mail-service responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
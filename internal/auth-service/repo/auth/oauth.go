@@ -0,0 +1,298 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	authEntity "retarget/internal/auth-service/entity/auth"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type OAuthProviderInterface interface {
+	Name() string
+	AuthCodeURL(state, codeChallenge string) string
+	Exchange(code, verifier, state string, extra url.Values) (string, error)
+	Identity(accessToken string) (authEntity.ExternalIdentity, error)
+}
+
+// OAuthProvider — клиент authorization code + PKCE (S256) у одного провайдера
+type OAuthProvider struct {
+	cfg    authEntity.OAuthProviderConfig
+	client *http.Client
+}
+
+func NewOAuthProvider(cfg authEntity.OAuthProviderConfig, client *http.Client) *OAuthProvider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &OAuthProvider{cfg: cfg, client: client}
+}
+
+// NewVKIDProvider — VK ID (id.vk.com). Почту VK ID отдаёт без признака подтверждения
+func NewVKIDProvider(clientID, clientSecret, redirectURL string, client *http.Client) *OAuthProvider {
+	return NewOAuthProvider(authEntity.OAuthProviderConfig{
+		Name:         "vk",
+		Kind:         authEntity.OAuthKindVK,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		AuthURL:      "https://id.vk.com/authorize",
+		TokenURL:     "https://id.vk.com/oauth2/auth",
+		UserInfoURL:  "https://id.vk.com/oauth2/user_info",
+		Scopes:       []string{"email"},
+		RedirectURL:  redirectURL,
+	}, client)
+}
+
+// NewYandexIDProvider — Яндекс ID (oauth.yandex.ru)
+func NewYandexIDProvider(clientID, clientSecret, redirectURL string, client *http.Client) *OAuthProvider {
+	return NewOAuthProvider(authEntity.OAuthProviderConfig{
+		Name:         "yandex",
+		Kind:         authEntity.OAuthKindYandex,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		AuthURL:      "https://oauth.yandex.ru/authorize",
+		TokenURL:     "https://oauth.yandex.ru/token",
+		UserInfoURL:  "https://login.yandex.ru/info?format=json",
+		Scopes:       []string{"login:email", "login:info"},
+		RedirectURL:  redirectURL,
+	}, client)
+}
+
+// DiscoverOIDCProvider заполняет адреса произвольного OpenID Connect
+// провайдера из <issuer>/.well-known/openid-configuration
+func DiscoverOIDCProvider(cfg authEntity.OAuthProviderConfig, client *http.Client) (*OAuthProvider, error) {
+	p := NewOAuthProvider(cfg, client)
+	p.cfg.Kind = authEntity.OAuthKindOIDC
+	if len(p.cfg.Scopes) == 0 {
+		p.cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	wellKnown := strings.TrimSuffix(cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	resp, err := p.client.Get(wellKnown)
+	if err != nil {
+		return nil, fmt.Errorf("oidc discovery failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc discovery responded with status %d", resp.StatusCode)
+	}
+
+	var doc struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+	if doc.Issuer != strings.TrimSuffix(cfg.Issuer, "/") && doc.Issuer != cfg.Issuer {
+		return nil, fmt.Errorf("oidc discovery: issuer mismatch %q", doc.Issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.UserinfoEndpoint == "" {
+		return nil, fmt.Errorf("oidc discovery: endpoints are missing")
+	}
+	p.cfg.AuthURL = doc.AuthorizationEndpoint
+	p.cfg.TokenURL = doc.TokenEndpoint
+	p.cfg.UserInfoURL = doc.UserinfoEndpoint
+	return p, nil
+}
+
+func (p *OAuthProvider) Name() string {
+	return p.cfg.Name
+}
+
+// AuthCodeURL — адрес страницы входа провайдера
+func (p *OAuthProvider) AuthCodeURL(state, codeChallenge string) string {
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientID},
+		"redirect_uri":          {p.cfg.RedirectURL},
+		"state":                 {state},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+	if len(p.cfg.Scopes) > 0 {
+		query.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	}
+
+	separator := "?"
+	if strings.Contains(p.cfg.AuthURL, "?") {
+		separator = "&"
+	}
+	return p.cfg.AuthURL + separator + query.Encode()
+}
+
+// Exchange меняет code на access token. extra — параметры, которые провайдер
+// вернул вместе с кодом (VK ID присылает device_id и ждёт его обратно вместе со state)
+func (p *OAuthProvider) Exchange(code, verifier, state string, extra url.Values) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"code_verifier": {verifier},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"client_id":     {p.cfg.ClientID},
+	}
+	if p.cfg.ClientSecret != "" {
+		form.Set("client_secret", p.cfg.ClientSecret)
+	}
+	if p.cfg.Kind == authEntity.OAuthKindVK {
+		form.Set("state", state)
+	}
+	for key, values := range extra {
+		form[key] = values
+	}
+
+	var token struct {
+		AccessToken      string `json:"access_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := p.do(http.MethodPost, p.cfg.TokenURL, form, "", &token); err != nil {
+		return "", err
+	}
+	if token.Error != "" || token.AccessToken == "" {
+		return "", fmt.Errorf("%w: %s %s", authEntity.ErrOAuthFailed, token.Error, token.ErrorDescription)
+	}
+	return token.AccessToken, nil
+}
+
+// Identity запрашивает пользователя по access token. Токен получен напрямую
+// с token endpoint, поэтому данным userinfo можно верить без проверки id_token
+func (p *OAuthProvider) Identity(accessToken string) (authEntity.ExternalIdentity, error) {
+	identity := authEntity.ExternalIdentity{Provider: p.cfg.Name}
+
+	switch p.cfg.Kind {
+	case authEntity.OAuthKindVK:
+		var info struct {
+			User struct {
+				UserID string `json:"user_id"`
+				Email  string `json:"email"`
+			} `json:"user"`
+		}
+		form := url.Values{"client_id": {p.cfg.ClientID}, "access_token": {accessToken}}
+		if err := p.do(http.MethodPost, p.cfg.UserInfoURL, form, "", &info); err != nil {
+			return identity, err
+		}
+		identity.Subject = info.User.UserID
+		identity.Email = info.User.Email
+		identity.Username = "vk" + info.User.UserID
+
+	case authEntity.OAuthKindYandex:
+		var info struct {
+			ID           string `json:"id"`
+			Login        string `json:"login"`
+			DefaultEmail string `json:"default_email"`
+		}
+		if err := p.do(http.MethodGet, p.cfg.UserInfoURL, nil, "OAuth "+accessToken, &info); err != nil {
+			return identity, err
+		}
+		identity.Subject = info.ID
+		identity.Email = info.DefaultEmail
+		identity.EmailVerified = info.DefaultEmail != ""
+		identity.Username = info.Login
+
+	default:
+		var info struct {
+			Sub               string          `json:"sub"`
+			Email             string          `json:"email"`
+			EmailVerified     json.RawMessage `json:"email_verified"`
+			PreferredUsername string          `json:"preferred_username"`
+		}
+		if err := p.do(http.MethodGet, p.cfg.UserInfoURL, nil, "Bearer "+accessToken, &info); err != nil {
+			return identity, err
+		}
+		identity.Subject = info.Sub
+		identity.Email = info.Email
+		// часть провайдеров отдаёт email_verified строкой
+		verified, _ := strconv.ParseBool(strings.Trim(string(info.EmailVerified), `"`))
+		identity.EmailVerified = verified
+		identity.Username = info.PreferredUsername
+	}
+
+	if identity.Subject == "" {
+		return identity, fmt.Errorf("%w: empty subject", authEntity.ErrOAuthFailed)
+	}
+	return identity, nil
+}
+
+func (p *OAuthProvider) do(method, endpoint string, form url.Values, authorization string, out interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", p.cfg.Name, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	// ошибки обмена кода приходят с 400 и телом {"error": ...}, их разбирает вызывающий
+	if resp.StatusCode >= http.StatusInternalServerError || (resp.StatusCode != http.StatusOK && len(data) == 0) {
+		return fmt.Errorf("%w: %s responded with status %d", authEntity.ErrOAuthFailed, p.cfg.Name, resp.StatusCode)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("%w: %s: %v", authEntity.ErrOAuthFailed, p.cfg.Name, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s responded with status %d", authEntity.ErrOAuthFailed, p.cfg.Name, resp.StatusCode)
+	}
+	return nil
+}
+
+func oauthStateKey(state string) string {
+	return "oauth_state:" + state
+}
+
+// SaveOAuthState запоминает начатый вход до возвращения от провайдера
+func (r *CodeRepository) SaveOAuthState(state string, st authEntity.OAuthState, ttl time.Duration) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	return r.client.Set(context.Background(), oauthStateKey(state), data, ttl).Err()
+}
+
+// TakeOAuthState гасит state: один и тот же ответ провайдера принимается один раз
+func (r *CodeRepository) TakeOAuthState(state string) (authEntity.OAuthState, error) {
+	var st authEntity.OAuthState
+
+	data, err := r.client.GetDel(context.Background(), oauthStateKey(state)).Bytes()
+	if err == redis.Nil {
+		return st, authEntity.ErrOAuthStateInvalid
+	}
+	if err != nil {
+		return st, err
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return st, fmt.Errorf("invalid oauth state: %w", err)
+	}
+	return st, nil
+}
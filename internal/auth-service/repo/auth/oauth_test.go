@@ -0,0 +1,166 @@
+package repo
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	authEntity "retarget/internal/auth-service/entity/auth"
+	authenticate "retarget/pkg/middleware/auth"
+)
+
+// mockOIDCServer — минимальный OpenID Connect провайдер: discovery, token с
+// проверкой PKCE и userinfo. Выдаёт код на challenge, заданный в authorize
+func mockOIDCServer(t *testing.T, claims map[string]interface{}) (*httptest.Server, func(challenge string) string) {
+	codes := map[string]string{}
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 srv.URL,
+			"authorization_endpoint": srv.URL + "/authorize",
+			"token_endpoint":         srv.URL + "/token",
+			"userinfo_endpoint":      srv.URL + "/userinfo",
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		//nolint:errcheck
+		r.ParseForm()
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if r.PostForm.Get("client_secret") != "secret" || codes[r.PostForm.Get("code")] != base64.RawURLEncoding.EncodeToString(sum[:]) {
+			w.WriteHeader(http.StatusBadRequest)
+			//nolint:errcheck
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "token", "token_type": "Bearer"})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(claims)
+	})
+
+	authorize := func(challenge string) string {
+		code := "code" + challenge[:8]
+		codes[code] = challenge
+		return code
+	}
+	return srv, authorize
+}
+
+func TestOIDCProvider_Flow(t *testing.T) {
+	srv, authorize := mockOIDCServer(t, map[string]interface{}{
+		"sub":                "42",
+		"email":              "user@example.com",
+		"email_verified":     "true",
+		"preferred_username": "user42",
+	})
+
+	p, err := DiscoverOIDCProvider(authEntity.OAuthProviderConfig{
+		Name:         "corp",
+		ClientID:     "client",
+		ClientSecret: "secret",
+		Issuer:       srv.URL,
+		RedirectURL:  "https://front/oauth/corp",
+	}, srv.Client())
+	if err != nil {
+		t.Fatalf("discovery: %v", err)
+	}
+
+	verifier := "verifier-verifier-verifier-verifier-verifier"
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	authURL, err := url.Parse(p.AuthCodeURL("st", challenge))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := authURL.Query()
+	if authURL.Path != "/authorize" || q.Get("state") != "st" || q.Get("code_challenge_method") != "S256" ||
+		q.Get("redirect_uri") != "https://front/oauth/corp" || q.Get("scope") != "openid email profile" {
+		t.Errorf("unexpected authorize url %s", authURL)
+	}
+
+	code := authorize(q.Get("code_challenge"))
+	if _, err := p.Exchange(code, "wrong-verifier", "st", nil); !errors.Is(err, authEntity.ErrOAuthFailed) {
+		t.Errorf("expected PKCE mismatch rejected, got %v", err)
+	}
+
+	token, err := p.Exchange(code, verifier, "st", nil)
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+	identity, err := p.Identity(token)
+	if err != nil {
+		t.Fatalf("identity: %v", err)
+	}
+	want := authEntity.ExternalIdentity{Provider: "corp", Subject: "42", Email: "user@example.com", EmailVerified: true, Username: "user42"}
+	if identity != want {
+		t.Errorf("expected %+v, got %+v", want, identity)
+	}
+}
+
+func TestOIDCProvider_IssuerMismatch(t *testing.T) {
+	srv, _ := mockOIDCServer(t, nil)
+	_, err := DiscoverOIDCProvider(authEntity.OAuthProviderConfig{Name: "corp", Issuer: srv.URL + "/other"}, srv.Client())
+	if err == nil {
+		t.Errorf("expected discovery to fail on foreign issuer")
+	}
+}
+
+func TestOAuthState_SingleUse(t *testing.T) {
+	repo, s := setupCodeRepo(t)
+	st := authEntity.OAuthState{Provider: "vk", Mode: authEntity.OAuthModeLogin, Verifier: "v", Role: 2}
+
+	if err := repo.SaveOAuthState("abc", st, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	got, err := repo.TakeOAuthState("abc")
+	if err != nil || got != st {
+		t.Fatalf("expected %+v, got %+v, %v", st, got, err)
+	}
+	if _, err := repo.TakeOAuthState("abc"); err != authEntity.ErrOAuthStateInvalid {
+		t.Errorf("expected state to be single use, got %v", err)
+	}
+
+	//nolint:errcheck
+	repo.SaveOAuthState("old", st, time.Minute)
+	s.FastForward(2 * time.Minute)
+	if _, err := repo.TakeOAuthState("old"); err != authEntity.ErrOAuthStateInvalid {
+		t.Errorf("expected expired state rejected, got %v", err)
+	}
+}
+
+func TestOAuthState_NotASession(t *testing.T) {
+	repo, s := setupCodeRepo(t)
+	// state в режиме привязки хранит user_id и role, как SessionData
+	state := "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+	st := authEntity.OAuthState{Provider: "vk", Mode: authEntity.OAuthModeLink, Verifier: "v", UserID: 7, Role: 1}
+	if err := repo.SaveOAuthState(state, st, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	authenticator, err := authenticate.NewAuthenticator(s.Addr(), "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cookie := range []string{oauthStateKey(state), state} {
+		if session, err := authenticator.Authenticate(cookie); err == nil {
+			t.Errorf("cookie %q: oauth state accepted as session %+v", cookie, session)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	authEntity "retarget/internal/auth-service/entity/auth"
+	authenticate "retarget/pkg/middleware/auth"
+	"retarget/pkg/utils/optiLog"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// -----------------------------
+// Организации (organization, organization_member, organization_invite)
+// -----------------------------
+
+const membershipColumns = "o.account_id, o.name, u.role, o.created_at, m.role"
+
+func scanMembership(row interface{ Scan(...interface{}) error }) (authEntity.Membership, error) {
+	var m authEntity.Membership
+	err := row.Scan(&m.ID, &m.Name, &m.Role, &m.CreatedAt, &m.MemberRole)
+	return m, err
+}
+
+func writeAudit(ctx context.Context, tx *sql.Tx, userID int, event string, details map[string]interface{}, requestID string) error {
+	data, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "INSERT INTO auth_audit_log (user_id, event, details, request_id) VALUES ($1, $2, $3, $4)",
+		userID, event, data, requestID)
+	return err
+}
+
+// lockMemberships блокирует пользователя и проверяет, что он может вступить
+// ещё в одну организацию
+func lockMemberships(ctx context.Context, tx *sql.Tx, userID int) error {
+	if _, err := tx.ExecContext(ctx, "SELECT 1 FROM auth_user WHERE id = $1 FOR UPDATE", userID); err != nil {
+		return err
+	}
+	var count int
+	if err := tx.QueryRowContext(ctx, "SELECT count(*) FROM organization_member WHERE user_id = $1", userID).Scan(&count); err != nil {
+		return err
+	}
+	if count >= authEntity.MaxOrgsPerUser {
+		return authEntity.ErrOrgLimit
+	}
+	return nil
+}
+
+// CreateOrganization заводит аккаунт организации account, саму организацию и
+// делает ownerID её владельцем
+func (r *AuthRepository) CreateOrganization(org *authEntity.Organization, account *authEntity.User, ownerID int, requestID string) error {
+	return r.inTx(requestID, "Organization creation failed", ownerID, func(ctx context.Context, tx *sql.Tx) error {
+		if err := lockMemberships(ctx, tx, ownerID); err != nil {
+			return err
+		}
+
+		err := tx.QueryRowContext(ctx, `INSERT INTO auth_user (username, email, password, description, balance, role, email_verified, is_organization)
+			VALUES ($1, $2, $3, $4, $5, $6, TRUE, TRUE) RETURNING id`,
+			account.Username, account.Email, account.Password, account.Description, account.Balance, account.Role,
+		).Scan(&account.ID)
+		if err != nil {
+			return err
+		}
+		org.ID, org.Role = account.ID, account.Role
+
+		err = tx.QueryRowContext(ctx, "INSERT INTO organization (account_id, name, created_by) VALUES ($1, $2, $3) RETURNING created_at",
+			org.ID, org.Name, ownerID).Scan(&org.CreatedAt)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO organization_member (org_id, user_id, role) VALUES ($1, $2, $3)",
+			org.ID, ownerID, authenticate.OrgRoleOwner); err != nil {
+			return err
+		}
+		return writeAudit(ctx, tx, org.ID, authEntity.AuditOrgCreated, map[string]interface{}{"name": org.Name, "actor_id": ownerID}, requestID)
+	})
+}
+
+// ListMemberships возвращает организации пользователя по названию
+func (r *AuthRepository) ListMemberships(userID int, requestID string) ([]authEntity.Membership, error) {
+	startTime := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT `+membershipColumns+` FROM organization_member m
+		JOIN organization o ON o.account_id = m.org_id JOIN auth_user u ON u.id = o.account_id
+		WHERE m.user_id = $1 ORDER BY o.name, o.account_id`, userID)
+	if err == nil {
+		defer rows.Close()
+	}
+
+	memberships := []authEntity.Membership{}
+	for err == nil && rows.Next() {
+		var m authEntity.Membership
+		if m, err = scanMembership(rows); err == nil {
+			memberships = append(memberships, m)
+		}
+	}
+	if err == nil {
+		err = rows.Err()
+	}
+	if err != nil {
+		r.asyncLogger.Log(zapcore.WarnLevel, requestID, "Memberships query failed",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"userID": userID,
+				"error":  err.Error(),
+			}))
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return memberships, nil
+}
+
+// GetMembership возвращает организацию orgID с ролью в ней пользователя.
+// Не участник — authEntity.ErrOrgNotFound
+func (r *AuthRepository) GetMembership(orgID, userID int, requestID string) (authEntity.Membership, error) {
+	startTime := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	m, err := scanMembership(r.db.QueryRowContext(ctx, `SELECT `+membershipColumns+` FROM organization_member m
+		JOIN organization o ON o.account_id = m.org_id JOIN auth_user u ON u.id = o.account_id
+		WHERE m.org_id = $1 AND m.user_id = $2`, orgID, userID))
+	if err == sql.ErrNoRows {
+		return m, authEntity.ErrOrgNotFound
+	}
+	if err != nil {
+		r.asyncLogger.Log(zapcore.WarnLevel, requestID, "Membership query failed",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"orgID":  orgID,
+				"userID": userID,
+				"error":  err.Error(),
+			}))
+		return m, fmt.Errorf("database error: %w", err)
+	}
+	return m, nil
+}
+
+// ListOrgMembers возвращает участников организации в порядке вступления
+func (r *AuthRepository) ListOrgMembers(orgID int, requestID string) ([]authEntity.OrgMember, error) {
+	startTime := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT m.user_id, u.username, u.email, m.role, m.created_at
+		FROM organization_member m JOIN auth_user u ON u.id = m.user_id
+		WHERE m.org_id = $1 ORDER BY m.created_at, m.user_id`, orgID)
+	if err == nil {
+		defer rows.Close()
+	}
+
+	members := []authEntity.OrgMember{}
+	for err == nil && rows.Next() {
+		var m authEntity.OrgMember
+		if err = rows.Scan(&m.UserID, &m.Username, &m.Email, &m.Role, &m.JoinedAt); err == nil {
+			members = append(members, m)
+		}
+	}
+	if err == nil {
+		err = rows.Err()
+	}
+	if err != nil {
+		r.asyncLogger.Log(zapcore.WarnLevel, requestID, "Organization members query failed",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"orgID": orgID,
+				"error": err.Error(),
+			}))
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return members, nil
+}
+
+func (r *AuthRepository) CreateOrgInvite(invite *authEntity.OrgInvite, requestID string) error {
+	return r.inTx(requestID, "Organization invite creation failed", invite.InvitedBy, func(ctx context.Context, tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, `INSERT INTO organization_invite (org_id, email, role, token_hash, invited_by, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`,
+			invite.OrgID, invite.Email, invite.Role, invite.TokenHash, invite.InvitedBy, invite.ExpiresAt.UTC(),
+		).Scan(&invite.ID, &invite.CreatedAt)
+	})
+}
+
+// AcceptOrgInvite добавляет пользователя в организацию по приглашению на его
+// почту. Приглашение одноразовое
+func (r *AuthRepository) AcceptOrgInvite(tokenHash string, userID int, email string, requestID string) (authEntity.Membership, error) {
+	var invite authEntity.OrgInvite
+	err := r.inTx(requestID, "Organization invite acceptance failed", userID, func(ctx context.Context, tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `SELECT id, org_id, email, role FROM organization_invite
+			WHERE token_hash = $1 AND accepted_at IS NULL AND expires_at > (now() AT TIME ZONE 'UTC') FOR UPDATE`, tokenHash,
+		).Scan(&invite.ID, &invite.OrgID, &invite.Email, &invite.Role)
+		if err == sql.ErrNoRows {
+			return authEntity.ErrOrgInviteInvalid
+		}
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(invite.Email, email) {
+			return authEntity.ErrOrgInviteEmail
+		}
+		if err := lockMemberships(ctx, tx, userID); err != nil {
+			return err
+		}
+
+		res, err := tx.ExecContext(ctx, `INSERT INTO organization_member (org_id, user_id, role) VALUES ($1, $2, $3)
+			ON CONFLICT (org_id, user_id) DO NOTHING`, invite.OrgID, userID, invite.Role)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return authEntity.ErrOrgAlreadyMember
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE organization_invite SET accepted_at = (now() AT TIME ZONE 'UTC') WHERE id = $1", invite.ID); err != nil {
+			return err
+		}
+		return writeAudit(ctx, tx, invite.OrgID, authEntity.AuditOrgMemberAdded,
+			map[string]interface{}{"user_id": userID, "role": invite.Role, "invite_id": invite.ID}, requestID)
+	})
+	if err != nil {
+		return authEntity.Membership{}, err
+	}
+	return r.GetMembership(invite.OrgID, userID, requestID)
+}
+
+// lockMember блокирует организацию и возвращает роль участника. Блокировка
+// организации не даёт параллельным запросам убрать последнего владельца
+func lockMember(ctx context.Context, tx *sql.Tx, orgID, userID int) (string, error) {
+	if _, err := tx.ExecContext(ctx, "SELECT 1 FROM organization WHERE account_id = $1 FOR UPDATE", orgID); err != nil {
+		return "", err
+	}
+	var role string
+	err := tx.QueryRowContext(ctx, "SELECT role FROM organization_member WHERE org_id = $1 AND user_id = $2", orgID, userID).Scan(&role)
+	if err == sql.ErrNoRows {
+		return "", authEntity.ErrOrgMemberNotFound
+	}
+	return role, err
+}
+
+// keepOwner запрещает оставить организацию без владельца
+func keepOwner(ctx context.Context, tx *sql.Tx, orgID int) error {
+	var owners int
+	err := tx.QueryRowContext(ctx, "SELECT count(*) FROM organization_member WHERE org_id = $1 AND role = $2",
+		orgID, authenticate.OrgRoleOwner).Scan(&owners)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return authEntity.ErrOrgLastOwner
+	}
+	return nil
+}
+
+// UpdateOrgMemberRole меняет роль участника. Последний владелец остаётся владельцем
+func (r *AuthRepository) UpdateOrgMemberRole(orgID, userID int, role string, actorID int, requestID string) error {
+	return r.inTx(requestID, "Organization member update failed", actorID, func(ctx context.Context, tx *sql.Tx) error {
+		current, err := lockMember(ctx, tx, orgID, userID)
+		if err != nil {
+			return err
+		}
+		if current == role {
+			return nil
+		}
+		if current == authenticate.OrgRoleOwner {
+			if err := keepOwner(ctx, tx, orgID); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE organization_member SET role = $3 WHERE org_id = $1 AND user_id = $2", orgID, userID, role); err != nil {
+			return err
+		}
+		return writeAudit(ctx, tx, orgID, authEntity.AuditOrgMemberRoleChanged,
+			map[string]interface{}{"user_id": userID, "from": current, "to": role, "actor_id": actorID}, requestID)
+	})
+}
+
+// RemoveOrgMember исключает участника. Последнего владельца исключить нельзя
+func (r *AuthRepository) RemoveOrgMember(orgID, userID int, actorID int, requestID string) error {
+	return r.inTx(requestID, "Organization member removal failed", actorID, func(ctx context.Context, tx *sql.Tx) error {
+		current, err := lockMember(ctx, tx, orgID, userID)
+		if err != nil {
+			return err
+		}
+		if current == authenticate.OrgRoleOwner {
+			if err := keepOwner(ctx, tx, orgID); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM organization_member WHERE org_id = $1 AND user_id = $2", orgID, userID); err != nil {
+			return err
+		}
+		return writeAudit(ctx, tx, orgID, authEntity.AuditOrgMemberRemoved,
+			map[string]interface{}{"user_id": userID, "role": current, "actor_id": actorID}, requestID)
+	})
+}
+
+// GetOrgContact возвращает название организации и почту её первого
+// владельца: на неё уходят письма аккаунта организации, у которого своей почты нет
+func (r *AuthRepository) GetOrgContact(orgID int, requestID string) (string, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var name, email string
+	err := r.db.QueryRowContext(ctx, `SELECT o.name, u.email FROM organization o
+		JOIN organization_member m ON m.org_id = o.account_id JOIN auth_user u ON u.id = m.user_id
+		WHERE o.account_id = $1 AND m.role = $2 ORDER BY m.created_at, m.user_id LIMIT 1`, orgID, authenticate.OrgRoleOwner).Scan(&name, &email)
+	if err == sql.ErrNoRows {
+		return "", "", authEntity.ErrOrgNotFound
+	}
+	if err != nil {
+		r.asyncLogger.Log(zapcore.WarnLevel, requestID, "Organization contact query failed",
+			optiLog.MakeLogFields(requestID, 0, map[string]interface{}{
+				"orgID": orgID,
+				"error": err.Error(),
+			}))
+		return "", "", fmt.Errorf("database error: %w", err)
+	}
+	return name, email, nil
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
+	"sort"
+	"strconv"
 	"time"
 
 	authEntity "retarget/internal/auth-service/entity/auth"
+	authenticate "retarget/pkg/middleware/auth"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
@@ -15,8 +19,17 @@ import (
 
 type SessionRepositoryInterface interface {
 	GetSession(sessionId string) (*authEntity.Session, error)
-	AddSession(userId int, role int) (*authEntity.Session, error)
+	AddSession(userId int, role int, verified bool, twoFactor bool, client authEntity.SessionClient) (*authEntity.Session, error)
+	ListUserSessions(userId int) ([]authEntity.Session, error)
+	MarkSessionVerified(sessionId string) error
+	MarkSessionTwoFactor(sessionId string) error
+	SwitchSessionOrg(sessionId string, orgID int, orgRole string, role int) error
+	UpdateUserSessionsOrg(userId int, orgID int, orgRole string, role int) error
 	DelSession(sessionId string) error
+	DelUserSession(userId int, handle string) error
+	DelUserSessions(userId int, exceptSessionId string) error
+	SaveAPIKey(key authEntity.APIKey) error
+	DelAPIKey(keyHash string) error
 	CloseConnection() error
 
 	generateSessionID() (string, error)
@@ -54,7 +67,7 @@ func (r *SessionRepository) CloseConnection() error {
 }
 
 // AddSession creates a new session for the user with random ID
-func (r *SessionRepository) AddSession(userId int, role int) (*authEntity.Session, error) {
+func (r *SessionRepository) AddSession(userId int, role int, verified bool, twoFactor bool, client authEntity.SessionClient) (*authEntity.Session, error) {
 	ctx := context.Background()
 
 	sessionId, err := r.generateSessionID()
@@ -62,12 +75,18 @@ func (r *SessionRepository) AddSession(userId int, role int) (*authEntity.Sessio
 		return nil, err
 	}
 
+	now := time.Now()
 	session := &authEntity.Session{
-		ID:        sessionId,
-		UserID:    userId,
-		Role:      role,
-		Expires:   time.Now().Add(r.ttl),
-		CreatedAt: time.Now(),
+		ID:         sessionId,
+		UserID:     userId,
+		Role:       role,
+		Expires:    now.Add(r.ttl),
+		CreatedAt:  now,
+		Unverified: !verified,
+		TwoFactor:  twoFactor,
+		IP:         client.IP,
+		UserAgent:  truncate(client.UserAgent, authEntity.MaxUserAgentLength),
+		LastSeen:   now,
 	}
 
 	sessionData, err := json.Marshal(session)
@@ -75,7 +94,14 @@ func (r *SessionRepository) AddSession(userId int, role int) (*authEntity.Sessio
 		return nil, err
 	}
 
-	err = r.client.Set(ctx, sessionId, sessionData, r.ttl).Err()
+	key := userSessionsKey(userId)
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, authenticate.SessionKey(sessionId), sessionData, r.ttl)
+		pipe.Set(ctx, authenticate.LastSeenKey(sessionId), now.Unix(), r.ttl)
+		pipe.SAdd(ctx, key, sessionId)
+		pipe.Expire(ctx, key, r.ttl)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +112,7 @@ func (r *SessionRepository) AddSession(userId int, role int) (*authEntity.Sessio
 func (r *SessionRepository) GetSession(sessionId string) (*authEntity.Session, error) {
 	ctx := context.Background()
 
-	data, err := r.client.Get(ctx, sessionId).Bytes()
+	data, err := r.client.Get(ctx, authenticate.SessionKey(sessionId)).Bytes()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			return nil, authEntity.ErrSessionNotFound
@@ -107,9 +133,215 @@ func (r *SessionRepository) GetSession(sessionId string) (*authEntity.Session, e
 	return &session, nil
 }
 
+// MarkSessionVerified снимает с сессии отметку о неподтверждённой почте, не меняя срок жизни
+func (r *SessionRepository) MarkSessionVerified(sessionId string) error {
+	return r.updateSession(sessionId, func(session *authEntity.Session) {
+		session.Unverified = false
+	})
+}
+
+// MarkSessionTwoFactor отмечает сессию подтверждённой вторым фактором, не меняя срок жизни
+func (r *SessionRepository) MarkSessionTwoFactor(sessionId string) error {
+	return r.updateSession(sessionId, func(session *authEntity.Session) {
+		session.TwoFactor = true
+	})
+}
+
+// SwitchSessionOrg переводит сессию в организацию orgID с ролью участника
+// orgRole и ролью аккаунта role. orgID == 0 — обратно в личный аккаунт
+func (r *SessionRepository) SwitchSessionOrg(sessionId string, orgID int, orgRole string, role int) error {
+	return r.updateSession(sessionId, func(session *authEntity.Session) {
+		session.OrgID, session.OrgRole, session.Role = orgID, orgRole, role
+	})
+}
+
+// UpdateUserSessionsOrg меняет роль участника во всех его сессиях, открытых в
+// организации orgID. Пустая orgRole возвращает их в личный аккаунт с ролью role
+func (r *SessionRepository) UpdateUserSessionsOrg(userId int, orgID int, orgRole string, role int) error {
+	sessions, err := r.ListUserSessions(userId)
+	if err != nil {
+		return err
+	}
+	for _, s := range sessions {
+		if s.OrgID != orgID {
+			continue
+		}
+		err := r.updateSession(s.ID, func(session *authEntity.Session) {
+			if orgRole == "" {
+				session.OrgID, session.Role = 0, role
+			}
+			session.OrgRole = orgRole
+		})
+		if err != nil && !errors.Is(err, authEntity.ErrSessionNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *SessionRepository) updateSession(sessionId string, update func(session *authEntity.Session)) error {
+	ctx := context.Background()
+
+	session, err := r.GetSession(sessionId)
+	if err != nil {
+		return err
+	}
+	update(session)
+
+	sessionData, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	return r.client.Set(ctx, authenticate.SessionKey(sessionId), sessionData, redis.KeepTTL).Err()
+}
+
+// DelSession завершает сессию и убирает её из списка сессий пользователя
 func (r *SessionRepository) DelSession(sessionId string) error {
 	ctx := context.Background()
-	return r.client.Del(ctx, sessionId).Err()
+
+	data, err := r.client.Get(ctx, authenticate.SessionKey(sessionId)).Bytes()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+	var session authEntity.Session
+	indexed := err == nil && json.Unmarshal(data, &session) == nil
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, authenticate.SessionKey(sessionId), authenticate.LastSeenKey(sessionId))
+		if indexed {
+			pipe.SRem(ctx, userSessionsKey(session.UserID), sessionId)
+		}
+		return nil
+	})
+	return err
+}
+
+// ListUserSessions возвращает действующие сессии пользователя, начиная с
+// последней активной. Истёкшие попутно вычищаются из множества сессий
+func (r *SessionRepository) ListUserSessions(userId int) ([]authEntity.Session, error) {
+	ctx := context.Background()
+
+	key := userSessionsKey(userId)
+	sessionIds, err := r.client.SMembers(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(sessionIds) == 0 {
+		return []authEntity.Session{}, nil
+	}
+
+	keys := make([]string, 0, 2*len(sessionIds))
+	for _, id := range sessionIds {
+		keys = append(keys, authenticate.SessionKey(id), authenticate.LastSeenKey(id))
+	}
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sessions := make([]authEntity.Session, 0, len(sessionIds))
+	stale := make([]string, 0)
+	for i, id := range sessionIds {
+		raw, ok := values[2*i].(string)
+		if !ok {
+			stale = append(stale, id)
+			continue
+		}
+		var session authEntity.Session
+		if err := json.Unmarshal([]byte(raw), &session); err != nil || now.After(session.Expires) {
+			stale = append(stale, id)
+			continue
+		}
+
+		session.LastSeen = session.CreatedAt
+		if seen, ok := values[2*i+1].(string); ok {
+			if unix, err := strconv.ParseInt(seen, 10, 64); err == nil {
+				session.LastSeen = time.Unix(unix, 0)
+			}
+		}
+		sessions = append(sessions, session)
+	}
+
+	if len(stale) > 0 {
+		//nolint:errcheck
+		r.client.SRem(ctx, key, toInterfaces(stale)...)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeen.After(sessions[j].LastSeen)
+	})
+	return sessions, nil
+}
+
+// DelUserSession завершает сессию пользователя по её публичному
+// идентификатору. Чужие и неизвестные сессии — authEntity.ErrSessionNotFound
+func (r *SessionRepository) DelUserSession(userId int, handle string) error {
+	ctx := context.Background()
+
+	sessionIds, err := r.client.SMembers(ctx, userSessionsKey(userId)).Result()
+	if err != nil {
+		return err
+	}
+	for _, id := range sessionIds {
+		if authEntity.SessionHandle(id) == handle {
+			return r.DelSession(id)
+		}
+	}
+	return authEntity.ErrSessionNotFound
+}
+
+// DelUserSessions завершает все сессии пользователя, кроме exceptSessionId
+// (пустая строка — завершить все)
+func (r *SessionRepository) DelUserSessions(userId int, exceptSessionId string) error {
+	ctx := context.Background()
+
+	key := userSessionsKey(userId)
+	sessionIds, err := r.client.SMembers(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+
+	revoked := make([]string, 0, len(sessionIds))
+	keys := make([]string, 0, 2*len(sessionIds))
+	for _, id := range sessionIds {
+		if id != exceptSessionId {
+			revoked = append(revoked, id)
+			keys = append(keys, authenticate.SessionKey(id), authenticate.LastSeenKey(id))
+		}
+	}
+	if len(revoked) == 0 {
+		return nil
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, keys...)
+		pipe.SRem(ctx, key, toInterfaces(revoked)...)
+		return nil
+	})
+	return err
+}
+
+// userSessionsKey — множество идентификаторов сессий пользователя. Истёкшие
+// сессии вычищает ListUserSessions, лишний DEL по ним безвреден
+func userSessionsKey(userId int) string {
+	return fmt.Sprintf("user_sessions:%d", userId)
+}
+
+func toInterfaces(values []string) []interface{} {
+	out := make([]interface{}, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
+
+func truncate(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+	return string(runes[:limit])
 }
 
 func (r *SessionRepository) generateSessionID() (string, error) {
@@ -1,6 +1,7 @@
 package repo
 
 import (
+	"strconv"
 	"testing"
 	"time"
 
@@ -27,7 +28,7 @@ func TestGenerateSessionID(t *testing.T) {
 
 func TestAddAndGetSession_Success(t *testing.T) {
 	repo, _ := setupMiniredis(t, time.Second)
-	sess, err := repo.AddSession(42, 2)
+	sess, err := repo.AddSession(42, 2, true, false, authEntity.SessionClient{})
 	if err != nil {
 		t.Fatalf("AddSession error: %v", err)
 	}
@@ -50,7 +51,7 @@ func TestGetSession_NotFound(t *testing.T) {
 
 func TestSessionExpiration(t *testing.T) {
 	repo, _ := setupMiniredis(t, 50*time.Millisecond)
-	sess, err := repo.AddSession(1, 1)
+	sess, err := repo.AddSession(1, 1, true, false, authEntity.SessionClient{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -63,7 +64,7 @@ func TestSessionExpiration(t *testing.T) {
 
 func TestDelSession(t *testing.T) {
 	repo, _ := setupMiniredis(t, time.Second)
-	sess, _ := repo.AddSession(7, 1)
+	sess, _ := repo.AddSession(7, 1, true, false, authEntity.SessionClient{})
 	if err := repo.DelSession(sess.ID); err != nil {
 		t.Fatalf("DelSession error: %v", err)
 	}
@@ -73,6 +74,80 @@ func TestDelSession(t *testing.T) {
 	}
 }
 
+func TestDelUserSessions(t *testing.T) {
+	repo, _ := setupMiniredis(t, time.Minute)
+	current, _ := repo.AddSession(7, 1, true, false, authEntity.SessionClient{})
+	other, _ := repo.AddSession(7, 1, true, false, authEntity.SessionClient{})
+	stranger, _ := repo.AddSession(8, 1, true, false, authEntity.SessionClient{})
+
+	if err := repo.DelUserSessions(7, current.ID); err != nil {
+		t.Fatalf("DelUserSessions error: %v", err)
+	}
+	if _, err := repo.GetSession(other.ID); err != authEntity.ErrSessionNotFound {
+		t.Errorf("expected other session revoked, got %v", err)
+	}
+	if _, err := repo.GetSession(current.ID); err != nil {
+		t.Errorf("expected current session kept, got %v", err)
+	}
+	if _, err := repo.GetSession(stranger.ID); err != nil {
+		t.Errorf("expected sessions of other users kept, got %v", err)
+	}
+
+	if err := repo.DelUserSessions(7, ""); err != nil {
+		t.Fatalf("DelUserSessions error: %v", err)
+	}
+	if _, err := repo.GetSession(current.ID); err != authEntity.ErrSessionNotFound {
+		t.Errorf("expected all sessions revoked, got %v", err)
+	}
+}
+
+func TestListUserSessions(t *testing.T) {
+	repo, s := setupMiniredis(t, time.Minute)
+	first, _ := repo.AddSession(7, 1, true, false, authEntity.SessionClient{IP: "10.0.0.1", UserAgent: "curl/8"})
+	second, _ := repo.AddSession(7, 1, true, false, authEntity.SessionClient{IP: "10.0.0.2"})
+	repo.AddSession(8, 1, true, false, authEntity.SessionClient{})
+	s.Set("session_seen:"+first.ID, strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+	// осиротевшая запись в множестве: сама сессия истекла
+	s.SAdd("user_sessions:7", "gone")
+
+	sessions, err := repo.ListUserSessions(7)
+	if err != nil {
+		t.Fatalf("ListUserSessions error: %v", err)
+	}
+	if len(sessions) != 2 || sessions[0].ID != first.ID || sessions[1].ID != second.ID {
+		t.Fatalf("expected both sessions, most recent first, got %+v", sessions)
+	}
+	if sessions[0].IP != "10.0.0.1" || sessions[0].UserAgent != "curl/8" {
+		t.Errorf("expected client metadata, got %+v", sessions[0])
+	}
+	if members, _ := s.Members("user_sessions:7"); len(members) != 2 {
+		t.Errorf("expected stale member dropped, got %v", members)
+	}
+}
+
+func TestDelUserSession(t *testing.T) {
+	repo, s := setupMiniredis(t, time.Minute)
+	own, _ := repo.AddSession(7, 1, true, false, authEntity.SessionClient{})
+	stranger, _ := repo.AddSession(8, 1, true, false, authEntity.SessionClient{})
+
+	if err := repo.DelUserSession(7, authEntity.SessionHandle(stranger.ID)); err != authEntity.ErrSessionNotFound {
+		t.Errorf("expected foreign session not found, got %v", err)
+	}
+	if _, err := repo.GetSession(stranger.ID); err != nil {
+		t.Errorf("expected foreign session kept, got %v", err)
+	}
+
+	if err := repo.DelUserSession(7, authEntity.SessionHandle(own.ID)); err != nil {
+		t.Fatalf("DelUserSession error: %v", err)
+	}
+	if _, err := repo.GetSession(own.ID); err != authEntity.ErrSessionNotFound {
+		t.Errorf("expected session revoked, got %v", err)
+	}
+	if s.Exists("session_seen:"+own.ID) || s.Exists("user_sessions:7") {
+		t.Errorf("expected last-seen key and index entry removed")
+	}
+}
+
 func TestCloseConnection(t *testing.T) {
 	repo, s := setupMiniredis(t, time.Second)
 	s.Close()
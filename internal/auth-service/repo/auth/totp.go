@@ -0,0 +1,136 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	authEntity "retarget/internal/auth-service/entity/auth"
+	optiLog "retarget/pkg/utils/optiLog"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// GetTOTP возвращает секрет второго фактора; без записи — authEntity.ErrTOTPNotFound
+func (r *AuthRepository) GetTOTP(userID int, requestID string) (authEntity.TOTP, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	totp := authEntity.TOTP{UserID: userID}
+	err := r.db.QueryRowContext(ctx, "SELECT secret, enabled FROM auth_totp WHERE user_id = $1", userID).
+		Scan(&totp.Secret, &totp.Enabled)
+	if errors.Is(err, sql.ErrNoRows) {
+		return totp, authEntity.ErrTOTPNotFound
+	}
+	if err != nil {
+		return totp, fmt.Errorf("database error: %w", err)
+	}
+	return totp, nil
+}
+
+// SaveTOTPSecret заводит или заменяет неподтверждённый секрет. Включённый
+// второй фактор так не перезаписывается: authEntity.ErrTOTPEnabled
+func (r *AuthRepository) SaveTOTPSecret(userID int, secret string, requestID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx, `
+        INSERT INTO auth_totp (user_id, secret) VALUES ($1, $2)
+        ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, created_at = (now() AT TIME ZONE 'UTC')
+        WHERE auth_totp.enabled = FALSE`, userID, secret)
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return authEntity.ErrTOTPEnabled
+	}
+	return nil
+}
+
+// EnableTOTP включает второй фактор и заменяет коды восстановления
+func (r *AuthRepository) EnableTOTP(userID int, recoveryHashes []string, requestID string) error {
+	return r.inTx(requestID, "TOTP enable failed", userID, func(ctx context.Context, tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, "UPDATE auth_totp SET enabled = TRUE, enabled_at = (now() AT TIME ZONE 'UTC') WHERE user_id = $1 AND enabled = FALSE", userID)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err == nil && n == 0 {
+			return authEntity.ErrTOTPEnabled
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM auth_recovery_code WHERE user_id = $1", userID); err != nil {
+			return err
+		}
+		for _, hash := range recoveryHashes {
+			if _, err := tx.ExecContext(ctx, "INSERT INTO auth_recovery_code (user_id, code_hash) VALUES ($1, $2)", userID, hash); err != nil {
+				return err
+			}
+		}
+		_, err = tx.ExecContext(ctx, "INSERT INTO auth_audit_log (user_id, event, details, request_id) VALUES ($1, $2, '{}', $3)",
+			userID, authEntity.AuditTOTPEnabled, requestID)
+		return err
+	})
+}
+
+// DisableTOTP удаляет секрет и коды восстановления
+func (r *AuthRepository) DisableTOTP(userID int, requestID string) error {
+	return r.inTx(requestID, "TOTP disable failed", userID, func(ctx context.Context, tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM auth_totp WHERE user_id = $1", userID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM auth_recovery_code WHERE user_id = $1", userID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "INSERT INTO auth_audit_log (user_id, event, details, request_id) VALUES ($1, $2, '{}', $3)",
+			userID, authEntity.AuditTOTPDisabled, requestID)
+		return err
+	})
+}
+
+// UseRecoveryCode гасит код восстановления; неизвестный или использованный
+// код — authEntity.ErrTOTPInvalid
+func (r *AuthRepository) UseRecoveryCode(userID int, codeHash string, requestID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx, "UPDATE auth_recovery_code SET used_at = (now() AT TIME ZONE 'UTC') WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL", userID, codeHash)
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return authEntity.ErrTOTPInvalid
+	}
+	return nil
+}
+
+func (r *AuthRepository) inTx(requestID, failure string, userID int, fn func(ctx context.Context, tx *sql.Tx) error) error {
+	startTime := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err == nil {
+		err = fn(ctx, tx)
+		if err == nil {
+			err = tx.Commit()
+		} else {
+			//nolint:errcheck
+			tx.Rollback()
+		}
+	}
+
+	var entityErr *authEntity.Error
+	if errors.As(err, &entityErr) {
+		return err
+	}
+	if err != nil {
+		r.asyncLogger.Log(zapcore.WarnLevel, requestID, failure,
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"userID": userID,
+				"error":  err.Error(),
+			}))
+		return fmt.Errorf("database error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package repo
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	authEntity "retarget/internal/auth-service/entity/auth"
+)
+
+func TestGetTOTP(t *testing.T) {
+	repo, mock := setupRepo(t)
+	mock.ExpectQuery("FROM auth_totp WHERE user_id = \\$1").WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"secret", "enabled"}).AddRow("SECRET", true))
+	got, err := repo.GetTOTP(3, "req")
+	if err != nil || got.Secret != "SECRET" || !got.Enabled || got.UserID != 3 {
+		t.Fatalf("unexpected totp %+v, err %v", got, err)
+	}
+
+	mock.ExpectQuery("FROM auth_totp").WithArgs(4).WillReturnError(sql.ErrNoRows)
+	if _, err := repo.GetTOTP(4, "req"); err != authEntity.ErrTOTPNotFound {
+		t.Errorf("expected ErrTOTPNotFound, got %v", err)
+	}
+}
+
+func TestSaveTOTPSecret_KeepsEnabled(t *testing.T) {
+	repo, mock := setupRepo(t)
+	mock.ExpectExec("INSERT INTO auth_totp").WithArgs(3, "NEW").WillReturnResult(sqlmock.NewResult(0, 0))
+	if err := repo.SaveTOTPSecret(3, "NEW", "req"); err != authEntity.ErrTOTPEnabled {
+		t.Errorf("expected ErrTOTPEnabled, got %v", err)
+	}
+}
+
+func TestEnableTOTP(t *testing.T) {
+	repo, mock := setupRepo(t)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE auth_totp SET enabled = TRUE").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM auth_recovery_code").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO auth_recovery_code").WithArgs(3, "h1").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO auth_recovery_code").WithArgs(3, "h2").WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec("INSERT INTO auth_audit_log").WithArgs(3, authEntity.AuditTOTPEnabled, "req").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	if err := repo.EnableTOTP(3, []string{"h1", "h2"}, "req"); err != nil {
+		t.Fatalf("EnableTOTP error: %v", err)
+	}
+
+	// уже включён: коды восстановления не трогаем
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE auth_totp SET enabled = TRUE").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	if err := repo.EnableTOTP(3, []string{"h3"}, "req"); err != authEntity.ErrTOTPEnabled {
+		t.Errorf("expected ErrTOTPEnabled, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestUseRecoveryCode(t *testing.T) {
+	repo, mock := setupRepo(t)
+	mock.ExpectExec("UPDATE auth_recovery_code SET used_at").WithArgs(3, "h1").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := repo.UseRecoveryCode(3, "h1", "req"); err != nil {
+		t.Fatalf("UseRecoveryCode error: %v", err)
+	}
+	mock.ExpectExec("UPDATE auth_recovery_code SET used_at").WithArgs(3, "h1").WillReturnResult(sqlmock.NewResult(0, 0))
+	if err := repo.UseRecoveryCode(3, "h1", "req"); err != authEntity.ErrTOTPInvalid {
+		t.Errorf("expected used code rejected, got %v", err)
+	}
+}
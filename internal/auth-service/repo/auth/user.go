@@ -3,6 +3,7 @@ package repo
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -11,7 +12,7 @@ import (
 	authEntity "retarget/internal/auth-service/entity/auth"
 	optiLog "retarget/pkg/utils/optiLog"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -22,6 +23,29 @@ type AuthRepositoryInterface interface {
 	GetUserByUsername(username string, requestID string) (*authEntity.User, error)
 	CheckEmailOrUsernameExists(email, username string, requestID string) (*authEntity.User, error)
 	CreateNewUser(user *authEntity.User, requestID string) error
+	SetEmailVerified(userID int, requestID string) error
+	UpdatePassword(userID int, passwordHash []byte, requestID string) error
+	UpdateEmail(userID int, oldEmail, newEmail string, requestID string) error
+	GetTOTP(userID int, requestID string) (authEntity.TOTP, error)
+	SaveTOTPSecret(userID int, secret string, requestID string) error
+	EnableTOTP(userID int, recoveryHashes []string, requestID string) error
+	DisableTOTP(userID int, requestID string) error
+	UseRecoveryCode(userID int, codeHash string, requestID string) error
+	GetUserByIdentity(provider, subject string, requestID string) (*authEntity.User, error)
+	LinkIdentity(userID int, identity authEntity.ExternalIdentity, requestID string) error
+	CreateUserWithIdentity(user *authEntity.User, identity authEntity.ExternalIdentity, requestID string) error
+	CreateAPIKey(key *authEntity.APIKey, requestID string) error
+	ListAPIKeys(userID int, requestID string) ([]authEntity.APIKey, error)
+	ListActiveAPIKeys(requestID string) ([]authEntity.APIKey, error)
+	RevokeAPIKey(userID int, keyID int, requestID string) (authEntity.APIKey, error)
+	CreateOrganization(org *authEntity.Organization, account *authEntity.User, ownerID int, requestID string) error
+	ListMemberships(userID int, requestID string) ([]authEntity.Membership, error)
+	GetMembership(orgID, userID int, requestID string) (authEntity.Membership, error)
+	ListOrgMembers(orgID int, requestID string) ([]authEntity.OrgMember, error)
+	CreateOrgInvite(invite *authEntity.OrgInvite, requestID string) error
+	AcceptOrgInvite(tokenHash string, userID int, email string, requestID string) (authEntity.Membership, error)
+	UpdateOrgMemberRole(orgID, userID int, role string, actorID int, requestID string) error
+	RemoveOrgMember(orgID, userID int, actorID int, requestID string) error
 	CloseConnection() error
 }
 
@@ -118,7 +142,7 @@ func (r *AuthRepository) getUserByColumnOnce(columnName, value string, requestID
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := fmt.Sprintf("SELECT id, username, email, password, description, balance, role FROM auth_user WHERE %s = $1", columnName)
+	query := fmt.Sprintf("SELECT id, username, email, password, description, balance, role, email_verified FROM auth_user WHERE %s = $1 AND NOT is_organization", columnName)
 
 	r.asyncLogger.Log(zapcore.DebugLevel, requestID, "Executing SQL query",
 		optiLog.MakeLogFields(requestID, 0, map[string]interface{}{
@@ -138,6 +162,7 @@ func (r *AuthRepository) getUserByColumnOnce(columnName, value string, requestID
 		&user.Description,
 		&user.Balance,
 		&user.Role,
+		&user.EmailVerified,
 	)
 
 	duration := time.Since(startTime).Milliseconds()
@@ -188,7 +213,7 @@ func (r *AuthRepository) getUserByIDOnce(id int, requestID string) (*authEntity.
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := "SELECT id, username, email, password, description, balance, role FROM auth_user WHERE id = $1"
+	query := "SELECT id, username, email, password, description, balance, role, email_verified FROM auth_user WHERE id = $1"
 	r.asyncLogger.Log(zapcore.DebugLevel, requestID, "Executing SQL query",
 		optiLog.MakeLogFields(requestID, 0, map[string]interface{}{
 			"query": query,
@@ -206,6 +231,7 @@ func (r *AuthRepository) getUserByIDOnce(id int, requestID string) (*authEntity.
 		&user.Description,
 		&user.Balance,
 		&user.Role,
+		&user.EmailVerified,
 	)
 
 	duration := time.Since(startTime).Milliseconds()
@@ -373,6 +399,84 @@ func (r *AuthRepository) CheckEmailOrUsernameExists(email, username string, requ
 	return user, nil
 }
 
+// SetEmailVerified отмечает почту пользователя подтверждённой
+func (r *AuthRepository) SetEmailVerified(userID int, requestID string) error {
+	startTime := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "UPDATE auth_user SET email_verified = TRUE, updated_at = (now() AT TIME ZONE 'UTC') WHERE id = $1", userID)
+	if err != nil {
+		r.asyncLogger.Log(zapcore.WarnLevel, requestID, "Email verification update failed",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"userID": userID,
+				"error":  err.Error(),
+			}))
+		return fmt.Errorf("database error: %w", err)
+	}
+	return nil
+}
+
+// UpdatePassword заменяет хеш пароля пользователя
+func (r *AuthRepository) UpdatePassword(userID int, passwordHash []byte, requestID string) error {
+	startTime := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "UPDATE auth_user SET password = $1, updated_at = (now() AT TIME ZONE 'UTC') WHERE id = $2", passwordHash, userID)
+	if err != nil {
+		r.asyncLogger.Log(zapcore.WarnLevel, requestID, "Password update failed",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"userID": userID,
+				"error":  err.Error(),
+			}))
+		return fmt.Errorf("database error: %w", err)
+	}
+	return nil
+}
+
+// UpdateEmail меняет почту пользователя (уже подтверждённую) и пишет событие в
+// auth_audit_log в одной транзакции. Занятый адрес — authEntity.ErrEmailTaken
+func (r *AuthRepository) UpdateEmail(userID int, oldEmail, newEmail string, requestID string) error {
+	startTime := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	details, err := json.Marshal(map[string]string{"old_email": oldEmail, "new_email": newEmail})
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	//nolint:errcheck
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "UPDATE auth_user SET email = $1, email_verified = TRUE, updated_at = (now() AT TIME ZONE 'UTC') WHERE id = $2", newEmail, userID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return authEntity.ErrEmailTaken
+	}
+	if err == nil {
+		_, err = tx.ExecContext(ctx, "INSERT INTO auth_audit_log (user_id, event, details, request_id) VALUES ($1, $2, $3, $4)",
+			userID, authEntity.AuditEmailChanged, details, requestID)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		r.asyncLogger.Log(zapcore.WarnLevel, requestID, "Email update failed",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"userID": userID,
+				"error":  err.Error(),
+			}))
+		return fmt.Errorf("database error: %w", err)
+	}
+	return nil
+}
+
 func (r *AuthRepository) CloseConnection() error {
 	r.asyncLogger.Close()
 	return r.db.Close()
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 	"gopkg.in/inf.v0"
 
@@ -26,9 +27,9 @@ func setupRepo(t *testing.T) (*AuthRepository, sqlmock.Sqlmock) {
 
 func TestGetUserByID_Success(t *testing.T) {
 	repo, mock := setupRepo(t)
-	rows := sqlmock.NewRows([]string{"id", "username", "email", "password", "description", "balance", "role"}).
-		AddRow(1, "user1", "u1@example.com", []byte("pass"), "desc", "100.0", 2)
-	mock.ExpectQuery("SELECT id, username, email, password, description, balance, role FROM auth_user WHERE id = \\$1").
+	rows := sqlmock.NewRows([]string{"id", "username", "email", "password", "description", "balance", "role", "email_verified"}).
+		AddRow(1, "user1", "u1@example.com", []byte("pass"), "desc", "100.0", 2, true)
+	mock.ExpectQuery("SELECT id, username, email, password, description, balance, role, email_verified FROM auth_user WHERE id = \\$1").
 		WithArgs(1).
 		WillReturnRows(rows)
 
@@ -43,7 +44,7 @@ func TestGetUserByID_Success(t *testing.T) {
 
 func TestGetUserByID_NotFound(t *testing.T) {
 	repo, mock := setupRepo(t)
-	mock.ExpectQuery("SELECT id, username, email, password, description, balance, role FROM auth_user WHERE id = \\$1").
+	mock.ExpectQuery("SELECT id, username, email, password, description, balance, role, email_verified FROM auth_user WHERE id = \\$1").
 		WithArgs(2).
 		WillReturnError(sql.ErrNoRows)
 
@@ -55,8 +56,8 @@ func TestGetUserByID_NotFound(t *testing.T) {
 
 func TestGetUserByEmail_Success(t *testing.T) {
 	repo, mock := setupRepo(t)
-	rows := sqlmock.NewRows([]string{"id", "username", "email", "password", "description", "balance", "role"}).
-		AddRow(3, "user3", "u3@example.com", []byte("pass3"), "", "0.0", 1)
+	rows := sqlmock.NewRows([]string{"id", "username", "email", "password", "description", "balance", "role", "email_verified"}).
+		AddRow(3, "user3", "u3@example.com", []byte("pass3"), "", "0.0", 1, true)
 	mock.ExpectQuery("WHERE email = \\$1").
 		WithArgs("u3@example.com").
 		WillReturnRows(rows)
@@ -69,8 +70,8 @@ func TestGetUserByEmail_Success(t *testing.T) {
 
 func TestGetUserByUsername_Success(t *testing.T) {
 	repo, mock := setupRepo(t)
-	rows := sqlmock.NewRows([]string{"id", "username", "email", "password", "description", "balance", "role"}).
-		AddRow(4, "user4", "u4@example.com", []byte("pass4"), "", "0.0", 1)
+	rows := sqlmock.NewRows([]string{"id", "username", "email", "password", "description", "balance", "role", "email_verified"}).
+		AddRow(4, "user4", "u4@example.com", []byte("pass4"), "", "0.0", 1, true)
 	mock.ExpectQuery("WHERE username = \\$1").
 		WithArgs("user4").
 		WillReturnRows(rows)
@@ -145,6 +146,50 @@ func TestCreateNewUser(t *testing.T) {
 	}
 }
 
+func TestUpdatePassword(t *testing.T) {
+	repo, mock := setupRepo(t)
+	mock.ExpectExec("UPDATE auth_user SET password = \\$1").
+		WithArgs([]byte("hash"), 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := repo.UpdatePassword(5, []byte("hash"), "req"); err != nil {
+		t.Fatalf("UpdatePassword error: %v", err)
+	}
+
+	mock.ExpectExec("UPDATE auth_user SET password =").WillReturnError(errors.New("boom"))
+	if err := repo.UpdatePassword(5, []byte("hash"), "req"); err == nil {
+		t.Error("expected error")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestUpdateEmail(t *testing.T) {
+	repo, mock := setupRepo(t)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE auth_user SET email = \\$1, email_verified = TRUE").
+		WithArgs("new@example.com", 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO auth_audit_log").
+		WithArgs(5, authEntity.AuditEmailChanged, sqlmock.AnyArg(), "req").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	if err := repo.UpdateEmail(5, "old@example.com", "new@example.com", "req"); err != nil {
+		t.Fatalf("UpdateEmail error: %v", err)
+	}
+
+	// адрес успели занять между запросом и подтверждением
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE auth_user SET email").WillReturnError(&pq.Error{Code: "23505"})
+	mock.ExpectRollback()
+	if err := repo.UpdateEmail(5, "old@example.com", "taken@example.com", "req"); err != authEntity.ErrEmailTaken {
+		t.Errorf("expected ErrEmailTaken, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sqlmock expectations: %v", err)
+	}
+}
+
 func TestIsTransientErrorAndWithRetry(t *testing.T) {
 	// isTransientError
 	if !isTransientError(context.DeadlineExceeded) {
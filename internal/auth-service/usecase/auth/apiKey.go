@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	entityAuth "retarget/internal/auth-service/entity/auth"
+	authenticate "retarget/pkg/middleware/auth"
+	"retarget/pkg/utils/optiLog"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// -----------------------------
+// API-ключи
+// -----------------------------
+
+func generateAPIKey() (string, error) {
+	secret, err := randomURLString(entityAuth.APIKeySecretBytes)
+	if err != nil {
+		return "", err
+	}
+	return authenticate.APIKeyPrefix + secret, nil
+}
+
+// normalizeScopes проверяет права ключа и убирает повторы
+func normalizeScopes(scopes []string) ([]string, error) {
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !authenticate.ValidScope(scope) {
+			return nil, entityAuth.ErrAPIKeyScope
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+	if len(result) == 0 {
+		return nil, entityAuth.ErrAPIKeyScope
+	}
+	return result, nil
+}
+
+// CreateAPIKey выпускает ключ с правами scopes. Сам ключ возвращается только
+// здесь: хранится лишь его хеш. expiresAt == nil — бессрочный ключ
+func (a *AuthUsecase) CreateAPIKey(ctx context.Context, userID int, role int, name string, scopes []string, expiresAt *time.Time, requestID string) (*entityAuth.APIKey, string, error) {
+	startTime := time.Now()
+
+	scopes, err := normalizeScopes(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", entityAuth.ErrAPIKeyExpiry
+	}
+
+	secret, err := generateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+	key := &entityAuth.APIKey{
+		UserID:    userID,
+		Role:      role,
+		Name:      strings.TrimSpace(name),
+		Prefix:    secret[:entityAuth.APIKeyPrefixLength],
+		KeyHash:   authenticate.APIKeyHash(secret),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+	if err := a.authRepository.CreateAPIKey(key, requestID); err != nil {
+		return nil, "", err
+	}
+
+	if err := a.sessionRepository.SaveAPIKey(*key); err != nil {
+		// ключ, который не проверить, не выдаём
+		//nolint:errcheck
+		a.authRepository.RevokeAPIKey(userID, key.ID, requestID)
+		return nil, "", err
+	}
+
+	a.asyncLogger.Log(zapcore.InfoLevel, requestID, "API key created",
+		optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+			"userID": userID,
+			"keyID":  key.ID,
+			"scopes": strings.Join(scopes, ","),
+		}))
+	return key, secret, nil
+}
+
+func (a *AuthUsecase) ListAPIKeys(ctx context.Context, userID int, requestID string) ([]entityAuth.APIKey, error) {
+	return a.authRepository.ListAPIKeys(userID, requestID)
+}
+
+// RevokeAPIKey отзывает ключ: запросы с ним перестают проходить сразу
+func (a *AuthUsecase) RevokeAPIKey(ctx context.Context, userID int, keyID int, requestID string) error {
+	startTime := time.Now()
+
+	key, err := a.authRepository.RevokeAPIKey(userID, keyID, requestID)
+	if err != nil {
+		return err
+	}
+	if err := a.sessionRepository.DelAPIKey(key.KeyHash); err != nil {
+		return err
+	}
+
+	a.asyncLogger.Log(zapcore.InfoLevel, requestID, "API key revoked",
+		optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+			"userID": userID,
+			"keyID":  keyID,
+		}))
+	return nil
+}
+
+// SyncAPIKeys заново публикует действующие ключи в Redis, например после
+// его перезапуска без сохранения данных
+func (a *AuthUsecase) SyncAPIKeys(ctx context.Context, requestID string) error {
+	keys, err := a.authRepository.ListActiveAPIKeys(requestID)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := a.sessionRepository.SaveAPIKey(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
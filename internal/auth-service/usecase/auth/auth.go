@@ -7,13 +7,14 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"net/url"
 	"strings"
-	"sync"
 	"time"
 
 	entityAuth "retarget/internal/auth-service/entity/auth"
 	repoAuth "retarget/internal/auth-service/repo/auth"
 	"retarget/pkg/utils/optiLog"
+	"retarget/pkg/utils/throttle"
 
 	"go.uber.org/zap/zapcore"
 	"golang.org/x/crypto/argon2"
@@ -37,60 +38,82 @@ var DefaultHashConfig = HashConfig{
 	KeyLength:   32,
 }
 
-// SimpleRateLimiter - простой рейт-лимитер на основе времени последнего доступа
-type SimpleRateLimiter struct {
-	lastAccess map[string]time.Time
-	interval   time.Duration
-	mu         sync.RWMutex
-}
-
-func NewSimpleRateLimiter(interval time.Duration) *SimpleRateLimiter {
-	return &SimpleRateLimiter{
-		lastAccess: make(map[string]time.Time),
-		interval:   interval,
-	}
-}
-
-func (r *SimpleRateLimiter) Allow(key string) bool {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	now := time.Now()
-	if last, ok := r.lastAccess[key]; ok && now.Sub(last) < r.interval {
-		return false
-	}
-	r.lastAccess[key] = now
-	return true
-}
-
 type AuthUsecaseInterface interface {
-	Login(ctx context.Context, email string, password string, role int, requestID string) (*entityAuth.User, error)
+	Login(ctx context.Context, email string, password string, role int, ip string, requestID string) (*entityAuth.User, *entityAuth.LoginChallenge, error)
 	Logout(sessionId string) error
 	Register(ctx context.Context, username string, email string, password string, role int, requestID string) (*entityAuth.User, error)
 	GetUser(ctx context.Context, userID int, requestID string) (*entityAuth.User, error)
 	CheckCode(code int, userId int) error
 	CreateCode(userId int) (int, error)
-	AddSession(userID int, role int) (*entityAuth.Session, error)
+	SendVerificationCode(ctx context.Context, userID int, requestID string) error
+	ConfirmEmail(ctx context.Context, userID int, code int, sessionID string, requestID string) error
+	AddSession(userID int, role int, verified bool, twoFactor bool, client entityAuth.SessionClient) (*entityAuth.Session, error)
+	ListSessions(ctx context.Context, userID int, requestID string) ([]entityAuth.Session, error)
+	RevokeSession(ctx context.Context, userID int, handle string, requestID string) error
+	RevokeAllSessions(ctx context.Context, userID int, requestID string) error
+	RequestPasswordReset(ctx context.Context, email string, requestID string) error
+	ResetPassword(ctx context.Context, token string, password string, requestID string) error
+	RequestPasswordChange(ctx context.Context, userID int, oldPassword string, requestID string) error
+	ChangePassword(ctx context.Context, userID int, code int, password string, sessionID string, requestID string) error
+	RequestEmailChange(ctx context.Context, userID int, newEmail string, requestID string) error
+	ConfirmEmailChange(ctx context.Context, userID int, oldCode int, newCode int, sessionID string, requestID string) error
+	VerifyLoginChallenge(ctx context.Context, challengeID string, code string, client entityAuth.SessionClient, requestID string) (*entityAuth.Session, error)
+	EnrollTOTP(ctx context.Context, userID int, requestID string) (string, string, error)
+	ConfirmTOTP(ctx context.Context, userID int, code string, sessionID string, requestID string) ([]string, error)
+	DisableTOTP(ctx context.Context, userID int, password string, code string, requestID string) error
+	StartOAuthLogin(ctx context.Context, providerName string, role int, requestID string) (string, error)
+	StartOAuthLink(ctx context.Context, userID int, providerName string, requestID string) (string, error)
+	CompleteOAuthLogin(ctx context.Context, providerName string, code string, state string, extra url.Values, requestID string) (*entityAuth.User, *entityAuth.LoginChallenge, error)
+	CompleteOAuthLink(ctx context.Context, userID int, providerName string, code string, state string, extra url.Values, requestID string) error
+	CreateAPIKey(ctx context.Context, userID int, role int, name string, scopes []string, expiresAt *time.Time, requestID string) (*entityAuth.APIKey, string, error)
+	ListAPIKeys(ctx context.Context, userID int, requestID string) ([]entityAuth.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID int, keyID int, requestID string) error
+	CreateOrganization(ctx context.Context, userID int, name string, requestID string) (*entityAuth.Membership, error)
+	ListOrganizations(ctx context.Context, userID int, requestID string) ([]entityAuth.Membership, error)
+	ListOrgMembers(ctx context.Context, userID int, orgID int, requestID string) ([]entityAuth.OrgMember, error)
+	InviteToOrganization(ctx context.Context, userID int, orgID int, email string, role string, requestID string) error
+	AcceptOrgInvite(ctx context.Context, userID int, token string, requestID string) (*entityAuth.Membership, error)
+	ChangeOrgMemberRole(ctx context.Context, userID int, orgID int, memberID int, role string, requestID string) error
+	RemoveOrgMember(ctx context.Context, userID int, orgID int, memberID int, requestID string) error
+	SwitchOrganization(ctx context.Context, userID int, orgID int, sessionID string, requestID string) (*entityAuth.Membership, error)
 }
 
 type AuthUsecase struct {
 	authRepository    *repoAuth.AuthRepository
 	sessionRepository *repoAuth.SessionRepository
+	codeRepository    *repoAuth.CodeRepository
+	mailRepository    *repoAuth.MailRepository
 	hashCfg           HashConfig
-	rateLimiter       *SimpleRateLimiter
-	asyncLogger       *optiLog.AsyncLogger
+	// nil-правила отключают ограничение попыток входа
+	loginEmailRule *throttle.Rule
+	loginIPRule    *throttle.Rule
+	// oauthProviders — включённые провайдеры входа по имени из URL
+	oauthProviders map[string]repoAuth.OAuthProviderInterface
+	asyncLogger    *optiLog.AsyncLogger
 }
 
 func NewAuthUsecase(
 	userRepo *repoAuth.AuthRepository,
 	sessionRepo *repoAuth.SessionRepository,
+	codeRepo *repoAuth.CodeRepository,
+	mailRepo *repoAuth.MailRepository,
+	limiter *throttle.Limiter,
+	oauthProviders []repoAuth.OAuthProviderInterface,
 	logger *optiLog.AsyncLogger,
 ) *AuthUsecase {
+	providers := make(map[string]repoAuth.OAuthProviderInterface, len(oauthProviders))
+	for _, p := range oauthProviders {
+		providers[p.Name()] = p
+	}
 	return &AuthUsecase{
 		authRepository:    userRepo,
 		sessionRepository: sessionRepo,
+		codeRepository:    codeRepo,
+		mailRepository:    mailRepo,
 		hashCfg:           DefaultHashConfig,
-		rateLimiter:       NewSimpleRateLimiter(1 * time.Second),
+		loginEmailRule:    limiter.Rule(entityAuth.ThrottleLoginEmail, entityAuth.LoginEmailPolicy),
+		loginIPRule:       limiter.Rule(entityAuth.ThrottleLoginIP, entityAuth.LoginIPPolicy),
+		oauthProviders:    providers,
 		asyncLogger:       logger,
 	}
 }
@@ -99,15 +122,13 @@ func NewAuthUsecase(
 // Методы авторизации
 // -----------------------------
 
-func (a *AuthUsecase) Login(ctx context.Context, email string, password string, role int, requestID string) (*entityAuth.User, error) {
+// Login проверяет пароль. Если у пользователя включён второй фактор, вместо
+// сессии нужно открыть challenge: вход завершает VerifyLoginChallenge
+func (a *AuthUsecase) Login(ctx context.Context, email string, password string, role int, ip string, requestID string) (*entityAuth.User, *entityAuth.LoginChallenge, error) {
 	startTime := time.Now()
 
-	if !a.rateLimiter.Allow(email) {
-		a.asyncLogger.Log(zapcore.WarnLevel, requestID, "Too many login attempts",
-			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
-				"email": email,
-			}))
-		return nil, errors.New("слишком много попыток")
+	if err := a.checkLoginThrottle(ctx, email, ip, requestID); err != nil {
+		return nil, nil, err
 	}
 
 	var user *entityAuth.User
@@ -122,7 +143,8 @@ func (a *AuthUsecase) Login(ctx context.Context, email string, password string,
 				"email": email,
 				"error": err.Error(),
 			}))
-		return nil, errors.New("incorrect user data")
+		a.loginFailed(ctx, email, ip, false, requestID)
+		return nil, nil, errors.New("incorrect user data")
 	}
 
 	if err := compareHashAndPassword(string(user.Password), password, a.hashCfg); err != nil {
@@ -131,16 +153,33 @@ func (a *AuthUsecase) Login(ctx context.Context, email string, password string,
 				"userID": user.ID,
 				"email":  user.Email,
 			}))
-		return nil, errors.New("incorrect user data")
+		a.loginFailed(ctx, user.Email, ip, true, requestID)
+		return nil, nil, errors.New("incorrect user data")
+	}
+
+	challenge, err := a.loginChallenge(user, requestID)
+	if err != nil {
+		a.asyncLogger.Log(zapcore.ErrorLevel, requestID, "Failed to start two-factor login",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"userID": user.ID,
+				"error":  err.Error(),
+			}))
+		return nil, nil, err
+	}
+
+	// со вторым фактором счётчик сбросит VerifyLoginChallenge
+	if challenge == nil {
+		a.loginSucceeded(ctx, email, requestID)
 	}
 
 	a.asyncLogger.Log(zapcore.DebugLevel, requestID, "Login successful",
 		optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
 			"userID": user.ID,
 			"email":  user.Email,
+			"2fa":    challenge != nil,
 		}))
 
-	return user, nil
+	return user, challenge, nil
 }
 
 func (a *AuthUsecase) Logout(sessionId string) error {
@@ -187,7 +226,7 @@ func (a *AuthUsecase) Register(ctx context.Context, username string, email strin
 			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
 				"username": username,
 			}))
-		return nil, errors.New("пароль должен быть не короче 8 символов")
+		return nil, entityAuth.ErrPasswordTooShort
 	}
 
 	existingUser, err := a.authRepository.CheckEmailOrUsernameExists(email, username, requestID)
@@ -254,11 +293,20 @@ func (a *AuthUsecase) Register(ctx context.Context, username string, email strin
 			"email":  user.Email,
 		}))
 
+	// регистрация не откатывается, если письмо не ушло: код можно запросить повторно
+	if err := a.sendVerificationCode(user, requestID); err != nil {
+		a.asyncLogger.Log(zapcore.WarnLevel, requestID, "Verification code was not sent",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"userID": user.ID,
+				"error":  err.Error(),
+			}))
+	}
+
 	return user, nil
 }
 
-func (a *AuthUsecase) AddSession(userID int, role int) (*entityAuth.Session, error) {
-	session, err := a.sessionRepository.AddSession(userID, role)
+func (a *AuthUsecase) AddSession(userID int, role int, verified bool, twoFactor bool, client entityAuth.SessionClient) (*entityAuth.Session, error) {
+	session, err := a.sessionRepository.AddSession(userID, role, verified, twoFactor, client)
 	if err != nil {
 		return nil, err
 	}
@@ -3,7 +3,15 @@ package auth
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -11,12 +19,29 @@ import (
 	"github.com/alicebob/miniredis/v2"
 	"go.uber.org/zap"
 
+	entityAuth "retarget/internal/auth-service/entity/auth"
 	repoAuth "retarget/internal/auth-service/repo/auth"
+	authenticate "retarget/pkg/middleware/auth"
 	"retarget/pkg/utils/optiLog"
+	"retarget/pkg/utils/throttle"
+	"retarget/pkg/utils/totp"
 )
 
-// setupUsecase создаёт AuthUsecase с sqlmock-репозиторием и miniredis-сессией
+// setupUsecase создаёт AuthUsecase с sqlmock-репозиторием, miniredis-сессией
+// и заглушкой mail-service, которая принимает любые письма
 func setupUsecase(t *testing.T) (*AuthUsecase, sqlmock.Sqlmock, *repoAuth.SessionRepository) {
+	uc, mock, sessionRepo, _ := setupUsecaseWithMail(t)
+	return uc, mock, sessionRepo
+}
+
+// setupUsecaseWithMail дополнительно отдаёт коды, отправленные в mail-service
+func setupUsecaseWithMail(t *testing.T) (*AuthUsecase, sqlmock.Sqlmock, *repoAuth.SessionRepository, *[]string) {
+	uc, mock, sessionRepo, sent, _ := setupUsecaseWithRedis(t)
+	return uc, mock, sessionRepo, sent
+}
+
+// setupUsecaseWithRedis дополнительно отдаёт miniredis, общий для всех репозиториев
+func setupUsecaseWithRedis(t *testing.T) (*AuthUsecase, sqlmock.Sqlmock, *repoAuth.SessionRepository, *[]string, *miniredis.Miniredis) {
 	// sqlmock
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -32,9 +57,29 @@ func setupUsecase(t *testing.T) (*AuthUsecase, sqlmock.Sqlmock, *repoAuth.Sessio
 		t.Fatalf("miniredis start failed: %v", err)
 	}
 	sessionRepo := repoAuth.NewSessionRepository(mr.Addr(), "", 0, time.Second)
+	codeRepo := repoAuth.NewCodeRepository(mr.Addr(), "", 0, entityAuth.CodeTTL, entityAuth.CodeResendInterval)
 
-	uc := NewAuthUsecase(authRepo, sessionRepo, asyncLogger)
-	return uc, mock, sessionRepo
+	// mail-service
+	sent := &[]string{}
+	mailServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Code  string `json:"code"`
+			Token string `json:"token"`
+		}
+		//nolint:errcheck
+		json.NewDecoder(r.Body).Decode(&req)
+		*sent = append(*sent, req.Code+req.Token)
+	}))
+	t.Cleanup(mailServer.Close)
+	mailRepo := repoAuth.NewMailRepository(mailServer.URL, mailServer.Client())
+
+	limiter, err := throttle.NewLimiter(mr.Addr(), "", 0)
+	if err != nil {
+		t.Fatalf("limiter: %v", err)
+	}
+
+	uc := NewAuthUsecase(authRepo, sessionRepo, codeRepo, mailRepo, limiter, nil, asyncLogger)
+	return uc, mock, sessionRepo, sent, mr
 }
 
 func TestLogin_SuccessAndFailures(t *testing.T) {
@@ -43,35 +88,60 @@ func TestLogin_SuccessAndFailures(t *testing.T) {
 	storedHash := hashForTest(t, pass)
 
 	// успешный вход
-	rows := sqlmock.NewRows([]string{"id", "username", "email", "password", "description", "balance", "role"}).
-		AddRow(7, "u", "e@e", storedHash, "", "0", 1)
+	rows := sqlmock.NewRows([]string{"id", "username", "email", "password", "description", "balance", "role", "email_verified"}).
+		AddRow(7, "u", "e@e", storedHash, "", "0", 1, true)
 	mock.ExpectQuery("WHERE email = \\$1").WithArgs("e@e").WillReturnRows(rows)
-	u, err := uc.Login(context.Background(), "e@e", pass, 1, "req1")
-	if err != nil || u.ID != 7 {
-		t.Fatalf("expected ID=7, got %v, err %v", u, err)
+	mock.ExpectQuery("FROM auth_totp").WithArgs(7).WillReturnError(sql.ErrNoRows)
+	u, challenge, err := uc.Login(context.Background(), "e@e", pass, 1, "10.0.0.1", "req1")
+	if err != nil || u.ID != 7 || challenge != nil {
+		t.Fatalf("expected ID=7 without challenge, got %v %v, err %v", u, challenge, err)
 	}
 
 	// неправильный пароль
-	rows = sqlmock.NewRows([]string{"id", "username", "email", "password", "description", "balance", "role"}).
-		AddRow(7, "u", "e@e", storedHash, "", "0", 1)
+	rows = sqlmock.NewRows([]string{"id", "username", "email", "password", "description", "balance", "role", "email_verified"}).
+		AddRow(7, "u", "e@e", storedHash, "", "0", 1, true)
 	mock.ExpectQuery("WHERE email = \\$1").WithArgs("e@e").WillReturnRows(rows)
-	_, err = uc.Login(context.Background(), "e@e", "bad", 1, "req2")
-	if err == nil || err.Error() == "incorrect user data" {
+	_, _, err = uc.Login(context.Background(), "e@e", "bad", 1, "10.0.0.1", "req2")
+	if err == nil || err.Error() != "incorrect user data" {
 		t.Errorf("expected incorrect user data, got %v", err)
 	}
 
-	// слишком много попыток
-	rows = sqlmock.NewRows([]string{"id", "username", "email", "password", "description", "balance", "role"}).
-		AddRow(7, "u", "slow@e", storedHash, "", "0", 1)
-	mock.ExpectQuery("WHERE email = \\$1").WithArgs("slow@e").WillReturnRows(rows)
-	_, _ = uc.Login(context.Background(), "slow@e", pass, 1, "req3")
-	_, err = uc.Login(context.Background(), "slow@e", pass, 1, "req3")
-	if err == nil || err.Error() != "слишком много попыток" {
-		t.Errorf("expected rate limit error, got %v", err)
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestLogin_Lockout(t *testing.T) {
+	uc, mock, _, sent := setupUsecaseWithMail(t)
+	storedHash := hashForTest(t, "correct")
+	userRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "username", "email", "password", "description", "balance", "role", "email_verified"}).
+			AddRow(7, "u", "e@e", storedHash, "", "0", 1, true)
 	}
 
-	if err := mock.ExpectationsWereMet(); err == nil {
-		t.Errorf("unmet sqlmock expectations: %v", err)
+	// первые неудачи проходят без задержки, дальше задержка растёт
+	for i := 0; i < entityAuth.LoginEmailPolicy.FreeAttempts+1; i++ {
+		mock.ExpectQuery("WHERE email = \\$1").WithArgs("e@e").WillReturnRows(userRows())
+		if _, _, err := uc.Login(context.Background(), "e@e", "bad", 1, "10.0.0.1", "r"); err == nil || err.Error() != "incorrect user data" {
+			t.Fatalf("attempt %d: expected incorrect user data, got %v", i, err)
+		}
+	}
+	_, _, err := uc.Login(context.Background(), "E@e", "correct", 1, "10.0.0.2", "r")
+	if limited, ok := throttle.IsThrottled(err); !ok || limited.Locked {
+		t.Fatalf("expected backoff, got %v", err)
+	}
+
+	// блокировка после LockoutAfter неудач и письмо владельцу; задержки
+	// между попытками тут пропускаем, засчитывая неудачи напрямую
+	for i := entityAuth.LoginEmailPolicy.FreeAttempts + 1; i < entityAuth.LoginEmailPolicy.LockoutAfter; i++ {
+		uc.loginFailed(context.Background(), "e@e", "", true, "r")
+	}
+	if len(*sent) != 1 {
+		t.Fatalf("expected lockout notice sent, got %v", *sent)
+	}
+	_, _, err = uc.Login(context.Background(), "e@e", "correct", 1, "10.0.0.3", "r")
+	if limited, ok := throttle.IsThrottled(err); !ok || !limited.Locked {
+		t.Errorf("expected lockout, got %v", err)
 	}
 }
 
@@ -79,8 +149,8 @@ func TestGetUser_SuccessAndError(t *testing.T) {
 	uc, mock, _ := setupUsecase(t)
 
 	// success
-	rows := sqlmock.NewRows([]string{"id", "username", "email", "password", "description", "balance", "role"}).
-		AddRow(42, "u", "e@e", []byte("p"), "", "0", 1)
+	rows := sqlmock.NewRows([]string{"id", "username", "email", "password", "description", "balance", "role", "email_verified"}).
+		AddRow(42, "u", "e@e", []byte("p"), "", "0", 1, true)
 	mock.ExpectQuery("WHERE id = \\$1").WithArgs(42).WillReturnRows(rows)
 	user, err := uc.GetUser(context.Background(), 42, "reqG1")
 	if err != nil || user.ID != 42 {
@@ -159,3 +229,679 @@ func hashForTest(t *testing.T, pw string) string {
 	}
 	return string(h)
 }
+
+func TestConfirmEmail_Flow(t *testing.T) {
+	uc, mock, sessionRepo, sent := setupUsecaseWithMail(t)
+	userRows := func(verified bool) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "username", "email", "password", "description", "balance", "role", "email_verified"}).
+			AddRow(7, "u", "e@e", []byte("p"), "", "0", 1, verified)
+	}
+	session, err := sessionRepo.AddSession(7, 1, false, false, entityAuth.SessionClient{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectQuery("WHERE id = \\$1").WithArgs(7).WillReturnRows(userRows(false))
+	if err := uc.SendVerificationCode(context.Background(), 7, "r1"); err != nil {
+		t.Fatalf("send code: %v", err)
+	}
+	if len(*sent) != 1 || len((*sent)[0]) != entityAuth.CodeLength {
+		t.Fatalf("expected one 6-digit code, got %v", *sent)
+	}
+
+	// повторная отправка раньше CodeResendInterval
+	mock.ExpectQuery("WHERE id = \\$1").WithArgs(7).WillReturnRows(userRows(false))
+	if err := uc.SendVerificationCode(context.Background(), 7, "r2"); !errors.Is(err, entityAuth.ErrCodeCooldown) {
+		t.Errorf("expected cooldown, got %v", err)
+	}
+
+	code, _ := strconv.Atoi((*sent)[0])
+	mock.ExpectQuery("WHERE id = \\$1").WithArgs(7).WillReturnRows(userRows(false))
+	if err := uc.ConfirmEmail(context.Background(), 7, (code+1)%1_000_000, session.ID, "r3"); !errors.Is(err, entityAuth.ErrCodeInvalid) {
+		t.Errorf("expected invalid code, got %v", err)
+	}
+
+	mock.ExpectQuery("WHERE id = \\$1").WithArgs(7).WillReturnRows(userRows(false))
+	mock.ExpectExec("UPDATE auth_user SET email_verified = TRUE").WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := uc.ConfirmEmail(context.Background(), 7, code, session.ID, "r4"); err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+	got, err := sessionRepo.GetSession(session.ID)
+	if err != nil || got.Unverified {
+		t.Errorf("expected verified session, got %+v, err %v", got, err)
+	}
+
+	// код одноразовый
+	mock.ExpectQuery("WHERE id = \\$1").WithArgs(7).WillReturnRows(userRows(false))
+	if err := uc.ConfirmEmail(context.Background(), 7, code, session.ID, "r5"); !errors.Is(err, entityAuth.ErrCodeExpired) {
+		t.Errorf("expected expired code, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestCheckCode_AttemptLimit(t *testing.T) {
+	uc, _, _ := setupUsecase(t)
+
+	code, err := uc.CreateCode(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrong := (code + 1) % 1_000_000
+	for i := 1; i < entityAuth.CodeMaxAttempts; i++ {
+		if err := uc.CheckCode(wrong, 8); !errors.Is(err, entityAuth.ErrCodeInvalid) {
+			t.Fatalf("attempt %d: expected invalid code, got %v", i, err)
+		}
+	}
+	if err := uc.CheckCode(wrong, 8); !errors.Is(err, entityAuth.ErrCodeAttempts) {
+		t.Errorf("expected attempts exhausted, got %v", err)
+	}
+	// после исчерпания попыток и верный код не принимается
+	if err := uc.CheckCode(code, 8); !errors.Is(err, entityAuth.ErrCodeExpired) {
+		t.Errorf("expected expired code, got %v", err)
+	}
+}
+
+func TestCheckCode_ConcurrentUse(t *testing.T) {
+	uc, _, _ := setupUsecase(t)
+
+	code, err := uc.CreateCode(9)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	var accepted atomic.Int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := uc.CheckCode(code, 9); err == nil {
+				accepted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := accepted.Load(); n != 1 {
+		t.Errorf("expected the code to be accepted once, got %d", n)
+	}
+}
+
+func TestResetPassword_Flow(t *testing.T) {
+	uc, mock, sessionRepo, sent := setupUsecaseWithMail(t)
+	session, _ := sessionRepo.AddSession(7, 1, false, false, entityAuth.SessionClient{})
+
+	// неизвестная почта не раскрывается
+	mock.ExpectQuery("WHERE email = \\$1").WithArgs("x@e").WillReturnError(sql.ErrNoRows)
+	if err := uc.RequestPasswordReset(context.Background(), "x@e", "r1"); err != nil || len(*sent) != 0 {
+		t.Fatalf("expected silent success, got %v, sent %v", err, *sent)
+	}
+
+	mock.ExpectQuery("WHERE email = \\$1").WithArgs("e@e").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "username", "email", "password", "description", "balance", "role", "email_verified"}).
+			AddRow(7, "u", "e@e", []byte("p"), "", "0", 1, false))
+	if err := uc.RequestPasswordReset(context.Background(), "e@e", "r2"); err != nil || len(*sent) != 1 {
+		t.Fatalf("expected token sent, got %v, sent %v", err, *sent)
+	}
+	token := (*sent)[0]
+
+	if err := uc.ResetPassword(context.Background(), token, "short", "r3"); !errors.Is(err, entityAuth.ErrPasswordTooShort) {
+		t.Errorf("expected short password rejected, got %v", err)
+	}
+
+	mock.ExpectExec("UPDATE auth_user SET password =").WithArgs(sqlmock.AnyArg(), 7).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE auth_user SET email_verified = TRUE").WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := uc.ResetPassword(context.Background(), token, "new-password", "r4"); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if _, err := sessionRepo.GetSession(session.ID); !errors.Is(err, entityAuth.ErrSessionNotFound) {
+		t.Errorf("expected sessions revoked, got %v", err)
+	}
+
+	if err := uc.ResetPassword(context.Background(), token, "new-password", "r5"); !errors.Is(err, entityAuth.ErrTokenInvalid) {
+		t.Errorf("expected single-use token, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestChangePassword_Flow(t *testing.T) {
+	uc, mock, sessionRepo, sent := setupUsecaseWithMail(t)
+	current, _ := sessionRepo.AddSession(7, 1, true, false, entityAuth.SessionClient{})
+	other, _ := sessionRepo.AddSession(7, 1, true, false, entityAuth.SessionClient{})
+	userRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "username", "email", "password", "description", "balance", "role", "email_verified"}).
+			AddRow(7, "u", "e@e", hashForTest(t, "old-password"), "", "0", 1, true)
+	}
+
+	mock.ExpectQuery("WHERE id = \\$1").WithArgs(7).WillReturnRows(userRows())
+	if err := uc.RequestPasswordChange(context.Background(), 7, "wrong", "r1"); !errors.Is(err, entityAuth.ErrWrongPassword) {
+		t.Fatalf("expected wrong password, got %v", err)
+	}
+
+	mock.ExpectQuery("WHERE id = \\$1").WithArgs(7).WillReturnRows(userRows())
+	if err := uc.RequestPasswordChange(context.Background(), 7, "old-password", "r2"); err != nil || len(*sent) != 1 {
+		t.Fatalf("expected code sent, got %v, sent %v", err, *sent)
+	}
+	code, _ := strconv.Atoi((*sent)[0])
+
+	if err := uc.ChangePassword(context.Background(), 7, (code+1)%1_000_000, "new-password", current.ID, "r3"); !errors.Is(err, entityAuth.ErrCodeInvalid) {
+		t.Errorf("expected invalid code, got %v", err)
+	}
+
+	mock.ExpectExec("UPDATE auth_user SET password =").WithArgs(sqlmock.AnyArg(), 7).WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := uc.ChangePassword(context.Background(), 7, code, "new-password", current.ID, "r4"); err != nil {
+		t.Fatalf("change: %v", err)
+	}
+	if _, err := sessionRepo.GetSession(current.ID); err != nil {
+		t.Errorf("expected current session kept, got %v", err)
+	}
+	if _, err := sessionRepo.GetSession(other.ID); !errors.Is(err, entityAuth.ErrSessionNotFound) {
+		t.Errorf("expected other session revoked, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestEmailChange_Flow(t *testing.T) {
+	uc, mock, sessionRepo, sent := setupUsecaseWithMail(t)
+	session, _ := sessionRepo.AddSession(7, 1, false, false, entityAuth.SessionClient{})
+	userRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "username", "email", "password", "description", "balance", "role", "email_verified"}).
+			AddRow(7, "u", "old@e.ru", []byte("p"), "", "0", 1, false)
+	}
+
+	mock.ExpectQuery("WHERE id = \\$1").WithArgs(7).WillReturnRows(userRows())
+	if err := uc.RequestEmailChange(context.Background(), 7, "OLD@e.ru", "r1"); !errors.Is(err, entityAuth.ErrEmailUnchanged) {
+		t.Errorf("expected unchanged email, got %v", err)
+	}
+
+	mock.ExpectQuery("WHERE id = \\$1").WithArgs(7).WillReturnRows(userRows())
+	mock.ExpectQuery("WHERE email = \\$1 OR username = \\$2").WithArgs("new@e.ru", "").WillReturnError(sql.ErrNoRows)
+	if err := uc.RequestEmailChange(context.Background(), 7, "new@e.ru", "r2"); err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	if len(*sent) != 2 {
+		t.Fatalf("expected codes for both addresses, got %v", *sent)
+	}
+	oldCode, _ := strconv.Atoi((*sent)[0])
+	newCode, _ := strconv.Atoi((*sent)[1])
+
+	// неверный код нового адреса не гасит код старого
+	mock.ExpectQuery("WHERE id = \\$1").WithArgs(7).WillReturnRows(userRows())
+	if err := uc.ConfirmEmailChange(context.Background(), 7, oldCode, (newCode+1)%1_000_000, session.ID, "r3"); !errors.Is(err, entityAuth.ErrCodeInvalid) {
+		t.Errorf("expected invalid code, got %v", err)
+	}
+
+	mock.ExpectQuery("WHERE id = \\$1").WithArgs(7).WillReturnRows(userRows())
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE auth_user SET email = \\$1").WithArgs("new@e.ru", 7).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO auth_audit_log").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	if err := uc.ConfirmEmailChange(context.Background(), 7, oldCode, newCode, session.ID, "r4"); err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+	if len(*sent) != 3 {
+		t.Errorf("expected change notice to the old address, got %v", *sent)
+	}
+	if got, err := sessionRepo.GetSession(session.ID); err != nil || got.Unverified {
+		t.Errorf("expected verified session, got %+v, err %v", got, err)
+	}
+
+	mock.ExpectQuery("WHERE id = \\$1").WithArgs(7).WillReturnRows(userRows())
+	if err := uc.ConfirmEmailChange(context.Background(), 7, oldCode, newCode, session.ID, "r5"); !errors.Is(err, entityAuth.ErrCodeExpired) {
+		t.Errorf("expected used codes rejected, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestTwoFactor_Flow(t *testing.T) {
+	uc, mock, sessionRepo, _ := setupUsecaseWithMail(t)
+	session, _ := sessionRepo.AddSession(7, 1, true, false, entityAuth.SessionClient{})
+	pass := "correct-password"
+	storedHash := hashForTest(t, pass)
+	userRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "username", "email", "password", "description", "balance", "role", "email_verified"}).
+			AddRow(7, "u", "e@e.ru", storedHash, "", "0", 1, true)
+	}
+	totpRows := func(secret string, enabled bool) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"secret", "enabled"}).AddRow(secret, enabled)
+	}
+
+	mock.ExpectQuery("WHERE id = \\$1").WithArgs(7).WillReturnRows(userRows())
+	mock.ExpectExec("INSERT INTO auth_totp").WillReturnResult(sqlmock.NewResult(0, 1))
+	secret, uri, err := uc.EnrollTOTP(context.Background(), 7, "r1")
+	if err != nil || !strings.Contains(uri, "secret="+secret) {
+		t.Fatalf("enroll: %q, %v", uri, err)
+	}
+
+	code, _ := totp.Code(secret, time.Now())
+	mock.ExpectQuery("FROM auth_totp").WithArgs(7).WillReturnRows(totpRows(secret, false))
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE auth_totp SET enabled = TRUE").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM auth_recovery_code").WillReturnResult(sqlmock.NewResult(0, 0))
+	for i := 0; i < entityAuth.RecoveryCodeCount; i++ {
+		mock.ExpectExec("INSERT INTO auth_recovery_code").WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	mock.ExpectExec("INSERT INTO auth_audit_log").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	recovery, err := uc.ConfirmTOTP(context.Background(), 7, code, session.ID, "r2")
+	if err != nil || len(recovery) != entityAuth.RecoveryCodeCount {
+		t.Fatalf("confirm: %v, %v", recovery, err)
+	}
+	if got, _ := sessionRepo.GetSession(session.ID); !got.TwoFactor {
+		t.Error("expected current session marked two-factor")
+	}
+
+	// вход с включённым вторым фактором не открывает сессию сразу
+	mock.ExpectQuery("WHERE email = \\$1").WithArgs("e@e.ru").WillReturnRows(userRows())
+	mock.ExpectQuery("FROM auth_totp").WithArgs(7).WillReturnRows(totpRows(secret, true))
+	_, challenge, err := uc.Login(context.Background(), "e@e.ru", pass, 1, "10.0.0.1", "r3")
+	if err != nil || challenge == nil {
+		t.Fatalf("expected challenge, got %v, err %v", challenge, err)
+	}
+
+	// код, уже предъявленный при включении, второй раз не принимается
+	mock.ExpectQuery("FROM auth_totp").WithArgs(7).WillReturnRows(totpRows(secret, true))
+	if _, err := uc.VerifyLoginChallenge(context.Background(), challenge.ID, code, entityAuth.SessionClient{}, "r4"); !errors.Is(err, entityAuth.ErrTOTPInvalid) {
+		t.Errorf("expected replayed code rejected, got %v", err)
+	}
+
+	mock.ExpectQuery("FROM auth_totp").WithArgs(7).WillReturnRows(totpRows(secret, true))
+	mock.ExpectExec("UPDATE auth_recovery_code SET used_at").
+		WithArgs(7, hashRecoveryCode(strings.ToUpper(recovery[0]))).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	loggedIn, err := uc.VerifyLoginChallenge(context.Background(), challenge.ID, recovery[0], entityAuth.SessionClient{}, "r5")
+	if err != nil || !loggedIn.TwoFactor || loggedIn.UserID != 7 {
+		t.Fatalf("expected two-factor session, got %+v, err %v", loggedIn, err)
+	}
+	if _, err := uc.VerifyLoginChallenge(context.Background(), challenge.ID, recovery[1], entityAuth.SessionClient{}, "r6"); !errors.Is(err, entityAuth.ErrChallengeNotFound) {
+		t.Errorf("expected used challenge rejected, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestVerifyLoginChallenge_AttemptLimit(t *testing.T) {
+	uc, mock, _ := setupUsecase(t)
+	secret, _ := totp.GenerateSecret()
+	challenge := entityAuth.LoginChallenge{ID: "c", UserID: 7, Role: 1}
+	if err := uc.codeRepository.SaveChallenge(challenge, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= entityAuth.ChallengeMaxAttempts; i++ {
+		mock.ExpectQuery("FROM auth_totp").WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"secret", "enabled"}).AddRow(secret, true))
+		mock.ExpectExec("UPDATE auth_recovery_code").WillReturnResult(sqlmock.NewResult(0, 0))
+		_, err := uc.VerifyLoginChallenge(context.Background(), "c", "not-a-code", entityAuth.SessionClient{}, "r")
+		want := entityAuth.ErrTOTPInvalid
+		if i == entityAuth.ChallengeMaxAttempts {
+			want = entityAuth.ErrCodeAttempts
+		}
+		if !errors.Is(err, want) {
+			t.Fatalf("attempt %d: expected %v, got %v", i, want, err)
+		}
+	}
+	if _, err := uc.VerifyLoginChallenge(context.Background(), "c", "000000", entityAuth.SessionClient{}, "r"); !errors.Is(err, entityAuth.ErrChallengeNotFound) {
+		t.Errorf("expected challenge dropped, got %v", err)
+	}
+}
+
+func TestRevokeSessions(t *testing.T) {
+	uc, _, sessionRepo, _ := setupUsecaseWithMail(t)
+	current, _ := sessionRepo.AddSession(7, 1, true, false, entityAuth.SessionClient{IP: "10.0.0.1"})
+	other, _ := sessionRepo.AddSession(7, 1, true, false, entityAuth.SessionClient{IP: "10.0.0.2"})
+
+	sessions, err := uc.ListSessions(context.Background(), 7, "r1")
+	if err != nil || len(sessions) != 2 {
+		t.Fatalf("expected two sessions, got %v, %v", sessions, err)
+	}
+
+	if err := uc.RevokeSession(context.Background(), 7, "unknown", "r2"); !errors.Is(err, entityAuth.ErrSessionNotFound) {
+		t.Errorf("expected session not found, got %v", err)
+	}
+	if err := uc.RevokeSession(context.Background(), 7, entityAuth.SessionHandle(other.ID), "r3"); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if _, err := sessionRepo.GetSession(other.ID); !errors.Is(err, entityAuth.ErrSessionNotFound) {
+		t.Errorf("expected other session revoked, got %v", err)
+	}
+
+	if err := uc.RevokeAllSessions(context.Background(), 7, "r4"); err != nil {
+		t.Fatalf("revoke all: %v", err)
+	}
+	if _, err := sessionRepo.GetSession(current.ID); !errors.Is(err, entityAuth.ErrSessionNotFound) {
+		t.Errorf("expected current session revoked, got %v", err)
+	}
+}
+
+// fakeOAuthProvider выдаёт identity на код, запомненный за PKCE challenge
+type fakeOAuthProvider struct {
+	identity   entityAuth.ExternalIdentity
+	challenges map[string]string
+}
+
+func (p *fakeOAuthProvider) Name() string { return "fake" }
+
+func (p *fakeOAuthProvider) AuthCodeURL(state, codeChallenge string) string {
+	p.challenges[state] = codeChallenge
+	return "https://provider/authorize?state=" + state
+}
+
+func (p *fakeOAuthProvider) Exchange(code, verifier, state string, extra url.Values) (string, error) {
+	if code != "code" || pkceChallenge(verifier) != p.challenges[state] {
+		return "", entityAuth.ErrOAuthFailed
+	}
+	return "token", nil
+}
+
+func (p *fakeOAuthProvider) Identity(token string) (entityAuth.ExternalIdentity, error) {
+	return p.identity, nil
+}
+
+func TestOAuthLogin_Flow(t *testing.T) {
+	uc, mock, _, sent := setupUsecaseWithMail(t)
+	provider := &fakeOAuthProvider{
+		identity:   entityAuth.ExternalIdentity{Subject: "42", Email: "new@e.ru", Username: "Иван.petrov"},
+		challenges: map[string]string{},
+	}
+	uc.oauthProviders = map[string]repoAuth.OAuthProviderInterface{"fake": provider}
+	ctx := context.Background()
+	userRows := func(id int, email string) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "username", "email", "password", "description", "balance", "role", "email_verified"}).
+			AddRow(id, "user", email, "p", "", "0", 2, true)
+	}
+	start := func() string {
+		authURL, err := uc.StartOAuthLogin(ctx, "fake", 2, "r")
+		if err != nil {
+			t.Fatalf("start: %v", err)
+		}
+		u, _ := url.Parse(authURL)
+		return u.Query().Get("state")
+	}
+
+	if _, err := uc.StartOAuthLogin(ctx, "unknown", 1, "r"); err != entityAuth.ErrOAuthProviderUnknown {
+		t.Errorf("expected unknown provider, got %v", err)
+	}
+
+	// первый вход создаёт пользователя с ролью из начала входа
+	state := start()
+	mock.ExpectQuery("FROM auth_identity").WithArgs("fake", "42").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("WHERE email = \\$1").WithArgs("new@e.ru").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("WHERE username = \\$1").WithArgs(".petrov").WillReturnError(sql.ErrNoRows)
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO auth_user").
+		WithArgs(".petrov", "new@e.ru", sqlmock.AnyArg(), "", sqlmock.AnyArg(), 2, false).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectExec("INSERT INTO auth_identity").WithArgs(7, "fake", "42", "new@e.ru").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery("FROM auth_totp").WithArgs(7).WillReturnError(sql.ErrNoRows)
+	user, challenge, err := uc.CompleteOAuthLogin(ctx, "fake", "code", state, nil, "r1")
+	if err != nil || challenge != nil || user.ID != 7 || user.Role != 2 || user.EmailVerified {
+		t.Fatalf("expected new user, got %+v, %v, %v", user, challenge, err)
+	}
+	if len(*sent) != 1 {
+		t.Errorf("expected verification code for unconfirmed email, got %v", *sent)
+	}
+
+	// ответ провайдера нельзя принять повторно
+	if _, _, err := uc.CompleteOAuthLogin(ctx, "fake", "code", state, nil, "r2"); err != entityAuth.ErrOAuthStateInvalid {
+		t.Errorf("expected replayed state rejected, got %v", err)
+	}
+
+	// state другого провайдера
+	state = start()
+	uc.oauthProviders["other"] = provider
+	if _, _, err := uc.CompleteOAuthLogin(ctx, "other", "code", state, nil, "r3"); err != entityAuth.ErrOAuthStateInvalid {
+		t.Errorf("expected foreign state rejected, got %v", err)
+	}
+
+	// неверный код
+	state = start()
+	if _, _, err := uc.CompleteOAuthLogin(ctx, "fake", "bad", state, nil, "r4"); err != entityAuth.ErrOAuthFailed {
+		t.Errorf("expected provider failure, got %v", err)
+	}
+
+	// повторный вход находит привязанного пользователя
+	state = start()
+	mock.ExpectQuery("FROM auth_identity").WithArgs("fake", "42").WillReturnRows(userRows(7, "new@e.ru"))
+	mock.ExpectQuery("FROM auth_totp").WithArgs(7).WillReturnError(sql.ErrNoRows)
+	if user, _, err := uc.CompleteOAuthLogin(ctx, "fake", "code", state, nil, "r5"); err != nil || user.ID != 7 {
+		t.Errorf("expected linked user, got %+v, %v", user, err)
+	}
+
+	// почта занята пользователем с паролем — автоматически не связываем
+	provider.identity = entityAuth.ExternalIdentity{Subject: "43", Email: "old@e.ru"}
+	state = start()
+	mock.ExpectQuery("FROM auth_identity").WithArgs("fake", "43").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("WHERE email = \\$1").WithArgs("old@e.ru").WillReturnRows(userRows(5, "old@e.ru"))
+	if _, _, err := uc.CompleteOAuthLogin(ctx, "fake", "code", state, nil, "r6"); err != entityAuth.ErrIdentityEmailTaken {
+		t.Errorf("expected email taken, got %v", err)
+	}
+
+	// привязка к вошедшему пользователю
+	authURL, err := uc.StartOAuthLink(ctx, 5, "fake", "r7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(authURL)
+	state = u.Query().Get("state")
+	if err := uc.CompleteOAuthLink(ctx, 6, "fake", "code", state, nil, "r8"); err != entityAuth.ErrOAuthStateInvalid {
+		t.Errorf("expected link by another user rejected, got %v", err)
+	}
+
+	authURL, _ = uc.StartOAuthLink(ctx, 5, "fake", "r9")
+	u, _ = url.Parse(authURL)
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO auth_identity").WithArgs(5, "fake", "43", "old@e.ru").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO auth_audit_log").WithArgs(5, entityAuth.AuditIdentityLinked, sqlmock.AnyArg(), "r10").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	if err := uc.CompleteOAuthLink(ctx, 5, "fake", "code", u.Query().Get("state"), nil, "r10"); err != nil {
+		t.Errorf("link: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestAPIKeys_Flow(t *testing.T) {
+	uc, mock, _, _, mr := setupUsecaseWithRedis(t)
+	ctx := context.Background()
+	authenticator, err := authenticate.NewAuthenticator(mr.Addr(), "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := uc.CreateAPIKey(ctx, 7, 1, "ci", []string{"banners:delete"}, nil, "r1"); err != entityAuth.ErrAPIKeyScope {
+		t.Errorf("expected unknown scope rejected, got %v", err)
+	}
+	past := time.Now().Add(-time.Hour)
+	if _, _, err := uc.CreateAPIKey(ctx, 7, 1, "ci", []string{authenticate.ScopeBannersRead}, &past, "r2"); err != entityAuth.ErrAPIKeyExpiry {
+		t.Errorf("expected past expiry rejected, got %v", err)
+	}
+
+	// предел действующих ключей
+	mock.ExpectBegin()
+	mock.ExpectExec("FOR UPDATE").WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT count").WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(entityAuth.MaxAPIKeysPerUser))
+	mock.ExpectRollback()
+	if _, _, err := uc.CreateAPIKey(ctx, 7, 1, "ci", []string{authenticate.ScopeBannersRead}, nil, "r3"); err != entityAuth.ErrAPIKeyLimit {
+		t.Errorf("expected key limit, got %v", err)
+	}
+
+	expires := time.Now().Add(time.Hour)
+	mock.ExpectBegin()
+	mock.ExpectExec("FOR UPDATE").WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT count").WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery("INSERT INTO auth_api_key").
+		WithArgs(7, "ci", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, time.Now()))
+	mock.ExpectExec("INSERT INTO auth_audit_log").WithArgs(7, entityAuth.AuditAPIKeyCreated, sqlmock.AnyArg(), "r4").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	key, secret, err := uc.CreateAPIKey(ctx, 7, 1, " ci ", []string{authenticate.ScopeBannersRead, authenticate.ScopeMetricsRead, authenticate.ScopeBannersRead}, &expires, "r4")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if !strings.HasPrefix(secret, authenticate.APIKeyPrefix) || key.Prefix != secret[:entityAuth.APIKeyPrefixLength] || len(key.Scopes) != 2 {
+		t.Errorf("unexpected key %+v (%s)", key, secret)
+	}
+
+	data, err := authenticator.AuthenticateAPIKey(secret)
+	if err != nil || data.UserID != 7 || data.KeyID != 3 || !data.HasScope(authenticate.ScopeMetricsRead) {
+		t.Fatalf("expected key published, got %+v, %v", data, err)
+	}
+	if ttl := mr.TTL(authenticate.APIKeyRedisKey(key.KeyHash)); ttl <= 0 || ttl > time.Hour {
+		t.Errorf("expected key to expire with its expiry, got %v", ttl)
+	}
+
+	columns := []string{"id", "user_id", "role", "name", "prefix", "key_hash", "scopes", "expires_at", "created_at"}
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE auth_api_key").WithArgs(3, 7).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(3, 7, 1, "ci", key.Prefix, key.KeyHash, "{banners:read,metrics:read}", expires, time.Now()))
+	mock.ExpectExec("INSERT INTO auth_audit_log").WithArgs(7, entityAuth.AuditAPIKeyRevoked, sqlmock.AnyArg(), "r5").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	if err := uc.RevokeAPIKey(ctx, 7, 3, "r5"); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if _, err := authenticator.AuthenticateAPIKey(secret); err == nil {
+		t.Errorf("expected revoked key rejected")
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE auth_api_key").WithArgs(3, 8).WillReturnRows(sqlmock.NewRows(columns))
+	mock.ExpectRollback()
+	if err := uc.RevokeAPIKey(ctx, 8, 3, "r6"); err != entityAuth.ErrAPIKeyNotFound {
+		t.Errorf("expected foreign key not found, got %v", err)
+	}
+
+	// восстановление ключей в Redis
+	mock.ExpectQuery("FROM auth_api_key").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(4, 9, 2, "sync", "rtk_abcdefgh", authenticate.APIKeyHash("rtk_synced"), "{slots:read}", nil, time.Now()))
+	if err := uc.SyncAPIKeys(ctx, "r7"); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := authenticator.AuthenticateAPIKey("rtk_synced"); err != nil || data.UserID != 9 || data.Role != 2 || !data.HasScope(authenticate.ScopeSlotsRead) {
+		t.Errorf("expected synced key, got %+v, %v", data, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestOrganizations_Flow(t *testing.T) {
+	uc, mock, sessionRepo, sent := setupUsecaseWithMail(t)
+	ctx := context.Background()
+	membershipRows := func(memberRole string) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"account_id", "name", "role", "created_at", "role"}).
+			AddRow(50, "Acme", 1, time.Now(), memberRole)
+	}
+	userRows := func(id int, email string) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "username", "email", "password", "description", "balance", "role", "email_verified"}).
+			AddRow(id, "u", email, "p", "", "0", 2, true)
+	}
+
+	// участник переключает сессию в организацию и действует с её ролью аккаунта
+	session, _ := sessionRepo.AddSession(8, 2, true, false, entityAuth.SessionClient{})
+	mock.ExpectQuery("FROM organization_member m").WithArgs(50, 8).WillReturnRows(membershipRows(authenticate.OrgRoleAnalyst))
+	if _, err := uc.SwitchOrganization(ctx, 8, 50, session.ID, "r1"); err != nil {
+		t.Fatalf("switch: %v", err)
+	}
+	switched, _ := sessionRepo.GetSession(session.ID)
+	if switched.OrgID != 50 || switched.OrgRole != authenticate.OrgRoleAnalyst || switched.Role != 1 {
+		t.Fatalf("unexpected session after switch: %+v", switched)
+	}
+
+	// приглашать может только владелец
+	mock.ExpectQuery("FROM organization_member m").WithArgs(50, 8).WillReturnRows(membershipRows(authenticate.OrgRoleAnalyst))
+	if err := uc.InviteToOrganization(ctx, 8, 50, "new@e.ru", authenticate.OrgRoleManager, "r2"); !errors.Is(err, entityAuth.ErrOrgForbidden) {
+		t.Errorf("expected forbidden, got %v", err)
+	}
+
+	// в базе хранится хеш токена, сам токен уходит письмом
+	mock.ExpectQuery("FROM organization_member m").WithArgs(50, 7).WillReturnRows(membershipRows(authenticate.OrgRoleOwner))
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO organization_invite").
+		WithArgs(50, "new@e.ru", authenticate.OrgRoleManager, sqlmock.AnyArg(), 7, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, time.Now()))
+	mock.ExpectCommit()
+	if err := uc.InviteToOrganization(ctx, 7, 50, "new@e.ru", authenticate.OrgRoleManager, "r3"); err != nil {
+		t.Fatalf("invite: %v", err)
+	}
+	if len(*sent) != 1 || (*sent)[0] == "" {
+		t.Fatalf("expected invite token sent, got %v", *sent)
+	}
+	token := (*sent)[0]
+
+	// приглашение принимает только владелец адреса
+	mock.ExpectQuery("WHERE id = \\$1").WithArgs(9).WillReturnRows(userRows(9, "other@e.ru"))
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM organization_invite").WithArgs(hashResetToken(token)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "org_id", "email", "role"}).AddRow(3, 50, "new@e.ru", authenticate.OrgRoleManager))
+	mock.ExpectRollback()
+	if _, err := uc.AcceptOrgInvite(ctx, 9, token, "r4"); !errors.Is(err, entityAuth.ErrOrgInviteEmail) {
+		t.Errorf("expected invite email mismatch, got %v", err)
+	}
+
+	// смена роли сразу видна в открытой сессии
+	mock.ExpectQuery("FROM organization_member m").WithArgs(50, 7).WillReturnRows(membershipRows(authenticate.OrgRoleOwner))
+	mock.ExpectBegin()
+	mock.ExpectExec("FROM organization WHERE account_id = \\$1 FOR UPDATE").WithArgs(50).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT role FROM organization_member").WithArgs(50, 8).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow(authenticate.OrgRoleAnalyst))
+	mock.ExpectExec("UPDATE organization_member SET role").WithArgs(50, 8, authenticate.OrgRoleManager).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO auth_audit_log").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	if err := uc.ChangeOrgMemberRole(ctx, 7, 50, 8, authenticate.OrgRoleManager, "r5"); err != nil {
+		t.Fatalf("change role: %v", err)
+	}
+	changed, _ := sessionRepo.GetSession(session.ID)
+	if changed.OrgID != 50 || changed.OrgRole != authenticate.OrgRoleManager {
+		t.Fatalf("expected manager session, got %+v", changed)
+	}
+
+	// после выхода из организации сессия возвращается в личный аккаунт
+	mock.ExpectQuery("FROM organization_member m").WithArgs(50, 8).WillReturnRows(membershipRows(authenticate.OrgRoleManager))
+	mock.ExpectBegin()
+	mock.ExpectExec("FROM organization WHERE account_id = \\$1 FOR UPDATE").WithArgs(50).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT role FROM organization_member").WithArgs(50, 8).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow(authenticate.OrgRoleManager))
+	mock.ExpectExec("DELETE FROM organization_member").WithArgs(50, 8).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO auth_audit_log").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery("WHERE id = \\$1").WithArgs(8).WillReturnRows(userRows(8, "m@e.ru"))
+	if err := uc.RemoveOrgMember(ctx, 8, 50, 8, "r6"); err != nil {
+		t.Fatalf("leave: %v", err)
+	}
+	personal, _ := sessionRepo.GetSession(session.ID)
+	if personal.OrgID != 0 || personal.OrgRole != "" || personal.Role != 2 {
+		t.Fatalf("expected personal session, got %+v", personal)
+	}
+
+	// последний владелец не может уйти
+	mock.ExpectQuery("FROM organization_member m").WithArgs(50, 7).WillReturnRows(membershipRows(authenticate.OrgRoleOwner))
+	mock.ExpectBegin()
+	mock.ExpectExec("FROM organization WHERE account_id = \\$1 FOR UPDATE").WithArgs(50).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT role FROM organization_member").WithArgs(50, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow(authenticate.OrgRoleOwner))
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM organization_member").WithArgs(50, authenticate.OrgRoleOwner).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+	if err := uc.RemoveOrgMember(ctx, 7, 50, 7, "r7"); !errors.Is(err, entityAuth.ErrOrgLastOwner) {
+		t.Errorf("expected last owner error, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
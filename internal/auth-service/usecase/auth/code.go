@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+
+	entityAuth "retarget/internal/auth-service/entity/auth"
+	"retarget/pkg/utils/optiLog"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// -----------------------------
+// Коды подтверждения
+// -----------------------------
+
+var codeSpace = big.NewInt(1_000_000) // 10^CodeLength
+
+func generateCode() (int, error) {
+	n, err := rand.Int(rand.Reader, codeSpace)
+	if err != nil {
+		return 0, err
+	}
+	return int(n.Int64()), nil
+}
+
+// formatCode дополняет код нулями слева: в письме всегда CodeLength цифр
+func formatCode(code int) string {
+	return fmt.Sprintf("%0*d", entityAuth.CodeLength, code)
+}
+
+func hashCode(code int, salt string) string {
+	sum := sha256.Sum256([]byte(salt + ":" + formatCode(code)))
+	return hex.EncodeToString(sum[:])
+}
+
+// createCode выпускает код; payload сохраняется рядом с хэшем и возвращается при проверке
+func (a *AuthUsecase) createCode(purpose string, userID int, payload string) (int, error) {
+	code, err := generateCode()
+	if err != nil {
+		return 0, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return 0, err
+	}
+	saltHex := hex.EncodeToString(salt)
+
+	stored := entityAuth.StoredCode{Hash: hashCode(code, saltHex), Salt: saltHex, Payload: payload}
+	if err := a.codeRepository.SaveCode(purpose, userID, stored); err != nil {
+		return 0, err
+	}
+	return code, nil
+}
+
+// verifyCode сверяет код; consume гасит его при совпадении тем же шагом, так что
+// один код не пройдёт в двух параллельных запросах. После CodeMaxAttempts
+// неверных вводов код удаляется, и нужно запросить новый
+func (a *AuthUsecase) verifyCode(purpose string, userID int, code int, consume bool) (entityAuth.StoredCode, error) {
+	return a.codeRepository.VerifyCode(purpose, userID, func(salt string) string {
+		return hashCode(code, salt)
+	}, consume)
+}
+
+// checkCode сверяет код и гасит его при совпадении
+func (a *AuthUsecase) checkCode(purpose string, userID int, code int) error {
+	_, err := a.verifyCode(purpose, userID, code, true)
+	return err
+}
+
+// CreateCode выпускает код подтверждения почты; в Redis остаётся только его хэш
+func (a *AuthUsecase) CreateCode(userId int) (int, error) {
+	return a.createCode(entityAuth.CodeRegister, userId, "")
+}
+
+func (a *AuthUsecase) CheckCode(code int, userId int) error {
+	return a.checkCode(entityAuth.CodeRegister, userId, code)
+}
+
+func (a *AuthUsecase) sendVerificationCode(user *entityAuth.User, requestID string) error {
+	code, err := a.CreateCode(user.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := a.mailRepository.SendRegisterCode(user.Email, formatCode(code)); err != nil {
+		a.asyncLogger.Log(zapcore.WarnLevel, requestID, "Mail-service failed to send code",
+			optiLog.MakeLogFields(requestID, 0, map[string]interface{}{
+				"userID": user.ID,
+				"error":  err.Error(),
+			}))
+		// неотправленный код не должен блокировать повторный запрос
+		//nolint:errcheck
+		a.codeRepository.DelCode(entityAuth.CodeRegister, user.ID)
+		return entityAuth.ErrEmailNotDelivered
+	}
+	return nil
+}
+
+// SendVerificationCode повторно отправляет код подтверждения почты
+func (a *AuthUsecase) SendVerificationCode(ctx context.Context, userID int, requestID string) error {
+	user, err := a.GetUser(ctx, userID, requestID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified {
+		return entityAuth.ErrEmailVerified
+	}
+	return a.sendVerificationCode(user, requestID)
+}
+
+// ConfirmEmail подтверждает почту кодом из письма и снимает ограничение с текущей сессии.
+// Остальные сессии пользователя получат подтверждение при следующем входе
+func (a *AuthUsecase) ConfirmEmail(ctx context.Context, userID int, code int, sessionID string, requestID string) error {
+	startTime := time.Now()
+
+	user, err := a.GetUser(ctx, userID, requestID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified {
+		return entityAuth.ErrEmailVerified
+	}
+
+	if err := a.CheckCode(code, userID); err != nil {
+		a.asyncLogger.Log(zapcore.WarnLevel, requestID, "Verification code rejected",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"userID": userID,
+				"error":  err.Error(),
+			}))
+		return err
+	}
+
+	if err := a.authRepository.SetEmailVerified(userID, requestID); err != nil {
+		return err
+	}
+
+	if err := a.sessionRepository.MarkSessionVerified(sessionID); err != nil {
+		a.asyncLogger.Log(zapcore.WarnLevel, requestID, "Failed to mark session verified",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"userID": userID,
+				"error":  err.Error(),
+			}))
+	}
+
+	a.asyncLogger.Log(zapcore.DebugLevel, requestID, "Email verified",
+		optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+			"userID": userID,
+		}))
+	return nil
+}
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	entityAuth "retarget/internal/auth-service/entity/auth"
+	"retarget/pkg/utils/optiLog"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// -----------------------------
+// Смена почты
+// -----------------------------
+
+// RequestEmailChange отправляет коды подтверждения на текущий и на новый адрес.
+// Новый адрес хранится вместе с кодом и применяется только после ввода обоих кодов
+func (a *AuthUsecase) RequestEmailChange(ctx context.Context, userID int, newEmail string, requestID string) error {
+	startTime := time.Now()
+
+	user, err := a.GetUser(ctx, userID, requestID)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(user.Email, newEmail) {
+		return entityAuth.ErrEmailUnchanged
+	}
+	existing, err := a.authRepository.CheckEmailOrUsernameExists(newEmail, "", requestID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return entityAuth.ErrEmailTaken
+	}
+
+	oldCode, err := a.createCode(entityAuth.CodeEditEmailOld, userID, "")
+	if err != nil {
+		return err
+	}
+	newCode, err := a.createCode(entityAuth.CodeEditEmailNew, userID, newEmail)
+	if err != nil {
+		//nolint:errcheck
+		a.codeRepository.DelCode(entityAuth.CodeEditEmailOld, userID)
+		return err
+	}
+
+	err = a.mailRepository.SendEditEmailCode(user.Email, formatCode(oldCode))
+	if err == nil {
+		err = a.mailRepository.SendEditEmailCode(newEmail, formatCode(newCode))
+	}
+	if err != nil {
+		a.asyncLogger.Log(zapcore.WarnLevel, requestID, "Mail-service failed to send code",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"userID": userID,
+				"error":  err.Error(),
+			}))
+		//nolint:errcheck
+		a.codeRepository.DelCode(entityAuth.CodeEditEmailOld, userID)
+		//nolint:errcheck
+		a.codeRepository.DelCode(entityAuth.CodeEditEmailNew, userID)
+		return entityAuth.ErrEmailNotDelivered
+	}
+	return nil
+}
+
+// ConfirmEmailChange применяет новую почту, когда подтверждены оба адреса.
+// Неверный код одного адреса не гасит код другого
+func (a *AuthUsecase) ConfirmEmailChange(ctx context.Context, userID int, oldCode int, newCode int, sessionID string, requestID string) error {
+	startTime := time.Now()
+
+	user, err := a.GetUser(ctx, userID, requestID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := a.verifyCode(entityAuth.CodeEditEmailOld, userID, oldCode, false); err != nil {
+		return err
+	}
+	// новый адрес лежит в коде нового адреса: гасим его при проверке, чтобы
+	// параллельный запрос с теми же кодами не применил смену второй раз
+	pending, err := a.verifyCode(entityAuth.CodeEditEmailNew, userID, newCode, true)
+	if err != nil {
+		return err
+	}
+	newEmail := pending.Payload
+	//nolint:errcheck
+	a.codeRepository.DelCode(entityAuth.CodeEditEmailOld, userID)
+
+	if err := a.authRepository.UpdateEmail(userID, user.Email, newEmail, requestID); err != nil {
+		return err
+	}
+
+	// новый адрес подтверждён кодом — снимаем ограничение с текущей сессии
+	if err := a.sessionRepository.MarkSessionVerified(sessionID); err != nil {
+		a.asyncLogger.Log(zapcore.WarnLevel, requestID, "Failed to mark session verified",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"userID": userID,
+				"error":  err.Error(),
+			}))
+	}
+	if err := a.mailRepository.SendEmailChanged(user.Email, newEmail); err != nil {
+		a.asyncLogger.Log(zapcore.WarnLevel, requestID, "Email change notice was not sent",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"userID": userID,
+				"error":  err.Error(),
+			}))
+	}
+
+	a.asyncLogger.Log(zapcore.InfoLevel, requestID, "Email changed",
+		optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+			"userID": userID,
+		}))
+	return nil
+}
@@ -0,0 +1,268 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+
+	entityAuth "retarget/internal/auth-service/entity/auth"
+	"retarget/pkg/utils/optiLog"
+
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/inf.v0"
+)
+
+// -----------------------------
+// Вход через внешних провайдеров (OAuth 2.0 / OpenID Connect)
+// -----------------------------
+
+// usernameMaxBase — длина имени из данных провайдера, чтобы с суффиксом
+// оно укладывалось в ограничение auth_user
+const usernameMaxBase = 40
+
+func randomURLString(n int) (string, error) {
+	raw := make([]byte, n)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// pkceChallenge — code_challenge метода S256 (RFC 7636)
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func (a *AuthUsecase) startOAuth(providerName string, st entityAuth.OAuthState) (string, error) {
+	provider, ok := a.oauthProviders[providerName]
+	if !ok {
+		return "", entityAuth.ErrOAuthProviderUnknown
+	}
+
+	state, err := randomURLString(entityAuth.OAuthStateBytes)
+	if err != nil {
+		return "", err
+	}
+	st.Provider = providerName
+	if st.Verifier, err = randomURLString(entityAuth.OAuthVerifierBytes); err != nil {
+		return "", err
+	}
+	if err := a.codeRepository.SaveOAuthState(state, st, entityAuth.OAuthStateTTL); err != nil {
+		return "", err
+	}
+	return provider.AuthCodeURL(state, pkceChallenge(st.Verifier)), nil
+}
+
+// StartOAuthLogin возвращает адрес входа у провайдера. role — роль, с которой
+// будет создан пользователь, если он пришёл впервые
+func (a *AuthUsecase) StartOAuthLogin(ctx context.Context, providerName string, role int, requestID string) (string, error) {
+	return a.startOAuth(providerName, entityAuth.OAuthState{Mode: entityAuth.OAuthModeLogin, Role: role})
+}
+
+// StartOAuthLink возвращает адрес провайдера для привязки аккаунта к вошедшему пользователю
+func (a *AuthUsecase) StartOAuthLink(ctx context.Context, userID int, providerName string, requestID string) (string, error) {
+	return a.startOAuth(providerName, entityAuth.OAuthState{Mode: entityAuth.OAuthModeLink, UserID: userID})
+}
+
+// completeOAuth гасит state и получает у провайдера пользователя, вернувшегося с code
+func (a *AuthUsecase) completeOAuth(providerName, code, state, mode string, extra url.Values, requestID string) (entityAuth.OAuthState, entityAuth.ExternalIdentity, error) {
+	startTime := time.Now()
+
+	provider, ok := a.oauthProviders[providerName]
+	if !ok {
+		return entityAuth.OAuthState{}, entityAuth.ExternalIdentity{}, entityAuth.ErrOAuthProviderUnknown
+	}
+	st, err := a.codeRepository.TakeOAuthState(state)
+	if err != nil {
+		return st, entityAuth.ExternalIdentity{}, err
+	}
+	// state другого провайдера или режима — подмена ответа
+	if st.Provider != providerName || st.Mode != mode {
+		return st, entityAuth.ExternalIdentity{}, entityAuth.ErrOAuthStateInvalid
+	}
+
+	var identity entityAuth.ExternalIdentity
+	token, err := provider.Exchange(code, st.Verifier, state, extra)
+	if err == nil {
+		identity, err = provider.Identity(token)
+	}
+	if err != nil {
+		a.asyncLogger.Log(zapcore.WarnLevel, requestID, "OAuth provider rejected login",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"provider": providerName,
+				"error":    err.Error(),
+			}))
+		return st, identity, entityAuth.ErrOAuthFailed
+	}
+	identity.Provider = providerName
+	identity.Email = strings.TrimSpace(identity.Email)
+	return st, identity, nil
+}
+
+// CompleteOAuthLogin завершает вход через провайдера. Привязанный аккаунт входит
+// в своего пользователя, новый — получает пользователя с ролью из StartOAuthLogin.
+// Почту, уже занятую паролем, автоматически не связываем: иначе аккаунт у
+// провайдера с чужой почтой получил бы доступ к нашему пользователю
+func (a *AuthUsecase) CompleteOAuthLogin(ctx context.Context, providerName string, code string, state string, extra url.Values, requestID string) (*entityAuth.User, *entityAuth.LoginChallenge, error) {
+	startTime := time.Now()
+
+	st, identity, err := a.completeOAuth(providerName, code, state, entityAuth.OAuthModeLogin, extra, requestID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := a.authRepository.GetUserByIdentity(identity.Provider, identity.Subject, requestID)
+	created := false
+	if errors.Is(err, sql.ErrNoRows) {
+		user, err = a.createOAuthUser(identity, st.Role, requestID)
+		created = err == nil
+	}
+	if err != nil {
+		a.asyncLogger.Log(zapcore.WarnLevel, requestID, "OAuth login failed",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"provider": identity.Provider,
+				"error":    err.Error(),
+			}))
+		return nil, nil, err
+	}
+
+	// почту, не подтверждённую провайдером, подтверждают кодом, как при регистрации
+	if created && !user.EmailVerified {
+		if err := a.sendVerificationCode(user, requestID); err != nil {
+			a.asyncLogger.Log(zapcore.WarnLevel, requestID, "Verification code was not sent",
+				optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+					"userID": user.ID,
+					"error":  err.Error(),
+				}))
+		}
+	}
+
+	challenge, err := a.loginChallenge(user, requestID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	a.asyncLogger.Log(zapcore.InfoLevel, requestID, "OAuth login successful",
+		optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+			"userID":   user.ID,
+			"provider": identity.Provider,
+			"created":  created,
+			"2fa":      challenge != nil,
+		}))
+	return user, challenge, nil
+}
+
+// CompleteOAuthLink привязывает аккаунт провайдера к пользователю, начавшему привязку
+func (a *AuthUsecase) CompleteOAuthLink(ctx context.Context, userID int, providerName string, code string, state string, extra url.Values, requestID string) error {
+	startTime := time.Now()
+
+	st, identity, err := a.completeOAuth(providerName, code, state, entityAuth.OAuthModeLink, extra, requestID)
+	if err != nil {
+		return err
+	}
+	if st.UserID != userID {
+		return entityAuth.ErrOAuthStateInvalid
+	}
+
+	if err := a.authRepository.LinkIdentity(userID, identity, requestID); err != nil {
+		return err
+	}
+
+	a.asyncLogger.Log(zapcore.InfoLevel, requestID, "External identity linked",
+		optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+			"userID":   userID,
+			"provider": identity.Provider,
+		}))
+	return nil
+}
+
+func (a *AuthUsecase) createOAuthUser(identity entityAuth.ExternalIdentity, role int, requestID string) (*entityAuth.User, error) {
+	if identity.Email == "" {
+		return nil, entityAuth.ErrIdentityNoEmail
+	}
+	if _, err := a.authRepository.GetUserByEmail(identity.Email, requestID); err == nil {
+		return nil, entityAuth.ErrIdentityEmailTaken
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	username, err := a.oauthUsername(identity, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	// войти паролем такой пользователь не может, пока не восстановит его по почте
+	secret, err := randomURLString(entityAuth.OAuthVerifierBytes)
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := hashPassword(secret, a.hashCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &entityAuth.User{
+		Username:      username,
+		Email:         identity.Email,
+		Password:      hashedPassword,
+		Balance:       entityAuth.Decimal{Dec: inf.NewDec(0, 0)},
+		Role:          role,
+		EmailVerified: identity.EmailVerified,
+	}
+	if err := a.authRepository.CreateUserWithIdentity(user, identity, requestID); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// oauthUsername подбирает свободное имя по логину у провайдера или началу почты
+func (a *AuthUsecase) oauthUsername(identity entityAuth.ExternalIdentity, requestID string) (string, error) {
+	base := sanitizeUsername(identity.Username)
+	if base == "" {
+		base = sanitizeUsername(strings.SplitN(identity.Email, "@", 2)[0])
+	}
+	if base == "" {
+		base = identity.Provider
+	}
+
+	candidate := base
+	for i := 0; i < 5; i++ {
+		if len(candidate) >= 5 {
+			_, err := a.authRepository.GetUserByUsername(candidate, requestID)
+			if errors.Is(err, sql.ErrNoRows) {
+				return candidate, nil
+			}
+			if err != nil {
+				return "", err
+			}
+		}
+		suffix := make([]byte, 3)
+		if _, err := rand.Read(suffix); err != nil {
+			return "", err
+		}
+		candidate = base + "_" + hex.EncodeToString(suffix)
+	}
+	return "", errors.New("не удалось подобрать имя пользователя")
+}
+
+func sanitizeUsername(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '-') {
+			b.WriteRune(r)
+		}
+		if b.Len() >= usernameMaxBase {
+			break
+		}
+	}
+	return b.String()
+}
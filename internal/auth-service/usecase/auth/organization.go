@@ -0,0 +1,218 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	entityAuth "retarget/internal/auth-service/entity/auth"
+	authenticate "retarget/pkg/middleware/auth"
+	"retarget/pkg/utils/optiLog"
+
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/inf.v0"
+)
+
+// -----------------------------
+// Организации
+// -----------------------------
+
+// newOrgAccount — аккаунт auth_user для организации. Войти в него нельзя:
+// почта служебная, а вместо хеша пароля случайные байты
+func newOrgAccount(role int) (*entityAuth.User, error) {
+	raw := make([]byte, 8)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	password := make([]byte, 32)
+	if _, err := rand.Read(password); err != nil {
+		return nil, err
+	}
+	handle := "org-" + hex.EncodeToString(raw)
+	return &entityAuth.User{
+		Username: handle,
+		Email:    handle + "@" + entityAuth.OrgAccountEmailDomain,
+		Password: password,
+		Balance:  entityAuth.Decimal{Dec: inf.NewDec(0, 0)},
+		Role:     role,
+	}, nil
+}
+
+// CreateOrganization заводит организацию с ролью аккаунта создателя и делает
+// его владельцем
+func (a *AuthUsecase) CreateOrganization(ctx context.Context, userID int, name string, requestID string) (*entityAuth.Membership, error) {
+	startTime := time.Now()
+
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > entityAuth.MaxOrgNameLen {
+		return nil, entityAuth.ErrOrgName
+	}
+	user, err := a.GetUser(ctx, userID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	// участники действуют с ролью аккаунта организации, служебные роли ей не передаются
+	if user.Role != authenticate.RoleAdvertiser && user.Role != authenticate.RolePlatform {
+		return nil, entityAuth.ErrOrgForbidden
+	}
+	account, err := newOrgAccount(user.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	org := entityAuth.Organization{Name: name}
+	if err := a.authRepository.CreateOrganization(&org, account, userID, requestID); err != nil {
+		return nil, err
+	}
+
+	a.asyncLogger.Log(zapcore.InfoLevel, requestID, "Organization created",
+		optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+			"userID": userID,
+			"orgID":  org.ID,
+		}))
+	return &entityAuth.Membership{Organization: org, MemberRole: authenticate.OrgRoleOwner}, nil
+}
+
+func (a *AuthUsecase) ListOrganizations(ctx context.Context, userID int, requestID string) ([]entityAuth.Membership, error) {
+	return a.authRepository.ListMemberships(userID, requestID)
+}
+
+// requireOrgOwner возвращает членство userID в orgID, если он владелец
+func (a *AuthUsecase) requireOrgOwner(orgID, userID int, requestID string) (entityAuth.Membership, error) {
+	membership, err := a.authRepository.GetMembership(orgID, userID, requestID)
+	if err != nil {
+		return membership, err
+	}
+	if membership.MemberRole != authenticate.OrgRoleOwner {
+		return membership, entityAuth.ErrOrgForbidden
+	}
+	return membership, nil
+}
+
+// ListOrgMembers выводит участников организации любому её участнику
+func (a *AuthUsecase) ListOrgMembers(ctx context.Context, userID int, orgID int, requestID string) ([]entityAuth.OrgMember, error) {
+	if _, err := a.authRepository.GetMembership(orgID, userID, requestID); err != nil {
+		return nil, err
+	}
+	return a.authRepository.ListOrgMembers(orgID, requestID)
+}
+
+// InviteToOrganization отправляет на email приглашение с ролью role. Приглашать
+// может только владелец
+func (a *AuthUsecase) InviteToOrganization(ctx context.Context, userID int, orgID int, email string, role string, requestID string) error {
+	startTime := time.Now()
+
+	if !authenticate.ValidOrgRole(role) {
+		return entityAuth.ErrOrgRole
+	}
+	membership, err := a.requireOrgOwner(orgID, userID, requestID)
+	if err != nil {
+		return err
+	}
+
+	token, err := randomURLString(entityAuth.OrgInviteTokenBytes)
+	if err != nil {
+		return err
+	}
+	invite := entityAuth.OrgInvite{
+		OrgID:     orgID,
+		Email:     strings.TrimSpace(email),
+		Role:      role,
+		TokenHash: hashResetToken(token),
+		InvitedBy: userID,
+		ExpiresAt: time.Now().Add(entityAuth.OrgInviteTTL),
+	}
+	if err := a.authRepository.CreateOrgInvite(&invite, requestID); err != nil {
+		return err
+	}
+
+	if err := a.mailRepository.SendOrgInvite(invite.Email, membership.Name, role, token); err != nil {
+		a.asyncLogger.Log(zapcore.WarnLevel, requestID, "Mail-service failed to send invite",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"orgID": orgID,
+				"error": err.Error(),
+			}))
+		return entityAuth.ErrEmailNotDelivered
+	}
+
+	a.asyncLogger.Log(zapcore.InfoLevel, requestID, "Organization invite sent",
+		optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+			"orgID":    orgID,
+			"inviteID": invite.ID,
+			"role":     role,
+		}))
+	return nil
+}
+
+// AcceptOrgInvite принимает приглашение, отправленное на почту пользователя
+func (a *AuthUsecase) AcceptOrgInvite(ctx context.Context, userID int, token string, requestID string) (*entityAuth.Membership, error) {
+	user, err := a.GetUser(ctx, userID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	membership, err := a.authRepository.AcceptOrgInvite(hashResetToken(strings.TrimSpace(token)), userID, user.Email, requestID)
+	if err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
+
+// ChangeOrgMemberRole меняет роль участника. Его открытые в организации
+// сессии получают новую роль сразу
+func (a *AuthUsecase) ChangeOrgMemberRole(ctx context.Context, userID int, orgID int, memberID int, role string, requestID string) error {
+	if !authenticate.ValidOrgRole(role) {
+		return entityAuth.ErrOrgRole
+	}
+	if _, err := a.requireOrgOwner(orgID, userID, requestID); err != nil {
+		return err
+	}
+	if err := a.authRepository.UpdateOrgMemberRole(orgID, memberID, role, userID, requestID); err != nil {
+		return err
+	}
+	return a.sessionRepository.UpdateUserSessionsOrg(memberID, orgID, role, 0)
+}
+
+// RemoveOrgMember исключает участника: это может владелец, а сам участник —
+// выйти. Сессии участника в организации возвращаются в его личный аккаунт
+func (a *AuthUsecase) RemoveOrgMember(ctx context.Context, userID int, orgID int, memberID int, requestID string) error {
+	if memberID == userID {
+		if _, err := a.authRepository.GetMembership(orgID, userID, requestID); err != nil {
+			return err
+		}
+	} else if _, err := a.requireOrgOwner(orgID, userID, requestID); err != nil {
+		return err
+	}
+
+	if err := a.authRepository.RemoveOrgMember(orgID, memberID, userID, requestID); err != nil {
+		return err
+	}
+	member, err := a.GetUser(ctx, memberID, requestID)
+	if err != nil {
+		return err
+	}
+	return a.sessionRepository.UpdateUserSessionsOrg(memberID, orgID, "", member.Role)
+}
+
+// SwitchOrganization переводит текущую сессию в организацию orgID: дальше
+// сервисы видят её аккаунт. orgID == 0 — обратно в личный аккаунт
+func (a *AuthUsecase) SwitchOrganization(ctx context.Context, userID int, orgID int, sessionID string, requestID string) (*entityAuth.Membership, error) {
+	if orgID == 0 {
+		user, err := a.GetUser(ctx, userID, requestID)
+		if err != nil {
+			return nil, err
+		}
+		return nil, a.sessionRepository.SwitchSessionOrg(sessionID, 0, "", user.Role)
+	}
+
+	membership, err := a.authRepository.GetMembership(orgID, userID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.sessionRepository.SwitchSessionOrg(sessionID, orgID, membership.MemberRole, membership.Role); err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	entityAuth "retarget/internal/auth-service/entity/auth"
+	"retarget/pkg/utils/optiLog"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// -----------------------------
+// Сброс и смена пароля
+// -----------------------------
+
+func generateResetToken() (string, error) {
+	raw := make([]byte, entityAuth.ResetTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// hashResetToken: у токена достаточно энтропии, соль не нужна — зато по
+// хешу токен находится без знания пользователя
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// setPassword перехеширует пароль и завершает сессии пользователя, кроме keepSessionID
+func (a *AuthUsecase) setPassword(userID int, password string, keepSessionID string, requestID string) error {
+	startTime := time.Now()
+
+	hashedPassword, err := hashPassword(password, a.hashCfg)
+	if err != nil {
+		return err
+	}
+	if err := a.authRepository.UpdatePassword(userID, hashedPassword, requestID); err != nil {
+		return err
+	}
+
+	if err := a.sessionRepository.DelUserSessions(userID, keepSessionID); err != nil {
+		a.asyncLogger.Log(zapcore.ErrorLevel, requestID, "Failed to revoke sessions after password change",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"userID": userID,
+				"error":  err.Error(),
+			}))
+		return err
+	}
+
+	a.asyncLogger.Log(zapcore.InfoLevel, requestID, "Password changed",
+		optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+			"userID": userID,
+		}))
+	return nil
+}
+
+// RequestPasswordReset отправляет токен сброса пароля на почту. Ответ не
+// зависит от того, зарегистрирована ли почта
+func (a *AuthUsecase) RequestPasswordReset(ctx context.Context, email string, requestID string) error {
+	startTime := time.Now()
+
+	user, err := a.authRepository.GetUserByEmail(email, requestID)
+	if err != nil {
+		a.asyncLogger.Log(zapcore.DebugLevel, requestID, "Password reset for unknown email",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"email": email,
+			}))
+		return nil
+	}
+
+	token, err := generateResetToken()
+	if err != nil {
+		return err
+	}
+	err = a.codeRepository.SaveToken(entityAuth.CodeResetPassword, user.ID, hashResetToken(token), entityAuth.ResetTokenTTL)
+	if errors.Is(err, entityAuth.ErrCodeCooldown) {
+		// письмо уже ушло недавно, а ошибка выдала бы, что почта зарегистрирована
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := a.mailRepository.SendResetPasswordToken(user.Email, token); err != nil {
+		a.asyncLogger.Log(zapcore.WarnLevel, requestID, "Mail-service failed to send reset token",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"userID": user.ID,
+				"error":  err.Error(),
+			}))
+		//nolint:errcheck
+		a.codeRepository.DelCode(entityAuth.CodeResetPassword, user.ID)
+		return entityAuth.ErrEmailNotDelivered
+	}
+	return nil
+}
+
+// ResetPassword задаёт новый пароль по токену из письма и завершает все сессии
+// пользователя. Токен одноразовый
+func (a *AuthUsecase) ResetPassword(ctx context.Context, token string, password string, requestID string) error {
+	if len(password) < 8 {
+		return entityAuth.ErrPasswordTooShort
+	}
+
+	userID, err := a.codeRepository.TakeToken(entityAuth.CodeResetPassword, hashResetToken(token))
+	if err != nil {
+		return err
+	}
+
+	if err := a.setPassword(userID, password, "", requestID); err != nil {
+		return err
+	}
+	// токен пришёл на почту — значит, она принадлежит пользователю
+	if err := a.authRepository.SetEmailVerified(userID, requestID); err != nil {
+		a.asyncLogger.Log(zapcore.WarnLevel, requestID, "Failed to mark email verified after reset",
+			optiLog.MakeLogFields(requestID, 0, map[string]interface{}{
+				"userID": userID,
+				"error":  err.Error(),
+			}))
+	}
+	return nil
+}
+
+// RequestPasswordChange проверяет текущий пароль и отправляет код
+// подтверждения смены пароля
+func (a *AuthUsecase) RequestPasswordChange(ctx context.Context, userID int, oldPassword string, requestID string) error {
+	startTime := time.Now()
+
+	user, err := a.GetUser(ctx, userID, requestID)
+	if err != nil {
+		return err
+	}
+	if err := compareHashAndPassword(string(user.Password), oldPassword, a.hashCfg); err != nil {
+		a.asyncLogger.Log(zapcore.WarnLevel, requestID, "Password mismatch on change",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"userID": userID,
+			}))
+		return entityAuth.ErrWrongPassword
+	}
+
+	code, err := a.createCode(entityAuth.CodeEditPassword, userID, "")
+	if err != nil {
+		return err
+	}
+	if err := a.mailRepository.SendEditPasswordCode(user.Email, formatCode(code)); err != nil {
+		a.asyncLogger.Log(zapcore.WarnLevel, requestID, "Mail-service failed to send code",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"userID": userID,
+				"error":  err.Error(),
+			}))
+		//nolint:errcheck
+		a.codeRepository.DelCode(entityAuth.CodeEditPassword, userID)
+		return entityAuth.ErrEmailNotDelivered
+	}
+	return nil
+}
+
+// ChangePassword задаёт новый пароль по коду из письма. Текущая сессия
+// остаётся, остальные завершаются
+func (a *AuthUsecase) ChangePassword(ctx context.Context, userID int, code int, password string, sessionID string, requestID string) error {
+	if len(password) < 8 {
+		return entityAuth.ErrPasswordTooShort
+	}
+
+	if err := a.checkCode(entityAuth.CodeEditPassword, userID, code); err != nil {
+		a.asyncLogger.Log(zapcore.WarnLevel, requestID, "Password change code rejected",
+			optiLog.MakeLogFields(requestID, 0, map[string]interface{}{
+				"userID": userID,
+				"error":  err.Error(),
+			}))
+		return err
+	}
+
+	return a.setPassword(userID, password, sessionID, requestID)
+}
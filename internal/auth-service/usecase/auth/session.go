@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	entityAuth "retarget/internal/auth-service/entity/auth"
+	"retarget/pkg/utils/optiLog"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// -----------------------------
+// Активные сессии
+// -----------------------------
+
+// ListSessions возвращает действующие сессии пользователя
+func (a *AuthUsecase) ListSessions(ctx context.Context, userID int, requestID string) ([]entityAuth.Session, error) {
+	return a.sessionRepository.ListUserSessions(userID)
+}
+
+// RevokeSession завершает одну сессию пользователя по её публичному идентификатору
+func (a *AuthUsecase) RevokeSession(ctx context.Context, userID int, handle string, requestID string) error {
+	startTime := time.Now()
+
+	if err := a.sessionRepository.DelUserSession(userID, handle); err != nil {
+		return err
+	}
+
+	a.asyncLogger.Log(zapcore.InfoLevel, requestID, "Session revoked",
+		optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+			"userID":  userID,
+			"session": handle,
+		}))
+	return nil
+}
+
+// RevokeAllSessions завершает все сессии пользователя, включая текущую
+func (a *AuthUsecase) RevokeAllSessions(ctx context.Context, userID int, requestID string) error {
+	startTime := time.Now()
+
+	if err := a.sessionRepository.DelUserSessions(userID, ""); err != nil {
+		return err
+	}
+
+	a.asyncLogger.Log(zapcore.InfoLevel, requestID, "All sessions revoked",
+		optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+			"userID": userID,
+		}))
+	return nil
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"retarget/pkg/utils/optiLog"
+	"retarget/pkg/utils/throttle"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// -----------------------------
+// Ограничение попыток входа
+// -----------------------------
+
+// lockoutTimeLayout — как время окончания блокировки выглядит в письме
+const lockoutTimeLayout = "02.01.2006 15:04 MST"
+
+func loginThrottleKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// checkLoginThrottle отклоняет вход, пока почта или адрес ждут окончания
+// задержки. Пустые почта и адрес не проверяются, недоступный Redis вход не блокирует
+func (a *AuthUsecase) checkLoginThrottle(ctx context.Context, email string, ip string, requestID string) error {
+	if a.loginEmailRule == nil {
+		return nil
+	}
+
+	var err error
+	if key := loginThrottleKey(email); key != "" {
+		err = a.loginEmailRule.Check(ctx, key)
+	}
+	if err == nil && ip != "" {
+		err = a.loginIPRule.Check(ctx, ip)
+	}
+	if err == nil {
+		return nil
+	}
+	if _, limited := throttle.IsThrottled(err); limited {
+		a.asyncLogger.Log(zapcore.WarnLevel, requestID, "Too many login attempts",
+			optiLog.MakeLogFields(requestID, 0, map[string]interface{}{
+				"email": email,
+				"ip":    ip,
+			}))
+		return err
+	}
+
+	a.asyncLogger.Log(zapcore.ErrorLevel, requestID, "Login throttle unavailable",
+		optiLog.MakeLogFields(requestID, 0, map[string]interface{}{
+			"error": err.Error(),
+		}))
+	return nil
+}
+
+// loginFailed учитывает неверный пароль или второй фактор. notify — аккаунт
+// с такой почтой существует, и о блокировке ему можно написать
+func (a *AuthUsecase) loginFailed(ctx context.Context, email string, ip string, notify bool, requestID string) {
+	if a.loginEmailRule == nil {
+		return
+	}
+	if key := loginThrottleKey(email); key != "" {
+		a.accountLoginFailed(ctx, key, email, ip, notify, requestID)
+	}
+
+	if ip == "" {
+		return
+	}
+	if result, err := a.loginIPRule.Fail(ctx, ip); err != nil {
+		a.asyncLogger.Log(zapcore.ErrorLevel, requestID, "Failed to count login failure",
+			optiLog.MakeLogFields(requestID, 0, map[string]interface{}{
+				"error": err.Error(),
+			}))
+	} else if result.Locked {
+		a.asyncLogger.Log(zapcore.WarnLevel, requestID, "Address login locked",
+			optiLog.MakeLogFields(requestID, 0, map[string]interface{}{
+				"ip":       ip,
+				"failures": result.Failures,
+			}))
+	}
+}
+
+// accountLoginFailed засчитывает неудачу аккаунту и пишет владельцу о блокировке
+func (a *AuthUsecase) accountLoginFailed(ctx context.Context, key string, email string, ip string, notify bool, requestID string) {
+	result, err := a.loginEmailRule.Fail(ctx, key)
+	if err != nil {
+		a.asyncLogger.Log(zapcore.ErrorLevel, requestID, "Failed to count login failure",
+			optiLog.MakeLogFields(requestID, 0, map[string]interface{}{
+				"error": err.Error(),
+			}))
+	} else if result.Locked {
+		a.asyncLogger.Log(zapcore.WarnLevel, requestID, "Account login locked",
+			optiLog.MakeLogFields(requestID, 0, map[string]interface{}{
+				"email":    email,
+				"ip":       ip,
+				"failures": result.Failures,
+			}))
+		if notify {
+			until := time.Now().Add(result.RetryAfter).Format(lockoutTimeLayout)
+			if err := a.mailRepository.SendAccountLocked(email, until); err != nil {
+				a.asyncLogger.Log(zapcore.WarnLevel, requestID, "Mail-service failed to send lockout notice",
+					optiLog.MakeLogFields(requestID, 0, map[string]interface{}{
+						"email": email,
+						"error": err.Error(),
+					}))
+			}
+		}
+	}
+}
+
+// loginSucceeded забывает неудачи аккаунта. Неудачи адреса не сбрасываются:
+// иначе перебор паролей чередовали бы со входом в свой аккаунт
+func (a *AuthUsecase) loginSucceeded(ctx context.Context, email string, requestID string) {
+	key := loginThrottleKey(email)
+	if a.loginEmailRule == nil || key == "" {
+		return
+	}
+	if err := a.loginEmailRule.Reset(ctx, key); err != nil {
+		a.asyncLogger.Log(zapcore.ErrorLevel, requestID, "Failed to reset login failures",
+			optiLog.MakeLogFields(requestID, 0, map[string]interface{}{
+				"error": err.Error(),
+			}))
+	}
+}
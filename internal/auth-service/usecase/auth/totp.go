@@ -0,0 +1,240 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	entityAuth "retarget/internal/auth-service/entity/auth"
+	"retarget/pkg/utils/optiLog"
+	"retarget/pkg/utils/totp"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap/zapcore"
+)
+
+// -----------------------------
+// Двухфакторная аутентификация
+// -----------------------------
+
+func generateRecoveryCode() (string, error) {
+	raw := make([]byte, entityAuth.RecoveryCodeBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw))
+	groups := make([]string, 0, len(code)/4)
+	for i := 0; i < len(code); i += 4 {
+		groups = append(groups, code[i:min(i+4, len(code))])
+	}
+	return strings.Join(groups, "-"), nil
+}
+
+// hashRecoveryCode не зависит от регистра и дефисов, с которыми код ввели
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != totp.Digits {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// checkTOTP принимает код приложения не больше одного раза
+func (a *AuthUsecase) checkTOTP(t entityAuth.TOTP, code string) error {
+	step, ok := totp.Validate(t.Secret, code, time.Now())
+	if !ok {
+		return entityAuth.ErrTOTPInvalid
+	}
+	fresh, err := a.codeRepository.MarkTOTPUsed(t.UserID, step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return entityAuth.ErrTOTPInvalid
+	}
+	return nil
+}
+
+// checkSecondFactor принимает код приложения или одноразовый код восстановления
+func (a *AuthUsecase) checkSecondFactor(t entityAuth.TOTP, code string, requestID string) error {
+	code = strings.TrimSpace(code)
+	if isTOTPCode(code) {
+		return a.checkTOTP(t, code)
+	}
+	if err := a.authRepository.UseRecoveryCode(t.UserID, hashRecoveryCode(code), requestID); err != nil {
+		return err
+	}
+	a.asyncLogger.Log(zapcore.InfoLevel, requestID, "Recovery code used",
+		optiLog.MakeLogFields(requestID, 0, map[string]interface{}{
+			"userID": t.UserID,
+		}))
+	return nil
+}
+
+// loginChallenge заводит ожидание второго фактора, если он включён у пользователя
+func (a *AuthUsecase) loginChallenge(user *entityAuth.User, requestID string) (*entityAuth.LoginChallenge, error) {
+	t, err := a.authRepository.GetTOTP(user.ID, requestID)
+	if errors.Is(err, entityAuth.ErrTOTPNotFound) || (err == nil && !t.Enabled) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := &entityAuth.LoginChallenge{
+		ID:        uuid.NewString(),
+		UserID:    user.ID,
+		Email:     user.Email,
+		Role:      user.Role,
+		Verified:  user.EmailVerified,
+		ExpiresAt: time.Now().Add(entityAuth.ChallengeTTL),
+	}
+	if err := a.codeRepository.SaveChallenge(*challenge, entityAuth.ChallengeTTL); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// VerifyLoginChallenge завершает вход вторым фактором и открывает сессию
+func (a *AuthUsecase) VerifyLoginChallenge(ctx context.Context, challengeID string, code string, client entityAuth.SessionClient, requestID string) (*entityAuth.Session, error) {
+	startTime := time.Now()
+
+	challenge, err := a.codeRepository.GetChallenge(challengeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.checkLoginThrottle(ctx, challenge.Email, client.IP, requestID); err != nil {
+		return nil, err
+	}
+	if challenge.Attempts >= entityAuth.ChallengeMaxAttempts {
+		//nolint:errcheck
+		a.codeRepository.DelChallenge(challengeID)
+		return nil, entityAuth.ErrCodeAttempts
+	}
+
+	t, err := a.authRepository.GetTOTP(challenge.UserID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.checkSecondFactor(t, code, requestID); err != nil {
+		if !errors.Is(err, entityAuth.ErrTOTPInvalid) {
+			return nil, err
+		}
+		a.asyncLogger.Log(zapcore.WarnLevel, requestID, "Second factor rejected",
+			optiLog.MakeLogFields(requestID, time.Since(startTime).Milliseconds(), map[string]interface{}{
+				"userID": challenge.UserID,
+			}))
+		a.loginFailed(ctx, challenge.Email, client.IP, true, requestID)
+		attempts, incErr := a.codeRepository.IncrementChallengeAttempts(challengeID)
+		if incErr != nil {
+			return nil, incErr
+		}
+		if attempts >= entityAuth.ChallengeMaxAttempts {
+			//nolint:errcheck
+			a.codeRepository.DelChallenge(challengeID)
+			return nil, entityAuth.ErrCodeAttempts
+		}
+		return nil, err
+	}
+
+	if err := a.codeRepository.DelChallenge(challengeID); err != nil {
+		return nil, err
+	}
+	a.loginSucceeded(ctx, challenge.Email, requestID)
+	return a.AddSession(challenge.UserID, challenge.Role, challenge.Verified, true, client)
+}
+
+// EnrollTOTP выпускает новый секрет и otpauth-ссылку для приложения.
+// Второй фактор заработает после ConfirmTOTP
+func (a *AuthUsecase) EnrollTOTP(ctx context.Context, userID int, requestID string) (string, string, error) {
+	user, err := a.GetUser(ctx, userID, requestID)
+	if err != nil {
+		return "", "", err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", "", err
+	}
+	if err := a.authRepository.SaveTOTPSecret(userID, secret, requestID); err != nil {
+		return "", "", err
+	}
+	return secret, totp.URI(entityAuth.TOTPIssuer, user.Email, secret), nil
+}
+
+// ConfirmTOTP включает второй фактор по первому коду из приложения и
+// возвращает коды восстановления — показываются один раз, хранятся только хэши
+func (a *AuthUsecase) ConfirmTOTP(ctx context.Context, userID int, code string, sessionID string, requestID string) ([]string, error) {
+	t, err := a.authRepository.GetTOTP(userID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Enabled {
+		return nil, entityAuth.ErrTOTPEnabled
+	}
+	if err := a.checkTOTP(t, strings.TrimSpace(code)); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, entityAuth.RecoveryCodeCount)
+	hashes := make([]string, 0, entityAuth.RecoveryCodeCount)
+	for i := 0; i < entityAuth.RecoveryCodeCount; i++ {
+		recovery, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, recovery)
+		hashes = append(hashes, hashRecoveryCode(recovery))
+	}
+	if err := a.authRepository.EnableTOTP(userID, hashes, requestID); err != nil {
+		return nil, err
+	}
+
+	// текущая сессия только что предъявила второй фактор
+	if err := a.sessionRepository.MarkSessionTwoFactor(sessionID); err != nil {
+		a.asyncLogger.Log(zapcore.WarnLevel, requestID, "Failed to mark session two-factor",
+			optiLog.MakeLogFields(requestID, 0, map[string]interface{}{
+				"userID": userID,
+				"error":  err.Error(),
+			}))
+	}
+	return codes, nil
+}
+
+// DisableTOTP выключает второй фактор; нужны пароль и код приложения или код восстановления
+func (a *AuthUsecase) DisableTOTP(ctx context.Context, userID int, password string, code string, requestID string) error {
+	user, err := a.GetUser(ctx, userID, requestID)
+	if err != nil {
+		return err
+	}
+	if err := compareHashAndPassword(string(user.Password), password, a.hashCfg); err != nil {
+		return entityAuth.ErrWrongPassword
+	}
+
+	t, err := a.authRepository.GetTOTP(userID, requestID)
+	if err != nil {
+		return err
+	}
+	if !t.Enabled {
+		return entityAuth.ErrTOTPNotFound
+	}
+	if err := a.checkSecondFactor(t, code, requestID); err != nil {
+		return err
+	}
+	return a.authRepository.DisableTOTP(userID, requestID)
+}
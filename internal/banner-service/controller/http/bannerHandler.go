@@ -127,6 +127,15 @@ func (h *BannerController) CreateBanner(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	currency, err := response.NormalizeCurrency(req.Currency)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		resp := response.NewResponse(true, err.Error())
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
 	userSession, ok := r.Context().Value(response.UserContextKey).(response.UserContext)
 	if !ok {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -147,6 +156,7 @@ func (h *BannerController) CreateBanner(w http.ResponseWriter, r *http.Request)
 		Balance:     0,
 		Status:      req.Status,
 		MaxPrice:    req.MaxPrice,
+		Currency:    currency,
 	}
 
 	if err := h.BannerUsecase.BannerRepository.CreateNewBanner(banner, requestID); err != nil {
@@ -191,6 +201,15 @@ func (h *BannerController) UpdateBanner(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	currency, err := response.NormalizeCurrency(req.Currency)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		resp := response.NewResponse(true, err.Error())
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
 	userSession, ok := r.Context().Value(response.UserContextKey).(response.UserContext)
 	if !ok {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -223,6 +242,7 @@ func (h *BannerController) UpdateBanner(w http.ResponseWriter, r *http.Request)
 		Content:     req.Content,
 		Status:      req.Status,
 		MaxPrice:    req.MaxPrice,
+		Currency:    currency,
 	}
 
 	if err := h.BannerUsecase.UpdateBanner(userID, banner, requestID); err != nil {
@@ -22,6 +22,7 @@ type IFrame struct {
 	Action      string
 	Banner      int
 	Slot        string
+	Reservation string
 }
 
 func (h *BannerController) GetBannerIFrameByID(w http.ResponseWriter, r *http.Request) {
@@ -21,26 +21,36 @@ func NewBannerController(bannerUsecase *banner.BannerUsecase, imageUsecase *bann
 
 func SetupBannerRoutes(authenticator *authenticate.Authenticator, bannerUsecase *banner.BannerUsecase, imageUsecase *banner.BannerImageUsecase) http.Handler {
 	muxRouter := mux.NewRouter()
+	// ручки со ScopedAuthMiddleware доступны и по API-ключу с нужным правом
 	linkBuilder := NewLinkBuilder(muxRouter)
 	bannerController := NewBannerController(bannerUsecase, imageUsecase, linkBuilder)
-	// middleware.AuthMiddleware(authUsecase)()
-	muxRouter.Handle("/api/v1/banner/", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(bannerController.GetUserBanners)))).Methods("GET")
+	// баннеры ведут рекламодатели; в организации генерация доступна тем, кто редактирует баннеры
+	advertisers := authenticate.Require(authenticate.PermBanners)
+	read := func(h http.HandlerFunc) http.Handler {
+		return logger.LogMiddleware(authenticate.ScopedAuthMiddleware(authenticator, authenticate.ScopeBannersRead)(advertisers(h)))
+	}
+	write := func(h http.Handler) http.Handler {
+		return logger.LogMiddleware(authenticate.ScopedAuthMiddleware(authenticator, authenticate.ScopeBannersWrite)(advertisers(h)))
+	}
+	editors := authenticate.RequireOrgRole(authenticate.OrgRoleOwner, authenticate.OrgRoleManager)
+
+	muxRouter.Handle("/api/v1/banner/", read(bannerController.GetUserBanners)).Methods("GET")
 	// CRUD
-	muxRouter.Handle("/api/v1/banner/create", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(bannerController.CreateBanner)))).Methods("POST")
-	muxRouter.Handle("/api/v1/banner/{banner_id:[0-9]+}", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(bannerController.ReadBanner)))).Methods("GET")
-	muxRouter.Handle("/api/v1/banner/{banner_id:[0-9]+}", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(bannerController.UpdateBanner)))).Methods("PUT")
-	muxRouter.Handle("/api/v1/banner/{banner_id:[0-9]+}", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(bannerController.DeleteBanner)))).Methods("DELETE")
+	muxRouter.Handle("/api/v1/banner/create", write(authenticate.RequireVerified(http.HandlerFunc(bannerController.CreateBanner)))).Methods("POST")
+	muxRouter.Handle("/api/v1/banner/{banner_id:[0-9]+}", read(bannerController.ReadBanner)).Methods("GET")
+	muxRouter.Handle("/api/v1/banner/{banner_id:[0-9]+}", write(http.HandlerFunc(bannerController.UpdateBanner))).Methods("PUT")
+	muxRouter.Handle("/api/v1/banner/{banner_id:[0-9]+}", write(http.HandlerFunc(bannerController.DeleteBanner))).Methods("DELETE")
 	//IFrame
-	muxRouter.Handle("/api/v1/banner/iframe/{banner_id:[0-9]+}", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(bannerController.GetBannerIFrameByID)))).Methods("GET")
+	muxRouter.Handle("/api/v1/banner/iframe/{banner_id:[0-9]+}", read(bannerController.GetBannerIFrameByID)).Methods("GET")
 	// Работа с картинками
 	muxRouter.Handle("/api/v1/banner/image/{image_id}", logger.LogMiddleware(http.HandlerFunc(bannerController.DownloadImage))).Methods("GET").Name("download_image")
-	muxRouter.Handle("/api/v1/banner/upload", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(bannerController.UploadImageHandler)))).Methods("PUT")
+	muxRouter.Handle("/api/v1/banner/upload", write(http.HandlerFunc(bannerController.UploadImageHandler))).Methods("PUT")
 	// Рандомный айфрейм юзера
 	muxRouter.Handle("/api/v1/banner/uniq_link/{uniq_link}", logger.LogMiddleware(http.HandlerFunc(bannerController.RandomIFrame))).Methods("GET")
 
 	// Маршрут для генерации описания
 	muxRouter.Handle("/api/v1/banner/generate/description",
-		logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(bannerController.GenerateDescription)))).
+		logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(advertisers(editors(http.HandlerFunc(bannerController.GenerateDescription)))))).
 		Methods("POST")
 
 	// Ручка генерации картинки
@@ -48,7 +58,7 @@ func SetupBannerRoutes(authenticator *authenticate.Authenticator, bannerUsecase
 		"/api/v1/banner/generate/image",
 		logger.LogMiddleware(
 			authenticate.AuthMiddleware(authenticator)(
-				http.HandlerFunc(bannerController.GenerateImage),
+				advertisers(editors(http.HandlerFunc(bannerController.GenerateImage))),
 			),
 		),
 	).Methods("POST")
@@ -7,6 +7,7 @@ import (
 	"github.com/gorilla/mux"
 	banneruc "retarget/internal/banner-service/usecase"
 	authenticate "retarget/pkg/middleware/auth"
+	"retarget/pkg/middleware/auth/authtest"
 )
 
 func TestSetupBannerRoutes_AllRoutes(t *testing.T) {
@@ -233,3 +234,20 @@ func TestNewBannerController_Complete(t *testing.T) {
 		t.Error("LinkBuilder wasn't properly assigned")
 	}
 }
+
+func TestSetupBannerRoutes_AccessMatrix(t *testing.T) {
+	router := SetupBannerRoutes(authtest.Authenticator(t), &banneruc.BannerUsecase{}, &banneruc.BannerImageUsecase{})
+	advertiser := []int{authenticate.RoleAdvertiser}
+
+	authtest.AssertRoutes(t, router, []authtest.Route{
+		{Method: http.MethodGet, Path: "/api/v1/banner/", Roles: advertiser},
+		{Method: http.MethodPost, Path: "/api/v1/banner/create", Roles: advertiser},
+		{Method: http.MethodGet, Path: "/api/v1/banner/1", Roles: advertiser},
+		{Method: http.MethodPut, Path: "/api/v1/banner/1", Roles: advertiser},
+		{Method: http.MethodDelete, Path: "/api/v1/banner/1", Roles: advertiser},
+		{Method: http.MethodGet, Path: "/api/v1/banner/iframe/1", Roles: advertiser},
+		{Method: http.MethodPut, Path: "/api/v1/banner/upload", Roles: advertiser},
+		{Method: http.MethodPost, Path: "/api/v1/banner/generate/description", Roles: advertiser},
+		{Method: http.MethodPost, Path: "/api/v1/banner/generate/image", Roles: advertiser},
+	})
+}
@@ -11,6 +11,7 @@ type CreateUpdateBannerRequest struct {
 	Link        string         `json:"link" validate:"required,max=100"`
 	Status      int            `json:"status"`
 	MaxPrice    entity.Decimal `json:"max_price" validate:"gt_decimal_01"`
+	Currency    string         `json:"currency"` // валюта ставки, пустая — рубли
 }
 
 type Banner struct {
@@ -24,6 +25,7 @@ type Banner struct {
 	Link        string         `json:"link"`
 	Deleted     bool           `json:"deleted"`
 	MaxPrice    entity.Decimal `json:"max_price"`
+	Currency    string         `json:"currency"`
 }
 
 //easyjson:json
@@ -50,6 +50,8 @@ func easyjsonC80ae7adDecodeRetargetInternalBannerServiceEasyjsonModels(in *jlexe
 			if data := in.Raw(); in.Ok() {
 				in.AddError((out.MaxPrice).UnmarshalJSON(data))
 			}
+		case "currency":
+			out.Currency = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -94,6 +96,11 @@ func easyjsonC80ae7adEncodeRetargetInternalBannerServiceEasyjsonModels(out *jwri
 		out.RawString(prefix)
 		out.Raw((in.MaxPrice).MarshalJSON())
 	}
+	{
+		const prefix string = ",\"currency\":"
+		out.RawString(prefix)
+		out.String(string(in.Currency))
+	}
 	out.RawByte('}')
 }
 
@@ -227,6 +234,8 @@ func easyjsonC80ae7adDecodeRetargetInternalBannerServiceEasyjsonModels2(in *jlex
 			if data := in.Raw(); in.Ok() {
 				in.AddError((out.MaxPrice).UnmarshalJSON(data))
 			}
+		case "currency":
+			out.Currency = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -291,6 +300,11 @@ func easyjsonC80ae7adEncodeRetargetInternalBannerServiceEasyjsonModels2(out *jwr
 		out.RawString(prefix)
 		out.Raw((in.MaxPrice).MarshalJSON())
 	}
+	{
+		const prefix string = ",\"currency\":"
+		out.RawString(prefix)
+		out.String(string(in.Currency))
+	}
 	out.RawByte('}')
 }
 
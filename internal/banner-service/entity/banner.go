@@ -16,4 +16,5 @@ var DefaultBanner = model.Banner{
 	Link:        "http://test.re-target.ru",
 	Deleted:     false,
 	MaxPrice:    *entity.NewDecWithoutErr("0.0"),
+	Currency:    entity.BaseCurrency,
 }
@@ -32,7 +32,11 @@ func (s *BannerServer) GetRandomBanner(
 	req *bannerpb.BannerWithMinPrice,
 ) (*bannerpb.Banner, error) {
 	dec, _ := entity.NewDec(req.MinPrice)
-	banner, err := s.bannerUC.GetRandomBannerForADV(0, "", dec)
+	currency, err := entity.NormalizeCurrency(req.GetCurrency())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid floor currency: %v", err)
+	}
+	banner, err := s.bannerUC.GetRandomBannerForADV(0, "", dec, currency)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get banner: %v", err)
 	}
@@ -44,6 +48,7 @@ func (s *BannerServer) GetRandomBanner(
 		OwnerID:     strconv.Itoa(banner.OwnerID),
 		Id:          int64(banner.ID),
 		MaxPrice:    banner.MaxPrice.String(),
+		Currency:    banner.Currency,
 	}, nil
 }
 
@@ -52,7 +57,11 @@ func (s *BannerServer) GetSuitableBanners(
 	req *bannerpb.BannerWithMinPrice,
 ) (*bannerpb.ActiveBanners, error) {
 	dec, _ := entity.NewDec(req.MinPrice)
-	bannerIDs, err := s.bannerUC.GetSuitableBannersForADV(dec)
+	currency, err := entity.NormalizeCurrency(req.GetCurrency())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid floor currency: %v", err)
+	}
+	bannerIDs, err := s.bannerUC.GetSuitableBannersForADV(dec, currency)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get banner: %v", err)
 	}
@@ -77,6 +86,7 @@ func (s *BannerServer) GetBannerByID(ctx context.Context, req *bannerpb.BannerRe
 		OwnerID:     strconv.Itoa(banner.OwnerID),
 		Id:          int64(banner.ID),
 		MaxPrice:    banner.MaxPrice.String(),
+		Currency:    banner.Currency,
 	}, nil
 }
 
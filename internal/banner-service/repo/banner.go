@@ -21,7 +21,7 @@ type BannerRepositoryInterface interface {
 	UpdateBanner(banner model.Banner)
 	GetBannerByID(id int) (*model.Banner, error)
 	DeleteBannerByID(owner, id int) error
-	GetSuitableBanners(floor *decimal.Decimal) ([]int64, error)
+	GetSuitableBanners(floor *decimal.Decimal, currency string) ([]int64, error)
 }
 
 type BannerRepository struct {
@@ -42,21 +42,38 @@ func NewBannerRepository(endPoint string, logger *zap.SugaredLogger, GigaChatSer
 	return bannerRepo
 }
 
-func (r *BannerRepository) GetSuitableBanners(floor *decimal.Decimal) ([]int64, error) {
-	r.logger.Debugw("Executing SQL query GetSuitableBanners", "floor", floor.String())
-
-	query := `
+// latestRates — курсы к рублю из последнего снимка, который ведёт pay-service.
+// Ставки и минимальные цены сравниваются только после пересчёта в рубли;
+// баннеры в валюте без курса в аукционе не участвуют
+const latestRates = `
+        WITH rates AS (
+            SELECT currency, rate FROM currency_rate
+            WHERE snapshot_id = (SELECT MAX(id) FROM currency_rate_snapshot)
+            UNION ALL
+            SELECT '` + decimal.BaseCurrency + `', 1
+        )`
+
+// advertiserFunds — средства рекламодателя, из которых оплачиваются показы:
+// основной баланс и промо-кредит
+const advertiserFunds = `(u.balance + u.promo_balance)`
+
+func (r *BannerRepository) GetSuitableBanners(floor *decimal.Decimal, currency string) ([]int64, error) {
+	r.logger.Debugw("Executing SQL query GetSuitableBanners", "floor", floor.String(), "currency", currency)
+
+	query := latestRates + `
         SELECT b.id
         FROM banner b
         JOIN auth_user u ON b.owner_id = u.id
+        JOIN rates r ON r.currency = b.currency
+        JOIN rates fr ON fr.currency = $2
         WHERE b.status = 1
-          AND u.balance > 0
-          AND b.max_price >= $1
-          AND u.balance >= b.max_price
+          AND ` + advertiserFunds + ` > 0
+          AND b.max_price * r.rate >= $1 * fr.rate
+          AND ` + advertiserFunds + ` >= b.max_price * r.rate
 		  AND NOT b.deleted
     `
 
-	rows, err := r.Db.Query(query, floor)
+	rows, err := r.Db.Query(query, floor, currency)
 	if err != nil {
 		return nil, err
 	}
@@ -79,7 +96,7 @@ func (r *BannerRepository) GetSuitableBanners(floor *decimal.Decimal) ([]int64,
 }
 
 func (r *BannerRepository) GetBannersByUserId(id int, requestID string) ([]model.Banner, error) {
-	query := "SELECT id, owner_id, title, description, content, status, link, max_price FROM banner WHERE owner_id = $1 AND NOT deleted;"
+	query := "SELECT id, owner_id, title, description, content, status, link, max_price, currency FROM banner WHERE owner_id = $1 AND NOT deleted;"
 	r.logger.Debugw("Executing SQL query GetProfileByID", "request_id", requestID, "query", query, "userID", id)
 	startTime := time.Now()
 	rows, err := r.Db.Query(query, id)
@@ -93,7 +110,7 @@ func (r *BannerRepository) GetBannersByUserId(id int, requestID string) ([]model
 
 	for rows.Next() {
 		banner := model.Banner{}
-		err := rows.Scan(&banner.ID, &banner.OwnerID, &banner.Title, &banner.Description, &banner.Content, &banner.Status, &banner.Link, &banner.MaxPrice)
+		err := rows.Scan(&banner.ID, &banner.OwnerID, &banner.Title, &banner.Description, &banner.Content, &banner.Status, &banner.Link, &banner.MaxPrice, &banner.Currency)
 		if err != nil {
 			r.logger.Debugw("SQL Error", "request_id", requestID, "userID", id, "duration", duration, "error", err)
 			return nil, err
@@ -109,22 +126,24 @@ func (r *BannerRepository) GetBannersByUserId(id int, requestID string) ([]model
 	return banners, nil
 }
 
-func (r *BannerRepository) GetMaxPriceBanner(floor *decimal.Decimal) *model.Banner {
-	r.logger.Debugw("Executing SQL query GetMaxPriceBanner", "floor", floor.String())
-
-	query := `
-        SELECT b.id, b.title, b.content, b.description, b.link, b.owner_id
-        FROM banner b
-        JOIN auth_user u ON b.owner_id = u.id
-        WHERE b.status = 1
-          AND u.balance > 0
-          AND b.max_price = (
-              SELECT MAX(b2.max_price)
-              FROM banner b2
-              JOIN auth_user u2 ON b2.owner_id = u2.id
-              WHERE b2.status = 1 AND u2.balance > 0 AND b2.max_price > $1
-          )
-		  AND NOT b.deleted
+func (r *BannerRepository) GetMaxPriceBanner(floor *decimal.Decimal, currency string) *model.Banner {
+	r.logger.Debugw("Executing SQL query GetMaxPriceBanner", "floor", floor.String(), "currency", currency)
+
+	query := latestRates + `,
+        bids AS (
+            SELECT b.id, b.title, b.content, b.description, b.link, b.owner_id, b.max_price, b.currency,
+                   b.max_price * r.rate AS base_price
+            FROM banner b
+            JOIN auth_user u ON b.owner_id = u.id
+            JOIN rates r ON r.currency = b.currency
+            WHERE b.status = 1 AND ` + advertiserFunds + ` > 0 AND NOT b.deleted
+        )
+        SELECT id, title, content, description, link, owner_id, max_price, currency
+        FROM bids
+        WHERE base_price = (
+            SELECT MAX(base_price) FROM bids
+            WHERE base_price > $1 * (SELECT rate FROM rates WHERE currency = $2)
+        )
         ORDER BY RANDOM()
         LIMIT 1;
     `
@@ -132,13 +151,15 @@ func (r *BannerRepository) GetMaxPriceBanner(floor *decimal.Decimal) *model.Bann
 	var banner model.Banner
 	startTime := time.Now()
 
-	err := r.Db.QueryRow(query, floor).Scan(
+	err := r.Db.QueryRow(query, floor, currency).Scan(
 		&banner.ID,
 		&banner.Title,
 		&banner.Content,
 		&banner.Description,
 		&banner.Link,
 		&banner.OwnerID,
+		&banner.MaxPrice,
+		&banner.Currency,
 	)
 
 	r.logger.Debugw("SQL query completed", "duration", time.Since(startTime))
@@ -159,7 +180,7 @@ func (r *BannerRepository) CreateNewBanner(banner model.Banner, requestID string
 		// "status", banner.Status,
 		"link", banner.Link,
 	)
-	stmt, err := r.Db.Prepare("INSERT INTO banner (owner_id, title, description, content, status, balance, link, max_price) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id;")
+	stmt, err := r.Db.Prepare("INSERT INTO banner (owner_id, title, description, content, status, balance, link, max_price, currency) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id;")
 	startTime := time.Now()
 
 	if err != nil {
@@ -171,7 +192,7 @@ func (r *BannerRepository) CreateNewBanner(banner model.Banner, requestID string
 	defer stmt.Close()
 
 	var id int64
-	err = stmt.QueryRow(banner.OwnerID, banner.Title, banner.Description, banner.Content, banner.Status, 0, banner.Link, banner.MaxPrice).Scan(&id)
+	err = stmt.QueryRow(banner.OwnerID, banner.Title, banner.Description, banner.Content, banner.Status, 0, banner.Link, banner.MaxPrice, bannerCurrency(banner)).Scan(&id)
 	if err != nil {
 		r.logger.Debugw("Error executing query to create new banner", "request_id", requestID, "error", err)
 		return err
@@ -185,7 +206,7 @@ func (r *BannerRepository) CreateNewBanner(banner model.Banner, requestID string
 
 func (r *BannerRepository) UpdateBanner(banner model.Banner, requestID string) error {
 	startTime := time.Now()
-	query := "UPDATE banner SET title = $1, description = $2, content = $3, link = $4, status = $5, max_price = $6 WHERE id = $7"
+	query := "UPDATE banner SET title = $1, description = $2, content = $3, link = $4, status = $5, max_price = $6, currency = $7 WHERE id = $8"
 	r.logger.Debugw("Starting banner update",
 		"request_id", requestID,
 		"bannerID", banner.ID,
@@ -202,7 +223,7 @@ func (r *BannerRepository) UpdateBanner(banner model.Banner, requestID string) e
 		return err
 	}
 	defer stmt.Close()
-	_, err = stmt.Exec(banner.Title, banner.Description, banner.Content, banner.Link, banner.Status, banner.MaxPrice, banner.ID)
+	_, err = stmt.Exec(banner.Title, banner.Description, banner.Content, banner.Link, banner.Status, banner.MaxPrice, bannerCurrency(banner), banner.ID)
 	if err != nil {
 		r.logger.Debugw("Failed to execute banner update",
 			"request_id", requestID,
@@ -223,7 +244,7 @@ func (r *BannerRepository) UpdateBanner(banner model.Banner, requestID string) e
 func (r *BannerRepository) GetBannerByID(id int, requestID string) (*model.Banner, error) {
 	startTime := time.Now()
 	query := `
-		SELECT owner_id, title, description, content, balance, link, status, max_price
+		SELECT owner_id, title, description, content, balance, link, status, max_price, currency
 		FROM banner
 		WHERE id = $1 AND deleted = FALSE;
 		`
@@ -244,6 +265,7 @@ func (r *BannerRepository) GetBannerByID(id int, requestID string) (*model.Banne
 		&banner.Link,
 		&banner.Status,
 		&banner.MaxPrice,
+		&banner.Currency,
 	)
 	if err != nil {
 		r.logger.Debugw("Failed to fetch banner",
@@ -379,6 +401,13 @@ func (r *BannerRepository) GenerateBannerImage(bannerID int, requestID string) (
 	return imageBytes, nil
 }
 
+func bannerCurrency(banner model.Banner) string {
+	if banner.Currency == "" {
+		return decimal.BaseCurrency
+	}
+	return banner.Currency
+}
+
 func (r *BannerRepository) CloseConnection() error {
 	return r.Db.Close()
 }
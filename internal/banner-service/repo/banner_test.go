@@ -0,0 +1,36 @@
+package repo
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	decimal "retarget/pkg/entity"
+)
+
+// у рекламодателя только промо-кредит: основной баланс нулевой
+func TestAuction_PromoOnlyAdvertiser(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	r := &BannerRepository{Db: db, logger: zap.NewNop().Sugar()}
+	floor := decimal.DecimalFromKopecks(100)
+
+	mock.ExpectQuery(`\(u\.balance \+ u\.promo_balance\) > 0\s+AND b\.max_price \* r\.rate >= \$1 \* fr\.rate\s+AND \(u\.balance \+ u\.promo_balance\) >= b\.max_price \* r\.rate`).
+		WithArgs(&floor, "RUB").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	ids, err := r.GetSuitableBanners(&floor, "RUB")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{7}, ids)
+
+	mock.ExpectQuery(`WHERE b\.status = 1 AND \(u\.balance \+ u\.promo_balance\) > 0 AND NOT b\.deleted`).
+		WithArgs(&floor, "RUB").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "description", "link", "owner_id", "max_price", "currency"}).
+			AddRow(7, "t", "c", "d", "l", 5, "2.00", "RUB"))
+	banner := r.GetMaxPriceBanner(&floor, "RUB")
+	if assert.NotNil(t, banner) {
+		assert.Equal(t, 7, banner.ID)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -14,7 +14,7 @@ import (
 type BannerRepo interface {
 	GetBannersByUserId(int, string) ([]model.Banner, error)
 	GetBannerByID(int, string) (*model.Banner, error)
-	GetMaxPriceBanner(interface{}) *model.Banner
+	GetMaxPriceBanner(*decimal.Decimal, string) *model.Banner
 	CreateNewBanner(model.Banner, string) error
 	UpdateBanner(model.Banner, string) error
 	DeleteBannerByID(int, int, string) error
@@ -68,16 +68,18 @@ func (b *BannerUsecase) GetRandomBannerForIFrame(userID int, requestID string) (
 	return &entity.DefaultBanner, nil
 }
 
-func (b *BannerUsecase) GetRandomBannerForADV(userID int, requestID string, floor *decimal.Decimal) (*model.Banner, error) {
-	banner := b.BannerRepository.GetMaxPriceBanner(floor)
+// GetRandomBannerForADV выбирает баннер с наибольшей ставкой выше минимальной
+// цены слота; floor задан в валюте слота currency
+func (b *BannerUsecase) GetRandomBannerForADV(userID int, requestID string, floor *decimal.Decimal, currency string) (*model.Banner, error) {
+	banner := b.BannerRepository.GetMaxPriceBanner(floor, currency)
 	if banner == nil {
 		return &entity.DefaultBanner, nil
 	}
 	return banner, nil
 }
 
-func (b *BannerUsecase) GetSuitableBannersForADV(floor *decimal.Decimal) ([]int64, error) {
-	bannerIDs, err := b.BannerRepository.GetSuitableBanners(floor)
+func (b *BannerUsecase) GetSuitableBannersForADV(floor *decimal.Decimal, currency string) ([]int64, error) {
+	bannerIDs, err := b.BannerRepository.GetSuitableBanners(floor, currency)
 	if err != nil {
 		return []int64{-1}, nil
 	}
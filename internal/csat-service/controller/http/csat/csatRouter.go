@@ -21,11 +21,15 @@ func SetupCsatRoutes(authenticator *authenticate.Authenticator, csatUsecase *csa
 	muxRouter := mux.NewRouter()
 	csatController := NewCsatController(csatUsecase)
 
-	muxRouter.Handle("/api/v1/csat/show/{page_id:[a-zA-Z0-9]+}", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(csatController.ShowQuestionByPageID)))).Methods("GET")
-	muxRouter.Handle("/api/v1/csat/my-reviews", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(csatController.ShowReviewsByUserID)))).Methods("GET")
-	muxRouter.Handle("/api/v1/csat/reviews", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(csatController.ShowAllReviews)))).Methods("GET")
-	muxRouter.Handle("/api/v1/csat/show/iframe/{page_id:[a-zA-Z0-9]+}", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(csatController.ShowQuestionIFrameByPageID)))).Methods("GET")
-	muxRouter.Handle("/api/v1/csat/send", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(csatController.SendReview)))).Methods("POST")
+	// опросы проходят все пользователи, чужие отзывы читают поддержка и администраторы
+	reviewers := authenticate.Require(authenticate.PermReviews)
+	support := authenticate.Require(authenticate.PermReviewsAll)
+
+	muxRouter.Handle("/api/v1/csat/show/{page_id:[a-zA-Z0-9]+}", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(reviewers(http.HandlerFunc(csatController.ShowQuestionByPageID))))).Methods("GET")
+	muxRouter.Handle("/api/v1/csat/my-reviews", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(reviewers(http.HandlerFunc(csatController.ShowReviewsByUserID))))).Methods("GET")
+	muxRouter.Handle("/api/v1/csat/reviews", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(support(http.HandlerFunc(csatController.ShowAllReviews))))).Methods("GET")
+	muxRouter.Handle("/api/v1/csat/show/iframe/{page_id:[a-zA-Z0-9]+}", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(reviewers(http.HandlerFunc(csatController.ShowQuestionIFrameByPageID))))).Methods("GET")
+	muxRouter.Handle("/api/v1/csat/send", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(reviewers(http.HandlerFunc(csatController.SendReview))))).Methods("POST")
 
 	// TODO Аналитика
 	// muxRouter.Handle("/api/v1/csat/put-questions", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(csatController.SendReview)))).Methods("POST")
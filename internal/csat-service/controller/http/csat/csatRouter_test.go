@@ -0,0 +1,24 @@
+package csat
+
+import (
+	"net/http"
+	"testing"
+
+	csat "retarget/internal/csat-service/usecase/csat"
+	authenticate "retarget/pkg/middleware/auth"
+	"retarget/pkg/middleware/auth/authtest"
+)
+
+func TestSetupCsatRoutes_AccessMatrix(t *testing.T) {
+	router := SetupCsatRoutes(authtest.Authenticator(t), &csat.CsatUsecase{})
+	everyone := authenticate.Roles
+	support := []int{authenticate.RoleSupport, authenticate.RoleAdmin}
+
+	authtest.AssertRoutes(t, router, []authtest.Route{
+		{Method: http.MethodGet, Path: "/api/v1/csat/show/main", Roles: everyone},
+		{Method: http.MethodGet, Path: "/api/v1/csat/show/iframe/main", Roles: everyone},
+		{Method: http.MethodGet, Path: "/api/v1/csat/my-reviews", Roles: everyone},
+		{Method: http.MethodPost, Path: "/api/v1/csat/send", Roles: everyone},
+		{Method: http.MethodGet, Path: "/api/v1/csat/reviews", Roles: support},
+	})
+}
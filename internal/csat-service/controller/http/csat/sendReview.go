@@ -41,7 +41,7 @@ func (c *CsatController) SendReview(w http.ResponseWriter, r *http.Request) {
 		//nolint:errcheck
 		json.NewEncoder(w).Encode(response.NewResponse(true, "Error of authenticator"))
 	}
-	userID := userSession.UserID
+	userID := userSession.Actor()
 
 	review := entity.Review{
 		UserID:   userID,
@@ -16,7 +16,7 @@ func (c *CsatController) ShowReviewsByUserID(w http.ResponseWriter, r *http.Requ
 		json.NewEncoder(w).Encode(response.NewResponse(true, "Error of authenticator"))
 		return
 	}
-	userID := userSession.UserID
+	userID := userSession.Actor()
 	reviews, err := c.csatUsecase.GetReviewsByUser(userID)
 	if err != nil {
 		w.WriteHeader(http.StatusNotFound)
@@ -20,8 +20,12 @@ func SetupMailRoutes(mailUsecase *usecaseMail.MailUsecase) http.Handler {
 	mailController := NewMailController(mailUsecase)
 
 	muxRouter.Handle("/api/v1/mail/send-register-code", http.HandlerFunc(mailController.SendRegisterCodeHandler))
-	// muxRouter.Handle("/api/v1/mail/send-recovery-code", http.HandlerFunc(mailController.SendRecoveryCodeHandler))
-	// muxRouter.Handle("/api/v1/mail/send-password-reset-code", http.HandlerFunc(mailController.SendEditPasswordCodeHandler))
+	muxRouter.Handle("/api/v1/mail/send-recovery-code", http.HandlerFunc(mailController.SendRecoveryCodeHandler))
+	muxRouter.Handle("/api/v1/mail/send-password-reset-code", http.HandlerFunc(mailController.SendEditPasswordCodeHandler))
+	muxRouter.Handle("/api/v1/mail/send-email-change-code", http.HandlerFunc(mailController.SendEditEmailCodeHandler))
+	muxRouter.Handle("/api/v1/mail/send-email-changed", http.HandlerFunc(mailController.SendEmailChangedHandler))
+	muxRouter.Handle("/api/v1/mail/send-account-locked", http.HandlerFunc(mailController.SendAccountLockedHandler))
+	muxRouter.Handle("/api/v1/mail/send-org-invite", http.HandlerFunc(mailController.SendOrgInviteHandler))
 
 	return muxRouter
 }
@@ -0,0 +1,58 @@
+package mail
+
+import (
+	"encoding/json"
+	"net/http"
+	entityMail "retarget/internal/mail-service/entity/mail"
+	entity "retarget/pkg/entity"
+	"retarget/pkg/utils/validator"
+
+	"github.com/mailru/easyjson"
+)
+
+type AccountLockedRequest struct {
+	Email string `json:"email" validate:"email,required"`
+	Until string `json:"until" validate:"required,max=64"`
+}
+
+// SendAccountLockedHandler сообщает владельцу о временной блокировке входа
+func (c *MailController) SendAccountLockedHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		resp := entity.NewResponse(true, "Method Not Allowed")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	var req AccountLockedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		resp := entity.NewResponse(true, "Invalid request body")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	errorMessages, err := validator.ValidateStruct(req)
+	if err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		resp := entity.NewResponse(true, errorMessages)
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	if err := c.mailUsecase.SendAccountLockedMail(entityMail.ACCOUNT_LOCKED, req.Email, req.Until); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		resp := entity.NewResponse(true, "Ошибка, повторите отправку позже")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := entity.NewResponse(false, "Sent")
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
@@ -0,0 +1,82 @@
+package mail
+
+import (
+	"encoding/json"
+	"net/http"
+	entityMail "retarget/internal/mail-service/entity/mail"
+	entity "retarget/pkg/entity"
+	"retarget/pkg/utils/validator"
+	"strings"
+
+	"github.com/mailru/easyjson"
+)
+
+// CodeRequest — в письме о сбросе пароля приходит длинный токен, а не 6 цифр
+type CodeRequest struct {
+	Email string `json:"email" validate:"email,required"`
+	Code  string `json:"code" validate:"required,min=6,max=64"`
+}
+
+// SendRecoveryCodeHandler отправляет токен сброса забытого пароля
+func (c *MailController) SendRecoveryCodeHandler(w http.ResponseWriter, r *http.Request) {
+	c.sendCode(w, r, entityMail.RESET_PASSWORD)
+}
+
+// SendEditPasswordCodeHandler отправляет код подтверждения смены пароля
+func (c *MailController) SendEditPasswordCodeHandler(w http.ResponseWriter, r *http.Request) {
+	c.sendCode(w, r, entityMail.EDIT_PASSWORD)
+}
+
+// SendEditEmailCodeHandler отправляет код подтверждения смены почты (на старый и на новый адрес)
+func (c *MailController) SendEditEmailCodeHandler(w http.ResponseWriter, r *http.Request) {
+	c.sendCode(w, r, entityMail.EDIT_EMAIL)
+}
+
+func (c *MailController) sendCode(w http.ResponseWriter, r *http.Request, operation int) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		resp := entity.NewResponse(true, "Method Not Allowed")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	var codeRequest CodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&codeRequest); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		resp := entity.NewResponse(true, "Invalid request body")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	errorMessages, err := validator.ValidateStruct(codeRequest)
+	if err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		resp := entity.NewResponse(true, errorMessages)
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	if err := c.mailUsecase.SendCodeMail(operation, codeRequest.Email, codeRequest.Code); err != nil {
+		if strings.HasPrefix(err.Error(), "5") {
+			w.WriteHeader(http.StatusBadRequest)
+			resp := entity.NewResponse(true, "Такой почты не существует")
+			//nolint:errcheck
+			easyjson.MarshalToWriter(&resp, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+		resp := entity.NewResponse(true, "Ошибка, повторите отправку позже")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := entity.NewResponse(false, "Sent")
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
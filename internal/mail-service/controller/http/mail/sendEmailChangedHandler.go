@@ -0,0 +1,58 @@
+package mail
+
+import (
+	"encoding/json"
+	"net/http"
+	entityMail "retarget/internal/mail-service/entity/mail"
+	entity "retarget/pkg/entity"
+	"retarget/pkg/utils/validator"
+
+	"github.com/mailru/easyjson"
+)
+
+type EmailChangedRequest struct {
+	Email    string `json:"email" validate:"email,required"`
+	NewEmail string `json:"new_email" validate:"email,required"`
+}
+
+// SendEmailChangedHandler уведомляет старый адрес о смене почты аккаунта
+func (c *MailController) SendEmailChangedHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		resp := entity.NewResponse(true, "Method Not Allowed")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	var req EmailChangedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		resp := entity.NewResponse(true, "Invalid request body")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	errorMessages, err := validator.ValidateStruct(req)
+	if err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		resp := entity.NewResponse(true, errorMessages)
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	if err := c.mailUsecase.SendEmailChangedMail(entityMail.EMAIL_CHANGED, req.Email, req.NewEmail); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		resp := entity.NewResponse(true, "Ошибка, повторите отправку позже")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := entity.NewResponse(false, "Sent")
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
@@ -0,0 +1,60 @@
+package mail
+
+import (
+	"encoding/json"
+	"net/http"
+	entityMail "retarget/internal/mail-service/entity/mail"
+	entity "retarget/pkg/entity"
+	"retarget/pkg/utils/validator"
+
+	"github.com/mailru/easyjson"
+)
+
+type OrgInviteRequest struct {
+	Email        string `json:"email" validate:"email,required"`
+	Organization string `json:"organization" validate:"required,max=100"`
+	Role         string `json:"role" validate:"required,oneof=owner manager analyst billing"`
+	Token        string `json:"token" validate:"required,min=6,max=64"`
+}
+
+// SendOrgInviteHandler отправляет приглашение в организацию
+func (c *MailController) SendOrgInviteHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		resp := entity.NewResponse(true, "Method Not Allowed")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	var req OrgInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		resp := entity.NewResponse(true, "Invalid request body")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	errorMessages, err := validator.ValidateStruct(req)
+	if err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		resp := entity.NewResponse(true, errorMessages)
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	if err := c.mailUsecase.SendOrgInviteMail(entityMail.ORG_INVITE, req.Email, req.Organization, req.Role, req.Token); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		resp := entity.NewResponse(true, "Ошибка, повторите отправку позже")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	resp := entity.NewResponse(false, "Sent")
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
@@ -3,9 +3,9 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"retarget/pkg/entity/notice"
-	"strconv"
 
 	"retarget/internal/mail-service/entity/mail"
 	usecaseMail "retarget/internal/mail-service/usecase/mail"
@@ -15,7 +15,8 @@ import (
 )
 
 const (
-	HREF = "https://re-target.ru/profile"
+	HREF           = "https://re-target.ru/profile"
+	STATEMENT_HREF = "https://re-target.ru/api/v1/payment/statements/%d/pdf"
 )
 
 type Consumer struct {
@@ -37,7 +38,7 @@ func NewConsumer(brokers []string, group string, topic goka.Stream, mailUseCase
 		log.Printf("\n[KAFKA] NEW MESSAGE\n"+
 			"User ID: %d\n"+
 			"Type: %d\n"+
-			"Amount: %f\n"+
+			"Amount: %s\n"+
 			"-------------------------",
 			event.UserID, event.Type, event.Amount)
 
@@ -53,7 +54,20 @@ func NewConsumer(brokers []string, group string, topic goka.Stream, mailUseCase
 				log.Printf("Email successfully sent to %s", email)
 			}
 		case notice.TopUpedBalance:
-			if err := mailUseCase.SendTopUpBalanceMail(mail.TOPUP_BALANCE, email, username, strconv.FormatFloat(event.Amount, 'f', 2, 64)); err != nil {
+			if err := mailUseCase.SendTopUpBalanceMail(mail.TOPUP_BALANCE, email, username, eventAmount(event)); err != nil {
+				log.Printf("Failed to send email: %v", err)
+			} else {
+				log.Printf("Email successfully sent to %s", email)
+			}
+		case notice.StatementReady:
+			href := fmt.Sprintf(STATEMENT_HREF, event.StatementID)
+			if err := mailUseCase.SendStatementMail(mail.STATEMENT, email, username, event.Period, href); err != nil {
+				log.Printf("Failed to send email: %v", err)
+			} else {
+				log.Printf("Email successfully sent to %s", email)
+			}
+		case notice.AutoRechargeFailed:
+			if err := mailUseCase.SendAutoRechargeFailedMail(mail.AUTO_RECHARGE, email, username, eventAmount(event), event.Reason, HREF); err != nil {
 				log.Printf("Failed to send email: %v", err)
 			} else {
 				log.Printf("Email successfully sent to %s", email)
@@ -87,3 +101,11 @@ func (c *Consumer) Run(ctx context.Context) {
 		log.Printf("error running processor: %v", err)
 	}
 }
+
+// eventAmount — сумма события с точностью до копейки
+func eventAmount(event notice.NoticeEvent) string {
+	if event.Amount == nil {
+		return "0.00"
+	}
+	return event.Amount.Money().String()
+}
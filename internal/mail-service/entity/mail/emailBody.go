@@ -12,6 +12,12 @@ const (
 	EDIT_PASSWORD  = 3
 	TOPUP_BALANCE  = 4
 	LOW_BALANCE    = 5
+	STATEMENT      = 6
+	AUTO_RECHARGE  = 7
+	EDIT_EMAIL     = 8
+	EMAIL_CHANGED  = 9
+	ACCOUNT_LOCKED = 10
+	ORG_INVITE     = 11
 	TEMPLATES_DIR  = "./internal/mail-service/entity/mail/templates" // TODO: Вынести в конфиг это
 )
 
@@ -33,6 +39,12 @@ func init() {
 		EDIT_PASSWORD:  "editPasswordEmail",
 		TOPUP_BALANCE:  "topUpedBalanceEmail",
 		LOW_BALANCE:    "lowBalanceEmail",
+		STATEMENT:      "statementReadyEmail",
+		AUTO_RECHARGE:  "autoRechargeFailedEmail",
+		EDIT_EMAIL:     "editEmailEmail",
+		EMAIL_CHANGED:  "emailChangedEmail",
+		ACCOUNT_LOCKED: "accountLockedEmail",
+		ORG_INVITE:     "orgInviteEmail",
 	}
 
 	for operation, name := range templates {
@@ -112,3 +124,120 @@ func GetEmailTopUpBody(operation int, username, amount string) (string, error) {
 
 	return result, nil
 }
+
+func GetEmailStatementBody(operation int, username, period, href string) (string, error) {
+	tmpl, ok := emailTemplates[operation]
+	if !ok {
+		return "", nil
+	}
+
+	if tmpl == nil {
+		return "", nil
+	}
+
+	onInsert := map[string]interface{}{
+		"Username": username,
+		"Period":   period,
+		"Href":     href,
+	}
+
+	result, err := tmpl.Exec(onInsert)
+	if err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+func GetEmailAutoRechargeBody(operation int, username, amount, reason, href string) (string, error) {
+	tmpl, ok := emailTemplates[operation]
+	if !ok {
+		return "", nil
+	}
+
+	if tmpl == nil {
+		return "", nil
+	}
+
+	onInsert := map[string]interface{}{
+		"Username": username,
+		"Amount":   amount,
+		"Reason":   reason,
+		"Href":     href,
+	}
+
+	result, err := tmpl.Exec(onInsert)
+	if err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+func GetEmailChangedBody(operation int, email string) (string, error) {
+	tmpl, ok := emailTemplates[operation]
+	if !ok {
+		return "", nil
+	}
+
+	if tmpl == nil {
+		return "", nil
+	}
+
+	onInsert := map[string]interface{}{
+		"Email": email,
+	}
+
+	result, err := tmpl.Exec(onInsert)
+	if err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+func GetAccountLockedBody(operation int, until string) (string, error) {
+	tmpl, ok := emailTemplates[operation]
+	if !ok {
+		return "", nil
+	}
+
+	if tmpl == nil {
+		return "", nil
+	}
+
+	onInsert := map[string]interface{}{
+		"Until": until,
+	}
+
+	result, err := tmpl.Exec(onInsert)
+	if err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+func GetOrgInviteBody(operation int, organization, role, token string) (string, error) {
+	tmpl, ok := emailTemplates[operation]
+	if !ok {
+		return "", nil
+	}
+
+	if tmpl == nil {
+		return "", nil
+	}
+
+	onInsert := map[string]interface{}{
+		"Organization": organization,
+		"Role":         role,
+		"Token":        token,
+	}
+
+	result, err := tmpl.Exec(onInsert)
+	if err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
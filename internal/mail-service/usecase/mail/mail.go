@@ -2,9 +2,11 @@ package mail
 
 import (
 	"errors"
+	entityAuth "retarget/internal/auth-service/entity/auth"
 	repoAuth "retarget/internal/auth-service/repo/auth"
 	entityMail "retarget/internal/mail-service/entity/mail"
 	repoMail "retarget/internal/mail-service/repo/mail"
+	"strings"
 )
 
 type MailUsecaseInterface interface {
@@ -79,6 +81,125 @@ func (m *MailUsecase) SendTopUpBalanceMail(operation int, to, username, amount s
 	return nil
 }
 
+func (m *MailUsecase) SendStatementMail(operation int, to, username, period, href string) error {
+	var subject string
+	var body string
+	var err error
+
+	switch operation {
+	case entityMail.STATEMENT:
+		subject = "Выписка по счёту ReTarget за " + period
+		body, err = entityMail.GetEmailStatementBody(entityMail.STATEMENT, username, period, href)
+	default:
+		return errors.New("undefined operation")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	msg := "To: " + to + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"Content-Type: text/html; charset=UTF-8\r\n" +
+		"\r\n" + body
+
+	err = m.mailRepository.Send(to, msg)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *MailUsecase) SendAutoRechargeFailedMail(operation int, to, username, amount, reason, href string) error {
+	var subject string
+	var body string
+	var err error
+
+	switch operation {
+	case entityMail.AUTO_RECHARGE:
+		subject = "Автопополнение баланса ReTarget не прошло"
+		body, err = entityMail.GetEmailAutoRechargeBody(entityMail.AUTO_RECHARGE, username, amount, reason, href)
+	default:
+		return errors.New("undefined operation")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	msg := "To: " + to + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"Content-Type: text/html; charset=UTF-8\r\n" +
+		"\r\n" + body
+
+	err = m.mailRepository.Send(to, msg)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SendEmailChangedMail предупреждает старый адрес о том, что почта аккаунта сменилась на newEmail
+func (m *MailUsecase) SendEmailChangedMail(operation int, to, newEmail string) error {
+	var subject string
+	var body string
+	var err error
+
+	switch operation {
+	case entityMail.EMAIL_CHANGED:
+		subject = "Почта аккаунта ReTarget изменена"
+		body, err = entityMail.GetEmailChangedBody(entityMail.EMAIL_CHANGED, newEmail)
+	default:
+		return errors.New("undefined operation")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	msg := "To: " + to + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"Content-Type: text/html; charset=UTF-8\r\n" +
+		"\r\n" + body
+
+	err = m.mailRepository.Send(to, msg)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SendAccountLockedMail сообщает владельцу, что вход в аккаунт заблокирован
+// после серии неверных паролей, и до какого времени
+func (m *MailUsecase) SendAccountLockedMail(operation int, to, until string) error {
+	var subject string
+	var body string
+	var err error
+
+	switch operation {
+	case entityMail.ACCOUNT_LOCKED:
+		subject = "Вход в аккаунт ReTarget временно заблокирован"
+		body, err = entityMail.GetAccountLockedBody(entityMail.ACCOUNT_LOCKED, until)
+	default:
+		return errors.New("undefined operation")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	msg := "To: " + to + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"Content-Type: text/html; charset=UTF-8\r\n" +
+		"\r\n" + body
+
+	err = m.mailRepository.Send(to, msg)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 func (m *MailUsecase) SendCodeMail(operation int, to, code string) error {
 	var subject string
 	var body string
@@ -94,6 +215,9 @@ func (m *MailUsecase) SendCodeMail(operation int, to, code string) error {
 	case entityMail.EDIT_PASSWORD:
 		subject = "Изменение пароля в ReTarget"
 		body, err = entityMail.GetEmailBody(entityMail.EDIT_PASSWORD, code)
+	case entityMail.EDIT_EMAIL:
+		subject = "Изменение почты в ReTarget"
+		body, err = entityMail.GetEmailBody(entityMail.EDIT_EMAIL, code)
 	default:
 		return errors.New("undefined operation")
 	}
@@ -119,5 +243,44 @@ func (m *MailUsecase) GetUserByID(user_id int) (string, string, string, error) {
 	if err != nil {
 		return "froloff1830@gmail.com", "Error if parse Username", "Balance", err
 	}
+	// у аккаунта организации служебная почта, письма получает её владелец
+	if strings.HasSuffix(user.Email, "@"+entityAuth.OrgAccountEmailDomain) {
+		name, email, err := m.userRepository.GetOrgContact(user_id, "UNIMPIMENTED request_id")
+		if err != nil {
+			return "", user.Username, user.Balance.String(), err
+		}
+		return email, name, user.Balance.String(), nil
+	}
 	return user.Email, user.Username, user.Balance.String(), nil
 }
+
+// SendOrgInviteMail передаёт приглашение в организацию с ролью role и токеном,
+// которым его принимают
+func (m *MailUsecase) SendOrgInviteMail(operation int, to, organization, role, token string) error {
+	var subject string
+	var body string
+	var err error
+
+	switch operation {
+	case entityMail.ORG_INVITE:
+		subject = "Приглашение в организацию на ReTarget"
+		body, err = entityMail.GetOrgInviteBody(entityMail.ORG_INVITE, organization, role, token)
+	default:
+		return errors.New("undefined operation")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	msg := "To: " + to + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"Content-Type: text/html; charset=UTF-8\r\n" +
+		"\r\n" + body
+
+	err = m.mailRepository.Send(to, msg)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -1,22 +1,35 @@
 package mailApp
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"retarget/configs"
 	payAppHttp "retarget/internal/pay-service/controller"
 	payMiddleware "retarget/internal/pay-service/controller/http/middleware"
+	"retarget/internal/pay-service/entity"
 	server "retarget/internal/pay-service/grpc"
 	repoPay "retarget/internal/pay-service/repo"
-	repoAttempt "retarget/internal/pay-service/repo/attempt"
+	repoBudget "retarget/internal/pay-service/repo/budget"
+	repoGateway "retarget/internal/pay-service/repo/gateway"
 	repoNotice "retarget/internal/pay-service/repo/notice"
+	repoStorage "retarget/internal/pay-service/repo/storage"
 	usecasePay "retarget/internal/pay-service/usecase"
+	"retarget/internal/pay-service/usecase/statement"
 	authenticate "retarget/pkg/middleware/auth"
+	"retarget/pkg/utils/throttle"
 	"time"
 
 	"go.uber.org/zap"
 )
 
+const (
+	// fakeGatewayCompleteAfter — через сколько фейковый шлюз подтверждает платежи и выплаты
+	fakeGatewayCompleteAfter = 3 * time.Second
+	defaultStatementBucket   = "statements"
+)
+
 func Run(cfg *configs.Config, logger *zap.SugaredLogger) {
 	authenticator, err := authenticate.NewAuthenticator(cfg.AuthRedis.EndPoint, cfg.AuthRedis.Password, cfg.AuthRedis.Database)
 	if err != nil {
@@ -34,11 +47,82 @@ func Run(cfg *configs.Config, logger *zap.SugaredLogger) {
 		logger.Fatal("failed to initialize NoticeRepository")
 	}
 	defer noticeRepository.Close()
-	attemptRepository := repoAttempt.NewAttemptRepository(cfg.AttemptRedis.EndPoint, cfg.AttemptRedis.Password, cfg.AttemptRedis.Database, 24*time.Hour, cfg.AttemptRedis.Attempts)
 
-	httpClient := &http.Client{Timeout: 10 * time.Second}
+	budgetRepository := repoBudget.NewBudgetRepository(cfg.AttemptRedis.EndPoint, cfg.AttemptRedis.Password, cfg.AttemptRedis.Database, usecasePay.BudgetHoldTTL)
+	defer func() {
+		if err := budgetRepository.CloseConnection(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	limiter, err := throttle.NewLimiter(cfg.AttemptRedis.EndPoint, cfg.AttemptRedis.Password, cfg.AttemptRedis.Database)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	defer func() {
+		if err := limiter.CloseConnection(); err != nil {
+			log.Printf("error closing attempt limiter: %v", err)
+		}
+	}()
+
+	takeRate := entity.DecimalFromKopecks(0)
+	if cfg.Billing.TakeRate != "" {
+		takeRate, err = entity.ParseTakeRate(cfg.Billing.TakeRate)
+		if err != nil {
+			log.Fatal("invalid PLATFORM_TAKE_RATE: ", err)
+		}
+	}
+
+	payoutPolicy, err := parsePayoutPolicy(cfg.Payout)
+	if err != nil {
+		log.Fatal("invalid payout config: ", err)
+	}
 
-	payUsecase := usecasePay.NewPayUsecase(logger, payRepository, noticeRepository, attemptRepository, cfg.Yoo.ShopID, cfg.Yoo.SecretKey, cfg.Yoo.UUID, cfg.Yoo.AccountNumber, httpClient)
+	var paymentGateway repoGateway.PaymentGateway
+	var fakeGateway *repoGateway.FakeGateway
+	switch cfg.Billing.Gateway {
+	case "", "yookassa":
+		httpClient := &http.Client{Timeout: 10 * time.Second}
+		paymentGateway = repoGateway.NewYooKassaGateway(cfg.Yoo.ShopID, cfg.Yoo.SecretKey, cfg.Yoo.UUID, httpClient)
+	case "fake":
+		logger.Warn("using fake payment gateway, no real money will be moved")
+		fakeGateway = repoGateway.NewFakeGateway(fakeGatewayCompleteAfter)
+		paymentGateway = fakeGateway
+	default:
+		log.Fatal("unknown PAYMENT_GATEWAY: ", cfg.Billing.Gateway)
+	}
+
+	statementBucket := cfg.Statement.Bucket
+	if statementBucket == "" {
+		statementBucket = defaultStatementBucket
+	}
+	statementStorage := repoStorage.NewStatementStorage(cfg.Minio.EndPoint, cfg.Minio.AccessKeyID, cfg.Minio.SecretAccesKey, cfg.Minio.Token, cfg.Minio.UseSSL == "true", statementBucket)
+
+	payUsecase := usecasePay.NewPayUsecase(logger, payRepository, noticeRepository, budgetRepository, takeRate, payoutPolicy, paymentGateway, cfg.Yoo.AccountNumber,
+		statementStorage, statement.NewRenderer(cfg.Statement.FontPath), limiter)
+	if fakeGateway != nil {
+		fakeGateway.Notify = func(event, objectID string) {
+			var n entity.YooNotification
+			n.Event = event
+			n.Object.ID = objectID
+			if err := payUsecase.HandleYooNotification(n); err != nil {
+				logger.Errorw("fake gateway notification failed", "event", event, "object_id", objectID, "error", err)
+			}
+		}
+	}
+
+	if cfg.Billing.RatesFile != "" {
+		if _, err := payUsecase.LoadRatesFromFile(cfg.Billing.RatesFile); err != nil {
+			log.Fatal("invalid EXCHANGE_RATES_FILE: ", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go payUsecase.RunBudgetReconciler(ctx, usecasePay.BudgetFlushInterval)
+	go payUsecase.RunLedgerReconciler(ctx, usecasePay.LedgerReconcileInterval)
+	go payUsecase.RunPaymentSweeper(ctx, usecasePay.PaymentSweepInterval)
+	go payUsecase.RunStatementGenerator(ctx, usecasePay.StatementGenerateInterval)
 
 	go func() {
 		log.Println("Starting gRPC server...")
@@ -48,3 +132,37 @@ func Run(cfg *configs.Config, logger *zap.SugaredLogger) {
 	mux := payAppHttp.SetupRoutes(authenticator, payUsecase)
 	log.Fatal(http.ListenAndServe(":8022", payMiddleware.CORS(mux)))
 }
+
+func parsePayoutPolicy(cfg configs.PayoutConfig) (entity.PayoutPolicy, error) {
+	policy := entity.DefaultPayoutPolicy()
+
+	amounts := []struct {
+		name  string
+		value string
+		dst   *entity.Decimal
+	}{
+		{"PAYOUT_MIN_AMOUNT", cfg.MinAmount, &policy.MinAmount},
+		{"PAYOUT_DAILY_LIMIT", cfg.DailyLimit, &policy.DailyLimit},
+		{"PAYOUT_MONTHLY_LIMIT", cfg.MonthlyLimit, &policy.MonthlyLimit},
+		{"PAYOUT_APPROVAL_THRESHOLD", cfg.ApprovalThreshold, &policy.ApprovalThreshold},
+	}
+	for _, a := range amounts {
+		if a.value == "" {
+			continue
+		}
+		v, err := entity.ParseAmount(a.value)
+		if err != nil || v.Sign() < 0 {
+			return policy, fmt.Errorf("%s: invalid amount %q", a.name, a.value)
+		}
+		*a.dst = v
+	}
+
+	if cfg.MinAccountAge != "" {
+		age, err := time.ParseDuration(cfg.MinAccountAge)
+		if err != nil {
+			return policy, fmt.Errorf("PAYOUT_MIN_ACCOUNT_AGE: %w", err)
+		}
+		policy.MinAccountAge = age
+	}
+	return policy, nil
+}
@@ -0,0 +1,134 @@
+package payment
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	payEntity "retarget/internal/pay-service/entity"
+	"retarget/pkg/entity"
+
+	"github.com/gorilla/mux"
+)
+
+func autoRechargeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, payEntity.ErrAutoRechargeNotFound),
+		errors.Is(err, payEntity.ErrNoPaymentMethod):
+		return http.StatusNotFound
+	case errors.Is(err, payEntity.ErrInvalidAutoRecharge):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeAutoRechargeError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(autoRechargeErrorStatus(err))
+	//nolint:errcheck
+	json.NewEncoder(w).Encode(entity.NewResponse(true, err.Error()))
+}
+
+func (h *PaymentController) GetAutoRecharge(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+		return
+	}
+
+	recharge, err := h.PaymentUsecase.GetAutoRecharge(userSession.UserID)
+	if err != nil {
+		writeAutoRechargeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, recharge)
+}
+
+// SetAutoRecharge включает автопополнение: «когда баланс ниже threshold, списать amount»
+func (h *PaymentController) SetAutoRecharge(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+		return
+	}
+
+	var req struct {
+		Threshold       payEntity.Decimal `json:"threshold"`
+		Amount          payEntity.Decimal `json:"amount"`
+		MonthlyCap      payEntity.Decimal `json:"monthly_cap"`
+		PaymentMethodID string            `json:"payment_method_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Invalid Request Body"))
+		return
+	}
+
+	recharge, err := h.PaymentUsecase.SetAutoRecharge(userSession.UserID, payEntity.AutoRecharge{
+		Enabled:         true,
+		Threshold:       req.Threshold,
+		Amount:          req.Amount,
+		MonthlyCap:      req.MonthlyCap,
+		PaymentMethodID: req.PaymentMethodID,
+	})
+	if err != nil {
+		writeAutoRechargeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, recharge)
+}
+
+func (h *PaymentController) DisableAutoRecharge(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+		return
+	}
+
+	if err := h.PaymentUsecase.DisableAutoRecharge(userSession.UserID); err != nil {
+		writeAutoRechargeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PaymentController) GetPaymentMethods(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+		return
+	}
+
+	methods, err := h.PaymentUsecase.GetPaymentMethods(userSession.UserID)
+	if err != nil {
+		writeAutoRechargeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, methods)
+}
+
+// DeletePaymentMethod забывает сохранённый способ оплаты и выключает автопополнение с ним
+func (h *PaymentController) DeletePaymentMethod(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+		return
+	}
+
+	if err := h.PaymentUsecase.DeletePaymentMethod(userSession.UserID, mux.Vars(r)["methodid"]); err != nil {
+		writeAutoRechargeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
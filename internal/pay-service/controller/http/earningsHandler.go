@@ -0,0 +1,84 @@
+package payment
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	payEntity "retarget/internal/pay-service/entity"
+	"retarget/pkg/entity"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+func takeRateErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, payEntity.ErrInvalidTakeRate):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GetEarnings отдаёт владельцу слотов доход с показов: полную стоимость,
+// удержанную комиссию площадки и сумму, зачисленную на баланс
+func (h *PaymentController) GetEarnings(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+		return
+	}
+
+	earnings, err := h.PaymentUsecase.GetEarnings(userSession.UserID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error fetching earnings: "+err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	//nolint:errcheck
+	json.NewEncoder(w).Encode(earnings)
+}
+
+// SetPublisherTakeRate задаёт владельцу слотов индивидуальную комиссию площадки:
+// {"take_rate": "0.10"}
+func (h *PaymentController) SetPublisherTakeRate(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+		return
+	}
+
+	publisherID, err := strconv.Atoi(mux.Vars(r)["publisherid"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Invalid publisher ID"))
+		return
+	}
+	var req struct {
+		TakeRate payEntity.Decimal `json:"take_rate"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Invalid Request Body"))
+		return
+	}
+
+	if err := h.PaymentUsecase.SetPublisherTakeRate(userSession.UserID, publisherID, req.TakeRate); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(takeRateErrorStatus(err))
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, err.Error()))
+		return
+	}
+	writeJSON(w, http.StatusOK, req)
+}
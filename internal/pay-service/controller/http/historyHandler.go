@@ -0,0 +1,198 @@
+package payment
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	payEntity "retarget/internal/pay-service/entity"
+	payment "retarget/internal/pay-service/usecase"
+	"retarget/pkg/entity"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const historyDateLayout = "2006-01-02"
+
+func historyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, payEntity.ErrInvalidHistoryFilter),
+		errors.Is(err, payEntity.ErrInvalidHistoryCursor),
+		errors.Is(err, payment.ErrHistoryExportTooLarge):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// queryList собирает значения параметра, переданные и повтором, и через запятую
+func queryList(q url.Values, key string) []string {
+	var values []string
+	for _, raw := range q[key] {
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// parseHistoryTime принимает RFC 3339 или дату; дата в to означает конец этого дня
+func parseHistoryTime(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(historyDateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid date %q", payEntity.ErrInvalidHistoryFilter, value)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+func parseHistoryFilter(userID int, q url.Values) (payEntity.HistoryFilter, error) {
+	filter := payEntity.HistoryFilter{UserID: userID, Desc: true}
+
+	for _, raw := range queryList(q, "type") {
+		t, err := payEntity.ParseHistoryType(raw)
+		if err != nil {
+			return filter, err
+		}
+		filter.Types = append(filter.Types, t)
+	}
+	for _, raw := range queryList(q, "status") {
+		status, err := payEntity.ParseTransactionStatus(raw)
+		if err != nil {
+			return filter, err
+		}
+		filter.Statuses = append(filter.Statuses, status)
+	}
+
+	var err error
+	if v := q.Get("from"); v != "" {
+		if filter.From, err = parseHistoryTime(v, false); err != nil {
+			return filter, err
+		}
+	}
+	if v := q.Get("to"); v != "" {
+		if filter.To, err = parseHistoryTime(v, true); err != nil {
+			return filter, err
+		}
+	}
+
+	amounts := []struct {
+		key string
+		dst **payEntity.Decimal
+	}{
+		{"min_amount", &filter.MinAmount},
+		{"max_amount", &filter.MaxAmount},
+	}
+	for _, a := range amounts {
+		v := q.Get(a.key)
+		if v == "" {
+			continue
+		}
+		var amount payEntity.Decimal
+		if err := amount.ParseFromString(v); err != nil {
+			return filter, fmt.Errorf("%w: invalid %s", payEntity.ErrInvalidHistoryFilter, a.key)
+		}
+		*a.dst = &amount
+	}
+
+	if filter.Sort, err = payEntity.ParseHistorySort(q.Get("sort")); err != nil {
+		return filter, err
+	}
+	switch q.Get("order") {
+	case "", "desc":
+	case "asc":
+		filter.Desc = false
+	default:
+		return filter, fmt.Errorf("%w: order must be asc or desc", payEntity.ErrInvalidHistoryFilter)
+	}
+
+	if v := q.Get("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil || filter.Limit <= 0 {
+			return filter, fmt.Errorf("%w: invalid limit", payEntity.ErrInvalidHistoryFilter)
+		}
+	}
+	if v := q.Get("cursor"); v != "" {
+		cursor, err := payEntity.DecodeHistoryCursor(v)
+		if err != nil {
+			return filter, err
+		}
+		filter.After = &cursor
+	}
+	return filter, nil
+}
+
+// GetTransactions отдаёт историю операций постранично или целиком в CSV (format=csv).
+// Фильтры: type, status, from, to, min_amount, max_amount; порядок: sort, order; страница: limit, cursor
+func (h *PaymentController) GetTransactions(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+		return
+	}
+
+	filter, err := parseHistoryFilter(userSession.UserID, r.URL.Query())
+	if err != nil {
+		writeHistoryError(w, err)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "csv" {
+		items, err := h.PaymentUsecase.ExportTransactionHistory(filter)
+		if err != nil {
+			writeHistoryError(w, err)
+			return
+		}
+		writeHistoryCSV(w, items)
+		return
+	}
+
+	page, err := h.PaymentUsecase.GetTransactionHistory(filter)
+	if err != nil {
+		writeHistoryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func writeHistoryError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(historyErrorStatus(err))
+	//nolint:errcheck
+	json.NewEncoder(w).Encode(entity.NewResponse(true, err.Error()))
+}
+
+func writeHistoryCSV(w http.ResponseWriter, items []payEntity.HistoryItem) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="transactions.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	//nolint:errcheck
+	cw.Write([]string{"created_at", "type", "status", "amount", "reference", "raw_type", "source", "id"})
+	for _, item := range items {
+		//nolint:errcheck
+		cw.Write([]string{
+			item.CreatedAt.UTC().Format(time.RFC3339),
+			string(item.Type),
+			item.Status,
+			item.Amount.String(),
+			item.Reference,
+			item.RawType,
+			item.Source,
+			strconv.FormatInt(item.ID, 10),
+		})
+	}
+	cw.Flush()
+}
@@ -0,0 +1,144 @@
+package payment
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	payEntity "retarget/internal/pay-service/entity"
+	payment "retarget/internal/pay-service/usecase"
+	"retarget/pkg/entity"
+	"strconv"
+
+	"github.com/mailru/easyjson"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	maxIdempotentBody    = 1 << 20
+)
+
+// idempotencyRecorder пишет ответ клиенту и одновременно запоминает его для повторов
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *idempotencyRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *idempotencyRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+// Idempotent выполняет запрос с заголовком Idempotency-Key не больше одного
+// раза: повтор получает сохранённый ответ, в том числе ошибку, повтор с другим
+// телом отклоняется.
+// Должен стоять после AuthMiddleware — ключи живут в пространстве пользователя,
+// а в организации — в пространстве её участника: один участник не получит
+// ответ, сохранённый для другого
+func (h *PaymentController) Idempotent(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+		if !ok {
+			writeIdempotencyError(w, http.StatusInternalServerError, "Error of authenticator")
+			return
+		}
+		userID, actorID := userSession.UserID, userSession.ActorID
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBody))
+		if err != nil {
+			writeIdempotencyError(w, http.StatusBadRequest, "Invalid Request Body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := payEntity.IdempotencyRequestHash(r.Method, r.URL.Path, body)
+		stored, err := h.PaymentUsecase.BeginIdempotent(userID, actorID, key, hash)
+		switch {
+		case errors.Is(err, payment.ErrInvalidIdempotencyKey):
+			writeIdempotencyError(w, http.StatusBadRequest, err.Error())
+			return
+		case errors.Is(err, payEntity.ErrIdempotencyKeyReused):
+			writeIdempotencyError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		case errors.Is(err, payEntity.ErrIdempotencyKeyInProgress):
+			writeIdempotencyError(w, http.StatusConflict, err.Error())
+			return
+		case err != nil:
+			writeIdempotencyError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		if stored != nil {
+			if stored.ContentType != "" {
+				w.Header().Set("Content-Type", stored.ContentType)
+			}
+			w.Header().Set("Idempotent-Replayed", strconv.FormatBool(true))
+			w.WriteHeader(stored.StatusCode)
+			//nolint:errcheck
+			w.Write(stored.Body)
+			return
+		}
+
+		// если обработчик упал, ключ освобождается, иначе он так и останется
+		// занятым и повтор получит ErrIdempotencyKeyInProgress
+		defer func() {
+			if p := recover(); p != nil {
+				h.PaymentUsecase.AbortIdempotent(userID, actorID, key)
+				panic(p)
+			}
+		}()
+
+		rec := &idempotencyRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+
+		// ответ 5xx тоже сохраняется: до ошибки обработчик мог уже списать деньги
+		// или создать платёж, и повтор с тем же ключом не должен сделать это ещё раз
+		h.PaymentUsecase.CompleteIdempotent(userID, actorID, key, rec.status, w.Header().Get("Content-Type"), rec.body.Bytes())
+	})
+}
+
+// gatewayIdempotenceKey выводит ключ для платёжного шлюза из клиентского
+// Idempotency-Key, чтобы повтор не создал второй платёж даже мимо нашего кэша ответов.
+// Без заголовка возвращает пустую строку
+func gatewayIdempotenceKey(r *http.Request, userID int, prefix string) string {
+	key := r.Header.Get(IdempotencyKeyHeader)
+	if key == "" {
+		return ""
+	}
+	// у участников организации ключи свои, как и в Idempotent
+	if userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext); ok && userSession.ActorID != 0 {
+		key = strconv.Itoa(userSession.ActorID) + ":" + key
+	}
+	sum := sha256.Sum256([]byte(strconv.Itoa(userID) + ":" + r.URL.Path + ":" + key))
+	return prefix + hex.EncodeToString(sum[:])[:32]
+}
+
+func writeIdempotencyError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	resp := entity.NewResponse(true, message)
+	//nolint:errcheck
+	easyjson.MarshalToWriter(&resp, w)
+}
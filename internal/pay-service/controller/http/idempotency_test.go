@@ -0,0 +1,236 @@
+package payment
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	payEntity "retarget/internal/pay-service/entity"
+	"retarget/internal/pay-service/repo"
+	"retarget/internal/pay-service/repo/gateway"
+	usecase "retarget/internal/pay-service/usecase"
+	response "retarget/pkg/entity"
+	auth "retarget/pkg/middleware/auth"
+	"retarget/pkg/middleware/auth/authtest"
+)
+
+func idempotentRequest(key, body string) *http.Request {
+	ctx := context.WithValue(context.Background(), response.UserContextKey, response.UserContext{UserID: 5})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/payment/withdraw", strings.NewReader(body)).WithContext(ctx)
+	req.Header.Set(IdempotencyKeyHeader, key)
+	return req
+}
+
+func setupIdempotent(t *testing.T) (*PaymentController, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	payRepo := repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar())
+	uc := usecase.NewPayUsecase(zap.NewNop().Sugar(), payRepo, nil, nil, payEntity.Decimal{}, payEntity.DefaultPayoutPolicy(), gateway.NewFakeGateway(0), "", nil, nil, nil)
+	return NewPaymentController(uc), mock
+}
+
+func Test_Idempotent_StoresAndReplays(t *testing.T) {
+	ctrl, mock := setupIdempotent(t)
+	calls := 0
+	handler := ctrl.Idempotent(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		//nolint:errcheck
+		w.Write([]byte(`{"ok":true}`))
+	}))
+
+	body := `{"amount":100}`
+	hash := payEntity.IdempotencyRequestHash(http.MethodPost, "/api/v1/payment/withdraw", []byte(body))
+
+	mock.ExpectExec("INSERT INTO idempotency_key").
+		WithArgs(5, 0, "k1", hash).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE idempotency_key").
+		WithArgs(http.StatusAccepted, "application/json", []byte(`{"ok":true}`), 5, 0, "k1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, idempotentRequest("k1", body))
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+
+	mock.ExpectExec("INSERT INTO idempotency_key").
+		WithArgs(5, 0, "k1", hash).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT request_hash, status_code").
+		WithArgs(5, 0, "k1").
+		WillReturnRows(sqlmock.NewRows([]string{"request_hash", "status_code", "content_type", "response_body", "created_at"}).
+			AddRow(hash, http.StatusAccepted, "application/json", []byte(`{"ok":true}`), time.Now()))
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, idempotentRequest("k1", body))
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Equal(t, `{"ok":true}`, rr.Body.String())
+	assert.Equal(t, "true", rr.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, 1, calls)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_Idempotent_RejectsDifferentBody(t *testing.T) {
+	ctrl, mock := setupIdempotent(t)
+	handler := ctrl.Idempotent(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler must not be called")
+	}))
+
+	mock.ExpectExec("INSERT INTO idempotency_key").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT request_hash, status_code").
+		WillReturnRows(sqlmock.NewRows([]string{"request_hash", "status_code", "content_type", "response_body", "created_at"}).
+			AddRow("other", http.StatusAccepted, "application/json", []byte(`{}`), time.Now()))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, idempotentRequest("k1", `{"amount":500}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_Idempotent_StoresServerError(t *testing.T) {
+	ctrl, mock := setupIdempotent(t)
+	handler := ctrl.Idempotent(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+
+	mock.ExpectExec("INSERT INTO idempotency_key").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE idempotency_key").
+		WithArgs(http.StatusInternalServerError, sqlmock.AnyArg(), []byte("boom\n"), 5, 0, "k1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, idempotentRequest("k1", `{}`))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_Idempotent_ReleasesKeyOnPanic(t *testing.T) {
+	ctrl, mock := setupIdempotent(t)
+	handler := ctrl.Idempotent(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	mock.ExpectExec("INSERT INTO idempotency_key").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM idempotency_key").
+		WithArgs(5, 0, "k1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.PanicsWithValue(t, "boom", func() {
+		handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest("k1", `{}`))
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// участники одной организации не делят ключи: одинаковый ключ второго
+// участника — новый запрос, а не повтор чужого
+func Test_Idempotent_ScopesKeysByOrgMember(t *testing.T) {
+	ctrl, mock := setupIdempotent(t)
+	calls := 0
+	handler := ctrl.Idempotent(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	body := `{"amount":100}`
+	hash := payEntity.IdempotencyRequestHash(http.MethodPost, "/api/v1/payment/withdraw", []byte(body))
+	for _, actorID := range []int{8, 9} {
+		mock.ExpectExec("INSERT INTO idempotency_key").
+			WithArgs(50, actorID, "k1", hash).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE idempotency_key").
+			WithArgs(http.StatusAccepted, sqlmock.AnyArg(), sqlmock.AnyArg(), 50, actorID, "k1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		req := idempotentRequest("k1", body)
+		req = req.WithContext(context.WithValue(req.Context(), response.UserContextKey,
+			response.UserContext{UserID: 50, ActorID: actorID, OrgRole: auth.OrgRoleBilling}))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusAccepted, rr.Code)
+		assert.Empty(t, rr.Header().Get("Idempotent-Replayed"))
+	}
+	assert.Equal(t, 2, calls)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_Idempotent_WithoutHeader(t *testing.T) {
+	handler := makeHandler().Idempotent(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/payment/withdraw", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+}
+
+// каждый изменяющий маршрут пользователя проходит через Idempotent (уведомления
+// шлюза и внутренний учёт кликов идут без сессии):
+// повтор ключа с другим телом отклоняется до обработчика
+func Test_SetupPaymentRoutes_MutatingRoutesAreIdempotent(t *testing.T) {
+	ctrl, mock := setupIdempotent(t)
+	router := SetupPaymentRoutes(authtest.Authenticator(t), ctrl.PaymentUsecase).(*mux.Router)
+	vars := regexp.MustCompile(`\{[^}]+\}`)
+
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil || template == "/api/v1/payment/webhooks/yookassa" {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+		path := vars.ReplaceAllStringFunc(template, func(v string) string {
+			if strings.HasSuffix(v, "approve|reject}") {
+				return "approve"
+			}
+			if strings.HasSuffix(v, "enable|disable}") {
+				return "disable"
+			}
+			return "1"
+		})
+
+		for _, method := range methods {
+			if method == http.MethodGet {
+				continue
+			}
+			mock.ExpectExec("INSERT INTO idempotency_key").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery("SELECT request_hash, status_code").
+				WillReturnRows(sqlmock.NewRows([]string{"request_hash", "status_code", "content_type", "response_body", "created_at"}).
+					AddRow("other", http.StatusOK, "application/json", []byte(`{}`), time.Now()))
+
+			code := 0
+			for _, role := range auth.Roles {
+				req := httptest.NewRequest(method, path, strings.NewReader("{}"))
+				req.AddCookie(&http.Cookie{Name: "session_id", Value: authtest.SessionID(role)})
+				req.Header.Set(IdempotencyKeyHeader, "k1")
+				rr := httptest.NewRecorder()
+				router.ServeHTTP(rr, req)
+				if code = rr.Code; code != http.StatusForbidden {
+					break
+				}
+			}
+			assert.Equal(t, http.StatusUnprocessableEntity, code, "%s %s", method, template)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,12 +2,10 @@ package payment
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
 	model "retarget/internal/pay-service/easyjsonModels"
 	"retarget/pkg/entity"
 	response "retarget/pkg/entity"
-	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/mailru/easyjson"
@@ -18,7 +16,9 @@ import (
 func (h *PaymentController) GetUserBalance(w http.ResponseWriter, r *http.Request) {
 	requestID := r.Context().Value(response.СtxKeyRequestID{}).(string)
 	cookie, err := r.Cookie("session_id")
-	if err != nil || cookie.Value == "" {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	// по API-ключу запрос приходит без cookie
+	if (err != nil || cookie.Value == "") && (!ok || userSession.APIKeyID == 0) {
 		w.WriteHeader(http.StatusUnauthorized)
 		//nolint:errcheck
 		// json.NewEncoder(w).Encode(entity.NewResponse(true, "Invalid Cookie"))
@@ -28,7 +28,6 @@ func (h *PaymentController) GetUserBalance(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
 	if !ok {
 		w.WriteHeader(http.StatusInternalServerError)
 		//nolint:errcheck
@@ -75,6 +74,7 @@ func (h *PaymentController) GetUserBalance(w http.ResponseWriter, r *http.Reques
 	}
 }
 
+// TopUpAccount — ручное зачисление администратора пользователю без платежа
 func (h *PaymentController) TopUpAccount(w http.ResponseWriter, r *http.Request) {
 	requestID := r.Context().Value(response.СtxKeyRequestID{}).(string)
 	cookie, err := r.Cookie("session_id")
@@ -111,7 +111,7 @@ func (h *PaymentController) TopUpAccount(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if req.Amount <= 0 {
+	if req.Amount.Money().Sign() <= 0 {
 		w.WriteHeader(http.StatusBadRequest)
 		//nolint:errcheck
 		// json.NewEncoder(w).Encode(entity.NewResponse(true, "Invalid Amount"))
@@ -120,8 +120,17 @@ func (h *PaymentController) TopUpAccount(w http.ResponseWriter, r *http.Request)
 		easyjson.MarshalToWriter(&resp, w)
 		return
 	}
+	if req.UserID <= 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		resp := entity.NewResponse(true, "Invalid User")
+		//nolint:errcheck
+		easyjson.MarshalToWriter(&resp, w)
+		return
+	}
+
+	transactionID := uuid.New().String()
 
-	if err = h.PaymentUsecase.TopUpBalance(userID, req.Amount, requestID); err != nil {
+	if err = h.PaymentUsecase.AdjustBalance(userID, req.UserID, req.Amount, transactionID, requestID); err != nil {
 		// handleTopUpError(w, err)
 		w.WriteHeader(http.StatusBadRequest)
 		//nolint:errcheck
@@ -132,8 +141,6 @@ func (h *PaymentController) TopUpAccount(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	transactionID := uuid.New().String()
-
 	responseData := model.TransactionResponse{
 		TransactionID: transactionID,
 		Status:        "completed",
@@ -149,11 +156,13 @@ func (h *PaymentController) TopUpAccount(w http.ResponseWriter, r *http.Request)
 
 func (c *PaymentController) CreateTransaction(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		Value          string `json:"value"`
-		Currency       string `json:"currency"`
-		ReturnURL      string `json:"return_url"`
-		Description    string `json:"description"`
-		IdempotenceKey string `json:"idempotence_key"`
+		Value             string `json:"value"`
+		Currency          string `json:"currency"`
+		ReturnURL         string `json:"return_url"`
+		Description       string `json:"description"`
+		IdempotenceKey    string `json:"idempotence_key"`
+		PromoCode         string `json:"promo_code"`
+		SavePaymentMethod bool   `json:"save_payment_method"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "invalid request payload", http.StatusBadRequest)
@@ -179,6 +188,10 @@ func (c *PaymentController) CreateTransaction(w http.ResponseWriter, r *http.Req
 	}
 	userID := userSession.UserID
 
+	if req.IdempotenceKey == "" {
+		req.IdempotenceKey = gatewayIdempotenceKey(r, userID, "pay")
+	}
+
 	confirmationURL, err := c.PaymentUsecase.CreateYooMoneyPayment(
 		userID,
 		req.Value,
@@ -186,9 +199,16 @@ func (c *PaymentController) CreateTransaction(w http.ResponseWriter, r *http.Req
 		req.ReturnURL,
 		req.Description,
 		req.IdempotenceKey,
+		req.PromoCode,
+		req.SavePaymentMethod,
 	)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		// неизвестная валюта, валюта без курса или негодный промокод — ошибка запроса, остальное — наша
+		status := promoErrorStatus(err)
+		if status == http.StatusInternalServerError {
+			status = rateErrorStatus(err)
+		}
+		http.Error(w, err.Error(), status)
 		return
 	}
 
@@ -240,107 +260,3 @@ func (h *PaymentController) GetTransactionByID(w http.ResponseWriter, r *http.Re
 func (h *PaymentController) RegUserActivity(w http.ResponseWriter, r *http.Request) {
 
 }
-
-func (c *PaymentController) WithdrawFunds(w http.ResponseWriter, r *http.Request) {
-	requestID := r.Context().Value(response.СtxKeyRequestID{}).(string)
-
-	// теперь принимаем return_url
-	var req struct {
-		Amount      float64 `json:"amount"`
-		ReturnURL   string  `json:"return_url"`
-		Description string  `json:"description"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request payload", http.StatusBadRequest)
-		return
-	}
-
-	if req.Amount < 10.0 {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(entity.NewResponse(true, "Minimum withdrawal amount is 10.00"))
-		return
-	}
-
-	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
-	if !ok {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
-		return
-	}
-	userID := userSession.UserID
-
-	idemKey := fmt.Sprintf("payout_%s_%d_%s", requestID, userID, uuid.New().String())
-
-	// redirect-flow
-	redirectURL, err := c.PaymentUsecase.CreateYooMoneyPayoutRedirect(
-		userID, req.Amount, req.Description, req.ReturnURL, idemKey,
-	)
-	if err != nil {
-		status := http.StatusInternalServerError
-		if strings.Contains(err.Error(), "insufficient funds") {
-			status = http.StatusBadRequest
-		}
-		w.WriteHeader(status)
-		json.NewEncoder(w).Encode(entity.NewResponse(true, err.Error()))
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusAccepted)
-	json.NewEncoder(w).Encode(map[string]string{
-		"confirmation_url": redirectURL,
-	})
-}
-
-func (c *PaymentController) WithdrawFundsRedirect(w http.ResponseWriter, r *http.Request) {
-	// _ := r.Context().Value(response.СtxKeyRequestID{}).(string)
-
-	var req struct {
-		Amount      float64 `json:"amount"`
-		ReturnURL   string  `json:"return_url"`
-		Description string  `json:"description"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request payload", http.StatusBadRequest)
-		return
-	}
-
-	if req.Amount < 10.0 {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(entity.NewResponse(true, "Minimum withdrawal amount is 10.00"))
-		return
-	}
-
-	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
-	if !ok {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
-		return
-	}
-	userID := userSession.UserID
-
-	// Генерируем более короткий идемпотентный ключ
-	// Используем только первые 8 символов UUID и ID пользователя
-	shortUUID := strings.ReplaceAll(uuid.New().String()[:8], "-", "")
-	idemKey := fmt.Sprintf("p%d%s", userID, shortUUID)
-
-	// redirect-flow
-	redirectURL, err := c.PaymentUsecase.CreateYooMoneyPayoutRedirect(
-		userID, req.Amount, req.Description, req.ReturnURL, idemKey,
-	)
-	if err != nil {
-		status := http.StatusInternalServerError
-		if strings.Contains(err.Error(), "insufficient funds") {
-			status = http.StatusBadRequest
-		}
-		w.WriteHeader(status)
-		json.NewEncoder(w).Encode(entity.NewResponse(true, err.Error()))
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusAccepted)
-	json.NewEncoder(w).Encode(map[string]string{
-		"confirmation_url": redirectURL,
-	})
-}
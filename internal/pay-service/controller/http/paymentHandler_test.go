@@ -9,7 +9,9 @@ import (
 	"go.uber.org/zap"
 	"net/http"
 	"net/http/httptest"
+	payEntity "retarget/internal/pay-service/entity"
 	"retarget/internal/pay-service/repo"
+	"retarget/internal/pay-service/repo/gateway"
 	"testing"
 
 	usecase "retarget/internal/pay-service/usecase"
@@ -128,7 +130,7 @@ func Test_Router_MethodRestrictions(t *testing.T) {
 	authn := &auth.Authenticator{}
 	mux := SetupPaymentRoutes(authn, &usecase.PaymentUsecase{})
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/payment/transactions", nil)
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/payment/transactions", nil)
 	rr := httptest.NewRecorder()
 	mux.ServeHTTP(rr, req)
 	assert.Equal(t, 405, rr.Code)
@@ -145,7 +147,7 @@ func Test_PostBalance_NoCookie(t *testing.T) {
 
 func Test_GetTopUp_NoCookie(t *testing.T) {
 	mux := SetupPaymentRoutes(&auth.Authenticator{}, &usecase.PaymentUsecase{})
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/payment/accounts/topup", nil)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/payment/accounts/topup", nil)
 	req = req.WithContext(context.WithValue(req.Context(), response.СtxKeyRequestID{}, "rid"))
 	rr := httptest.NewRecorder()
 
@@ -201,7 +203,7 @@ func Test_GetUserBalance_Success(t *testing.T) {
 	defer db.Close()
 
 	payRepo := repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar())
-	uc := usecase.NewPayUsecase(zap.NewNop().Sugar(), payRepo, nil, nil, "", "", &http.Client{})
+	uc := usecase.NewPayUsecase(zap.NewNop().Sugar(), payRepo, nil, nil, payEntity.Decimal{}, payEntity.DefaultPayoutPolicy(), gateway.NewFakeGateway(0), "", nil, nil, nil)
 	ctrl := NewPaymentController(uc)
 
 	ctx := context.WithValue(context.Background(), response.СtxKeyRequestID{}, "req1")
@@ -210,19 +212,16 @@ func Test_GetUserBalance_Success(t *testing.T) {
 	req.AddCookie(&http.Cookie{Name: "session_id", Value: "sess5"})
 	rr := httptest.NewRecorder()
 
-	mock.ExpectQuery("SELECT id, transaction_id, user_id, amount, type, status, created_at FROM transaction").
-		WithArgs(5).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "transaction_id", "user_id", "amount", "type", "status", "created_at"}))
-	mock.ExpectQuery("SELECT balance FROM auth_user").
+	mock.ExpectQuery(`SELECT balance \+ promo_balance FROM auth_user`).
 		WithArgs(5).
-		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(250.75))
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("250.75"))
 
 	ctrl.GetUserBalance(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
-	var resp map[string]float64
+	var resp map[string]string
 	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
-	assert.Equal(t, 250.75, resp["balance"])
+	assert.Equal(t, "250.75", resp["balance"])
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -235,14 +234,16 @@ func Test_HeadBalance_NoCookie(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, rr.Code)
 }
 
-func Test_HeadTopUp_NoCookie(t *testing.T) {
+func Test_HeadTopUp_MethodNotAllowed(t *testing.T) {
 	mux := SetupPaymentRoutes(&auth.Authenticator{}, &usecase.PaymentUsecase{})
-	req := httptest.NewRequest(http.MethodHead, "/api/v1/payment/accounts/topup", nil)
-	req = req.WithContext(context.WithValue(req.Context(), response.СtxKeyRequestID{}, "rid"))
-	rr := httptest.NewRecorder()
-	mux.ServeHTTP(rr, req)
-
-	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	for _, method := range []string{http.MethodGet, http.MethodHead} {
+		req := httptest.NewRequest(method, "/api/v1/payment/accounts/topup", nil)
+		req = req.WithContext(context.WithValue(req.Context(), response.СtxKeyRequestID{}, "rid"))
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code, method)
+	}
 }
 
 func Test_RegUserActivity_Direct(t *testing.T) {
@@ -253,12 +254,13 @@ func Test_RegUserActivity_Direct(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr.Code)
 	assert.Zero(t, rr.Body.Len())
 }
-func Test_GetTransactions_List_MethodNotAllowed(t *testing.T) {
+func Test_GetTransactions_List_NoCookie(t *testing.T) {
 	mux := SetupPaymentRoutes(&auth.Authenticator{}, &usecase.PaymentUsecase{})
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/payment/transactions", nil)
+	req = req.WithContext(context.WithValue(req.Context(), response.СtxKeyRequestID{}, "rid"))
 	rr := httptest.NewRecorder()
 	mux.ServeHTTP(rr, req)
-	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
 }
 
 func Test_POST_Transactions_Clicks(t *testing.T) {
@@ -309,13 +311,13 @@ func Test_GetTransactionByID_Success(t *testing.T) {
 	defer db.Close()
 
 	payRepo := repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar())
-	uc := usecase.NewPayUsecase(zap.NewNop().Sugar(), payRepo, nil, nil, "", "", &http.Client{})
+	uc := usecase.NewPayUsecase(zap.NewNop().Sugar(), payRepo, nil, nil, payEntity.Decimal{}, payEntity.DefaultPayoutPolicy(), gateway.NewFakeGateway(0), "", nil, nil, nil)
 	ctrl := NewPaymentController(uc)
 
-	cols := []string{"id", "transaction_id", "user_id", "amount", "type", "status", "created_at"}
-	mock.ExpectQuery("SELECT * FROM transaction").
+	cols := []string{"id", "transaction_id", "user_id", "amount", "type", "status", "created_at", "currency"}
+	mock.ExpectQuery("SELECT id, transaction_id, user_id, amount, type, status, created_at, currency FROM transaction").
 		WithArgs("tx1").
-		WillReturnRows(sqlmock.NewRows(cols).AddRow(1, "tx1", 7, 99.9, "yoo_money", 1, nil))
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(1, "tx1", 7, "99.90", "yoo_money", 1, nil, "RUB"))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/payment/transactions/tx1", nil)
 	req = mux.SetURLVars(req, map[string]string{"transactionid": "tx1"})
@@ -334,10 +336,10 @@ func Test_GetTransactionByID_NotFound(t *testing.T) {
 	defer db.Close()
 
 	payRepo := repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar())
-	uc := usecase.NewPayUsecase(zap.NewNop().Sugar(), payRepo, nil, nil, "", "", &http.Client{})
+	uc := usecase.NewPayUsecase(zap.NewNop().Sugar(), payRepo, nil, nil, payEntity.Decimal{}, payEntity.DefaultPayoutPolicy(), gateway.NewFakeGateway(0), "", nil, nil, nil)
 	ctrl := NewPaymentController(uc)
 
-	mock.ExpectQuery("SELECT \\* FROM transaction").
+	mock.ExpectQuery("SELECT id, transaction_id, user_id, amount, type, status, created_at, currency FROM transaction").
 		WithArgs("nope").
 		WillReturnError(repo.ErrUserNotFound)
 
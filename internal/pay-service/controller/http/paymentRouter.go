@@ -2,6 +2,7 @@ package payment
 
 import (
 	"net/http"
+	payEntity "retarget/internal/pay-service/entity"
 	payment "retarget/internal/pay-service/usecase"
 	logger "retarget/pkg/middleware"
 	authenticate "retarget/pkg/middleware/auth"
@@ -20,16 +21,53 @@ func NewPaymentController(PaymentUsecase *payment.PaymentUsecase) *PaymentContro
 func SetupPaymentRoutes(authenticator *authenticate.Authenticator, PaymentUsecase *payment.PaymentUsecase) http.Handler {
 	muxRouter := mux.NewRouter()
 	PaymentController := NewPaymentController(PaymentUsecase)
+	// в организации деньгами распоряжаются владелец и бухгалтерия, выводит их только владелец
+	billing := authenticate.RequireOrgRole(authenticate.OrgRoleOwner, authenticate.OrgRoleBilling)
+	ownerOnly := authenticate.RequireOrgRole(authenticate.OrgRoleOwner)
+	// свои деньги у рекламодателей и площадок, /admin — у администраторов платформы.
+	// Право billing:admin проверяется только здесь, usecase его не повторяет
+	payments := authenticate.Require(authenticate.PermPayments)
+	billingAdmin := authenticate.Require(authenticate.PermBillingAdmin)
+	// чужие выписки скачивают администраторы: право из сессии обработчик передаёт в usecase
+	statementReaders := authenticate.Require(authenticate.PermPayments, authenticate.PermBillingAdmin)
 	// middleware.AuthMiddleware(authUsecase)()
-	muxRouter.Handle("/api/v1/payment/balance", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(PaymentController.GetUserBalance))))
-	muxRouter.Handle("/api/v1/payment/accounts/topup", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(PaymentController.TopUpAccount))))
+	muxRouter.Handle("/api/v1/payment/balance", logger.LogMiddleware(authenticate.ScopedAuthMiddleware(authenticator, authenticate.ScopePaymentsRead)(payments(http.HandlerFunc(PaymentController.GetUserBalance)))))
+	muxRouter.Handle("/api/v1/payment/balances", logger.LogMiddleware(authenticate.ScopedAuthMiddleware(authenticator, authenticate.ScopePaymentsRead)(payments(http.HandlerFunc(PaymentController.GetBalances))))).Methods("GET")
+	muxRouter.Handle("/api/v1/payment/balances/exchange", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(payments(billing(PaymentController.Idempotent(http.HandlerFunc(PaymentController.ExchangeBalance))))))).Methods("POST")
+	muxRouter.Handle("/api/v1/payment/rates", logger.LogMiddleware(authenticate.ScopedAuthMiddleware(authenticator, authenticate.ScopePaymentsRead)(payments(http.HandlerFunc(PaymentController.GetRates))))).Methods("GET")
+	muxRouter.Handle("/api/v1/payment/admin/rates", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(billingAdmin(PaymentController.Idempotent(http.HandlerFunc(PaymentController.SetRates)))))).Methods("PUT")
+	muxRouter.Handle("/api/v1/payment/admin/publishers/{publisherid:[0-9]+}/take-rate", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(billingAdmin(PaymentController.Idempotent(http.HandlerFunc(PaymentController.SetPublisherTakeRate)))))).Methods("PUT")
+	muxRouter.Handle("/api/v1/payment/earnings", logger.LogMiddleware(authenticate.ScopedAuthMiddleware(authenticator, authenticate.ScopePaymentsRead)(payments(http.HandlerFunc(PaymentController.GetEarnings))))).Methods("GET")
+	// зачисление без платежа — ручная операция администратора, сами пользователи пополняют баланс через /transactions
+	muxRouter.Handle("/api/v1/payment/accounts/topup", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(billingAdmin(PaymentController.Idempotent(http.HandlerFunc(PaymentController.TopUpAccount)))))).Methods("POST")
 	muxRouter.Handle("/api/v1/payment/transactions/clicks", logger.LogMiddleware(http.HandlerFunc(PaymentController.RegUserActivity)))
+	muxRouter.Handle("/api/v1/payment/webhooks/yookassa", logger.LogMiddleware(http.HandlerFunc(PaymentController.YooKassaWebhook))).Methods("POST")
 	//muxRouter.Handle("/api/v1/payment/transactions/{transactionid}/confirm", http.HandlerFunc(skibidi))
 
-	muxRouter.Handle("/api/v1/payment/transactions/{transactionid}", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(PaymentController.GetTransactionByID))))
-	muxRouter.Handle("/api/v1/payment/transactions", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(PaymentController.CreateTransaction)))).Methods("POST")
-	muxRouter.Handle("/api/v1/payment/withdraw", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(PaymentController.WithdrawFunds)))).Methods("POST")
-	muxRouter.Handle("/api/v1/payment/withdraw/redirect", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(PaymentController.WithdrawFundsRedirect)))).Methods("POST")
+	muxRouter.Handle("/api/v1/payment/transactions/{transactionid}", logger.LogMiddleware(authenticate.ScopedAuthMiddleware(authenticator, authenticate.ScopePaymentsRead)(payments(http.HandlerFunc(PaymentController.GetTransactionByID)))))
+	muxRouter.Handle("/api/v1/payment/transactions", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(payments(billing(PaymentController.Idempotent(http.HandlerFunc(PaymentController.CreateTransaction))))))).Methods("POST")
+	muxRouter.Handle("/api/v1/payment/transactions", logger.LogMiddleware(authenticate.ScopedAuthMiddleware(authenticator, authenticate.ScopePaymentsRead)(payments(http.HandlerFunc(PaymentController.GetTransactions))))).Methods("GET")
+	muxRouter.Handle("/api/v1/payment/payouts", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(payments(ownerOnly(authenticate.RequireTwoFactor(PaymentController.Throttled(payEntity.ThrottlePayout)(PaymentController.Idempotent(http.HandlerFunc(PaymentController.RequestPayout))))))))).Methods("POST")
+	muxRouter.Handle("/api/v1/payment/payouts", logger.LogMiddleware(authenticate.ScopedAuthMiddleware(authenticator, authenticate.ScopePaymentsRead)(payments(http.HandlerFunc(PaymentController.GetPayouts))))).Methods("GET")
+	muxRouter.Handle("/api/v1/payment/admin/payouts", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(billingAdmin(http.HandlerFunc(PaymentController.GetPendingPayouts))))).Methods("GET")
+	muxRouter.Handle("/api/v1/payment/admin/payouts/{payoutid:[0-9]+}/{action:approve|reject}", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(billingAdmin(authenticate.RequireTwoFactor(PaymentController.Idempotent(http.HandlerFunc(PaymentController.ReviewPayout))))))).Methods("POST")
+	muxRouter.Handle("/api/v1/payment/admin/refunds", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(billingAdmin(PaymentController.Idempotent(http.HandlerFunc(PaymentController.CreateRefund)))))).Methods("POST")
+	muxRouter.Handle("/api/v1/payment/admin/refunds", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(billingAdmin(http.HandlerFunc(PaymentController.GetRefunds))))).Methods("GET")
+	muxRouter.Handle("/api/v1/payment/promo", logger.LogMiddleware(authenticate.ScopedAuthMiddleware(authenticator, authenticate.ScopePaymentsRead)(payments(http.HandlerFunc(PaymentController.GetPromoSummary))))).Methods("GET")
+	muxRouter.Handle("/api/v1/payment/promo/redeem", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(payments(billing(PaymentController.Throttled(payEntity.ThrottlePromoRedeem)(PaymentController.Idempotent(http.HandlerFunc(PaymentController.RedeemPromoCode)))))))).Methods("POST")
+	muxRouter.Handle("/api/v1/payment/admin/promo-codes", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(billingAdmin(PaymentController.Idempotent(http.HandlerFunc(PaymentController.CreatePromoCode)))))).Methods("POST")
+	muxRouter.Handle("/api/v1/payment/admin/promo-codes", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(billingAdmin(http.HandlerFunc(PaymentController.GetPromoCodes))))).Methods("GET")
+	muxRouter.Handle("/api/v1/payment/admin/promo-codes/{codeid:[0-9]+}/{action:enable|disable}", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(billingAdmin(PaymentController.Idempotent(http.HandlerFunc(PaymentController.UpdatePromoCode)))))).Methods("POST")
+	muxRouter.Handle("/api/v1/payment/admin/promo-codes/{codeid:[0-9]+}/redemptions", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(billingAdmin(http.HandlerFunc(PaymentController.GetPromoCodeRedemptions))))).Methods("GET")
+	muxRouter.Handle("/api/v1/payment/statements", logger.LogMiddleware(authenticate.ScopedAuthMiddleware(authenticator, authenticate.ScopePaymentsRead)(payments(http.HandlerFunc(PaymentController.GetStatements))))).Methods("GET")
+	muxRouter.Handle("/api/v1/payment/statements", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(payments(billing(PaymentController.Idempotent(http.HandlerFunc(PaymentController.CreateStatement))))))).Methods("POST")
+	muxRouter.Handle("/api/v1/payment/statements/{statementid:[0-9]+}/{format:json|csv|pdf}", logger.LogMiddleware(authenticate.ScopedAuthMiddleware(authenticator, authenticate.ScopePaymentsRead)(statementReaders(http.HandlerFunc(PaymentController.DownloadStatement))))).Methods("GET")
+
+	muxRouter.Handle("/api/v1/payment/auto-recharge", logger.LogMiddleware(authenticate.ScopedAuthMiddleware(authenticator, authenticate.ScopePaymentsRead)(payments(http.HandlerFunc(PaymentController.GetAutoRecharge))))).Methods("GET")
+	muxRouter.Handle("/api/v1/payment/auto-recharge", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(payments(billing(PaymentController.Idempotent(http.HandlerFunc(PaymentController.SetAutoRecharge))))))).Methods("PUT")
+	muxRouter.Handle("/api/v1/payment/auto-recharge", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(payments(billing(PaymentController.Idempotent(http.HandlerFunc(PaymentController.DisableAutoRecharge))))))).Methods("DELETE")
+	muxRouter.Handle("/api/v1/payment/payment-methods", logger.LogMiddleware(authenticate.ScopedAuthMiddleware(authenticator, authenticate.ScopePaymentsRead)(payments(http.HandlerFunc(PaymentController.GetPaymentMethods))))).Methods("GET")
+	muxRouter.Handle("/api/v1/payment/payment-methods/{methodid}", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(payments(billing(PaymentController.Idempotent(http.HandlerFunc(PaymentController.DeletePaymentMethod))))))).Methods("DELETE")
 
 	return muxRouter
 }
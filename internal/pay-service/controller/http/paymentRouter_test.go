@@ -0,0 +1,67 @@
+package payment
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	usecase "retarget/internal/pay-service/usecase"
+	auth "retarget/pkg/middleware/auth"
+	"retarget/pkg/middleware/auth/authtest"
+)
+
+func TestSetupPaymentRoutes_AccessMatrix(t *testing.T) {
+	router := SetupPaymentRoutes(authtest.Authenticator(t), &usecase.PaymentUsecase{})
+	accounts := []int{auth.RoleAdvertiser, auth.RolePlatform}
+	admin := []int{auth.RoleAdmin}
+
+	authtest.AssertRoutes(t, router, []authtest.Route{
+		{Method: http.MethodGet, Path: "/api/v1/payment/balance", Roles: accounts},
+		{Method: http.MethodGet, Path: "/api/v1/payment/balances", Roles: accounts},
+		{Method: http.MethodPost, Path: "/api/v1/payment/balances/exchange", Roles: accounts},
+		{Method: http.MethodGet, Path: "/api/v1/payment/rates", Roles: accounts},
+		{Method: http.MethodGet, Path: "/api/v1/payment/earnings", Roles: accounts},
+		{Method: http.MethodGet, Path: "/api/v1/payment/transactions/abc", Roles: accounts},
+		{Method: http.MethodPost, Path: "/api/v1/payment/transactions", Roles: accounts},
+		{Method: http.MethodGet, Path: "/api/v1/payment/transactions", Roles: accounts},
+		{Method: http.MethodPost, Path: "/api/v1/payment/payouts", Roles: accounts},
+		{Method: http.MethodGet, Path: "/api/v1/payment/payouts", Roles: accounts},
+		{Method: http.MethodGet, Path: "/api/v1/payment/promo", Roles: accounts},
+		{Method: http.MethodPost, Path: "/api/v1/payment/promo/redeem", Roles: accounts},
+		{Method: http.MethodGet, Path: "/api/v1/payment/statements", Roles: accounts},
+		{Method: http.MethodPost, Path: "/api/v1/payment/statements", Roles: accounts},
+		{Method: http.MethodGet, Path: "/api/v1/payment/statements/1/csv", Roles: []int{auth.RoleAdvertiser, auth.RolePlatform, auth.RoleAdmin}},
+		{Method: http.MethodGet, Path: "/api/v1/payment/auto-recharge", Roles: accounts},
+		{Method: http.MethodPut, Path: "/api/v1/payment/auto-recharge", Roles: accounts},
+		{Method: http.MethodDelete, Path: "/api/v1/payment/auto-recharge", Roles: accounts},
+		{Method: http.MethodGet, Path: "/api/v1/payment/payment-methods", Roles: accounts},
+		{Method: http.MethodDelete, Path: "/api/v1/payment/payment-methods/pm1", Roles: accounts},
+
+		{Method: http.MethodPut, Path: "/api/v1/payment/admin/rates", Roles: admin},
+		// зачисление без платежа: рекламодатели и площадки получают 403
+		{Method: http.MethodPost, Path: "/api/v1/payment/accounts/topup", Roles: admin},
+		{Method: http.MethodPut, Path: "/api/v1/payment/admin/publishers/3/take-rate", Roles: admin},
+		{Method: http.MethodGet, Path: "/api/v1/payment/admin/payouts", Roles: admin},
+		{Method: http.MethodPost, Path: "/api/v1/payment/admin/payouts/1/approve", Roles: admin},
+		{Method: http.MethodPost, Path: "/api/v1/payment/admin/refunds", Roles: admin},
+		{Method: http.MethodGet, Path: "/api/v1/payment/admin/refunds", Roles: admin},
+		{Method: http.MethodPost, Path: "/api/v1/payment/admin/promo-codes", Roles: admin},
+		{Method: http.MethodGet, Path: "/api/v1/payment/admin/promo-codes", Roles: admin},
+		{Method: http.MethodPost, Path: "/api/v1/payment/admin/promo-codes/1/disable", Roles: admin},
+		{Method: http.MethodGet, Path: "/api/v1/payment/admin/promo-codes/1/redemptions", Roles: admin},
+	})
+}
+
+// вывод средств идёт только через заявки на выплату с лимитами, одобрением и вторым фактором
+func TestSetupPaymentRoutes_NoDirectWithdraw(t *testing.T) {
+	router := SetupPaymentRoutes(authtest.Authenticator(t), &usecase.PaymentUsecase{})
+	for _, path := range []string{"/api/v1/payment/withdraw", "/api/v1/payment/withdraw/redirect"} {
+		r := httptest.NewRequest(http.MethodPost, path, nil)
+		r.AddCookie(&http.Cookie{Name: "session_id", Value: authtest.SessionID(auth.RolePlatform)})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%s: expected 404, got %d", path, w.Code)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package payment
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	payEntity "retarget/internal/pay-service/entity"
+	payment "retarget/internal/pay-service/usecase"
+	"retarget/pkg/entity"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+func payoutErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, payEntity.ErrPayoutNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, payEntity.ErrPayoutTransition):
+		return http.StatusConflict
+	case errors.Is(err, payEntity.ErrInsufficientBalance),
+		errors.Is(err, payEntity.ErrPayoutBelowMinimum),
+		errors.Is(err, payEntity.ErrPayoutAccountTooNew),
+		errors.Is(err, payment.ErrInvalidPayoutDestination):
+		return http.StatusBadRequest
+	case errors.Is(err, payEntity.ErrPayoutDailyLimit),
+		errors.Is(err, payEntity.ErrPayoutMonthlyLimit):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writePayoutError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(payoutErrorStatus(err))
+	//nolint:errcheck
+	json.NewEncoder(w).Encode(entity.NewResponse(true, err.Error()))
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	//nolint:errcheck
+	json.NewEncoder(w).Encode(payload)
+}
+
+// RequestPayout заводит заявку на вывод средств
+func (h *PaymentController) RequestPayout(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+		return
+	}
+
+	var req struct {
+		Amount          payEntity.Decimal `json:"amount"`
+		DestinationType string            `json:"destination_type"`
+		Destination     string            `json:"destination"`
+		Description     string            `json:"description"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Amount.Money().Sign() <= 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Invalid Request Body"))
+		return
+	}
+
+	payout, err := h.PaymentUsecase.RequestPayout(userSession.UserID, req.Amount, req.DestinationType, req.Destination, req.Description)
+	if err != nil && payout.ID == 0 {
+		writePayoutError(w, err)
+		return
+	}
+	// заявка создана, но шлюз её отклонил: деньги уже вернулись на баланс
+	if err != nil {
+		writeJSON(w, http.StatusBadGateway, payout)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, payout)
+}
+
+func (h *PaymentController) GetPayouts(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+		return
+	}
+
+	payouts, err := h.PaymentUsecase.GetPayoutRequests(userSession.UserID)
+	if err != nil {
+		writePayoutError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, payouts)
+}
+
+func (h *PaymentController) GetPendingPayouts(w http.ResponseWriter, r *http.Request) {
+	payouts, err := h.PaymentUsecase.GetPendingPayouts()
+	if err != nil {
+		writePayoutError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, payouts)
+}
+
+// ReviewPayout одобряет или отклоняет заявку в зависимости от {action}
+func (h *PaymentController) ReviewPayout(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+		return
+	}
+
+	vars := mux.Vars(r)
+	payoutID, err := strconv.Atoi(vars["payoutid"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Invalid payout id"))
+		return
+	}
+
+	var payout payEntity.PayoutRequest
+	switch vars["action"] {
+	case "approve":
+		payout, err = h.PaymentUsecase.ApprovePayout(userSession.UserID, payoutID)
+	case "reject":
+		var req struct {
+			Reason string `json:"reason"`
+		}
+		//nolint:errcheck
+		json.NewDecoder(r.Body).Decode(&req)
+		payout, err = h.PaymentUsecase.RejectPayout(userSession.UserID, payoutID, req.Reason)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil && payout.Status != payEntity.PayoutFailed {
+		writePayoutError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, payout)
+}
@@ -0,0 +1,186 @@
+package payment
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	payEntity "retarget/internal/pay-service/entity"
+	"retarget/pkg/entity"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func promoErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, payEntity.ErrPromoNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, payEntity.ErrPromoExists),
+		errors.Is(err, payEntity.ErrPromoUserLimit),
+		errors.Is(err, payEntity.ErrPromoExhausted):
+		return http.StatusConflict
+	case errors.Is(err, payEntity.ErrInvalidPromoCode),
+		errors.Is(err, payEntity.ErrPromoInactive),
+		errors.Is(err, payEntity.ErrPromoExpired),
+		errors.Is(err, payEntity.ErrPromoNeedsTopUp),
+		errors.Is(err, payEntity.ErrPromoCurrency):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writePromoError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(promoErrorStatus(err))
+	//nolint:errcheck
+	json.NewEncoder(w).Encode(entity.NewResponse(true, err.Error()))
+}
+
+// CreatePromoCode заводит промокод
+func (h *PaymentController) CreatePromoCode(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+		return
+	}
+
+	var req struct {
+		Code           string              `json:"code"`
+		Kind           payEntity.PromoKind `json:"kind"`
+		Value          payEntity.Decimal   `json:"value"`
+		MaxBonus       *payEntity.Decimal  `json:"max_bonus"`
+		MaxRedemptions int                 `json:"max_redemptions"`
+		PerUserLimit   *int                `json:"per_user_limit"`
+		ExpiresAt      *time.Time          `json:"expires_at"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Invalid Request Body"))
+		return
+	}
+	// без явного лимита код погашается пользователем один раз
+	perUserLimit := 1
+	if req.PerUserLimit != nil {
+		perUserLimit = *req.PerUserLimit
+	}
+
+	promo, err := h.PaymentUsecase.CreatePromoCode(userSession.UserID, payEntity.PromoCode{
+		Code:           req.Code,
+		Kind:           req.Kind,
+		Value:          req.Value,
+		MaxBonus:       req.MaxBonus,
+		MaxRedemptions: req.MaxRedemptions,
+		PerUserLimit:   perUserLimit,
+		ExpiresAt:      req.ExpiresAt,
+	})
+	if err != nil {
+		writePromoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, promo)
+}
+
+func (h *PaymentController) GetPromoCodes(w http.ResponseWriter, r *http.Request) {
+	codes, err := h.PaymentUsecase.GetPromoCodes()
+	if err != nil {
+		writePromoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, codes)
+}
+
+// UpdatePromoCode включает или отключает код в зависимости от {action}
+func (h *PaymentController) UpdatePromoCode(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+		return
+	}
+
+	vars := mux.Vars(r)
+	codeID, err := strconv.Atoi(vars["codeid"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Invalid promo code id"))
+		return
+	}
+
+	promo, err := h.PaymentUsecase.SetPromoCodeActive(userSession.UserID, codeID, vars["action"] == "enable")
+	if err != nil {
+		writePromoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, promo)
+}
+
+// GetPromoCodeRedemptions отдаёт журнал погашений кода
+func (h *PaymentController) GetPromoCodeRedemptions(w http.ResponseWriter, r *http.Request) {
+	codeID, err := strconv.Atoi(mux.Vars(r)["codeid"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Invalid promo code id"))
+		return
+	}
+
+	redemptions, err := h.PaymentUsecase.GetPromoCodeRedemptions(codeID)
+	if err != nil {
+		writePromoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, redemptions)
+}
+
+// RedeemPromoCode начисляет промо-кредит по коду без пополнения
+func (h *PaymentController) RedeemPromoCode(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+		return
+	}
+
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Invalid Request Body"))
+		return
+	}
+
+	redemption, err := h.PaymentUsecase.RedeemPromoCode(userSession.UserID, req.Code)
+	if err != nil {
+		writePromoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, redemption)
+}
+
+// GetPromoSummary отдаёт промо-кредит пользователя и историю погашений
+func (h *PaymentController) GetPromoSummary(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+		return
+	}
+
+	summary, err := h.PaymentUsecase.GetPromoSummary(userSession.UserID)
+	if err != nil {
+		writePromoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, summary)
+}
@@ -0,0 +1,116 @@
+package payment
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	payEntity "retarget/internal/pay-service/entity"
+	"retarget/pkg/entity"
+)
+
+func rateErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, payEntity.ErrNoRateSnapshot):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, payEntity.ErrInvalidCurrency),
+		errors.Is(err, payEntity.ErrInvalidRate),
+		errors.Is(err, payEntity.ErrRateNotFound),
+		errors.Is(err, payEntity.ErrSameCurrency),
+		errors.Is(err, payEntity.ErrInvalidExchange),
+		errors.Is(err, payEntity.ErrInsufficientBalance):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeRateError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(rateErrorStatus(err))
+	//nolint:errcheck
+	json.NewEncoder(w).Encode(entity.NewResponse(true, err.Error()))
+}
+
+// GetBalances отдаёт остатки пользователя во всех валютах
+func (h *PaymentController) GetBalances(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+		return
+	}
+
+	balances, err := h.PaymentUsecase.GetBalances(userSession.UserID)
+	if err != nil {
+		writeRateError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, balances)
+}
+
+// ExchangeBalance переводит деньги между валютными счетами пользователя
+func (h *PaymentController) ExchangeBalance(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+		return
+	}
+
+	var req struct {
+		From   string            `json:"from"`
+		To     string            `json:"to"`
+		Amount payEntity.Decimal `json:"amount"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Amount.Dec == nil {
+		w.WriteHeader(http.StatusBadRequest)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Invalid Request Body"))
+		return
+	}
+
+	conversion, err := h.PaymentUsecase.ExchangeBalance(userSession.UserID, req.From, req.To, req.Amount)
+	if err != nil {
+		writeRateError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, conversion)
+}
+
+// GetRates отдаёт действующий снимок курсов
+func (h *PaymentController) GetRates(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := h.PaymentUsecase.GetRates()
+	if err != nil {
+		writeRateError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, snapshot)
+}
+
+// SetRates загружает новую таблицу курсов: {"USD": "92.5", "EUR": "100.1"}
+func (h *PaymentController) SetRates(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+		return
+	}
+
+	var rates map[string]payEntity.Decimal
+	if err := json.NewDecoder(r.Body).Decode(&rates); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Invalid Request Body"))
+		return
+	}
+
+	snapshot, err := h.PaymentUsecase.SetRates(userSession.UserID, rates)
+	if err != nil {
+		writeRateError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, snapshot)
+}
@@ -0,0 +1,77 @@
+package payment
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	payEntity "retarget/internal/pay-service/entity"
+	"retarget/pkg/entity"
+)
+
+func refundErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, payEntity.ErrRefundNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, payEntity.ErrAlreadyReversed):
+		return http.StatusConflict
+	case errors.Is(err, payEntity.ErrRefundNotAllowed),
+		errors.Is(err, payEntity.ErrRefundExceedsOriginal),
+		errors.Is(err, payEntity.ErrInsufficientBalance):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// CreateRefund возвращает пополнение через шлюз или отменяет списание за показ
+func (h *PaymentController) CreateRefund(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+		return
+	}
+
+	var req struct {
+		Kind       payEntity.RefundKind `json:"kind"`
+		OriginalID string               `json:"original_id"`
+		Amount     payEntity.Decimal    `json:"amount"`
+		Reason     string               `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.OriginalID == "" || req.Amount.Sign() < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Invalid Request Body"))
+		return
+	}
+
+	refund, err := h.PaymentUsecase.Refund(userSession.UserID, req.Kind, req.OriginalID, req.Amount, req.Reason)
+	if err != nil {
+		// возврат заведён, но шлюз его отклонил: деньги уже вернулись на баланс
+		if refund.Status == payEntity.RefundCanceled {
+			writeJSON(w, http.StatusBadGateway, refund)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(refundErrorStatus(err))
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, err.Error()))
+		return
+	}
+	writeJSON(w, http.StatusCreated, refund)
+}
+
+// GetRefunds отдаёт возвраты по исходной операции: ?kind=topup&original_id=...
+func (h *PaymentController) GetRefunds(w http.ResponseWriter, r *http.Request) {
+	kind := payEntity.RefundKind(r.URL.Query().Get("kind"))
+	refunds, err := h.PaymentUsecase.GetRefunds(kind, r.URL.Query().Get("original_id"))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(refundErrorStatus(err))
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, err.Error()))
+		return
+	}
+	writeJSON(w, http.StatusOK, refunds)
+}
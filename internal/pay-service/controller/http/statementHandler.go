@@ -0,0 +1,119 @@
+package payment
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	payEntity "retarget/internal/pay-service/entity"
+	payment "retarget/internal/pay-service/usecase"
+	"retarget/pkg/entity"
+	authenticate "retarget/pkg/middleware/auth"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+func statementErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, payEntity.ErrStatementNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, payEntity.ErrStatementPeriodOpen),
+		errors.Is(err, payEntity.ErrInvalidStatementFormat),
+		errors.Is(err, payment.ErrInvalidStatementPeriod):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeStatementError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statementErrorStatus(err))
+	//nolint:errcheck
+	json.NewEncoder(w).Encode(entity.NewResponse(true, err.Error()))
+}
+
+// GetStatements отдаёт список выписок пользователя, новые первыми
+func (h *PaymentController) GetStatements(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+		return
+	}
+
+	statements, err := h.PaymentUsecase.GetStatements(userSession.UserID)
+	if err != nil {
+		writeStatementError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, statements)
+}
+
+// CreateStatement генерирует выписку за завершённый месяц: {"period": "2025-04"}
+func (h *PaymentController) CreateStatement(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+		return
+	}
+
+	var req struct {
+		Period string `json:"period"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Invalid Request Body"))
+		return
+	}
+
+	statement, err := h.PaymentUsecase.RequestStatement(userSession.UserID, req.Period)
+	if err != nil {
+		writeStatementError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, statement)
+}
+
+// DownloadStatement отдаёт файл выписки в формате json, csv или pdf
+func (h *PaymentController) DownloadStatement(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+		return
+	}
+
+	vars := mux.Vars(r)
+	statementID, err := strconv.Atoi(vars["statementid"])
+	if err != nil {
+		writeStatementError(w, payEntity.ErrStatementNotFound)
+		return
+	}
+	format, err := payEntity.ParseStatementFormat(vars["format"])
+	if err != nil {
+		writeStatementError(w, err)
+		return
+	}
+
+	billingAdmin := authenticate.RoleHasPermission(userSession.Role, authenticate.PermBillingAdmin)
+	data, statement, err := h.PaymentUsecase.DownloadStatement(userSession.UserID, statementID, format, billingAdmin)
+	if err != nil {
+		writeStatementError(w, err)
+		return
+	}
+
+	fileName := fmt.Sprintf("statement-%s.%s", statement.PeriodStart.Format("2006-01"), format)
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	//nolint:errcheck
+	w.Write(data)
+}
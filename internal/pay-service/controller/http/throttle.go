@@ -0,0 +1,72 @@
+package payment
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"retarget/pkg/entity"
+	"retarget/pkg/utils/throttle"
+	"strconv"
+)
+
+// statusRecorder запоминает код ответа обработчика
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (rec *statusRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *statusRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	return rec.ResponseWriter.Write(b)
+}
+
+// Throttled ограничивает неудачные попытки action по аккаунту и адресу клиента.
+// Неудача — ответ 4xx, успех забывает неудачи аккаунта. Должен стоять после
+// AuthMiddleware и до Idempotent: иначе отказ лимитера сохранился бы как ответ
+// на ключ, а повторы сохранённых ответов засчитывались бы заново
+func (h *PaymentController) Throttled(action string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userSession, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+			if !ok {
+				w.WriteHeader(http.StatusInternalServerError)
+				//nolint:errcheck
+				json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+				return
+			}
+			var ip string
+			if clientIP := throttle.ClientIP(r); clientIP != nil {
+				ip = clientIP.String()
+			}
+
+			if err := h.PaymentUsecase.CheckThrottle(r.Context(), action, userSession.UserID, ip); err != nil {
+				if limited, ok := throttle.IsThrottled(err); ok {
+					w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(limited.RetryAfter.Seconds()))))
+				}
+				writeJSON(w, http.StatusTooManyRequests, entity.NewResponse(true, err.Error()))
+				return
+			}
+
+			rec := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+			if w.Header().Get("Idempotent-Replayed") != "" {
+				return
+			}
+			switch {
+			case rec.status == 0 || rec.status < http.StatusBadRequest:
+				h.PaymentUsecase.ThrottleSucceeded(r.Context(), action, userSession.UserID)
+			case rec.status < http.StatusInternalServerError && rec.status != http.StatusTooManyRequests:
+				h.PaymentUsecase.ThrottleFailed(r.Context(), action, userSession.UserID, ip)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package payment
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	payEntity "retarget/internal/pay-service/entity"
+	"retarget/internal/pay-service/repo/gateway"
+	usecase "retarget/internal/pay-service/usecase"
+	response "retarget/pkg/entity"
+	"retarget/pkg/utils/throttle"
+)
+
+func throttledRequest(userID int, remoteAddr string) *http.Request {
+	ctx := context.WithValue(context.Background(), response.UserContextKey, response.UserContext{UserID: userID})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/payment/promo/redeem", nil).WithContext(ctx)
+	req.RemoteAddr = remoteAddr
+	return req
+}
+
+func Test_Throttled_PromoRedeemFailures(t *testing.T) {
+	s := miniredis.RunT(t)
+	limiter, err := throttle.NewLimiter(s.Addr(), "", 0)
+	assert.NoError(t, err)
+	uc := usecase.NewPayUsecase(zap.NewNop().Sugar(), nil, nil, nil, payEntity.Decimal{}, payEntity.DefaultPayoutPolicy(), gateway.NewFakeGateway(0), "", nil, nil, limiter)
+	ctrl := NewPaymentController(uc)
+
+	status := http.StatusNotFound
+	calls := 0
+	handler := ctrl.Throttled(payEntity.ThrottlePromoRedeem)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(status)
+	}))
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	for i := 0; i < payEntity.PromoRedeemPolicy.FreeAttempts; i++ {
+		assert.Equal(t, http.StatusNotFound, serve(throttledRequest(5, "8.8.8.8:41000")).Code)
+	}
+	// неудача сверх бесплатных ставит задержку и аккаунту, и адресу
+	assert.Equal(t, http.StatusNotFound, serve(throttledRequest(5, "8.8.8.8:41000")).Code)
+	calls = 0
+
+	rr := serve(throttledRequest(5, "9.9.9.9:41000"))
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "2", rr.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusTooManyRequests, serve(throttledRequest(6, "8.8.8.8:41000")).Code)
+	assert.Equal(t, 0, calls)
+
+	// другой аккаунт с другого адреса не затронут, успех сбрасывает неудачи аккаунта
+	status = http.StatusCreated
+	assert.Equal(t, http.StatusCreated, serve(throttledRequest(6, "9.9.9.9:41000")).Code)
+	s.FastForward(payEntity.PromoRedeemPolicy.BaseDelay)
+	assert.Equal(t, http.StatusCreated, serve(throttledRequest(5, "9.9.9.9:41000")).Code)
+	status = http.StatusNotFound
+	assert.Equal(t, http.StatusNotFound, serve(throttledRequest(5, "9.9.9.9:41000")).Code)
+	assert.Equal(t, http.StatusNotFound, serve(throttledRequest(5, "9.9.9.9:41000")).Code)
+}
+
+func Test_Throttled_WithoutLimiter(t *testing.T) {
+	ctrl := NewPaymentController(&usecase.PaymentUsecase{})
+	handler := ctrl.Throttled(payEntity.ThrottlePayout)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	for i := 0; i < 20; i++ {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, throttledRequest(5, "8.8.8.8:41000"))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	}
+}
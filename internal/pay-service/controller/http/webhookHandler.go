@@ -0,0 +1,72 @@
+package payment
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+
+	payEntity "retarget/internal/pay-service/entity"
+	payment "retarget/internal/pay-service/usecase"
+	"retarget/pkg/utils/throttle"
+)
+
+// Адреса, с которых YooKassa отправляет уведомления
+// https://yookassa.ru/developers/using-api/webhooks#ip
+var yooNotificationNets = mustParseNets(
+	"185.71.76.0/27",
+	"185.71.77.0/27",
+	"77.75.153.0/25",
+	"77.75.156.11/32",
+	"77.75.156.35/32",
+	"77.75.154.128/25",
+	"2a02:5180::/32",
+)
+
+func mustParseNets(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// YooKassaWebhook принимает уведомления YooKassa о платежах и выплатах.
+// Любой ответ кроме 200 YooKassa повторяет, поэтому ошибки обработки
+// отдаются как 500, а заведомо неподходящие уведомления — как 200
+func (h *PaymentController) YooKassaWebhook(w http.ResponseWriter, r *http.Request) {
+	ip := throttle.ClientIP(r)
+	if ip == nil || !containsIP(yooNotificationNets, ip) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	var notification payEntity.YooNotification
+	if err := json.NewDecoder(r.Body).Decode(&notification); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := h.PaymentUsecase.HandleYooNotification(notification); err != nil {
+		if errors.Is(err, payment.ErrUnknownNotification) {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
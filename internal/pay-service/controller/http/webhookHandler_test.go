@@ -0,0 +1,34 @@
+package payment
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	usecase "retarget/internal/pay-service/usecase"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_YooKassaWebhook_ForbiddenIP(t *testing.T) {
+	ctrl := NewPaymentController(&usecase.PaymentUsecase{})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/payment/webhooks/yookassa", strings.NewReader(`{}`))
+	req.RemoteAddr = "8.8.8.8:41000"
+	req.Header.Set("X-Real-IP", "185.71.76.5")
+	rr := httptest.NewRecorder()
+
+	ctrl.YooKassaWebhook(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func Test_YooKassaWebhook_UnsupportedEvent(t *testing.T) {
+	ctrl := NewPaymentController(&usecase.PaymentUsecase{})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/payment/webhooks/yookassa",
+		strings.NewReader(`{"type":"notification","event":"deal.closed","object":{"id":"r1"}}`))
+	req.RemoteAddr = "185.71.77.10:41000"
+	rr := httptest.NewRecorder()
+
+	ctrl.YooKassaWebhook(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
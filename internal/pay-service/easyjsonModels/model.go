@@ -2,12 +2,16 @@
 
 package model
 
+import "retarget/pkg/entity"
+
 type TransactionResponse struct {
 	TransactionID string `json:"transactionId"`
 	Status        string `json:"status"`
 	NextAction    string `json:"nextAction"`
 }
 
+// TopUpRequest — ручное зачисление администратора пользователю UserID
 type TopUpRequest struct {
-	Amount float64 `json:"amount"`
+	UserID int            `json:"user_id"`
+	Amount entity.Decimal `json:"amount"`
 }
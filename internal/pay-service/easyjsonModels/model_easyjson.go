@@ -116,8 +116,12 @@ func easyjsonC80ae7adDecodeRetargetInternalPayServiceEasyjsonModels1(in *jlexer.
 			continue
 		}
 		switch key {
+		case "user_id":
+			out.UserID = int(in.Int())
 		case "amount":
-			out.Amount = float64(in.Float64())
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Amount).UnmarshalJSON(data))
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -133,9 +137,14 @@ func easyjsonC80ae7adEncodeRetargetInternalPayServiceEasyjsonModels1(out *jwrite
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"amount\":"
+		const prefix string = ",\"user_id\":"
 		out.RawString(prefix[1:])
-		out.Float64(float64(in.Amount))
+		out.Int(int(in.UserID))
+	}
+	{
+		const prefix string = ",\"amount\":"
+		out.RawString(prefix)
+		out.Raw((in.Amount).MarshalJSON())
 	}
 	out.RawByte('}')
 }
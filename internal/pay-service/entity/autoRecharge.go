@@ -0,0 +1,95 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+// TransactionAutoRecharge — тип транзакции автопополнения сохранённым способом оплаты
+const TransactionAutoRecharge = "topup_auto"
+
+// AutoRechargeMaxFailures — после стольких неудачных списаний подряд автопополнение выключается
+const AutoRechargeMaxFailures = 3
+
+// autoRechargeRetryDelay — пауза после первой неудачи, дальше она удваивается
+const autoRechargeRetryDelay = 15 * time.Minute
+
+var (
+	ErrInvalidAutoRecharge  = errors.New("invalid auto-recharge settings")
+	ErrAutoRechargeNotFound = errors.New("auto-recharge is not configured")
+	ErrAutoRechargeNotDue   = errors.New("auto-recharge is not due")
+	ErrAutoRechargeCap      = errors.New("auto-recharge monthly cap reached")
+	ErrNoPaymentMethod      = errors.New("no saved payment method")
+)
+
+// PaymentMethod — способ оплаты, сохранённый в шлюзе при первом пополнении.
+// Списывать им можно после того, как этот платёж прошёл
+type PaymentMethod struct {
+	ID            string    `json:"id"`
+	UserID        int       `json:"-"`
+	Title         string    `json:"title"`
+	TransactionID string    `json:"-"`
+	Active        bool      `json:"active"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// AutoRecharge — правило «когда баланс ниже Threshold, списать Amount», не
+// больше MonthlyCap за календарный месяц (UTC, см. StatementPeriod)
+type AutoRecharge struct {
+	UserID               int        `json:"-"`
+	Enabled              bool       `json:"enabled"`
+	Threshold            Decimal    `json:"threshold"`
+	Amount               Decimal    `json:"amount"`
+	MonthlyCap           Decimal    `json:"monthly_cap"`
+	PaymentMethodID      string     `json:"payment_method_id"`
+	Failures             int        `json:"failures"`
+	NextAttemptAt        *time.Time `json:"next_attempt_at,omitempty"`
+	PendingTransactionID string     `json:"pending_transaction_id,omitempty"`
+	LastError            string     `json:"last_error,omitempty"`
+	ChargedThisMonth     Decimal    `json:"charged_this_month"`
+}
+
+func (a AutoRecharge) Validate() error {
+	if a.Threshold.Sign() <= 0 || a.Amount.Sign() <= 0 {
+		return ErrInvalidAutoRecharge
+	}
+	if a.MonthlyCap.Cmp(a.Amount) < 0 {
+		return ErrInvalidAutoRecharge
+	}
+	return nil
+}
+
+// Ready сообщает, что новое списание возможно: правило включено, предыдущее
+// списание завершено, а пауза после неудачи прошла
+func (a AutoRecharge) Ready(now time.Time) bool {
+	if !a.Enabled || a.PendingTransactionID != "" || a.PaymentMethodID == "" {
+		return false
+	}
+	return a.NextAttemptAt == nil || !now.Before(*a.NextAttemptAt)
+}
+
+// Due сообщает, что пора списывать: списание возможно, а баланс опустился ниже порога
+func (a AutoRecharge) Due(balance Decimal, now time.Time) bool {
+	return a.Ready(now) && balance.Cmp(a.Threshold) < 0
+}
+
+// RecordSuccess сбрасывает счётчик неудач после зачисленного списания
+func (a *AutoRecharge) RecordSuccess() {
+	a.PendingTransactionID = ""
+	a.Failures = 0
+	a.NextAttemptAt = nil
+	a.LastError = ""
+}
+
+// RecordFailure откладывает следующую попытку с удвоением паузы и выключает
+// правило после AutoRechargeMaxFailures неудач подряд
+func (a *AutoRecharge) RecordFailure(reason string, now time.Time) {
+	a.PendingTransactionID = ""
+	a.Failures++
+	a.LastError = reason
+	next := now.Add(autoRechargeRetryDelay << (a.Failures - 1))
+	a.NextAttemptAt = &next
+	if a.Failures >= AutoRechargeMaxFailures {
+		a.Enabled = false
+	}
+}
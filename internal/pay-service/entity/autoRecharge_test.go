@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoRecharge_Validate(t *testing.T) {
+	a := AutoRecharge{Threshold: DecimalFromKopecks(10000), Amount: DecimalFromKopecks(50000), MonthlyCap: DecimalFromKopecks(100000)}
+	assert.NoError(t, a.Validate())
+
+	bad := a
+	bad.MonthlyCap = DecimalFromKopecks(40000)
+	assert.ErrorIs(t, bad.Validate(), ErrInvalidAutoRecharge)
+
+	bad = a
+	bad.Amount = DecimalFromKopecks(0)
+	assert.ErrorIs(t, bad.Validate(), ErrInvalidAutoRecharge)
+}
+
+func TestAutoRecharge_Due(t *testing.T) {
+	now := time.Date(2025, time.May, 1, 12, 0, 0, 0, time.UTC)
+	a := AutoRecharge{Enabled: true, Threshold: DecimalFromKopecks(10000), PaymentMethodID: "pm-1"}
+	assert.True(t, a.Due(DecimalFromKopecks(9999), now))
+	assert.False(t, a.Due(DecimalFromKopecks(10000), now))
+
+	a.PendingTransactionID = "tx1"
+	assert.False(t, a.Due(DecimalFromKopecks(0), now))
+	a.PendingTransactionID = ""
+
+	later := now.Add(time.Minute)
+	a.NextAttemptAt = &later
+	assert.False(t, a.Due(DecimalFromKopecks(0), now))
+	assert.True(t, a.Due(DecimalFromKopecks(0), later))
+}
+
+func TestAutoRecharge_RecordFailure(t *testing.T) {
+	now := time.Date(2025, time.May, 1, 12, 0, 0, 0, time.UTC)
+	a := AutoRecharge{Enabled: true, PendingTransactionID: "tx1"}
+
+	a.RecordFailure("declined", now)
+	assert.Equal(t, now.Add(15*time.Minute), *a.NextAttemptAt)
+	assert.Empty(t, a.PendingTransactionID)
+	a.RecordFailure("declined", now)
+	assert.Equal(t, now.Add(30*time.Minute), *a.NextAttemptAt)
+	assert.True(t, a.Enabled)
+	a.RecordFailure("declined", now)
+	assert.False(t, a.Enabled)
+	assert.Equal(t, "declined", a.LastError)
+
+	a.RecordSuccess()
+	assert.Zero(t, a.Failures)
+	assert.Nil(t, a.NextAttemptAt)
+}
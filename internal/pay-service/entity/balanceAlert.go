@@ -0,0 +1,14 @@
+package entity
+
+import (
+	pkgEntity "retarget/pkg/entity"
+)
+
+// BalanceAlertSettings — пороги уведомлений и автопауза, общие с profile-service
+type BalanceAlertSettings = pkgEntity.BalanceAlertSettings
+
+var ErrInvalidBalanceAlerts = pkgEntity.ErrInvalidBalanceAlerts
+
+func DefaultBalanceAlertSettings() BalanceAlertSettings {
+	return pkgEntity.DefaultBalanceAlertSettings()
+}
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+
+	"gopkg.in/inf.v0"
+)
+
+var (
+	ErrInvalidTakeRate = errors.New("take rate must be in range [0, 1)")
+)
+
+// ParseTakeRate разбирает долю комиссии площадки, например "0.15"
+func ParseTakeRate(s string) (Decimal, error) {
+	var rate Decimal
+	if err := rate.ParseFromString(s); err != nil {
+		return Decimal{}, err
+	}
+	if err := ValidateTakeRate(rate); err != nil {
+		return Decimal{}, err
+	}
+	return rate, nil
+}
+
+func ValidateTakeRate(rate Decimal) error {
+	if rate.Dec == nil {
+		return nil
+	}
+	if rate.Sign() < 0 || rate.Cmp(Decimal{Dec: inf.NewDec(1, 0)}) >= 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidTakeRate, rate.String())
+	}
+	return nil
+}
+
+// SplitFee делит сумму показа на комиссию площадки и начисление владельцу
+// слота. Комиссия округляется до копейки, остаток целиком уходит владельцу
+func SplitFee(amount, takeRate Decimal) (net Decimal, fee Decimal, err error) {
+	amountKopecks, err := amount.Kopecks()
+	if err != nil {
+		return Decimal{}, Decimal{}, err
+	}
+	if takeRate.Dec == nil || takeRate.Sign() == 0 {
+		return DecimalFromKopecks(amountKopecks), DecimalFromKopecks(0), nil
+	}
+
+	raw := new(inf.Dec).Mul(DecimalFromKopecks(amountKopecks).Dec, takeRate.Dec)
+	feeKopecks, err := Decimal{Dec: raw}.Kopecks()
+	if err != nil {
+		return Decimal{}, Decimal{}, err
+	}
+	return DecimalFromKopecks(amountKopecks - feeKopecks), DecimalFromKopecks(feeKopecks), nil
+}
+
+// Earnings — отчёт о доходе владельца слотов с показов
+type Earnings struct {
+	Impressions int     `json:"impressions"`
+	Gross       Decimal `json:"gross"`
+	Fee         Decimal `json:"fee"`
+	Net         Decimal `json:"net"`
+	TakeRate    Decimal `json:"take_rate"`
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitFee(t *testing.T) {
+	rate, err := ParseTakeRate("0.15")
+	assert.NoError(t, err)
+
+	net, fee, err := SplitFee(DecimalFromKopecks(333), rate)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.50", fee.String())
+	assert.Equal(t, "2.83", net.String())
+
+	net, fee, err = SplitFee(DecimalFromKopecks(333), Decimal{})
+	assert.NoError(t, err)
+	assert.Equal(t, "0.00", fee.String())
+	assert.Equal(t, "3.33", net.String())
+
+	_, err = ParseTakeRate("1")
+	assert.ErrorIs(t, err, ErrInvalidTakeRate)
+	_, err = ParseTakeRate("-0.1")
+	assert.ErrorIs(t, err, ErrInvalidTakeRate)
+}
+
+func TestImpressionChargeEntry_WithFee(t *testing.T) {
+	entry := ImpressionChargeEntry(1, 2, DecimalFromKopecks(1000), DecimalFromKopecks(150), "hold-1")
+	assert.NoError(t, entry.Validate())
+	assert.Len(t, entry.Postings, 3)
+	assert.Equal(t, "8.50", entry.Postings[1].Amount.String())
+	assert.Equal(t, AccountPlatformFee, entry.Postings[2].Account)
+	assert.Equal(t, "1.50", entry.Postings[2].Amount.String())
+}
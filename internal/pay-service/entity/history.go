@@ -0,0 +1,156 @@
+package entity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidHistoryFilter = errors.New("invalid transaction history filter")
+	ErrInvalidHistoryCursor = errors.New("invalid transaction history cursor")
+)
+
+// HistoryType — вид операции в истории с точки зрения пользователя
+type HistoryType string
+
+const (
+	HistoryTopUp  HistoryType = "top_up"
+	HistoryPayout HistoryType = "payout"
+	HistoryCharge HistoryType = "charge" // списание за показ баннера
+	HistoryCredit HistoryType = "credit" // начисление за показ в слоте
+	HistoryRefund HistoryType = "refund"
+	HistoryOther  HistoryType = "other"
+)
+
+var historyTypes = []HistoryType{HistoryTopUp, HistoryPayout, HistoryCharge, HistoryCredit, HistoryRefund, HistoryOther}
+
+func ParseHistoryType(s string) (HistoryType, error) {
+	for _, t := range historyTypes {
+		if string(t) == s {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("%w: unknown type %q", ErrInvalidHistoryFilter, s)
+}
+
+// Источники записей истории: платёжные операции из transaction и списания за показы из журнала
+const (
+	HistorySourceTransaction = "transaction"
+	HistorySourceLedger      = "ledger"
+)
+
+type HistorySort string
+
+const (
+	HistorySortCreatedAt HistorySort = "created_at"
+	HistorySortAmount    HistorySort = "amount"
+)
+
+func ParseHistorySort(s string) (HistorySort, error) {
+	switch HistorySort(s) {
+	case "", HistorySortCreatedAt:
+		return HistorySortCreatedAt, nil
+	case HistorySortAmount:
+		return HistorySortAmount, nil
+	default:
+		return "", fmt.Errorf("%w: unknown sort %q", ErrInvalidHistoryFilter, s)
+	}
+}
+
+var transactionStatusNames = map[int]string{
+	TransactionPending:   "pending",
+	TransactionSucceeded: "succeeded",
+	TransactionCanceled:  "canceled",
+}
+
+func TransactionStatusName(status int) string {
+	if name, ok := transactionStatusNames[status]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+func ParseTransactionStatus(s string) (int, error) {
+	for status, name := range transactionStatusNames {
+		if name == s {
+			return status, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unknown status %q", ErrInvalidHistoryFilter, s)
+}
+
+// HistoryItem — строка истории операций. Amount всегда положительный,
+// направление движения денег задаёт Type
+type HistoryItem struct {
+	Source    string      `json:"source"`
+	ID        int64       `json:"id"`
+	Reference string      `json:"reference"`
+	Type      HistoryType `json:"type"`
+	RawType   string      `json:"raw_type"`
+	Status    string      `json:"status"`
+	Amount    Decimal     `json:"amount"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// HistoryCursor указывает на последнюю отданную строку; следующая страница
+// начинается строго после неё в выбранном порядке. Type нужен, потому что одна
+// проводка может дать пользователю и списание, и начисление
+type HistoryCursor struct {
+	Sort   HistorySort `json:"k"`
+	Value  string      `json:"v"`
+	Source string      `json:"s"`
+	ID     int64       `json:"i"`
+	Type   HistoryType `json:"t"`
+}
+
+func (c HistoryCursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeHistoryCursor(s string) (HistoryCursor, error) {
+	var c HistoryCursor
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidHistoryCursor
+	}
+	if err := json.Unmarshal(raw, &c); err != nil || c.Source == "" {
+		return c, ErrInvalidHistoryCursor
+	}
+	return c, nil
+}
+
+// CursorAfter строит курсор, указывающий на item при сортировке sort
+func CursorAfter(item HistoryItem, sort HistorySort) HistoryCursor {
+	c := HistoryCursor{Sort: sort, Source: item.Source, ID: item.ID, Type: item.Type}
+	if sort == HistorySortAmount {
+		c.Value = item.Amount.String()
+	} else {
+		c.Value = item.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return c
+}
+
+// HistoryFilter — условия выборки истории. Нулевые значения полей означают
+// отсутствие ограничения; To не включается в интервал
+type HistoryFilter struct {
+	UserID    int
+	Types     []HistoryType
+	Statuses  []int
+	From      time.Time
+	To        time.Time
+	MinAmount *Decimal
+	MaxAmount *Decimal
+	Sort      HistorySort
+	Desc      bool
+	Limit     int
+	After     *HistoryCursor
+}
+
+type HistoryPage struct {
+	Items      []HistoryItem `json:"items"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistoryCursor_RoundTrip(t *testing.T) {
+	item := HistoryItem{
+		Source:    HistorySourceLedger,
+		ID:        42,
+		Type:      HistoryCharge,
+		Amount:    DecimalFromKopecks(150),
+		CreatedAt: time.Date(2025, time.April, 1, 10, 0, 0, 500, time.FixedZone("MSK", 3*60*60)),
+	}
+
+	cursor, err := DecodeHistoryCursor(CursorAfter(item, HistorySortCreatedAt).Encode())
+	assert.NoError(t, err)
+	assert.Equal(t, HistoryCursor{Sort: HistorySortCreatedAt, Value: "2025-04-01T07:00:00.0000005Z", Source: "ledger", ID: 42, Type: HistoryCharge}, cursor)
+
+	cursor, err = DecodeHistoryCursor(CursorAfter(item, HistorySortAmount).Encode())
+	assert.NoError(t, err)
+	assert.Equal(t, "1.50", cursor.Value)
+
+	_, err = DecodeHistoryCursor("not a cursor")
+	assert.ErrorIs(t, err, ErrInvalidHistoryCursor)
+}
+
+func TestParseHistoryFilterValues(t *testing.T) {
+	status, err := ParseTransactionStatus("canceled")
+	assert.NoError(t, err)
+	assert.Equal(t, TransactionCanceled, status)
+	assert.Equal(t, "succeeded", TransactionStatusName(TransactionSucceeded))
+
+	_, err = ParseHistoryType("gift")
+	assert.ErrorIs(t, err, ErrInvalidHistoryFilter)
+
+	sort, err := ParseHistorySort("")
+	assert.NoError(t, err)
+	assert.Equal(t, HistorySortCreatedAt, sort)
+}
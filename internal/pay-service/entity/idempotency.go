@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+var (
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was used with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("request with this idempotency key is still in progress")
+)
+
+// IdempotencyRecord — сохранённый ответ на запрос с заголовком Idempotency-Key.
+// Пока запрос выполняется, StatusCode равен нулю. Ключи участников организации
+// не пересекаются: у каждого ActorID своё пространство
+type IdempotencyRecord struct {
+	UserID      int
+	ActorID     int
+	Key         string
+	RequestHash string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+}
+
+func (r IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
+
+// IdempotencyRequestHash отпечаток запроса: повтор с тем же ключом должен
+// совпадать по методу, пути и телу
+func IdempotencyRequestHash(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
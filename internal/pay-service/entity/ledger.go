@@ -0,0 +1,224 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrUnbalancedEntry = errors.New("ledger entry is not balanced")
+)
+
+type AccountType string
+
+const (
+	AccountAdvertiser  AccountType = "advertiser"
+	AccountPublisher   AccountType = "publisher"
+	AccountPlatformFee AccountType = "platform_fee"
+	AccountGateway     AccountType = "gateway"
+	AccountPayoutHold  AccountType = "payout_hold"
+	AccountExchange    AccountType = "exchange"   // встречный счёт обмена валют
+	AccountPromo       AccountType = "promo"      // промо-кредит пользователя, не выводится
+	AccountMarketing   AccountType = "marketing"  // системный счёт, из которого выдаётся промо-кредит
+	AccountAdjustment  AccountType = "adjustment" // системный счёт ручных зачислений администратора, со шлюзом не сверяется
+)
+
+type EntryKind string
+
+const (
+	EntryTopUp            EntryKind = "topup"
+	EntryImpressionCharge EntryKind = "impression_charge"
+	EntryPayout           EntryKind = "payout"
+	EntryRefund           EntryKind = "refund"
+	EntryPayoutHold       EntryKind = "payout_hold"
+	EntryPayoutRelease    EntryKind = "payout_release"
+	EntryConversion       EntryKind = "conversion"
+	EntryPromoCredit      EntryKind = "promo_credit"
+	EntryAdjustment       EntryKind = "adjustment"
+)
+
+// Posting — одна сторона проводки. OwnerID равен нулю у системных счетов
+// (комиссия площадки, внешний шлюз), пустая Currency означает базовую валюту
+type Posting struct {
+	OwnerID  int         `json:"owner_id,omitempty"`
+	Account  AccountType `json:"account"`
+	Currency string      `json:"currency,omitempty"`
+	Amount   Decimal     `json:"amount"`
+}
+
+// PostingCurrency — валюта счёта, на который идёт сторона проводки
+func (p Posting) PostingCurrency() string {
+	if p.Currency == "" {
+		return BaseCurrency
+	}
+	return p.Currency
+}
+
+// Placement — баннер и слот, за показ в котором прошло списание. Conversion
+// заполнен, если ставка баннера была не в базовой валюте
+type Placement struct {
+	BannerID   int64       `json:"banner_id,omitempty"`
+	SlotLink   string      `json:"slot_link,omitempty"`
+	Conversion *Conversion `json:"-"`
+}
+
+func (p Placement) IsZero() bool {
+	return p.BannerID == 0 && p.SlotLink == ""
+}
+
+type LedgerEntry struct {
+	ID         int64       `json:"id"`
+	Kind       EntryKind   `json:"kind"`
+	Reference  string      `json:"reference"`
+	Postings   []Posting   `json:"postings"`
+	Placement  *Placement  `json:"placement,omitempty"`
+	Conversion *Conversion `json:"conversion,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+type LedgerDrift struct {
+	OwnerID       int     `json:"owner_id"`
+	LedgerBalance Decimal `json:"ledger_balance"`
+	UserBalance   Decimal `json:"user_balance"`
+}
+
+// Validate проверяет, что сумма сторон проводки в каждой валюте равна нулю
+func (e LedgerEntry) Validate() error {
+	if len(e.Postings) < 2 {
+		return fmt.Errorf("%w: entry %s has %d postings", ErrUnbalancedEntry, e.Reference, len(e.Postings))
+	}
+	sums := make(map[string]int64)
+	for _, p := range e.Postings {
+		kopecks, err := p.Amount.Kopecks()
+		if err != nil {
+			return err
+		}
+		if kopecks == 0 {
+			return fmt.Errorf("%w: zero posting in entry %s", ErrUnbalancedEntry, e.Reference)
+		}
+		sums[p.PostingCurrency()] += kopecks
+	}
+	for currency, sum := range sums {
+		if sum != 0 {
+			return fmt.Errorf("%w: entry %s is off by %d minor units of %s", ErrUnbalancedEntry, e.Reference, sum, currency)
+		}
+	}
+	return nil
+}
+
+// Transfer собирает проводку из двух сторон: amount уходит со счёта from на счёт to
+func Transfer(kind EntryKind, reference string, from, to Posting, amount Decimal) LedgerEntry {
+	from.Amount = amount.Neg()
+	to.Amount = amount
+	return LedgerEntry{
+		Kind:      kind,
+		Reference: reference,
+		Postings:  []Posting{from, to},
+	}
+}
+
+func TopUpEntry(userID int, amount Decimal, reference string) LedgerEntry {
+	return Transfer(EntryTopUp, reference,
+		Posting{Account: AccountGateway},
+		Posting{OwnerID: userID, Account: AccountAdvertiser},
+		amount)
+}
+
+// AdjustmentEntry зачисляет деньги, за которыми нет платежа во внешнем шлюзе
+func AdjustmentEntry(userID int, amount Decimal, reference string) LedgerEntry {
+	return Transfer(EntryAdjustment, reference,
+		Posting{Account: AccountAdjustment},
+		Posting{OwnerID: userID, Account: AccountAdvertiser},
+		amount)
+}
+
+func PayoutEntry(userID int, amount Decimal, reference string) LedgerEntry {
+	return Transfer(EntryPayout, reference,
+		Posting{OwnerID: userID, Account: AccountPublisher},
+		Posting{Account: AccountGateway},
+		amount)
+}
+
+// PayoutReversalEntry возвращает на счёт деньги отменённой выплаты
+func PayoutReversalEntry(userID int, amount Decimal, reference string) LedgerEntry {
+	return Transfer(EntryPayout, reference,
+		Posting{Account: AccountGateway},
+		Posting{OwnerID: userID, Account: AccountPublisher},
+		amount)
+}
+
+// ImpressionChargeEntry списывает полную стоимость показа с рекламодателя,
+// начисляет владельцу слота сумму за вычетом комиссии, а комиссию — площадке
+func ImpressionChargeEntry(advertiserID, publisherID int, amount, fee Decimal, reference string) LedgerEntry {
+	entry := Transfer(EntryImpressionCharge, reference,
+		Posting{OwnerID: advertiserID, Account: AccountAdvertiser},
+		Posting{OwnerID: publisherID, Account: AccountPublisher},
+		amount)
+
+	feeKopecks, err := fee.Kopecks()
+	if err != nil || feeKopecks == 0 {
+		return entry
+	}
+	amountKopecks, _ := amount.Kopecks()
+	entry.Postings[1].Amount = DecimalFromKopecks(amountKopecks - feeKopecks)
+	entry.Postings = append(entry.Postings, Posting{Account: AccountPlatformFee, Amount: DecimalFromKopecks(feeKopecks)})
+	return entry
+}
+
+// WithPlacement привязывает проводку к баннеру и слоту, пустое размещение не записывается
+func (e LedgerEntry) WithPlacement(placement Placement) LedgerEntry {
+	if placement.Conversion != nil {
+		e.Conversion = placement.Conversion
+		placement.Conversion = nil
+	}
+	if placement.IsZero() {
+		e.Placement = nil
+		return e
+	}
+	e.Placement = &placement
+	return e
+}
+
+// InCurrency переносит все стороны проводки на счета в валюте currency
+func (e LedgerEntry) InCurrency(currency string) LedgerEntry {
+	if currency == BaseCurrency {
+		currency = ""
+	}
+	postings := make([]Posting, len(e.Postings))
+	for i, p := range e.Postings {
+		p.Currency = currency
+		postings[i] = p
+	}
+	e.Postings = postings
+	return e
+}
+
+// WithConversion привязывает к проводке пересчёт, по которому получена её сумма
+func (e LedgerEntry) WithConversion(conversion Conversion) LedgerEntry {
+	e.Conversion = &conversion
+	return e
+}
+
+// ExchangeEntry меняет деньги пользователя между валютными счетами через
+// встречный счёт обмена, так что каждая валюта остаётся сбалансированной
+func ExchangeEntry(userID int, conversion Conversion, reference string) LedgerEntry {
+	from, to := conversion.FromCurrency, conversion.Currency
+	if from == BaseCurrency {
+		from = ""
+	}
+	if to == BaseCurrency {
+		to = ""
+	}
+	entry := LedgerEntry{
+		Kind:      EntryConversion,
+		Reference: reference,
+		Postings: []Posting{
+			{OwnerID: userID, Account: AccountAdvertiser, Currency: from, Amount: conversion.OriginalAmount.Neg()},
+			{Account: AccountExchange, Currency: from, Amount: conversion.OriginalAmount},
+			{Account: AccountExchange, Currency: to, Amount: conversion.Amount.Neg()},
+			{OwnerID: userID, Account: AccountAdvertiser, Currency: to, Amount: conversion.Amount},
+		},
+	}
+	return entry.WithConversion(conversion)
+}
@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedgerEntry_Validate(t *testing.T) {
+	entry := ImpressionChargeEntry(1, 2, DecimalFromKopecks(150), Decimal{}, "hold-1")
+	assert.NoError(t, entry.Validate())
+	assert.Equal(t, "-1.50", entry.Postings[0].Amount.String())
+	assert.Equal(t, AccountPublisher, entry.Postings[1].Account)
+
+	entry.Postings[1].Amount = DecimalFromKopecks(149)
+	assert.ErrorIs(t, entry.Validate(), ErrUnbalancedEntry)
+
+	single := LedgerEntry{Postings: []Posting{{Amount: DecimalFromKopecks(1)}}}
+	assert.ErrorIs(t, single.Validate(), ErrUnbalancedEntry)
+
+	zero := TopUpEntry(1, Decimal{}, "trx")
+	assert.ErrorIs(t, zero.Validate(), ErrUnbalancedEntry)
+}
@@ -0,0 +1,111 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrPayoutNotFound      = errors.New("payout request not found")
+	ErrPayoutBelowMinimum  = errors.New("payout amount is below the minimum")
+	ErrPayoutDailyLimit    = errors.New("daily payout limit exceeded")
+	ErrPayoutMonthlyLimit  = errors.New("monthly payout limit exceeded")
+	ErrPayoutAccountTooNew = errors.New("account is too new for payouts")
+	ErrPayoutTransition    = errors.New("payout request is not in the expected status")
+	ErrInsufficientBalance = errors.New("insufficient funds for withdrawal")
+)
+
+type PayoutStatus string
+
+// Жизненный цикл заявки: requested → approved → sent, при ошибке шлюза или
+// отказе — failed, если шлюз отменил уже отправленную выплату — returned.
+// В failed и returned деньги возвращаются на баланс
+const (
+	PayoutRequested PayoutStatus = "requested"
+	PayoutApproved  PayoutStatus = "approved"
+	PayoutSent      PayoutStatus = "sent"
+	PayoutFailed    PayoutStatus = "failed"
+	PayoutReturned  PayoutStatus = "returned"
+)
+
+type PayoutRequest struct {
+	ID              int          `json:"id"`
+	UserID          int          `json:"user_id"`
+	Amount          Decimal      `json:"amount"`
+	DestinationType string       `json:"destination_type"`
+	Destination     string       `json:"destination"`
+	Description     string       `json:"description,omitempty"`
+	Status          PayoutStatus `json:"status"`
+	GatewayID       string       `json:"gateway_id,omitempty"`
+	FailureReason   string       `json:"failure_reason,omitempty"`
+	ReviewedBy      int          `json:"reviewed_by,omitempty"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+}
+
+// PayoutUpdate — поля заявки, которые меняются вместе со статусом
+type PayoutUpdate struct {
+	GatewayID     string
+	FailureReason string
+	ReviewedBy    int
+}
+
+// PayoutPolicy — ограничения на выплаты. Нулевые лимиты и порог означают
+// отсутствие ограничения
+type PayoutPolicy struct {
+	MinAmount         Decimal
+	DailyLimit        Decimal
+	MonthlyLimit      Decimal
+	MinAccountAge     time.Duration
+	ApprovalThreshold Decimal // заявки от этой суммы ждут ручного одобрения
+}
+
+func DefaultPayoutPolicy() PayoutPolicy {
+	return PayoutPolicy{MinAmount: DecimalFromKopecks(1000)}
+}
+
+func (p PayoutPolicy) NeedsApproval(amount Decimal) bool {
+	return p.ApprovalThreshold.Sign() > 0 && amount.Cmp(p.ApprovalThreshold) >= 0
+}
+
+// Check проверяет новую заявку на amount при уже заявленных за сутки и за
+// месяц суммах и дате регистрации аккаунта
+func (p PayoutPolicy) Check(amount, daySpent, monthSpent Decimal, registeredAt, now time.Time) error {
+	if amount.Cmp(p.MinAmount) < 0 {
+		return ErrPayoutBelowMinimum
+	}
+	if p.MinAccountAge > 0 && now.Sub(registeredAt) < p.MinAccountAge {
+		return ErrPayoutAccountTooNew
+	}
+	if p.DailyLimit.Sign() > 0 && daySpent.Add(amount).Cmp(p.DailyLimit) > 0 {
+		return ErrPayoutDailyLimit
+	}
+	if p.MonthlyLimit.Sign() > 0 && monthSpent.Add(amount).Cmp(p.MonthlyLimit) > 0 {
+		return ErrPayoutMonthlyLimit
+	}
+	return nil
+}
+
+// PayoutHoldEntry переводит сумму заявки с баланса владельца на счёт удержаний
+func PayoutHoldEntry(userID int, amount Decimal, reference string) LedgerEntry {
+	return Transfer(EntryPayoutHold, reference,
+		Posting{OwnerID: userID, Account: AccountPublisher},
+		Posting{Account: AccountPayoutHold},
+		amount)
+}
+
+// PayoutReleaseEntry возвращает удержание владельцу, если выплата не состоялась
+func PayoutReleaseEntry(userID int, amount Decimal, reference string) LedgerEntry {
+	return Transfer(EntryPayoutRelease, reference,
+		Posting{Account: AccountPayoutHold},
+		Posting{OwnerID: userID, Account: AccountPublisher},
+		amount)
+}
+
+// PayoutSentEntry списывает удержание во внешний шлюз после отправки выплаты
+func PayoutSentEntry(amount Decimal, reference string) LedgerEntry {
+	return Transfer(EntryPayout, reference,
+		Posting{Account: AccountPayoutHold},
+		Posting{Account: AccountGateway},
+		amount)
+}
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayoutPolicy_Check(t *testing.T) {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	old := now.AddDate(0, -1, 0)
+	policy := PayoutPolicy{
+		MinAmount:     DecimalFromKopecks(1000),
+		DailyLimit:    DecimalFromKopecks(100000),
+		MonthlyLimit:  DecimalFromKopecks(500000),
+		MinAccountAge: 7 * 24 * time.Hour,
+	}
+
+	rub := func(r int64) Decimal { return DecimalFromKopecks(r * 100) }
+	assert.NoError(t, policy.Check(rub(500), rub(400), rub(400), old, now))
+	assert.ErrorIs(t, policy.Check(rub(5), rub(0), rub(0), old, now), ErrPayoutBelowMinimum)
+	assert.ErrorIs(t, policy.Check(DecimalFromKopecks(999), rub(0), rub(0), old, now), ErrPayoutBelowMinimum)
+	assert.ErrorIs(t, policy.Check(rub(500), rub(0), rub(0), now.Add(-time.Hour), now), ErrPayoutAccountTooNew)
+	assert.ErrorIs(t, policy.Check(rub(500), rub(600), rub(600), old, now), ErrPayoutDailyLimit)
+	assert.NoError(t, policy.Check(rub(500), rub(500), rub(500), old, now))
+	assert.ErrorIs(t, policy.Check(rub(500), DecimalFromKopecks(50001), rub(0), old, now), ErrPayoutDailyLimit)
+	assert.ErrorIs(t, policy.Check(rub(500), rub(0), rub(4800), old, now), ErrPayoutMonthlyLimit)
+
+	assert.NoError(t, DefaultPayoutPolicy().Check(rub(1e6), rub(1e6), rub(1e6), now, now))
+}
+
+func TestPayoutPolicy_Approval(t *testing.T) {
+	policy := PayoutPolicy{ApprovalThreshold: DecimalFromKopecks(100000)}
+	assert.False(t, policy.NeedsApproval(DecimalFromKopecks(99999)))
+	assert.True(t, policy.NeedsApproval(DecimalFromKopecks(100000)))
+	assert.False(t, DefaultPayoutPolicy().NeedsApproval(DecimalFromKopecks(1e8)))
+}
+
+func TestPayoutEntries_Balanced(t *testing.T) {
+	amount := DecimalFromKopecks(15000)
+	for _, entry := range []LedgerEntry{
+		PayoutHoldEntry(5, amount, "payout_request:1"),
+		PayoutReleaseEntry(5, amount, "payout_request:1"),
+		PayoutSentEntry(amount, "payout_request:1"),
+	} {
+		assert.NoError(t, entry.Validate())
+	}
+}
@@ -0,0 +1,191 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	pkgEntity "retarget/pkg/entity"
+
+	"gopkg.in/inf.v0"
+)
+
+var (
+	ErrPromoNotFound    = errors.New("promo code not found")
+	ErrPromoExists      = errors.New("promo code already exists")
+	ErrInvalidPromoCode = errors.New("invalid promo code")
+	ErrPromoInactive    = errors.New("promo code is disabled")
+	ErrPromoExpired     = errors.New("promo code has expired")
+	ErrPromoExhausted   = errors.New("promo code redemption limit reached")
+	ErrPromoUserLimit   = errors.New("promo code already used by this user")
+	ErrPromoNeedsTopUp  = errors.New("promo code applies only to a top-up")
+	ErrPromoCurrency    = errors.New("promo code applies only to top-ups in the base currency")
+)
+
+type PromoKind string
+
+const (
+	PromoFixed   PromoKind = "fixed"   // фиксированная сумма кредита
+	PromoPercent PromoKind = "percent" // процент от суммы пополнения
+)
+
+var promoCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+
+// PromoCode — промокод на промо-кредит. Фиксированный код можно погасить
+// отдельно или вместе с пополнением, процентный — только с пополнением.
+// MaxRedemptions и PerUserLimit, равные нулю, означают «без ограничений»
+type PromoCode struct {
+	ID             int        `json:"id"`
+	Code           string     `json:"code"`
+	Kind           PromoKind  `json:"kind"`
+	Value          Decimal    `json:"value"`
+	MaxBonus       *Decimal   `json:"max_bonus,omitempty"`
+	MaxRedemptions int        `json:"max_redemptions"`
+	PerUserLimit   int        `json:"per_user_limit"`
+	Redemptions    int        `json:"redemptions"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	Active         bool       `json:"active"`
+	CreatedBy      int        `json:"created_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// PromoRedemption — запись аудита о начисленном по промокоду кредите.
+// TransactionID заполнен, если код применён к пополнению
+type PromoRedemption struct {
+	ID            int       `json:"id"`
+	PromoCodeID   int       `json:"promo_code_id"`
+	Code          string    `json:"code"`
+	UserID        int       `json:"user_id"`
+	Amount        Decimal   `json:"amount"`
+	TopUpAmount   *Decimal  `json:"top_up_amount,omitempty"`
+	TransactionID string    `json:"transaction_id,omitempty"`
+	EntryID       int64     `json:"entry_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// PromoSummary — промо-кредит пользователя и история погашений
+type PromoSummary struct {
+	PromoBalance Decimal           `json:"promo_balance"`
+	Redemptions  []PromoRedemption `json:"redemptions"`
+}
+
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Validate проверяет параметры нового промокода
+func (p PromoCode) Validate() error {
+	if !promoCodePattern.MatchString(p.Code) {
+		return fmt.Errorf("%w: code must be 3-32 characters of A-Z, 0-9, '_' or '-'", ErrInvalidPromoCode)
+	}
+	if p.Value.Sign() <= 0 {
+		return fmt.Errorf("%w: value must be positive", ErrInvalidPromoCode)
+	}
+	switch p.Kind {
+	case PromoFixed:
+	case PromoPercent:
+		if p.Value.Cmp(Decimal{Dec: inf.NewDec(100, 0)}) > 0 {
+			return fmt.Errorf("%w: percent must not exceed 100", ErrInvalidPromoCode)
+		}
+	default:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidPromoCode, p.Kind)
+	}
+	if p.MaxBonus != nil && p.MaxBonus.Sign() <= 0 {
+		return fmt.Errorf("%w: max bonus must be positive", ErrInvalidPromoCode)
+	}
+	if p.MaxRedemptions < 0 || p.PerUserLimit < 0 {
+		return fmt.Errorf("%w: limits must not be negative", ErrInvalidPromoCode)
+	}
+	return nil
+}
+
+// CheckRedeemable проверяет, что userRedemptions-е погашение кода пользователем
+// в момент now укладывается в срок действия и лимиты
+func (p PromoCode) CheckRedeemable(now time.Time, userRedemptions int) error {
+	switch {
+	case !p.Active:
+		return ErrPromoInactive
+	case p.ExpiresAt != nil && !now.Before(*p.ExpiresAt):
+		return ErrPromoExpired
+	case p.MaxRedemptions > 0 && p.Redemptions >= p.MaxRedemptions:
+		return ErrPromoExhausted
+	case p.PerUserLimit > 0 && userRedemptions >= p.PerUserLimit:
+		return ErrPromoUserLimit
+	}
+	return nil
+}
+
+// Bonus считает промо-кредит за погашение. topUp — сумма пополнения, к
+// которому применяется код, nil — погашение без пополнения
+func (p PromoCode) Bonus(topUp *Decimal) (Decimal, error) {
+	var bonus Decimal
+	switch {
+	case p.Kind == PromoFixed:
+		bonus = p.Value.Money()
+	case topUp == nil:
+		return Decimal{}, ErrPromoNeedsTopUp
+	default:
+		raw := new(inf.Dec).Mul(topUp.Money().Dec, p.Value.Dec)
+		raw.QuoRound(raw, inf.NewDec(100, 0), pkgEntity.MoneyScale, pkgEntity.MoneyRounding)
+		bonus = Decimal{Dec: raw}
+	}
+	if p.MaxBonus != nil && bonus.Cmp(*p.MaxBonus) > 0 {
+		bonus = p.MaxBonus.Money()
+	}
+	if bonus.Sign() <= 0 {
+		return Decimal{}, fmt.Errorf("%w: bonus rounds to zero", ErrInvalidPromoCode)
+	}
+	return bonus, nil
+}
+
+// PromoCreditEntry начисляет промо-кредит со счёта маркетинга на промо-счёт пользователя
+func PromoCreditEntry(userID int, amount Decimal, reference string) LedgerEntry {
+	return Transfer(EntryPromoCredit, reference,
+		Posting{Account: AccountMarketing},
+		Posting{OwnerID: userID, Account: AccountPromo},
+		amount)
+}
+
+// AdvertiserCharge возвращает рекламодателя и сумму списания с его основного
+// счёта в базовой валюте, если проводка — списание за показ
+func (e LedgerEntry) AdvertiserCharge() (int, Decimal, bool) {
+	if e.Kind != EntryImpressionCharge {
+		return 0, Decimal{}, false
+	}
+	for _, p := range e.Postings {
+		if p.Account == AccountAdvertiser && p.Currency == "" && p.Amount.Sign() < 0 {
+			return p.OwnerID, p.Amount.Neg(), true
+		}
+	}
+	return 0, Decimal{}, false
+}
+
+// SpendPromo переносит до promo рублей списания за показ с основного счёта
+// рекламодателя на его промо-счёт. Возвращает проводку и остаток promo
+func (e LedgerEntry) SpendPromo(promo Decimal) (LedgerEntry, Decimal) {
+	ownerID, charge, ok := e.AdvertiserCharge()
+	if !ok || promo.Sign() <= 0 {
+		return e, promo
+	}
+	used := promo
+	if used.Cmp(charge) > 0 {
+		used = charge
+	}
+
+	postings := make([]Posting, 0, len(e.Postings)+1)
+	for _, p := range e.Postings {
+		if p.Account != AccountAdvertiser || p.OwnerID != ownerID || p.Currency != "" {
+			postings = append(postings, p)
+			continue
+		}
+		if rest := charge.Sub(used); rest.Sign() > 0 {
+			p.Amount = rest.Neg()
+			postings = append(postings, p)
+		}
+		postings = append(postings, Posting{OwnerID: ownerID, Account: AccountPromo, Amount: used.Neg()})
+	}
+	e.Postings = postings
+	return e, promo.Sub(used)
+}
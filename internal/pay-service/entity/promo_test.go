@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromoCode_Validate(t *testing.T) {
+	p := PromoCode{Code: "WELCOME-500", Kind: PromoFixed, Value: DecimalFromKopecks(50000), PerUserLimit: 1}
+	assert.NoError(t, p.Validate())
+
+	bad := p
+	bad.Code = "no spaces"
+	assert.ErrorIs(t, bad.Validate(), ErrInvalidPromoCode)
+
+	bad = p
+	bad.Kind = PromoPercent
+	bad.Value = DecimalFromKopecks(10100)
+	assert.ErrorIs(t, bad.Validate(), ErrInvalidPromoCode)
+
+	bad = p
+	bad.Kind = "gift"
+	assert.ErrorIs(t, bad.Validate(), ErrInvalidPromoCode)
+}
+
+func TestPromoCode_CheckRedeemable(t *testing.T) {
+	now := time.Date(2025, time.May, 1, 12, 0, 0, 0, time.UTC)
+	expires := now.Add(time.Hour)
+	p := PromoCode{Active: true, MaxRedemptions: 2, PerUserLimit: 1, ExpiresAt: &expires}
+	assert.NoError(t, p.CheckRedeemable(now, 0))
+	assert.ErrorIs(t, p.CheckRedeemable(now, 1), ErrPromoUserLimit)
+	assert.ErrorIs(t, p.CheckRedeemable(expires, 0), ErrPromoExpired)
+
+	p.Redemptions = 2
+	assert.ErrorIs(t, p.CheckRedeemable(now, 0), ErrPromoExhausted)
+
+	p.Active = false
+	assert.ErrorIs(t, p.CheckRedeemable(now, 0), ErrPromoInactive)
+}
+
+func TestPromoCode_Bonus(t *testing.T) {
+	fixed := PromoCode{Kind: PromoFixed, Value: DecimalFromKopecks(50000)}
+	bonus, err := fixed.Bonus(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "500.00", bonus.String())
+
+	maxBonus := DecimalFromKopecks(10000)
+	percent := PromoCode{Kind: PromoPercent, Value: DecimalFromKopecks(1250), MaxBonus: &maxBonus}
+	_, err = percent.Bonus(nil)
+	assert.ErrorIs(t, err, ErrPromoNeedsTopUp)
+
+	topUp := DecimalFromKopecks(33333)
+	bonus, err = percent.Bonus(&topUp)
+	assert.NoError(t, err)
+	assert.Equal(t, "41.67", bonus.String())
+
+	topUp = DecimalFromKopecks(1000000)
+	bonus, err = percent.Bonus(&topUp)
+	assert.NoError(t, err)
+	assert.Equal(t, "100.00", bonus.String())
+}
+
+func TestLedgerEntry_SpendPromo(t *testing.T) {
+	charge := ImpressionChargeEntry(1, 2, DecimalFromKopecks(1000), DecimalFromKopecks(150), "h1")
+
+	partial, rest := charge.SpendPromo(DecimalFromKopecks(400))
+	assert.NoError(t, partial.Validate())
+	assert.Equal(t, "0.00", rest.String())
+	assert.Len(t, partial.Postings, 4)
+	assert.Equal(t, "-6.00", partial.Postings[0].Amount.String())
+	assert.Equal(t, AccountPromo, partial.Postings[1].Account)
+	assert.Equal(t, "-4.00", partial.Postings[1].Amount.String())
+	assert.Equal(t, AccountAdvertiser, charge.Postings[0].Account)
+
+	full, rest := charge.SpendPromo(DecimalFromKopecks(2500))
+	assert.NoError(t, full.Validate())
+	assert.Equal(t, "15.00", rest.String())
+	assert.Len(t, full.Postings, 3)
+	assert.Equal(t, AccountPromo, full.Postings[0].Account)
+
+	topUp := TopUpEntry(1, DecimalFromKopecks(1000), "trx")
+	same, rest := topUp.SpendPromo(DecimalFromKopecks(100))
+	assert.Equal(t, topUp, same)
+	assert.Equal(t, "1.00", rest.String())
+}
@@ -0,0 +1,172 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	pkgEntity "retarget/pkg/entity"
+
+	"gopkg.in/inf.v0"
+)
+
+var (
+	ErrInvalidRate     = errors.New("invalid exchange rate")
+	ErrRateNotFound    = errors.New("no exchange rate for currency")
+	ErrNoRateSnapshot  = errors.New("exchange rates are not loaded")
+	ErrSameCurrency    = errors.New("source and target currencies are the same")
+	ErrInvalidExchange = errors.New("exchange amount must be positive")
+	ErrInvalidCurrency = pkgEntity.ErrInvalidCurrency
+)
+
+// BaseCurrency — валюта auth_user.balance, бюджетного кэша и журнала по умолчанию
+const BaseCurrency = pkgEntity.BaseCurrency
+
+// rateScale — сколько знаков курса храним и пишем в снимок конверсии
+const rateScale = 6
+
+// NormalizeCurrency приводит код валюты к виду, в котором он хранится в базе
+func NormalizeCurrency(code string) (string, error) {
+	return pkgEntity.NormalizeCurrency(code)
+}
+
+// RateSnapshot — неизменяемый набор курсов: Rates[c] — сколько базовой валюты
+// стоит единица валюты c. Каждая конверсия ссылается на снимок, по которому
+// она посчитана
+type RateSnapshot struct {
+	ID        int64              `json:"id"`
+	Source    string             `json:"source"`
+	Rates     map[string]Decimal `json:"rates"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// ParseRates разбирает таблицу курсов вида {"USD": "92.5", "EUR": "100.1"}.
+// Базовую валюту можно не указывать, а если указана — её курс обязан быть 1
+func ParseRates(data []byte) (map[string]Decimal, error) {
+	var raw map[string]Decimal
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRate, err)
+	}
+	return NormalizeRates(raw)
+}
+
+// NormalizeRates проверяет коды валют и курсы; курс базовой валюты отбрасывается
+func NormalizeRates(raw map[string]Decimal) (map[string]Decimal, error) {
+	rates := make(map[string]Decimal, len(raw))
+	for code, rate := range raw {
+		currency, err := NormalizeCurrency(code)
+		if err != nil {
+			return nil, err
+		}
+		if rate.Dec == nil || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("%w: %s must be positive", ErrInvalidRate, currency)
+		}
+		if currency == BaseCurrency {
+			if rate.Cmp(Decimal{Dec: inf.NewDec(1, 0)}) != 0 {
+				return nil, fmt.Errorf("%w: %s rate must be 1", ErrInvalidRate, BaseCurrency)
+			}
+			continue
+		}
+		rates[currency] = Decimal{Dec: new(inf.Dec).Round(rate.Dec, rateScale, inf.RoundHalfEven)}
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("%w: table is empty", ErrInvalidRate)
+	}
+	return rates, nil
+}
+
+// Currencies — валюты снимка вместе с базовой, по алфавиту
+func (s RateSnapshot) Currencies() []string {
+	currencies := []string{BaseCurrency}
+	for code := range s.Rates {
+		currencies = append(currencies, code)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+// Rate отдаёт курс валюты к базовой
+func (s RateSnapshot) Rate(currency string) (Decimal, error) {
+	if currency == BaseCurrency {
+		return Decimal{Dec: inf.NewDec(1, 0)}, nil
+	}
+	rate, ok := s.Rates[currency]
+	if !ok {
+		return Decimal{}, fmt.Errorf("%w: %s", ErrRateNotFound, currency)
+	}
+	return rate, nil
+}
+
+// Conversion — пересчёт суммы из одной валюты в другую по снимку курсов.
+// Rate — сколько единиц Currency стоит единица FromCurrency
+type Conversion struct {
+	SnapshotID     int64   `json:"snapshot_id"`
+	FromCurrency   string  `json:"from_currency"`
+	OriginalAmount Decimal `json:"original_amount"`
+	Currency       string  `json:"currency"`
+	Amount         Decimal `json:"amount"`
+	Rate           Decimal `json:"rate"`
+}
+
+// Convert пересчитывает amount из from в to. Результат округляется до копейки
+// половиной к чётному, чтобы ошибки округления не копились в одну сторону
+func (s RateSnapshot) Convert(amount Decimal, from, to string) (Conversion, error) {
+	fromRate, err := s.Rate(from)
+	if err != nil {
+		return Conversion{}, err
+	}
+	toRate, err := s.Rate(to)
+	if err != nil {
+		return Conversion{}, err
+	}
+
+	rate := new(inf.Dec).QuoRound(fromRate.Dec, toRate.Dec, rateScale, inf.RoundHalfEven)
+	converted := new(inf.Dec).Mul(amount.Dec, fromRate.Dec)
+	converted.QuoRound(converted, toRate.Dec, pkgEntity.MoneyScale, pkgEntity.MoneyRounding)
+	return Conversion{
+		SnapshotID:     s.ID,
+		FromCurrency:   from,
+		OriginalAmount: amount,
+		Currency:       to,
+		Amount:         Decimal{Dec: converted},
+		Rate:           Decimal{Dec: rate},
+	}, nil
+}
+
+// Compact упаковывает пересчёт ставки в базовую валюту в строку
+// "валюта,исходная сумма,снимок,курс" для кэша бюджета
+func (c Conversion) Compact() string {
+	return fmt.Sprintf("%s,%s,%d,%s", c.FromCurrency, c.OriginalAmount.String(), c.SnapshotID, c.Rate.String())
+}
+
+// ParseCompactConversion восстанавливает пересчёт из Compact; amount — сумма
+// списания в базовой валюте
+func ParseCompactConversion(raw string, amount Decimal) (Conversion, error) {
+	parts := strings.Split(raw, ",")
+	if len(parts) != 4 {
+		return Conversion{}, fmt.Errorf("invalid conversion %q", raw)
+	}
+	c := Conversion{FromCurrency: parts[0], Currency: BaseCurrency, Amount: amount}
+	if err := c.OriginalAmount.ParseFromString(parts[1]); err != nil {
+		return Conversion{}, fmt.Errorf("invalid conversion amount %q: %w", raw, err)
+	}
+	snapshotID, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return Conversion{}, fmt.Errorf("invalid conversion snapshot %q: %w", raw, err)
+	}
+	c.SnapshotID = snapshotID
+	if err := c.Rate.ParseFromString(parts[3]); err != nil {
+		return Conversion{}, fmt.Errorf("invalid conversion rate %q: %w", raw, err)
+	}
+	return c, nil
+}
+
+// CurrencyBalance — остаток пользователя в одной валюте
+type CurrencyBalance struct {
+	Currency string  `json:"currency"`
+	Balance  Decimal `json:"balance"`
+}
@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRates(t *testing.T) {
+	rates, err := ParseRates([]byte(`{"usd": "92.5", "EUR": "100.1234567", "RUB": "1"}`))
+	assert.NoError(t, err)
+	assert.Len(t, rates, 2)
+	assert.Equal(t, "92.500000", rates["USD"].String())
+	assert.Equal(t, "100.123457", rates["EUR"].String())
+
+	_, err = ParseRates([]byte(`{"USD": "0"}`))
+	assert.ErrorIs(t, err, ErrInvalidRate)
+
+	_, err = ParseRates([]byte(`{"RUB": "2"}`))
+	assert.ErrorIs(t, err, ErrInvalidRate)
+
+	_, err = ParseRates([]byte(`{"DOLLAR": "90"}`))
+	assert.ErrorIs(t, err, ErrInvalidCurrency)
+
+	_, err = ParseRates([]byte(`{}`))
+	assert.ErrorIs(t, err, ErrInvalidRate)
+}
+
+func TestRateSnapshot_Convert(t *testing.T) {
+	rates, err := ParseRates([]byte(`{"USD": "90", "EUR": "100"}`))
+	assert.NoError(t, err)
+	snapshot := RateSnapshot{ID: 3, Rates: rates}
+
+	c, err := snapshot.Convert(DecimalFromKopecks(150), "USD", BaseCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), c.SnapshotID)
+	assert.Equal(t, "135.00", c.Amount.String())
+	assert.Equal(t, "90.000000", c.Rate.String())
+
+	// 10 EUR = 1000 RUB = 11.111… USD, округляем до копейки
+	c, err = snapshot.Convert(DecimalFromKopecks(1000), "EUR", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, "11.11", c.Amount.String())
+	assert.Equal(t, "1.111111", c.Rate.String())
+
+	_, err = snapshot.Convert(DecimalFromKopecks(100), "GBP", BaseCurrency)
+	assert.ErrorIs(t, err, ErrRateNotFound)
+}
+
+func TestConversion_CompactRoundTrip(t *testing.T) {
+	rates, err := ParseRates([]byte(`{"USD": "92.3"}`))
+	assert.NoError(t, err)
+	c, err := RateSnapshot{ID: 4, Rates: rates}.Convert(DecimalFromKopecks(325), "USD", BaseCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, "USD,3.25,4,92.300000", c.Compact())
+
+	parsed, err := ParseCompactConversion(c.Compact(), c.Amount)
+	assert.NoError(t, err)
+	assert.Equal(t, c.Compact(), parsed.Compact())
+	assert.Equal(t, BaseCurrency, parsed.Currency)
+	assert.Equal(t, c.Amount.String(), parsed.Amount.String())
+
+	_, err = ParseCompactConversion("USD,3.25", c.Amount)
+	assert.Error(t, err)
+}
+
+func TestExchangeEntry_BalancedPerCurrency(t *testing.T) {
+	c := Conversion{
+		FromCurrency:   BaseCurrency,
+		OriginalAmount: DecimalFromKopecks(9000),
+		Currency:       "USD",
+		Amount:         DecimalFromKopecks(100),
+	}
+	entry := ExchangeEntry(5, c, "exchange:1")
+	assert.NoError(t, entry.Validate())
+	assert.Equal(t, "", entry.Postings[0].Currency)
+	assert.Equal(t, "USD", entry.Postings[3].PostingCurrency())
+
+	// сумма по всем проводкам нулевая, но в каждой валюте баланс нарушен
+	entry.Postings[2].Amount = DecimalFromKopecks(-9000)
+	entry.Postings[1].Amount = DecimalFromKopecks(100)
+	assert.ErrorIs(t, entry.Validate(), ErrUnbalancedEntry)
+}
@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrRefundNotFound        = errors.New("refund not found")
+	ErrRefundNotAllowed      = errors.New("transaction cannot be refunded")
+	ErrRefundExceedsOriginal = errors.New("refund exceeds the refundable amount")
+	ErrAlreadyReversed       = errors.New("charge is already reversed")
+)
+
+type RefundKind string
+
+const (
+	RefundTopUp      RefundKind = "topup"      // возврат пополнения через платёжный шлюз
+	RefundImpression RefundKind = "impression" // отмена списания за показ
+)
+
+type RefundStatus string
+
+const (
+	RefundPending   RefundStatus = "pending"
+	RefundSucceeded RefundStatus = "succeeded"
+	RefundCanceled  RefundStatus = "canceled"
+)
+
+// Refund — отмена ранее проведённой операции. OriginalID — transaction_id
+// пополнения или ID проводки списания за показ
+type Refund struct {
+	ID          int          `json:"id"`
+	Kind        RefundKind   `json:"kind"`
+	OriginalID  string       `json:"original_id"`
+	UserID      int          `json:"user_id"`
+	PublisherID int          `json:"publisher_id,omitempty"`
+	Amount      Decimal      `json:"amount"`
+	Status      RefundStatus `json:"status"`
+	GatewayID   string       `json:"gateway_id,omitempty"`
+	Reason      string       `json:"reason"`
+	CreatedBy   int          `json:"created_by"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// TopUpRefundEntry возвращает деньги пополнения обратно во внешний шлюз
+func TopUpRefundEntry(userID int, amount Decimal, reference string) LedgerEntry {
+	return Transfer(EntryRefund, reference,
+		Posting{OwnerID: userID, Account: AccountAdvertiser},
+		Posting{Account: AccountGateway},
+		amount)
+}
+
+// ReversalEntry сторнирует проводку: те же счета с противоположными суммами
+func ReversalEntry(original LedgerEntry, reference string) LedgerEntry {
+	postings := make([]Posting, 0, len(original.Postings))
+	for _, p := range original.Postings {
+		p.Amount = p.Amount.Neg()
+		postings = append(postings, p)
+	}
+	return LedgerEntry{
+		Kind:      EntryRefund,
+		Reference: reference,
+		Postings:  postings,
+	}
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReversalEntry(t *testing.T) {
+	charge := ImpressionChargeEntry(1, 2, DecimalFromKopecks(1000), DecimalFromKopecks(150), "h1")
+	reversal := ReversalEntry(charge, "refund:1")
+
+	assert.NoError(t, reversal.Validate())
+	assert.Equal(t, EntryRefund, reversal.Kind)
+	assert.Len(t, reversal.Postings, 3)
+
+	for i, p := range reversal.Postings {
+		original, _ := charge.Postings[i].Amount.Kopecks()
+		reversed, _ := p.Amount.Kopecks()
+		assert.Equal(t, charge.Postings[i].OwnerID, p.OwnerID)
+		assert.Equal(t, -original, reversed)
+	}
+}
+
+func TestTopUpRefundEntry(t *testing.T) {
+	entry := TopUpRefundEntry(5, DecimalFromKopecks(3000), "refund:2")
+	assert.NoError(t, entry.Validate())
+	assert.Equal(t, AccountAdvertiser, entry.Postings[0].Account)
+	assert.Equal(t, AccountGateway, entry.Postings[1].Account)
+}
@@ -0,0 +1,302 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var (
+	ErrStatementNotFound      = errors.New("statement not found")
+	ErrStatementPeriodOpen    = errors.New("statement period is not finished yet")
+	ErrInvalidStatementFormat = errors.New("unknown statement format")
+)
+
+type StatementFormat string
+
+const (
+	StatementJSON StatementFormat = "json"
+	StatementCSV  StatementFormat = "csv"
+	StatementPDF  StatementFormat = "pdf"
+)
+
+var StatementFormats = []StatementFormat{StatementJSON, StatementCSV, StatementPDF}
+
+func ParseStatementFormat(s string) (StatementFormat, error) {
+	for _, f := range StatementFormats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidStatementFormat, s)
+}
+
+func (f StatementFormat) ContentType() string {
+	switch f {
+	case StatementCSV:
+		return "text/csv; charset=utf-8"
+	case StatementPDF:
+		return "application/pdf"
+	default:
+		return "application/json"
+	}
+}
+
+// StatementRow — обороты пользователя за период, сгруппированные по виду
+// проводки, типу и валюте счёта, размещению и валюте пересчёта. CounterAmount —
+// сумма тех же проводок в CounterCurrency, пустой CounterCurrency означает,
+// что пересчёта не было
+type StatementRow struct {
+	Kind            EntryKind
+	Account         AccountType
+	Currency        string
+	BannerID        int64
+	SlotLink        string
+	CounterCurrency string
+	Count           int
+	Amount          Decimal
+	CounterAmount   Decimal
+}
+
+// StatementLine — строка разбивки: расход по баннеру или доход по слоту.
+// Для ставок в другой валюте рядом с пересчитанной суммой показана исходная
+type StatementLine struct {
+	BannerID         int64    `json:"banner_id,omitempty"`
+	SlotLink         string   `json:"slot_link,omitempty"`
+	Impressions      int      `json:"impressions"`
+	Amount           Decimal  `json:"amount"`
+	OriginalCurrency string   `json:"original_currency,omitempty"`
+	OriginalAmount   *Decimal `json:"original_amount,omitempty"`
+}
+
+// StatementExchange — обмен между базовой валютой и Currency за период.
+// Amount — изменение базового счёта, OriginalAmount — сумма в Currency
+type StatementExchange struct {
+	Currency       string  `json:"currency"`
+	Amount         Decimal `json:"amount"`
+	OriginalAmount Decimal `json:"original_amount"`
+}
+
+// CurrencyAmount — сумма в указанной валюте
+type CurrencyAmount struct {
+	Currency string  `json:"currency"`
+	Amount   Decimal `json:"amount"`
+}
+
+// Statement — выписка за период [PeriodStart, PeriodEnd) в базовой валюте.
+// Остатки включают промо-кредит, расход — в том числе оплаченный промо-кредитом.
+// Расход, доход и выплаты указаны положительными числами, возвраты, обмен и
+// прочие движения — со знаком. Движения по счетам в других валютах собраны в
+// ForeignMovements и в остатки не входят
+type Statement struct {
+	UserID           int                 `json:"user_id"`
+	PeriodStart      time.Time           `json:"period_start"`
+	PeriodEnd        time.Time           `json:"period_end"`
+	OpeningBalance   Decimal             `json:"opening_balance"`
+	TopUps           Decimal             `json:"top_ups"`
+	PromoCredits     Decimal             `json:"promo_credits"`
+	Spend            Decimal             `json:"spend"`
+	Earnings         Decimal             `json:"earnings"`
+	Payouts          Decimal             `json:"payouts"`
+	Refunds          Decimal             `json:"refunds"`
+	Exchanges        Decimal             `json:"exchanges"`
+	Other            Decimal             `json:"other"`
+	ClosingBalance   Decimal             `json:"closing_balance"`
+	SpendByBanner    []StatementLine     `json:"spend_by_banner"`
+	EarningsBySlot   []StatementLine     `json:"earnings_by_slot"`
+	ExchangeLines    []StatementExchange `json:"exchange_lines"`
+	ForeignMovements []CurrencyAmount    `json:"foreign_movements"`
+	CreatedAt        time.Time           `json:"created_at"`
+}
+
+// StatementInfo — запись о готовой выписке; файлы лежат в хранилище под ObjectPrefix
+type StatementInfo struct {
+	ID             int       `json:"id"`
+	UserID         int       `json:"user_id"`
+	PeriodStart    time.Time `json:"period_start"`
+	PeriodEnd      time.Time `json:"period_end"`
+	OpeningBalance Decimal   `json:"opening_balance"`
+	ClosingBalance Decimal   `json:"closing_balance"`
+	ObjectPrefix   string    `json:"-"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ObjectName — имя файла выписки в хранилище
+func (s StatementInfo) ObjectName(format StatementFormat) string {
+	return s.ObjectPrefix + "." + string(format)
+}
+
+// StatementObjectPrefix — общий префикс файлов выписки пользователя за период
+func StatementObjectPrefix(userID int, periodStart time.Time) string {
+	return fmt.Sprintf("%d/%s", userID, periodStart.Format("2006-01"))
+}
+
+// StatementPeriod возвращает границы календарного месяца, в который попадает t (UTC)
+func StatementPeriod(t time.Time) (time.Time, time.Time) {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// BuildStatement сводит обороты из журнала в выписку. Остаток на конец
+// периода — остаток на начало плюс все движения за период
+func BuildStatement(userID int, start, end time.Time, opening Decimal, rows []StatementRow) (Statement, error) {
+	openingKopecks, err := opening.Kopecks()
+	if err != nil {
+		return Statement{}, err
+	}
+
+	var topUps, promoCredits, spend, earnings, payouts, refunds, exchanges, other, total int64
+	spendByBanner := make(map[lineKey]*lineTotal)
+	earningsBySlot := make(map[lineKey]*lineTotal)
+	exchangeLines := make(map[string]*exchangeTotal)
+	foreign := make(map[string]int64)
+
+	for _, row := range rows {
+		amount, err := row.Amount.Kopecks()
+		if err != nil {
+			return Statement{}, err
+		}
+		counter, err := row.CounterAmount.Kopecks()
+		if err != nil {
+			return Statement{}, err
+		}
+		if row.Currency != "" && row.Currency != BaseCurrency {
+			foreign[row.Currency] += amount
+			continue
+		}
+		total += amount
+
+		switch {
+		case row.Kind == EntryTopUp:
+			topUps += amount
+		case row.Kind == EntryImpressionCharge && row.Account == AccountAdvertiser:
+			spend -= amount
+			addLine(spendByBanner, lineKey{bannerID: row.BannerID, currency: row.CounterCurrency}, row.Count, -amount, counter)
+		case row.Kind == EntryImpressionCharge && row.Account == AccountPublisher:
+			earnings += amount
+			addLine(earningsBySlot, lineKey{slotLink: row.SlotLink}, row.Count, amount, 0)
+		case row.Kind == EntryPayout || row.Kind == EntryPayoutHold || row.Kind == EntryPayoutRelease:
+			payouts -= amount
+		case row.Kind == EntryRefund:
+			refunds += amount
+		case row.Kind == EntryPromoCredit:
+			promoCredits += amount
+		case row.Kind == EntryConversion:
+			exchanges += amount
+			line, ok := exchangeLines[row.CounterCurrency]
+			if !ok {
+				line = &exchangeTotal{}
+				exchangeLines[row.CounterCurrency] = line
+			}
+			line.kopecks += amount
+			line.counter += counter
+		default:
+			other += amount
+		}
+	}
+
+	s := Statement{
+		UserID:           userID,
+		PeriodStart:      start,
+		PeriodEnd:        end,
+		OpeningBalance:   DecimalFromKopecks(openingKopecks),
+		TopUps:           DecimalFromKopecks(topUps),
+		PromoCredits:     DecimalFromKopecks(promoCredits),
+		Spend:            DecimalFromKopecks(spend),
+		Earnings:         DecimalFromKopecks(earnings),
+		Payouts:          DecimalFromKopecks(payouts),
+		Refunds:          DecimalFromKopecks(refunds),
+		Exchanges:        DecimalFromKopecks(exchanges),
+		Other:            DecimalFromKopecks(other),
+		ClosingBalance:   DecimalFromKopecks(openingKopecks + total),
+		SpendByBanner:    make([]StatementLine, 0, len(spendByBanner)),
+		EarningsBySlot:   make([]StatementLine, 0, len(earningsBySlot)),
+		ExchangeLines:    make([]StatementExchange, 0, len(exchangeLines)),
+		ForeignMovements: make([]CurrencyAmount, 0, len(foreign)),
+	}
+	for key, line := range spendByBanner {
+		s.SpendByBanner = append(s.SpendByBanner, line.statementLine(key))
+	}
+	for key, line := range earningsBySlot {
+		s.EarningsBySlot = append(s.EarningsBySlot, line.statementLine(key))
+	}
+	for currency, line := range exchangeLines {
+		s.ExchangeLines = append(s.ExchangeLines, StatementExchange{
+			Currency:       currency,
+			Amount:         DecimalFromKopecks(line.kopecks),
+			OriginalAmount: DecimalFromKopecks(line.counter),
+		})
+	}
+	for currency, kopecks := range foreign {
+		s.ForeignMovements = append(s.ForeignMovements, CurrencyAmount{Currency: currency, Amount: DecimalFromKopecks(kopecks)})
+	}
+	sortStatementLines(s.SpendByBanner)
+	sortStatementLines(s.EarningsBySlot)
+	sort.Slice(s.ExchangeLines, func(i, j int) bool { return s.ExchangeLines[i].Currency < s.ExchangeLines[j].Currency })
+	sort.Slice(s.ForeignMovements, func(i, j int) bool { return s.ForeignMovements[i].Currency < s.ForeignMovements[j].Currency })
+	return s, nil
+}
+
+// lineKey — строка разбивки: баннер или слот и валюта исходной ставки
+type lineKey struct {
+	bannerID int64
+	slotLink string
+	currency string
+}
+
+type exchangeTotal struct {
+	kopecks int64
+	counter int64
+}
+
+type lineTotal struct {
+	count    int
+	kopecks  int64
+	original int64
+}
+
+func addLine(lines map[lineKey]*lineTotal, key lineKey, count int, kopecks, original int64) {
+	line, ok := lines[key]
+	if !ok {
+		line = &lineTotal{}
+		lines[key] = line
+	}
+	line.count += count
+	line.kopecks += kopecks
+	line.original += original
+}
+
+func (l *lineTotal) statementLine(key lineKey) StatementLine {
+	line := StatementLine{
+		BannerID:    key.bannerID,
+		SlotLink:    key.slotLink,
+		Impressions: l.count,
+		Amount:      DecimalFromKopecks(l.kopecks),
+	}
+	if key.currency != "" && key.currency != BaseCurrency {
+		original := DecimalFromKopecks(l.original)
+		line.OriginalCurrency = key.currency
+		line.OriginalAmount = &original
+	}
+	return line
+}
+
+// строки с большей суммой идут первыми, при равенстве — по ID баннера и ссылке слота
+func sortStatementLines(lines []StatementLine) {
+	sort.Slice(lines, func(i, j int) bool {
+		a, _ := lines[i].Amount.Kopecks()
+		b, _ := lines[j].Amount.Kopecks()
+		if a != b {
+			return a > b
+		}
+		if lines[i].BannerID != lines[j].BannerID {
+			return lines[i].BannerID < lines[j].BannerID
+		}
+		if lines[i].SlotLink != lines[j].SlotLink {
+			return lines[i].SlotLink < lines[j].SlotLink
+		}
+		return lines[i].OriginalCurrency < lines[j].OriginalCurrency
+	})
+}
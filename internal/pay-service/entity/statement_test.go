@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatementPeriod(t *testing.T) {
+	start, end := StatementPeriod(time.Date(2025, time.December, 31, 23, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), end)
+}
+
+func TestBuildStatement(t *testing.T) {
+	start, end := StatementPeriod(time.Date(2025, time.April, 10, 0, 0, 0, 0, time.UTC))
+	rows := []StatementRow{
+		{Kind: EntryTopUp, Account: AccountAdvertiser, Count: 1, Amount: DecimalFromKopecks(50000)},
+		{Kind: EntryImpressionCharge, Account: AccountAdvertiser, BannerID: 7, Count: 3, Amount: DecimalFromKopecks(-900)},
+		{Kind: EntryImpressionCharge, Account: AccountAdvertiser, BannerID: 9, Count: 2, Amount: DecimalFromKopecks(-1200)},
+		{Kind: EntryImpressionCharge, Account: AccountPublisher, SlotLink: "slot-a", Count: 4, Amount: DecimalFromKopecks(2000)},
+		{Kind: EntryPayoutHold, Account: AccountPublisher, Count: 1, Amount: DecimalFromKopecks(-1500)},
+		{Kind: EntryPayoutRelease, Account: AccountPublisher, Count: 1, Amount: DecimalFromKopecks(500)},
+		{Kind: EntryRefund, Account: AccountAdvertiser, Count: 1, Amount: DecimalFromKopecks(300)},
+	}
+
+	s, err := BuildStatement(5, start, end, DecimalFromKopecks(1000), rows)
+	assert.NoError(t, err)
+	assert.Equal(t, "500.00", s.TopUps.String())
+	assert.Equal(t, "21.00", s.Spend.String())
+	assert.Equal(t, "20.00", s.Earnings.String())
+	assert.Equal(t, "10.00", s.Payouts.String())
+	assert.Equal(t, "3.00", s.Refunds.String())
+	assert.Equal(t, "0.00", s.Other.String())
+	assert.Equal(t, "502.00", s.ClosingBalance.String())
+
+	assert.Len(t, s.SpendByBanner, 2)
+	assert.Equal(t, int64(9), s.SpendByBanner[0].BannerID)
+	assert.Equal(t, "12.00", s.SpendByBanner[0].Amount.String())
+	assert.Equal(t, 2, s.SpendByBanner[0].Impressions)
+	assert.Equal(t, []StatementLine{{SlotLink: "slot-a", Impressions: 4, Amount: DecimalFromKopecks(2000)}}, s.EarningsBySlot)
+}
+
+func TestParseStatementFormat(t *testing.T) {
+	format, err := ParseStatementFormat("pdf")
+	assert.NoError(t, err)
+	assert.Equal(t, "application/pdf", format.ContentType())
+
+	_, err = ParseStatementFormat("xlsx")
+	assert.ErrorIs(t, err, ErrInvalidStatementFormat)
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+
+	"retarget/pkg/utils/throttle"
+)
+
+// Действия, неудачные попытки которых ограничиваются. Ключи внутри правила —
+// аккаунт и адрес клиента
+const (
+	ThrottlePromoRedeem = "promo_redeem"
+	ThrottlePayout      = "payout"
+)
+
+// PromoRedeemPolicy — неверные или недоступные промокоды: без ограничения
+// коды подбирали бы перебором
+var PromoRedeemPolicy = throttle.Policy{
+	FreeAttempts: 5,
+	BaseDelay:    2 * time.Second,
+	MaxDelay:     time.Minute,
+	LockoutAfter: 20,
+	LockoutFor:   time.Hour,
+	Window:       time.Hour,
+}
+
+// PayoutThrottlePolicy — отклонённые заявки на вывод: реквизиты и суммы
+// не перебираются, поэтому порог ниже, а неудачи помнятся сутки
+var PayoutThrottlePolicy = throttle.Policy{
+	FreeAttempts: 3,
+	BaseDelay:    5 * time.Second,
+	MaxDelay:     5 * time.Minute,
+	LockoutAfter: 10,
+	LockoutFor:   time.Hour,
+	Window:       24 * time.Hour,
+}
@@ -0,0 +1,49 @@
+package entity
+
+import "strings"
+
+// Статусы transaction.status
+const (
+	TransactionPending   = 0
+	TransactionSucceeded = 1
+	TransactionCanceled  = 2
+)
+
+// YooNotification — тело входящего уведомления YooKassa. Статусу из
+// уведомления не доверяем, объект всегда перезапрашивается по ID
+type YooNotification struct {
+	Type   string `json:"type"`
+	Event  string `json:"event"`
+	Object struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	} `json:"object"`
+}
+
+// ObjectType возвращает раздел API YooKassa, к которому относится событие
+func (n YooNotification) ObjectType() string {
+	switch {
+	case strings.HasPrefix(n.Event, "payment."):
+		return "payments"
+	case strings.HasPrefix(n.Event, "payout."):
+		return "payouts"
+	case strings.HasPrefix(n.Event, "refund."):
+		return "refunds"
+	default:
+		return ""
+	}
+}
+
+// IsWithdrawal сообщает, что деньги по транзакции уже списаны с баланса при
+// её создании, и при отмене их нужно вернуть
+func (t Transaction) IsWithdrawal() bool {
+	return strings.HasPrefix(t.Type, "payout_") || t.Type == "withdrawal_payment"
+}
+
+// YooObjectType возвращает раздел API YooKassa, в котором живёт транзакция
+func (t Transaction) YooObjectType() string {
+	if strings.HasPrefix(t.Type, "payout_") {
+		return "payouts"
+	}
+	return "payments"
+}
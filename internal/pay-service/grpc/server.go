@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net"
 	"strconv"
@@ -43,11 +44,11 @@ func RunGRPCServer(paymentUC usecase.PaymentUsecase) {
 }
 
 func (s *PaymentServer) RegUserActivity(ctx context.Context, req *paymentpb.PaymentRequest) (*paymentpb.PaymentResponse, error) {
-	amount := entity.Decimal{}
-	if err := amount.ParseFromString(req.GetAmount()); err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, "failed to parse amount: %v", err)
+	amount, placement, err := s.bidFromRequest(req)
+	if err != nil {
+		return nil, err
 	}
-	err := s.paymentUC.RegUserActivity(int(req.GetToUserId()), int(req.GetFromUserId()), amount)
+	err = s.paymentUC.RegUserActivity(int(req.GetToUserId()), int(req.GetFromUserId()), amount, req.GetReservationId(), placement)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to process payment: %v", err)
 	}
@@ -58,3 +59,88 @@ func (s *PaymentServer) RegUserActivity(ctx context.Context, req *paymentpb.Paym
 	}
 	return response, nil
 }
+
+func (s *PaymentServer) ReserveSpend(ctx context.Context, req *paymentpb.PaymentRequest) (*paymentpb.ReserveResponse, error) {
+	amount, placement, err := s.bidFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	reservationID, err := s.paymentUC.ReserveSpend(int(req.GetFromUserId()), int(req.GetToUserId()), amount, placement)
+	if errors.Is(err, usecase.ErrInsufficientFunds) {
+		return &paymentpb.ReserveResponse{Reserved: false}, nil
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to reserve spend: %v", err)
+	}
+
+	return &paymentpb.ReserveResponse{
+		Reserved:      true,
+		ReservationId: reservationID,
+	}, nil
+}
+
+// bidFromRequest переводит ставку в базовую валюту; пересчёт уходит в
+// размещение и сохраняется вместе со списанием
+func (s *PaymentServer) bidFromRequest(req *paymentpb.PaymentRequest) (entity.Decimal, entity.Placement, error) {
+	amount := entity.Decimal{}
+	if err := amount.ParseFromString(req.GetAmount()); err != nil {
+		return amount, entity.Placement{}, status.Errorf(codes.InvalidArgument, "failed to parse amount: %v", err)
+	}
+	placement := entity.Placement{BannerID: req.GetBannerId(), SlotLink: req.GetSlotLink()}
+	base, conversion, err := s.paymentUC.ToBase(amount, req.GetCurrency())
+	if errors.Is(err, entity.ErrInvalidCurrency) || errors.Is(err, entity.ErrRateNotFound) {
+		return amount, placement, status.Errorf(codes.InvalidArgument, "failed to convert bid: %v", err)
+	}
+	if err != nil {
+		return amount, placement, status.Errorf(codes.Internal, "failed to convert bid: %v", err)
+	}
+	placement.Conversion = conversion
+	return base, placement, nil
+}
+
+func (s *PaymentServer) GetBalanceAlerts(ctx context.Context, req *paymentpb.BalanceAlertsRequest) (*paymentpb.BalanceAlerts, error) {
+	settings, err := s.paymentUC.GetBalanceAlerts(int(req.GetUserId()))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get balance alerts: %v", err)
+	}
+	return balanceAlertsToProto(req.GetUserId(), settings), nil
+}
+
+func (s *PaymentServer) SetBalanceAlerts(ctx context.Context, req *paymentpb.BalanceAlerts) (*paymentpb.BalanceAlerts, error) {
+	settings := entity.BalanceAlertSettings{AutoPause: req.GetAutoPause()}
+	for _, raw := range req.GetThresholds() {
+		threshold := entity.Decimal{}
+		if err := threshold.ParseFromString(raw); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%v: threshold %q", entity.ErrInvalidBalanceAlerts, raw)
+		}
+		settings.Thresholds = append(settings.Thresholds, threshold)
+	}
+	// пустой порог автопаузы — ноль
+	if raw := req.GetPauseFloor(); raw != "" {
+		if err := settings.PauseFloor.ParseFromString(raw); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%v: pause floor %q", entity.ErrInvalidBalanceAlerts, raw)
+		}
+	}
+
+	settings, err := s.paymentUC.SetBalanceAlerts(int(req.GetUserId()), settings)
+	if errors.Is(err, entity.ErrInvalidBalanceAlerts) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save balance alerts: %v", err)
+	}
+	return balanceAlertsToProto(req.GetUserId(), settings), nil
+}
+
+func balanceAlertsToProto(userID int32, settings entity.BalanceAlertSettings) *paymentpb.BalanceAlerts {
+	thresholds := make([]string, 0, len(settings.Thresholds))
+	for _, t := range settings.Thresholds {
+		thresholds = append(thresholds, t.String())
+	}
+	return &paymentpb.BalanceAlerts{
+		UserId:     userID,
+		Thresholds: thresholds,
+		AutoPause:  settings.AutoPause,
+		PauseFloor: settings.PauseFloor.String(),
+	}
+}
@@ -0,0 +1,318 @@
+//go:build !exclude_tests
+
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	budget "retarget/internal/pay-service/repo/budget"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// BudgetRepositoryInterface is an autogenerated mock type for the BudgetRepositoryInterface type
+type BudgetRepositoryInterface struct {
+	mock.Mock
+}
+
+// Adjust provides a mock function with given fields: userID, kopecks
+func (_m *BudgetRepositoryInterface) Adjust(userID int, kopecks int64) error {
+	ret := _m.Called(userID, kopecks)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Adjust")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int64) error); ok {
+		r0 = rf(userID, kopecks)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Capture provides a mock function with given fields: holdID
+func (_m *BudgetRepositoryInterface) Capture(holdID string) (int64, error) {
+	ret := _m.Called(holdID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Capture")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (int64, error)); ok {
+		return rf(holdID)
+	}
+	if rf, ok := ret.Get(0).(func(string) int64); ok {
+		r0 = rf(holdID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(holdID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Charge provides a mock function with given fields: advertiserID, publisherID, kopecks, feeKopecks, bannerID, slotLink, conversion
+func (_m *BudgetRepositoryInterface) Charge(advertiserID int, publisherID int, kopecks int64, feeKopecks int64, bannerID int64, slotLink string, conversion string) (int64, error) {
+	ret := _m.Called(advertiserID, publisherID, kopecks, feeKopecks, bannerID, slotLink, conversion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Charge")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int, int64, int64, int64, string, string) (int64, error)); ok {
+		return rf(advertiserID, publisherID, kopecks, feeKopecks, bannerID, slotLink, conversion)
+	}
+	if rf, ok := ret.Get(0).(func(int, int, int64, int64, int64, string, string) int64); ok {
+		r0 = rf(advertiserID, publisherID, kopecks, feeKopecks, bannerID, slotLink, conversion)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int, int64, int64, int64, string, string) error); ok {
+		r1 = rf(advertiserID, publisherID, kopecks, feeKopecks, bannerID, slotLink, conversion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CloseConnection provides a mock function with no fields
+func (_m *BudgetRepositoryInterface) CloseConnection() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for CloseConnection")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CompleteBatch provides a mock function with no fields
+func (_m *BudgetRepositoryInterface) CompleteBatch() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteBatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FlushingBatchID provides a mock function with no fields
+func (_m *BudgetRepositoryInterface) FlushingBatchID() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for FlushingBatchID")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAvailable provides a mock function with given fields: userID
+func (_m *BudgetRepositoryInterface) GetAvailable(userID int) (int64, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAvailable")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (int64, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int) int64); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoadBalance provides a mock function with given fields: userID, kopecks, batchID, includeFlushing
+func (_m *BudgetRepositoryInterface) LoadBalance(userID int, kopecks int64, batchID string, includeFlushing bool) error {
+	ret := _m.Called(userID, kopecks, batchID, includeFlushing)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LoadBalance")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int64, string, bool) error); ok {
+		r0 = rf(userID, kopecks, batchID, includeFlushing)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReleaseExpired provides a mock function with given fields: now
+func (_m *BudgetRepositoryInterface) ReleaseExpired(now time.Time) (int, error) {
+	ret := _m.Called(now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseExpired")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (int, error)); ok {
+		return rf(now)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) int); ok {
+		r0 = rf(now)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reserve provides a mock function with given fields: holdID, advertiserID, publisherID, kopecks, feeKopecks, bannerID, slotLink, conversion
+func (_m *BudgetRepositoryInterface) Reserve(holdID string, advertiserID int, publisherID int, kopecks int64, feeKopecks int64, bannerID int64, slotLink string, conversion string) (int64, error) {
+	ret := _m.Called(holdID, advertiserID, publisherID, kopecks, feeKopecks, bannerID, slotLink, conversion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reserve")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int, int, int64, int64, int64, string, string) (int64, error)); ok {
+		return rf(holdID, advertiserID, publisherID, kopecks, feeKopecks, bannerID, slotLink, conversion)
+	}
+	if rf, ok := ret.Get(0).(func(string, int, int, int64, int64, int64, string, string) int64); ok {
+		r0 = rf(holdID, advertiserID, publisherID, kopecks, feeKopecks, bannerID, slotLink, conversion)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int, int, int64, int64, int64, string, string) error); ok {
+		r1 = rf(holdID, advertiserID, publisherID, kopecks, feeKopecks, bannerID, slotLink, conversion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TakeBatch provides a mock function with given fields: batchID
+func (_m *BudgetRepositoryInterface) TakeBatch(batchID string) (string, map[int]int64, []budget.Charge, error) {
+	ret := _m.Called(batchID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TakeBatch")
+	}
+
+	var r0 string
+	var r1 map[int]int64
+	var r2 []budget.Charge
+	var r3 error
+	if rf, ok := ret.Get(0).(func(string) (string, map[int]int64, []budget.Charge, error)); ok {
+		return rf(batchID)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(batchID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) map[int]int64); ok {
+		r1 = rf(batchID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(map[int]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(string) []budget.Charge); ok {
+		r2 = rf(batchID)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).([]budget.Charge)
+		}
+	}
+
+	if rf, ok := ret.Get(3).(func(string) error); ok {
+		r3 = rf(batchID)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// NewBudgetRepositoryInterface creates a new instance of BudgetRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBudgetRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BudgetRepositoryInterface {
+	mock := &BudgetRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
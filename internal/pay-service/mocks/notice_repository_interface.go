@@ -4,7 +4,11 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	entity "retarget/pkg/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // NoticeRepositoryInterface is an autogenerated mock type for the NoticeRepositoryInterface type
 type NoticeRepositoryInterface struct {
@@ -16,17 +20,53 @@ func (_m *NoticeRepositoryInterface) Close() {
 	_m.Called()
 }
 
-// SendLowBalanceNotification provides a mock function with given fields: userID, message
-func (_m *NoticeRepositoryInterface) SendLowBalanceNotification(userID int, message string) error {
-	ret := _m.Called(userID, message)
+// SendAutoRechargeFailedEvent provides a mock function with given fields: userID, amount, reason
+func (_m *NoticeRepositoryInterface) SendAutoRechargeFailedEvent(userID int, amount entity.Decimal, reason string) error {
+	ret := _m.Called(userID, amount, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendAutoRechargeFailedEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, entity.Decimal, string) error); ok {
+		r0 = rf(userID, amount, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendLowBalanceNotification provides a mock function with given fields: userID, threshold, balance
+func (_m *NoticeRepositoryInterface) SendLowBalanceNotification(userID int, threshold entity.Decimal, balance entity.Decimal) error {
+	ret := _m.Called(userID, threshold, balance)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SendLowBalanceNotification")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int, string) error); ok {
-		r0 = rf(userID, message)
+	if rf, ok := ret.Get(0).(func(int, entity.Decimal, entity.Decimal) error); ok {
+		r0 = rf(userID, threshold, balance)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendStatementReadyEvent provides a mock function with given fields: userID, statementID, period
+func (_m *NoticeRepositoryInterface) SendStatementReadyEvent(userID int, statementID int, period string) error {
+	ret := _m.Called(userID, statementID, period)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendStatementReadyEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int, string) error); ok {
+		r0 = rf(userID, statementID, period)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -34,17 +74,17 @@ func (_m *NoticeRepositoryInterface) SendLowBalanceNotification(userID int, mess
 	return r0
 }
 
-// SendTopUpBalanceEvent provides a mock function with given fields: userID, message
-func (_m *NoticeRepositoryInterface) SendTopUpBalanceEvent(userID int, message string) error {
-	ret := _m.Called(userID, message)
+// SendTopUpBalanceEvent provides a mock function with given fields: userID, amount
+func (_m *NoticeRepositoryInterface) SendTopUpBalanceEvent(userID int, amount entity.Decimal) error {
+	ret := _m.Called(userID, amount)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SendTopUpBalanceEvent")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int, string) error); ok {
-		r0 = rf(userID, message)
+	if rf, ok := ret.Get(0).(func(int, entity.Decimal) error); ok {
+		r0 = rf(userID, amount)
 	} else {
 		r0 = ret.Error(0)
 	}
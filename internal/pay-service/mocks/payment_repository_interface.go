@@ -9,6 +9,14 @@ import (
 	entity "retarget/internal/pay-service/entity"
 
 	mock "github.com/stretchr/testify/mock"
+
+	pkgentity "retarget/pkg/entity"
+
+	sql "database/sql"
+
+	time "time"
+
+	zap "go.uber.org/zap"
 )
 
 // PaymentRepositoryInterface is an autogenerated mock type for the PaymentRepositoryInterface type
@@ -16,6 +24,143 @@ type PaymentRepositoryInterface struct {
 	mock.Mock
 }
 
+// ActivatePaymentMethod provides a mock function with given fields: transactionID
+func (_m *PaymentRepositoryInterface) ActivatePaymentMethod(transactionID string) (entity.PaymentMethod, bool, error) {
+	ret := _m.Called(transactionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ActivatePaymentMethod")
+	}
+
+	var r0 entity.PaymentMethod
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string) (entity.PaymentMethod, bool, error)); ok {
+		return rf(transactionID)
+	}
+	if rf, ok := ret.Get(0).(func(string) entity.PaymentMethod); ok {
+		r0 = rf(transactionID)
+	} else {
+		r0 = ret.Get(0).(entity.PaymentMethod)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) bool); ok {
+		r1 = rf(transactionID)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(transactionID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ApplyBudgetBatch provides a mock function with given fields: batchID, deltas, charges
+func (_m *PaymentRepositoryInterface) ApplyBudgetBatch(batchID string, deltas map[int]pkgentity.Decimal, charges []entity.LedgerEntry) error {
+	ret := _m.Called(batchID, deltas, charges)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyBudgetBatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, map[int]pkgentity.Decimal, []entity.LedgerEntry) error); ok {
+		r0 = rf(batchID, deltas, charges)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AttachAutoRechargeTransaction provides a mock function with given fields: pendingID, trx
+func (_m *PaymentRepositoryInterface) AttachAutoRechargeTransaction(pendingID string, trx entity.Transaction) error {
+	ret := _m.Called(pendingID, trx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AttachAutoRechargeTransaction")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, entity.Transaction) error); ok {
+		r0 = rf(pendingID, trx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AttachTopUpPromo provides a mock function with given fields: transactionID, code, userID
+func (_m *PaymentRepositoryInterface) AttachTopUpPromo(transactionID string, code string, userID int) error {
+	ret := _m.Called(transactionID, code, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AttachTopUpPromo")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, int) error); ok {
+		r0 = rf(transactionID, code, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClaimStalePendingTransactions provides a mock function with given fields: olderThan, limit
+func (_m *PaymentRepositoryInterface) ClaimStalePendingTransactions(olderThan time.Time, limit int) ([]entity.Transaction, error) {
+	ret := _m.Called(olderThan, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimStalePendingTransactions")
+	}
+
+	var r0 []entity.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, int) ([]entity.Transaction, error)); ok {
+		return rf(olderThan, limit)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, int) []entity.Transaction); ok {
+		r0 = rf(olderThan, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, int) error); ok {
+		r1 = rf(olderThan, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClearBalanceAlerts provides a mock function with given fields: userID, thresholds
+func (_m *PaymentRepositoryInterface) ClearBalanceAlerts(userID int, thresholds []pkgentity.Decimal) error {
+	ret := _m.Called(userID, thresholds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClearBalanceAlerts")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, []pkgentity.Decimal) error); ok {
+		r0 = rf(userID, thresholds)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CloseConnection provides a mock function with no fields
 func (_m *PaymentRepositoryInterface) CloseConnection() error {
 	ret := _m.Called()
@@ -34,6 +179,90 @@ func (_m *PaymentRepositoryInterface) CloseConnection() error {
 	return r0
 }
 
+// CreatePayoutRequest provides a mock function with given fields: p, policy, now
+func (_m *PaymentRepositoryInterface) CreatePayoutRequest(p entity.PayoutRequest, policy entity.PayoutPolicy, now time.Time) (entity.PayoutRequest, error) {
+	ret := _m.Called(p, policy, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePayoutRequest")
+	}
+
+	var r0 entity.PayoutRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.PayoutRequest, entity.PayoutPolicy, time.Time) (entity.PayoutRequest, error)); ok {
+		return rf(p, policy, now)
+	}
+	if rf, ok := ret.Get(0).(func(entity.PayoutRequest, entity.PayoutPolicy, time.Time) entity.PayoutRequest); ok {
+		r0 = rf(p, policy, now)
+	} else {
+		r0 = ret.Get(0).(entity.PayoutRequest)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.PayoutRequest, entity.PayoutPolicy, time.Time) error); ok {
+		r1 = rf(p, policy, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreatePromoCode provides a mock function with given fields: p
+func (_m *PaymentRepositoryInterface) CreatePromoCode(p entity.PromoCode) (entity.PromoCode, error) {
+	ret := _m.Called(p)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePromoCode")
+	}
+
+	var r0 entity.PromoCode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.PromoCode) (entity.PromoCode, error)); ok {
+		return rf(p)
+	}
+	if rf, ok := ret.Get(0).(func(entity.PromoCode) entity.PromoCode); ok {
+		r0 = rf(p)
+	} else {
+		r0 = ret.Get(0).(entity.PromoCode)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.PromoCode) error); ok {
+		r1 = rf(p)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateTopUpRefund provides a mock function with given fields: refund
+func (_m *PaymentRepositoryInterface) CreateTopUpRefund(refund entity.Refund) (entity.Refund, error) {
+	ret := _m.Called(refund)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTopUpRefund")
+	}
+
+	var r0 entity.Refund
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.Refund) (entity.Refund, error)); ok {
+		return rf(refund)
+	}
+	if rf, ok := ret.Get(0).(func(entity.Refund) entity.Refund); ok {
+		r0 = rf(refund)
+	} else {
+		r0 = ret.Get(0).(entity.Refund)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.Refund) error); ok {
+		r1 = rf(refund)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateTransaction provides a mock function with given fields: trx
 func (_m *PaymentRepositoryInterface) CreateTransaction(trx entity.Transaction) error {
 	ret := _m.Called(trx)
@@ -70,27 +299,45 @@ func (_m *PaymentRepositoryInterface) DeactivateBannersByUserID(ctx context.Cont
 	return r0
 }
 
-// GetBalanceByUserId provides a mock function with given fields: id, requestID
-func (_m *PaymentRepositoryInterface) GetBalanceByUserId(id int, requestID string) (float64, error) {
-	ret := _m.Called(id, requestID)
+// DeletePaymentMethod provides a mock function with given fields: userID, methodID
+func (_m *PaymentRepositoryInterface) DeletePaymentMethod(userID int, methodID string) error {
+	ret := _m.Called(userID, methodID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetBalanceByUserId")
+		panic("no return value specified for DeletePaymentMethod")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(userID, methodID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExchangeBalance provides a mock function with given fields: userID, entry
+func (_m *PaymentRepositoryInterface) ExchangeBalance(userID int, entry entity.LedgerEntry) (int64, error) {
+	ret := _m.Called(userID, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExchangeBalance")
 	}
 
-	var r0 float64
+	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, string) (float64, error)); ok {
-		return rf(id, requestID)
+	if rf, ok := ret.Get(0).(func(int, entity.LedgerEntry) (int64, error)); ok {
+		return rf(userID, entry)
 	}
-	if rf, ok := ret.Get(0).(func(int, string) float64); ok {
-		r0 = rf(id, requestID)
+	if rf, ok := ret.Get(0).(func(int, entity.LedgerEntry) int64); ok {
+		r0 = rf(userID, entry)
 	} else {
-		r0 = ret.Get(0).(float64)
+		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(int, string) error); ok {
-		r1 = rf(id, requestID)
+	if rf, ok := ret.Get(1).(func(int, entity.LedgerEntry) error); ok {
+		r1 = rf(userID, entry)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -98,29 +345,29 @@ func (_m *PaymentRepositoryInterface) GetBalanceByUserId(id int, requestID strin
 	return r0, r1
 }
 
-// GetLastTransaction provides a mock function with given fields: userID, requestID
-func (_m *PaymentRepositoryInterface) GetLastTransaction(userID int, requestID string) (*entity.Transaction, error) {
-	ret := _m.Called(userID, requestID)
+// FindLedgerDrift provides a mock function with no fields
+func (_m *PaymentRepositoryInterface) FindLedgerDrift() ([]entity.LedgerDrift, error) {
+	ret := _m.Called()
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetLastTransaction")
+		panic("no return value specified for FindLedgerDrift")
 	}
 
-	var r0 *entity.Transaction
+	var r0 []entity.LedgerDrift
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, string) (*entity.Transaction, error)); ok {
-		return rf(userID, requestID)
+	if rf, ok := ret.Get(0).(func() ([]entity.LedgerDrift, error)); ok {
+		return rf()
 	}
-	if rf, ok := ret.Get(0).(func(int, string) *entity.Transaction); ok {
-		r0 = rf(userID, requestID)
+	if rf, ok := ret.Get(0).(func() []entity.LedgerDrift); ok {
+		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*entity.Transaction)
+			r0 = ret.Get(0).([]entity.LedgerDrift)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int, string) error); ok {
-		r1 = rf(userID, requestID)
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -128,29 +375,62 @@ func (_m *PaymentRepositoryInterface) GetLastTransaction(userID int, requestID s
 	return r0, r1
 }
 
-// GetPendingTransactions provides a mock function with given fields: userID
-func (_m *PaymentRepositoryInterface) GetPendingTransactions(userID int) ([]entity.Transaction, error) {
-	ret := _m.Called(userID)
+// FinishAutoRecharge provides a mock function with given fields: userID, pendingID, failure, now
+func (_m *PaymentRepositoryInterface) FinishAutoRecharge(userID int, pendingID string, failure string, now time.Time) (entity.AutoRecharge, bool, error) {
+	ret := _m.Called(userID, pendingID, failure, now)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetPendingTransactions")
+		panic("no return value specified for FinishAutoRecharge")
 	}
 
-	var r0 []entity.Transaction
+	var r0 entity.AutoRecharge
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(int, string, string, time.Time) (entity.AutoRecharge, bool, error)); ok {
+		return rf(userID, pendingID, failure, now)
+	}
+	if rf, ok := ret.Get(0).(func(int, string, string, time.Time) entity.AutoRecharge); ok {
+		r0 = rf(userID, pendingID, failure, now)
+	} else {
+		r0 = ret.Get(0).(entity.AutoRecharge)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, string, string, time.Time) bool); ok {
+		r1 = rf(userID, pendingID, failure, now)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(int, string, string, time.Time) error); ok {
+		r2 = rf(userID, pendingID, failure, now)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FinishRefund provides a mock function with given fields: refundID, status, gatewayID
+func (_m *PaymentRepositoryInterface) FinishRefund(refundID int, status entity.RefundStatus, gatewayID string) (bool, error) {
+	ret := _m.Called(refundID, status, gatewayID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishRefund")
+	}
+
+	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) ([]entity.Transaction, error)); ok {
-		return rf(userID)
+	if rf, ok := ret.Get(0).(func(int, entity.RefundStatus, string) (bool, error)); ok {
+		return rf(refundID, status, gatewayID)
 	}
-	if rf, ok := ret.Get(0).(func(int) []entity.Transaction); ok {
-		r0 = rf(userID)
+	if rf, ok := ret.Get(0).(func(int, entity.RefundStatus, string) bool); ok {
+		r0 = rf(refundID, status, gatewayID)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]entity.Transaction)
-		}
+		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(userID)
+	if rf, ok := ret.Get(1).(func(int, entity.RefundStatus, string) error); ok {
+		r1 = rf(refundID, status, gatewayID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -158,29 +438,27 @@ func (_m *PaymentRepositoryInterface) GetPendingTransactions(userID int) ([]enti
 	return r0, r1
 }
 
-// GetTransactionByID provides a mock function with given fields: transactionID, requestID
-func (_m *PaymentRepositoryInterface) GetTransactionByID(transactionID string, requestID string) (*entity.Transaction, error) {
-	ret := _m.Called(transactionID, requestID)
+// GetAutoRecharge provides a mock function with given fields: userID, now
+func (_m *PaymentRepositoryInterface) GetAutoRecharge(userID int, now time.Time) (entity.AutoRecharge, error) {
+	ret := _m.Called(userID, now)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTransactionByID")
+		panic("no return value specified for GetAutoRecharge")
 	}
 
-	var r0 *entity.Transaction
+	var r0 entity.AutoRecharge
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string) (*entity.Transaction, error)); ok {
-		return rf(transactionID, requestID)
+	if rf, ok := ret.Get(0).(func(int, time.Time) (entity.AutoRecharge, error)); ok {
+		return rf(userID, now)
 	}
-	if rf, ok := ret.Get(0).(func(string, string) *entity.Transaction); ok {
-		r0 = rf(transactionID, requestID)
+	if rf, ok := ret.Get(0).(func(int, time.Time) entity.AutoRecharge); ok {
+		r0 = rf(userID, now)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*entity.Transaction)
-		}
+		r0 = ret.Get(0).(entity.AutoRecharge)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(transactionID, requestID)
+	if rf, ok := ret.Get(1).(func(int, time.Time) error); ok {
+		r1 = rf(userID, now)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -188,62 +466,1570 @@ func (_m *PaymentRepositoryInterface) GetTransactionByID(transactionID string, r
 	return r0, r1
 }
 
-// RegUserActivity provides a mock function with given fields: user_banner_id, user_slot_id, amount
-func (_m *PaymentRepositoryInterface) RegUserActivity(user_banner_id int, user_slot_id int, amount entity.Decimal) (int, int, error) {
-	ret := _m.Called(user_banner_id, user_slot_id, amount)
+// GetBalanceAlertSettings provides a mock function with given fields: userID
+func (_m *PaymentRepositoryInterface) GetBalanceAlertSettings(userID int) (pkgentity.BalanceAlertSettings, error) {
+	ret := _m.Called(userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for RegUserActivity")
+		panic("no return value specified for GetBalanceAlertSettings")
 	}
 
-	var r0 int
-	var r1 int
-	var r2 error
-	if rf, ok := ret.Get(0).(func(int, int, entity.Decimal) (int, int, error)); ok {
-		return rf(user_banner_id, user_slot_id, amount)
+	var r0 pkgentity.BalanceAlertSettings
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (pkgentity.BalanceAlertSettings, error)); ok {
+		return rf(userID)
 	}
-	if rf, ok := ret.Get(0).(func(int, int, entity.Decimal) int); ok {
-		r0 = rf(user_banner_id, user_slot_id, amount)
+	if rf, ok := ret.Get(0).(func(int) pkgentity.BalanceAlertSettings); ok {
+		r0 = rf(userID)
 	} else {
-		r0 = ret.Get(0).(int)
+		r0 = ret.Get(0).(pkgentity.BalanceAlertSettings)
 	}
 
-	if rf, ok := ret.Get(1).(func(int, int, entity.Decimal) int); ok {
-		r1 = rf(user_banner_id, user_slot_id, amount)
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
 	} else {
-		r1 = ret.Get(1).(int)
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBalanceByUserId provides a mock function with given fields: id, requestID
+func (_m *PaymentRepositoryInterface) GetBalanceByUserId(id int, requestID string) (pkgentity.Decimal, error) {
+	ret := _m.Called(id, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBalanceByUserId")
 	}
 
-	if rf, ok := ret.Get(2).(func(int, int, entity.Decimal) error); ok {
-		r2 = rf(user_banner_id, user_slot_id, amount)
+	var r0 pkgentity.Decimal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, string) (pkgentity.Decimal, error)); ok {
+		return rf(id, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(int, string) pkgentity.Decimal); ok {
+		r0 = rf(id, requestID)
 	} else {
-		r2 = ret.Error(2)
+		r0 = ret.Get(0).(pkgentity.Decimal)
 	}
 
-	return r0, r1, r2
+	if rf, ok := ret.Get(1).(func(int, string) error); ok {
+		r1 = rf(id, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBalanceForBudget provides a mock function with given fields: userID, batchID
+func (_m *PaymentRepositoryInterface) GetBalanceForBudget(userID int, batchID string) (pkgentity.Decimal, bool, error) {
+	ret := _m.Called(userID, batchID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBalanceForBudget")
+	}
+
+	var r0 pkgentity.Decimal
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(int, string) (pkgentity.Decimal, bool, error)); ok {
+		return rf(userID, batchID)
+	}
+	if rf, ok := ret.Get(0).(func(int, string) pkgentity.Decimal); ok {
+		r0 = rf(userID, batchID)
+	} else {
+		r0 = ret.Get(0).(pkgentity.Decimal)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, string) bool); ok {
+		r1 = rf(userID, batchID)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(int, string) error); ok {
+		r2 = rf(userID, batchID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetDB provides a mock function with no fields
+func (_m *PaymentRepositoryInterface) GetDB() *sql.DB {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDB")
+	}
+
+	var r0 *sql.DB
+	if rf, ok := ret.Get(0).(func() *sql.DB); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sql.DB)
+		}
+	}
+
+	return r0
+}
+
+// GetEarnings provides a mock function with given fields: publisherID
+func (_m *PaymentRepositoryInterface) GetEarnings(publisherID int) (entity.Earnings, error) {
+	ret := _m.Called(publisherID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEarnings")
+	}
+
+	var r0 entity.Earnings
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (entity.Earnings, error)); ok {
+		return rf(publisherID)
+	}
+	if rf, ok := ret.Get(0).(func(int) entity.Earnings); ok {
+		r0 = rf(publisherID)
+	} else {
+		r0 = ret.Get(0).(entity.Earnings)
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(publisherID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLastTransaction provides a mock function with given fields: userID, requestID
+func (_m *PaymentRepositoryInterface) GetLastTransaction(userID int, requestID string) (*entity.Transaction, error) {
+	ret := _m.Called(userID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLastTransaction")
+	}
+
+	var r0 *entity.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, string) (*entity.Transaction, error)); ok {
+		return rf(userID, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(int, string) *entity.Transaction); ok {
+		r0 = rf(userID, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, string) error); ok {
+		r1 = rf(userID, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLatestRateSnapshot provides a mock function with no fields
+func (_m *PaymentRepositoryInterface) GetLatestRateSnapshot() (entity.RateSnapshot, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestRateSnapshot")
+	}
+
+	var r0 entity.RateSnapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (entity.RateSnapshot, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() entity.RateSnapshot); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(entity.RateSnapshot)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLedgerBalance provides a mock function with given fields: ownerID
+func (_m *PaymentRepositoryInterface) GetLedgerBalance(ownerID int) (pkgentity.Decimal, error) {
+	ret := _m.Called(ownerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLedgerBalance")
+	}
+
+	var r0 pkgentity.Decimal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (pkgentity.Decimal, error)); ok {
+		return rf(ownerID)
+	}
+	if rf, ok := ret.Get(0).(func(int) pkgentity.Decimal); ok {
+		r0 = rf(ownerID)
+	} else {
+		r0 = ret.Get(0).(pkgentity.Decimal)
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(ownerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLedgerBalanceAt provides a mock function with given fields: ownerID, at
+func (_m *PaymentRepositoryInterface) GetLedgerBalanceAt(ownerID int, at time.Time) (pkgentity.Decimal, error) {
+	ret := _m.Called(ownerID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLedgerBalanceAt")
+	}
+
+	var r0 pkgentity.Decimal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, time.Time) (pkgentity.Decimal, error)); ok {
+		return rf(ownerID, at)
+	}
+	if rf, ok := ret.Get(0).(func(int, time.Time) pkgentity.Decimal); ok {
+		r0 = rf(ownerID, at)
+	} else {
+		r0 = ret.Get(0).(pkgentity.Decimal)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, time.Time) error); ok {
+		r1 = rf(ownerID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLedgerBalances provides a mock function with given fields: ownerID
+func (_m *PaymentRepositoryInterface) GetLedgerBalances(ownerID int) ([]entity.CurrencyBalance, error) {
+	ret := _m.Called(ownerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLedgerBalances")
+	}
+
+	var r0 []entity.CurrencyBalance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]entity.CurrencyBalance, error)); ok {
+		return rf(ownerID)
+	}
+	if rf, ok := ret.Get(0).(func(int) []entity.CurrencyBalance); ok {
+		r0 = rf(ownerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.CurrencyBalance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(ownerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLedgerEntries provides a mock function with given fields: ownerID, limit
+func (_m *PaymentRepositoryInterface) GetLedgerEntries(ownerID int, limit int) ([]entity.LedgerEntry, error) {
+	ret := _m.Called(ownerID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLedgerEntries")
+	}
+
+	var r0 []entity.LedgerEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int) ([]entity.LedgerEntry, error)); ok {
+		return rf(ownerID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(int, int) []entity.LedgerEntry); ok {
+		r0 = rf(ownerID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.LedgerEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(ownerID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLedgerEntry provides a mock function with given fields: entryID
+func (_m *PaymentRepositoryInterface) GetLedgerEntry(entryID int64) (entity.LedgerEntry, error) {
+	ret := _m.Called(entryID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLedgerEntry")
+	}
+
+	var r0 entity.LedgerEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (entity.LedgerEntry, error)); ok {
+		return rf(entryID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) entity.LedgerEntry); ok {
+		r0 = rf(entryID)
+	} else {
+		r0 = ret.Get(0).(entity.LedgerEntry)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(entryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLogger provides a mock function with no fields
+func (_m *PaymentRepositoryInterface) GetLogger() *zap.SugaredLogger {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLogger")
+	}
+
+	var r0 *zap.SugaredLogger
+	if rf, ok := ret.Get(0).(func() *zap.SugaredLogger); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*zap.SugaredLogger)
+		}
+	}
+
+	return r0
+}
+
+// GetPaymentMethods provides a mock function with given fields: userID
+func (_m *PaymentRepositoryInterface) GetPaymentMethods(userID int) ([]entity.PaymentMethod, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPaymentMethods")
+	}
+
+	var r0 []entity.PaymentMethod
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]entity.PaymentMethod, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int) []entity.PaymentMethod); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PaymentMethod)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPayoutByGatewayID provides a mock function with given fields: gatewayID
+func (_m *PaymentRepositoryInterface) GetPayoutByGatewayID(gatewayID string) (entity.PayoutRequest, error) {
+	ret := _m.Called(gatewayID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPayoutByGatewayID")
+	}
+
+	var r0 entity.PayoutRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (entity.PayoutRequest, error)); ok {
+		return rf(gatewayID)
+	}
+	if rf, ok := ret.Get(0).(func(string) entity.PayoutRequest); ok {
+		r0 = rf(gatewayID)
+	} else {
+		r0 = ret.Get(0).(entity.PayoutRequest)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(gatewayID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPayoutRequest provides a mock function with given fields: payoutID
+func (_m *PaymentRepositoryInterface) GetPayoutRequest(payoutID int) (entity.PayoutRequest, error) {
+	ret := _m.Called(payoutID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPayoutRequest")
+	}
+
+	var r0 entity.PayoutRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (entity.PayoutRequest, error)); ok {
+		return rf(payoutID)
+	}
+	if rf, ok := ret.Get(0).(func(int) entity.PayoutRequest); ok {
+		r0 = rf(payoutID)
+	} else {
+		r0 = ret.Get(0).(entity.PayoutRequest)
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(payoutID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPayoutRequests provides a mock function with given fields: userID, limit
+func (_m *PaymentRepositoryInterface) GetPayoutRequests(userID int, limit int) ([]entity.PayoutRequest, error) {
+	ret := _m.Called(userID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPayoutRequests")
+	}
+
+	var r0 []entity.PayoutRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int) ([]entity.PayoutRequest, error)); ok {
+		return rf(userID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(int, int) []entity.PayoutRequest); ok {
+		r0 = rf(userID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PayoutRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(userID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPayoutsByStatus provides a mock function with given fields: status, limit
+func (_m *PaymentRepositoryInterface) GetPayoutsByStatus(status entity.PayoutStatus, limit int) ([]entity.PayoutRequest, error) {
+	ret := _m.Called(status, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPayoutsByStatus")
+	}
+
+	var r0 []entity.PayoutRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.PayoutStatus, int) ([]entity.PayoutRequest, error)); ok {
+		return rf(status, limit)
+	}
+	if rf, ok := ret.Get(0).(func(entity.PayoutStatus, int) []entity.PayoutRequest); ok {
+		r0 = rf(status, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PayoutRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.PayoutStatus, int) error); ok {
+		r1 = rf(status, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPendingTransactions provides a mock function with given fields: userID
+func (_m *PaymentRepositoryInterface) GetPendingTransactions(userID int) ([]entity.Transaction, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingTransactions")
+	}
+
+	var r0 []entity.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]entity.Transaction, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int) []entity.Transaction); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPromoBalance provides a mock function with given fields: userID
+func (_m *PaymentRepositoryInterface) GetPromoBalance(userID int) (pkgentity.Decimal, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPromoBalance")
+	}
+
+	var r0 pkgentity.Decimal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (pkgentity.Decimal, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int) pkgentity.Decimal); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(pkgentity.Decimal)
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPromoCode provides a mock function with given fields: code, userID
+func (_m *PaymentRepositoryInterface) GetPromoCode(code string, userID int) (entity.PromoCode, int, error) {
+	ret := _m.Called(code, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPromoCode")
+	}
+
+	var r0 entity.PromoCode
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, int) (entity.PromoCode, int, error)); ok {
+		return rf(code, userID)
+	}
+	if rf, ok := ret.Get(0).(func(string, int) entity.PromoCode); ok {
+		r0 = rf(code, userID)
+	} else {
+		r0 = ret.Get(0).(entity.PromoCode)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int) int); ok {
+		r1 = rf(code, userID)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(string, int) error); ok {
+		r2 = rf(code, userID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetPromoCodes provides a mock function with no fields
+func (_m *PaymentRepositoryInterface) GetPromoCodes() ([]entity.PromoCode, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPromoCodes")
+	}
+
+	var r0 []entity.PromoCode
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]entity.PromoCode, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []entity.PromoCode); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PromoCode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPromoRedemptions provides a mock function with given fields: codeID, userID, limit
+func (_m *PaymentRepositoryInterface) GetPromoRedemptions(codeID int, userID int, limit int) ([]entity.PromoRedemption, error) {
+	ret := _m.Called(codeID, userID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPromoRedemptions")
+	}
+
+	var r0 []entity.PromoRedemption
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int, int) ([]entity.PromoRedemption, error)); ok {
+		return rf(codeID, userID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(int, int, int) []entity.PromoRedemption); ok {
+		r0 = rf(codeID, userID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PromoRedemption)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int, int) error); ok {
+		r1 = rf(codeID, userID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRefundByGatewayID provides a mock function with given fields: gatewayID
+func (_m *PaymentRepositoryInterface) GetRefundByGatewayID(gatewayID string) (entity.Refund, error) {
+	ret := _m.Called(gatewayID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRefundByGatewayID")
+	}
+
+	var r0 entity.Refund
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (entity.Refund, error)); ok {
+		return rf(gatewayID)
+	}
+	if rf, ok := ret.Get(0).(func(string) entity.Refund); ok {
+		r0 = rf(gatewayID)
+	} else {
+		r0 = ret.Get(0).(entity.Refund)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(gatewayID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRefunds provides a mock function with given fields: kind, originalID
+func (_m *PaymentRepositoryInterface) GetRefunds(kind entity.RefundKind, originalID string) ([]entity.Refund, error) {
+	ret := _m.Called(kind, originalID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRefunds")
+	}
+
+	var r0 []entity.Refund
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.RefundKind, string) ([]entity.Refund, error)); ok {
+		return rf(kind, originalID)
+	}
+	if rf, ok := ret.Get(0).(func(entity.RefundKind, string) []entity.Refund); ok {
+		r0 = rf(kind, originalID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Refund)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.RefundKind, string) error); ok {
+		r1 = rf(kind, originalID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStaleApprovedPayouts provides a mock function with given fields: olderThan, approvedAfter, limit
+func (_m *PaymentRepositoryInterface) GetStaleApprovedPayouts(olderThan time.Time, approvedAfter time.Time, limit int) ([]entity.PayoutRequest, error) {
+	ret := _m.Called(olderThan, approvedAfter, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStaleApprovedPayouts")
+	}
+
+	var r0 []entity.PayoutRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time, int) ([]entity.PayoutRequest, error)); ok {
+		return rf(olderThan, approvedAfter, limit)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time, int) []entity.PayoutRequest); ok {
+		r0 = rf(olderThan, approvedAfter, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PayoutRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, time.Time, int) error); ok {
+		r1 = rf(olderThan, approvedAfter, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStaleAutoRecharges provides a mock function with given fields: olderThan, limit
+func (_m *PaymentRepositoryInterface) GetStaleAutoRecharges(olderThan time.Time, limit int) ([]entity.AutoRecharge, error) {
+	ret := _m.Called(olderThan, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStaleAutoRecharges")
+	}
+
+	var r0 []entity.AutoRecharge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, int) ([]entity.AutoRecharge, error)); ok {
+		return rf(olderThan, limit)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, int) []entity.AutoRecharge); ok {
+		r0 = rf(olderThan, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.AutoRecharge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, int) error); ok {
+		r1 = rf(olderThan, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStatement provides a mock function with given fields: statementID
+func (_m *PaymentRepositoryInterface) GetStatement(statementID int) (entity.StatementInfo, error) {
+	ret := _m.Called(statementID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStatement")
+	}
+
+	var r0 entity.StatementInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (entity.StatementInfo, error)); ok {
+		return rf(statementID)
+	}
+	if rf, ok := ret.Get(0).(func(int) entity.StatementInfo); ok {
+		r0 = rf(statementID)
+	} else {
+		r0 = ret.Get(0).(entity.StatementInfo)
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(statementID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStatementOwners provides a mock function with given fields: from, to
+func (_m *PaymentRepositoryInterface) GetStatementOwners(from time.Time, to time.Time) ([]int, error) {
+	ret := _m.Called(from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStatementOwners")
+	}
+
+	var r0 []int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time) ([]int, error)); ok {
+		return rf(from, to)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time) []int); ok {
+		r0 = rf(from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, time.Time) error); ok {
+		r1 = rf(from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStatementRows provides a mock function with given fields: ownerID, from, to
+func (_m *PaymentRepositoryInterface) GetStatementRows(ownerID int, from time.Time, to time.Time) ([]entity.StatementRow, error) {
+	ret := _m.Called(ownerID, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStatementRows")
+	}
+
+	var r0 []entity.StatementRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, time.Time, time.Time) ([]entity.StatementRow, error)); ok {
+		return rf(ownerID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(int, time.Time, time.Time) []entity.StatementRow); ok {
+		r0 = rf(ownerID, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.StatementRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, time.Time, time.Time) error); ok {
+		r1 = rf(ownerID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStatements provides a mock function with given fields: userID
+func (_m *PaymentRepositoryInterface) GetStatements(userID int) ([]entity.StatementInfo, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStatements")
+	}
+
+	var r0 []entity.StatementInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]entity.StatementInfo, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int) []entity.StatementInfo); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.StatementInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTakeRate provides a mock function with given fields: publisherID
+func (_m *PaymentRepositoryInterface) GetTakeRate(publisherID int) (pkgentity.Decimal, bool, error) {
+	ret := _m.Called(publisherID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTakeRate")
+	}
+
+	var r0 pkgentity.Decimal
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(int) (pkgentity.Decimal, bool, error)); ok {
+		return rf(publisherID)
+	}
+	if rf, ok := ret.Get(0).(func(int) pkgentity.Decimal); ok {
+		r0 = rf(publisherID)
+	} else {
+		r0 = ret.Get(0).(pkgentity.Decimal)
+	}
+
+	if rf, ok := ret.Get(1).(func(int) bool); ok {
+		r1 = rf(publisherID)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(int) error); ok {
+		r2 = rf(publisherID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTopUpPromo provides a mock function with given fields: transactionID
+func (_m *PaymentRepositoryInterface) GetTopUpPromo(transactionID string) (string, error) {
+	ret := _m.Called(transactionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTopUpPromo")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(transactionID)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(transactionID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(transactionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTransactionByID provides a mock function with given fields: transactionID, requestID
+func (_m *PaymentRepositoryInterface) GetTransactionByID(transactionID string, requestID string) (*entity.Transaction, error) {
+	ret := _m.Called(transactionID, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactionByID")
+	}
+
+	var r0 *entity.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*entity.Transaction, error)); ok {
+		return rf(transactionID, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *entity.Transaction); ok {
+		r0 = rf(transactionID, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(transactionID, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTransactionHistory provides a mock function with given fields: filter
+func (_m *PaymentRepositoryInterface) GetTransactionHistory(filter entity.HistoryFilter) ([]entity.HistoryItem, error) {
+	ret := _m.Called(filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactionHistory")
+	}
+
+	var r0 []entity.HistoryItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.HistoryFilter) ([]entity.HistoryItem, error)); ok {
+		return rf(filter)
+	}
+	if rf, ok := ret.Get(0).(func(entity.HistoryFilter) []entity.HistoryItem); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.HistoryItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.HistoryFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkBalanceAlertsCrossed provides a mock function with given fields: userID, thresholds
+func (_m *PaymentRepositoryInterface) MarkBalanceAlertsCrossed(userID int, thresholds []pkgentity.Decimal) ([]pkgentity.Decimal, error) {
+	ret := _m.Called(userID, thresholds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkBalanceAlertsCrossed")
+	}
+
+	var r0 []pkgentity.Decimal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, []pkgentity.Decimal) ([]pkgentity.Decimal, error)); ok {
+		return rf(userID, thresholds)
+	}
+	if rf, ok := ret.Get(0).(func(int, []pkgentity.Decimal) []pkgentity.Decimal); ok {
+		r0 = rf(userID, thresholds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pkgentity.Decimal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, []pkgentity.Decimal) error); ok {
+		r1 = rf(userID, thresholds)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PostEntry provides a mock function with given fields: entry
+func (_m *PaymentRepositoryInterface) PostEntry(entry entity.LedgerEntry) (int64, error) {
+	ret := _m.Called(entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PostEntry")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.LedgerEntry) (int64, error)); ok {
+		return rf(entry)
+	}
+	if rf, ok := ret.Get(0).(func(entity.LedgerEntry) int64); ok {
+		r0 = rf(entry)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.LedgerEntry) error); ok {
+		r1 = rf(entry)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeIdempotencyKeys provides a mock function with given fields: olderThan
+func (_m *PaymentRepositoryInterface) PurgeIdempotencyKeys(olderThan time.Time) (int64, error) {
+	ret := _m.Called(olderThan)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeIdempotencyKeys")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (int64, error)); ok {
+		return rf(olderThan)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) int64); ok {
+		r0 = rf(olderThan)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(olderThan)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RedeemPromoCode provides a mock function with given fields: code, userID, topUp, transactionID, now
+func (_m *PaymentRepositoryInterface) RedeemPromoCode(code string, userID int, topUp *pkgentity.Decimal, transactionID string, now time.Time) (entity.PromoRedemption, error) {
+	ret := _m.Called(code, userID, topUp, transactionID, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RedeemPromoCode")
+	}
+
+	var r0 entity.PromoRedemption
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int, *pkgentity.Decimal, string, time.Time) (entity.PromoRedemption, error)); ok {
+		return rf(code, userID, topUp, transactionID, now)
+	}
+	if rf, ok := ret.Get(0).(func(string, int, *pkgentity.Decimal, string, time.Time) entity.PromoRedemption); ok {
+		r0 = rf(code, userID, topUp, transactionID, now)
+	} else {
+		r0 = ret.Get(0).(entity.PromoRedemption)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int, *pkgentity.Decimal, string, time.Time) error); ok {
+		r1 = rf(code, userID, topUp, transactionID, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RegUserActivity provides a mock function with given fields: user_banner_id, user_slot_id, amount, fee, reference, placement
+func (_m *PaymentRepositoryInterface) RegUserActivity(user_banner_id int, user_slot_id int, amount pkgentity.Decimal, fee pkgentity.Decimal, reference string, placement entity.Placement) (int, int, error) {
+	ret := _m.Called(user_banner_id, user_slot_id, amount, fee, reference, placement)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegUserActivity")
+	}
+
+	var r0 int
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(int, int, pkgentity.Decimal, pkgentity.Decimal, string, entity.Placement) (int, int, error)); ok {
+		return rf(user_banner_id, user_slot_id, amount, fee, reference, placement)
+	}
+	if rf, ok := ret.Get(0).(func(int, int, pkgentity.Decimal, pkgentity.Decimal, string, entity.Placement) int); ok {
+		r0 = rf(user_banner_id, user_slot_id, amount, fee, reference, placement)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int, pkgentity.Decimal, pkgentity.Decimal, string, entity.Placement) int); ok {
+		r1 = rf(user_banner_id, user_slot_id, amount, fee, reference, placement)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(int, int, pkgentity.Decimal, pkgentity.Decimal, string, entity.Placement) error); ok {
+		r2 = rf(user_banner_id, user_slot_id, amount, fee, reference, placement)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ReleaseIdempotencyKey provides a mock function with given fields: userID, actorID, key
+func (_m *PaymentRepositoryInterface) ReleaseIdempotencyKey(userID int, actorID int, key string) error {
+	ret := _m.Called(userID, actorID, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseIdempotencyKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int, string) error); ok {
+		r0 = rf(userID, actorID, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReserveIdempotencyKey provides a mock function with given fields: userID, actorID, key, requestHash
+func (_m *PaymentRepositoryInterface) ReserveIdempotencyKey(userID int, actorID int, key string, requestHash string) (entity.IdempotencyRecord, bool, error) {
+	ret := _m.Called(userID, actorID, key, requestHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReserveIdempotencyKey")
+	}
+
+	var r0 entity.IdempotencyRecord
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(int, int, string, string) (entity.IdempotencyRecord, bool, error)); ok {
+		return rf(userID, actorID, key, requestHash)
+	}
+	if rf, ok := ret.Get(0).(func(int, int, string, string) entity.IdempotencyRecord); ok {
+		r0 = rf(userID, actorID, key, requestHash)
+	} else {
+		r0 = ret.Get(0).(entity.IdempotencyRecord)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int, string, string) bool); ok {
+		r1 = rf(userID, actorID, key, requestHash)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(int, int, string, string) error); ok {
+		r2 = rf(userID, actorID, key, requestHash)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ResetBalanceAlerts provides a mock function with given fields: userID, balance
+func (_m *PaymentRepositoryInterface) ResetBalanceAlerts(userID int, balance pkgentity.Decimal) error {
+	ret := _m.Called(userID, balance)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetBalanceAlerts")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, pkgentity.Decimal) error); ok {
+		r0 = rf(userID, balance)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReverseImpressionCharge provides a mock function with given fields: original, reason, createdBy
+func (_m *PaymentRepositoryInterface) ReverseImpressionCharge(original entity.LedgerEntry, reason string, createdBy int) (entity.Refund, error) {
+	ret := _m.Called(original, reason, createdBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReverseImpressionCharge")
+	}
+
+	var r0 entity.Refund
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.LedgerEntry, string, int) (entity.Refund, error)); ok {
+		return rf(original, reason, createdBy)
+	}
+	if rf, ok := ret.Get(0).(func(entity.LedgerEntry, string, int) entity.Refund); ok {
+		r0 = rf(original, reason, createdBy)
+	} else {
+		r0 = ret.Get(0).(entity.Refund)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.LedgerEntry, string, int) error); ok {
+		r1 = rf(original, reason, createdBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveAutoRecharge provides a mock function with given fields: a
+func (_m *PaymentRepositoryInterface) SaveAutoRecharge(a entity.AutoRecharge) error {
+	ret := _m.Called(a)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveAutoRecharge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.AutoRecharge) error); ok {
+		r0 = rf(a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveBalanceAlertSettings provides a mock function with given fields: userID, s
+func (_m *PaymentRepositoryInterface) SaveBalanceAlertSettings(userID int, s pkgentity.BalanceAlertSettings) error {
+	ret := _m.Called(userID, s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveBalanceAlertSettings")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, pkgentity.BalanceAlertSettings) error); ok {
+		r0 = rf(userID, s)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveIdempotentResponse provides a mock function with given fields: userID, actorID, key, statusCode, contentType, body
+func (_m *PaymentRepositoryInterface) SaveIdempotentResponse(userID int, actorID int, key string, statusCode int, contentType string, body []byte) error {
+	ret := _m.Called(userID, actorID, key, statusCode, contentType, body)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveIdempotentResponse")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int, string, int, string, []byte) error); ok {
+		r0 = rf(userID, actorID, key, statusCode, contentType, body)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveLedgerDrift provides a mock function with given fields: drifts
+func (_m *PaymentRepositoryInterface) SaveLedgerDrift(drifts []entity.LedgerDrift) error {
+	ret := _m.Called(drifts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveLedgerDrift")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]entity.LedgerDrift) error); ok {
+		r0 = rf(drifts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SavePaymentMethod provides a mock function with given fields: m
+func (_m *PaymentRepositoryInterface) SavePaymentMethod(m entity.PaymentMethod) error {
+	ret := _m.Called(m)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SavePaymentMethod")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.PaymentMethod) error); ok {
+		r0 = rf(m)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveRateSnapshot provides a mock function with given fields: source, createdBy, rates
+func (_m *PaymentRepositoryInterface) SaveRateSnapshot(source string, createdBy int, rates map[string]pkgentity.Decimal) (entity.RateSnapshot, error) {
+	ret := _m.Called(source, createdBy, rates)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveRateSnapshot")
+	}
+
+	var r0 entity.RateSnapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int, map[string]pkgentity.Decimal) (entity.RateSnapshot, error)); ok {
+		return rf(source, createdBy, rates)
+	}
+	if rf, ok := ret.Get(0).(func(string, int, map[string]pkgentity.Decimal) entity.RateSnapshot); ok {
+		r0 = rf(source, createdBy, rates)
+	} else {
+		r0 = ret.Get(0).(entity.RateSnapshot)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int, map[string]pkgentity.Decimal) error); ok {
+		r1 = rf(source, createdBy, rates)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveStatement provides a mock function with given fields: s, objectPrefix
+func (_m *PaymentRepositoryInterface) SaveStatement(s entity.Statement, objectPrefix string) (entity.StatementInfo, error) {
+	ret := _m.Called(s, objectPrefix)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveStatement")
+	}
+
+	var r0 entity.StatementInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.Statement, string) (entity.StatementInfo, error)); ok {
+		return rf(s, objectPrefix)
+	}
+	if rf, ok := ret.Get(0).(func(entity.Statement, string) entity.StatementInfo); ok {
+		r0 = rf(s, objectPrefix)
+	} else {
+		r0 = ret.Get(0).(entity.StatementInfo)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.Statement, string) error); ok {
+		r1 = rf(s, objectPrefix)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetPromoCodeActive provides a mock function with given fields: codeID, active
+func (_m *PaymentRepositoryInterface) SetPromoCodeActive(codeID int, active bool) (entity.PromoCode, error) {
+	ret := _m.Called(codeID, active)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPromoCodeActive")
+	}
+
+	var r0 entity.PromoCode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, bool) (entity.PromoCode, error)); ok {
+		return rf(codeID, active)
+	}
+	if rf, ok := ret.Get(0).(func(int, bool) entity.PromoCode); ok {
+		r0 = rf(codeID, active)
+	} else {
+		r0 = ret.Get(0).(entity.PromoCode)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, bool) error); ok {
+		r1 = rf(codeID, active)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetTakeRate provides a mock function with given fields: publisherID, rate
+func (_m *PaymentRepositoryInterface) SetTakeRate(publisherID int, rate pkgentity.Decimal) error {
+	ret := _m.Called(publisherID, rate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetTakeRate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, pkgentity.Decimal) error); ok {
+		r0 = rf(publisherID, rate)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SettleTransaction provides a mock function with given fields: transactionID, status, userID, delta, entry
+func (_m *PaymentRepositoryInterface) SettleTransaction(transactionID string, status int, userID int, delta pkgentity.Decimal, entry *entity.LedgerEntry) (bool, error) {
+	ret := _m.Called(transactionID, status, userID, delta, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SettleTransaction")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int, int, pkgentity.Decimal, *entity.LedgerEntry) (bool, error)); ok {
+		return rf(transactionID, status, userID, delta, entry)
+	}
+	if rf, ok := ret.Get(0).(func(string, int, int, pkgentity.Decimal, *entity.LedgerEntry) bool); ok {
+		r0 = rf(transactionID, status, userID, delta, entry)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int, int, pkgentity.Decimal, *entity.LedgerEntry) error); ok {
+		r1 = rf(transactionID, status, userID, delta, entry)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StartAutoRecharge provides a mock function with given fields: userID, pendingID, now
+func (_m *PaymentRepositoryInterface) StartAutoRecharge(userID int, pendingID string, now time.Time) (entity.AutoRecharge, error) {
+	ret := _m.Called(userID, pendingID, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartAutoRecharge")
+	}
+
+	var r0 entity.AutoRecharge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, string, time.Time) (entity.AutoRecharge, error)); ok {
+		return rf(userID, pendingID, now)
+	}
+	if rf, ok := ret.Get(0).(func(int, string, time.Time) entity.AutoRecharge); ok {
+		r0 = rf(userID, pendingID, now)
+	} else {
+		r0 = ret.Get(0).(entity.AutoRecharge)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, string, time.Time) error); ok {
+		r1 = rf(userID, pendingID, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransitionPayout provides a mock function with given fields: payoutID, from, to, update, delta, entry
+func (_m *PaymentRepositoryInterface) TransitionPayout(payoutID int, from entity.PayoutStatus, to entity.PayoutStatus, update entity.PayoutUpdate, delta pkgentity.Decimal, entry *entity.LedgerEntry) (bool, error) {
+	ret := _m.Called(payoutID, from, to, update, delta, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TransitionPayout")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, entity.PayoutStatus, entity.PayoutStatus, entity.PayoutUpdate, pkgentity.Decimal, *entity.LedgerEntry) (bool, error)); ok {
+		return rf(payoutID, from, to, update, delta, entry)
+	}
+	if rf, ok := ret.Get(0).(func(int, entity.PayoutStatus, entity.PayoutStatus, entity.PayoutUpdate, pkgentity.Decimal, *entity.LedgerEntry) bool); ok {
+		r0 = rf(payoutID, from, to, update, delta, entry)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, entity.PayoutStatus, entity.PayoutStatus, entity.PayoutUpdate, pkgentity.Decimal, *entity.LedgerEntry) error); ok {
+		r1 = rf(payoutID, from, to, update, delta, entry)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// UpdateBalance provides a mock function with given fields: userID, amount, requestID
-func (_m *PaymentRepositoryInterface) UpdateBalance(userID int, amount float64, requestID string) (float64, error) {
-	ret := _m.Called(userID, amount, requestID)
+// UpdateBalance provides a mock function with given fields: userID, amount, entry, requestID
+func (_m *PaymentRepositoryInterface) UpdateBalance(userID int, amount pkgentity.Decimal, entry entity.LedgerEntry, requestID string) (pkgentity.Decimal, error) {
+	ret := _m.Called(userID, amount, entry, requestID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateBalance")
 	}
 
-	var r0 float64
+	var r0 pkgentity.Decimal
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, float64, string) (float64, error)); ok {
-		return rf(userID, amount, requestID)
+	if rf, ok := ret.Get(0).(func(int, pkgentity.Decimal, entity.LedgerEntry, string) (pkgentity.Decimal, error)); ok {
+		return rf(userID, amount, entry, requestID)
 	}
-	if rf, ok := ret.Get(0).(func(int, float64, string) float64); ok {
-		r0 = rf(userID, amount, requestID)
+	if rf, ok := ret.Get(0).(func(int, pkgentity.Decimal, entity.LedgerEntry, string) pkgentity.Decimal); ok {
+		r0 = rf(userID, amount, entry, requestID)
 	} else {
-		r0 = ret.Get(0).(float64)
+		r0 = ret.Get(0).(pkgentity.Decimal)
 	}
 
-	if rf, ok := ret.Get(1).(func(int, float64, string) error); ok {
-		r1 = rf(userID, amount, requestID)
+	if rf, ok := ret.Get(1).(func(int, pkgentity.Decimal, entity.LedgerEntry, string) error); ok {
+		r1 = rf(userID, amount, entry, requestID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,328 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"retarget/internal/pay-service/entity"
+)
+
+const paymentMethodColumns = `id, user_id, title, transaction_id, active, created_at`
+
+const autoRechargeColumns = `user_id, enabled, threshold, amount, monthly_cap, COALESCE(payment_method_id, ''),
+        failures, next_attempt_at, COALESCE(pending_transaction_id, ''), last_error`
+
+// autoRechargeChargedQuery — сумма автопополнений с начала месяца $2, кроме отменённых
+const autoRechargeChargedQuery = `
+        SELECT COALESCE(SUM(amount), 0) FROM transaction
+        WHERE user_id = $1 AND type = '` + entity.TransactionAutoRecharge + `' AND status IN ('0', '1') AND created_at >= $2`
+
+func scanPaymentMethod(row rowScanner) (entity.PaymentMethod, error) {
+	var m entity.PaymentMethod
+	err := row.Scan(&m.ID, &m.UserID, &m.Title, &m.TransactionID, &m.Active, &m.CreatedAt)
+	return m, err
+}
+
+func scanAutoRecharge(row rowScanner) (entity.AutoRecharge, error) {
+	var a entity.AutoRecharge
+	err := row.Scan(&a.UserID, &a.Enabled, &a.Threshold, &a.Amount, &a.MonthlyCap, &a.PaymentMethodID,
+		&a.Failures, &a.NextAttemptAt, &a.PendingTransactionID, &a.LastError)
+	return a, err
+}
+
+// SavePaymentMethod запоминает способ оплаты, который шлюз сохранит, если платёж пройдёт
+func (r *PaymentRepository) SavePaymentMethod(m entity.PaymentMethod) error {
+	_, err := r.db.Exec(`
+        INSERT INTO payment_method (id, user_id, title, transaction_id)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (id) DO NOTHING`,
+		m.ID,
+		m.UserID,
+		m.Title,
+		m.TransactionID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save payment method: %w", err)
+	}
+	return nil
+}
+
+// ActivatePaymentMethod разрешает списания способом, сохранённым при платеже
+// transactionID; false — такого способа нет или он уже активен
+func (r *PaymentRepository) ActivatePaymentMethod(transactionID string) (entity.PaymentMethod, bool, error) {
+	m, err := scanPaymentMethod(r.db.QueryRow(`
+        UPDATE payment_method SET active = TRUE
+        WHERE transaction_id = $1 AND NOT active
+        RETURNING `+paymentMethodColumns,
+		transactionID,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return m, false, nil
+	}
+	if err != nil {
+		return m, false, fmt.Errorf("failed to activate payment method: %w", err)
+	}
+	return m, true, nil
+}
+
+// GetPaymentMethods отдаёт активные способы оплаты пользователя, новые первыми
+func (r *PaymentRepository) GetPaymentMethods(userID int) ([]entity.PaymentMethod, error) {
+	rows, err := r.db.Query(`
+        SELECT `+paymentMethodColumns+`
+        FROM payment_method
+        WHERE user_id = $1 AND active
+        ORDER BY created_at DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment methods: %w", err)
+	}
+	defer rows.Close()
+
+	methods := make([]entity.PaymentMethod, 0)
+	for rows.Next() {
+		m, err := scanPaymentMethod(rows)
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return methods, nil
+}
+
+// DeletePaymentMethod забывает способ оплаты; автопополнение с ним выключается
+func (r *PaymentRepository) DeletePaymentMethod(userID int, methodID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if _, err = tx.Exec(`UPDATE auto_recharge SET enabled = FALSE WHERE user_id = $1 AND payment_method_id = $2`, userID, methodID); err != nil {
+		return r.rollback(tx, fmt.Errorf("failed to disable auto-recharge: %w", err))
+	}
+	res, err := tx.Exec(`DELETE FROM payment_method WHERE id = $1 AND user_id = $2`, methodID, userID)
+	if err != nil {
+		return r.rollback(tx, fmt.Errorf("failed to delete payment method: %w", err))
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return r.rollback(tx, entity.ErrNoPaymentMethod)
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit payment method removal: %w", err)
+	}
+	return nil
+}
+
+// GetAutoRecharge отдаёт правило автопополнения и сумму списаний с начала месяца
+func (r *PaymentRepository) GetAutoRecharge(userID int, now time.Time) (entity.AutoRecharge, error) {
+	a, err := scanAutoRecharge(r.db.QueryRow(`SELECT `+autoRechargeColumns+` FROM auto_recharge WHERE user_id = $1`, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return a, entity.ErrAutoRechargeNotFound
+	}
+	if err != nil {
+		return a, fmt.Errorf("failed to get auto-recharge: %w", err)
+	}
+	monthStart, _ := entity.StatementPeriod(now)
+	if err := r.db.QueryRow(autoRechargeChargedQuery, userID, monthStart).Scan(&a.ChargedThisMonth); err != nil {
+		return a, fmt.Errorf("failed to sum auto-recharges: %w", err)
+	}
+	return a, nil
+}
+
+// SaveAutoRecharge заводит или меняет правило; счётчик неудач и пауза
+// сбрасываются, незавершённое списание остаётся
+func (r *PaymentRepository) SaveAutoRecharge(a entity.AutoRecharge) error {
+	_, err := r.db.Exec(`
+        INSERT INTO auto_recharge (user_id, enabled, threshold, amount, monthly_cap, payment_method_id)
+        VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+        ON CONFLICT (user_id) DO UPDATE
+        SET enabled = EXCLUDED.enabled,
+            threshold = EXCLUDED.threshold,
+            amount = EXCLUDED.amount,
+            monthly_cap = EXCLUDED.monthly_cap,
+            payment_method_id = EXCLUDED.payment_method_id,
+            failures = 0,
+            next_attempt_at = NULL,
+            last_error = '',
+            updated_at = (now() AT TIME ZONE 'UTC')`,
+		a.UserID,
+		a.Enabled,
+		a.Threshold,
+		a.Amount,
+		a.MonthlyCap,
+		a.PaymentMethodID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save auto-recharge: %w", err)
+	}
+	return nil
+}
+
+func (r *PaymentRepository) lockAutoRecharge(tx *sql.Tx, userID int) (entity.AutoRecharge, error) {
+	a, err := scanAutoRecharge(tx.QueryRow(`SELECT `+autoRechargeColumns+` FROM auto_recharge WHERE user_id = $1 FOR UPDATE`, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return a, entity.ErrAutoRechargeNotFound
+	}
+	if err != nil {
+		return a, fmt.Errorf("failed to lock auto-recharge: %w", err)
+	}
+	return a, nil
+}
+
+func (r *PaymentRepository) updateAutoRechargeState(tx *sql.Tx, a entity.AutoRecharge) error {
+	_, err := tx.Exec(`
+        UPDATE auto_recharge
+        SET enabled = $2, failures = $3, next_attempt_at = $4, pending_transaction_id = NULLIF($5, ''),
+            last_error = $6, updated_at = (now() AT TIME ZONE 'UTC')
+        WHERE user_id = $1`,
+		a.UserID,
+		a.Enabled,
+		a.Failures,
+		a.NextAttemptAt,
+		a.PendingTransactionID,
+		a.LastError,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update auto-recharge: %w", err)
+	}
+	return nil
+}
+
+// StartAutoRecharge резервирует списание ключом pendingID, чтобы параллельные
+// показы не запустили второе. Если списание не укладывается в месячный лимит,
+// следующая попытка откладывается до начала следующего месяца и возвращается
+// entity.ErrAutoRechargeCap вместе с обновлённым правилом
+func (r *PaymentRepository) StartAutoRecharge(userID int, pendingID string, now time.Time) (entity.AutoRecharge, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return entity.AutoRecharge{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	a, err := r.lockAutoRecharge(tx, userID)
+	if err != nil {
+		return a, r.rollback(tx, err)
+	}
+	if !a.Ready(now) {
+		return a, r.rollback(tx, entity.ErrAutoRechargeNotDue)
+	}
+	monthStart, monthEnd := entity.StatementPeriod(now)
+	if err := tx.QueryRow(autoRechargeChargedQuery, userID, monthStart).Scan(&a.ChargedThisMonth); err != nil {
+		return a, r.rollback(tx, fmt.Errorf("failed to sum auto-recharges: %w", err))
+	}
+
+	var result error
+	if a.ChargedThisMonth.Add(a.Amount).Cmp(a.MonthlyCap) > 0 {
+		a.NextAttemptAt = &monthEnd
+		a.LastError = entity.ErrAutoRechargeCap.Error()
+		result = entity.ErrAutoRechargeCap
+	} else {
+		a.PendingTransactionID = pendingID
+	}
+	if err := r.updateAutoRechargeState(tx, a); err != nil {
+		return a, r.rollback(tx, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return a, fmt.Errorf("failed to commit auto-recharge start: %w", err)
+	}
+	return a, result
+}
+
+// AttachAutoRechargeTransaction сохраняет транзакцию списания и заменяет ею
+// ключ резерва одной транзакцией БД: уведомление о платеже найдёт и транзакцию,
+// и правило, которое нужно завершить
+func (r *PaymentRepository) AttachAutoRechargeTransaction(pendingID string, trx entity.Transaction) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	_, err = tx.Exec(`
+        INSERT INTO transaction (transaction_id, user_id, amount, type, status, currency)
+        VALUES ($1, $2, $3, $4, $5, $6)`,
+		trx.TransactionID,
+		trx.UserID,
+		trx.Amount,
+		trx.Type,
+		trx.Status,
+		trx.Currency,
+	)
+	if err != nil {
+		return r.rollback(tx, fmt.Errorf("failed to save auto-recharge transaction: %w", err))
+	}
+	_, err = tx.Exec(`UPDATE auto_recharge SET pending_transaction_id = $3 WHERE user_id = $1 AND pending_transaction_id = $2`,
+		trx.UserID,
+		pendingID,
+		trx.TransactionID,
+	)
+	if err != nil {
+		return r.rollback(tx, fmt.Errorf("failed to attach auto-recharge transaction: %w", err))
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit auto-recharge transaction: %w", err)
+	}
+	return nil
+}
+
+// GetStaleAutoRecharges отдаёт правила, резерв которых старше olderThan и так
+// и не сменился транзакцией списания. Сначала идут самые старые резервы
+func (r *PaymentRepository) GetStaleAutoRecharges(olderThan time.Time, limit int) ([]entity.AutoRecharge, error) {
+	rows, err := r.db.Query(`
+        SELECT `+autoRechargeColumns+`
+        FROM auto_recharge a
+        WHERE pending_transaction_id IS NOT NULL AND updated_at < $1
+          AND NOT EXISTS (SELECT 1 FROM transaction t WHERE t.transaction_id = a.pending_transaction_id)
+        ORDER BY updated_at
+        LIMIT $2`,
+		olderThan,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stale auto-recharges: %w", err)
+	}
+	defer rows.Close()
+
+	list := make([]entity.AutoRecharge, 0)
+	for rows.Next() {
+		a, err := scanAutoRecharge(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// FinishAutoRecharge завершает списание pendingID: пустой failure — успех.
+// false — правило ждёт другое списание, и ничего не изменилось
+func (r *PaymentRepository) FinishAutoRecharge(userID int, pendingID, failure string, now time.Time) (entity.AutoRecharge, bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return entity.AutoRecharge{}, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	a, err := r.lockAutoRecharge(tx, userID)
+	if err != nil {
+		return a, false, r.rollback(tx, err)
+	}
+	if a.PendingTransactionID != pendingID {
+		return a, false, r.rollback(tx, nil)
+	}
+	if failure == "" {
+		a.RecordSuccess()
+	} else {
+		a.RecordFailure(failure, now)
+	}
+	if err := r.updateAutoRechargeState(tx, a); err != nil {
+		return a, false, r.rollback(tx, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return a, false, fmt.Errorf("failed to commit auto-recharge result: %w", err)
+	}
+	return a, true, nil
+}
@@ -0,0 +1,96 @@
+package repo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"retarget/internal/pay-service/entity"
+)
+
+var autoRechargeRowColumns = []string{"user_id", "enabled", "threshold", "amount", "monthly_cap", "payment_method_id",
+	"failures", "next_attempt_at", "pending_transaction_id", "last_error"}
+
+func TestStartAutoRecharge(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+	now := time.Date(2025, time.May, 10, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM auto_recharge WHERE user_id = \\$1 FOR UPDATE").
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows(autoRechargeRowColumns).AddRow(5, true, "100.00", "500.00", "1000.00", "pm-1", 0, nil, "", ""))
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(amount\\), 0\\) FROM transaction").
+		WithArgs(5, time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)).
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow("500.00"))
+	mock.ExpectExec("UPDATE auto_recharge").
+		WithArgs(5, true, 0, nil, "key-1", "").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	a, err := r.StartAutoRecharge(5, "key-1", now)
+	assert.NoError(t, err)
+	assert.Equal(t, "key-1", a.PendingTransactionID)
+	assert.Equal(t, "500.00", a.ChargedThisMonth.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStartAutoRecharge_MonthlyCap(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+	now := time.Date(2025, time.May, 10, 12, 0, 0, 0, time.UTC)
+	nextMonth := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM auto_recharge WHERE user_id = \\$1 FOR UPDATE").
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows(autoRechargeRowColumns).AddRow(5, true, "100.00", "500.00", "1000.00", "pm-1", 0, nil, "", ""))
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(amount\\), 0\\) FROM transaction").
+		WithArgs(5, time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)).
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow("600.00"))
+	mock.ExpectExec("UPDATE auto_recharge").
+		WithArgs(5, true, 0, &nextMonth, "", entity.ErrAutoRechargeCap.Error()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	a, err := r.StartAutoRecharge(5, "key-1", now)
+	assert.ErrorIs(t, err, entity.ErrAutoRechargeCap)
+	assert.Empty(t, a.PendingTransactionID)
+	assert.False(t, a.Ready(now))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFinishAutoRecharge(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+	now := time.Date(2025, time.May, 10, 12, 0, 0, 0, time.UTC)
+	retryAt := now.Add(15 * time.Minute)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM auto_recharge WHERE user_id = \\$1 FOR UPDATE").
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows(autoRechargeRowColumns).AddRow(5, true, "100.00", "500.00", "1000.00", "pm-1", 0, nil, "tx1", ""))
+	mock.ExpectExec("UPDATE auto_recharge").
+		WithArgs(5, true, 1, &retryAt, "", "declined").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	a, finished, err := r.FinishAutoRecharge(5, "tx1", "declined", now)
+	assert.NoError(t, err)
+	assert.True(t, finished)
+	assert.Equal(t, 1, a.Failures)
+
+	// уведомление о чужом списании правило не трогает
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM auto_recharge WHERE user_id = \\$1 FOR UPDATE").
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows(autoRechargeRowColumns).AddRow(5, true, "100.00", "500.00", "1000.00", "pm-1", 0, nil, "tx2", ""))
+	mock.ExpectRollback()
+
+	_, finished, err = r.FinishAutoRecharge(5, "tx1", "", now)
+	assert.NoError(t, err)
+	assert.False(t, finished)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,122 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"retarget/internal/pay-service/entity"
+
+	"github.com/lib/pq"
+)
+
+// GetBalanceAlertSettings отдаёт настройки уведомлений пользователя или
+// настройки по умолчанию, если он их не сохранял
+func (r *PaymentRepository) GetBalanceAlertSettings(userID int) (entity.BalanceAlertSettings, error) {
+	var s entity.BalanceAlertSettings
+	err := r.db.QueryRow(`
+        SELECT thresholds, auto_pause, pause_floor
+        FROM balance_alert_settings
+        WHERE user_id = $1`,
+		userID,
+	).Scan(pq.Array(&s.Thresholds), &s.AutoPause, &s.PauseFloor)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.DefaultBalanceAlertSettings(), nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("failed to get balance alert settings: %w", err)
+	}
+	return s.Normalize(), nil
+}
+
+// SaveBalanceAlertSettings сохраняет настройки и забывает отметки о
+// пересечении удалённых порогов, чтобы они не копились
+func (r *PaymentRepository) SaveBalanceAlertSettings(userID int, s entity.BalanceAlertSettings) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO balance_alert_settings (user_id, thresholds, auto_pause, pause_floor)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (user_id) DO UPDATE
+        SET thresholds = EXCLUDED.thresholds,
+            auto_pause = EXCLUDED.auto_pause,
+            pause_floor = EXCLUDED.pause_floor,
+            updated_at = (now() AT TIME ZONE 'UTC')`,
+		userID,
+		pq.Array(s.Thresholds),
+		s.AutoPause,
+		s.PauseFloor,
+	)
+	if err == nil {
+		_, err = tx.Exec(`DELETE FROM balance_alert_crossing WHERE user_id = $1 AND NOT (threshold = ANY($2::DECIMAL(14, 2)[]))`,
+			userID,
+			pq.Array(s.Thresholds),
+		)
+	}
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			err = fmt.Errorf("rollback failed: %v; original error: %w", rbErr, err)
+		}
+		return fmt.Errorf("failed to save balance alert settings: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit balance alert settings: %w", err)
+	}
+	return nil
+}
+
+// MarkBalanceAlertsCrossed отмечает пороги пересечёнными и отдаёт только те,
+// что не были отмечены раньше: по ним и нужно уведомление
+func (r *PaymentRepository) MarkBalanceAlertsCrossed(userID int, thresholds []entity.Decimal) ([]entity.Decimal, error) {
+	rows, err := r.db.Query(`
+        INSERT INTO balance_alert_crossing (user_id, threshold)
+        SELECT $1, unnest($2::DECIMAL(14, 2)[])
+        ON CONFLICT (user_id, threshold) DO NOTHING
+        RETURNING threshold`,
+		userID,
+		pq.Array(thresholds),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark balance alerts: %w", err)
+	}
+	defer rows.Close()
+
+	marked := make([]entity.Decimal, 0, len(thresholds))
+	for rows.Next() {
+		var t entity.Decimal
+		if err := rows.Scan(&t); err != nil {
+			return nil, err
+		}
+		marked = append(marked, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return marked, nil
+}
+
+// ClearBalanceAlerts снимает отметки, если уведомление так и не ушло
+func (r *PaymentRepository) ClearBalanceAlerts(userID int, thresholds []entity.Decimal) error {
+	_, err := r.db.Exec(`DELETE FROM balance_alert_crossing WHERE user_id = $1 AND threshold = ANY($2::DECIMAL(14, 2)[])`,
+		userID,
+		pq.Array(thresholds),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to clear balance alerts: %w", err)
+	}
+	return nil
+}
+
+// ResetBalanceAlerts снимает отметки с порогов ниже баланса после пополнения,
+// чтобы следующее снижение до них снова уведомило пользователя
+func (r *PaymentRepository) ResetBalanceAlerts(userID int, balance entity.Decimal) error {
+	_, err := r.db.Exec(`DELETE FROM balance_alert_crossing WHERE user_id = $1 AND threshold < $2`, userID, balance)
+	if err != nil {
+		return fmt.Errorf("failed to reset balance alerts: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package repo_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"retarget/internal/pay-service/entity"
+)
+
+func TestGetBalanceAlertSettings(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	mock.ExpectQuery("FROM balance_alert_settings").
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"thresholds", "auto_pause", "pause_floor"}).AddRow("{50.00,500.00}", false, "0.00"))
+	s, err := r.GetBalanceAlertSettings(5)
+	assert.NoError(t, err)
+	assert.Len(t, s.Thresholds, 2)
+	assert.Equal(t, "500.00", s.Thresholds[0].String())
+	assert.False(t, s.AutoPause)
+
+	mock.ExpectQuery("FROM balance_alert_settings").
+		WithArgs(6).
+		WillReturnError(sql.ErrNoRows)
+	s, err = r.GetBalanceAlertSettings(6)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.DefaultBalanceAlertSettings(), s)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveBalanceAlertSettings(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+	settings := entity.BalanceAlertSettings{
+		Thresholds: []entity.Decimal{entity.DecimalFromKopecks(30000)},
+		PauseFloor: entity.DecimalFromKopecks(0),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO balance_alert_settings").
+		WithArgs(1, `{"300.00"}`, false, "0.00").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM balance_alert_crossing").
+		WithArgs(1, `{"300.00"}`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.SaveBalanceAlertSettings(1, settings))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkBalanceAlertsCrossed_ReturnsOnlyNew(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	mock.ExpectQuery("INSERT INTO balance_alert_crossing").
+		WithArgs(5, `{"500.00","100.00"}`).
+		WillReturnRows(sqlmock.NewRows([]string{"threshold"}).AddRow("100.00"))
+
+	marked, err := r.MarkBalanceAlertsCrossed(5, []entity.Decimal{entity.DecimalFromKopecks(50000), entity.DecimalFromKopecks(10000)})
+	assert.NoError(t, err)
+	assert.Len(t, marked, 1)
+	assert.Equal(t, "100.00", marked[0].String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestResetBalanceAlerts(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	mock.ExpectExec("DELETE FROM balance_alert_crossing WHERE user_id = \\$1 AND threshold < \\$2").
+		WithArgs(5, "250.00").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	assert.NoError(t, r.ResetBalanceAlerts(5, entity.DecimalFromKopecks(25000)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
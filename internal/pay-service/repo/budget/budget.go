@@ -0,0 +1,493 @@
+package budget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrInsufficientFunds = errors.New("insufficient funds for reservation")
+	ErrBalanceNotLoaded  = errors.New("balance is not loaded into budget cache")
+	ErrHoldNotFound      = errors.New("reservation not found or expired")
+	ErrBatchChanged      = errors.New("budget batch changed while loading balance")
+)
+
+const (
+	holdsKey        = "budget:holds"
+	pendingKey      = "budget:pending"
+	flushingKey     = "budget:pending:flushing"
+	chargesKey      = "budget:charges"
+	chargesFlushing = "budget:charges:flushing"
+	batchField      = "__batch"
+	notLoadedResult = -2
+	noFundsResult   = -1
+	changedResult   = -3
+)
+
+type BudgetRepositoryInterface interface {
+	FlushingBatchID() (string, error)
+	LoadBalance(userID int, kopecks int64, batchID string, includeFlushing bool) error
+	Reserve(holdID string, advertiserID, publisherID int, kopecks, feeKopecks int64, bannerID int64, slotLink, conversion string) (int64, error)
+	Capture(holdID string) (int64, error)
+	Charge(advertiserID, publisherID int, kopecks, feeKopecks int64, bannerID int64, slotLink, conversion string) (int64, error)
+	Adjust(userID int, kopecks int64) error
+	GetAvailable(userID int) (int64, error)
+	ReleaseExpired(now time.Time) (int, error)
+	TakeBatch(batchID string) (string, map[int]int64, []Charge, error)
+	CompleteBatch() error
+	CloseConnection() error
+}
+
+// Charge — одно списание за показ, из которого при сверке получится проводка в журнале.
+// FeeKopecks — часть Kopecks, удержанная площадкой. BannerID и SlotLink пустые
+// у списаний, попавших в кэш до их появления. Conversion — упакованный пересчёт
+// ставки в базовую валюту, пустой для ставок в рублях
+type Charge struct {
+	AdvertiserID int
+	PublisherID  int
+	Kopecks      int64
+	FeeKopecks   int64
+	Reference    string
+	BannerID     int64
+	SlotLink     string
+	Conversion   string
+}
+
+type BudgetRepository struct {
+	Client  *redis.Client
+	HoldTTL time.Duration
+}
+
+func NewBudgetRepository(endpoint, password string, db int, holdTTL time.Duration) *BudgetRepository {
+	client := redis.NewClient(&redis.Options{
+		Addr:     endpoint,
+		Password: password,
+		DB:       db,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Fatal("Failed to connect to Redis:", err)
+	}
+
+	return &BudgetRepository{
+		Client:  client,
+		HoldTTL: holdTTL,
+	}
+}
+
+func balanceKey(userID int) string {
+	return fmt.Sprintf("budget:balance:%d", userID)
+}
+
+func holdKey(holdID string) string {
+	return fmt.Sprintf("budget:hold:%s", holdID)
+}
+
+// Остаток из Postgres дополняется ещё не сверенными изменениями. Изменения
+// из пачки, которая сейчас доезжает в Postgres, учитываются, только если
+// снимок баланса был сделан до её применения
+var loadScript = redis.NewScript(`
+	if redis.call("EXISTS", KEYS[1]) == 1 then
+		return 0
+	end
+	local batch = redis.call("HGET", KEYS[3], ARGV[4]) or ""
+	if batch ~= ARGV[2] then
+		return -3
+	end
+	local balance = tonumber(ARGV[1]) + tonumber(redis.call("HGET", KEYS[2], ARGV[5]) or "0")
+	if ARGV[3] == "1" then
+		balance = balance + tonumber(redis.call("HGET", KEYS[3], ARGV[5]) or "0")
+	end
+	redis.call("SET", KEYS[1], balance)
+	return 1
+`)
+
+// Списываем доступный остаток рекламодателя и кладём холд, который потом
+// превратится в списание (Capture) либо вернётся обратно (ReleaseExpired)
+var reserveScript = redis.NewScript(`
+	local balance = redis.call("GET", KEYS[1])
+	if not balance then
+		return -2
+	end
+	local amount = tonumber(ARGV[1])
+	if tonumber(balance) < amount then
+		return -1
+	end
+	local left = redis.call("DECRBY", KEYS[1], amount)
+	redis.call("HSET", KEYS[2], "advertiser", ARGV[2], "publisher", ARGV[3], "amount", amount, "fee", ARGV[6], "banner", ARGV[7], "slot", ARGV[8], "conversion", ARGV[9])
+	redis.call("ZADD", KEYS[3], ARGV[4], ARGV[5])
+	return left
+`)
+
+// Холд превращается в проводку: минус рекламодателю, плюс владельцу слота
+// за вычетом комиссии площадки. Владельца слота Capture читает из холда заранее,
+// чтобы его баланс пришёл в KEYS, как того требует Redis Cluster; скрипт
+// сверяет, что холд всё ещё тот же
+var captureScript = redis.NewScript(`
+	local hold = redis.call("HMGET", KEYS[1], "advertiser", "publisher", "amount", "fee", "banner", "slot", "conversion")
+	if not hold[1] or hold[2] ~= ARGV[2] then
+		return -1
+	end
+	if redis.call("ZREM", KEYS[2], ARGV[1]) == 0 then
+		return -1
+	end
+	redis.call("DEL", KEYS[1])
+	local amount = tonumber(hold[3])
+	local fee = tonumber(hold[4] or "0")
+	local banner = hold[5] or "0"
+	if hold[7] and hold[7] ~= "" then
+		banner = banner .. "," .. hold[7]
+	end
+	redis.call("HINCRBY", KEYS[3], hold[1], -amount)
+	redis.call("HINCRBY", KEYS[3], hold[2], amount - fee)
+	redis.call("RPUSH", KEYS[4], hold[1] .. ":" .. hold[2] .. ":" .. amount .. ":" .. fee .. ":" .. ARGV[1] .. ":" .. banner .. ":" .. (hold[6] or ""))
+	if redis.call("EXISTS", KEYS[5]) == 1 then
+		redis.call("INCRBY", KEYS[5], amount - fee)
+	end
+	return amount
+`)
+
+// Списание без предварительного холда (например, повторный клик)
+var chargeScript = redis.NewScript(`
+	local balance = redis.call("GET", KEYS[1])
+	if not balance then
+		return -2
+	end
+	local amount = tonumber(ARGV[1])
+	if tonumber(balance) < amount then
+		return -1
+	end
+	local fee = tonumber(ARGV[4])
+	local banner = ARGV[5]
+	if ARGV[7] ~= "" then
+		banner = banner .. "," .. ARGV[7]
+	end
+	local left = redis.call("DECRBY", KEYS[1], amount)
+	redis.call("HINCRBY", KEYS[3], ARGV[2], -amount)
+	redis.call("HINCRBY", KEYS[3], ARGV[3], amount - fee)
+	redis.call("RPUSH", KEYS[4], ARGV[2] .. ":" .. ARGV[3] .. ":" .. amount .. ":" .. fee .. "::" .. banner .. ":" .. ARGV[6])
+	if redis.call("EXISTS", KEYS[2]) == 1 then
+		redis.call("INCRBY", KEYS[2], amount - fee)
+	end
+	return left
+`)
+
+var adjustScript = redis.NewScript(`
+	if redis.call("EXISTS", KEYS[1]) == 1 then
+		return redis.call("INCRBY", KEYS[1], ARGV[1])
+	end
+	return 0
+`)
+
+// Возвращает рекламодателю один истёкший холд. Как и в Capture, рекламодатель
+// прочитан заранее и сверяется, а холд, уже превращённый в списание, не трогается
+var releaseScript = redis.NewScript(`
+	if not redis.call("ZSCORE", KEYS[2], ARGV[1]) then
+		return 0
+	end
+	local hold = redis.call("HMGET", KEYS[1], "advertiser", "amount")
+	if hold[1] and hold[1] ~= ARGV[2] then
+		return 0
+	end
+	if hold[1] and redis.call("EXISTS", KEYS[3]) == 1 then
+		redis.call("INCRBY", KEYS[3], tonumber(hold[2]))
+	end
+	redis.call("DEL", KEYS[1])
+	redis.call("ZREM", KEYS[2], ARGV[1])
+	return 1
+`)
+
+// Незавершённая пачка всегда доезжает первой, новая собирается только после неё
+var takeBatchScript = redis.NewScript(`
+	if redis.call("EXISTS", KEYS[2]) == 0 then
+		if redis.call("EXISTS", KEYS[1]) == 0 then
+			return {}
+		end
+		redis.call("RENAME", KEYS[1], KEYS[2])
+		redis.call("HSET", KEYS[2], ARGV[1], ARGV[2])
+		if redis.call("EXISTS", KEYS[3]) == 1 then
+			redis.call("RENAME", KEYS[3], KEYS[4])
+		end
+	end
+	return redis.call("HGETALL", KEYS[2])
+`)
+
+func (r *BudgetRepository) FlushingBatchID() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	batchID, err := r.Client.HGet(ctx, flushingKey, batchField).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get flushing batch: %w", err)
+	}
+	return batchID, nil
+}
+
+func (r *BudgetRepository) LoadBalance(userID int, kopecks int64, batchID string, includeFlushing bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	include := "0"
+	if includeFlushing {
+		include = "1"
+	}
+	keys := []string{balanceKey(userID), pendingKey, flushingKey}
+	result, err := loadScript.Run(ctx, r.Client, keys, kopecks, batchID, include, batchField, userID).Int64()
+	if err != nil {
+		return fmt.Errorf("failed to load balance: %w", err)
+	}
+	if result == changedResult {
+		return ErrBatchChanged
+	}
+	return nil
+}
+
+func (r *BudgetRepository) Reserve(holdID string, advertiserID, publisherID int, kopecks, feeKopecks int64, bannerID int64, slotLink, conversion string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	expireAt := time.Now().Add(r.HoldTTL).Unix()
+	keys := []string{balanceKey(advertiserID), holdKey(holdID), holdsKey}
+	left, err := reserveScript.Run(ctx, r.Client, keys, kopecks, advertiserID, publisherID, expireAt, holdID, feeKopecks, bannerID, slotLink, conversion).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to reserve: %w", err)
+	}
+
+	switch left {
+	case notLoadedResult:
+		return 0, ErrBalanceNotLoaded
+	case noFundsResult:
+		return 0, ErrInsufficientFunds
+	}
+	return left, nil
+}
+
+func (r *BudgetRepository) Capture(holdID string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	publisher, err := r.Client.HGet(ctx, holdKey(holdID), "publisher").Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, ErrHoldNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read reservation: %w", err)
+	}
+	publisherID, err := strconv.Atoi(publisher)
+	if err != nil {
+		return 0, fmt.Errorf("invalid publisher in reservation: %w", err)
+	}
+
+	keys := []string{holdKey(holdID), holdsKey, pendingKey, chargesKey, balanceKey(publisherID)}
+	amount, err := captureScript.Run(ctx, r.Client, keys, holdID, publisher).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to capture reservation: %w", err)
+	}
+	if amount < 0 {
+		return 0, ErrHoldNotFound
+	}
+	return amount, nil
+}
+
+func (r *BudgetRepository) Charge(advertiserID, publisherID int, kopecks, feeKopecks int64, bannerID int64, slotLink, conversion string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	keys := []string{balanceKey(advertiserID), balanceKey(publisherID), pendingKey, chargesKey}
+	left, err := chargeScript.Run(ctx, r.Client, keys, kopecks, advertiserID, publisherID, feeKopecks, bannerID, slotLink, conversion).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to charge: %w", err)
+	}
+
+	switch left {
+	case notLoadedResult:
+		return 0, ErrBalanceNotLoaded
+	case noFundsResult:
+		return 0, ErrInsufficientFunds
+	}
+	return left, nil
+}
+
+// Adjust двигает закэшированный остаток вслед за изменениями, прошедшими мимо
+// кэша (пополнения, выплаты). Если остаток ещё не загружен, делать ничего не нужно
+func (r *BudgetRepository) Adjust(userID int, kopecks int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := adjustScript.Run(ctx, r.Client, []string{balanceKey(userID)}, kopecks).Err(); err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("failed to adjust balance: %w", err)
+	}
+	return nil
+}
+
+func (r *BudgetRepository) GetAvailable(userID int) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	val, err := r.Client.Get(ctx, balanceKey(userID)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, ErrBalanceNotLoaded
+		}
+		return 0, fmt.Errorf("failed to get available balance: %w", err)
+	}
+
+	available, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid balance value in Redis: %w", err)
+	}
+	return available, nil
+}
+
+func (r *BudgetRepository) ReleaseExpired(now time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	expired, err := r.Client.ZRangeByScore(ctx, holdsKey, &redis.ZRangeBy{Min: "-inf", Max: strconv.FormatInt(now.Unix(), 10)}).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired holds: %w", err)
+	}
+
+	released := 0
+	for _, holdID := range expired {
+		advertiser, err := r.Client.HGet(ctx, holdKey(holdID), "advertiser").Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return released, fmt.Errorf("failed to read expired hold: %w", err)
+		}
+		// у холда без данных возвращать нечего, остаётся убрать его из очереди
+		advertiserID, _ := strconv.Atoi(advertiser)
+
+		keys := []string{holdKey(holdID), holdsKey, balanceKey(advertiserID)}
+		n, err := releaseScript.Run(ctx, r.Client, keys, holdID, advertiser).Int()
+		if err != nil {
+			return released, fmt.Errorf("failed to release expired hold: %w", err)
+		}
+		released += n
+	}
+	return released, nil
+}
+
+// TakeBatch возвращает накопленные изменения балансов и отдельные списания
+// для сверки с Postgres. Пока пачка не подтверждена через CompleteBatch,
+// возвращается она же
+func (r *BudgetRepository) TakeBatch(batchID string) (string, map[int]int64, []Charge, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	keys := []string{pendingKey, flushingKey, chargesKey, chargesFlushing}
+	raw, err := takeBatchScript.Run(ctx, r.Client, keys, batchField, batchID).StringSlice()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return "", nil, nil, fmt.Errorf("failed to take batch: %w", err)
+	}
+
+	deltas := make(map[int]int64, len(raw)/2)
+	currentBatch := ""
+	for i := 0; i+1 < len(raw); i += 2 {
+		if raw[i] == batchField {
+			currentBatch = raw[i+1]
+			continue
+		}
+		userID, err := strconv.Atoi(raw[i])
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("invalid user id in batch: %w", err)
+		}
+		delta, err := strconv.ParseInt(raw[i+1], 10, 64)
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("invalid delta in batch: %w", err)
+		}
+		if delta != 0 {
+			deltas[userID] = delta
+		}
+	}
+	if currentBatch == "" {
+		return currentBatch, deltas, nil, nil
+	}
+
+	rawCharges, err := r.Client.LRange(ctx, chargesFlushing, 0, -1).Result()
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to read batch charges: %w", err)
+	}
+	charges := make([]Charge, 0, len(rawCharges))
+	for _, item := range rawCharges {
+		charge, err := parseCharge(item)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		charges = append(charges, charge)
+	}
+	return currentBatch, deltas, charges, nil
+}
+
+// parseCharge разбирает "advertiser:publisher:amount:fee:reference[:banner[,conversion]:slot]",
+// короткий формат остаётся от списаний, записанных до появления размещения.
+// Пересчёт ставки хранится вместе с баннером, потому что ссылка слота может содержать ':'
+func parseCharge(raw string) (Charge, error) {
+	parts := strings.SplitN(raw, ":", 7)
+	if len(parts) != 5 && len(parts) != 7 {
+		return Charge{}, fmt.Errorf("invalid charge in batch: %q", raw)
+	}
+	advertiserID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return Charge{}, fmt.Errorf("invalid advertiser in charge: %w", err)
+	}
+	publisherID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return Charge{}, fmt.Errorf("invalid publisher in charge: %w", err)
+	}
+	kopecks, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return Charge{}, fmt.Errorf("invalid amount in charge: %w", err)
+	}
+	feeKopecks, err := strconv.ParseInt(parts[3], 10, 64)
+	if err != nil {
+		return Charge{}, fmt.Errorf("invalid fee in charge: %w", err)
+	}
+	charge := Charge{
+		AdvertiserID: advertiserID,
+		PublisherID:  publisherID,
+		Kopecks:      kopecks,
+		FeeKopecks:   feeKopecks,
+		Reference:    parts[4],
+	}
+	if len(parts) == 7 {
+		banner, conversion, _ := strings.Cut(parts[5], ",")
+		charge.Conversion = conversion
+		if banner != "" {
+			charge.BannerID, err = strconv.ParseInt(banner, 10, 64)
+			if err != nil {
+				return Charge{}, fmt.Errorf("invalid banner in charge: %w", err)
+			}
+		}
+		charge.SlotLink = parts[6]
+	}
+	return charge, nil
+}
+
+func (r *BudgetRepository) CompleteBatch() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := r.Client.Del(ctx, flushingKey, chargesFlushing).Err(); err != nil {
+		return fmt.Errorf("failed to complete batch: %w", err)
+	}
+	return nil
+}
+
+func (r *BudgetRepository) CloseConnection() error {
+	if r.Client != nil {
+		return r.Client.Close()
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package budget
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupBudget(t *testing.T) (*BudgetRepository, *miniredis.Miniredis) {
+	s, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("miniredis start failed: %v", err)
+	}
+	t.Cleanup(s.Close)
+	return NewBudgetRepository(s.Addr(), "", 0, time.Minute), s
+}
+
+func Test_Reserve_NotLoaded(t *testing.T) {
+	repo, _ := setupBudget(t)
+
+	_, err := repo.Reserve("h1", 1, 2, 100, 0, 0, "", "")
+	assert.ErrorIs(t, err, ErrBalanceNotLoaded)
+}
+
+func Test_Reserve_NeverGoesNegative(t *testing.T) {
+	repo, _ := setupBudget(t)
+	assert.NoError(t, repo.LoadBalance(1, 250, "", false))
+
+	left, err := repo.Reserve("h1", 1, 2, 100, 0, 0, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(150), left)
+
+	left, err = repo.Reserve("h2", 1, 2, 100, 0, 0, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(50), left)
+
+	_, err = repo.Reserve("h3", 1, 2, 100, 0, 0, "", "")
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+
+	available, err := repo.GetAvailable(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(50), available)
+}
+
+func Test_Capture_MovesHoldToBatch(t *testing.T) {
+	repo, _ := setupBudget(t)
+	assert.NoError(t, repo.LoadBalance(1, 1000, "", false))
+	assert.NoError(t, repo.LoadBalance(2, 0, "", false))
+
+	_, err := repo.Reserve("h1", 1, 2, 300, 45, 7, "slot-a", "")
+	assert.NoError(t, err)
+
+	amount, err := repo.Capture("h1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(300), amount)
+
+	_, err = repo.Capture("h1")
+	assert.ErrorIs(t, err, ErrHoldNotFound)
+
+	publisher, err := repo.GetAvailable(2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(255), publisher)
+
+	batchID, deltas, charges, err := repo.TakeBatch("b1")
+	assert.NoError(t, err)
+	assert.Equal(t, "b1", batchID)
+	assert.Equal(t, map[int]int64{1: -300, 2: 255}, deltas)
+	assert.Equal(t, []Charge{{AdvertiserID: 1, PublisherID: 2, Kopecks: 300, FeeKopecks: 45, Reference: "h1", BannerID: 7, SlotLink: "slot-a"}}, charges)
+}
+
+func Test_Charge_WithoutHold(t *testing.T) {
+	repo, _ := setupBudget(t)
+	assert.NoError(t, repo.LoadBalance(1, 100, "", false))
+
+	left, err := repo.Charge(1, 2, 60, 0, 0, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(40), left)
+
+	_, err = repo.Charge(1, 2, 60, 0, 0, "", "")
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func Test_ReleaseExpired_ReturnsFunds(t *testing.T) {
+	repo, _ := setupBudget(t)
+	assert.NoError(t, repo.LoadBalance(1, 100, "", false))
+
+	_, err := repo.Reserve("h1", 1, 2, 100, 0, 0, "", "")
+	assert.NoError(t, err)
+
+	released, err := repo.ReleaseExpired(time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, released)
+
+	released, err = repo.ReleaseExpired(time.Now().Add(2 * time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, released)
+
+	available, err := repo.GetAvailable(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), available)
+
+	_, err = repo.Capture("h1")
+	assert.ErrorIs(t, err, ErrHoldNotFound)
+}
+
+func Test_ReleaseExpired_DropsHoldsWithoutData(t *testing.T) {
+	repo, s := setupBudget(t)
+	assert.NoError(t, repo.LoadBalance(1, 100, "", false))
+	_, err := repo.Reserve("h1", 1, 2, 40, 0, 0, "", "")
+	assert.NoError(t, err)
+	_, err = s.ZAdd(holdsKey, 0, "orphan")
+	assert.NoError(t, err)
+
+	released, err := repo.ReleaseExpired(time.Now().Add(2 * time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, released)
+
+	assert.False(t, s.Exists(holdsKey))
+	available, err := repo.GetAvailable(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), available)
+	assert.False(t, s.Exists(balanceKey(0)))
+}
+
+func Test_TakeBatch_RetriesUntilCompleted(t *testing.T) {
+	repo, _ := setupBudget(t)
+	assert.NoError(t, repo.LoadBalance(1, 100, "", false))
+
+	batchID, deltas, charges, err := repo.TakeBatch("empty")
+	assert.NoError(t, err)
+	assert.Empty(t, batchID)
+	assert.Empty(t, deltas)
+	assert.Empty(t, charges)
+
+	_, err = repo.Charge(1, 2, 10, 0, 0, "", "")
+	assert.NoError(t, err)
+
+	batchID, _, _, err = repo.TakeBatch("b1")
+	assert.NoError(t, err)
+	assert.Equal(t, "b1", batchID)
+
+	_, err = repo.Charge(1, 2, 10, 0, 0, "", "")
+	assert.NoError(t, err)
+
+	batchID, deltas, charges, err = repo.TakeBatch("b2")
+	assert.NoError(t, err)
+	assert.Equal(t, "b1", batchID)
+	assert.Equal(t, int64(-10), deltas[1])
+	assert.Len(t, charges, 1)
+
+	assert.NoError(t, repo.CompleteBatch())
+
+	batchID, deltas, charges, err = repo.TakeBatch("b3")
+	assert.NoError(t, err)
+	assert.Equal(t, "b3", batchID)
+	assert.Equal(t, int64(-10), deltas[1])
+	assert.Equal(t, []Charge{{AdvertiserID: 1, PublisherID: 2, Kopecks: 10}}, charges)
+}
+
+func Test_LoadBalance_IncludesUnflushedDeltas(t *testing.T) {
+	repo, _ := setupBudget(t)
+	assert.NoError(t, repo.LoadBalance(1, 100, "", false))
+
+	_, err := repo.Charge(1, 2, 30, 0, 0, "", "")
+	assert.NoError(t, err)
+	batchID, _, _, err := repo.TakeBatch("b1")
+	assert.NoError(t, err)
+	_, err = repo.Charge(1, 2, 20, 0, 0, "", "")
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, repo.LoadBalance(2, 0, "", false), ErrBatchChanged)
+
+	assert.NoError(t, repo.LoadBalance(2, 0, batchID, true))
+	available, err := repo.GetAvailable(2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(50), available)
+}
+
+func Test_Adjust_OnlyLoaded(t *testing.T) {
+	repo, _ := setupBudget(t)
+
+	assert.NoError(t, repo.Adjust(1, 500))
+	_, err := repo.GetAvailable(1)
+	assert.ErrorIs(t, err, ErrBalanceNotLoaded)
+
+	assert.NoError(t, repo.LoadBalance(1, 100, "", false))
+	assert.NoError(t, repo.Adjust(1, -40))
+	available, err := repo.GetAvailable(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(60), available)
+}
+
+func Test_ParseCharge_Formats(t *testing.T) {
+	charge, err := parseCharge("1:2:300:45:h1")
+	assert.NoError(t, err)
+	assert.Equal(t, Charge{AdvertiserID: 1, PublisherID: 2, Kopecks: 300, FeeKopecks: 45, Reference: "h1"}, charge)
+
+	charge, err = parseCharge("1:2:300:45::7:slot-a")
+	assert.NoError(t, err)
+	assert.Equal(t, Charge{AdvertiserID: 1, PublisherID: 2, Kopecks: 300, FeeKopecks: 45, BannerID: 7, SlotLink: "slot-a"}, charge)
+
+	charge, err = parseCharge("1:2:300:45::7,USD,3.25,4,92.300000:https://site.ru/a")
+	assert.NoError(t, err)
+	assert.Equal(t, Charge{AdvertiserID: 1, PublisherID: 2, Kopecks: 300, FeeKopecks: 45, BannerID: 7, SlotLink: "https://site.ru/a", Conversion: "USD,3.25,4,92.300000"}, charge)
+
+	_, err = parseCharge("1:2:300:45:h1:x:slot-a")
+	assert.Error(t, err)
+}
+
+func Test_Capture_KeepsConversion(t *testing.T) {
+	repo, _ := setupBudget(t)
+	assert.NoError(t, repo.LoadBalance(1, 1000, "", false))
+
+	_, err := repo.Reserve("h1", 1, 2, 300, 0, 7, "https://site.ru/a", "USD,3.25,4,92.300000")
+	assert.NoError(t, err)
+	_, err = repo.Capture("h1")
+	assert.NoError(t, err)
+
+	_, _, charges, err := repo.TakeBatch("b1")
+	assert.NoError(t, err)
+	assert.Len(t, charges, 1)
+	assert.Equal(t, "USD,3.25,4,92.300000", charges[0].Conversion)
+	assert.Equal(t, "https://site.ru/a", charges[0].SlotLink)
+}
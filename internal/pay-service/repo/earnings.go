@@ -0,0 +1,94 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"retarget/internal/pay-service/entity"
+)
+
+func amountAfterFee(amount, fee entity.Decimal) (entity.Decimal, error) {
+	amountKopecks, err := amount.Kopecks()
+	if err != nil {
+		return entity.Decimal{}, err
+	}
+	feeKopecks, err := fee.Kopecks()
+	if err != nil {
+		return entity.Decimal{}, err
+	}
+	return entity.DecimalFromKopecks(amountKopecks - feeKopecks), nil
+}
+
+// GetTakeRate возвращает индивидуальную комиссию владельца слота, если она задана
+func (r *PaymentRepository) GetTakeRate(publisherID int) (entity.Decimal, bool, error) {
+	var rate entity.Decimal
+	err := r.db.QueryRow(`
+        SELECT take_rate FROM publisher_take_rate WHERE user_id = $1`,
+		publisherID,
+	).Scan(&rate)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Decimal{}, false, nil
+		}
+		return entity.Decimal{}, false, fmt.Errorf("failed to get take rate: %w", err)
+	}
+	return rate, true, nil
+}
+
+func (r *PaymentRepository) SetTakeRate(publisherID int, rate entity.Decimal) error {
+	_, err := r.db.Exec(`
+        INSERT INTO publisher_take_rate (user_id, take_rate)
+        VALUES ($1, $2)
+        ON CONFLICT (user_id) DO UPDATE
+        SET take_rate = EXCLUDED.take_rate,
+            updated_at = (now() AT TIME ZONE 'UTC')`,
+		publisherID,
+		rate,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set take rate: %w", err)
+	}
+	return nil
+}
+
+// GetEarnings считает по журналу доход владельца слота: полную стоимость
+// показов, удержанную комиссию и сумму, зачисленную на баланс
+func (r *PaymentRepository) GetEarnings(publisherID int) (entity.Earnings, error) {
+	const query = `
+        SELECT COUNT(*), COALESCE(SUM(pub.amount), 0), COALESCE(SUM(fee.amount), 0)
+        FROM ledger_entry e
+        JOIN ledger_posting pub ON pub.entry_id = e.id
+        JOIN ledger_account pa ON pa.id = pub.account_id
+        LEFT JOIN (
+            SELECT p.entry_id, p.amount
+            FROM ledger_posting p
+            JOIN ledger_account a ON a.id = p.account_id
+            WHERE a.type = 'platform_fee'
+        ) fee ON fee.entry_id = e.id
+        WHERE e.kind = 'impression_charge'
+          AND pa.owner_id = $1
+          AND pa.type = 'publisher'`
+
+	var (
+		earnings entity.Earnings
+		net      entity.Decimal
+		fee      entity.Decimal
+	)
+	if err := r.db.QueryRow(query, publisherID).Scan(&earnings.Impressions, &net, &fee); err != nil {
+		return entity.Earnings{}, fmt.Errorf("failed to get earnings: %w", err)
+	}
+
+	netKopecks, err := net.Kopecks()
+	if err != nil {
+		return entity.Earnings{}, err
+	}
+	feeKopecks, err := fee.Kopecks()
+	if err != nil {
+		return entity.Earnings{}, err
+	}
+	earnings.Net = entity.DecimalFromKopecks(netKopecks)
+	earnings.Fee = entity.DecimalFromKopecks(feeKopecks)
+	earnings.Gross = entity.DecimalFromKopecks(netKopecks + feeKopecks)
+	return earnings, nil
+}
@@ -0,0 +1,55 @@
+package repo_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"retarget/internal/pay-service/entity"
+)
+
+func TestGetTakeRate_NotSet(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	mock.ExpectQuery("SELECT take_rate FROM publisher_take_rate").
+		WithArgs(3).
+		WillReturnError(sql.ErrNoRows)
+
+	_, found, err := r.GetTakeRate(3)
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetTakeRate(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	rate, _ := entity.ParseTakeRate("0.10")
+	mock.ExpectExec("INSERT INTO publisher_take_rate").
+		WithArgs(3, "0.10").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, r.SetTakeRate(3, rate))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetEarnings(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	mock.ExpectQuery("FROM ledger_entry e").
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "net", "fee"}).AddRow(4, "8.50", "1.5"))
+
+	earnings, err := r.GetEarnings(3)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, earnings.Impressions)
+	assert.Equal(t, "10.00", earnings.Gross.String())
+	assert.Equal(t, "1.50", earnings.Fee.String())
+	assert.Equal(t, "8.50", earnings.Net.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
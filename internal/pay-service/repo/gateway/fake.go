@@ -0,0 +1,165 @@
+package gateway
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FakeGateway — шлюз в памяти процесса для интеграционных тестов и стенда:
+// деньги никуда не уходят, а платежи и выплаты сами завершаются через
+// CompleteAfter и присылают уведомление так же, как настоящая YooKassa
+type FakeGateway struct {
+	// CompleteAfter — через сколько объект переходит в succeeded; ноль
+	// оставляет его в pending до ручного вызова SetStatus
+	CompleteAfter time.Duration
+	// Notify получает событие вида "payment.succeeded" и ID объекта
+	Notify func(event, objectID string)
+
+	mu      sync.Mutex
+	objects map[string]*fakeObject
+	keys    map[string]string // Idempotence-Key -> ID объекта
+	methods map[string]bool   // сохранённые способы оплаты
+}
+
+type fakeObject struct {
+	objectType string
+	status     string
+	amount     Amount
+	paymentID  string
+}
+
+func NewFakeGateway(completeAfter time.Duration) *FakeGateway {
+	return &FakeGateway{
+		CompleteAfter: completeAfter,
+		objects:       make(map[string]*fakeObject),
+		keys:          make(map[string]string),
+		methods:       make(map[string]bool),
+	}
+}
+
+// create сохраняет объект; повторный запрос с тем же ключом идемпотентности
+// возвращает уже созданный объект
+func (g *FakeGateway) create(objectType, idempotenceKey, status string, amount Amount, paymentID string) (string, *fakeObject, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if idempotenceKey != "" {
+		if id, ok := g.keys[objectType+":"+idempotenceKey]; ok {
+			return id, g.objects[id], false
+		}
+	}
+	id := uuid.NewString()
+	obj := &fakeObject{objectType: objectType, status: status, amount: amount, paymentID: paymentID}
+	g.objects[id] = obj
+	if idempotenceKey != "" {
+		g.keys[objectType+":"+idempotenceKey] = id
+	}
+	return id, obj, true
+}
+
+func (g *FakeGateway) scheduleCompletion(id string) {
+	if g.CompleteAfter <= 0 {
+		return
+	}
+	time.AfterFunc(g.CompleteAfter, func() {
+		//nolint:errcheck
+		g.SetStatus(id, StatusSucceeded)
+	})
+}
+
+func (g *FakeGateway) CreatePayment(req PaymentRequest) (*Payment, error) {
+	if req.PaymentMethodID != "" {
+		g.mu.Lock()
+		known := g.methods[req.PaymentMethodID]
+		g.mu.Unlock()
+		if !known {
+			return nil, ErrNotFound
+		}
+	}
+
+	id, obj, created := g.create(ObjectPayments, req.IdempotenceKey, StatusPending, req.Amount, "")
+	if created {
+		g.scheduleCompletion(id)
+	}
+	payment := &Payment{
+		ID:              id,
+		Status:          obj.status,
+		Amount:          obj.amount,
+		ConfirmationURL: req.ReturnURL,
+	}
+	if req.SavePaymentMethod {
+		payment.PaymentMethodID = "pm-" + id
+		payment.PaymentMethodTitle = "Fake card *4242"
+		g.mu.Lock()
+		g.methods[payment.PaymentMethodID] = true
+		g.mu.Unlock()
+	}
+	return payment, nil
+}
+
+func (g *FakeGateway) GetStatus(objectType, objectID string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	obj, ok := g.objects[objectID]
+	if !ok || obj.objectType != objectType {
+		return "", ErrNotFound
+	}
+	return obj.status, nil
+}
+
+func (g *FakeGateway) CreatePayout(req PayoutRequest) (*Payout, error) {
+	if req.DestinationType != "bank_card" && req.DestinationType != "yoo_money" {
+		return nil, ErrUnsupportedPayout
+	}
+	id, obj, created := g.create(ObjectPayouts, req.IdempotenceKey, StatusPending, req.Amount, "")
+	if created {
+		g.scheduleCompletion(id)
+	}
+	return &Payout{ID: id, Status: obj.status, Amount: obj.amount}, nil
+}
+
+func (g *FakeGateway) CreateRefund(req RefundRequest) (*Refund, error) {
+	g.mu.Lock()
+	payment, ok := g.objects[req.PaymentID]
+	g.mu.Unlock()
+	if !ok || payment.objectType != ObjectPayments {
+		return nil, ErrNotFound
+	}
+
+	id, obj, _ := g.create(ObjectRefunds, req.IdempotenceKey, StatusSucceeded, req.Amount, req.PaymentID)
+	return &Refund{ID: id, PaymentID: obj.paymentID, Status: obj.status, Amount: obj.amount}, nil
+}
+
+// SetStatus переводит объект в новый статус и рассылает уведомление, если статус изменился
+func (g *FakeGateway) SetStatus(objectID, status string) error {
+	g.mu.Lock()
+	obj, ok := g.objects[objectID]
+	if !ok {
+		g.mu.Unlock()
+		return ErrNotFound
+	}
+	changed := obj.status != status
+	obj.status = status
+	objectType := obj.objectType
+	g.mu.Unlock()
+
+	if changed && g.Notify != nil {
+		g.Notify(fmt.Sprintf("%s.%s", eventPrefix(objectType), status), objectID)
+	}
+	return nil
+}
+
+func eventPrefix(objectType string) string {
+	switch objectType {
+	case ObjectPayouts:
+		return "payout"
+	case ObjectRefunds:
+		return "refund"
+	default:
+		return "payment"
+	}
+}
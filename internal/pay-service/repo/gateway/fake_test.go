@@ -0,0 +1,75 @@
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Fake_PaymentLifecycle(t *testing.T) {
+	g := NewFakeGateway(0)
+	var events []string
+	g.Notify = func(event, objectID string) { events = append(events, event+":"+objectID) }
+
+	payment, err := g.CreatePayment(PaymentRequest{
+		Amount:         Amount{Value: "100.00", Currency: "RUB"},
+		ReturnURL:      "https://example.com/back",
+		IdempotenceKey: "k1",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, StatusPending, payment.Status)
+	assert.Equal(t, "https://example.com/back", payment.ConfirmationURL)
+
+	again, err := g.CreatePayment(PaymentRequest{IdempotenceKey: "k1"})
+	assert.NoError(t, err)
+	assert.Equal(t, payment.ID, again.ID)
+
+	assert.NoError(t, g.SetStatus(payment.ID, StatusSucceeded))
+	assert.NoError(t, g.SetStatus(payment.ID, StatusSucceeded))
+	assert.Equal(t, []string{"payment.succeeded:" + payment.ID}, events)
+
+	status, err := g.GetStatus(ObjectPayments, payment.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusSucceeded, status)
+
+	_, err = g.GetStatus(ObjectPayouts, payment.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	refund, err := g.CreateRefund(RefundRequest{PaymentID: payment.ID, Amount: Amount{Value: "10.00", Currency: "RUB"}})
+	assert.NoError(t, err)
+	assert.Equal(t, StatusSucceeded, refund.Status)
+	assert.Equal(t, payment.ID, refund.PaymentID)
+}
+
+func Test_Fake_PayoutCompletesAutomatically(t *testing.T) {
+	g := NewFakeGateway(10 * time.Millisecond)
+	done := make(chan string, 1)
+	g.Notify = func(event, objectID string) { done <- event }
+
+	payout, err := g.CreatePayout(PayoutRequest{DestinationType: "yoo_money", Amount: Amount{Value: "5.00", Currency: "RUB"}})
+	assert.NoError(t, err)
+	assert.Equal(t, StatusPending, payout.Status)
+
+	select {
+	case event := <-done:
+		assert.Equal(t, "payout.succeeded", event)
+	case <-time.After(time.Second):
+		t.Fatal("payout was not completed")
+	}
+}
+
+func Test_Fake_RecurringPaymentNeedsSavedMethod(t *testing.T) {
+	g := NewFakeGateway(0)
+
+	_, err := g.CreatePayment(PaymentRequest{PaymentMethodID: "pm-unknown"})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	first, err := g.CreatePayment(PaymentRequest{SavePaymentMethod: true})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, first.PaymentMethodID)
+
+	recurring, err := g.CreatePayment(PaymentRequest{PaymentMethodID: first.PaymentMethodID})
+	assert.NoError(t, err)
+	assert.Equal(t, StatusPending, recurring.Status)
+}
@@ -0,0 +1,91 @@
+package gateway
+
+import "errors"
+
+// Разделы API платёжного шлюза, в которых живут объекты
+const (
+	ObjectPayments = "payments"
+	ObjectPayouts  = "payouts"
+	ObjectRefunds  = "refunds"
+)
+
+// Статусы объектов в терминах YooKassa, их же отдаёт фейковый шлюз
+const (
+	StatusPending           = "pending"
+	StatusWaitingForCapture = "waiting_for_capture"
+	StatusSucceeded         = "succeeded"
+	StatusCanceled          = "canceled"
+)
+
+var (
+	ErrNotFound           = errors.New("gateway object not found")
+	ErrUnsupportedPayout  = errors.New("unsupported destination type")
+	ErrUnexpectedResponse = errors.New("unexpected gateway response")
+	// ErrRejected — шлюз явно отказал в запросе, и объект не создан. Прочие
+	// ошибки не гарантируют, что запрос не был исполнен
+	ErrRejected = errors.New("request rejected by gateway")
+)
+
+type Amount struct {
+	Value    string `json:"value"`
+	Currency string `json:"currency"`
+}
+
+type PaymentRequest struct {
+	Amount         Amount
+	Description    string
+	ReturnURL      string
+	IdempotenceKey string
+	Metadata       map[string]interface{}
+	// SavePaymentMethod просит шлюз сохранить способ оплаты для повторных списаний
+	SavePaymentMethod bool
+	// PaymentMethodID списывает деньги сохранённым способом без подтверждения пользователем
+	PaymentMethodID string
+}
+
+type Payment struct {
+	ID              string
+	Status          string
+	Amount          Amount
+	ConfirmationURL string
+	// PaymentMethodID и PaymentMethodTitle заполнены, если способ оплаты сохраняется
+	PaymentMethodID    string
+	PaymentMethodTitle string
+}
+
+type PayoutRequest struct {
+	Amount          Amount
+	DestinationType string // bank_card или yoo_money
+	Destination     string
+	Description     string
+	IdempotenceKey  string
+	UserID          int
+}
+
+type Payout struct {
+	ID     string
+	Status string
+	Amount Amount
+}
+
+type RefundRequest struct {
+	PaymentID      string
+	Amount         Amount
+	Description    string
+	IdempotenceKey string
+}
+
+type Refund struct {
+	ID        string
+	PaymentID string
+	Status    string
+	Amount    Amount
+}
+
+// PaymentGateway — внешний платёжный провайдер: приём платежей, выплаты и возвраты
+type PaymentGateway interface {
+	CreatePayment(req PaymentRequest) (*Payment, error)
+	GetStatus(objectType, objectID string) (string, error)
+	CreatePayout(req PayoutRequest) (*Payout, error)
+	CreateRefund(req RefundRequest) (*Refund, error)
+}
@@ -0,0 +1,226 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const yooKassaBaseURL = "https://api.yookassa.ru/v3"
+
+type YooKassaGateway struct {
+	shopID     string
+	secretKey  string
+	uuid       string // идентификатор мерчанта для payouts
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewYooKassaGateway(shopID, secretKey, uuid string, httpClient *http.Client) *YooKassaGateway {
+	return &YooKassaGateway{
+		shopID:     shopID,
+		secretKey:  secretKey,
+		uuid:       uuid,
+		baseURL:    yooKassaBaseURL,
+		httpClient: httpClient,
+	}
+}
+
+type yooConfirmation struct {
+	Type            string `json:"type"`
+	ReturnURL       string `json:"return_url,omitempty"`
+	ConfirmationURL string `json:"confirmation_url,omitempty"`
+}
+
+type yooPaymentRequest struct {
+	Amount            Amount                 `json:"amount"`
+	Confirmation      *yooConfirmation       `json:"confirmation,omitempty"`
+	Description       string                 `json:"description"`
+	Metadata          map[string]interface{} `json:"metadata,omitempty"`
+	SavePaymentMethod bool                   `json:"save_payment_method,omitempty"`
+	PaymentMethodID   string                 `json:"payment_method_id,omitempty"`
+}
+
+type yooPaymentMethod struct {
+	ID    string `json:"id"`
+	Saved bool   `json:"saved"`
+	Title string `json:"title"`
+}
+
+type yooPayoutRequest struct {
+	Amount                Amount `json:"amount"`
+	PayoutDestinationData struct {
+		Type string `json:"type"`
+		Card struct {
+			Number string `json:"number"`
+		} `json:"card,omitempty"`
+		YooMoney struct {
+			AccountNumber string `json:"account_number"`
+		} `json:"yoo_money,omitempty"`
+	} `json:"payoutDestinationData"`
+	Description string `json:"description"`
+	Metadata    struct {
+		UserID int `json:"user_id"`
+	} `json:"metadata,omitempty"`
+}
+
+type yooRefundRequest struct {
+	PaymentID   string `json:"payment_id"`
+	Amount      Amount `json:"amount"`
+	Description string `json:"description,omitempty"`
+}
+
+type yooObject struct {
+	ID           string          `json:"id"`
+	Status       string          `json:"status"`
+	PaymentID    string          `json:"payment_id"`
+	Amount       Amount          `json:"amount"`
+	Confirmation yooConfirmation `json:"confirmation"`
+	// PaymentMethod приходит только для платежей
+	PaymentMethod yooPaymentMethod `json:"payment_method"`
+}
+
+// rejected сообщает, что статус ответа — окончательный отказ. Конфликт ключа
+// идемпотентности и превышение частоты запросов к ним не относятся
+func rejected(status int) bool {
+	return status >= 400 && status < 500 && status != http.StatusConflict && status != http.StatusTooManyRequests
+}
+
+func (g *YooKassaGateway) do(method, path, idempotenceKey string, headers http.Header, body interface{}) (*yooObject, error) {
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("marshal request: %w", err)
+		}
+		payload = bytes.NewBuffer(data)
+	}
+
+	req, err := http.NewRequest(method, g.baseURL+path, payload)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.SetBasicAuth(g.shopID, g.secretKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if idempotenceKey != "" {
+		req.Header.Set("Idempotence-Key", idempotenceKey)
+	}
+	for key, values := range headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		if rejected(resp.StatusCode) {
+			return nil, fmt.Errorf("%w: %w: status %d: %s", ErrRejected, ErrUnexpectedResponse, resp.StatusCode, string(bodyBytes))
+		}
+		return nil, fmt.Errorf("%w: status %d: %s", ErrUnexpectedResponse, resp.StatusCode, string(bodyBytes))
+	}
+
+	var out yooObject
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &out, nil
+}
+
+func (g *YooKassaGateway) CreatePayment(req PaymentRequest) (*Payment, error) {
+	body := yooPaymentRequest{
+		Amount:            req.Amount,
+		Description:       req.Description,
+		Metadata:          req.Metadata,
+		SavePaymentMethod: req.SavePaymentMethod,
+		PaymentMethodID:   req.PaymentMethodID,
+	}
+	// повторное списание сохранённым способом проходит без редиректа
+	if req.PaymentMethodID == "" {
+		body.Confirmation = &yooConfirmation{Type: "redirect", ReturnURL: req.ReturnURL}
+	}
+
+	out, err := g.do(http.MethodPost, "/payments", req.IdempotenceKey, nil, body)
+	if err != nil {
+		return nil, err
+	}
+	payment := &Payment{
+		ID:              out.ID,
+		Status:          out.Status,
+		Amount:          out.Amount,
+		ConfirmationURL: out.Confirmation.ConfirmationURL,
+	}
+	if req.SavePaymentMethod {
+		payment.PaymentMethodID = out.PaymentMethod.ID
+		payment.PaymentMethodTitle = out.PaymentMethod.Title
+	}
+	return payment, nil
+}
+
+// GetStatus перезапрашивает объект у YooKassa, статусам из уведомлений не доверяем
+func (g *YooKassaGateway) GetStatus(objectType, objectID string) (string, error) {
+	out, err := g.do(http.MethodGet, "/"+objectType+"/"+objectID, "", nil, nil)
+	if err != nil {
+		return "", err
+	}
+	if out.ID != "" && out.ID != objectID {
+		return "", fmt.Errorf("%w: object %s instead of %s", ErrUnexpectedResponse, out.ID, objectID)
+	}
+	return out.Status, nil
+}
+
+func (g *YooKassaGateway) CreatePayout(req PayoutRequest) (*Payout, error) {
+	body := yooPayoutRequest{Amount: req.Amount, Description: req.Description}
+	body.PayoutDestinationData.Type = req.DestinationType
+	switch req.DestinationType {
+	case "bank_card":
+		body.PayoutDestinationData.Card.Number = req.Destination
+	case "yoo_money":
+		body.PayoutDestinationData.YooMoney.AccountNumber = req.Destination
+	default:
+		return nil, ErrUnsupportedPayout
+	}
+	body.Metadata.UserID = req.UserID
+
+	headers := http.Header{}
+	headers.Set("X-Request-ID", req.IdempotenceKey)
+	if g.uuid != "" {
+		headers.Set("X-YooMoney-UUID", g.uuid)
+	}
+
+	out, err := g.do(http.MethodPost, "/payouts", req.IdempotenceKey, headers, body)
+	if err != nil {
+		return nil, err
+	}
+	return &Payout{ID: out.ID, Status: out.Status, Amount: out.Amount}, nil
+}
+
+func (g *YooKassaGateway) CreateRefund(req RefundRequest) (*Refund, error) {
+	body := yooRefundRequest{
+		PaymentID:   req.PaymentID,
+		Amount:      req.Amount,
+		Description: req.Description,
+	}
+	out, err := g.do(http.MethodPost, "/refunds", req.IdempotenceKey, nil, body)
+	if err != nil {
+		return nil, err
+	}
+	return &Refund{
+		ID:        out.ID,
+		PaymentID: out.PaymentID,
+		Status:    out.Status,
+		Amount:    out.Amount,
+	}, nil
+}
@@ -0,0 +1,98 @@
+package gateway
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripper func(req *http.Request) *http.Response
+
+func (rt roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return rt(req), nil
+}
+
+func respond(status int, body string) *http.Response {
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func Test_YooKassa_CreatePayout(t *testing.T) {
+	var sent yooPayoutRequest
+	var headers http.Header
+	g := NewYooKassaGateway("shop", "secret", "merchant", &http.Client{Transport: roundTripper(func(req *http.Request) *http.Response {
+		headers = req.Header
+		assert.Equal(t, "/v3/payouts", req.URL.Path)
+		//nolint:errcheck
+		json.NewDecoder(req.Body).Decode(&sent)
+		return respond(http.StatusOK, `{"id":"po1","status":"pending","amount":{"value":"10.00","currency":"RUB"}}`)
+	})})
+
+	out, err := g.CreatePayout(PayoutRequest{
+		Amount:          Amount{Value: "10.00", Currency: "RUB"},
+		DestinationType: "yoo_money",
+		Destination:     "4100",
+		IdempotenceKey:  "key",
+		UserID:          7,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "po1", out.ID)
+	assert.Equal(t, "4100", sent.PayoutDestinationData.YooMoney.AccountNumber)
+	assert.Equal(t, 7, sent.Metadata.UserID)
+	assert.Equal(t, "key", headers.Get("Idempotence-Key"))
+	assert.Equal(t, "merchant", headers.Get("X-YooMoney-UUID"))
+
+	_, err = g.CreatePayout(PayoutRequest{DestinationType: "sbp"})
+	assert.ErrorIs(t, err, ErrUnsupportedPayout)
+}
+
+func Test_YooKassa_GetStatus(t *testing.T) {
+	g := NewYooKassaGateway("", "", "", &http.Client{Transport: roundTripper(func(req *http.Request) *http.Response {
+		switch req.URL.Path {
+		case "/v3/payments/p1":
+			return respond(http.StatusOK, `{"id":"p1","status":"succeeded"}`)
+		case "/v3/payments/p2":
+			return respond(http.StatusOK, `{"id":"other","status":"succeeded"}`)
+		default:
+			return respond(http.StatusNotFound, `{}`)
+		}
+	})})
+
+	status, err := g.GetStatus(ObjectPayments, "p1")
+	assert.NoError(t, err)
+	assert.Equal(t, StatusSucceeded, status)
+
+	_, err = g.GetStatus(ObjectPayments, "p2")
+	assert.ErrorIs(t, err, ErrUnexpectedResponse)
+
+	_, err = g.GetStatus(ObjectPayouts, "p1")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func Test_YooKassa_CreatePayment_SavedMethod(t *testing.T) {
+	var sent map[string]interface{}
+	g := NewYooKassaGateway("shop", "secret", "", &http.Client{Transport: roundTripper(func(req *http.Request) *http.Response {
+		sent = nil
+		//nolint:errcheck
+		json.NewDecoder(req.Body).Decode(&sent)
+		return respond(http.StatusOK, `{"id":"p1","status":"pending","amount":{"value":"500.00","currency":"RUB"},
+			"confirmation":{"type":"redirect","confirmation_url":"https://pay"},
+			"payment_method":{"id":"pm1","saved":false,"title":"Bank card *4444"}}`)
+	})})
+
+	out, err := g.CreatePayment(PaymentRequest{Amount: Amount{Value: "500.00", Currency: "RUB"}, ReturnURL: "ret", SavePaymentMethod: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "pm1", out.PaymentMethodID)
+	assert.Equal(t, "Bank card *4444", out.PaymentMethodTitle)
+	assert.Equal(t, true, sent["save_payment_method"])
+	assert.Contains(t, sent, "confirmation")
+
+	out, err = g.CreatePayment(PaymentRequest{Amount: Amount{Value: "500.00", Currency: "RUB"}, PaymentMethodID: "pm1"})
+	assert.NoError(t, err)
+	assert.Empty(t, out.PaymentMethodID)
+	assert.Equal(t, "pm1", sent["payment_method_id"])
+	assert.NotContains(t, sent, "confirmation")
+}
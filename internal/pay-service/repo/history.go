@@ -0,0 +1,120 @@
+package repo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"retarget/internal/pay-service/entity"
+
+	"github.com/lib/pq"
+)
+
+// история собирается из платёжных операций и списаний за показы из журнала;
+// у записей журнала статуса нет, они всегда проведены. Показ, оплаченный
+// частично промо-кредитом, остаётся одной строкой
+const historyQuery = `
+    WITH history AS (
+        SELECT 'transaction' AS source, t.id::BIGINT AS id, t.transaction_id AS reference,
+               CASE
+                   WHEN t.type = 'yoomoney_payment' OR t.type LIKE 'topup%' THEN 'top_up'
+                   WHEN t.type = 'withdrawal_payment' OR t.type LIKE 'payout%' THEN 'payout'
+                   WHEN t.type LIKE 'refund%' THEN 'refund'
+                   ELSE 'other'
+               END AS type,
+               t.type AS raw_type, t.status::INT AS status, ABS(t.amount) AS amount, t.created_at
+        FROM transaction t
+        WHERE t.user_id = $1
+        UNION ALL
+        SELECT 'ledger', e.id, e.reference,
+               CASE WHEN a.type IN ('advertiser', 'promo') THEN 'charge' ELSE 'credit' END,
+               e.kind, 1, ABS(SUM(p.amount)), e.created_at
+        FROM ledger_posting p
+        JOIN ledger_account a ON a.id = p.account_id
+        JOIN ledger_entry e ON e.id = p.entry_id
+        WHERE a.owner_id = $1 AND e.kind = 'impression_charge'
+        GROUP BY e.id, 4
+    )
+    SELECT source, id, reference, type, raw_type, status, amount, created_at
+    FROM history`
+
+// GetTransactionHistory отдаёт не больше filter.Limit строк истории пользователя,
+// идущих после filter.After в порядке filter.Sort
+func (r *PaymentRepository) GetTransactionHistory(filter entity.HistoryFilter) ([]entity.HistoryItem, error) {
+	args := []interface{}{filter.UserID}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	var where []string
+	if len(filter.Types) > 0 {
+		types := make([]string, 0, len(filter.Types))
+		for _, t := range filter.Types {
+			types = append(types, string(t))
+		}
+		where = append(where, "type = ANY("+arg(pq.Array(types))+")")
+	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]int64, 0, len(filter.Statuses))
+		for _, s := range filter.Statuses {
+			statuses = append(statuses, int64(s))
+		}
+		where = append(where, "status = ANY("+arg(pq.Array(statuses))+")")
+	}
+	if !filter.From.IsZero() {
+		where = append(where, "created_at >= "+arg(filter.From.UTC()))
+	}
+	if !filter.To.IsZero() {
+		where = append(where, "created_at < "+arg(filter.To.UTC()))
+	}
+	if filter.MinAmount != nil {
+		where = append(where, "amount >= "+arg(*filter.MinAmount))
+	}
+	if filter.MaxAmount != nil {
+		where = append(where, "amount <= "+arg(*filter.MaxAmount))
+	}
+
+	column, cast := "created_at", "::TIMESTAMP"
+	if filter.Sort == entity.HistorySortAmount {
+		column, cast = "amount", "::NUMERIC"
+	}
+	direction, compare := "ASC", ">"
+	if filter.Desc {
+		direction, compare = "DESC", "<"
+	}
+	if filter.After != nil {
+		where = append(where, fmt.Sprintf("(%s, source, id, type) %s (%s%s, %s, %s, %s)",
+			column, compare, arg(filter.After.Value), cast, arg(filter.After.Source), arg(filter.After.ID), arg(string(filter.After.Type))))
+	}
+
+	query := historyQuery
+	if len(where) > 0 {
+		query += "\n    WHERE " + strings.Join(where, " AND ")
+	}
+	query += fmt.Sprintf("\n    ORDER BY %[1]s %[2]s, source %[2]s, id %[2]s, type %[2]s\n    LIMIT %[3]s",
+		column, direction, arg(filter.Limit))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction history: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]entity.HistoryItem, 0)
+	for rows.Next() {
+		var (
+			item   entity.HistoryItem
+			status int
+		)
+		if err := rows.Scan(&item.Source, &item.ID, &item.Reference, &item.Type, &item.RawType, &status, &item.Amount, &item.CreatedAt); err != nil {
+			return nil, err
+		}
+		item.Status = entity.TransactionStatusName(status)
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
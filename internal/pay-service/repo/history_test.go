@@ -0,0 +1,60 @@
+package repo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"retarget/internal/pay-service/entity"
+)
+
+var historyColumns = []string{"source", "id", "reference", "type", "raw_type", "status", "amount", "created_at"}
+
+func TestGetTransactionHistory_FiltersAndCursor(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	from := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+	minAmount := entity.DecimalFromKopecks(100)
+	filter := entity.HistoryFilter{
+		UserID:    5,
+		Types:     []entity.HistoryType{entity.HistoryCharge, entity.HistoryTopUp},
+		From:      from,
+		MinAmount: &minAmount,
+		Sort:      entity.HistorySortAmount,
+		Desc:      true,
+		Limit:     3,
+		After:     &entity.HistoryCursor{Sort: entity.HistorySortAmount, Value: "7.00", Source: "transaction", ID: 9, Type: entity.HistoryTopUp},
+	}
+
+	mock.ExpectQuery(`WHERE type = ANY\(\$2\) AND created_at >= \$3 AND amount >= \$4 AND \(amount, source, id, type\) < \(\$5::NUMERIC, \$6, \$7, \$8\)\s+ORDER BY amount DESC, source DESC, id DESC, type DESC\s+LIMIT \$9`).
+		WithArgs(5, "{\"charge\",\"top_up\"}", from, "1.00", "7.00", "transaction", int64(9), "top_up", 3).
+		WillReturnRows(sqlmock.NewRows(historyColumns).
+			AddRow("ledger", 12, "hold-1", "charge", "impression_charge", 1, "5.00", from).
+			AddRow("transaction", 4, "pay-1", "top_up", "yoomoney_payment", 0, "1.00", from))
+
+	items, err := r.GetTransactionHistory(filter)
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+	assert.Equal(t, entity.HistoryCharge, items[0].Type)
+	assert.Equal(t, "succeeded", items[0].Status)
+	assert.Equal(t, "5.00", items[0].Amount.String())
+	assert.Equal(t, "pending", items[1].Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTransactionHistory_NoFilters(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	mock.ExpectQuery(`FROM history\s+ORDER BY created_at ASC, source ASC, id ASC, type ASC\s+LIMIT \$2`).
+		WithArgs(5, 50).
+		WillReturnRows(sqlmock.NewRows(historyColumns))
+
+	items, err := r.GetTransactionHistory(entity.HistoryFilter{UserID: 5, Sort: entity.HistorySortCreatedAt, Limit: 50})
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,97 @@
+package repo
+
+import (
+	"fmt"
+	"time"
+
+	"retarget/internal/pay-service/entity"
+)
+
+// ReserveIdempotencyKey занимает ключ под новый запрос. Если ключ уже занят,
+// возвращает существующую запись и false
+func (r *PaymentRepository) ReserveIdempotencyKey(userID, actorID int, key, requestHash string) (entity.IdempotencyRecord, bool, error) {
+	record := entity.IdempotencyRecord{UserID: userID, ActorID: actorID, Key: key, RequestHash: requestHash}
+
+	res, err := r.db.Exec(`
+        INSERT INTO idempotency_key (user_id, actor_id, key, request_hash)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (user_id, actor_id, key) DO NOTHING`,
+		userID,
+		actorID,
+		key,
+		requestHash,
+	)
+	if err != nil {
+		return record, false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 1 {
+		return record, true, nil
+	}
+
+	var (
+		status      *int
+		contentType *string
+		body        []byte
+	)
+	err = r.db.QueryRow(`
+        SELECT request_hash, status_code, content_type, response_body, created_at
+        FROM idempotency_key
+        WHERE user_id = $1 AND actor_id = $2 AND key = $3`,
+		userID,
+		actorID,
+		key,
+	).Scan(&record.RequestHash, &status, &contentType, &body, &record.CreatedAt)
+	if err != nil {
+		return record, false, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	if status != nil {
+		record.StatusCode = *status
+	}
+	if contentType != nil {
+		record.ContentType = *contentType
+	}
+	record.Body = body
+	return record, false, nil
+}
+
+// SaveIdempotentResponse запоминает ответ, который получат повторы запроса
+func (r *PaymentRepository) SaveIdempotentResponse(userID, actorID int, key string, statusCode int, contentType string, body []byte) error {
+	_, err := r.db.Exec(`
+        UPDATE idempotency_key
+        SET status_code = $1, content_type = $2, response_body = $3
+        WHERE user_id = $4 AND actor_id = $5 AND key = $6`,
+		statusCode,
+		contentType,
+		body,
+		userID,
+		actorID,
+		key,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save idempotent response: %w", err)
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey освобождает ключ незавершённого запроса, чтобы его можно было повторить
+func (r *PaymentRepository) ReleaseIdempotencyKey(userID, actorID int, key string) error {
+	_, err := r.db.Exec(`
+        DELETE FROM idempotency_key
+        WHERE user_id = $1 AND actor_id = $2 AND key = $3 AND status_code IS NULL`,
+		userID,
+		actorID,
+		key,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *PaymentRepository) PurgeIdempotencyKeys(olderThan time.Time) (int64, error) {
+	res, err := r.db.Exec(`DELETE FROM idempotency_key WHERE created_at < $1`, olderThan)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge idempotency keys: %w", err)
+	}
+	return res.RowsAffected()
+}
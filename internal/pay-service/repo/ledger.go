@@ -0,0 +1,328 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"retarget/internal/pay-service/entity"
+)
+
+// postEntry записывает проводку в рамках уже открытой транзакции, чтобы
+// изменение auth_user.balance и запись в журнале фиксировались вместе
+func (r *PaymentRepository) postEntry(tx *sql.Tx, entry entity.LedgerEntry) (int64, error) {
+	if err := entry.Validate(); err != nil {
+		return 0, err
+	}
+
+	var entryID int64
+	err := tx.QueryRow(`
+        INSERT INTO ledger_entry (kind, reference)
+        VALUES ($1, $2)
+        RETURNING id`,
+		entry.Kind,
+		entry.Reference,
+	).Scan(&entryID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert ledger entry: %w", err)
+	}
+
+	for _, posting := range entry.Postings {
+		accountID, err := r.ledgerAccountID(tx, posting.OwnerID, posting.Account, posting.PostingCurrency())
+		if err != nil {
+			return 0, err
+		}
+		_, err = tx.Exec(`
+            INSERT INTO ledger_posting (entry_id, account_id, amount)
+            VALUES ($1, $2, $3)`,
+			entryID,
+			accountID,
+			posting.Amount,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert ledger posting: %w", err)
+		}
+	}
+
+	if entry.Placement != nil {
+		_, err = tx.Exec(`
+            INSERT INTO ledger_entry_placement (entry_id, banner_id, slot_link)
+            VALUES ($1, $2, $3)`,
+			entryID,
+			entry.Placement.BannerID,
+			entry.Placement.SlotLink,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert ledger entry placement: %w", err)
+		}
+	}
+
+	if c := entry.Conversion; c != nil {
+		_, err = tx.Exec(`
+            INSERT INTO ledger_entry_conversion (entry_id, snapshot_id, from_currency, original_amount, currency, amount, rate)
+            VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			entryID,
+			c.SnapshotID,
+			c.FromCurrency,
+			c.OriginalAmount,
+			c.Currency,
+			c.Amount,
+			c.Rate,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert ledger entry conversion: %w", err)
+		}
+	}
+	return entryID, nil
+}
+
+func (r *PaymentRepository) ledgerAccountID(tx *sql.Tx, ownerID int, accountType entity.AccountType, currency string) (int, error) {
+	owner := sql.NullInt64{Int64: int64(ownerID), Valid: ownerID > 0}
+
+	var accountID int
+	err := tx.QueryRow(`
+        WITH created AS (
+            INSERT INTO ledger_account (owner_id, type, currency)
+            VALUES ($1, $2, $3)
+            ON CONFLICT (owner_id, type, currency) DO NOTHING
+            RETURNING id
+        )
+        SELECT id FROM created
+        UNION ALL
+        SELECT id FROM ledger_account WHERE owner_id IS NOT DISTINCT FROM $1 AND type = $2 AND currency = $3
+        LIMIT 1`,
+		owner,
+		accountType,
+		currency,
+	).Scan(&accountID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve ledger account %s/%s for owner %d: %w", accountType, currency, ownerID, err)
+	}
+	return accountID, nil
+}
+
+// PostEntry записывает самостоятельную проводку, не меняющую auth_user.balance
+func (r *PaymentRepository) PostEntry(entry entity.LedgerEntry) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	entryID, err := r.postEntry(tx, entry)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			err = fmt.Errorf("rollback failed: %v; original error: %w", rbErr, err)
+		}
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit ledger entry: %w", err)
+	}
+	return entryID, nil
+}
+
+// GetLedgerBalance выводит баланс пользователя в базовой валюте из журнала
+// как сумму по всем его счетам
+func (r *PaymentRepository) GetLedgerBalance(ownerID int) (entity.Decimal, error) {
+	const query = `
+        SELECT COALESCE(SUM(p.amount), 0)
+        FROM ledger_posting p
+        JOIN ledger_account a ON a.id = p.account_id
+        WHERE a.owner_id = $1 AND a.currency = $2`
+
+	var balance entity.Decimal
+	if err := r.db.QueryRow(query, ownerID, entity.BaseCurrency).Scan(&balance); err != nil {
+		return entity.Decimal{}, fmt.Errorf("failed to get ledger balance: %w", err)
+	}
+	return balance, nil
+}
+
+// GetLedgerBalances отдаёт остатки пользователя во всех валютах, где у него есть счета
+func (r *PaymentRepository) GetLedgerBalances(ownerID int) ([]entity.CurrencyBalance, error) {
+	const query = `
+        SELECT a.currency, COALESCE(SUM(p.amount), 0)
+        FROM ledger_account a
+        LEFT JOIN ledger_posting p ON p.account_id = a.id
+        WHERE a.owner_id = $1
+        GROUP BY a.currency
+        ORDER BY a.currency`
+
+	rows, err := r.db.Query(query, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger balances: %w", err)
+	}
+	defer rows.Close()
+
+	balances := make([]entity.CurrencyBalance, 0)
+	for rows.Next() {
+		var b entity.CurrencyBalance
+		if err := rows.Scan(&b.Currency, &b.Balance); err != nil {
+			return nil, err
+		}
+		balances = append(balances, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return balances, nil
+}
+
+func (r *PaymentRepository) GetLedgerEntries(ownerID int, limit int) ([]entity.LedgerEntry, error) {
+	const query = `
+        SELECT e.id, e.kind, e.reference, e.created_at, a.owner_id, a.type, a.currency, p.amount
+        FROM ledger_entry e
+        JOIN ledger_posting p ON p.entry_id = e.id
+        JOIN ledger_account a ON a.id = p.account_id
+        WHERE e.id IN (
+            SELECT p2.entry_id
+            FROM ledger_posting p2
+            JOIN ledger_account a2 ON a2.id = p2.account_id
+            WHERE a2.owner_id = $1
+            ORDER BY p2.entry_id DESC
+            LIMIT $2
+        )
+        ORDER BY e.id DESC, p.id`
+
+	rows, err := r.db.Query(query, ownerID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]entity.LedgerEntry, 0)
+	for rows.Next() {
+		var (
+			entryID   int64
+			kind      string
+			reference string
+			createdAt time.Time
+			owner     sql.NullInt64
+			account   string
+			currency  string
+			amount    entity.Decimal
+		)
+		if err := rows.Scan(&entryID, &kind, &reference, &createdAt, &owner, &account, &currency, &amount); err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 || entries[len(entries)-1].ID != entryID {
+			entries = append(entries, entity.LedgerEntry{
+				ID:        entryID,
+				Kind:      entity.EntryKind(kind),
+				Reference: reference,
+				CreatedAt: createdAt,
+			})
+		}
+		last := &entries[len(entries)-1]
+		last.Postings = append(last.Postings, entity.Posting{
+			OwnerID:  int(owner.Int64),
+			Account:  entity.AccountType(account),
+			Currency: postingCurrency(currency),
+			Amount:   amount,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetLedgerEntry возвращает проводку со всеми её сторонами
+func (r *PaymentRepository) GetLedgerEntry(entryID int64) (entity.LedgerEntry, error) {
+	const query = `
+        SELECT e.id, e.kind, e.reference, e.created_at, a.owner_id, a.type, a.currency, p.amount
+        FROM ledger_entry e
+        JOIN ledger_posting p ON p.entry_id = e.id
+        JOIN ledger_account a ON a.id = p.account_id
+        WHERE e.id = $1
+        ORDER BY p.id`
+
+	rows, err := r.db.Query(query, entryID)
+	if err != nil {
+		return entity.LedgerEntry{}, fmt.Errorf("failed to get ledger entry: %w", err)
+	}
+	defer rows.Close()
+
+	var entry entity.LedgerEntry
+	for rows.Next() {
+		var (
+			owner    sql.NullInt64
+			account  string
+			currency string
+			posting  entity.Posting
+		)
+		if err := rows.Scan(&entry.ID, &entry.Kind, &entry.Reference, &entry.CreatedAt, &owner, &account, &currency, &posting.Amount); err != nil {
+			return entity.LedgerEntry{}, err
+		}
+		posting.OwnerID = int(owner.Int64)
+		posting.Account = entity.AccountType(account)
+		posting.Currency = postingCurrency(currency)
+		entry.Postings = append(entry.Postings, posting)
+	}
+	if err := rows.Err(); err != nil {
+		return entity.LedgerEntry{}, err
+	}
+	if len(entry.Postings) == 0 {
+		return entity.LedgerEntry{}, ErrLedgerEntryNotFound
+	}
+	return entry, nil
+}
+
+// postingCurrency переводит валюту счёта в вид Posting, где базовая валюта не указывается
+func postingCurrency(currency string) string {
+	if currency == entity.BaseCurrency {
+		return ""
+	}
+	return currency
+}
+
+// FindLedgerDrift ищет пользователей, у которых auth_user.balance вместе с
+// промо-кредитом разошёлся с журналом; auth_user.balance хранит только базовую валюту
+func (r *PaymentRepository) FindLedgerDrift() ([]entity.LedgerDrift, error) {
+	const query = `
+        SELECT u.id, COALESCE(l.balance, 0), (u.balance + u.promo_balance)::numeric
+        FROM auth_user u
+        LEFT JOIN (
+            SELECT a.owner_id, SUM(p.amount) AS balance
+            FROM ledger_posting p
+            JOIN ledger_account a ON a.id = p.account_id
+            WHERE a.owner_id IS NOT NULL AND a.currency = $1
+            GROUP BY a.owner_id
+        ) l ON l.owner_id = u.id
+        WHERE u.balance + u.promo_balance <> COALESCE(l.balance, 0)`
+
+	rows, err := r.db.Query(query, entity.BaseCurrency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find ledger drift: %w", err)
+	}
+	defer rows.Close()
+
+	drifts := make([]entity.LedgerDrift, 0)
+	for rows.Next() {
+		var drift entity.LedgerDrift
+		if err := rows.Scan(&drift.OwnerID, &drift.LedgerBalance, &drift.UserBalance); err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, drift)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return drifts, nil
+}
+
+func (r *PaymentRepository) SaveLedgerDrift(drifts []entity.LedgerDrift) error {
+	for _, drift := range drifts {
+		_, err := r.db.Exec(`
+            INSERT INTO ledger_drift (owner_id, ledger_balance, user_balance)
+            VALUES ($1, $2, $3)`,
+			drift.OwnerID,
+			drift.LedgerBalance,
+			drift.UserBalance,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save ledger drift for user %d: %w", drift.OwnerID, err)
+		}
+	}
+	return nil
+}
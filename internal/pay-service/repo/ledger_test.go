@@ -0,0 +1,127 @@
+package repo_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"retarget/internal/pay-service/entity"
+)
+
+func expectPostEntry(mock sqlmock.Sqlmock, kind, reference string, postings int) {
+	mock.ExpectQuery("INSERT INTO ledger_entry").
+		WithArgs(kind, reference).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	for i := 0; i < postings; i++ {
+		mock.ExpectQuery("INSERT INTO ledger_account").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(i + 1))
+		mock.ExpectExec("INSERT INTO ledger_posting").
+			WithArgs(1, i+1, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+}
+
+func TestUpdateBalance_PostsEntry(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE auth_user").
+		WithArgs("10.00", 5).
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("110.00"))
+	expectPostEntry(mock, "topup", "trx-1", 2)
+	mock.ExpectCommit()
+
+	entry := entity.TopUpEntry(5, entity.DecimalFromKopecks(1000), "trx-1")
+	bal, err := r.UpdateBalance(5, entity.DecimalFromKopecks(1000), entry, "req")
+	assert.NoError(t, err)
+	assert.Equal(t, "110.00", bal.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateBalance_UnbalancedEntryRollsBack(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE auth_user").
+		WithArgs("10.00", 5).
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("110.00"))
+	mock.ExpectRollback()
+
+	entry := entity.LedgerEntry{
+		Kind:      entity.EntryTopUp,
+		Reference: "trx-1",
+		Postings: []entity.Posting{
+			{OwnerID: 5, Account: entity.AccountAdvertiser, Amount: entity.DecimalFromKopecks(1000)},
+			{Account: entity.AccountGateway, Amount: entity.DecimalFromKopecks(-900)},
+		},
+	}
+	_, err := r.UpdateBalance(5, entity.DecimalFromKopecks(1000), entry, "req")
+	assert.ErrorIs(t, err, entity.ErrUnbalancedEntry)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostEntry_PostingError(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO ledger_entry").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectQuery("INSERT INTO ledger_account").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectExec("INSERT INTO ledger_posting").
+		WillReturnError(fmt.Errorf("insert error"))
+	mock.ExpectRollback()
+
+	_, err := r.PostEntry(entity.PayoutEntry(5, entity.DecimalFromKopecks(500), "payout-1"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to insert ledger posting")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetLedgerEntries_GroupsPostings(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "kind", "reference", "created_at", "owner_id", "type", "currency", "amount"}).
+		AddRow(2, "impression_charge", "hold-1", now, 5, "advertiser", "RUB", "-1.50").
+		AddRow(2, "impression_charge", "hold-1", now, 6, "publisher", "RUB", "1.50").
+		AddRow(1, "topup", "trx-1", now, nil, "gateway", "RUB", "-10.00").
+		AddRow(1, "topup", "trx-1", now, 5, "advertiser", "RUB", "10.00")
+	mock.ExpectQuery("FROM ledger_entry").
+		WithArgs(5, 10).
+		WillReturnRows(rows)
+
+	entries, err := r.GetLedgerEntries(5, 10)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, entity.EntryImpressionCharge, entries[0].Kind)
+	assert.Len(t, entries[1].Postings, 2)
+	assert.Equal(t, 0, entries[1].Postings[0].OwnerID)
+	assert.NoError(t, entries[1].Validate())
+}
+
+func TestFindLedgerDrift(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	mock.ExpectQuery("FROM auth_user u").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ledger", "balance"}).AddRow(5, "10.00", "12"))
+	mock.ExpectExec("INSERT INTO ledger_drift").
+		WithArgs(5, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	drifts, err := r.FindLedgerDrift()
+	assert.NoError(t, err)
+	assert.Len(t, drifts, 1)
+	assert.Equal(t, "12", drifts[0].UserBalance.String())
+
+	assert.NoError(t, r.SaveLedgerDrift(drifts))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"retarget/pkg/entity"
 	"retarget/pkg/entity/notice"
 	noticeType "retarget/pkg/entity/notice"
 
@@ -14,8 +15,10 @@ import (
 )
 
 type NoticeRepositoryInterface interface {
-	SendLowBalanceNotification(userID int, message string) error
-	SendTopUpBalanceEvent(userID int, message string) error
+	SendLowBalanceNotification(userID int, threshold, balance entity.Decimal) error
+	SendTopUpBalanceEvent(userID int, amount entity.Decimal) error
+	SendStatementReadyEvent(userID, statementID int, period string) error
+	SendAutoRechargeFailedEvent(userID int, amount entity.Decimal, reason string) error
 	Close()
 }
 
@@ -59,7 +62,7 @@ func NewNoticeRepository(brokers []string, topic string, logger *zap.SugaredLogg
 	}
 }
 
-func (r *NoticeRepository) SendTopUpBalanceEvent(userID int, amount float64) error {
+func (r *NoticeRepository) SendTopUpBalanceEvent(userID int, amount entity.Decimal) error {
 	if userID <= 0 {
 		return fmt.Errorf("invalid user ID: %d", userID)
 	}
@@ -71,7 +74,7 @@ func (r *NoticeRepository) SendTopUpBalanceEvent(userID int, amount float64) err
 	event := notice.NoticeEvent{
 		UserID: userID,
 		Type:   noticeType.TopUpedBalance,
-		Amount: amount,
+		Amount: &amount,
 	}
 
 	payload, err := json.Marshal(event)
@@ -92,7 +95,78 @@ func (r *NoticeRepository) SendTopUpBalanceEvent(userID int, amount float64) err
 	return nil
 }
 
-func (r *NoticeRepository) SendLowBalanceNotification(userID int) error {
+// SendLowBalanceNotification сообщает, что баланс balance опустился до порога threshold
+func (r *NoticeRepository) SendLowBalanceNotification(userID int, threshold, balance entity.Decimal) error {
+	if userID <= 0 {
+		return fmt.Errorf("invalid user ID: %d", userID)
+	}
+
+	if r.emitter == nil {
+		return fmt.Errorf("emitter not initialized")
+	}
+
+	event := notice.NoticeEvent{
+		UserID:    userID,
+		Type:      noticeType.LowBalance,
+		Amount:    &balance,
+		Threshold: &threshold,
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		r.logger.Errorw("failed to marshal notice event", "event", event, "error", err)
+		return err
+	}
+
+	key := fmt.Sprintf("%d", userID)
+
+	err = r.emitter.EmitSync(key, string(payload))
+	if err != nil {
+		r.logger.Errorw("failed to emit notice event", "key", key, "payload", string(payload), "error", err)
+		return err
+	}
+
+	r.logger.Infow("notice event sent", "key", key, "payload", string(payload))
+	return nil
+}
+
+// SendStatementReadyEvent сообщает, что выписка за период period (например, 2025-04) готова
+func (r *NoticeRepository) SendStatementReadyEvent(userID, statementID int, period string) error {
+	if userID <= 0 {
+		return fmt.Errorf("invalid user ID: %d", userID)
+	}
+
+	if r.emitter == nil {
+		return fmt.Errorf("emitter not initialized")
+	}
+
+	event := notice.NoticeEvent{
+		UserID:      userID,
+		Type:        noticeType.StatementReady,
+		StatementID: statementID,
+		Period:      period,
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		r.logger.Errorw("failed to marshal notice event", "event", event, "error", err)
+		return err
+	}
+
+	key := fmt.Sprintf("%d", userID)
+
+	err = r.emitter.EmitSync(key, string(payload))
+	if err != nil {
+		r.logger.Errorw("failed to emit notice event", "key", key, "payload", string(payload), "error", err)
+		return err
+	}
+
+	r.logger.Infow("notice event sent", "key", key, "payload", string(payload))
+	return nil
+}
+
+// SendAutoRechargeFailedEvent сообщает, что автопополнение на amount не прошло
+func (r *NoticeRepository) SendAutoRechargeFailedEvent(userID int, amount entity.Decimal, reason string) error {
 	if userID <= 0 {
 		return fmt.Errorf("invalid user ID: %d", userID)
 	}
@@ -103,7 +177,9 @@ func (r *NoticeRepository) SendLowBalanceNotification(userID int) error {
 
 	event := notice.NoticeEvent{
 		UserID: userID,
-		Type:   noticeType.LowBalance,
+		Type:   noticeType.AutoRechargeFailed,
+		Amount: &amount,
+		Reason: reason,
 	}
 
 	payload, err := json.Marshal(event)
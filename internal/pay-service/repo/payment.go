@@ -17,20 +17,85 @@ import (
 )
 
 var (
-	ErrUserNotFound  = errors.New("user not found")
-	ErrInvalidAmount = errors.New("invalid amount")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrLedgerEntryNotFound = errors.New("ledger entry not found")
+	ErrInvalidAmount       = errors.New("invalid amount")
 )
 
 type PaymentRepositoryInterface interface {
-	GetBalanceByUserId(id int, requestID string) (float64, error)
-	UpdateBalance(userID int, amount float64, requestID string) (float64, error)
+	GetBalanceByUserId(id int, requestID string) (entity.Decimal, error)
+	UpdateBalance(userID int, amount entity.Decimal, entry entity.LedgerEntry, requestID string) (entity.Decimal, error)
 	CreateTransaction(trx entity.Transaction) error
 	GetLastTransaction(userID int, requestID string) (*entity.Transaction, error)
 	GetTransactionByID(transactionID string, requestID string) (*entity.Transaction, error)
-	RegUserActivity(user_banner_id, user_slot_id int, amount entity.Decimal) (int, int, error)
+	RegUserActivity(user_banner_id, user_slot_id int, amount, fee entity.Decimal, reference string, placement entity.Placement) (int, int, error)
+	GetTakeRate(publisherID int) (entity.Decimal, bool, error)
+	SetTakeRate(publisherID int, rate entity.Decimal) error
+	GetEarnings(publisherID int) (entity.Earnings, error)
+	SettleTransaction(transactionID string, status int, userID int, delta entity.Decimal, entry *entity.LedgerEntry) (bool, error)
+	ClaimStalePendingTransactions(olderThan time.Time, limit int) ([]entity.Transaction, error)
 	GetPendingTransactions(userID int) ([]entity.Transaction, error)
 	UpdateTransactionStatus(transactionID string, status int) error
 	DeactivateBannersByUserID(ctx context.Context, userID int) error
+	GetBalanceForBudget(userID int, batchID string) (entity.Decimal, bool, error)
+	GetPromoBalance(userID int) (entity.Decimal, error)
+	ApplyBudgetBatch(batchID string, deltas map[int]entity.Decimal, charges []entity.LedgerEntry) error
+	PostEntry(entry entity.LedgerEntry) (int64, error)
+	GetLedgerBalance(ownerID int) (entity.Decimal, error)
+	GetLedgerEntries(ownerID int, limit int) ([]entity.LedgerEntry, error)
+	FindLedgerDrift() ([]entity.LedgerDrift, error)
+	SaveLedgerDrift(drifts []entity.LedgerDrift) error
+	ReserveIdempotencyKey(userID, actorID int, key, requestHash string) (entity.IdempotencyRecord, bool, error)
+	SaveIdempotentResponse(userID, actorID int, key string, statusCode int, contentType string, body []byte) error
+	ReleaseIdempotencyKey(userID, actorID int, key string) error
+	PurgeIdempotencyKeys(olderThan time.Time) (int64, error)
+	CreatePayoutRequest(p entity.PayoutRequest, policy entity.PayoutPolicy, now time.Time) (entity.PayoutRequest, error)
+	TransitionPayout(payoutID int, from, to entity.PayoutStatus, update entity.PayoutUpdate, delta entity.Decimal, entry *entity.LedgerEntry) (bool, error)
+	GetPayoutRequest(payoutID int) (entity.PayoutRequest, error)
+	GetPayoutByGatewayID(gatewayID string) (entity.PayoutRequest, error)
+	GetPayoutRequests(userID int, limit int) ([]entity.PayoutRequest, error)
+	GetPayoutsByStatus(status entity.PayoutStatus, limit int) ([]entity.PayoutRequest, error)
+	GetStaleApprovedPayouts(olderThan, approvedAfter time.Time, limit int) ([]entity.PayoutRequest, error)
+	GetLedgerEntry(entryID int64) (entity.LedgerEntry, error)
+	CreateTopUpRefund(refund entity.Refund) (entity.Refund, error)
+	FinishRefund(refundID int, status entity.RefundStatus, gatewayID string) (bool, error)
+	ReverseImpressionCharge(original entity.LedgerEntry, reason string, createdBy int) (entity.Refund, error)
+	GetRefundByGatewayID(gatewayID string) (entity.Refund, error)
+	GetRefunds(kind entity.RefundKind, originalID string) ([]entity.Refund, error)
+	GetStatementRows(ownerID int, from, to time.Time) ([]entity.StatementRow, error)
+	GetLedgerBalanceAt(ownerID int, at time.Time) (entity.Decimal, error)
+	GetStatementOwners(from, to time.Time) ([]int, error)
+	SaveStatement(s entity.Statement, objectPrefix string) (entity.StatementInfo, error)
+	GetStatement(statementID int) (entity.StatementInfo, error)
+	GetStatements(userID int) ([]entity.StatementInfo, error)
+	GetTransactionHistory(filter entity.HistoryFilter) ([]entity.HistoryItem, error)
+	GetLedgerBalances(ownerID int) ([]entity.CurrencyBalance, error)
+	SaveRateSnapshot(source string, createdBy int, rates map[string]entity.Decimal) (entity.RateSnapshot, error)
+	GetLatestRateSnapshot() (entity.RateSnapshot, error)
+	ExchangeBalance(userID int, entry entity.LedgerEntry) (int64, error)
+	CreatePromoCode(p entity.PromoCode) (entity.PromoCode, error)
+	GetPromoCodes() ([]entity.PromoCode, error)
+	GetPromoCode(code string, userID int) (entity.PromoCode, int, error)
+	SetPromoCodeActive(codeID int, active bool) (entity.PromoCode, error)
+	RedeemPromoCode(code string, userID int, topUp *entity.Decimal, transactionID string, now time.Time) (entity.PromoRedemption, error)
+	AttachTopUpPromo(transactionID, code string, userID int) error
+	GetTopUpPromo(transactionID string) (string, error)
+	GetPromoRedemptions(codeID, userID int, limit int) ([]entity.PromoRedemption, error)
+	GetBalanceAlertSettings(userID int) (entity.BalanceAlertSettings, error)
+	SaveBalanceAlertSettings(userID int, s entity.BalanceAlertSettings) error
+	MarkBalanceAlertsCrossed(userID int, thresholds []entity.Decimal) ([]entity.Decimal, error)
+	ClearBalanceAlerts(userID int, thresholds []entity.Decimal) error
+	ResetBalanceAlerts(userID int, balance entity.Decimal) error
+	SavePaymentMethod(m entity.PaymentMethod) error
+	ActivatePaymentMethod(transactionID string) (entity.PaymentMethod, bool, error)
+	GetPaymentMethods(userID int) ([]entity.PaymentMethod, error)
+	DeletePaymentMethod(userID int, methodID string) error
+	GetAutoRecharge(userID int, now time.Time) (entity.AutoRecharge, error)
+	SaveAutoRecharge(a entity.AutoRecharge) error
+	StartAutoRecharge(userID int, pendingID string, now time.Time) (entity.AutoRecharge, error)
+	AttachAutoRechargeTransaction(pendingID string, trx entity.Transaction) error
+	FinishAutoRecharge(userID int, pendingID, failure string, now time.Time) (entity.AutoRecharge, bool, error)
+	GetStaleAutoRecharges(olderThan time.Time, limit int) ([]entity.AutoRecharge, error)
 	CloseConnection() error
 	GetDB() *sql.DB
 	GetLogger() *zap.SugaredLogger
@@ -59,9 +124,10 @@ func NewPaymentRepository(username, password, dbname, host string, port int, ssl
 	return paymentRepo
 }
 
-func (r *PaymentRepository) GetBalanceByUserId(id int, requestID string) (float64, error) {
+func (r *PaymentRepository) GetBalanceByUserId(id int, requestID string) (entity.Decimal, error) {
 	startTime := time.Now()
-	query := "SELECT balance FROM auth_user WHERE id = $1"
+	// промо-кредит тратится на показы наравне с деньгами, поэтому входит в баланс
+	query := "SELECT balance + promo_balance FROM auth_user WHERE id = $1"
 
 	r.logger.Debugw("Getting user balance",
 		"request_id", requestID,
@@ -69,7 +135,7 @@ func (r *PaymentRepository) GetBalanceByUserId(id int, requestID string) (float6
 		"userID", id,
 	)
 
-	var balance float64
+	var balance entity.Decimal
 	err := r.db.QueryRow(query, id).Scan(&balance)
 
 	switch {
@@ -79,7 +145,7 @@ func (r *PaymentRepository) GetBalanceByUserId(id int, requestID string) (float6
 			"userID", id,
 			"timeTakenMs", time.Since(startTime).Milliseconds(),
 		)
-		return 0, fmt.Errorf("user with id %d not found", id)
+		return entity.Decimal{}, fmt.Errorf("user with id %d not found", id)
 	case err != nil:
 		r.logger.Debugw("Failed to get balance",
 			"request_id", requestID,
@@ -87,19 +153,20 @@ func (r *PaymentRepository) GetBalanceByUserId(id int, requestID string) (float6
 			"error", err.Error(),
 			"timeTakenMs", time.Since(startTime).Milliseconds(),
 		)
-		return 0, fmt.Errorf("error getting balance: %w", err)
+		return entity.Decimal{}, fmt.Errorf("error getting balance: %w", err)
 	default:
 		r.logger.Debugw("Balance retrieved successfully",
 			"request_id", requestID,
 			"userID", id,
-			"balance", balance,
+			"balance", balance.String(),
 			"timeTakenMs", time.Since(startTime).Milliseconds(),
 		)
 		return balance, nil
 	}
 }
 
-func (r *PaymentRepository) UpdateBalance(userID int, amount float64, requestID string) (float64, error) {
+// UpdateBalance меняет баланс и в той же транзакции записывает проводку entry
+func (r *PaymentRepository) UpdateBalance(userID int, amount entity.Decimal, entry entity.LedgerEntry, requestID string) (entity.Decimal, error) {
 	startTime := time.Now()
 	query := `
         UPDATE auth_user 
@@ -112,36 +179,55 @@ func (r *PaymentRepository) UpdateBalance(userID int, amount float64, requestID
 		"request_id", requestID,
 		"query", query,
 		"userID", userID,
-		"amount", amount,
+		"amount", amount.String(),
 	)
 
-	var newBalance float64
-	err := r.db.QueryRow(query, amount, userID).Scan(&newBalance)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return entity.Decimal{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	var newBalance entity.Decimal
+	err = tx.QueryRow(query, amount, userID).Scan(&newBalance)
 
 	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			err = fmt.Errorf("rollback failed: %v; original error: %w", rbErr, err)
+		}
 		if errors.Is(err, sql.ErrNoRows) {
 			r.logger.Debugw("User not found for balance update",
 				"request_id", requestID,
 				"userID", userID,
 				"timeTakenMs", time.Since(startTime).Milliseconds(),
 			)
-			return 0, ErrUserNotFound
+			return entity.Decimal{}, ErrUserNotFound
 		}
 
 		r.logger.Debugw("Balance update failed",
 			"request_id", requestID,
 			"userID", userID,
-			"amount", amount,
+			"amount", amount.String(),
 			"error", err.Error(),
 			"timeTakenMs", time.Since(startTime).Milliseconds(),
 		)
-		return 0, fmt.Errorf("update balance failed: %w", err)
+		return entity.Decimal{}, fmt.Errorf("update balance failed: %w", err)
+	}
+
+	if _, err = r.postEntry(tx, entry); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			err = fmt.Errorf("rollback failed: %v; original error: %w", rbErr, err)
+		}
+		return entity.Decimal{}, fmt.Errorf("update balance failed: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return entity.Decimal{}, fmt.Errorf("failed to commit balance update: %w", err)
 	}
 
 	r.logger.Debugw("Balance updated successfully",
 		"request_id", requestID,
 		"userID", userID,
-		"newBalance", newBalance,
+		"newBalance", newBalance.String(),
 		"timeTakenMs", time.Since(startTime).Milliseconds(),
 	)
 
@@ -158,27 +244,37 @@ func (r *PaymentRepository) CreateTransaction(trx entity.Transaction) error {
 
 	const q = `
     INSERT INTO transaction (
-        transaction_id, user_id, amount, type, status
-    ) VALUES ($1, $2, $3, $4, $5)
+        transaction_id, user_id, amount, type, status, currency
+    ) VALUES ($1, $2, $3, $4, $5, $6)
     `
+	currency := trx.Currency
+	if currency == "" {
+		currency = entity.BaseCurrency
+	}
 	_, err = r.db.Exec(q,
 		trx.TransactionID,
 		trx.UserID,
 		trx.Amount,
 		trx.Type,
 		trx.Status,
+		currency,
 	)
 	return err
 }
 
-func (r *PaymentRepository) GetLastTransaction(userID int, requestID string) (*entity.Transaction, error) {
+const transactionColumns = `id, transaction_id, user_id, amount, type, status, created_at, currency`
+
+func scanTransaction(row rowScanner) (entity.Transaction, error) {
 	var tx entity.Transaction
+	err := row.Scan(&tx.ID, &tx.TransactionID, &tx.UserID, &tx.Amount, &tx.Type, &tx.Status, &tx.CreatedAt, &tx.Currency)
+	return tx, err
+}
 
-	err := r.db.QueryRow(
-		"SELECT * FROM transaction WHERE user_id = $1 ORDER BY created_at DESC LIMIT 1",
+func (r *PaymentRepository) GetLastTransaction(userID int, requestID string) (*entity.Transaction, error) {
+	tx, err := scanTransaction(r.db.QueryRow(
+		"SELECT "+transactionColumns+" FROM transaction WHERE user_id = $1 ORDER BY created_at DESC LIMIT 1",
 		userID,
-	).Scan(&tx.ID, &tx.TransactionID, &tx.UserID, &tx.Amount, &tx.Type, &tx.Status, &tx.CreatedAt)
-
+	))
 	if err != nil {
 		return nil, err
 	}
@@ -187,13 +283,10 @@ func (r *PaymentRepository) GetLastTransaction(userID int, requestID string) (*e
 }
 
 func (r *PaymentRepository) GetTransactionByID(transactionID string, requestID string) (*entity.Transaction, error) {
-	var tx entity.Transaction
-
-	err := r.db.QueryRow(
-		"SELECT * FROM transaction WHERE transaction_id = $1",
+	tx, err := scanTransaction(r.db.QueryRow(
+		"SELECT "+transactionColumns+" FROM transaction WHERE transaction_id = $1",
 		transactionID,
-	).Scan(&tx.ID, &tx.TransactionID, &tx.UserID, &tx.Amount, &tx.Type, &tx.Status, &tx.CreatedAt)
-
+	))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("transaction with ID %s not found", transactionID)
@@ -203,17 +296,29 @@ func (r *PaymentRepository) GetTransactionByID(transactionID string, requestID s
 	return &tx, nil
 }
 
-func (r *PaymentRepository) RegUserActivity(user_banner_id, user_slot_id int, amount entity.Decimal) (int, int, error) {
+// RegUserActivity списывает amount с рекламодателя, сначала из промо-кредита, а владельцу
+// слота начисляет amount за вычетом комиссии fee
+func (r *PaymentRepository) RegUserActivity(user_banner_id, user_slot_id int, amount, fee entity.Decimal, reference string, placement entity.Placement) (int, int, error) {
+	net, err := amountAfterFee(amount, fee)
+	if err != nil {
+		return -1, -1, err
+	}
+
 	tx, err := r.db.Begin()
 	if err != nil {
 		return -1, -1, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
+	promo, err := r.spendPromo(tx, user_slot_id, amount)
+	if err != nil {
+		return -1, -1, r.rollback(tx, err)
+	}
+
 	_, err = tx.Exec(`
         UPDATE auth_user 
         SET balance = balance - $1 
         WHERE id = $2`,
-		amount,
+		amount.Sub(promo),
 		user_slot_id)
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
@@ -227,7 +332,7 @@ func (r *PaymentRepository) RegUserActivity(user_banner_id, user_slot_id int, am
         UPDATE auth_user 
         SET balance = balance + $1 
         WHERE id = $2`,
-		amount,
+		net,
 		user_banner_id)
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
@@ -235,6 +340,14 @@ func (r *PaymentRepository) RegUserActivity(user_banner_id, user_slot_id int, am
 		}
 		return -1, -1, fmt.Errorf("failed to update second user balance: %w", err)
 	}
+
+	entry, _ := entity.ImpressionChargeEntry(user_slot_id, user_banner_id, amount, fee, reference).WithPlacement(placement).SpendPromo(promo)
+	if _, err = r.postEntry(tx, entry); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			err = fmt.Errorf("rollback failed: %v; original error: %w", rbErr, err)
+		}
+		return -1, -1, fmt.Errorf("failed to post impression charge: %w", err)
+	}
 	err = tx.Commit()
 	if err != nil {
 		return -1, -1, fmt.Errorf("failed to commit transaction: %w", err)
@@ -244,7 +357,7 @@ func (r *PaymentRepository) RegUserActivity(user_banner_id, user_slot_id int, am
 
 func (r *PaymentRepository) GetPendingTransactions(userID int) ([]entity.Transaction, error) {
 	const q = `
-    	SELECT id, transaction_id, user_id, amount, type, status, created_at
+    	SELECT ` + transactionColumns + `
     	FROM transaction
     	WHERE user_id = $1 AND status = '0'
     `
@@ -256,8 +369,8 @@ func (r *PaymentRepository) GetPendingTransactions(userID int) ([]entity.Transac
 
 	list := make([]entity.Transaction, 0)
 	for rows.Next() {
-		var tx entity.Transaction
-		if err := rows.Scan(&tx.ID, &tx.TransactionID, &tx.UserID, &tx.Amount, &tx.Type, &tx.Status, &tx.CreatedAt); err != nil {
+		tx, err := scanTransaction(rows)
+		if err != nil {
 			return nil, err
 		}
 		list = append(list, tx)
@@ -297,6 +410,139 @@ func (r *PaymentRepository) DeactivateBannersByUserID(ctx context.Context, userI
 	return nil
 }
 
+// GetBalanceForBudget одним снимком читает баланс вместе с промо-кредитом и
+// признак того, что пачка batchID уже применена, чтобы кэш бюджета не учёл её дважды
+func (r *PaymentRepository) GetBalanceForBudget(userID int, batchID string) (entity.Decimal, bool, error) {
+	const query = `
+        SELECT balance + promo_balance, EXISTS (SELECT 1 FROM budget_batch WHERE batch_id = $2)
+        FROM auth_user
+        WHERE id = $1`
+
+	var balance entity.Decimal
+	var applied bool
+	err := r.db.QueryRow(query, userID, batchID).Scan(&balance, &applied)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Decimal{}, false, ErrUserNotFound
+		}
+		return entity.Decimal{}, false, fmt.Errorf("error getting balance for budget: %w", err)
+	}
+	return balance, applied, nil
+}
+
+// ApplyBudgetBatch переносит пачку списаний и начислений из кэша бюджета.
+// Списания рекламодателя сначала гасятся его промо-кредитом, остаток — с баланса
+func (r *PaymentRepository) ApplyBudgetBatch(batchID string, deltas map[int]entity.Decimal, charges []entity.LedgerEntry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	result, err := tx.Exec(`
+        INSERT INTO budget_batch (batch_id)
+        VALUES ($1)
+        ON CONFLICT (batch_id) DO NOTHING`,
+		batchID)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			err = fmt.Errorf("rollback failed: %v; original error: %w", rbErr, err)
+		}
+		return fmt.Errorf("failed to register budget batch: %w", err)
+	}
+	if inserted, _ := result.RowsAffected(); inserted == 0 {
+		r.logger.Infow("budget batch already applied", "batch_id", batchID)
+		return tx.Rollback()
+	}
+
+	charges, deltas, err = r.spendPromoInBatch(tx, deltas, charges)
+	if err != nil {
+		return r.rollback(tx, err)
+	}
+
+	for userID, delta := range deltas {
+		_, err = tx.Exec(`
+            UPDATE auth_user 
+            SET balance = balance + $1 
+            WHERE id = $2`,
+			delta,
+			userID)
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				err = fmt.Errorf("rollback failed: %v; original error: %w", rbErr, err)
+			}
+			return fmt.Errorf("failed to apply budget delta for user %d: %w", userID, err)
+		}
+	}
+
+	for _, charge := range charges {
+		if _, err = r.postEntry(tx, charge); err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				err = fmt.Errorf("rollback failed: %v; original error: %w", rbErr, err)
+			}
+			return fmt.Errorf("failed to post budget charge: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit budget batch: %w", err)
+	}
+
+	r.logger.Infow("budget batch applied",
+		"batch_id", batchID,
+		"users", len(deltas),
+		"charges", len(charges))
+	return nil
+}
+
+// spendPromoInBatch гасит списания пачки промо-кредитом рекламодателей: часть
+// каждой проводки переносится на промо-счёт, а дельта баланса уменьшается на
+// потраченный кредит. Исходные deltas и charges не меняются
+func (r *PaymentRepository) spendPromoInBatch(tx *sql.Tx, deltas map[int]entity.Decimal, charges []entity.LedgerEntry) ([]entity.LedgerEntry, map[int]entity.Decimal, error) {
+	var advertisers []int
+	spent := make(map[int]entity.Decimal)
+	for _, charge := range charges {
+		ownerID, amount, ok := charge.AdvertiserCharge()
+		if !ok {
+			continue
+		}
+		if _, seen := spent[ownerID]; !seen {
+			advertisers = append(advertisers, ownerID)
+		}
+		spent[ownerID] = spent[ownerID].Add(amount)
+	}
+
+	promo := make(map[int]entity.Decimal, len(advertisers))
+	for _, ownerID := range advertisers {
+		used, err := r.spendPromo(tx, ownerID, spent[ownerID])
+		if err != nil {
+			return nil, nil, err
+		}
+		if used.Sign() > 0 {
+			promo[ownerID] = used
+		}
+	}
+	if len(promo) == 0 {
+		return charges, deltas, nil
+	}
+
+	adjusted := make(map[int]entity.Decimal, len(deltas))
+	for userID, delta := range deltas {
+		adjusted[userID] = delta
+	}
+	for ownerID, used := range promo {
+		adjusted[ownerID] = adjusted[ownerID].Add(used)
+	}
+
+	split := make([]entity.LedgerEntry, 0, len(charges))
+	for _, charge := range charges {
+		if ownerID, _, ok := charge.AdvertiserCharge(); ok {
+			charge, promo[ownerID] = charge.SpendPromo(promo[ownerID])
+		}
+		split = append(split, charge)
+	}
+	return split, adjusted, nil
+}
+
 func (r *PaymentRepository) CloseConnection() error {
 	if r.db != nil {
 		return r.db.Close()
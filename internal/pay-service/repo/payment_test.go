@@ -26,13 +26,13 @@ func TestGetBalanceByUserId_Success(t *testing.T) {
 	r, mock, close := setup()
 	defer close()
 
-	mock.ExpectQuery("SELECT balance FROM auth_user").
+	mock.ExpectQuery(`SELECT balance \+ promo_balance FROM auth_user`).
 		WithArgs(42).
-		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(123.45))
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("123.45"))
 
 	bal, err := r.GetBalanceByUserId(42, "req-1")
 	assert.NoError(t, err)
-	assert.Equal(t, 123.45, bal)
+	assert.Equal(t, "123.45", bal.String())
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -40,7 +40,7 @@ func TestGetBalanceByUserId_NoRows(t *testing.T) {
 	r, mock, close := setup()
 	defer close()
 
-	mock.ExpectQuery("SELECT balance FROM auth_user").
+	mock.ExpectQuery(`SELECT balance \+ promo_balance FROM auth_user`).
 		WithArgs(7).
 		WillReturnError(sql.ErrNoRows)
 
@@ -55,11 +55,14 @@ func TestUpdateBalance_UserNotFound(t *testing.T) {
 	r, mock, close := setup()
 	defer close()
 
+	mock.ExpectBegin()
 	mock.ExpectQuery("UPDATE auth_user").
-		WithArgs(10.0, 99).
+		WithArgs("10.00", 99).
 		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
 
-	bal, err := r.UpdateBalance(99, 10.0, "req-3")
+	entry := entity.TopUpEntry(99, entity.DecimalFromKopecks(1000), "trx-3")
+	bal, err := r.UpdateBalance(99, entity.DecimalFromKopecks(1000), entry, "req-3")
 	assert.ErrorIs(t, err, repo.ErrUserNotFound)
 	assert.Zero(t, bal)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -86,7 +89,7 @@ func TestCreateTransaction_New(t *testing.T) {
 		WithArgs("trx-2").
 		WillReturnError(sql.ErrNoRows)
 	mock.ExpectExec("INSERT INTO transaction").
-		WithArgs("trx-2", 0, 0.0, "", 0).
+		WithArgs("trx-2", 0, "0", "", 0, "RUB").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	err := r.CreateTransaction(entity.Transaction{TransactionID: "trx-2"})
@@ -111,11 +114,11 @@ func TestGetLastTransaction_Success(t *testing.T) {
 	r, mock, close := setup()
 	defer close()
 
-	cols := []string{"id", "transaction_id", "user_id", "amount", "type", "status", "created_at"}
+	cols := []string{"id", "transaction_id", "user_id", "amount", "type", "status", "created_at", "currency"}
 	now := time.Now()
-	mock.ExpectQuery("SELECT \\* FROM transaction").
+	mock.ExpectQuery("SELECT id, transaction_id, user_id, amount, type, status, created_at, currency FROM transaction").
 		WithArgs(11).
-		WillReturnRows(sqlmock.NewRows(cols).AddRow(1, "trx-id", 11, 50.0, "t", 1, now))
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(1, "trx-id", 11, "50.00", "t", 1, now, "RUB"))
 
 	tx, err := r.GetLastTransaction(11, "req")
 	assert.NoError(t, err)
@@ -127,7 +130,7 @@ func TestGetLastTransaction_Error(t *testing.T) {
 	r, mock, close := setup()
 	defer close()
 
-	mock.ExpectQuery("SELECT \\* FROM transaction").
+	mock.ExpectQuery("SELECT id, transaction_id, user_id, amount, type, status, created_at, currency FROM transaction").
 		WithArgs(12).
 		WillReturnError(sql.ErrNoRows)
 
@@ -140,11 +143,11 @@ func TestGetTransactionByID_Success(t *testing.T) {
 	r, mock, close := setup()
 	defer close()
 
-	cols := []string{"id", "transaction_id", "user_id", "amount", "type", "status", "created_at"}
+	cols := []string{"id", "transaction_id", "user_id", "amount", "type", "status", "created_at", "currency"}
 	now := time.Now()
-	mock.ExpectQuery("SELECT \\* FROM transaction WHERE transaction_id").
+	mock.ExpectQuery("SELECT id, transaction_id, user_id, amount, type, status, created_at, currency FROM transaction WHERE transaction_id").
 		WithArgs("id-1").
-		WillReturnRows(sqlmock.NewRows(cols).AddRow(2, "id-1", 22, 75.5, "t", 0, now))
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(2, "id-1", 22, "75.50", "t", 0, now, "RUB"))
 
 	tx, err := r.GetTransactionByID("id-1", "req")
 	assert.NoError(t, err)
@@ -155,7 +158,7 @@ func TestGetTransactionByID_NotFound(t *testing.T) {
 	r, mock, close := setup()
 	defer close()
 
-	mock.ExpectQuery("SELECT \\* FROM transaction WHERE transaction_id").
+	mock.ExpectQuery("SELECT id, transaction_id, user_id, amount, type, status, created_at, currency FROM transaction WHERE transaction_id").
 		WithArgs("nope").
 		WillReturnError(sql.ErrNoRows)
 
@@ -165,24 +168,87 @@ func TestGetTransactionByID_NotFound(t *testing.T) {
 	assert.Contains(t, err.Error(), "not found")
 }
 
+// expectSpendPromo ждёт списания промо-кредита; пустой used — кредита нет
+func expectSpendPromo(mock sqlmock.Sqlmock, userID int, used string) {
+	expected := mock.ExpectQuery("WITH promo AS").WithArgs(sqlmock.AnyArg(), userID)
+	if used == "" {
+		expected.WillReturnError(sql.ErrNoRows)
+		return
+	}
+	expected.WillReturnRows(sqlmock.NewRows([]string{"least"}).AddRow(used))
+}
+
 func TestRegUserActivity_Success(t *testing.T) {
 	r, mock, close := setup()
 	defer close()
 
-	amount := entity.Decimal{Dec: nil} // Value=>"0"
+	amount := entity.DecimalFromKopecks(150)
+	fee := entity.DecimalFromKopecks(15)
 	mock.ExpectBegin()
+	expectSpendPromo(mock, 2, "")
 	mock.ExpectExec("UPDATE auth_user").
-		WithArgs(sqlmock.AnyArg(), 2).
+		WithArgs("1.50", 2).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectExec("UPDATE auth_user").
-		WithArgs(sqlmock.AnyArg(), 1).
+		WithArgs("1.35", 1).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectPostEntry(mock, "impression_charge", "hold-1", 3)
 	mock.ExpectCommit()
 
-	from, to, err := r.RegUserActivity(1, 2, amount)
+	from, to, err := r.RegUserActivity(1, 2, amount, fee, "hold-1", entity.Placement{})
 	assert.NoError(t, err)
 	assert.Equal(t, 1, from)
 	assert.Equal(t, 2, to)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRegUserActivity_SpendsPromoFirst(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	mock.ExpectBegin()
+	expectSpendPromo(mock, 2, "1.00")
+	mock.ExpectExec("UPDATE auth_user").
+		WithArgs("0.50", 2).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE auth_user").
+		WithArgs("1.35", 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectPostEntry(mock, "impression_charge", "hold-2", 4)
+	mock.ExpectCommit()
+
+	_, _, err := r.RegUserActivity(1, 2, entity.DecimalFromKopecks(150), entity.DecimalFromKopecks(15), "hold-2", entity.Placement{})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestApplyBudgetBatch_SpendsPromoFirst(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	charges := []entity.LedgerEntry{
+		entity.ImpressionChargeEntry(2, 1, entity.DecimalFromKopecks(100), entity.Decimal{}, "h1"),
+		entity.ImpressionChargeEntry(2, 1, entity.DecimalFromKopecks(100), entity.Decimal{}, "h2"),
+	}
+	deltas := map[int]entity.Decimal{2: entity.DecimalFromKopecks(-200)}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO budget_batch").
+		WithArgs("b1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectSpendPromo(mock, 2, "1.50")
+	mock.ExpectExec("UPDATE auth_user").
+		WithArgs("-0.50", 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// первый показ целиком за счёт промо-кредита, второй — поровну
+	expectPostEntry(mock, "impression_charge", "h1", 2)
+	expectPostEntry(mock, "impression_charge", "h2", 3)
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.ApplyBudgetBatch("b1", deltas, charges))
+	assert.Equal(t, "-2.00", deltas[2].String())
+	assert.Equal(t, entity.AccountAdvertiser, charges[0].Postings[0].Account)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestRegUserActivity_FirstUpdateError(t *testing.T) {
@@ -191,12 +257,13 @@ func TestRegUserActivity_FirstUpdateError(t *testing.T) {
 
 	amount := entity.Decimal{Dec: nil}
 	mock.ExpectBegin()
+	expectSpendPromo(mock, 2, "")
 	mock.ExpectExec("UPDATE auth_user").
 		WithArgs(sqlmock.AnyArg(), 2).
 		WillReturnError(fmt.Errorf("first error"))
 	mock.ExpectRollback().WillReturnError(fmt.Errorf("rb error"))
 
-	_, _, err := r.RegUserActivity(1, 2, amount)
+	_, _, err := r.RegUserActivity(1, 2, amount, entity.Decimal{}, "", entity.Placement{})
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to rollback")
 }
@@ -207,6 +274,7 @@ func TestRegUserActivity_SecondUpdateError(t *testing.T) {
 
 	amount := entity.Decimal{Dec: nil}
 	mock.ExpectBegin()
+	expectSpendPromo(mock, 2, "")
 	mock.ExpectExec("UPDATE auth_user").
 		WithArgs(sqlmock.AnyArg(), 2).
 		WillReturnResult(sqlmock.NewResult(1, 1))
@@ -215,7 +283,7 @@ func TestRegUserActivity_SecondUpdateError(t *testing.T) {
 		WillReturnError(fmt.Errorf("second error"))
 	mock.ExpectRollback()
 
-	_, _, err := r.RegUserActivity(1, 2, amount)
+	_, _, err := r.RegUserActivity(1, 2, amount, entity.Decimal{}, "", entity.Placement{})
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to update second user balance")
 }
@@ -224,17 +292,19 @@ func TestRegUserActivity_CommitError(t *testing.T) {
 	r, mock, close := setup()
 	defer close()
 
-	amount := entity.Decimal{Dec: nil}
+	amount := entity.DecimalFromKopecks(150)
 	mock.ExpectBegin()
+	expectSpendPromo(mock, 2, "")
 	mock.ExpectExec("UPDATE auth_user").
 		WithArgs(sqlmock.AnyArg(), 2).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectExec("UPDATE auth_user").
 		WithArgs(sqlmock.AnyArg(), 1).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectPostEntry(mock, "impression_charge", "", 2)
 	mock.ExpectCommit().WillReturnError(fmt.Errorf("commit error"))
 
-	_, _, err := r.RegUserActivity(1, 2, amount)
+	_, _, err := r.RegUserActivity(1, 2, amount, entity.Decimal{}, "", entity.Placement{})
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to commit")
 }
@@ -243,13 +313,13 @@ func TestGetPendingTransactions_Success(t *testing.T) {
 	r, mock, close := setup()
 	defer close()
 
-	cols := []string{"id", "transaction_id", "user_id", "amount", "type", "status", "created_at"}
+	cols := []string{"id", "transaction_id", "user_id", "amount", "type", "status", "created_at", "currency"}
 	now := time.Now()
 	mock.ExpectQuery("SELECT id, transaction_id").
 		WithArgs(33).
 		WillReturnRows(sqlmock.NewRows(cols).
-			AddRow(1, "a", 33, 1.1, "t", 0, now).
-			AddRow(2, "b", 33, 2.2, "t", 0, now))
+			AddRow(1, "a", 33, "1.10", "t", 0, now, "RUB").
+			AddRow(2, "b", 33, "2.20", "t", 0, now, "RUB"))
 
 	list, err := r.GetPendingTransactions(33)
 	assert.NoError(t, err)
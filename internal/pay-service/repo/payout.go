@@ -0,0 +1,269 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"retarget/internal/pay-service/entity"
+)
+
+const payoutColumns = `id, user_id, amount, destination_type, destination, description, status,
+        COALESCE(gateway_id, ''), failure_reason, COALESCE(reviewed_by, 0), created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPayout(row rowScanner) (entity.PayoutRequest, error) {
+	var p entity.PayoutRequest
+	err := row.Scan(&p.ID, &p.UserID, &p.Amount, &p.DestinationType, &p.Destination, &p.Description, &p.Status,
+		&p.GatewayID, &p.FailureReason, &p.ReviewedBy, &p.CreatedAt, &p.UpdatedAt)
+	return p, err
+}
+
+// PayoutReference — reference проводок по заявке на выплату
+func PayoutReference(payoutID int) string {
+	return "payout_request:" + strconv.Itoa(payoutID)
+}
+
+// CreatePayoutRequest проверяет заявку лимитами policy и в той же транзакции
+// заводит её и удерживает сумму с баланса. Строка пользователя блокируется до
+// конца транзакции, поэтому параллельные заявки не обойдут суточный и месячный
+// лимиты. Если средств не хватает, возвращает entity.ErrInsufficientBalance
+func (r *PaymentRepository) CreatePayoutRequest(p entity.PayoutRequest, policy entity.PayoutPolicy, now time.Time) (entity.PayoutRequest, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return p, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	rollback := func(err error) (entity.PayoutRequest, error) {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			err = fmt.Errorf("rollback failed: %v; original error: %w", rbErr, err)
+		}
+		return p, err
+	}
+
+	registeredAt, err := r.accountCreatedAt(tx, p.UserID, true)
+	if err != nil {
+		return rollback(err)
+	}
+	daySpent, err := r.payoutTotalSince(tx, p.UserID, now.Add(-24*time.Hour))
+	if err != nil {
+		return rollback(err)
+	}
+	monthSpent, err := r.payoutTotalSince(tx, p.UserID, now.AddDate(0, -1, 0))
+	if err != nil {
+		return rollback(err)
+	}
+	if err := policy.Check(p.Amount, daySpent, monthSpent, registeredAt, now); err != nil {
+		return rollback(err)
+	}
+
+	res, err := tx.Exec(`
+        UPDATE auth_user
+        SET balance = balance - $1
+        WHERE id = $2 AND balance >= $1`,
+		p.Amount,
+		p.UserID,
+	)
+	if err != nil {
+		return rollback(fmt.Errorf("failed to hold payout amount: %w", err))
+	}
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		if err == nil {
+			err = entity.ErrInsufficientBalance
+		}
+		return rollback(err)
+	}
+
+	p.Status = entity.PayoutRequested
+	err = tx.QueryRow(`
+        INSERT INTO payout_request (user_id, amount, destination_type, destination, description, status)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, created_at, updated_at`,
+		p.UserID,
+		p.Amount,
+		p.DestinationType,
+		p.Destination,
+		p.Description,
+		p.Status,
+	).Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return rollback(fmt.Errorf("failed to insert payout request: %w", err))
+	}
+
+	entry := entity.PayoutHoldEntry(p.UserID, p.Amount, PayoutReference(p.ID))
+	if _, err = r.postEntry(tx, entry); err != nil {
+		return rollback(err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return p, fmt.Errorf("failed to commit payout request: %w", err)
+	}
+	return p, nil
+}
+
+// TransitionPayout переводит заявку из статуса from в to. Если заявка уже не в
+// статусе from, ничего не меняется и возвращается false. При entry != nil в той
+// же транзакции баланс меняется на delta и записывается проводка
+func (r *PaymentRepository) TransitionPayout(payoutID int, from, to entity.PayoutStatus, update entity.PayoutUpdate, delta entity.Decimal, entry *entity.LedgerEntry) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	var userID int
+	err = tx.QueryRow(`
+        UPDATE payout_request
+        SET status = $1,
+            gateway_id = COALESCE(NULLIF($2, ''), gateway_id),
+            failure_reason = $3,
+            reviewed_by = COALESCE(NULLIF($4, 0), reviewed_by),
+            approved_at = CASE WHEN $1 = 'approved' AND status = 'requested' THEN (now() AT TIME ZONE 'UTC') ELSE approved_at END,
+            updated_at = (now() AT TIME ZONE 'UTC')
+        WHERE id = $5 AND status = $6
+        RETURNING user_id`,
+		to,
+		update.GatewayID,
+		update.FailureReason,
+		update.ReviewedBy,
+		payoutID,
+		from,
+	).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return false, fmt.Errorf("rollback failed: %w", rbErr)
+		}
+		return false, nil
+	}
+	if err == nil && entry != nil {
+		if delta.Sign() != 0 {
+			_, err = tx.Exec(`
+                UPDATE auth_user
+                SET balance = balance + $1
+                WHERE id = $2`,
+				delta,
+				userID,
+			)
+		}
+		if err == nil {
+			_, err = r.postEntry(tx, *entry)
+		}
+	}
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			err = fmt.Errorf("rollback failed: %v; original error: %w", rbErr, err)
+		}
+		return false, fmt.Errorf("failed to transition payout %d to %s: %w", payoutID, to, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit payout transition: %w", err)
+	}
+	return true, nil
+}
+
+func (r *PaymentRepository) GetPayoutRequest(payoutID int) (entity.PayoutRequest, error) {
+	p, err := scanPayout(r.db.QueryRow(`SELECT `+payoutColumns+` FROM payout_request WHERE id = $1`, payoutID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return p, entity.ErrPayoutNotFound
+	}
+	if err != nil {
+		return p, fmt.Errorf("failed to get payout request: %w", err)
+	}
+	return p, nil
+}
+
+func (r *PaymentRepository) GetPayoutByGatewayID(gatewayID string) (entity.PayoutRequest, error) {
+	p, err := scanPayout(r.db.QueryRow(`SELECT `+payoutColumns+` FROM payout_request WHERE gateway_id = $1`, gatewayID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return p, entity.ErrPayoutNotFound
+	}
+	if err != nil {
+		return p, fmt.Errorf("failed to get payout request: %w", err)
+	}
+	return p, nil
+}
+
+func (r *PaymentRepository) GetPayoutRequests(userID int, limit int) ([]entity.PayoutRequest, error) {
+	return r.queryPayouts(`SELECT `+payoutColumns+`
+        FROM payout_request
+        WHERE user_id = $1
+        ORDER BY created_at DESC
+        LIMIT $2`, userID, limit)
+}
+
+func (r *PaymentRepository) GetPayoutsByStatus(status entity.PayoutStatus, limit int) ([]entity.PayoutRequest, error) {
+	return r.queryPayouts(`SELECT `+payoutColumns+`
+        FROM payout_request
+        WHERE status = $1
+        ORDER BY created_at
+        LIMIT $2`, status, limit)
+}
+
+func (r *PaymentRepository) queryPayouts(query string, args ...interface{}) ([]entity.PayoutRequest, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payout requests: %w", err)
+	}
+	defer rows.Close()
+
+	list := make([]entity.PayoutRequest, 0)
+	for rows.Next() {
+		p, err := scanPayout(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetStaleApprovedPayouts отдаёт одобренные заявки, которые не менялись с
+// olderThan и одобрены не раньше approvedAfter. Сначала идут те, что дольше
+// всех ждут повтора
+func (r *PaymentRepository) GetStaleApprovedPayouts(olderThan, approvedAfter time.Time, limit int) ([]entity.PayoutRequest, error) {
+	return r.queryPayouts(`SELECT `+payoutColumns+`
+        FROM payout_request
+        WHERE status = $1 AND updated_at < $2 AND approved_at >= $3
+        ORDER BY updated_at
+        LIMIT $4`, entity.PayoutApproved, olderThan, approvedAfter, limit)
+}
+
+// payoutTotalSince суммирует заявки пользователя начиная с since, кроме
+// несостоявшихся — по ним деньги вернулись на баланс
+func (r *PaymentRepository) payoutTotalSince(q queryRower, userID int, since time.Time) (entity.Decimal, error) {
+	var total entity.Decimal
+	err := q.QueryRow(`
+        SELECT COALESCE(SUM(amount), 0)
+        FROM payout_request
+        WHERE user_id = $1 AND created_at >= $2 AND status NOT IN ('failed', 'returned')`,
+		userID,
+		since,
+	).Scan(&total)
+	if err != nil {
+		return entity.Decimal{}, fmt.Errorf("failed to get payout total: %w", err)
+	}
+	return total, nil
+}
+
+func (r *PaymentRepository) accountCreatedAt(q queryRower, userID int, lock bool) (time.Time, error) {
+	query := `SELECT created_at FROM auth_user WHERE id = $1`
+	if lock {
+		query += ` FOR UPDATE`
+	}
+	var createdAt time.Time
+	err := q.QueryRow(query, userID).Scan(&createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return createdAt, ErrUserNotFound
+	}
+	if err != nil {
+		return createdAt, fmt.Errorf("failed to get account creation date: %w", err)
+	}
+	return createdAt, nil
+}
@@ -0,0 +1,109 @@
+package repo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"retarget/internal/pay-service/entity"
+)
+
+// expectPayoutLimits ждёт блокировки пользователя и сумм его заявок за сутки и месяц
+func expectPayoutLimits(mock sqlmock.Sqlmock, userID int, registeredAt time.Time, spent string) {
+	mock.ExpectQuery(`SELECT created_at FROM auth_user WHERE id = \$1 FOR UPDATE`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(registeredAt))
+	for i := 0; i < 2; i++ {
+		mock.ExpectQuery("FROM payout_request").
+			WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(spent))
+	}
+}
+
+func TestCreatePayoutRequest_HoldsFunds(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	now := time.Now()
+	mock.ExpectBegin()
+	expectPayoutLimits(mock, 5, now.AddDate(-1, 0, 0), "0.00")
+	mock.ExpectExec("UPDATE auth_user").
+		WithArgs("150.00", 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO payout_request").
+		WithArgs(5, "150.00", "yoo_money", "4100", "", entity.PayoutRequested).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(7, now, now))
+	expectPostEntry(mock, "payout_hold", "payout_request:7", 2)
+	mock.ExpectCommit()
+
+	p, err := r.CreatePayoutRequest(entity.PayoutRequest{UserID: 5, Amount: entity.DecimalFromKopecks(15000), DestinationType: "yoo_money", Destination: "4100"}, entity.PayoutPolicy{}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, p.ID)
+	assert.Equal(t, entity.PayoutRequested, p.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreatePayoutRequest_InsufficientFunds(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	now := time.Now()
+	mock.ExpectBegin()
+	expectPayoutLimits(mock, 5, now.AddDate(-1, 0, 0), "0.00")
+	mock.ExpectExec("UPDATE auth_user").
+		WithArgs("150.00", 5).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	_, err := r.CreatePayoutRequest(entity.PayoutRequest{UserID: 5, Amount: entity.DecimalFromKopecks(15000)}, entity.PayoutPolicy{}, now)
+	assert.ErrorIs(t, err, entity.ErrInsufficientBalance)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreatePayoutRequest_LimitCheckedUnderLock(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	now := time.Now()
+	mock.ExpectBegin()
+	expectPayoutLimits(mock, 5, now.AddDate(-1, 0, 0), "900.00")
+	mock.ExpectRollback()
+
+	policy := entity.PayoutPolicy{DailyLimit: entity.DecimalFromKopecks(100000)}
+	_, err := r.CreatePayoutRequest(entity.PayoutRequest{UserID: 5, Amount: entity.DecimalFromKopecks(15000)}, policy, now)
+	assert.ErrorIs(t, err, entity.ErrPayoutDailyLimit)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransitionPayout_ReleasesOnce(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	entry := entity.PayoutReleaseEntry(5, entity.DecimalFromKopecks(15000), "payout_request:7")
+	update := entity.PayoutUpdate{FailureReason: "rejected", ReviewedBy: 3}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE payout_request").
+		WithArgs(entity.PayoutFailed, "", "rejected", 3, 7, entity.PayoutRequested).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(5))
+	mock.ExpectExec("UPDATE auth_user").
+		WithArgs("150.00", 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectPostEntry(mock, "payout_release", "payout_request:7", 2)
+	mock.ExpectCommit()
+
+	ok, err := r.TransitionPayout(7, entity.PayoutRequested, entity.PayoutFailed, update, entity.DecimalFromKopecks(15000), &entry)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE payout_request").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+	mock.ExpectRollback()
+
+	ok, err = r.TransitionPayout(7, entity.PayoutRequested, entity.PayoutFailed, update, entity.DecimalFromKopecks(15000), &entry)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
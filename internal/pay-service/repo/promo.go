@@ -0,0 +1,290 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"retarget/internal/pay-service/entity"
+
+	"github.com/lib/pq"
+)
+
+const promoCodeColumns = `id, code, kind, value, max_bonus, max_redemptions, per_user_limit, redemptions,
+        expires_at, active, COALESCE(created_by, 0), created_at`
+
+const promoRedemptionColumns = `r.id, r.promo_code_id, c.code, r.user_id, r.amount, r.top_up_amount,
+        COALESCE(r.transaction_id, ''), r.entry_id, r.created_at`
+
+func scanPromoCode(row rowScanner) (entity.PromoCode, error) {
+	var p entity.PromoCode
+	err := row.Scan(&p.ID, &p.Code, &p.Kind, &p.Value, &p.MaxBonus, &p.MaxRedemptions, &p.PerUserLimit, &p.Redemptions,
+		&p.ExpiresAt, &p.Active, &p.CreatedBy, &p.CreatedAt)
+	return p, err
+}
+
+func scanPromoRedemption(row rowScanner) (entity.PromoRedemption, error) {
+	var pr entity.PromoRedemption
+	err := row.Scan(&pr.ID, &pr.PromoCodeID, &pr.Code, &pr.UserID, &pr.Amount, &pr.TopUpAmount,
+		&pr.TransactionID, &pr.EntryID, &pr.CreatedAt)
+	return pr, err
+}
+
+// PromoReference — reference проводки с начислением промо-кредита
+func PromoReference(code string, userID int) string {
+	return fmt.Sprintf("promo:%s:%d", code, userID)
+}
+
+func (r *PaymentRepository) CreatePromoCode(p entity.PromoCode) (entity.PromoCode, error) {
+	query := `
+        INSERT INTO promo_code (code, kind, value, max_bonus, max_redemptions, per_user_limit, expires_at, created_by)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING ` + promoCodeColumns
+
+	created, err := scanPromoCode(r.db.QueryRow(query,
+		p.Code, p.Kind, p.Value, p.MaxBonus, p.MaxRedemptions, p.PerUserLimit, p.ExpiresAt, p.CreatedBy))
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return entity.PromoCode{}, entity.ErrPromoExists
+	}
+	if err != nil {
+		return entity.PromoCode{}, fmt.Errorf("failed to create promo code: %w", err)
+	}
+	return created, nil
+}
+
+func (r *PaymentRepository) GetPromoCodes() ([]entity.PromoCode, error) {
+	rows, err := r.db.Query(`SELECT ` + promoCodeColumns + ` FROM promo_code ORDER BY created_at DESC, id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get promo codes: %w", err)
+	}
+	defer rows.Close()
+
+	codes := make([]entity.PromoCode, 0)
+	for rows.Next() {
+		p, err := scanPromoCode(rows)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// GetPromoCode отдаёт код и число его погашений пользователем userID
+func (r *PaymentRepository) GetPromoCode(code string, userID int) (entity.PromoCode, int, error) {
+	return r.promoCodeForUser(r.db, code, userID, false)
+}
+
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func (r *PaymentRepository) promoCodeForUser(q queryRower, code string, userID int, lock bool) (entity.PromoCode, int, error) {
+	query := `SELECT ` + promoCodeColumns + ` FROM promo_code WHERE code = $1`
+	if lock {
+		query += ` FOR UPDATE`
+	}
+	p, err := scanPromoCode(q.QueryRow(query, code))
+	if errors.Is(err, sql.ErrNoRows) {
+		return p, 0, entity.ErrPromoNotFound
+	}
+	if err != nil {
+		return p, 0, fmt.Errorf("failed to get promo code: %w", err)
+	}
+
+	var used int
+	err = q.QueryRow(`SELECT COUNT(*) FROM promo_redemption WHERE promo_code_id = $1 AND user_id = $2`, p.ID, userID).Scan(&used)
+	if err != nil {
+		return p, 0, fmt.Errorf("failed to count promo redemptions: %w", err)
+	}
+	return p, used, nil
+}
+
+func (r *PaymentRepository) SetPromoCodeActive(codeID int, active bool) (entity.PromoCode, error) {
+	query := `UPDATE promo_code SET active = $2 WHERE id = $1 RETURNING ` + promoCodeColumns
+
+	p, err := scanPromoCode(r.db.QueryRow(query, codeID, active))
+	if errors.Is(err, sql.ErrNoRows) {
+		return p, entity.ErrPromoNotFound
+	}
+	if err != nil {
+		return p, fmt.Errorf("failed to update promo code: %w", err)
+	}
+	return p, nil
+}
+
+// RedeemPromoCode начисляет промо-кредит по коду. Код блокируется до конца
+// транзакции, поэтому общий и пользовательский лимиты не превышаются при
+// параллельных погашениях. topUp и transactionID передаются, когда код
+// применяется к пополнению; повторное применение к тому же пополнению
+// возвращает entity.ErrPromoUserLimit
+func (r *PaymentRepository) RedeemPromoCode(code string, userID int, topUp *entity.Decimal, transactionID string, now time.Time) (entity.PromoRedemption, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return entity.PromoRedemption{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	p, used, err := r.promoCodeForUser(tx, code, userID, true)
+	if err != nil {
+		return entity.PromoRedemption{}, r.rollback(tx, err)
+	}
+	if err := p.CheckRedeemable(now, used); err != nil {
+		return entity.PromoRedemption{}, r.rollback(tx, err)
+	}
+	bonus, err := p.Bonus(topUp)
+	if err != nil {
+		return entity.PromoRedemption{}, r.rollback(tx, err)
+	}
+
+	reference := PromoReference(p.Code, userID)
+	if transactionID != "" {
+		reference = transactionID
+	}
+	entryID, err := r.postEntry(tx, entity.PromoCreditEntry(userID, bonus, reference))
+	if err != nil {
+		return entity.PromoRedemption{}, r.rollback(tx, err)
+	}
+
+	pr := entity.PromoRedemption{
+		PromoCodeID:   p.ID,
+		Code:          p.Code,
+		UserID:        userID,
+		Amount:        bonus,
+		TopUpAmount:   topUp,
+		TransactionID: transactionID,
+		EntryID:       entryID,
+	}
+	err = tx.QueryRow(`
+        INSERT INTO promo_redemption (promo_code_id, user_id, amount, top_up_amount, transaction_id, entry_id)
+        VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6)
+        RETURNING id, created_at`,
+		pr.PromoCodeID,
+		pr.UserID,
+		pr.Amount,
+		pr.TopUpAmount,
+		pr.TransactionID,
+		pr.EntryID,
+	).Scan(&pr.ID, &pr.CreatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return pr, r.rollback(tx, entity.ErrPromoUserLimit)
+	}
+	if err != nil {
+		return pr, r.rollback(tx, fmt.Errorf("failed to insert promo redemption: %w", err))
+	}
+
+	if _, err = tx.Exec(`UPDATE promo_code SET redemptions = redemptions + 1 WHERE id = $1`, p.ID); err != nil {
+		return pr, r.rollback(tx, fmt.Errorf("failed to count promo redemption: %w", err))
+	}
+	if _, err = tx.Exec(`UPDATE auth_user SET promo_balance = promo_balance + $1 WHERE id = $2`, bonus, userID); err != nil {
+		return pr, r.rollback(tx, fmt.Errorf("failed to credit promo balance: %w", err))
+	}
+
+	if err = tx.Commit(); err != nil {
+		return pr, fmt.Errorf("failed to commit promo redemption: %w", err)
+	}
+	return pr, nil
+}
+
+// AttachTopUpPromo запоминает код, указанный при создании платежа; кредит
+// начисляется, когда пополнение будет зачислено
+func (r *PaymentRepository) AttachTopUpPromo(transactionID, code string, userID int) error {
+	_, err := r.db.Exec(`
+        INSERT INTO promo_topup (transaction_id, code, user_id)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (transaction_id) DO NOTHING`,
+		transactionID,
+		code,
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to attach promo code to top-up: %w", err)
+	}
+	return nil
+}
+
+// GetTopUpPromo отдаёт код, указанный при создании платежа; пустая строка — кода нет
+func (r *PaymentRepository) GetTopUpPromo(transactionID string) (string, error) {
+	var code string
+	err := r.db.QueryRow(`SELECT code FROM promo_topup WHERE transaction_id = $1`, transactionID).Scan(&code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get top-up promo code: %w", err)
+	}
+	return code, nil
+}
+
+// GetPromoRedemptions отдаёт погашения по коду codeID и/или пользователя
+// userID; нулевое значение фильтра не ограничивает выборку
+func (r *PaymentRepository) GetPromoRedemptions(codeID, userID int, limit int) ([]entity.PromoRedemption, error) {
+	query := `
+        SELECT ` + promoRedemptionColumns + `
+        FROM promo_redemption r
+        JOIN promo_code c ON c.id = r.promo_code_id
+        WHERE ($1 = 0 OR r.promo_code_id = $1) AND ($2 = 0 OR r.user_id = $2)
+        ORDER BY r.created_at DESC, r.id DESC
+        LIMIT $3`
+
+	rows, err := r.db.Query(query, codeID, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get promo redemptions: %w", err)
+	}
+	defer rows.Close()
+
+	redemptions := make([]entity.PromoRedemption, 0)
+	for rows.Next() {
+		pr, err := scanPromoRedemption(rows)
+		if err != nil {
+			return nil, err
+		}
+		redemptions = append(redemptions, pr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return redemptions, nil
+}
+
+func (r *PaymentRepository) GetPromoBalance(userID int) (entity.Decimal, error) {
+	var balance entity.Decimal
+	err := r.db.QueryRow(`SELECT promo_balance FROM auth_user WHERE id = $1`, userID).Scan(&balance)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Decimal{}, ErrUserNotFound
+	}
+	if err != nil {
+		return entity.Decimal{}, fmt.Errorf("failed to get promo balance: %w", err)
+	}
+	return balance, nil
+}
+
+// spendPromo списывает с промо-остатка пользователя не больше amount и
+// возвращает списанное: промо-кредит тратится раньше денег на балансе
+func (r *PaymentRepository) spendPromo(tx *sql.Tx, userID int, amount entity.Decimal) (entity.Decimal, error) {
+	var used entity.Decimal
+	err := tx.QueryRow(`
+        WITH promo AS (
+            SELECT id, promo_balance FROM auth_user WHERE id = $2 FOR UPDATE
+        )
+        UPDATE auth_user u
+        SET promo_balance = u.promo_balance - LEAST(promo.promo_balance, $1)
+        FROM promo
+        WHERE u.id = promo.id AND promo.promo_balance > 0 AND $1 > 0
+        RETURNING LEAST(promo.promo_balance, $1)`,
+		amount,
+		userID,
+	).Scan(&used)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.DecimalFromKopecks(0), nil
+	}
+	if err != nil {
+		return entity.Decimal{}, fmt.Errorf("failed to spend promo balance: %w", err)
+	}
+	return used, nil
+}
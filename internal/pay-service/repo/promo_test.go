@@ -0,0 +1,93 @@
+package repo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"retarget/internal/pay-service/entity"
+)
+
+var promoCodeCols = []string{"id", "code", "kind", "value", "max_bonus", "max_redemptions", "per_user_limit", "redemptions",
+	"expires_at", "active", "created_by", "created_at"}
+
+func expectLockedPromoCode(mock sqlmock.Sqlmock, kind, value string, redemptions, used int) {
+	mock.ExpectQuery("FROM promo_code WHERE code = \\$1 FOR UPDATE").
+		WithArgs("WELCOME").
+		WillReturnRows(sqlmock.NewRows(promoCodeCols).
+			AddRow(3, "WELCOME", kind, value, nil, 10, 1, redemptions, nil, true, 9, time.Now()))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM promo_redemption").
+		WithArgs(3, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(used))
+}
+
+func TestRedeemPromoCode_Fixed(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	mock.ExpectBegin()
+	expectLockedPromoCode(mock, "fixed", "500.00", 2, 0)
+	expectPostEntry(mock, "promo_credit", "promo:WELCOME:5", 2)
+	mock.ExpectQuery("INSERT INTO promo_redemption").
+		WithArgs(3, 5, "500.00", nil, "", int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(11, time.Now()))
+	mock.ExpectExec("UPDATE promo_code SET redemptions").
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE auth_user SET promo_balance").
+		WithArgs("500.00", 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	pr, err := r.RedeemPromoCode("WELCOME", 5, nil, "", time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 11, pr.ID)
+	assert.Equal(t, "500.00", pr.Amount.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRedeemPromoCode_PercentOnTopUp(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	topUp := entity.DecimalFromKopecks(150000)
+	mock.ExpectBegin()
+	expectLockedPromoCode(mock, "percent", "10", 0, 0)
+	expectPostEntry(mock, "promo_credit", "trx-1", 2)
+	mock.ExpectQuery("INSERT INTO promo_redemption").
+		WithArgs(3, 5, "150.00", "1500.00", "trx-1", int64(1)).
+		WillReturnError(&pq.Error{Code: "23505"})
+	mock.ExpectRollback()
+
+	_, err := r.RedeemPromoCode("WELCOME", 5, &topUp, "trx-1", time.Now())
+	assert.ErrorIs(t, err, entity.ErrPromoUserLimit)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRedeemPromoCode_UserLimit(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	mock.ExpectBegin()
+	expectLockedPromoCode(mock, "fixed", "500.00", 2, 1)
+	mock.ExpectRollback()
+
+	_, err := r.RedeemPromoCode("WELCOME", 5, nil, "", time.Now())
+	assert.ErrorIs(t, err, entity.ErrPromoUserLimit)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreatePromoCode_Duplicate(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	mock.ExpectQuery("INSERT INTO promo_code").
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	_, err := r.CreatePromoCode(entity.PromoCode{Code: "WELCOME", Kind: entity.PromoFixed, Value: entity.DecimalFromKopecks(100)})
+	assert.ErrorIs(t, err, entity.ErrPromoExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
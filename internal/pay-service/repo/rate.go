@@ -0,0 +1,164 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+
+	"retarget/internal/pay-service/entity"
+)
+
+// SaveRateSnapshot записывает новую таблицу курсов; с этого момента она
+// используется для всех пересчётов. createdBy пустой у загрузки из файла
+func (r *PaymentRepository) SaveRateSnapshot(source string, createdBy int, rates map[string]entity.Decimal) (entity.RateSnapshot, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return entity.RateSnapshot{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	snapshot := entity.RateSnapshot{Source: source, Rates: rates}
+	err = tx.QueryRow(`
+        INSERT INTO currency_rate_snapshot (source, created_by)
+        VALUES ($1, $2)
+        RETURNING id, created_at`,
+		source,
+		sql.NullInt64{Int64: int64(createdBy), Valid: createdBy > 0},
+	).Scan(&snapshot.ID, &snapshot.CreatedAt)
+	if err != nil {
+		return entity.RateSnapshot{}, r.rollback(tx, fmt.Errorf("failed to insert rate snapshot: %w", err))
+	}
+
+	for _, currency := range snapshot.Currencies() {
+		rate, ok := rates[currency]
+		if !ok {
+			continue
+		}
+		_, err = tx.Exec(`
+            INSERT INTO currency_rate (snapshot_id, currency, rate)
+            VALUES ($1, $2, $3)`,
+			snapshot.ID,
+			currency,
+			rate,
+		)
+		if err != nil {
+			return entity.RateSnapshot{}, r.rollback(tx, fmt.Errorf("failed to insert rate %s: %w", currency, err))
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return entity.RateSnapshot{}, fmt.Errorf("failed to commit rate snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// GetLatestRateSnapshot отдаёт действующую таблицу курсов
+func (r *PaymentRepository) GetLatestRateSnapshot() (entity.RateSnapshot, error) {
+	const query = `
+        SELECT s.id, s.source, s.created_at, c.currency, c.rate
+        FROM currency_rate_snapshot s
+        JOIN currency_rate c ON c.snapshot_id = s.id
+        WHERE s.id = (SELECT MAX(id) FROM currency_rate_snapshot)
+        ORDER BY c.currency`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return entity.RateSnapshot{}, fmt.Errorf("failed to get rate snapshot: %w", err)
+	}
+	defer rows.Close()
+
+	snapshot := entity.RateSnapshot{Rates: make(map[string]entity.Decimal)}
+	for rows.Next() {
+		var (
+			currency string
+			rate     entity.Decimal
+		)
+		if err := rows.Scan(&snapshot.ID, &snapshot.Source, &snapshot.CreatedAt, &currency, &rate); err != nil {
+			return entity.RateSnapshot{}, err
+		}
+		snapshot.Rates[currency] = rate
+	}
+	if err := rows.Err(); err != nil {
+		return entity.RateSnapshot{}, err
+	}
+	if snapshot.ID == 0 {
+		return entity.RateSnapshot{}, entity.ErrNoRateSnapshot
+	}
+	return snapshot, nil
+}
+
+// ExchangeBalance проводит обмен между валютными счетами пользователя. Списание
+// с базовой валюты идёт через auth_user.balance, с другой валюты — со счёта в
+// журнале, который на время проверки остатка блокируется. Если средств не
+// хватает, возвращает entity.ErrInsufficientBalance
+func (r *PaymentRepository) ExchangeBalance(userID int, entry entity.LedgerEntry) (int64, error) {
+	c := entry.Conversion
+	if c == nil {
+		return 0, fmt.Errorf("exchange entry %s has no conversion", entry.Reference)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if c.FromCurrency == entity.BaseCurrency {
+		res, err := tx.Exec(`
+            UPDATE auth_user
+            SET balance = balance - $1
+            WHERE id = $2 AND balance >= $1`,
+			c.OriginalAmount,
+			userID,
+		)
+		if err != nil {
+			return 0, r.rollback(tx, fmt.Errorf("failed to debit exchange: %w", err))
+		}
+		if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+			if err == nil {
+				err = entity.ErrInsufficientBalance
+			}
+			return 0, r.rollback(tx, err)
+		}
+	} else {
+		accountID, err := r.ledgerAccountID(tx, userID, entity.AccountAdvertiser, c.FromCurrency)
+		if err != nil {
+			return 0, r.rollback(tx, err)
+		}
+		var enough bool
+		err = tx.QueryRow(`
+            SELECT COALESCE(SUM(p.amount), 0) >= $2
+            FROM ledger_account a
+            LEFT JOIN ledger_posting p ON p.account_id = a.id
+            WHERE a.id = (SELECT id FROM ledger_account WHERE id = $1 FOR UPDATE)
+            GROUP BY a.id`,
+			accountID,
+			c.OriginalAmount,
+		).Scan(&enough)
+		if err != nil {
+			return 0, r.rollback(tx, fmt.Errorf("failed to check %s balance: %w", c.FromCurrency, err))
+		}
+		if !enough {
+			return 0, r.rollback(tx, entity.ErrInsufficientBalance)
+		}
+	}
+
+	if c.Currency == entity.BaseCurrency {
+		_, err = tx.Exec(`
+            UPDATE auth_user
+            SET balance = balance + $1
+            WHERE id = $2`,
+			c.Amount,
+			userID,
+		)
+		if err != nil {
+			return 0, r.rollback(tx, fmt.Errorf("failed to credit exchange: %w", err))
+		}
+	}
+
+	entryID, err := r.postEntry(tx, entry)
+	if err != nil {
+		return 0, r.rollback(tx, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit exchange: %w", err)
+	}
+	return entryID, nil
+}
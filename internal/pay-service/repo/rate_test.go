@@ -0,0 +1,122 @@
+package repo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"retarget/internal/pay-service/entity"
+)
+
+func TestSaveRateSnapshot(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	rates, err := entity.ParseRates([]byte(`{"USD": "90", "EUR": "100"}`))
+	assert.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO currency_rate_snapshot").
+		WithArgs("admin", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(7, now))
+	mock.ExpectExec("INSERT INTO currency_rate").
+		WithArgs(int64(7), "EUR", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO currency_rate").
+		WithArgs(int64(7), "USD", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	snapshot, err := r.SaveRateSnapshot("admin", 3, rates)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), snapshot.ID)
+	assert.Equal(t, now, snapshot.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetLatestRateSnapshot(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("FROM currency_rate_snapshot").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "source", "created_at", "currency", "rate"}).
+			AddRow(7, "file:rates.json", now, "EUR", "100.000000").
+			AddRow(7, "file:rates.json", now, "USD", "90.000000"))
+
+	snapshot, err := r.GetLatestRateSnapshot()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), snapshot.ID)
+	assert.Equal(t, "file:rates.json", snapshot.Source)
+	assert.Equal(t, []string{"EUR", "RUB", "USD"}, snapshot.Currencies())
+	assert.Equal(t, "90.000000", snapshot.Rates["USD"].String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetLatestRateSnapshot_Empty(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	mock.ExpectQuery("FROM currency_rate_snapshot").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "source", "created_at", "currency", "rate"}))
+
+	_, err := r.GetLatestRateSnapshot()
+	assert.ErrorIs(t, err, entity.ErrNoRateSnapshot)
+}
+
+func TestExchangeBalance_FromBase(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	conversion := entity.Conversion{
+		SnapshotID:     7,
+		FromCurrency:   entity.BaseCurrency,
+		OriginalAmount: entity.DecimalFromKopecks(9000),
+		Currency:       "USD",
+		Amount:         entity.DecimalFromKopecks(100),
+	}
+	entry := entity.ExchangeEntry(5, conversion, "exchange:1")
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE auth_user").
+		WithArgs(conversion.OriginalAmount, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectPostEntry(mock, "conversion", "exchange:1", 4)
+	mock.ExpectExec("INSERT INTO ledger_entry_conversion").
+		WithArgs(1, int64(7), "RUB", sqlmock.AnyArg(), "USD", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	_, err := r.ExchangeBalance(5, entry)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExchangeBalance_ForeignInsufficient(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	conversion := entity.Conversion{
+		SnapshotID:     7,
+		FromCurrency:   "USD",
+		OriginalAmount: entity.DecimalFromKopecks(100),
+		Currency:       entity.BaseCurrency,
+		Amount:         entity.DecimalFromKopecks(9000),
+	}
+	entry := entity.ExchangeEntry(5, conversion, "exchange:2")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO ledger_account").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+	mock.ExpectQuery("FOR UPDATE").
+		WithArgs(11, conversion.OriginalAmount).
+		WillReturnRows(sqlmock.NewRows([]string{"enough"}).AddRow(false))
+	mock.ExpectRollback()
+
+	_, err := r.ExchangeBalance(5, entry)
+	assert.ErrorIs(t, err, entity.ErrInsufficientBalance)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
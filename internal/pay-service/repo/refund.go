@@ -0,0 +1,302 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"retarget/internal/pay-service/entity"
+
+	"github.com/lib/pq"
+)
+
+const refundColumns = `id, kind, original_id, user_id, COALESCE(publisher_id, 0), amount, status,
+        COALESCE(gateway_id, ''), reason, created_by, created_at`
+
+func scanRefund(row rowScanner) (entity.Refund, error) {
+	var rf entity.Refund
+	err := row.Scan(&rf.ID, &rf.Kind, &rf.OriginalID, &rf.UserID, &rf.PublisherID, &rf.Amount, &rf.Status,
+		&rf.GatewayID, &rf.Reason, &rf.CreatedBy, &rf.CreatedAt)
+	return rf, err
+}
+
+// RefundReference — reference проводок по возврату
+func RefundReference(refundID int) string {
+	return "refund:" + strconv.Itoa(refundID)
+}
+
+func (r *PaymentRepository) rollback(tx *sql.Tx, err error) error {
+	if rbErr := tx.Rollback(); rbErr != nil {
+		return fmt.Errorf("rollback failed: %v; original error: %w", rbErr, err)
+	}
+	return err
+}
+
+// CreateTopUpRefund заводит возврат пополнения и сразу списывает его сумму с
+// баланса. Исходная транзакция блокируется, чтобы параллельные возвраты не
+// превысили сумму пополнения
+func (r *PaymentRepository) CreateTopUpRefund(rf entity.Refund) (entity.Refund, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return rf, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	var (
+		original entity.Decimal
+		refunded entity.Decimal
+	)
+	err = tx.QueryRow(`
+        SELECT amount
+        FROM transaction
+        WHERE transaction_id = $1 AND user_id = $2
+        FOR UPDATE`,
+		rf.OriginalID,
+		rf.UserID,
+	).Scan(&original)
+	if errors.Is(err, sql.ErrNoRows) {
+		return rf, r.rollback(tx, entity.ErrRefundNotAllowed)
+	}
+	if err == nil {
+		err = tx.QueryRow(`
+            SELECT COALESCE(SUM(amount), 0)
+            FROM refund
+            WHERE kind = $1 AND original_id = $2 AND status <> $3`,
+			entity.RefundTopUp,
+			rf.OriginalID,
+			entity.RefundCanceled,
+		).Scan(&refunded)
+	}
+	if err != nil {
+		return rf, r.rollback(tx, fmt.Errorf("failed to check refundable amount: %w", err))
+	}
+	if rf.Amount.Cmp(original.Sub(refunded)) > 0 {
+		return rf, r.rollback(tx, entity.ErrRefundExceedsOriginal)
+	}
+
+	res, err := tx.Exec(`
+        UPDATE auth_user
+        SET balance = balance - $1
+        WHERE id = $2 AND balance >= $1`,
+		rf.Amount,
+		rf.UserID,
+	)
+	if err != nil {
+		return rf, r.rollback(tx, fmt.Errorf("failed to debit refund: %w", err))
+	}
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		if err == nil {
+			err = entity.ErrInsufficientBalance
+		}
+		return rf, r.rollback(tx, err)
+	}
+
+	rf.Kind = entity.RefundTopUp
+	rf.Status = entity.RefundPending
+	err = tx.QueryRow(`
+        INSERT INTO refund (kind, original_id, user_id, amount, status, reason, created_by)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING id, created_at`,
+		rf.Kind,
+		rf.OriginalID,
+		rf.UserID,
+		rf.Amount,
+		rf.Status,
+		rf.Reason,
+		rf.CreatedBy,
+	).Scan(&rf.ID, &rf.CreatedAt)
+	if err != nil {
+		return rf, r.rollback(tx, fmt.Errorf("failed to insert refund: %w", err))
+	}
+
+	entry := entity.TopUpRefundEntry(rf.UserID, rf.Amount, RefundReference(rf.ID))
+	if _, err = r.postEntry(tx, entry); err != nil {
+		return rf, r.rollback(tx, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return rf, fmt.Errorf("failed to commit refund: %w", err)
+	}
+	return rf, nil
+}
+
+// FinishRefund фиксирует ответ шлюза по возврату пополнения. При отмене деньги
+// возвращаются на баланс обратной проводкой. Повторный вызов ничего не меняет
+func (r *PaymentRepository) FinishRefund(refundID int, status entity.RefundStatus, gatewayID string) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	var (
+		userID int
+		amount entity.Decimal
+	)
+	err = tx.QueryRow(`
+        UPDATE refund
+        SET status = $1, gateway_id = COALESCE(NULLIF($2, ''), gateway_id)
+        WHERE id = $3 AND status = $4
+        RETURNING user_id, amount`,
+		status,
+		gatewayID,
+		refundID,
+		entity.RefundPending,
+	).Scan(&userID, &amount)
+	if errors.Is(err, sql.ErrNoRows) {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return false, fmt.Errorf("rollback failed: %w", rbErr)
+		}
+		return false, nil
+	}
+	if err == nil && status == entity.RefundCanceled {
+		_, err = tx.Exec(`
+            UPDATE auth_user
+            SET balance = balance + $1
+            WHERE id = $2`,
+			amount,
+			userID,
+		)
+		if err == nil {
+			entry := entity.ReversalEntry(entity.TopUpRefundEntry(userID, amount, ""), RefundReference(refundID))
+			_, err = r.postEntry(tx, entry)
+		}
+	}
+	if err != nil {
+		return false, r.rollback(tx, fmt.Errorf("failed to finish refund %d: %w", refundID, err))
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit refund: %w", err)
+	}
+	return true, nil
+}
+
+// ReverseImpressionCharge сторнирует списание за показ: рекламодателю
+// возвращается полная цена, с владельца слота и площадки снимается их доля.
+// Одно списание можно отменить только один раз
+func (r *PaymentRepository) ReverseImpressionCharge(original entity.LedgerEntry, reason string, createdBy int) (entity.Refund, error) {
+	rf := entity.Refund{
+		Kind:       entity.RefundImpression,
+		OriginalID: strconv.FormatInt(original.ID, 10),
+		Status:     entity.RefundSucceeded,
+		Reason:     reason,
+		CreatedBy:  createdBy,
+	}
+	// оплаченная промо-кредитом часть показа возвращается на промо-остаток
+	var owners []int
+	deltas := make(map[int]int64)
+	promoDeltas := make(map[int]int64)
+	var charged int64
+	for _, p := range original.Postings {
+		kopecks, err := p.Amount.Kopecks()
+		if err != nil {
+			return rf, err
+		}
+		switch p.Account {
+		case entity.AccountAdvertiser, entity.AccountPromo:
+			rf.UserID = p.OwnerID
+			charged -= kopecks
+		case entity.AccountPublisher:
+			rf.PublisherID = p.OwnerID
+		}
+		if p.OwnerID > 0 {
+			if _, seen := deltas[p.OwnerID]; !seen {
+				owners = append(owners, p.OwnerID)
+				deltas[p.OwnerID] = 0
+			}
+			if p.Account == entity.AccountPromo {
+				promoDeltas[p.OwnerID] -= kopecks
+			} else {
+				deltas[p.OwnerID] -= kopecks
+			}
+		}
+	}
+	rf.Amount = entity.DecimalFromKopecks(charged)
+	if original.Kind != entity.EntryImpressionCharge || rf.UserID == 0 {
+		return rf, entity.ErrRefundNotAllowed
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return rf, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	err = tx.QueryRow(`
+        INSERT INTO refund (kind, original_id, user_id, publisher_id, amount, status, reason, created_by)
+        VALUES ($1, $2, $3, NULLIF($4, 0), $5, $6, $7, $8)
+        RETURNING id, created_at`,
+		rf.Kind,
+		rf.OriginalID,
+		rf.UserID,
+		rf.PublisherID,
+		rf.Amount,
+		rf.Status,
+		rf.Reason,
+		rf.CreatedBy,
+	).Scan(&rf.ID, &rf.CreatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return rf, r.rollback(tx, entity.ErrAlreadyReversed)
+	}
+	if err != nil {
+		return rf, r.rollback(tx, fmt.Errorf("failed to insert refund: %w", err))
+	}
+
+	for _, userID := range owners {
+		_, err = tx.Exec(`
+            UPDATE auth_user
+            SET balance = balance + $1, promo_balance = promo_balance + $3
+            WHERE id = $2`,
+			entity.DecimalFromKopecks(deltas[userID]),
+			userID,
+			entity.DecimalFromKopecks(promoDeltas[userID]),
+		)
+		if err != nil {
+			return rf, r.rollback(tx, fmt.Errorf("failed to apply reversal: %w", err))
+		}
+	}
+	if _, err = r.postEntry(tx, entity.ReversalEntry(original, RefundReference(rf.ID))); err != nil {
+		return rf, r.rollback(tx, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return rf, fmt.Errorf("failed to commit reversal: %w", err)
+	}
+	return rf, nil
+}
+
+func (r *PaymentRepository) GetRefundByGatewayID(gatewayID string) (entity.Refund, error) {
+	rf, err := scanRefund(r.db.QueryRow(`SELECT `+refundColumns+` FROM refund WHERE gateway_id = $1`, gatewayID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return rf, entity.ErrRefundNotFound
+	}
+	if err != nil {
+		return rf, fmt.Errorf("failed to get refund: %w", err)
+	}
+	return rf, nil
+}
+
+// GetRefunds возвращает возвраты по исходной операции
+func (r *PaymentRepository) GetRefunds(kind entity.RefundKind, originalID string) ([]entity.Refund, error) {
+	rows, err := r.db.Query(`SELECT `+refundColumns+`
+        FROM refund
+        WHERE kind = $1 AND original_id = $2
+        ORDER BY id`, kind, originalID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refunds: %w", err)
+	}
+	defer rows.Close()
+
+	list := make([]entity.Refund, 0)
+	for rows.Next() {
+		rf, err := scanRefund(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, rf)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,92 @@
+package repo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"retarget/internal/pay-service/entity"
+)
+
+func TestCreateTopUpRefund_ExceedsOriginal(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT amount\\s+FROM transaction").
+		WithArgs("pay1", 5).
+		WillReturnRows(sqlmock.NewRows([]string{"amount"}).AddRow("100.00"))
+	mock.ExpectQuery("FROM refund").
+		WithArgs(entity.RefundTopUp, "pay1", entity.RefundCanceled).
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow("80.00"))
+	mock.ExpectRollback()
+
+	_, err := r.CreateTopUpRefund(entity.Refund{OriginalID: "pay1", UserID: 5, Amount: entity.DecimalFromKopecks(3000), CreatedBy: 1})
+	assert.ErrorIs(t, err, entity.ErrRefundExceedsOriginal)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateTopUpRefund_DebitsBalance(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT amount\\s+FROM transaction").
+		WithArgs("pay1", 5).
+		WillReturnRows(sqlmock.NewRows([]string{"amount"}).AddRow("100.00"))
+	mock.ExpectQuery("FROM refund").
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow("0"))
+	mock.ExpectExec("UPDATE auth_user").
+		WithArgs("30.00", 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO refund").
+		WithArgs(entity.RefundTopUp, "pay1", 5, "30.00", entity.RefundPending, "", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, time.Now()))
+	expectPostEntry(mock, "refund", "refund:3", 2)
+	mock.ExpectCommit()
+
+	rf, err := r.CreateTopUpRefund(entity.Refund{OriginalID: "pay1", UserID: 5, Amount: entity.DecimalFromKopecks(3000), CreatedBy: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, rf.ID)
+	assert.Equal(t, entity.RefundPending, rf.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReverseImpressionCharge(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	original := entity.ImpressionChargeEntry(1, 2, entity.DecimalFromKopecks(1000), entity.DecimalFromKopecks(150), "h1")
+	original.ID = 42
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO refund").
+		WithArgs(entity.RefundImpression, "42", 1, 2, "10.00", entity.RefundSucceeded, "fraud", 9).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(4, time.Now()))
+	mock.ExpectExec("UPDATE auth_user").
+		WithArgs("10.00", 1, "0.00").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE auth_user").
+		WithArgs("-8.50", 2, "0.00").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectPostEntry(mock, "refund", "refund:4", 3)
+	mock.ExpectCommit()
+
+	rf, err := r.ReverseImpressionCharge(original, "fraud", 9)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, rf.UserID)
+	assert.Equal(t, 2, rf.PublisherID)
+	assert.Equal(t, "10.00", rf.Amount.String())
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO refund").
+		WillReturnError(&pq.Error{Code: "23505"})
+	mock.ExpectRollback()
+
+	_, err = r.ReverseImpressionCharge(original, "fraud", 9)
+	assert.ErrorIs(t, err, entity.ErrAlreadyReversed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,102 @@
+package repo
+
+import (
+	"fmt"
+	"time"
+
+	"retarget/internal/pay-service/entity"
+)
+
+// SettleTransaction переводит ожидающую транзакцию в итоговый статус и в той же
+// транзакции БД применяет изменение баланса с проводкой. Если транзакция уже
+// не в статусе ожидания, ничего не меняется и возвращается false — повторные
+// уведомления и проход фоновой сверки безопасны
+func (r *PaymentRepository) SettleTransaction(transactionID string, status int, userID int, delta entity.Decimal, entry *entity.LedgerEntry) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	res, err := tx.Exec(`
+        UPDATE transaction
+        SET status = $1
+        WHERE transaction_id = $2 AND status = '0'`,
+		status,
+		transactionID,
+	)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			err = fmt.Errorf("rollback failed: %v; original error: %w", rbErr, err)
+		}
+		return false, fmt.Errorf("failed to update transaction status: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil || affected == 0 {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return false, fmt.Errorf("rollback failed: %w", rbErr)
+		}
+		return false, err
+	}
+
+	if entry != nil {
+		_, err = tx.Exec(`
+            UPDATE auth_user
+            SET balance = balance + $1
+            WHERE id = $2`,
+			delta,
+			userID,
+		)
+		if err == nil {
+			_, err = r.postEntry(tx, *entry)
+		}
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				err = fmt.Errorf("rollback failed: %v; original error: %w", rbErr, err)
+			}
+			return false, fmt.Errorf("failed to apply settlement: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit settlement: %w", err)
+	}
+	return true, nil
+}
+
+// ClaimStalePendingTransactions отмечает проверенными и возвращает транзакции,
+// которые висят в ожидании дольше olderThan: по ним уведомление могло
+// потеряться. Транзакция, проверенная после olderThan, пропускается, а первыми
+// идут те, что дольше всех не проверялись, — зависшие у шлюза платежи не
+// занимают всю пачку
+func (r *PaymentRepository) ClaimStalePendingTransactions(olderThan time.Time, limit int) ([]entity.Transaction, error) {
+	const q = `
+        UPDATE transaction
+        SET swept_at = (now() AT TIME ZONE 'UTC')
+        WHERE id IN (
+            SELECT id FROM transaction
+            WHERE status = '0' AND created_at < $1 AND (swept_at IS NULL OR swept_at < $1)
+            ORDER BY swept_at NULLS FIRST, created_at
+            LIMIT $2
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING ` + transactionColumns
+
+	rows, err := r.db.Query(q, olderThan, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stale transactions: %w", err)
+	}
+	defer rows.Close()
+
+	list := make([]entity.Transaction, 0)
+	for rows.Next() {
+		tx, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, tx)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,75 @@
+package repo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"retarget/internal/pay-service/entity"
+)
+
+func TestSettleTransaction_AppliesOnce(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	entry := entity.TopUpEntry(5, entity.DecimalFromKopecks(1000), "tx1")
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE transaction").
+		WithArgs(entity.TransactionSucceeded, "tx1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE auth_user").
+		WithArgs("10.00", 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectPostEntry(mock, "topup", "tx1", 2)
+	mock.ExpectCommit()
+
+	applied, err := r.SettleTransaction("tx1", entity.TransactionSucceeded, 5, entity.DecimalFromKopecks(1000), &entry)
+	assert.NoError(t, err)
+	assert.True(t, applied)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE transaction").
+		WithArgs(entity.TransactionSucceeded, "tx1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	applied, err = r.SettleTransaction("tx1", entity.TransactionSucceeded, 5, entity.DecimalFromKopecks(1000), &entry)
+	assert.NoError(t, err)
+	assert.False(t, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSettleTransaction_StatusOnly(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE transaction").
+		WithArgs(entity.TransactionCanceled, "tx2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	applied, err := r.SettleTransaction("tx2", entity.TransactionCanceled, 5, entity.Decimal{}, nil)
+	assert.NoError(t, err)
+	assert.True(t, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClaimStalePendingTransactions(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	cutoff := time.Now()
+	mock.ExpectQuery(`SET swept_at[\s\S]+WHERE status = '0' AND created_at < \$1 AND \(swept_at IS NULL OR swept_at < \$1\)\s+ORDER BY swept_at NULLS FIRST, created_at`).
+		WithArgs(cutoff, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "transaction_id", "user_id", "amount", "type", "status", "created_at", "currency"}).
+			AddRow(1, "tx1", 5, "10.00", "yoomoney_payment", "0", cutoff, "RUB"))
+
+	list, err := r.ClaimStalePendingTransactions(cutoff, 100)
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, "payments", list[0].YooObjectType())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,175 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"retarget/internal/pay-service/entity"
+)
+
+const statementColumns = `id, user_id, period_start, period_end, opening_balance, closing_balance, object_prefix, created_at`
+
+func scanStatement(row rowScanner) (entity.StatementInfo, error) {
+	var s entity.StatementInfo
+	err := row.Scan(&s.ID, &s.UserID, &s.PeriodStart, &s.PeriodEnd, &s.OpeningBalance, &s.ClosingBalance, &s.ObjectPrefix, &s.CreatedAt)
+	return s, err
+}
+
+// GetStatementRows собирает обороты пользователя за [from, to) по видам
+// проводок, счетам, размещениям и валютам. Для пересчитанных проводок
+// Counter* — сумма в другой валюте пересчёта: исходная ставка или обменянные деньги.
+// Промо-счёт сводится с основным счётом рекламодателя: показ, оплаченный частично
+// промо-кредитом, считается одним показом
+func (r *PaymentRepository) GetStatementRows(ownerID int, from, to time.Time) ([]entity.StatementRow, error) {
+	const query = `
+        SELECT e.kind, p.type, p.currency, COALESCE(pl.banner_id, 0), COALESCE(pl.slot_link, ''),
+               COALESCE(CASE WHEN c.from_currency = p.currency THEN c.currency ELSE c.from_currency END, '') AS counter_currency,
+               COUNT(*), SUM(p.amount),
+               COALESCE(SUM(CASE WHEN c.from_currency = p.currency THEN c.amount ELSE c.original_amount END), 0)
+        FROM (
+            SELECT p.entry_id, CASE WHEN a.type = 'promo' THEN 'advertiser' ELSE a.type END AS type,
+                   a.currency, SUM(p.amount) AS amount
+            FROM ledger_posting p
+            JOIN ledger_account a ON a.id = p.account_id
+            WHERE a.owner_id = $1
+            GROUP BY p.entry_id, 2, a.currency
+        ) p
+        JOIN ledger_entry e ON e.id = p.entry_id
+        LEFT JOIN ledger_entry_placement pl ON pl.entry_id = e.id
+        LEFT JOIN ledger_entry_conversion c ON c.entry_id = e.id
+        WHERE e.created_at >= $2 AND e.created_at < $3
+        GROUP BY e.kind, p.type, p.currency, pl.banner_id, pl.slot_link, 6
+        ORDER BY e.kind, p.type, p.currency, 4, 5, 6`
+
+	rows, err := r.db.Query(query, ownerID, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statement rows: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]entity.StatementRow, 0)
+	for rows.Next() {
+		var row entity.StatementRow
+		if err := rows.Scan(&row.Kind, &row.Account, &row.Currency, &row.BannerID, &row.SlotLink,
+			&row.CounterCurrency, &row.Count, &row.Amount, &row.CounterAmount); err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetLedgerBalanceAt выводит баланс пользователя в базовой валюте из журнала на момент at
+func (r *PaymentRepository) GetLedgerBalanceAt(ownerID int, at time.Time) (entity.Decimal, error) {
+	const query = `
+        SELECT COALESCE(SUM(p.amount), 0)
+        FROM ledger_posting p
+        JOIN ledger_account a ON a.id = p.account_id
+        JOIN ledger_entry e ON e.id = p.entry_id
+        WHERE a.owner_id = $1 AND a.currency = $3 AND e.created_at < $2`
+
+	var balance entity.Decimal
+	if err := r.db.QueryRow(query, ownerID, at.UTC(), entity.BaseCurrency).Scan(&balance); err != nil {
+		return entity.Decimal{}, fmt.Errorf("failed to get ledger balance: %w", err)
+	}
+	return balance, nil
+}
+
+// GetStatementOwners возвращает пользователей, которым нужна выписка за [from, to):
+// у них были движения в периоде или ненулевой остаток, а выписки ещё нет
+func (r *PaymentRepository) GetStatementOwners(from, to time.Time) ([]int, error) {
+	const query = `
+        SELECT a.owner_id
+        FROM ledger_posting p
+        JOIN ledger_account a ON a.id = p.account_id
+        JOIN ledger_entry e ON e.id = p.entry_id
+        WHERE a.owner_id IS NOT NULL AND e.created_at < $2
+          AND NOT EXISTS (
+              SELECT 1 FROM statement s WHERE s.user_id = a.owner_id AND s.period_start = $1
+          )
+        GROUP BY a.owner_id
+        HAVING bool_or(e.created_at >= $1) OR SUM(p.amount) <> 0
+        ORDER BY a.owner_id`
+
+	rows, err := r.db.Query(query, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statement owners: %w", err)
+	}
+	defer rows.Close()
+
+	owners := make([]int, 0)
+	for rows.Next() {
+		var ownerID int
+		if err := rows.Scan(&ownerID); err != nil {
+			return nil, err
+		}
+		owners = append(owners, ownerID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return owners, nil
+}
+
+// SaveStatement записывает выписку; повторная генерация за тот же период
+// заменяет предыдущую
+func (r *PaymentRepository) SaveStatement(s entity.Statement, objectPrefix string) (entity.StatementInfo, error) {
+	query := `
+        INSERT INTO statement (user_id, period_start, period_end, opening_balance, closing_balance, object_prefix)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (user_id, period_start) DO UPDATE
+        SET period_end = EXCLUDED.period_end,
+            opening_balance = EXCLUDED.opening_balance,
+            closing_balance = EXCLUDED.closing_balance,
+            object_prefix = EXCLUDED.object_prefix,
+            created_at = now() AT TIME ZONE 'UTC'
+        RETURNING ` + statementColumns
+
+	info, err := scanStatement(r.db.QueryRow(query,
+		s.UserID, s.PeriodStart.UTC(), s.PeriodEnd.UTC(), s.OpeningBalance, s.ClosingBalance, objectPrefix))
+	if err != nil {
+		return entity.StatementInfo{}, fmt.Errorf("failed to save statement: %w", err)
+	}
+	return info, nil
+}
+
+func (r *PaymentRepository) GetStatement(statementID int) (entity.StatementInfo, error) {
+	query := `SELECT ` + statementColumns + ` FROM statement WHERE id = $1`
+
+	info, err := scanStatement(r.db.QueryRow(query, statementID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.StatementInfo{}, entity.ErrStatementNotFound
+		}
+		return entity.StatementInfo{}, fmt.Errorf("failed to get statement: %w", err)
+	}
+	return info, nil
+}
+
+func (r *PaymentRepository) GetStatements(userID int) ([]entity.StatementInfo, error) {
+	query := `SELECT ` + statementColumns + ` FROM statement WHERE user_id = $1 ORDER BY period_start DESC`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statements: %w", err)
+	}
+	defer rows.Close()
+
+	statements := make([]entity.StatementInfo, 0)
+	for rows.Next() {
+		info, err := scanStatement(rows)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, info)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return statements, nil
+}
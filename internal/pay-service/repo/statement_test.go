@@ -0,0 +1,83 @@
+package repo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"retarget/internal/pay-service/entity"
+)
+
+func TestGetStatementRows(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	from, to := entity.StatementPeriod(time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC))
+	mock.ExpectQuery("LEFT JOIN ledger_entry_placement").
+		WithArgs(5, from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"kind", "type", "currency", "banner_id", "slot_link", "counter_currency", "count", "sum", "counter_sum"}).
+			AddRow("impression_charge", "advertiser", "RUB", 7, "", "USD", 3, "-9.00", "0.10").
+			AddRow("topup", "advertiser", "RUB", 0, "", "", 1, "500.00", "0"))
+
+	rows, err := r.GetStatementRows(5, from, to)
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, entity.EntryImpressionCharge, rows[0].Kind)
+	assert.Equal(t, int64(7), rows[0].BannerID)
+	assert.Equal(t, 3, rows[0].Count)
+	assert.Equal(t, "-9.00", rows[0].Amount.String())
+	assert.Equal(t, "USD", rows[0].CounterCurrency)
+	assert.Equal(t, "0.10", rows[0].CounterAmount.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveStatement_Upserts(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	from, to := entity.StatementPeriod(time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC))
+	s := entity.Statement{UserID: 5, PeriodStart: from, PeriodEnd: to,
+		OpeningBalance: entity.DecimalFromKopecks(1000), ClosingBalance: entity.DecimalFromKopecks(2000)}
+	mock.ExpectQuery("INSERT INTO statement").
+		WithArgs(5, from, to, "10.00", "20.00", "5/2025-04").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "period_start", "period_end", "opening_balance", "closing_balance", "object_prefix", "created_at"}).
+			AddRow(3, 5, from, to, "10.00", "20.00", "5/2025-04", to))
+
+	info, err := r.SaveStatement(s, "5/2025-04")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, info.ID)
+	assert.Equal(t, "5/2025-04.pdf", info.ObjectName(entity.StatementPDF))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetStatement_NotFound(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	mock.ExpectQuery("FROM statement WHERE id").
+		WithArgs(9).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := r.GetStatement(9)
+	assert.ErrorIs(t, err, entity.ErrStatementNotFound)
+}
+
+func TestPostEntry_WritesPlacement(t *testing.T) {
+	r, mock, close := setup()
+	defer close()
+
+	entry := entity.ImpressionChargeEntry(1, 2, entity.DecimalFromKopecks(300), entity.Decimal{}, "h1").
+		WithPlacement(entity.Placement{BannerID: 7, SlotLink: "slot-a"})
+	mock.ExpectBegin()
+	expectPostEntry(mock, "impression_charge", "h1", 2)
+	mock.ExpectExec("INSERT INTO ledger_entry_placement").
+		WithArgs(int64(1), int64(7), "slot-a").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	_, err := r.PostEntry(entry)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
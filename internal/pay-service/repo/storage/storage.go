@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+var ErrObjectNotFound = errors.New("object not found")
+
+type StatementStorageInterface interface {
+	Upload(objectName, contentType string, data []byte) error
+	Download(objectName string) ([]byte, error)
+}
+
+// StatementStorage хранит файлы выписок в MinIO
+type StatementStorage struct {
+	minioClient *minio.Client
+	bucketName  string
+}
+
+func NewStatementStorage(endpoint, accessKeyID, secretAccessKey, token string, useSSL bool, bucketName string) *StatementStorage {
+	minioClient, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKeyID, secretAccessKey, token),
+		Secure: useSSL,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	storage := &StatementStorage{minioClient: minioClient, bucketName: bucketName}
+	if err := storage.createBucket(); err != nil {
+		log.Fatal(err)
+	}
+	return storage
+}
+
+func (s *StatementStorage) createBucket() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := s.minioClient.MakeBucket(ctx, s.bucketName, minio.MakeBucketOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "BucketAlreadyOwnedByYou" {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *StatementStorage) Upload(objectName, contentType string, data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := s.minioClient.PutObject(ctx, s.bucketName, objectName, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", objectName, err)
+	}
+	return nil
+}
+
+func (s *StatementStorage) Download(objectName string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	object, err := s.minioClient.GetObject(ctx, s.bucketName, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", objectName, err)
+	}
+	defer object.Close()
+
+	data, err := io.ReadAll(object)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", objectName, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,351 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"retarget/internal/pay-service/entity"
+	"retarget/internal/pay-service/repo/gateway"
+
+	"github.com/cenkalti/backoff"
+	"github.com/google/uuid"
+)
+
+// autoRechargeTTL — сколько живёт закэшированное правило автопополнения:
+// проверка идёт на каждом показе, а меняется правило редко
+const autoRechargeTTL = time.Minute
+
+// errAutoRechargeCanceled — причина неудачи, если шлюз отменил списание
+var errAutoRechargeCanceled = errors.New("payment was canceled by the gateway")
+
+type cachedAutoRecharge struct {
+	recharge  entity.AutoRecharge
+	found     bool
+	expiresAt time.Time
+}
+
+func (uc *PaymentUsecase) rememberAutoRecharge(a entity.AutoRecharge, found bool) {
+	if uc.autoRecharges == nil {
+		return
+	}
+	uc.autoRecharges.Store(a.UserID, cachedAutoRecharge{recharge: a, found: found, expiresAt: time.Now().Add(autoRechargeTTL)})
+}
+
+func (uc *PaymentUsecase) forgetAutoRecharge(userID int) {
+	if uc.autoRecharges != nil {
+		uc.autoRecharges.Delete(userID)
+	}
+}
+
+func (uc *PaymentUsecase) cachedAutoRecharge(userID int) (entity.AutoRecharge, bool, error) {
+	if uc.autoRecharges != nil {
+		if cached, ok := uc.autoRecharges.Load(userID); ok {
+			entry := cached.(cachedAutoRecharge)
+			if time.Now().Before(entry.expiresAt) {
+				return entry.recharge, entry.found, nil
+			}
+		}
+	}
+
+	a, err := uc.PaymentRepository.GetAutoRecharge(userID, time.Now())
+	if errors.Is(err, entity.ErrAutoRechargeNotFound) {
+		a.UserID = userID
+		uc.rememberAutoRecharge(a, false)
+		return a, false, nil
+	}
+	if err != nil {
+		return a, false, err
+	}
+	uc.rememberAutoRecharge(a, true)
+	return a, true, nil
+}
+
+// maybeAutoRecharge запускает списание сохранённым способом оплаты, если
+// баланс опустился ниже порога правила. Само списание идёт в фоне, а
+// повторный запуск отсекает резерв в StartAutoRecharge
+func (uc *PaymentUsecase) maybeAutoRecharge(userID int, balance entity.Decimal) {
+	if uc.Gateway == nil {
+		return
+	}
+	a, found, err := uc.cachedAutoRecharge(userID)
+	if err != nil {
+		uc.logger.Errorw("failed to get auto-recharge",
+			"user_id", userID,
+			"error", err)
+		return
+	}
+	if !found || !a.Due(balance, time.Now()) {
+		return
+	}
+
+	key := uuid.NewString()
+	// до ответа базы следующие показы видят правило занятым
+	a.PendingTransactionID = key
+	uc.rememberAutoRecharge(a, true)
+	go uc.runAutoRecharge(userID, key)
+}
+
+func (uc *PaymentUsecase) runAutoRecharge(userID int, key string) {
+	defer func() {
+		if r := recover(); r != nil {
+			uc.logger.Errorw("error/panic in runAutoRecharge",
+				"recovered", r,
+				"user_id", userID)
+		}
+	}()
+
+	a, err := uc.PaymentRepository.StartAutoRecharge(userID, key, time.Now())
+	switch {
+	case errors.Is(err, entity.ErrAutoRechargeCap):
+		uc.rememberAutoRecharge(a, true)
+		uc.notifyAutoRechargeFailed(a, err.Error())
+		return
+	case errors.Is(err, entity.ErrAutoRechargeNotDue):
+		uc.rememberAutoRecharge(a, true)
+		return
+	case err != nil:
+		uc.forgetAutoRecharge(userID)
+		uc.logger.Errorw("failed to start auto-recharge",
+			"user_id", userID,
+			"error", err)
+		return
+	}
+	uc.rememberAutoRecharge(a, true)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	backoffConfig := backoff.NewExponentialBackOff()
+	backoffConfig.MaxElapsedTime = 1 * time.Minute
+
+	// ключ идемпотентности один на все повторы, шлюз не спишет дважды
+	var out *gateway.Payment
+	charge := func() error {
+		select {
+		case <-ctx.Done():
+			return backoff.Permanent(ctx.Err())
+		default:
+		}
+		var err error
+		out, err = uc.chargeAutoRecharge(a, key)
+		if errors.Is(err, gateway.ErrNotFound) {
+			return backoff.Permanent(err)
+		}
+		return err
+	}
+	if err := backoff.Retry(charge, backoff.WithMaxRetries(backoffConfig, 5)); err != nil {
+		uc.logger.Errorw("auto-recharge charge failed",
+			"user_id", userID,
+			"error", err)
+		uc.finishAutoRecharge(userID, key, a.Amount, err.Error())
+		return
+	}
+
+	uc.attachAutoRecharge(a, key, out)
+}
+
+// chargeAutoRecharge списывает сумму правила сохранённым способом оплаты.
+// Повтор с тем же key вернёт уже созданный платёж
+func (uc *PaymentUsecase) chargeAutoRecharge(a entity.AutoRecharge, key string) (*gateway.Payment, error) {
+	return uc.Gateway.CreatePayment(gateway.PaymentRequest{
+		Amount:          gateway.Amount{Value: a.Amount.Money().String(), Currency: entity.BaseCurrency},
+		Description:     "Автопополнение баланса",
+		IdempotenceKey:  key,
+		PaymentMethodID: a.PaymentMethodID,
+	})
+}
+
+// attachAutoRecharge сохраняет платёж out вместо резерва key и проводит его,
+// если шлюз уже сообщил итог
+func (uc *PaymentUsecase) attachAutoRecharge(a entity.AutoRecharge, key string, out *gateway.Payment) {
+	amount, err := entity.ParseAmount(out.Amount.Value)
+	if err != nil {
+		amount = a.Amount
+	}
+	trx := entity.Transaction{
+		TransactionID: out.ID,
+		UserID:        a.UserID,
+		Amount:        amount,
+		Type:          entity.TransactionAutoRecharge,
+		Status:        mapYooStatus(out.Status),
+		Currency:      entity.BaseCurrency,
+	}
+	if err := uc.PaymentRepository.AttachAutoRechargeTransaction(key, trx); err != nil {
+		// резерв не снимаем: деньги, возможно, уже списаны, и второе
+		// списание хуже пропущенного. Резерв подберёт SweepStaleAutoRecharges
+		uc.logger.Errorw("failed to save auto-recharge transaction",
+			"user_id", a.UserID,
+			"transaction_id", out.ID,
+			"pending_id", key,
+			"error", err)
+		return
+	}
+	uc.forgetAutoRecharge(a.UserID)
+
+	if trx.Status == entity.TransactionSucceeded || trx.Status == entity.TransactionCanceled {
+		if _, err := uc.SettleTransaction(trx, trx.Status); err != nil {
+			uc.logger.Errorw("failed to settle auto-recharge",
+				"user_id", a.UserID,
+				"transaction_id", trx.TransactionID,
+				"error", err)
+		}
+	}
+}
+
+// SweepStaleAutoRecharges доводит до конца резервы, к которым так и не
+// привязалась транзакция списания. Запрос в шлюз повторяется с ключом резерва:
+// если платёж уже создан, шлюз вернёт его, а не спишет деньги второй раз
+func (uc *PaymentUsecase) SweepStaleAutoRecharges(olderThan time.Time) error {
+	if uc.Gateway == nil {
+		return nil
+	}
+	stale, err := uc.PaymentRepository.GetStaleAutoRecharges(olderThan.UTC(), pendingSweepBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range stale {
+		key := a.PendingTransactionID
+		out, err := uc.chargeAutoRecharge(a, key)
+		if errors.Is(err, gateway.ErrNotFound) || errors.Is(err, gateway.ErrRejected) {
+			uc.finishAutoRecharge(a.UserID, key, a.Amount, err.Error())
+			continue
+		}
+		if err != nil {
+			uc.logger.Warnw("failed to resolve stale auto-recharge",
+				"user_id", a.UserID,
+				"pending_id", key,
+				"error", err)
+			continue
+		}
+		uc.attachAutoRecharge(a, key, out)
+	}
+	return nil
+}
+
+// finishAutoRecharge завершает списание pendingID; пустой failure — успех
+func (uc *PaymentUsecase) finishAutoRecharge(userID int, pendingID string, amount entity.Decimal, failure string) {
+	a, finished, err := uc.PaymentRepository.FinishAutoRecharge(userID, pendingID, failure, time.Now())
+	if err != nil {
+		uc.forgetAutoRecharge(userID)
+		uc.logger.Errorw("failed to finish auto-recharge",
+			"user_id", userID,
+			"pending_id", pendingID,
+			"error", err)
+		return
+	}
+	uc.rememberAutoRecharge(a, true)
+	if !finished || failure == "" {
+		return
+	}
+
+	uc.logger.Warnw("auto-recharge failed",
+		"user_id", userID,
+		"failures", a.Failures,
+		"enabled", a.Enabled,
+		"reason", failure)
+	a.Amount = amount
+	uc.notifyAutoRechargeFailed(a, failure)
+}
+
+func (uc *PaymentUsecase) notifyAutoRechargeFailed(a entity.AutoRecharge, reason string) {
+	if uc.NoticeRepository == nil {
+		return
+	}
+	if err := uc.NoticeRepository.SendAutoRechargeFailedEvent(a.UserID, a.Amount, reason); err != nil {
+		uc.logger.Errorw("failed to send auto-recharge failure notice",
+			"user_id", a.UserID,
+			"error", err)
+	}
+}
+
+// settleAutoRecharge завершает правило по итогу транзакции автопополнения
+func (uc *PaymentUsecase) settleAutoRecharge(trx entity.Transaction, status int) {
+	failure := ""
+	if status == entity.TransactionCanceled {
+		failure = errAutoRechargeCanceled.Error()
+	}
+	uc.finishAutoRecharge(trx.UserID, trx.TransactionID, trx.Amount, failure)
+}
+
+// activatePaymentMethod разрешает списания способом, сохранённым при пополнении trx
+func (uc *PaymentUsecase) activatePaymentMethod(trx entity.Transaction) {
+	m, activated, err := uc.PaymentRepository.ActivatePaymentMethod(trx.TransactionID)
+	if err != nil {
+		uc.logger.Errorw("failed to activate payment method",
+			"transaction_id", trx.TransactionID,
+			"error", err)
+		return
+	}
+	if activated {
+		uc.logger.Infow("payment method saved",
+			"user_id", m.UserID,
+			"payment_method_id", m.ID)
+	}
+}
+
+func (uc *PaymentUsecase) GetAutoRecharge(userID int) (entity.AutoRecharge, error) {
+	return uc.PaymentRepository.GetAutoRecharge(userID, time.Now())
+}
+
+// SetAutoRecharge сохраняет правило. Без явного способа оплаты берётся
+// последний сохранённый
+func (uc *PaymentUsecase) SetAutoRecharge(userID int, a entity.AutoRecharge) (entity.AutoRecharge, error) {
+	a.UserID = userID
+	a.Threshold = a.Threshold.Money()
+	a.Amount = a.Amount.Money()
+	a.MonthlyCap = a.MonthlyCap.Money()
+	if err := a.Validate(); err != nil {
+		return a, err
+	}
+
+	methods, err := uc.PaymentRepository.GetPaymentMethods(userID)
+	if err != nil {
+		return a, err
+	}
+	if a.PaymentMethodID == "" && len(methods) > 0 {
+		a.PaymentMethodID = methods[0].ID
+	}
+	known := false
+	for _, m := range methods {
+		if m.ID == a.PaymentMethodID {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return a, entity.ErrNoPaymentMethod
+	}
+
+	if err := uc.PaymentRepository.SaveAutoRecharge(a); err != nil {
+		return a, err
+	}
+	uc.forgetAutoRecharge(userID)
+	return uc.GetAutoRecharge(userID)
+}
+
+func (uc *PaymentUsecase) DisableAutoRecharge(userID int) error {
+	a, err := uc.PaymentRepository.GetAutoRecharge(userID, time.Now())
+	if err != nil {
+		return err
+	}
+	a.Enabled = false
+	if err := uc.PaymentRepository.SaveAutoRecharge(a); err != nil {
+		return err
+	}
+	uc.forgetAutoRecharge(userID)
+	return nil
+}
+
+func (uc *PaymentUsecase) GetPaymentMethods(userID int) ([]entity.PaymentMethod, error) {
+	return uc.PaymentRepository.GetPaymentMethods(userID)
+}
+
+func (uc *PaymentUsecase) DeletePaymentMethod(userID int, methodID string) error {
+	if err := uc.PaymentRepository.DeletePaymentMethod(userID, methodID); err != nil {
+		return err
+	}
+	uc.forgetAutoRecharge(userID)
+	return nil
+}
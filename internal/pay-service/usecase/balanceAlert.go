@@ -0,0 +1,162 @@
+package payment
+
+import (
+	"context"
+	"time"
+
+	"retarget/internal/pay-service/entity"
+
+	"github.com/cenkalti/backoff"
+)
+
+// balanceAlertTTL — сколько живут закэшированные настройки уведомлений:
+// читаются они на каждом показе, а сохранение сбрасывает кэш только своей реплики
+const balanceAlertTTL = time.Minute
+
+type cachedBalanceAlerts struct {
+	settings  entity.BalanceAlertSettings
+	expiresAt time.Time
+}
+
+func (uc *PaymentUsecase) balanceAlertSettings(userID int) (entity.BalanceAlertSettings, error) {
+	if uc.balanceAlerts != nil {
+		if cached, ok := uc.balanceAlerts.Load(userID); ok {
+			entry := cached.(cachedBalanceAlerts)
+			if time.Now().Before(entry.expiresAt) {
+				return entry.settings, nil
+			}
+		}
+	}
+
+	settings, err := uc.PaymentRepository.GetBalanceAlertSettings(userID)
+	if err != nil {
+		return settings, err
+	}
+	if uc.balanceAlerts != nil {
+		uc.balanceAlerts.Store(userID, cachedBalanceAlerts{settings: settings, expiresAt: time.Now().Add(balanceAlertTTL)})
+	}
+	return settings, nil
+}
+
+// GetBalanceAlerts отдаёт настройки уведомлений о низком балансе; profile-service
+// показывает и меняет их через gRPC
+func (uc *PaymentUsecase) GetBalanceAlerts(userID int) (entity.BalanceAlertSettings, error) {
+	return uc.PaymentRepository.GetBalanceAlertSettings(userID)
+}
+
+// SetBalanceAlerts проверяет и сохраняет пороги уведомлений о низком балансе
+func (uc *PaymentUsecase) SetBalanceAlerts(userID int, settings entity.BalanceAlertSettings) (entity.BalanceAlertSettings, error) {
+	settings = settings.Normalize()
+	if err := settings.Validate(); err != nil {
+		return entity.BalanceAlertSettings{}, err
+	}
+	if err := uc.PaymentRepository.SaveBalanceAlertSettings(userID, settings); err != nil {
+		return entity.BalanceAlertSettings{}, err
+	}
+	if uc.balanceAlerts != nil {
+		uc.balanceAlerts.Delete(userID)
+	}
+	return settings, nil
+}
+
+// checkBalance сверяет баланс с настройками пользователя: errTooLittleBalance
+// означает, что баланс дошёл до порога уведомления или до порога автопаузы
+func (uc *PaymentUsecase) checkBalance(userID int) (entity.Decimal, entity.BalanceAlertSettings, error) {
+	balance, err := uc.availableBalance(userID, "UNIMPLEMENTED request_id")
+	if err != nil {
+		return balance, entity.BalanceAlertSettings{}, err
+	}
+	settings, err := uc.balanceAlertSettings(userID)
+	if err != nil {
+		return balance, settings, err
+	}
+	if len(settings.Crossed(balance)) > 0 || settings.ShouldPause(balance) {
+		return balance, settings, errTooLittleBalance
+	}
+	return balance, settings, nil
+}
+
+// notifyLowBalance отправляет одно уведомление на пересечение порога: пороги
+// отмечаются в Postgres, и повторные показы ниже того же порога ничего не шлют,
+// а после пополнения отметки снимаются и следующее пересечение снова уведомит.
+// Если баланс проскочил сразу несколько порогов, уведомление идёт по нижнему
+func (uc *PaymentUsecase) notifyLowBalance(userID int, balance entity.Decimal, crossed []entity.Decimal) {
+	if uc.NoticeRepository == nil || len(crossed) == 0 {
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			uc.logger.Errorw("error/panic in notifyLowBalance",
+				"recovered", r,
+				"user_id", userID)
+		}
+	}()
+
+	marked, err := uc.PaymentRepository.MarkBalanceAlertsCrossed(userID, crossed)
+	if err != nil {
+		uc.logger.Errorw("failed to mark balance alerts",
+			"user_id", userID,
+			"error", err)
+		return
+	}
+	if len(marked) == 0 {
+		return
+	}
+	threshold := marked[0]
+	for _, t := range marked[1:] {
+		if t.Cmp(threshold) < 0 {
+			threshold = t
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	backoffConfig := backoff.NewExponentialBackOff()
+	backoffConfig.MaxElapsedTime = 1 * time.Minute
+
+	notify := func() error {
+		select {
+		case <-ctx.Done():
+			return backoff.Permanent(ctx.Err())
+		default:
+			return uc.NoticeRepository.SendLowBalanceNotification(userID, threshold, balance)
+		}
+	}
+
+	if err := backoff.Retry(notify, backoff.WithMaxRetries(backoffConfig, 9)); err != nil {
+		uc.logger.Errorw("notification failed after retries",
+			"user_id", userID,
+			"threshold", threshold.String(),
+			"error", err)
+
+		if err := uc.PaymentRepository.ClearBalanceAlerts(userID, marked); err != nil {
+			uc.logger.Errorw("failed to clear balance alerts",
+				"user_id", userID,
+				"error", err)
+		}
+		return
+	}
+
+	uc.logger.Infow("low balance notification sent",
+		"user_id", userID,
+		"threshold", threshold.String(),
+		"balance", balance.String())
+}
+
+// resetBalanceAlerts после пополнения снова включает уведомления по порогам ниже баланса
+func (uc *PaymentUsecase) resetBalanceAlerts(userID int) {
+	balance, err := uc.availableBalance(userID, "")
+	if err != nil {
+		uc.logger.Errorw("failed to get balance to reset alerts",
+			"user_id", userID,
+			"error", err)
+		return
+	}
+	if err := uc.PaymentRepository.ResetBalanceAlerts(userID, balance); err != nil {
+		uc.logger.Errorw("failed to reset balance alerts after top up",
+			"user_id", userID,
+			"error", err)
+	}
+}
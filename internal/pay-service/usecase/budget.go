@@ -0,0 +1,237 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"retarget/internal/pay-service/entity"
+	"retarget/internal/pay-service/repo/budget"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	BudgetHoldTTL       = 2 * time.Minute
+	BudgetFlushInterval = 2 * time.Second
+	budgetLoadRetries   = 3
+)
+
+var ErrInsufficientFunds = budget.ErrInsufficientFunds
+
+// ReserveSpend холдит цену показа на балансе рекламодателя до того, как
+// баннер будет отдан в слот. Пустой идентификатор без ошибки означает,
+// что бюджетный слой не подключен и резервировать нечего
+func (uc *PaymentUsecase) ReserveSpend(advertiserID, publisherID int, amount entity.Decimal, placement entity.Placement) (string, error) {
+	if uc.BudgetRepository == nil {
+		return "", nil
+	}
+
+	kopecks, err := amount.Kopecks()
+	if err != nil {
+		return "", err
+	}
+	if kopecks <= 0 {
+		return "", fmt.Errorf("%w: %s", errInvalidPrice, amount.String())
+	}
+
+	feeKopecks, err := uc.impressionFee(publisherID, amount)
+	if err != nil {
+		return "", err
+	}
+
+	holdID := uuid.NewString()
+	reserve := func() error {
+		_, err := uc.BudgetRepository.Reserve(holdID, advertiserID, publisherID, kopecks, feeKopecks, placement.BannerID, placement.SlotLink, compactConversion(placement))
+		return err
+	}
+	if err := uc.withBudgetLoaded(advertiserID, reserve); err != nil {
+		if errors.Is(err, budget.ErrInsufficientFunds) {
+			go uc.offBannersByUserID(context.Background(), advertiserID)
+		}
+		return "", err
+	}
+	return holdID, nil
+}
+
+// chargeThroughBudget списывает показ из кэша бюджета: сначала пытается
+// закрыть ранее взятый холд, иначе списывает с проверкой остатка
+func (uc *PaymentUsecase) chargeThroughBudget(advertiserID, publisherID int, amount entity.Decimal, reservationID string, placement entity.Placement) error {
+	if reservationID != "" {
+		_, err := uc.BudgetRepository.Capture(reservationID)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, budget.ErrHoldNotFound) {
+			return err
+		}
+	}
+
+	kopecks, err := amount.Kopecks()
+	if err != nil {
+		return err
+	}
+	feeKopecks, err := uc.impressionFee(publisherID, amount)
+	if err != nil {
+		return err
+	}
+	charge := func() error {
+		_, err := uc.BudgetRepository.Charge(advertiserID, publisherID, kopecks, feeKopecks, placement.BannerID, placement.SlotLink, compactConversion(placement))
+		return err
+	}
+	return uc.withBudgetLoaded(advertiserID, charge)
+}
+
+func compactConversion(placement entity.Placement) string {
+	if placement.Conversion == nil {
+		return ""
+	}
+	return placement.Conversion.Compact()
+}
+
+func (uc *PaymentUsecase) impressionFee(publisherID int, amount entity.Decimal) (int64, error) {
+	_, fee, err := uc.splitImpression(publisherID, amount)
+	if err != nil {
+		return 0, err
+	}
+	return fee.Kopecks()
+}
+
+func (uc *PaymentUsecase) withBudgetLoaded(userID int, op func() error) error {
+	for i := 0; i < budgetLoadRetries; i++ {
+		err := op()
+		if !errors.Is(err, budget.ErrBalanceNotLoaded) {
+			return err
+		}
+		if err := uc.loadBudget(userID); err != nil && !errors.Is(err, budget.ErrBatchChanged) {
+			return err
+		}
+	}
+	return fmt.Errorf("failed to load budget for user %d", userID)
+}
+
+func (uc *PaymentUsecase) loadBudget(userID int) error {
+	batchID, err := uc.BudgetRepository.FlushingBatchID()
+	if err != nil {
+		return err
+	}
+	balance, applied, err := uc.PaymentRepository.GetBalanceForBudget(userID, batchID)
+	if err != nil {
+		return err
+	}
+	kopecks, err := balance.Kopecks()
+	if err != nil {
+		return err
+	}
+	return uc.BudgetRepository.LoadBalance(userID, kopecks, batchID, batchID != "" && !applied)
+}
+
+// adjustBudget переносит в кэш изменения баланса, прошедшие напрямую через Postgres
+func (uc *PaymentUsecase) adjustBudget(userID int, amount entity.Decimal) {
+	if uc.BudgetRepository == nil {
+		return
+	}
+	kopecks, err := amount.Kopecks()
+	if err == nil {
+		err = uc.BudgetRepository.Adjust(userID, kopecks)
+	}
+	if err != nil {
+		uc.logger.Errorw("failed to adjust budget cache",
+			"user_id", userID,
+			"amount", amount.String(),
+			"error", err)
+	}
+}
+
+// availableBalance отдаёт остаток с учётом ещё не сверенных списаний и холдов
+func (uc *PaymentUsecase) availableBalance(userID int, requestID string) (entity.Decimal, error) {
+	if uc.BudgetRepository != nil {
+		kopecks, err := uc.BudgetRepository.GetAvailable(userID)
+		if err == nil {
+			return entity.DecimalFromKopecks(kopecks), nil
+		}
+		if !errors.Is(err, budget.ErrBalanceNotLoaded) {
+			return entity.Decimal{}, err
+		}
+	}
+	return uc.PaymentRepository.GetBalanceByUserId(userID, requestID)
+}
+
+// RunBudgetReconciler периодически возвращает просроченные холды и переносит
+// накопленные в Redis списания и начисления в Postgres одной транзакцией
+func (uc *PaymentUsecase) RunBudgetReconciler(ctx context.Context, interval time.Duration) {
+	if uc.BudgetRepository == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := uc.ReconcileBudget(time.Now()); err != nil {
+				uc.logger.Errorw("budget reconciliation failed", "error", err)
+			}
+		}
+	}
+}
+
+func (uc *PaymentUsecase) ReconcileBudget(now time.Time) error {
+	released, err := uc.BudgetRepository.ReleaseExpired(now)
+	if err != nil {
+		return err
+	}
+	if released > 0 {
+		uc.logger.Infow("expired budget holds released", "count", released)
+	}
+
+	batchID, deltas, charges, err := uc.BudgetRepository.TakeBatch(uuid.NewString())
+	if err != nil {
+		return err
+	}
+	if batchID == "" {
+		return nil
+	}
+
+	amounts := make(map[int]entity.Decimal, len(deltas))
+	for userID, kopecks := range deltas {
+		amounts[userID] = entity.DecimalFromKopecks(kopecks)
+	}
+	entries := make([]entity.LedgerEntry, 0, len(charges))
+	for _, charge := range charges {
+		reference := charge.Reference
+		if reference == "" {
+			reference = batchID
+		}
+		entry := entity.ImpressionChargeEntry(
+			charge.AdvertiserID,
+			charge.PublisherID,
+			entity.DecimalFromKopecks(charge.Kopecks),
+			entity.DecimalFromKopecks(charge.FeeKopecks),
+			reference,
+		)
+		placement := entity.Placement{BannerID: charge.BannerID, SlotLink: charge.SlotLink}
+		if charge.Conversion != "" {
+			// испорченный пересчёт не должен держать всю пачку: списание
+			// проводится без него, исходная строка остаётся в логе
+			conversion, err := entity.ParseCompactConversion(charge.Conversion, entity.DecimalFromKopecks(charge.Kopecks))
+			if err != nil {
+				uc.logger.Warnw("skipping invalid charge conversion",
+					"batch_id", batchID,
+					"reference", reference,
+					"conversion", charge.Conversion,
+					"error", err)
+			} else {
+				placement.Conversion = &conversion
+			}
+		}
+		entries = append(entries, entry.WithPlacement(placement))
+	}
+	if err := uc.PaymentRepository.ApplyBudgetBatch(batchID, amounts, entries); err != nil {
+		return err
+	}
+	return uc.BudgetRepository.CompleteBatch()
+}
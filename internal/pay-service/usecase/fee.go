@@ -0,0 +1,85 @@
+package payment
+
+import (
+	"time"
+
+	"retarget/internal/pay-service/entity"
+)
+
+// takeRateTTL — сколько живёт закэшированная комиссия владельца слота,
+// чтобы не ходить в Postgres на каждый показ
+const takeRateTTL = time.Minute
+
+type cachedTakeRate struct {
+	rate      entity.Decimal
+	expiresAt time.Time
+}
+
+// takeRate возвращает комиссию площадки для владельца слота: индивидуальную,
+// если она задана, иначе общую по умолчанию
+func (uc *PaymentUsecase) takeRate(publisherID int) (entity.Decimal, error) {
+	if uc.takeRates != nil {
+		if cached, ok := uc.takeRates.Load(publisherID); ok {
+			entry := cached.(cachedTakeRate)
+			if time.Now().Before(entry.expiresAt) {
+				return entry.rate, nil
+			}
+		}
+	}
+
+	rate, found, err := uc.PaymentRepository.GetTakeRate(publisherID)
+	if err != nil {
+		return entity.Decimal{}, err
+	}
+	if !found {
+		rate = uc.defaultTakeRate
+	}
+	if uc.takeRates != nil {
+		uc.takeRates.Store(publisherID, cachedTakeRate{rate: rate, expiresAt: time.Now().Add(takeRateTTL)})
+	}
+	return rate, nil
+}
+
+// splitImpression делит цену показа на комиссию площадки и начисление владельцу слота
+func (uc *PaymentUsecase) splitImpression(publisherID int, amount entity.Decimal) (net entity.Decimal, fee entity.Decimal, err error) {
+	rate, err := uc.takeRate(publisherID)
+	if err != nil {
+		return entity.Decimal{}, entity.Decimal{}, err
+	}
+	return entity.SplitFee(amount, rate)
+}
+
+// SetPublisherTakeRate задаёт владельцу слотов индивидуальную комиссию площадки.
+// В кэше других реплик старая комиссия доживёт до takeRateTTL
+func (uc *PaymentUsecase) SetPublisherTakeRate(adminID, publisherID int, rate entity.Decimal) error {
+	if rate.Dec == nil {
+		return entity.ErrInvalidTakeRate
+	}
+	if err := entity.ValidateTakeRate(rate); err != nil {
+		return err
+	}
+	if err := uc.PaymentRepository.SetTakeRate(publisherID, rate); err != nil {
+		return err
+	}
+	if uc.takeRates != nil {
+		uc.takeRates.Delete(publisherID)
+	}
+	uc.logger.Infow("publisher take rate updated",
+		"admin_id", adminID,
+		"publisher_id", publisherID,
+		"take_rate", rate.String())
+	return nil
+}
+
+func (uc *PaymentUsecase) GetEarnings(publisherID int) (entity.Earnings, error) {
+	earnings, err := uc.PaymentRepository.GetEarnings(publisherID)
+	if err != nil {
+		return entity.Earnings{}, err
+	}
+	rate, err := uc.takeRate(publisherID)
+	if err != nil {
+		return entity.Earnings{}, err
+	}
+	earnings.TakeRate = rate
+	return earnings, nil
+}
@@ -0,0 +1,81 @@
+package payment
+
+import (
+	"fmt"
+
+	"retarget/internal/pay-service/entity"
+)
+
+const (
+	DefaultHistoryPageSize = 50
+	MaxHistoryPageSize     = 200
+	MaxHistoryExportRows   = 10000
+)
+
+var ErrHistoryExportTooLarge = fmt.Errorf("export is limited to %d rows, narrow the filter", MaxHistoryExportRows)
+
+func normalizeHistoryFilter(filter *entity.HistoryFilter) error {
+	if filter.Sort == "" {
+		filter.Sort = entity.HistorySortCreatedAt
+	}
+	switch {
+	case filter.Limit == 0:
+		filter.Limit = DefaultHistoryPageSize
+	case filter.Limit < 0 || filter.Limit > MaxHistoryPageSize:
+		return fmt.Errorf("%w: limit must be between 1 and %d", entity.ErrInvalidHistoryFilter, MaxHistoryPageSize)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return fmt.Errorf("%w: from must be before to", entity.ErrInvalidHistoryFilter)
+	}
+	if filter.MinAmount != nil && filter.MaxAmount != nil && filter.MinAmount.Cmp(*filter.MaxAmount) > 0 {
+		return fmt.Errorf("%w: min_amount is greater than max_amount", entity.ErrInvalidHistoryFilter)
+	}
+	// курсор от другой сортировки указывает не туда, продолжать по нему нельзя
+	if filter.After != nil && filter.After.Sort != filter.Sort {
+		return entity.ErrInvalidHistoryCursor
+	}
+	return nil
+}
+
+// GetTransactionHistory отдаёт страницу истории операций и курсор следующей
+// страницы, если она есть
+func (uc *PaymentUsecase) GetTransactionHistory(filter entity.HistoryFilter) (entity.HistoryPage, error) {
+	if err := normalizeHistoryFilter(&filter); err != nil {
+		return entity.HistoryPage{}, err
+	}
+
+	pageSize := filter.Limit
+	filter.Limit = pageSize + 1
+	items, err := uc.PaymentRepository.GetTransactionHistory(filter)
+	if err != nil {
+		return entity.HistoryPage{}, err
+	}
+
+	page := entity.HistoryPage{Items: items}
+	if len(items) > pageSize {
+		page.Items = items[:pageSize]
+		page.NextCursor = entity.CursorAfter(page.Items[pageSize-1], filter.Sort).Encode()
+	}
+	return page, nil
+}
+
+// ExportTransactionHistory выгружает всю историю по фильтру, начиная с курсора, если он задан
+func (uc *PaymentUsecase) ExportTransactionHistory(filter entity.HistoryFilter) ([]entity.HistoryItem, error) {
+	filter.Limit = MaxHistoryPageSize
+	items := make([]entity.HistoryItem, 0)
+	for {
+		page, err := uc.GetTransactionHistory(filter)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page.Items...)
+		if len(items) > MaxHistoryExportRows {
+			return nil, ErrHistoryExportTooLarge
+		}
+		if page.NextCursor == "" {
+			return items, nil
+		}
+		cursor := entity.CursorAfter(page.Items[len(page.Items)-1], filter.Sort)
+		filter.After = &cursor
+	}
+}
@@ -0,0 +1,73 @@
+package payment
+
+import (
+	"errors"
+	"time"
+
+	"retarget/internal/pay-service/entity"
+)
+
+const (
+	// IdempotencyKeyTTL — сколько хранится ответ на запрос с Idempotency-Key
+	IdempotencyKeyTTL    = 24 * time.Hour
+	maxIdempotencyKeyLen = 255
+)
+
+var ErrInvalidIdempotencyKey = errors.New("invalid idempotency key")
+
+// BeginIdempotent занимает ключ под запрос. Возвращает nil, если запрос нужно
+// выполнить, или сохранённый ответ, если такой запрос уже выполнялся.
+// actorID — участник, действующий от имени организации userID, 0 — сам userID
+func (uc *PaymentUsecase) BeginIdempotent(userID, actorID int, key, requestHash string) (*entity.IdempotencyRecord, error) {
+	if key == "" || len(key) > maxIdempotencyKeyLen {
+		return nil, ErrInvalidIdempotencyKey
+	}
+
+	record, created, err := uc.PaymentRepository.ReserveIdempotencyKey(userID, actorID, key, requestHash)
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		return nil, nil
+	}
+	if record.RequestHash != requestHash {
+		return nil, entity.ErrIdempotencyKeyReused
+	}
+	if !record.Completed() {
+		return nil, entity.ErrIdempotencyKeyInProgress
+	}
+	return &record, nil
+}
+
+func (uc *PaymentUsecase) CompleteIdempotent(userID, actorID int, key string, statusCode int, contentType string, body []byte) {
+	if err := uc.PaymentRepository.SaveIdempotentResponse(userID, actorID, key, statusCode, contentType, body); err != nil {
+		uc.logger.Errorw("failed to save idempotent response",
+			"user_id", userID,
+			"actor_id", actorID,
+			"idempotency_key", key,
+			"error", err)
+	}
+}
+
+// AbortIdempotent освобождает ключ, если обработчик упал, не дав ответа:
+// клиент может повторить запрос с тем же ключом
+func (uc *PaymentUsecase) AbortIdempotent(userID, actorID int, key string) {
+	if err := uc.PaymentRepository.ReleaseIdempotencyKey(userID, actorID, key); err != nil {
+		uc.logger.Errorw("failed to release idempotency key",
+			"user_id", userID,
+			"actor_id", actorID,
+			"idempotency_key", key,
+			"error", err)
+	}
+}
+
+func (uc *PaymentUsecase) purgeIdempotencyKeys(now time.Time) {
+	purged, err := uc.PaymentRepository.PurgeIdempotencyKeys(now.Add(-IdempotencyKeyTTL).UTC())
+	if err != nil {
+		uc.logger.Errorw("idempotency keys purge failed", "error", err)
+		return
+	}
+	if purged > 0 {
+		uc.logger.Infow("idempotency keys purged", "count", purged)
+	}
+}
@@ -0,0 +1,53 @@
+package payment
+
+import (
+	"context"
+	"time"
+
+	"retarget/internal/pay-service/entity"
+)
+
+const LedgerReconcileInterval = 10 * time.Minute
+
+// RunLedgerReconciler периодически сверяет auth_user.balance с журналом
+// проводок и сохраняет найденные расхождения
+func (uc *PaymentUsecase) RunLedgerReconciler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := uc.ReconcileLedger(); err != nil {
+				uc.logger.Errorw("ledger reconciliation failed", "error", err)
+			}
+		}
+	}
+}
+
+func (uc *PaymentUsecase) ReconcileLedger() ([]entity.LedgerDrift, error) {
+	drifts, err := uc.PaymentRepository.FindLedgerDrift()
+	if err != nil {
+		return nil, err
+	}
+	if len(drifts) == 0 {
+		return drifts, nil
+	}
+
+	for _, drift := range drifts {
+		uc.logger.Warnw("ledger drift detected",
+			"user_id", drift.OwnerID,
+			"ledger_balance", drift.LedgerBalance.String(),
+			"user_balance", drift.UserBalance.String())
+	}
+	if err := uc.PaymentRepository.SaveLedgerDrift(drifts); err != nil {
+		return nil, err
+	}
+	return drifts, nil
+}
+
+func (uc *PaymentUsecase) GetLedgerEntries(userID int, limit int) ([]entity.LedgerEntry, error) {
+	return uc.PaymentRepository.GetLedgerEntries(userID, limit)
+}
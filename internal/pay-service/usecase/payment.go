@@ -1,102 +1,87 @@
 package payment
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"retarget/internal/pay-service/entity"
 	"retarget/internal/pay-service/repo"
-	"retarget/internal/pay-service/repo/attempt"
+	"retarget/internal/pay-service/repo/budget"
+	"retarget/internal/pay-service/repo/gateway"
 	"retarget/internal/pay-service/repo/notice"
-	"strconv"
-	"time"
-
-	"github.com/cenkalti/backoff"
+	"retarget/internal/pay-service/repo/storage"
+	"retarget/internal/pay-service/usecase/statement"
+	"retarget/pkg/utils/throttle"
+	"sync"
 
 	"go.uber.org/zap"
 )
 
 var (
 	errTooLittleBalance = errors.New("balance of user is need to top up")
-)
-
-const (
-	BalanceLimit     = 100.00
-	MinActiveBalance = 10.00
+	errInvalidPrice     = errors.New("invalid impression price")
 )
 
 type PaymentUsecase struct {
 	logger            *zap.SugaredLogger
 	PaymentRepository *repo.PaymentRepository
-	NoticeRepository  *notice.NoticeRepository
-	AttemptRepository *attempt.AttemptRepository
-	shopID            string
-	secretKey         string
-	uuid              string // идентификатор мерчанта для payouts
+	NoticeRepository  notice.NoticeRepositoryInterface
+	BudgetRepository  *budget.BudgetRepository
+	defaultTakeRate   entity.Decimal // комиссия площадки, если у владельца слота нет своей
+	payoutPolicy      entity.PayoutPolicy
+	takeRates         *sync.Map
+	balanceAlerts     *sync.Map // настройки уведомлений о низком балансе по user_id
+	autoRecharges     *sync.Map // правила автопополнения по user_id
+	Gateway           gateway.PaymentGateway
 	accountNumber     string // кошелёк для автоматических выплат
-	httpClient        *http.Client
+	StatementStorage  storage.StatementStorageInterface
+	statementRenderer *statement.Renderer
+	throttleRules     map[string]*throttle.Rule // ограничение неудачных попыток по действиям
 }
 
 func NewPayUsecase(
 	zapLogger *zap.SugaredLogger,
 	payRepository *repo.PaymentRepository,
-	noticeRepository *notice.NoticeRepository,
-	attemptRepository *attempt.AttemptRepository,
-	shopID, secretKey, uuid, accountNumber string,
-	httpClient *http.Client,
+	noticeRepository notice.NoticeRepositoryInterface,
+	budgetRepository *budget.BudgetRepository,
+	defaultTakeRate entity.Decimal,
+	payoutPolicy entity.PayoutPolicy,
+	paymentGateway gateway.PaymentGateway,
+	accountNumber string,
+	statementStorage storage.StatementStorageInterface,
+	statementRenderer *statement.Renderer,
+	limiter *throttle.Limiter,
 ) *PaymentUsecase {
 	return &PaymentUsecase{
 		logger:            zapLogger,
 		PaymentRepository: payRepository,
 		NoticeRepository:  noticeRepository,
-		AttemptRepository: attemptRepository,
-		shopID:            shopID,
-		secretKey:         secretKey,
-		uuid:              uuid, // инициализируем UUID
+		BudgetRepository:  budgetRepository,
+		defaultTakeRate:   defaultTakeRate,
+		payoutPolicy:      payoutPolicy,
+		takeRates:         &sync.Map{},
+		balanceAlerts:     &sync.Map{},
+		autoRecharges:     &sync.Map{},
+		Gateway:           paymentGateway,
 		accountNumber:     accountNumber,
-		httpClient:        httpClient,
+		StatementStorage:  statementStorage,
+		statementRenderer: statementRenderer,
+		throttleRules: map[string]*throttle.Rule{
+			entity.ThrottlePromoRedeem: limiter.Rule(entity.ThrottlePromoRedeem, entity.PromoRedeemPolicy),
+			entity.ThrottlePayout:      limiter.Rule(entity.ThrottlePayout, entity.PayoutThrottlePolicy),
+		},
 	}
 }
 
-func (u *PaymentUsecase) GetBalanceByUserId(userID int, requestID string) (float64, error) {
-	pending, err := u.PaymentRepository.GetPendingTransactions(userID)
-
-	if err != nil {
-		return 0, fmt.Errorf("error while loaing pending tx: %w", err)
-	}
-
-	for _, tx := range pending {
-		statusStr, err := u.getYooPaymentStatus(tx.TransactionID)
-
-		if err != nil {
-			continue
-		}
-		statusInt := mapYooStatus(statusStr)
-		if statusInt == 1 && tx.Status != 1 {
-			if err := u.TopUpBalance(userID, tx.Amount, requestID); err == nil {
-				_ = u.PaymentRepository.UpdateTransactionStatus(tx.TransactionID, statusInt)
-			}
-		}
-	}
-
-	balik, err := u.PaymentRepository.GetBalanceByUserId(userID, requestID)
-	if err != nil {
-		return 0, err
-	}
-
-	return balik, nil
-	// return u.PaymentRepository.GetBalanceByUserId(userID, requestID)
+func (u *PaymentUsecase) GetBalanceByUserId(userID int, requestID string) (entity.Decimal, error) {
+	return u.availableBalance(userID, requestID)
 }
 
 func mapYooStatus(s string) int {
 	switch s {
 	case "pending":
 		return 0
-	case "waiting_for_capture":
+	case "waiting_for_capture", "succeeded":
 		return 1
 	case "canceled":
 		return 2
@@ -105,75 +90,84 @@ func mapYooStatus(s string) int {
 	}
 }
 
-func (u *PaymentUsecase) getYooPaymentStatus(paymentID string) (string, error) {
-	url := fmt.Sprintf("https://api.yookassa.ru/v3/payments/%s", paymentID)
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return "", fmt.Errorf("create request: %w", err)
-	}
-	req.SetBasicAuth(u.shopID, u.secretKey)
-
-	resp, err := u.httpClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("call YooKassa: %w", err)
-	}
-	defer resp.Body.Close()
-
-	var out struct {
-		Status string `json:"status"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
-		return "", fmt.Errorf("decode status: %w", err)
-	}
-	return out.Status, nil
+// fetchYooStatus запрашивает актуальный статус объекта (payments или payouts) напрямую у шлюза
+func (u *PaymentUsecase) fetchYooStatus(objectType, objectID string) (string, error) {
+	return u.Gateway.GetStatus(objectType, objectID)
 }
 
-func (uc *PaymentUsecase) TopUpBalance(userID int, amount float64, requestID string) error {
-	if amount <= 0 {
+// AdjustBalance зачисляет пользователю деньги без платежа, например компенсацию.
+// Проводка идёт со счёта ручных зачислений, а не со шлюза, чтобы сверка со
+// шлюзом сходилась; reference — идентификатор, к которому она привязывается
+func (uc *PaymentUsecase) AdjustBalance(adminID, userID int, amount entity.Decimal, reference, requestID string) error {
+	amount = amount.Money()
+	if amount.Sign() <= 0 {
 		return repo.ErrInvalidAmount
 	}
 
-	_, err := uc.PaymentRepository.UpdateBalance(userID, float64(amount), requestID)
+	entry := entity.AdjustmentEntry(userID, amount, reference)
+	_, err := uc.PaymentRepository.UpdateBalance(userID, amount, entry, requestID)
 	if err != nil {
 		return err
 	}
+	uc.adjustBudget(userID, amount)
+	uc.afterTopUp(userID, amount)
+	uc.logger.Infow("balance adjusted",
+		"request_id", requestID,
+		"admin_id", adminID,
+		"user_id", userID,
+		"amount", amount.String(),
+		"reference", reference)
+	return nil
+}
 
+// afterTopUp сбрасывает отметки порогов низкого баланса и уведомляет о пополнении
+func (uc *PaymentUsecase) afterTopUp(userID int, amount entity.Decimal) {
 	go func() {
-		if uc.AttemptRepository != nil {
-			if err := uc.AttemptRepository.ResetAttemptsByUserID(userID); err != nil {
-				uc.logger.Errorw("failed to reset attempts after top up",
-					"user_id", userID,
-					"error", err)
-			}
-		}
+		uc.resetBalanceAlerts(userID)
 		if uc.NoticeRepository != nil {
-			if err = uc.NoticeRepository.SendTopUpBalanceEvent(userID, float64(amount)); err != nil {
+			if err := uc.NoticeRepository.SendTopUpBalanceEvent(userID, amount); err != nil {
 				uc.logger.Errorw("failed to send topUp message after top up",
 					"user_id", userID,
 					"error", err)
 			}
 		}
 	}()
-
-	return nil
 }
 
 func (uc *PaymentUsecase) GetTransactionByID(transactionID string, requestID string) (*entity.Transaction, error) {
 	return uc.PaymentRepository.GetTransactionByID(transactionID, requestID)
 }
 
-func (uc *PaymentUsecase) RegUserActivity(user_banner_id, user_slot_id int, amount entity.Decimal) error {
-	_, user_from_id, err := uc.PaymentRepository.RegUserActivity(user_banner_id, user_slot_id, amount)
-	if err != nil {
-		return err
+func (uc *PaymentUsecase) RegUserActivity(user_banner_id, user_slot_id int, amount entity.Decimal, reservationID string, placement entity.Placement) error {
+	user_from_id := user_slot_id
+	if uc.BudgetRepository != nil {
+		if err := uc.chargeThroughBudget(user_slot_id, user_banner_id, amount, reservationID, placement); err != nil {
+			if errors.Is(err, ErrInsufficientFunds) {
+				go uc.offBannersByUserID(context.Background(), user_from_id)
+				uc.maybeAutoRecharge(user_from_id, entity.DecimalFromKopecks(0))
+			}
+			return err
+		}
+	} else {
+		_, fee, err := uc.splitImpression(user_banner_id, amount)
+		if err != nil {
+			return err
+		}
+		_, user_from_id, err = uc.PaymentRepository.RegUserActivity(user_banner_id, user_slot_id, amount, fee, reservationID, placement)
+		if err != nil {
+			return err
+		}
+	}
+	balance_from, settings, err := uc.checkBalance(user_from_id)
+	if err == nil || err == errTooLittleBalance {
+		uc.maybeAutoRecharge(user_from_id, balance_from)
 	}
-	balance_from, err := uc.CheckBalance(user_from_id)
 	if err == errTooLittleBalance {
-		if balance_from < MinActiveBalance {
+		if settings.ShouldPause(balance_from) {
 			go uc.offBannersByUserID(context.Background(), user_from_id)
 		}
-		uc.logger.Infow("balance checked", "user_id", user_from_id, "balance", balance_from, "limit", BalanceLimit)
-		go uc.requireSend(user_from_id, strconv.FormatFloat(balance_from, 'f', 2, 64))
+		uc.logger.Infow("balance checked", "user_id", user_from_id, "balance", balance_from.String())
+		go uc.notifyLowBalance(user_from_id, balance_from, settings.Crossed(balance_from))
 		return nil
 	}
 	if err != nil {
@@ -202,142 +196,43 @@ func (uc *PaymentUsecase) offBannersByUserID(ctx context.Context, userID int) {
 		"user_id", userID)
 }
 
-func (uc *PaymentUsecase) requireSend(userID int, message string) {
-	if uc.AttemptRepository == nil || uc.NoticeRepository == nil {
-		return
-	}
-
-	defer func() {
-		if r := recover(); r != nil {
-			uc.logger.Errorw("error/panic in sendRequired",
-				"recovered", r,
-				"user_id", userID)
-		}
-	}()
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-	defer cancel()
-
-	attempts, err := uc.AttemptRepository.GetAttemptsByUserID(userID)
-	if err != nil {
-		uc.logger.Errorw("failed to get attempts",
-			"user_id", userID,
-			"error", err)
-		return
-	}
-
-	if attempts >= uc.AttemptRepository.MaxAttempts {
-		return
-	}
-
-	if err := uc.AttemptRepository.IncrementAttemptsByUserID(userID); err != nil {
-		uc.logger.Errorw("failed to increment attempts",
-			"user_id", userID,
-			"error", err)
-		return
-	}
-
-	backoffConfig := backoff.NewExponentialBackOff()
-	backoffConfig.MaxElapsedTime = 1 * time.Minute
-
-	notify := func() error {
-		select {
-		case <-ctx.Done():
-			return backoff.Permanent(ctx.Err())
-		default:
-			return uc.NoticeRepository.SendLowBalanceNotification(userID)
-		}
-	}
-
-	if err := backoff.Retry(notify, backoff.WithMaxRetries(backoffConfig, 9)); err != nil {
-		uc.logger.Errorw("notification failed after retries",
-			"user_id", userID,
-			"error", err)
-
-		if err := uc.AttemptRepository.DecrementAttemptsByUserID(userID); err != nil {
-			uc.logger.Errorw("failed to decrement attempts",
-				"user_id", userID,
-				"error", err)
-		}
-	}
+// CheckBalance сверяет баланс с порогами пользователя, см. checkBalance
+func (uc *PaymentUsecase) CheckBalance(user_id int) (entity.Decimal, error) {
+	balance, _, err := uc.checkBalance(user_id)
+	return balance, err
 }
 
-func (uc *PaymentUsecase) CheckBalance(user_id int) (float64, error) {
-	balance, err := uc.PaymentRepository.GetBalanceByUserId(user_id, "UNIMPLEMENTED request_id")
-	if err != nil {
-		return balance, err
-	}
-	if balance <= BalanceLimit {
-		return balance, errTooLittleBalance
-	}
-	return balance, nil
-}
-
-type YooPaymentRequest struct {
-	Amount struct {
-		Value    string `json:"value"`
-		Currency string `json:"currency"`
-	} `json:"amount"`
-	Confirmation struct {
-		Type      string `json:"type"`
-		ReturnURL string `json:"return_url"`
-	} `json:"confirmation"`
-	Description string `json:"description"`
-}
-
-type YooPaymentResponse struct {
-	ID           string `json:"id"`
-	Status       string `json:"status"`
-	Confirmation struct {
-		Type            string `json:"type"`
-		ConfirmationURL string `json:"confirmation_url"`
-	} `json:"confirmation"`
-	Amount struct {
-		Value    string `json:"value"`
-		Currency string `json:"currency"`
-	} `json:"amount"`
-}
-
-func (u *PaymentUsecase) CreateYooMoneyPayment(userID int, value, currency, returnURL, description, idempotenceKey string) (string, error) {
+// CreateYooMoneyPayment создаёт платёж на пополнение. Непустой promoCode
+// проверяется сразу, а бонус по нему начисляется при зачислении пополнения.
+// savePaymentMethod просит шлюз сохранить способ оплаты для автопополнения
+func (u *PaymentUsecase) CreateYooMoneyPayment(userID int, value, currency, returnURL, description, idempotenceKey, promoCode string, savePaymentMethod bool) (string, error) {
 	if len(idempotenceKey) > 40 {
 		idempotenceKey = idempotenceKey[:40]
 	}
 
-	reqBody := YooPaymentRequest{Description: description}
-	reqBody.Amount.Value = value
-	reqBody.Amount.Currency = currency
-	reqBody.Confirmation.Type = "redirect"
-	reqBody.Confirmation.ReturnURL = returnURL
-
-	payload, err := json.Marshal(reqBody)
+	currency, err := u.checkCurrency(currency)
 	if err != nil {
-		return "", fmt.Errorf("marshal request: %w", err)
+		return "", err
 	}
-
-	req, err := http.NewRequest(http.MethodPost, "https://api.yookassa.ru/v3/payments", bytes.NewBuffer(payload))
-	if err != nil {
-		return "", fmt.Errorf("create request: %w", err)
+	promoCode = entity.NormalizePromoCode(promoCode)
+	if promoCode != "" {
+		if err := u.checkTopUpPromo(userID, promoCode, currency); err != nil {
+			return "", err
+		}
 	}
-	req.SetBasicAuth(u.shopID, u.secretKey)
-	req.Header.Set("Idempotence-Key", idempotenceKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := u.httpClient.Do(req)
+	out, err := u.Gateway.CreatePayment(gateway.PaymentRequest{
+		Amount:            gateway.Amount{Value: value, Currency: currency},
+		Description:       description,
+		ReturnURL:         returnURL,
+		IdempotenceKey:    idempotenceKey,
+		SavePaymentMethod: savePaymentMethod,
+	})
 	if err != nil {
-		return "", fmt.Errorf("request error: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
-		return "", fmt.Errorf("unexpected status %d", resp.StatusCode)
-	}
-
-	var out YooPaymentResponse
-	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
-		return "", fmt.Errorf("decode response: %w", err)
+		return "", err
 	}
 
-	amt, err := strconv.ParseFloat(out.Amount.Value, 64)
+	amt, err := entity.ParseAmount(out.Amount.Value)
 	if err != nil {
 		return "", fmt.Errorf("parse amount: %w", err)
 	}
@@ -349,267 +244,36 @@ func (u *PaymentUsecase) CreateYooMoneyPayment(userID int, value, currency, retu
 		Amount:        amt,
 		Type:          "yoomoney_payment",
 		Status:        statusInt,
+		Currency:      currency,
 	}
 	if err := u.PaymentRepository.CreateTransaction(trx); err != nil {
 		return "", fmt.Errorf("save transaction: %w", err)
 	}
-
-	return out.Confirmation.ConfirmationURL, nil
-}
-
-type YooPayoutRequest struct {
-	Amount struct {
-		Value    string `json:"value"`
-		Currency string `json:"currency"`
-	} `json:"amount"`
-	PayoutDestinationData struct {
-		Type string `json:"type"`
-		Card struct {
-			Number string `json:"number"`
-		} `json:"card,omitempty"`
-		YooMoney struct {
-			AccountNumber string `json:"account_number"`
-		} `json:"yoo_money,omitempty"`
-	} `json:"payoutDestinationData"`
-	Confirmation struct {
-		Type      string `json:"type"`
-		ReturnURL string `json:"return_url"`
-	} `json:"confirmation,omitempty"`
-	Description string `json:"description"`
-	Metadata    struct {
-		UserID int `json:"user_id"`
-	} `json:"metadata,omitempty"`
-}
-
-type YooPayoutResponse struct {
-	ID           string `json:"id"`
-	Status       string `json:"status"`
-	Confirmation struct {
-		Type            string `json:"type"`
-		ConfirmationURL string `json:"confirmation_url"`
-	} `json:"confirmation"`
-	Amount struct {
-		Value    string `json:"value"`
-		Currency string `json:"currency"`
-	} `json:"amount"`
-	Description string `json:"description"`
-	CreatedAt   string `json:"created_at"`
-}
-
-func (u *PaymentUsecase) CreateYooMoneyPayout(
-	userID int,
-	amount float64,
-	destination string,
-	destinationType string,
-	description string,
-	idempotenceKey string,
-) (*entity.Transaction, error) {
-	balance, err := u.PaymentRepository.GetBalanceByUserId(userID, idempotenceKey)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get balance: %w", err)
-	}
-
-	if balance < amount {
-		return nil, errors.New("insufficient funds for withdrawal")
-	}
-
-	reqBody := YooPayoutRequest{Description: description}
-	reqBody.Amount.Value = fmt.Sprintf("%.2f", amount)
-	reqBody.Amount.Currency = "RUB"
-	reqBody.PayoutDestinationData.Type = destinationType
-
-	switch destinationType {
-	case "bank_card":
-		if len(destination) < 16 || len(destination) > 19 {
-			return nil, errors.New("invalid card number format")
+	if promoCode != "" {
+		if err := u.PaymentRepository.AttachTopUpPromo(out.ID, promoCode, userID); err != nil {
+			return "", err
 		}
-		reqBody.PayoutDestinationData.Card.Number = destination
-	case "yoo_money":
-		reqBody.PayoutDestinationData.YooMoney.AccountNumber = destination
-	default:
-		return nil, errors.New("unsupported destination type")
-	}
-
-	reqBody.Metadata.UserID = userID
-
-	payload, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("marshal request: %w", err)
-	}
-
-	u.logger.Debugw("YooMoney payout request",
-		"payload", string(payload),
-		"userID", userID,
-		"destinationType", destinationType)
-
-	req, err := http.NewRequest(http.MethodPost, "https://api.yookassa.ru/v3/payouts", bytes.NewBuffer(payload))
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-
-	req.SetBasicAuth(u.shopID, u.secretKey)
-	req.Header.Set("Idempotence-Key", idempotenceKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Request-ID", idempotenceKey)
-	if u.uuid != "" {
-		req.Header.Set("X-YooMoney-UUID", u.uuid)
-	}
-
-	resp, err := u.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request error: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(bodyBytes))
-	}
-
-	var out YooPayoutResponse
-	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
-	}
-
-	_, err = u.PaymentRepository.UpdateBalance(userID, -amount, idempotenceKey)
-	if err != nil {
-		u.logger.Errorw("Failed to update balance after payout",
-			"user_id", userID,
-			"amount", amount,
-			"payout_id", out.ID,
-			"error", err)
-		return nil, fmt.Errorf("update balance after payout: %w", err)
-	}
-
-	statusInt := mapYooStatus(out.Status)
-	trx := entity.Transaction{
-		TransactionID: out.ID,
-		UserID:        userID,
-		Amount:        amount,
-		Type:          "payout_" + destinationType,
-		Status:        statusInt,
 	}
-
-	if err := u.PaymentRepository.CreateTransaction(trx); err != nil {
-		u.logger.Errorw("Failed to save payout transaction",
-			"user_id", userID,
-			"payout_id", out.ID,
-			"error", err)
-		return nil, fmt.Errorf("save transaction: %w", err)
-	}
-
-	return &trx, nil
-}
-
-func (u *PaymentUsecase) CreateYooMoneyPayoutRedirect(
-	userID int,
-	amount float64,
-	description, returnURL, idempotenceKey string,
-) (string, error) {
-	if len(idempotenceKey) > 40 {
-		idempotenceKey = idempotenceKey[:40]
-	}
-
-	bal, err := u.PaymentRepository.GetBalanceByUserId(userID, idempotenceKey)
-	if err != nil {
-		return "", fmt.Errorf("get balance: %w", err)
-	}
-	if bal < amount {
-		return "", errors.New("insufficient funds")
-	}
-
-	requestMap := map[string]interface{}{
-		"amount": map[string]string{
-			"value":    fmt.Sprintf("%.2f", amount),
-			"currency": "RUB",
-		},
-		"description": description,
-		"confirmation": map[string]interface{}{
-			"type":       "redirect",
-			"return_url": returnURL,
-		},
-		"metadata": map[string]interface{}{
-			"user_id": userID,
-			"type":    "withdrawal",
-		},
-	}
-
-	payload, _ := json.Marshal(requestMap)
-	u.logger.Debugw("Payment request instead of payout", "payload", string(payload))
-
-	req, err := http.NewRequest(http.MethodPost, "https://api.yookassa.ru/v3/payments", bytes.NewBuffer(payload))
-	if err != nil {
-		return "", fmt.Errorf("create request: %w", err)
-	}
-
-	req.SetBasicAuth(u.shopID, u.secretKey)
-	req.Header.Set("Idempotence-Key", idempotenceKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := u.httpClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("request error: %w", err)
-	}
-	defer resp.Body.Close()
-
-	bodyBytes, _ := io.ReadAll(resp.Body)
-	bodyString := string(bodyBytes)
-	u.logger.Debugw("YooMoney API response", "status", resp.StatusCode, "body", bodyString)
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
-		return "", fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bodyString)
-	}
-
-	var response struct {
-		ID           string `json:"id"`
-		Status       string `json:"status"`
-		Confirmation struct {
-			Type            string `json:"type"`
-			ConfirmationURL string `json:"confirmation_url"`
-		} `json:"confirmation"`
-	}
-
-	if err := json.Unmarshal(bodyBytes, &response); err != nil {
-		return "", fmt.Errorf("decode response: %w", err)
-	}
-
-	_, err = u.PaymentRepository.UpdateBalance(userID, -amount, idempotenceKey)
-	if err != nil {
-		u.logger.Errorw("Failed to update balance after successful payment initiation",
-			"user_id", userID,
-			"amount", amount,
-			"error", err)
-		return "", fmt.Errorf("update balance after payment: %w", err)
-	}
-
-	trx := entity.Transaction{
-		TransactionID: response.ID,
-		UserID:        userID,
-		Amount:        amount,
-		Type:          "withdrawal_payment",
-		Status:        mapYooStatus(response.Status),
+	if out.PaymentMethodID != "" {
+		method := entity.PaymentMethod{
+			ID:            out.PaymentMethodID,
+			UserID:        userID,
+			Title:         out.PaymentMethodTitle,
+			TransactionID: out.ID,
+		}
+		if err := u.PaymentRepository.SavePaymentMethod(method); err != nil {
+			return "", err
+		}
 	}
-	_ = u.PaymentRepository.CreateTransaction(trx)
-
-	u.logger.Debugw("Payment for withdrawal created",
-		"confirmation_url", response.Confirmation.ConfirmationURL,
-		"payment_id", response.ID)
 
-	return response.Confirmation.ConfirmationURL, nil
+	return out.ConfirmationURL, nil
 }
 
+// CreateYooMoneyPayoutAuto заводит заявку на вывод на кошелёк площадки из конфигурации
 func (u *PaymentUsecase) CreateYooMoneyPayoutAuto(
 	userID int,
-	amount float64,
+	amount entity.Decimal,
 	description string,
-	idempotenceKey string,
-) (*entity.Transaction, error) {
-	return u.CreateYooMoneyPayout(
-		userID,
-		amount,
-		u.accountNumber,
-		"yoo_money",
-		description,
-		idempotenceKey,
-	)
+) (entity.PayoutRequest, error) {
+	return u.RequestPayout(userID, amount, "yoo_money", u.accountNumber, description)
 }
@@ -8,15 +8,22 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 
 	"retarget/internal/pay-service/entity"
 	"retarget/internal/pay-service/repo"
+	"retarget/internal/pay-service/repo/budget"
+	"retarget/internal/pay-service/repo/gateway"
+	"retarget/internal/pay-service/repo/notice"
+	"retarget/internal/pay-service/repo/storage"
+	"retarget/internal/pay-service/usecase/statement"
 )
 
 type roundTripper func(req *http.Request) *http.Response
@@ -32,6 +39,7 @@ func Test_mapYooStatus(t *testing.T) {
 	}{
 		{"pending", 0},
 		{"waiting_for_capture", 1},
+		{"succeeded", 1},
 		{"canceled", 2},
 		{"something", -1},
 	}
@@ -46,19 +54,35 @@ func Test_CheckBalance_SuccessAndLow(t *testing.T) {
 	p := repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar())
 	uc := &PaymentUsecase{PaymentRepository: p}
 
-	mock.ExpectQuery("SELECT balance FROM auth_user").
+	mock.ExpectQuery(`SELECT balance \+ promo_balance FROM auth_user`).
 		WithArgs(1).
-		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(200.0))
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("200.00"))
+	mock.ExpectQuery("FROM balance_alert_settings").
+		WithArgs(1).
+		WillReturnError(sql.ErrNoRows)
 	bal, err := uc.CheckBalance(1)
 	assert.NoError(t, err)
-	assert.Equal(t, 200.0, bal)
+	assert.Equal(t, "200.00", bal.String())
 
-	mock.ExpectQuery("SELECT balance FROM auth_user").
+	mock.ExpectQuery(`SELECT balance \+ promo_balance FROM auth_user`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("50.00"))
+	mock.ExpectQuery("FROM balance_alert_settings").
 		WithArgs(2).
-		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(50.0))
+		WillReturnError(sql.ErrNoRows)
 	bal2, err2 := uc.CheckBalance(2)
 	assert.ErrorIs(t, err2, errTooLittleBalance)
-	assert.Equal(t, 50.0, bal2)
+	assert.Equal(t, "50.00", bal2.String())
+
+	// свои пороги ниже баланса и выключенная автопауза
+	mock.ExpectQuery(`SELECT balance \+ promo_balance FROM auth_user`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("5.00"))
+	mock.ExpectQuery("FROM balance_alert_settings").
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"thresholds", "auto_pause", "pause_floor"}).AddRow("{1.00}", false, "10.00"))
+	_, err3 := uc.CheckBalance(3)
+	assert.NoError(t, err3)
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -69,12 +93,12 @@ func Test_GetTransactionByID_FoundAndNotFound(t *testing.T) {
 	p := repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar())
 	uc := &PaymentUsecase{PaymentRepository: p}
 
-	cols := []string{"id", "transaction_id", "user_id", "amount", "type", "status", "created_at"}
+	cols := []string{"id", "transaction_id", "user_id", "amount", "type", "status", "created_at", "currency"}
 	now := time.Now()
 
 	mock.ExpectQuery("WHERE transaction_id = \\$1").
 		WithArgs("tx1").
-		WillReturnRows(sqlmock.NewRows(cols).AddRow(1, "tx1", 3, 12.5, "x", 0, now))
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(1, "tx1", 3, "12.50", "x", 0, now, "RUB"))
 	tx, err := uc.GetTransactionByID("tx1", "req")
 	assert.NoError(t, err)
 	assert.Equal(t, "tx1", tx.TransactionID)
@@ -103,34 +127,32 @@ func Test_CreateYooMoneyPayment_VariousScenarios(t *testing.T) {
 	})}
 	uc := &PaymentUsecase{
 		PaymentRepository: p,
-		httpClient:        clientOK,
-		shopID:            "s",
-		secretKey:         "k",
+		Gateway:           gateway.NewYooKassaGateway("s", "k", "", clientOK),
 	}
 
 	mock.ExpectQuery("SELECT 1 FROM transaction").
 		WithArgs("i1").
 		WillReturnError(sql.ErrNoRows)
 	mock.ExpectExec("INSERT INTO transaction").
-		WithArgs("i1", 5, 15.00, "yoo_money", 1).
+		WithArgs("i1", 5, "15.00", "yoomoney_payment", 1, "RUB").
 		WillReturnResult(sqlmock.NewResult(1, 1))
-	url, err := uc.CreateYooMoneyPayment(5, "15.00", "RUB", "ret", "d", "key1")
+	url, err := uc.CreateYooMoneyPayment(5, "15.00", "RUB", "ret", "d", "key1", "", false)
 	assert.NoError(t, err)
 	assert.Equal(t, "u1", url)
 
 	clientBadStatus := &http.Client{Transport: roundTripper(func(req *http.Request) *http.Response {
 		return &http.Response{StatusCode: 500, Body: ioutil.NopCloser(bytes.NewBufferString(`{}`))}
 	})}
-	uc.httpClient = clientBadStatus
-	_, err = uc.CreateYooMoneyPayment(5, "1", "RUB", "", "", "")
+	uc.Gateway = gateway.NewYooKassaGateway("s", "k", "", clientBadStatus)
+	_, err = uc.CreateYooMoneyPayment(5, "1", "RUB", "", "", "", "", false)
 	assert.Error(t, err)
 
 	badJSON := `{"id":`
 	clientDec := &http.Client{Transport: roundTripper(func(req *http.Request) *http.Response {
 		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(badJSON))}
 	})}
-	uc.httpClient = clientDec
-	_, err = uc.CreateYooMoneyPayment(5, "1", "RUB", "", "", "")
+	uc.Gateway = gateway.NewYooKassaGateway("s", "k", "", clientDec)
+	_, err = uc.CreateYooMoneyPayment(5, "1", "RUB", "", "", "", "", false)
 	assert.Error(t, err)
 
 	resp2 := `{
@@ -141,14 +163,14 @@ func Test_CreateYooMoneyPayment_VariousScenarios(t *testing.T) {
 	client2 := &http.Client{Transport: roundTripper(func(req *http.Request) *http.Response {
 		return &http.Response{StatusCode: http.StatusAccepted, Body: ioutil.NopCloser(strings.NewReader(resp2))}
 	})}
-	uc.httpClient = client2
+	uc.Gateway = gateway.NewYooKassaGateway("s", "k", "", client2)
 	mock.ExpectQuery("SELECT 1 FROM transaction").
 		WithArgs("i3").
 		WillReturnError(sql.ErrNoRows)
 	mock.ExpectExec("INSERT INTO transaction").
-		WithArgs("i3", 6, 1.23, "yoo_money", 0).
+		WithArgs("i3", 6, "1.23", "yoomoney_payment", 0, "RUB").
 		WillReturnError(errors.New("db error"))
-	_, err = uc.CreateYooMoneyPayment(6, "1.23", "RUB", "", "", "")
+	_, err = uc.CreateYooMoneyPayment(6, "1.23", "RUB", "", "", "", "", false)
 	assert.Error(t, err)
 
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -166,13 +188,19 @@ func Test_RegUserActivity_ErrorOnDebit(t *testing.T) {
 	amt := entity.Decimal{}
 	_ = amt.ParseFromString("5.0")
 
+	mock.ExpectQuery("SELECT take_rate FROM publisher_take_rate").
+		WithArgs(1).
+		WillReturnError(sql.ErrNoRows)
 	mock.ExpectBegin()
-	mock.ExpectExec("UPDATE auth_user").
+	mock.ExpectQuery("WITH promo AS").
 		WithArgs(amt, 2).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectExec("UPDATE auth_user").
+		WithArgs("5.00", 2).
 		WillReturnError(errors.New("debit fail"))
 	mock.ExpectRollback()
 
-	err := uc.RegUserActivity(1, 2, amt)
+	err := uc.RegUserActivity(1, 2, amt, "", entity.Placement{})
 	assert.Error(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -208,6 +236,78 @@ func (n *noticeStub) SendTopUpBalanceEvent(uid int, a float64) error {
 	return nil
 }
 
+func Test_SetBalanceAlerts_SavesAndDropsCache(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	uc := &PaymentUsecase{
+		logger:            zap.NewNop().Sugar(),
+		PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+		balanceAlerts:     &sync.Map{},
+	}
+	uc.balanceAlerts.Store(5, cachedBalanceAlerts{settings: entity.DefaultBalanceAlertSettings(), expiresAt: time.Now().Add(time.Hour)})
+
+	_, err := uc.SetBalanceAlerts(5, entity.BalanceAlertSettings{Thresholds: []entity.Decimal{entity.DecimalFromKopecks(-100)}})
+	assert.ErrorIs(t, err, entity.ErrInvalidBalanceAlerts)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO balance_alert_settings").
+		WithArgs(5, `{"500.00","100.00"}`, true, "0.00").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM balance_alert_crossing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	settings, err := uc.SetBalanceAlerts(5, entity.BalanceAlertSettings{
+		Thresholds: []entity.Decimal{entity.DecimalFromKopecks(10000), entity.DecimalFromKopecks(50000)},
+		AutoPause:  true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "500.00", settings.Thresholds[0].String())
+	_, cached := uc.balanceAlerts.Load(5)
+	assert.False(t, cached)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// lowBalanceNotices запоминает пороги отправленных уведомлений о низком балансе
+type lowBalanceNotices struct {
+	notice.NoticeRepositoryInterface
+	thresholds []string
+}
+
+func (n *lowBalanceNotices) SendLowBalanceNotification(userID int, threshold, balance entity.Decimal) error {
+	n.thresholds = append(n.thresholds, threshold.String())
+	return nil
+}
+
+// письма ограничивает только отметка пересечения: каждое новое пересечение
+// порога, в том числе повторное после пополнения, уведомляет пользователя
+func Test_NotifyLowBalance_SendsEveryCrossing(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	notices := &lowBalanceNotices{}
+	uc := &PaymentUsecase{
+		logger:            zap.NewNop().Sugar(),
+		PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+		NoticeRepository:  notices,
+	}
+
+	crossings := []string{"100.00", "50.00", "20.00", "100.00", "50.00", "20.00"}
+	for _, threshold := range crossings {
+		mock.ExpectQuery("INSERT INTO balance_alert_crossing").
+			WithArgs(5, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"threshold"}).AddRow(threshold))
+		crossed, _ := entity.ParseAmount(threshold)
+		uc.notifyLowBalance(5, entity.DecimalFromKopecks(1000), []entity.Decimal{crossed})
+	}
+	// тот же порог без пополнения уже отмечен и письма не даёт
+	mock.ExpectQuery("INSERT INTO balance_alert_crossing").
+		WillReturnRows(sqlmock.NewRows([]string{"threshold"}))
+	uc.notifyLowBalance(5, entity.DecimalFromKopecks(1000), []entity.Decimal{entity.DecimalFromKopecks(2000)})
+
+	assert.Equal(t, crossings, notices.thresholds)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func Test_offBannersByUserID_SuccessAndErrorLogged(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	defer db.Close()
@@ -230,28 +330,90 @@ func Test_offBannersByUserID_SuccessAndErrorLogged(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func Test_GetBalanceByUserId_PendingCanceledTransaction(t *testing.T) {
+func Test_HandleYooNotification_TopUpSucceeded(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	defer db.Close()
-	repoDB := repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar())
+	var fetched string
 	uc := &PaymentUsecase{
-		PaymentRepository: repoDB,
-		httpClient: &http.Client{Transport: roundTripper(func(req *http.Request) *http.Response {
-			return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(strings.NewReader(`{"status":"canceled"}`))}
-		})},
+		logger:            zap.NewNop().Sugar(),
+		PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+		Gateway: gateway.NewYooKassaGateway("", "", "", &http.Client{Transport: roundTripper(func(req *http.Request) *http.Response {
+			fetched = req.URL.Path
+			return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(strings.NewReader(`{"id":"tx5","status":"succeeded"}`))}
+		})}),
 	}
 
 	now := time.Now()
-	mock.ExpectQuery("FROM transaction").
-		WithArgs(5).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "transaction_id", "user_id", "amount", "type", "status", "created_at"}).
-			AddRow(1, "tx5", 5, 10.0, "yoo_money", 0, now))
-	mock.ExpectQuery("SELECT balance FROM auth_user").
-		WithArgs(5, "req5").
-		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(80.0))
+	mock.ExpectQuery("SELECT id, transaction_id, user_id, amount, type, status, created_at, currency FROM transaction").
+		WithArgs("tx5").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "transaction_id", "user_id", "amount", "type", "status", "created_at", "currency"}).
+			AddRow(1, "tx5", 5, "10.00", "yoomoney_payment", "0", now, "RUB"))
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE transaction").
+		WithArgs(entity.TransactionSucceeded, "tx5").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE auth_user").
+		WithArgs("10.00", 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO ledger_entry").
+		WithArgs("topup", "tx5").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	for i := 0; i < 2; i++ {
+		mock.ExpectQuery("INSERT INTO ledger_account").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(i + 1))
+		mock.ExpectExec("INSERT INTO ledger_posting").WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+	mock.ExpectQuery("SELECT code FROM promo_topup").
+		WithArgs("tx5").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("UPDATE payment_method SET active = TRUE").
+		WithArgs("tx5").
+		WillReturnError(sql.ErrNoRows)
 
-	bal, _ := uc.GetBalanceByUserId(5, "req5")
-	assert.Equal(t, 0.0, bal)
+	var n entity.YooNotification
+	n.Event = "payment.succeeded"
+	n.Object.ID = "tx5"
+	n.Object.Status = "succeeded"
+	assert.NoError(t, uc.HandleYooNotification(n))
+	assert.Equal(t, "/v3/payments/tx5", fetched)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_HandleYooNotification_AlreadySettled(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	uc := &PaymentUsecase{
+		logger:            zap.NewNop().Sugar(),
+		PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+		Gateway: gateway.NewYooKassaGateway("", "", "", &http.Client{Transport: roundTripper(func(req *http.Request) *http.Response {
+			return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(strings.NewReader(`{"id":"po1","status":"canceled"}`))}
+		})}),
+	}
+
+	mock.ExpectQuery("SELECT id, transaction_id, user_id, amount, type, status, created_at, currency FROM transaction").
+		WithArgs("po1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "transaction_id", "user_id", "amount", "type", "status", "created_at", "currency"}).
+			AddRow(1, "po1", 5, "10.00", "payout_yoo_money", "2", time.Now(), "RUB"))
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE transaction").
+		WithArgs(entity.TransactionCanceled, "po1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	var n entity.YooNotification
+	n.Event = "payout.canceled"
+	n.Object.ID = "po1"
+	assert.NoError(t, uc.HandleYooNotification(n))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_HandleYooNotification_Unsupported(t *testing.T) {
+	uc := &PaymentUsecase{logger: zap.NewNop().Sugar()}
+
+	var n entity.YooNotification
+	n.Event = "deal.closed"
+	n.Object.ID = "r1"
+	assert.ErrorIs(t, uc.HandleYooNotification(n), ErrUnknownNotification)
 }
 
 func Test_GetBalanceByUserId_FinalBalanceError(t *testing.T) {
@@ -260,13 +422,794 @@ func Test_GetBalanceByUserId_FinalBalanceError(t *testing.T) {
 	repoDB := repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar())
 	uc := &PaymentUsecase{PaymentRepository: repoDB}
 
-	mock.ExpectQuery("FROM transaction").
-		WithArgs(6).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "transaction_id", "user_id", "amount", "type", "status", "created_at"}))
-	mock.ExpectQuery("SELECT balance FROM auth_user").
+	mock.ExpectQuery(`SELECT balance \+ promo_balance FROM auth_user`).
 		WithArgs(6, "req6").
 		WillReturnError(errors.New("scan error"))
 
 	_, err := uc.GetBalanceByUserId(6, "req6")
 	assert.Error(t, err)
 }
+
+func Test_ReconcileBudget_AppliesBatch(t *testing.T) {
+	s, err := miniredis.Run()
+	assert.NoError(t, err)
+	defer s.Close()
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	uc := &PaymentUsecase{
+		logger:            zap.NewNop().Sugar(),
+		PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+		BudgetRepository:  budget.NewBudgetRepository(s.Addr(), "", 0, time.Minute),
+	}
+
+	mock.ExpectQuery("SELECT take_rate FROM publisher_take_rate").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"take_rate"}).AddRow("0.20"))
+	mock.ExpectQuery(`SELECT balance \+ promo_balance, EXISTS`).
+		WithArgs(1, "").
+		WillReturnRows(sqlmock.NewRows([]string{"balance", "exists"}).AddRow("10.00", false))
+	amt := entity.Decimal{}
+	_ = amt.ParseFromString("2.50")
+	placement := entity.Placement{BannerID: 7, SlotLink: "slot-a"}
+	holdID, err := uc.ReserveSpend(1, 2, amt, placement)
+	assert.NoError(t, err)
+	assert.NoError(t, uc.chargeThroughBudget(1, 2, amt, holdID, entity.Placement{}))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO budget_batch").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("WITH promo AS").WithArgs("2.50", 1).WillReturnError(sql.ErrNoRows)
+	mock.ExpectExec("UPDATE auth_user").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE auth_user").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO ledger_entry").
+		WithArgs("impression_charge", holdID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	for i := 0; i < 3; i++ {
+		mock.ExpectQuery("INSERT INTO ledger_account").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(i + 1))
+		mock.ExpectExec("INSERT INTO ledger_posting").WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectExec("INSERT INTO ledger_entry_placement").
+		WithArgs(int64(1), int64(7), "slot-a").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	assert.NoError(t, uc.ReconcileBudget(time.Now()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	available, err := uc.availableBalance(1, "req")
+	assert.NoError(t, err)
+	assert.Equal(t, "7.50", available.String())
+}
+
+func Test_ReconcileBudget_SkipsInvalidConversion(t *testing.T) {
+	s, err := miniredis.Run()
+	assert.NoError(t, err)
+	defer s.Close()
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	budgetRepo := budget.NewBudgetRepository(s.Addr(), "", 0, time.Minute)
+	uc := &PaymentUsecase{
+		logger:            zap.NewNop().Sugar(),
+		PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+		BudgetRepository:  budgetRepo,
+	}
+	assert.NoError(t, budgetRepo.LoadBalance(1, 1000, "", false))
+	assert.NoError(t, budgetRepo.LoadBalance(2, 0, "", false))
+	_, err = budgetRepo.Charge(1, 2, 250, 50, 7, "slot-a", "USD,broken")
+	assert.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO budget_batch").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("WITH promo AS").WithArgs("2.50", 1).WillReturnError(sql.ErrNoRows)
+	mock.ExpectExec("UPDATE auth_user").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE auth_user").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO ledger_entry").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	for i := 0; i < 3; i++ {
+		mock.ExpectQuery("INSERT INTO ledger_account").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(i + 1))
+		mock.ExpectExec("INSERT INTO ledger_posting").WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	// списание проводится с местом показа, но без пересчёта
+	mock.ExpectExec("INSERT INTO ledger_entry_placement").
+		WithArgs(int64(1), int64(7), "slot-a").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	assert.NoError(t, uc.ReconcileBudget(time.Now()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	batchID, _, charges, err := budgetRepo.TakeBatch("next")
+	assert.NoError(t, err)
+	assert.Empty(t, batchID)
+	assert.Empty(t, charges)
+}
+
+// expectPayoutBalance ждёт проверки баланса перед заявкой на выплату при балансе 500.00, из которых promo — промо-кредит
+func expectPayoutBalance(mock sqlmock.Sqlmock, userID int, promo string) {
+	mock.ExpectQuery(`SELECT balance \+ promo_balance FROM auth_user`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("500.00"))
+	mock.ExpectQuery("SELECT promo_balance FROM auth_user").
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"promo_balance"}).AddRow(promo))
+}
+
+// expectPayoutLimits ждёт в транзакции заявки блокировки пользователя и сумм его заявок
+func expectPayoutLimits(mock sqlmock.Sqlmock, userID int, registeredAt time.Time) {
+	mock.ExpectQuery("SELECT created_at FROM auth_user WHERE id = \\$1 FOR UPDATE").
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(registeredAt))
+	for i := 0; i < 2; i++ {
+		mock.ExpectQuery("FROM payout_request").
+			WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow("0.00"))
+	}
+}
+
+// expectPayoutHold ждёт заведения заявки 7 пользователя 5 на 200.00
+func expectPayoutHold(mock sqlmock.Sqlmock, now time.Time) {
+	expectPayoutBalance(mock, 5, "0.00")
+	mock.ExpectBegin()
+	expectPayoutLimits(mock, 5, now.AddDate(-1, 0, 0))
+	mock.ExpectExec("UPDATE auth_user").
+		WithArgs("200.00", 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO payout_request").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(7, now, now))
+	expectLedgerEntry(mock, "payout_hold", "payout_request:7")
+	mock.ExpectCommit()
+}
+
+func expectLedgerEntry(mock sqlmock.Sqlmock, kind, reference string) {
+	mock.ExpectQuery("INSERT INTO ledger_entry").
+		WithArgs(kind, reference).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	for i := 0; i < 2; i++ {
+		mock.ExpectQuery("INSERT INTO ledger_account").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(i + 1))
+		mock.ExpectExec("INSERT INTO ledger_posting").WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+}
+
+func Test_RequestPayout_AboveThresholdWaitsForApproval(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	fake := gateway.NewFakeGateway(0)
+	uc := &PaymentUsecase{
+		logger:            zap.NewNop().Sugar(),
+		PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+		payoutPolicy:      entity.PayoutPolicy{MinAmount: entity.DecimalFromKopecks(1000), ApprovalThreshold: entity.DecimalFromKopecks(10000)},
+		Gateway:           fake,
+	}
+
+	now := time.Now()
+	expectPayoutHold(mock, now)
+
+	p, err := uc.RequestPayout(5, entity.DecimalFromKopecks(20000), "yoo_money", "4100", "")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.PayoutRequested, p.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_RequestPayout_ExcludesPromoCredit(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	uc := &PaymentUsecase{
+		logger:            zap.NewNop().Sugar(),
+		PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+		payoutPolicy:      entity.PayoutPolicy{MinAmount: entity.DecimalFromKopecks(1000)},
+	}
+
+	expectPayoutBalance(mock, 5, "450.00")
+
+	_, err := uc.RequestPayout(5, entity.DecimalFromKopecks(10000), "yoo_money", "4100", "")
+	assert.ErrorIs(t, err, entity.ErrInsufficientBalance)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_RequestPayout_SendsBelowThreshold(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	uc := &PaymentUsecase{
+		logger:            zap.NewNop().Sugar(),
+		PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+		payoutPolicy:      entity.PayoutPolicy{MinAmount: entity.DecimalFromKopecks(1000), ApprovalThreshold: entity.DecimalFromKopecks(100000)},
+		Gateway:           gateway.NewFakeGateway(0),
+	}
+
+	now := time.Now()
+	expectPayoutHold(mock, now)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE payout_request").
+		WithArgs(entity.PayoutApproved, "", "", 0, 7, entity.PayoutRequested).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(5))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE payout_request").
+		WithArgs(entity.PayoutSent, sqlmock.AnyArg(), "", 0, 7, entity.PayoutApproved).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(5))
+	expectLedgerEntry(mock, "payout", "payout_request:7")
+	mock.ExpectCommit()
+
+	mock.ExpectQuery("SELECT 1 FROM transaction").WillReturnError(sql.ErrNoRows)
+	mock.ExpectExec("INSERT INTO transaction").
+		WithArgs(sqlmock.AnyArg(), 5, "200.00", "payout_yoo_money", entity.TransactionPending, "RUB").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	p, err := uc.RequestPayout(5, entity.DecimalFromKopecks(20000), "yoo_money", "4100", "")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.PayoutSent, p.Status)
+	assert.NotEmpty(t, p.GatewayID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_RequestPayout_PolicyRejects(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	uc := &PaymentUsecase{
+		logger:            zap.NewNop().Sugar(),
+		PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+		payoutPolicy:      entity.PayoutPolicy{MinAmount: entity.DecimalFromKopecks(1000), MinAccountAge: 24 * time.Hour},
+	}
+
+	_, err := uc.RequestPayout(5, entity.DecimalFromKopecks(20000), "sbp", "x", "")
+	assert.ErrorIs(t, err, ErrInvalidPayoutDestination)
+
+	expectPayoutBalance(mock, 5, "0.00")
+	mock.ExpectBegin()
+	expectPayoutLimits(mock, 5, time.Now())
+	mock.ExpectRollback()
+	_, err = uc.RequestPayout(5, entity.DecimalFromKopecks(20000), "yoo_money", "4100", "")
+	assert.ErrorIs(t, err, entity.ErrPayoutAccountTooNew)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_RequestPayout_GatewayOutcome(t *testing.T) {
+	for name, tc := range map[string]struct {
+		status   int
+		expected entity.PayoutStatus
+	}{
+		"unknown outcome keeps hold": {http.StatusInternalServerError, entity.PayoutApproved},
+		"rejection releases hold":    {http.StatusBadRequest, entity.PayoutFailed},
+		"rate limit keeps hold":      {http.StatusTooManyRequests, entity.PayoutApproved},
+	} {
+		t.Run(name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+			var keys []string
+			uc := &PaymentUsecase{
+				logger:            zap.NewNop().Sugar(),
+				PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+				payoutPolicy:      entity.PayoutPolicy{MinAmount: entity.DecimalFromKopecks(1000)},
+				Gateway: gateway.NewYooKassaGateway("", "", "", &http.Client{Transport: roundTripper(func(req *http.Request) *http.Response {
+					keys = append(keys, req.Header.Get("Idempotence-Key"))
+					return &http.Response{StatusCode: tc.status, Body: ioutil.NopCloser(strings.NewReader(`{}`))}
+				})}),
+			}
+
+			now := time.Now()
+			expectPayoutHold(mock, now)
+			mock.ExpectBegin()
+			mock.ExpectQuery("UPDATE payout_request").
+				WithArgs(entity.PayoutApproved, "", "", 0, 7, entity.PayoutRequested).
+				WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(5))
+			mock.ExpectCommit()
+
+			mock.ExpectBegin()
+			mock.ExpectQuery("UPDATE payout_request").
+				WithArgs(tc.expected, "", sqlmock.AnyArg(), 0, 7, entity.PayoutApproved).
+				WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(5))
+			if tc.expected == entity.PayoutFailed {
+				mock.ExpectExec("UPDATE auth_user").
+					WithArgs("200.00", 5).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectLedgerEntry(mock, "payout_release", "payout_request:7")
+			}
+			mock.ExpectCommit()
+
+			p, err := uc.RequestPayout(5, entity.DecimalFromKopecks(20000), "yoo_money", "4100", "")
+			if tc.expected == entity.PayoutFailed {
+				assert.ErrorIs(t, err, gateway.ErrRejected)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expected, p.Status)
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			if tc.expected != entity.PayoutApproved {
+				return
+			}
+			// повтор идёт с тем же ключом идемпотентности и закрывает заявку при успехе
+			tc.status = http.StatusOK
+			mock.ExpectQuery("FROM payout_request").
+				WithArgs(entity.PayoutApproved, sqlmock.AnyArg(), sqlmock.AnyArg(), pendingSweepBatch).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "amount", "destination_type", "destination", "description", "status", "gateway_id", "failure_reason", "reviewed_by", "created_at", "updated_at"}).
+					AddRow(7, 5, "200.00", "yoo_money", "4100", "", entity.PayoutApproved, "", "timeout", 0, now, now))
+			mock.ExpectBegin()
+			mock.ExpectQuery("UPDATE payout_request").
+				WithArgs(entity.PayoutSent, sqlmock.AnyArg(), "", 0, 7, entity.PayoutApproved).
+				WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(5))
+			expectLedgerEntry(mock, "payout", "payout_request:7")
+			mock.ExpectCommit()
+			mock.ExpectQuery("SELECT 1 FROM transaction").WillReturnError(sql.ErrNoRows)
+			mock.ExpectExec("INSERT INTO transaction").WillReturnResult(sqlmock.NewResult(1, 1))
+
+			assert.NoError(t, uc.RetryApprovedPayouts(now))
+			assert.Len(t, keys, 2)
+			assert.Equal(t, keys[0], keys[1])
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_RefundTopUp_GatewayRejects(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	uc := &PaymentUsecase{
+		logger:            zap.NewNop().Sugar(),
+		PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+		Gateway: gateway.NewYooKassaGateway("", "", "", &http.Client{Transport: roundTripper(func(req *http.Request) *http.Response {
+			return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(strings.NewReader(`{"id":"rf1","payment_id":"pay1","status":"canceled"}`))}
+		})}),
+	}
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT id, transaction_id, user_id, amount, type, status, created_at, currency FROM transaction").
+		WithArgs("pay1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "transaction_id", "user_id", "amount", "type", "status", "created_at", "currency"}).
+			AddRow(1, "pay1", 5, "100.00", "yoomoney_payment", "1", now, "RUB"))
+	mock.ExpectQuery("FROM refund").
+		WithArgs(entity.RefundTopUp, "pay1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT amount\\s+FROM transaction").
+		WillReturnRows(sqlmock.NewRows([]string{"amount"}).AddRow("100.00"))
+	mock.ExpectQuery("FROM refund").
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow("0.00"))
+	mock.ExpectExec("UPDATE auth_user").
+		WithArgs("100.00", 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO refund").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, now))
+	expectLedgerEntry(mock, "refund", "refund:3")
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE refund").
+		WithArgs(entity.RefundCanceled, "rf1", 3, entity.RefundPending).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "amount"}).AddRow(5, "100.00"))
+	mock.ExpectExec("UPDATE auth_user").
+		WithArgs("100.00", 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectLedgerEntry(mock, "refund", "refund:3")
+	mock.ExpectCommit()
+
+	rf, err := uc.RefundTopUp(1, "pay1", entity.Decimal{}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.RefundCanceled, rf.Status)
+	assert.Equal(t, "100.00", rf.Amount.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_ReverseImpressionCharge_OnlyImpressions(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	uc := &PaymentUsecase{
+		logger:            zap.NewNop().Sugar(),
+		PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+	}
+
+	mock.ExpectQuery("FROM ledger_entry e").
+		WithArgs(int64(8)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "kind", "reference", "created_at", "owner_id", "type", "currency", "amount"}).
+			AddRow(8, "topup", "tx1", time.Now(), nil, "gateway", "RUB", "-10.00").
+			AddRow(8, "topup", "tx1", time.Now(), 5, "advertiser", "RUB", "10.00"))
+
+	_, err := uc.ReverseImpressionCharge(1, 8, "fraud")
+	assert.ErrorIs(t, err, entity.ErrRefundNotAllowed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+type memStatementStorage map[string][]byte
+
+func (m memStatementStorage) Upload(objectName, contentType string, data []byte) error {
+	m[objectName] = data
+	return nil
+}
+
+func (m memStatementStorage) Download(objectName string) ([]byte, error) {
+	data, ok := m[objectName]
+	if !ok {
+		return nil, storage.ErrObjectNotFound
+	}
+	return data, nil
+}
+
+var statementColumns = []string{"id", "user_id", "period_start", "period_end", "opening_balance", "closing_balance", "object_prefix", "created_at"}
+
+func Test_GenerateStatement_UploadsAllFormats(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	files := memStatementStorage{}
+	uc := &PaymentUsecase{
+		logger:            zap.NewNop().Sugar(),
+		PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+		StatementStorage:  files,
+		statementRenderer: statement.NewRenderer("/nonexistent/font.ttf"),
+	}
+
+	from, to := entity.StatementPeriod(time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC))
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(p.amount\\), 0\\)").
+		WithArgs(5, from, "RUB").
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow("10.00"))
+	mock.ExpectQuery("LEFT JOIN ledger_entry_placement").
+		WithArgs(5, from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"kind", "type", "currency", "banner_id", "slot_link", "counter_currency", "count", "sum", "counter_sum"}).
+			AddRow("impression_charge", "advertiser", "RUB", 7, "", "", 3, "-9.00", "0"))
+	mock.ExpectQuery("INSERT INTO statement").
+		WithArgs(5, from, to, "10.00", "1.00", "5/2025-04").
+		WillReturnRows(sqlmock.NewRows(statementColumns).AddRow(3, 5, from, to, "10.00", "1.00", "5/2025-04", to))
+
+	info, err := uc.GenerateStatement(5, from.AddDate(0, 0, 14), to.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, info.ID)
+	assert.Len(t, files, 3)
+	assert.Contains(t, string(files["5/2025-04.csv"]), "spend_by_banner,7,3,9.00")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_GenerateStatement_PeriodNotFinished(t *testing.T) {
+	uc := &PaymentUsecase{
+		logger:            zap.NewNop().Sugar(),
+		StatementStorage:  memStatementStorage{},
+		statementRenderer: statement.NewRenderer(""),
+	}
+
+	now := time.Date(2025, time.April, 20, 0, 0, 0, 0, time.UTC)
+	_, err := uc.GenerateStatement(5, now, now)
+	assert.ErrorIs(t, err, entity.ErrStatementPeriodOpen)
+}
+
+func Test_DownloadStatement_HidesForeignStatements(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	uc := &PaymentUsecase{
+		logger:            zap.NewNop().Sugar(),
+		PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+		StatementStorage:  memStatementStorage{"5/2025-04.pdf": []byte("%PDF-")},
+	}
+
+	from, to := entity.StatementPeriod(time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC))
+	for i := 0; i < 2; i++ {
+		mock.ExpectQuery("FROM statement WHERE id").
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows(statementColumns).AddRow(3, 5, from, to, "10.00", "1.00", "5/2025-04", to))
+	}
+
+	_, _, err := uc.DownloadStatement(6, 3, entity.StatementPDF, false)
+	assert.ErrorIs(t, err, entity.ErrStatementNotFound)
+
+	data, info, err := uc.DownloadStatement(1, 3, entity.StatementPDF, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("%PDF-"), data)
+	assert.Equal(t, 5, info.UserID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_GetTransactionHistory_NextCursor(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	uc := &PaymentUsecase{
+		logger:            zap.NewNop().Sugar(),
+		PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+	}
+
+	at := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"source", "id", "reference", "type", "raw_type", "status", "amount", "created_at"}
+	mock.ExpectQuery("FROM history").
+		WithArgs(5, 3).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("transaction", 3, "pay-3", "top_up", "yoomoney_payment", 1, "30.00", at.Add(2*time.Hour)).
+			AddRow("ledger", 8, "hold-8", "charge", "impression_charge", 1, "0.50", at.Add(time.Hour)).
+			AddRow("transaction", 1, "pay-1", "top_up", "yoomoney_payment", 1, "10.00", at))
+
+	page, err := uc.GetTransactionHistory(entity.HistoryFilter{UserID: 5, Desc: true, Limit: 2})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+
+	cursor, err := entity.DecodeHistoryCursor(page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.HistoryCursor{Sort: entity.HistorySortCreatedAt, Value: "2025-04-01T01:00:00Z", Source: "ledger", ID: 8, Type: entity.HistoryCharge}, cursor)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_GetTransactionHistory_RejectsForeignCursor(t *testing.T) {
+	uc := &PaymentUsecase{logger: zap.NewNop().Sugar()}
+
+	_, err := uc.GetTransactionHistory(entity.HistoryFilter{
+		UserID: 5,
+		Sort:   entity.HistorySortAmount,
+		After:  &entity.HistoryCursor{Sort: entity.HistorySortCreatedAt, Source: "ledger", ID: 1},
+	})
+	assert.ErrorIs(t, err, entity.ErrInvalidHistoryCursor)
+
+	_, err = uc.GetTransactionHistory(entity.HistoryFilter{UserID: 5, Limit: MaxHistoryPageSize + 1})
+	assert.ErrorIs(t, err, entity.ErrInvalidHistoryFilter)
+}
+
+func Test_ExchangeBalance_ForeignToBase(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	uc := &PaymentUsecase{
+		logger:            zap.NewNop().Sugar(),
+		PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+	}
+
+	at := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("FROM currency_rate_snapshot").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "source", "created_at", "currency", "rate"}).
+			AddRow(7, "admin", at, "USD", "90.000000"))
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO ledger_account").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+	mock.ExpectQuery("FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows([]string{"enough"}).AddRow(true))
+	mock.ExpectExec("UPDATE auth_user").
+		WithArgs(sqlmock.AnyArg(), 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO ledger_entry").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	for i := 0; i < 4; i++ {
+		mock.ExpectQuery("INSERT INTO ledger_account").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(i + 1))
+		mock.ExpectExec("INSERT INTO ledger_posting").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	mock.ExpectExec("INSERT INTO ledger_entry_conversion").
+		WithArgs(1, int64(7), "USD", sqlmock.AnyArg(), "RUB", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	conversion, err := uc.ExchangeBalance(5, "usd", "rub", entity.DecimalFromKopecks(250))
+	assert.NoError(t, err)
+	assert.Equal(t, "225.00", conversion.Amount.String())
+	assert.Equal(t, int64(7), conversion.SnapshotID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_ExchangeBalance_RejectsBadInput(t *testing.T) {
+	uc := &PaymentUsecase{logger: zap.NewNop().Sugar()}
+
+	_, err := uc.ExchangeBalance(5, "RUB", "rub", entity.DecimalFromKopecks(100))
+	assert.ErrorIs(t, err, entity.ErrSameCurrency)
+
+	_, err = uc.ExchangeBalance(5, "RUB", "USD", entity.DecimalFromKopecks(-100))
+	assert.ErrorIs(t, err, entity.ErrInvalidExchange)
+
+	_, err = uc.ExchangeBalance(5, "RUBLES", "USD", entity.DecimalFromKopecks(100))
+	assert.ErrorIs(t, err, entity.ErrInvalidCurrency)
+}
+
+func Test_SetPublisherTakeRate(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	uc := &PaymentUsecase{
+		logger:            zap.NewNop().Sugar(),
+		PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+		takeRates:         &sync.Map{},
+	}
+	uc.takeRates.Store(3, cachedTakeRate{rate: entity.DecimalFromKopecks(20), expiresAt: time.Now().Add(time.Hour)})
+
+	rate, _ := entity.ParseTakeRate("0.10")
+	assert.ErrorIs(t, uc.SetPublisherTakeRate(1, 3, entity.DecimalFromKopecks(100)), entity.ErrInvalidTakeRate)
+	assert.ErrorIs(t, uc.SetPublisherTakeRate(1, 3, entity.Decimal{}), entity.ErrInvalidTakeRate)
+
+	mock.ExpectExec("INSERT INTO publisher_take_rate").
+		WithArgs(3, rate).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, uc.SetPublisherTakeRate(1, 3, rate))
+	_, cached := uc.takeRates.Load(3)
+	assert.False(t, cached)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_AdjustBalance_PostsToAdjustmentAccount(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	uc := &PaymentUsecase{
+		logger:            zap.NewNop().Sugar(),
+		PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE auth_user").
+		WithArgs(entity.DecimalFromKopecks(1000), 7).
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("10.00"))
+	mock.ExpectQuery("INSERT INTO ledger_entry").
+		WithArgs(entity.EntryAdjustment, "adj-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	// деньги приходят со счёта ручных зачислений, а не со шлюза
+	mock.ExpectQuery("INSERT INTO ledger_account").
+		WithArgs(sqlmock.AnyArg(), entity.AccountAdjustment, entity.BaseCurrency).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec("INSERT INTO ledger_posting").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO ledger_account").
+		WithArgs(sqlmock.AnyArg(), entity.AccountAdvertiser, entity.BaseCurrency).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectExec("INSERT INTO ledger_posting").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, uc.AdjustBalance(1, 7, entity.DecimalFromKopecks(1000), "adj-1", "rid"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_SettleTransaction_AppliesTopUpPromo(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	uc := &PaymentUsecase{
+		logger:            zap.NewNop().Sugar(),
+		PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE transaction").
+		WithArgs(entity.TransactionSucceeded, "tx6").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE auth_user").
+		WithArgs("1000.00", 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectLedgerEntry(mock, "topup", "tx6")
+	mock.ExpectCommit()
+	mock.ExpectQuery("SELECT code FROM promo_topup").
+		WithArgs("tx6").
+		WillReturnRows(sqlmock.NewRows([]string{"code"}).AddRow("BONUS10"))
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM promo_code WHERE code").
+		WithArgs("BONUS10").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "kind", "value", "max_bonus", "max_redemptions", "per_user_limit",
+			"redemptions", "expires_at", "active", "created_by", "created_at"}).
+			AddRow(3, "BONUS10", "percent", "10", nil, 0, 1, 0, nil, true, 1, time.Now()))
+	mock.ExpectQuery("SELECT COUNT").
+		WithArgs(3, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	expectLedgerEntry(mock, "promo_credit", "tx6")
+	mock.ExpectQuery("INSERT INTO promo_redemption").
+		WithArgs(3, 5, "100.00", "1000.00", "tx6", int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, time.Now()))
+	mock.ExpectExec("UPDATE promo_code").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE auth_user SET promo_balance").
+		WithArgs("100.00", 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery("UPDATE payment_method SET active = TRUE").
+		WithArgs("tx6").
+		WillReturnError(sql.ErrNoRows)
+
+	trx := entity.Transaction{TransactionID: "tx6", UserID: 5, Amount: entity.DecimalFromKopecks(100000), Type: "yoomoney_payment", Currency: "RUB"}
+	applied, err := uc.SettleTransaction(trx, entity.TransactionSucceeded)
+	assert.NoError(t, err)
+	assert.True(t, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_CreatePromoCode_Validates(t *testing.T) {
+	uc := &PaymentUsecase{logger: zap.NewNop().Sugar()}
+
+	_, err := uc.CreatePromoCode(1, entity.PromoCode{Code: "x", Kind: entity.PromoFixed, Value: entity.DecimalFromKopecks(100)})
+	assert.ErrorIs(t, err, entity.ErrInvalidPromoCode)
+}
+
+func Test_SettleTransaction_ActivatesPaymentMethod(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	uc := &PaymentUsecase{
+		logger:            zap.NewNop().Sugar(),
+		PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE transaction").
+		WithArgs(entity.TransactionSucceeded, "tx7").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE auth_user").
+		WithArgs("10.00", 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectLedgerEntry(mock, "topup", "tx7")
+	mock.ExpectCommit()
+	mock.ExpectQuery("SELECT code FROM promo_topup").
+		WithArgs("tx7").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("UPDATE payment_method SET active = TRUE").
+		WithArgs("tx7").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "transaction_id", "active", "created_at"}).
+			AddRow("pm-1", 5, "Bank card *4444", "tx7", true, time.Now()))
+
+	trx := entity.Transaction{TransactionID: "tx7", UserID: 5, Amount: entity.DecimalFromKopecks(1000), Type: "yoomoney_payment", Currency: "RUB"}
+	applied, err := uc.SettleTransaction(trx, entity.TransactionSucceeded)
+	assert.NoError(t, err)
+	assert.True(t, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_SettleTransaction_AutoRechargeCanceled(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	uc := &PaymentUsecase{
+		logger:            zap.NewNop().Sugar(),
+		PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE transaction").
+		WithArgs(entity.TransactionCanceled, "tx8").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM auto_recharge WHERE user_id = \\$1 FOR UPDATE").
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "enabled", "threshold", "amount", "monthly_cap", "payment_method_id",
+			"failures", "next_attempt_at", "pending_transaction_id", "last_error"}).
+			AddRow(5, true, "100.00", "500.00", "1000.00", "pm-1", 2, nil, "tx8", ""))
+	mock.ExpectExec("UPDATE auto_recharge").
+		WithArgs(5, false, 3, sqlmock.AnyArg(), "", errAutoRechargeCanceled.Error()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	trx := entity.Transaction{TransactionID: "tx8", UserID: 5, Amount: entity.DecimalFromKopecks(50000), Type: entity.TransactionAutoRecharge, Currency: "RUB"}
+	applied, err := uc.SettleTransaction(trx, entity.TransactionCanceled)
+	assert.NoError(t, err)
+	assert.True(t, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_SweepStaleAutoRecharges_ReusesReservationKey(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	fake := gateway.NewFakeGateway(0)
+	uc := &PaymentUsecase{
+		logger:            zap.NewNop().Sugar(),
+		PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+		Gateway:           fake,
+	}
+
+	// первый запуск успел списать деньги, но не сохранил транзакцию
+	rule := entity.AutoRecharge{UserID: 5, Amount: entity.DecimalFromKopecks(50000)}
+	charged, err := uc.chargeAutoRecharge(rule, "k1")
+	assert.NoError(t, err)
+
+	mock.ExpectQuery("FROM auto_recharge a").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "enabled", "threshold", "amount", "monthly_cap", "payment_method_id",
+			"failures", "next_attempt_at", "pending_transaction_id", "last_error"}).
+			AddRow(5, true, "100.00", "500.00", "1000.00", "", 0, nil, "k1", ""))
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO transaction").
+		WithArgs(charged.ID, 5, "500.00", entity.TransactionAutoRecharge, entity.TransactionPending, "RUB").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE auto_recharge SET pending_transaction_id").
+		WithArgs(5, "k1", charged.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, uc.SweepStaleAutoRecharges(time.Now()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_SetAutoRecharge_RequiresSavedMethod(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	uc := &PaymentUsecase{
+		logger:            zap.NewNop().Sugar(),
+		PaymentRepository: repo.NewPaymentRepositoryWithDB(db, zap.NewNop().Sugar()),
+	}
+
+	_, err := uc.SetAutoRecharge(5, entity.AutoRecharge{Enabled: true, Threshold: entity.DecimalFromKopecks(10000),
+		Amount: entity.DecimalFromKopecks(50000), MonthlyCap: entity.DecimalFromKopecks(10000)})
+	assert.ErrorIs(t, err, entity.ErrInvalidAutoRecharge)
+
+	mock.ExpectQuery("FROM payment_method").
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "transaction_id", "active", "created_at"}))
+	_, err = uc.SetAutoRecharge(5, entity.AutoRecharge{Enabled: true, Threshold: entity.DecimalFromKopecks(10000),
+		Amount: entity.DecimalFromKopecks(50000), MonthlyCap: entity.DecimalFromKopecks(100000)})
+	assert.ErrorIs(t, err, entity.ErrNoPaymentMethod)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
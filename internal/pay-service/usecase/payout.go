@@ -0,0 +1,243 @@
+package payment
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"retarget/internal/pay-service/entity"
+	"retarget/internal/pay-service/repo"
+	"retarget/internal/pay-service/repo/gateway"
+)
+
+const (
+	payoutListLimit = 100
+	// payoutRetryWindow — сколько шлюз помнит ключ идемпотентности выплаты.
+	// Позже повтор с тем же ключом может создать вторую выплату, поэтому такие
+	// заявки остаются одобренными с удержанием и сверяются вручную
+	payoutRetryWindow = 24 * time.Hour
+)
+
+var ErrInvalidPayoutDestination = errors.New("invalid payout destination")
+
+func validatePayoutDestination(destinationType, destination string) error {
+	switch destinationType {
+	case "bank_card":
+		if len(destination) < 16 || len(destination) > 19 {
+			return fmt.Errorf("%w: invalid card number format", ErrInvalidPayoutDestination)
+		}
+	case "yoo_money":
+		if destination == "" {
+			return fmt.Errorf("%w: empty wallet number", ErrInvalidPayoutDestination)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported destination type %q", ErrInvalidPayoutDestination, destinationType)
+	}
+	return nil
+}
+
+// RequestPayout заводит заявку на вывод и удерживает её сумму с баланса.
+// Заявки ниже порога ручной проверки сразу отправляются в шлюз
+func (uc *PaymentUsecase) RequestPayout(userID int, amount entity.Decimal, destinationType, destination, description string) (entity.PayoutRequest, error) {
+	amount = amount.Money()
+	if err := validatePayoutDestination(destinationType, destination); err != nil {
+		return entity.PayoutRequest{}, err
+	}
+
+	// в Redis могут быть ещё не перенесённые в Postgres списания за показы,
+	// промо-кредит не выводится
+	available, err := uc.withdrawableBalance(userID, "")
+	if err != nil {
+		return entity.PayoutRequest{}, fmt.Errorf("failed to get balance: %w", err)
+	}
+	if available.Cmp(amount) < 0 {
+		return entity.PayoutRequest{}, entity.ErrInsufficientBalance
+	}
+
+	p, err := uc.PaymentRepository.CreatePayoutRequest(entity.PayoutRequest{
+		UserID:          userID,
+		Amount:          amount,
+		DestinationType: destinationType,
+		Destination:     destination,
+		Description:     description,
+	}, uc.payoutPolicy, time.Now().UTC())
+	if err != nil {
+		return p, err
+	}
+	uc.adjustBudget(userID, amount.Neg())
+
+	uc.logger.Infow("payout requested",
+		"payout_id", p.ID,
+		"user_id", userID,
+		"amount", amount.String(),
+		"needs_approval", uc.payoutPolicy.NeedsApproval(amount))
+
+	if uc.payoutPolicy.NeedsApproval(amount) {
+		return p, nil
+	}
+	return uc.approveAndSend(p, 0)
+}
+
+// ApprovePayout одобряет заявку, ожидающую ручной проверки, и отправляет её в шлюз
+func (uc *PaymentUsecase) ApprovePayout(adminID, payoutID int) (entity.PayoutRequest, error) {
+	p, err := uc.PaymentRepository.GetPayoutRequest(payoutID)
+	if err != nil {
+		return p, err
+	}
+	return uc.approveAndSend(p, adminID)
+}
+
+// RejectPayout отклоняет заявку и возвращает удержанные деньги на баланс
+func (uc *PaymentUsecase) RejectPayout(adminID, payoutID int, reason string) (entity.PayoutRequest, error) {
+	p, err := uc.PaymentRepository.GetPayoutRequest(payoutID)
+	if err != nil {
+		return p, err
+	}
+	if reason == "" {
+		reason = "rejected by reviewer"
+	}
+	return uc.failPayout(p, entity.PayoutRequested, entity.PayoutUpdate{FailureReason: reason, ReviewedBy: adminID})
+}
+
+func (uc *PaymentUsecase) GetPayoutRequests(userID int) ([]entity.PayoutRequest, error) {
+	return uc.PaymentRepository.GetPayoutRequests(userID, payoutListLimit)
+}
+
+// GetPendingPayouts отдаёт проверяющему заявки, ждущие решения
+func (uc *PaymentUsecase) GetPendingPayouts() ([]entity.PayoutRequest, error) {
+	return uc.PaymentRepository.GetPayoutsByStatus(entity.PayoutRequested, payoutListLimit)
+}
+
+func (uc *PaymentUsecase) approveAndSend(p entity.PayoutRequest, reviewerID int) (entity.PayoutRequest, error) {
+	ok, err := uc.PaymentRepository.TransitionPayout(p.ID, entity.PayoutRequested, entity.PayoutApproved, entity.PayoutUpdate{ReviewedBy: reviewerID}, entity.Decimal{}, nil)
+	if err != nil {
+		return p, err
+	}
+	if !ok {
+		return p, fmt.Errorf("%w: payout %d is %s", entity.ErrPayoutTransition, p.ID, p.Status)
+	}
+	p.Status = entity.PayoutApproved
+	p.ReviewedBy = reviewerID
+	return uc.sendPayout(p)
+}
+
+// RetryApprovedPayouts повторно отправляет одобренные заявки, исход отправки
+// которых неизвестен. Шлюз по тому же ключу идемпотентности вернёт уже
+// созданную выплату, если первый запрос до него дошёл
+func (uc *PaymentUsecase) RetryApprovedPayouts(olderThan time.Time) error {
+	stale, err := uc.PaymentRepository.GetStaleApprovedPayouts(olderThan.UTC(), time.Now().UTC().Add(-payoutRetryWindow), pendingSweepBatch)
+	if err != nil {
+		return err
+	}
+	for _, p := range stale {
+		if _, err := uc.sendPayout(p); err != nil {
+			uc.logger.Errorw("failed to retry payout",
+				"payout_id", p.ID,
+				"error", err)
+		}
+	}
+	return nil
+}
+
+// sendPayout отправляет одобренную заявку в шлюз. Ключ идемпотентности
+// выводится из ID заявки, поэтому повторная отправка не создаст вторую выплату.
+// Удержание возвращается только при явном отказе шлюза: при таймауте или
+// сбое выплата могла быть создана, и заявка ждёт повтора в RetryApprovedPayouts
+func (uc *PaymentUsecase) sendPayout(p entity.PayoutRequest) (entity.PayoutRequest, error) {
+	out, err := uc.Gateway.CreatePayout(gateway.PayoutRequest{
+		Amount:          gateway.Amount{Value: p.Amount.Money().String(), Currency: entity.BaseCurrency},
+		DestinationType: p.DestinationType,
+		Destination:     p.Destination,
+		Description:     p.Description,
+		IdempotenceKey:  repo.PayoutReference(p.ID),
+		UserID:          p.UserID,
+	})
+	if err != nil && !errors.Is(err, gateway.ErrRejected) && !errors.Is(err, gateway.ErrUnsupportedPayout) {
+		uc.logger.Warnw("payout gateway outcome unknown, will retry",
+			"payout_id", p.ID,
+			"user_id", p.UserID,
+			"error", err)
+		// отмечаем попытку, чтобы повтор шёл по очереди с остальными заявками
+		p.FailureReason = err.Error()
+		if _, touchErr := uc.PaymentRepository.TransitionPayout(p.ID, entity.PayoutApproved, entity.PayoutApproved, entity.PayoutUpdate{FailureReason: p.FailureReason}, entity.Decimal{}, nil); touchErr != nil {
+			return p, touchErr
+		}
+		return p, nil
+	}
+	if err != nil {
+		uc.logger.Errorw("payout rejected by gateway",
+			"payout_id", p.ID,
+			"user_id", p.UserID,
+			"error", err)
+		failed, failErr := uc.failPayout(p, entity.PayoutApproved, entity.PayoutUpdate{FailureReason: err.Error()})
+		if failErr != nil {
+			return failed, failErr
+		}
+		return failed, fmt.Errorf("payout gateway: %w", err)
+	}
+
+	entry := entity.PayoutSentEntry(p.Amount, repo.PayoutReference(p.ID))
+	ok, err := uc.PaymentRepository.TransitionPayout(p.ID, entity.PayoutApproved, entity.PayoutSent, entity.PayoutUpdate{GatewayID: out.ID}, entity.Decimal{}, &entry)
+	if err != nil {
+		return p, err
+	}
+	if !ok {
+		return p, fmt.Errorf("%w: payout %d was changed concurrently", entity.ErrPayoutTransition, p.ID)
+	}
+	p.Status = entity.PayoutSent
+	p.FailureReason = ""
+	p.GatewayID = out.ID
+
+	// итог выплаты приходит уведомлением или находится фоновой сверкой по transaction
+	trx := entity.Transaction{
+		TransactionID: out.ID,
+		UserID:        p.UserID,
+		Amount:        p.Amount,
+		Type:          "payout_" + p.DestinationType,
+		Status:        entity.TransactionPending,
+	}
+	if err := uc.PaymentRepository.CreateTransaction(trx); err != nil {
+		uc.logger.Errorw("failed to save payout transaction",
+			"payout_id", p.ID,
+			"gateway_id", out.ID,
+			"error", err)
+	}
+	return p, nil
+}
+
+// failPayout закрывает заявку в статусе failed и возвращает удержание на баланс
+func (uc *PaymentUsecase) failPayout(p entity.PayoutRequest, from entity.PayoutStatus, update entity.PayoutUpdate) (entity.PayoutRequest, error) {
+	entry := entity.PayoutReleaseEntry(p.UserID, p.Amount, repo.PayoutReference(p.ID))
+	ok, err := uc.PaymentRepository.TransitionPayout(p.ID, from, entity.PayoutFailed, update, p.Amount, &entry)
+	if err != nil {
+		return p, err
+	}
+	if !ok {
+		return p, fmt.Errorf("%w: payout %d is not %s", entity.ErrPayoutTransition, p.ID, from)
+	}
+	uc.adjustBudget(p.UserID, p.Amount)
+
+	p.Status = entity.PayoutFailed
+	p.FailureReason = update.FailureReason
+	if update.ReviewedBy != 0 {
+		p.ReviewedBy = update.ReviewedBy
+	}
+	return p, nil
+}
+
+// markPayoutReturned отмечает заявку, выплату по которой отменил шлюз. Деньги
+// к этому моменту уже возвращены при закрытии транзакции
+func (uc *PaymentUsecase) markPayoutReturned(gatewayID string) {
+	p, err := uc.PaymentRepository.GetPayoutByGatewayID(gatewayID)
+	if errors.Is(err, entity.ErrPayoutNotFound) {
+		return
+	}
+	if err == nil {
+		_, err = uc.PaymentRepository.TransitionPayout(p.ID, entity.PayoutSent, entity.PayoutReturned, entity.PayoutUpdate{FailureReason: "canceled by payment gateway"}, entity.Decimal{}, nil)
+	}
+	if err != nil {
+		uc.logger.Errorw("failed to mark payout returned",
+			"gateway_id", gatewayID,
+			"error", err)
+	}
+}
@@ -0,0 +1,142 @@
+package payment
+
+import (
+	"time"
+
+	"retarget/internal/pay-service/entity"
+)
+
+const promoRedemptionsLimit = 100
+
+// CreatePromoCode заводит промокод от имени администратора биллинга
+func (uc *PaymentUsecase) CreatePromoCode(adminID int, p entity.PromoCode) (entity.PromoCode, error) {
+	p.Code = entity.NormalizePromoCode(p.Code)
+	p.CreatedBy = adminID
+	if err := p.Validate(); err != nil {
+		return entity.PromoCode{}, err
+	}
+
+	created, err := uc.PaymentRepository.CreatePromoCode(p)
+	if err != nil {
+		return created, err
+	}
+	uc.logger.Infow("promo code created",
+		"promo_code_id", created.ID,
+		"code", created.Code,
+		"kind", created.Kind,
+		"value", created.Value.String(),
+		"admin_id", adminID)
+	return created, nil
+}
+
+func (uc *PaymentUsecase) GetPromoCodes() ([]entity.PromoCode, error) {
+	return uc.PaymentRepository.GetPromoCodes()
+}
+
+// SetPromoCodeActive включает или отключает код; уже начисленный кредит остаётся у пользователей
+func (uc *PaymentUsecase) SetPromoCodeActive(adminID, codeID int, active bool) (entity.PromoCode, error) {
+	p, err := uc.PaymentRepository.SetPromoCodeActive(codeID, active)
+	if err != nil {
+		return p, err
+	}
+	uc.logger.Infow("promo code updated",
+		"promo_code_id", codeID,
+		"active", active,
+		"admin_id", adminID)
+	return p, nil
+}
+
+// GetPromoCodeRedemptions отдаёт журнал погашений кода
+func (uc *PaymentUsecase) GetPromoCodeRedemptions(codeID int) ([]entity.PromoRedemption, error) {
+	return uc.PaymentRepository.GetPromoRedemptions(codeID, 0, promoRedemptionsLimit)
+}
+
+// GetPromoSummary отдаёт промо-кредит пользователя и его погашения
+func (uc *PaymentUsecase) GetPromoSummary(userID int) (entity.PromoSummary, error) {
+	balance, err := uc.PaymentRepository.GetPromoBalance(userID)
+	if err != nil {
+		return entity.PromoSummary{}, err
+	}
+	redemptions, err := uc.PaymentRepository.GetPromoRedemptions(0, userID, promoRedemptionsLimit)
+	if err != nil {
+		return entity.PromoSummary{}, err
+	}
+	return entity.PromoSummary{PromoBalance: balance, Redemptions: redemptions}, nil
+}
+
+// RedeemPromoCode сразу начисляет промо-кредит по фиксированному коду
+func (uc *PaymentUsecase) RedeemPromoCode(userID int, code string) (entity.PromoRedemption, error) {
+	pr, err := uc.PaymentRepository.RedeemPromoCode(entity.NormalizePromoCode(code), userID, nil, "", time.Now().UTC())
+	if err != nil {
+		return pr, err
+	}
+	uc.afterPromoCredit(pr)
+	return pr, nil
+}
+
+// checkTopUpPromo проверяет код до создания платежа, чтобы ошибка пришла сразу.
+// Лимиты перепроверяются при зачислении пополнения
+func (uc *PaymentUsecase) checkTopUpPromo(userID int, code, currency string) error {
+	if currency != entity.BaseCurrency {
+		return entity.ErrPromoCurrency
+	}
+	p, used, err := uc.PaymentRepository.GetPromoCode(code, userID)
+	if err != nil {
+		return err
+	}
+	return p.CheckRedeemable(time.Now().UTC(), used)
+}
+
+// applyTopUpPromo начисляет бонус по коду, указанному при создании платежа.
+// Если код за это время истёк или исчерпан, пополнение остаётся без бонуса
+func (uc *PaymentUsecase) applyTopUpPromo(trx entity.Transaction, amount entity.Decimal) {
+	code, err := uc.PaymentRepository.GetTopUpPromo(trx.TransactionID)
+	if err != nil || code == "" {
+		if err != nil {
+			uc.logger.Errorw("failed to get top-up promo code",
+				"transaction_id", trx.TransactionID,
+				"error", err)
+		}
+		return
+	}
+
+	pr, err := uc.PaymentRepository.RedeemPromoCode(code, trx.UserID, &amount, trx.TransactionID, time.Now().UTC())
+	if err != nil {
+		uc.logger.Warnw("promo code not applied to top-up",
+			"transaction_id", trx.TransactionID,
+			"user_id", trx.UserID,
+			"code", code,
+			"error", err)
+		return
+	}
+	uc.afterPromoCredit(pr)
+}
+
+func (uc *PaymentUsecase) afterPromoCredit(pr entity.PromoRedemption) {
+	uc.adjustBudget(pr.UserID, pr.Amount)
+	uc.logger.Infow("promo credit granted",
+		"promo_code_id", pr.PromoCodeID,
+		"code", pr.Code,
+		"user_id", pr.UserID,
+		"amount", pr.Amount.String(),
+		"transaction_id", pr.TransactionID)
+}
+
+// withdrawableBalance — остаток, который можно вывести или обменять: без промо-кредита.
+// Промо-кредит вычитается по данным Postgres, поэтому ещё не перенесённые
+// списания за показы только уменьшают результат
+func (uc *PaymentUsecase) withdrawableBalance(userID int, requestID string) (entity.Decimal, error) {
+	available, err := uc.availableBalance(userID, requestID)
+	if err != nil {
+		return entity.Decimal{}, err
+	}
+	promo, err := uc.PaymentRepository.GetPromoBalance(userID)
+	if err != nil {
+		return entity.Decimal{}, err
+	}
+	cash := available.Sub(promo)
+	if cash.Sign() < 0 {
+		return entity.DecimalFromKopecks(0), nil
+	}
+	return cash, nil
+}
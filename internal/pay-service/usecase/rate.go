@@ -0,0 +1,181 @@
+package payment
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"retarget/internal/pay-service/entity"
+
+	"github.com/google/uuid"
+)
+
+// LoadRatesFromFile записывает таблицу курсов из JSON-файла новым снимком.
+// Вызывается при старте, если задан EXCHANGE_RATES_FILE
+func (uc *PaymentUsecase) LoadRatesFromFile(path string) (entity.RateSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return entity.RateSnapshot{}, fmt.Errorf("failed to read rates file: %w", err)
+	}
+	rates, err := entity.ParseRates(data)
+	if err != nil {
+		return entity.RateSnapshot{}, err
+	}
+	snapshot, err := uc.PaymentRepository.SaveRateSnapshot("file:"+filepath.Base(path), 0, rates)
+	if err != nil {
+		return snapshot, err
+	}
+	uc.logger.Infow("exchange rates loaded",
+		"snapshot_id", snapshot.ID,
+		"path", path,
+		"currencies", len(rates))
+	return snapshot, nil
+}
+
+// SetRates заменяет таблицу курсов от имени администратора биллинга
+func (uc *PaymentUsecase) SetRates(adminID int, raw map[string]entity.Decimal) (entity.RateSnapshot, error) {
+	rates, err := entity.NormalizeRates(raw)
+	if err != nil {
+		return entity.RateSnapshot{}, err
+	}
+	snapshot, err := uc.PaymentRepository.SaveRateSnapshot("admin", adminID, rates)
+	if err != nil {
+		return snapshot, err
+	}
+	uc.logger.Infow("exchange rates updated",
+		"snapshot_id", snapshot.ID,
+		"admin_id", adminID,
+		"currencies", len(rates))
+	return snapshot, nil
+}
+
+func (uc *PaymentUsecase) GetRates() (entity.RateSnapshot, error) {
+	return uc.PaymentRepository.GetLatestRateSnapshot()
+}
+
+// checkCurrency проверяет, что в валюте можно принимать деньги: для всех,
+// кроме базовой, нужен курс в действующем снимке
+func (uc *PaymentUsecase) checkCurrency(code string) (string, error) {
+	currency, err := entity.NormalizeCurrency(code)
+	if err != nil || currency == entity.BaseCurrency {
+		return currency, err
+	}
+	snapshot, err := uc.PaymentRepository.GetLatestRateSnapshot()
+	if err != nil {
+		return "", err
+	}
+	if _, err := snapshot.Rate(currency); err != nil {
+		return "", err
+	}
+	return currency, nil
+}
+
+// ToBase пересчитывает сумму в базовую валюту по действующему снимку.
+// Для базовой валюты возвращает nil вместо конверсии
+func (uc *PaymentUsecase) ToBase(amount entity.Decimal, code string) (entity.Decimal, *entity.Conversion, error) {
+	currency, err := entity.NormalizeCurrency(code)
+	if err != nil {
+		return entity.Decimal{}, nil, err
+	}
+	if currency == entity.BaseCurrency {
+		return amount, nil, nil
+	}
+	snapshot, err := uc.PaymentRepository.GetLatestRateSnapshot()
+	if err != nil {
+		return entity.Decimal{}, nil, err
+	}
+	conversion, err := snapshot.Convert(amount, currency, entity.BaseCurrency)
+	if err != nil {
+		return entity.Decimal{}, nil, err
+	}
+	return conversion.Amount, &conversion, nil
+}
+
+// GetBalances отдаёт остатки по всем валютам. Базовый остаток — доступный, с
+// учётом холдов и несверенных списаний, остальные берутся из журнала
+func (uc *PaymentUsecase) GetBalances(userID int) ([]entity.CurrencyBalance, error) {
+	available, err := uc.availableBalance(userID, "")
+	if err != nil {
+		return nil, err
+	}
+	ledger, err := uc.PaymentRepository.GetLedgerBalances(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := []entity.CurrencyBalance{{Currency: entity.BaseCurrency, Balance: available}}
+	for _, b := range ledger {
+		if b.Currency != entity.BaseCurrency {
+			balances = append(balances, b)
+		}
+	}
+	return balances, nil
+}
+
+// ExchangeBalance обменивает amount валюты from на валюту to по действующему
+// снимку курсов. Снимок и курс сохраняются вместе с проводкой
+func (uc *PaymentUsecase) ExchangeBalance(userID int, from, to string, amount entity.Decimal) (entity.Conversion, error) {
+	from, err := entity.NormalizeCurrency(from)
+	if err != nil {
+		return entity.Conversion{}, err
+	}
+	to, err = entity.NormalizeCurrency(to)
+	if err != nil {
+		return entity.Conversion{}, err
+	}
+	if from == to {
+		return entity.Conversion{}, entity.ErrSameCurrency
+	}
+	kopecks, err := amount.Kopecks()
+	if err != nil || kopecks <= 0 {
+		return entity.Conversion{}, entity.ErrInvalidExchange
+	}
+	amount = entity.DecimalFromKopecks(kopecks)
+
+	snapshot, err := uc.PaymentRepository.GetLatestRateSnapshot()
+	if err != nil {
+		return entity.Conversion{}, err
+	}
+	conversion, err := snapshot.Convert(amount, from, to)
+	if err != nil {
+		return entity.Conversion{}, err
+	}
+	converted, err := conversion.Amount.Kopecks()
+	if err != nil || converted <= 0 {
+		return entity.Conversion{}, entity.ErrInvalidExchange
+	}
+
+	// в Redis могут быть ещё не перенесённые в Postgres списания за показы,
+	// промо-кредит не обменивается
+	if from == entity.BaseCurrency {
+		available, err := uc.withdrawableBalance(userID, "")
+		if err != nil {
+			return entity.Conversion{}, fmt.Errorf("failed to get balance: %w", err)
+		}
+		if available.Cmp(amount) < 0 {
+			return entity.Conversion{}, entity.ErrInsufficientBalance
+		}
+	}
+
+	entry := entity.ExchangeEntry(userID, conversion, "exchange:"+uuid.NewString())
+	entryID, err := uc.PaymentRepository.ExchangeBalance(userID, entry)
+	if err != nil {
+		return entity.Conversion{}, err
+	}
+	switch entity.BaseCurrency {
+	case from:
+		uc.adjustBudget(userID, amount.Neg())
+	case to:
+		uc.adjustBudget(userID, entity.DecimalFromKopecks(converted))
+	}
+
+	uc.logger.Infow("balance exchanged",
+		"user_id", userID,
+		"entry_id", entryID,
+		"snapshot_id", snapshot.ID,
+		"from", from,
+		"to", to,
+		"amount", amount.String(),
+		"converted", conversion.Amount.String())
+	return conversion, nil
+}
@@ -0,0 +1,210 @@
+package payment
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"retarget/internal/pay-service/entity"
+	"retarget/internal/pay-service/repo"
+	"retarget/internal/pay-service/repo/gateway"
+)
+
+// RefundTopUp возвращает пополнение через платёжный шлюз целиком (amount == 0)
+// или частично. Сумма сразу списывается с баланса и возвращается, если шлюз
+// возврат отклонил
+func (uc *PaymentUsecase) RefundTopUp(adminID int, transactionID string, amount entity.Decimal, reason string) (entity.Refund, error) {
+	trx, err := uc.PaymentRepository.GetTransactionByID(transactionID, transactionID)
+	if err != nil {
+		return entity.Refund{}, fmt.Errorf("%w: %v", entity.ErrRefundNotAllowed, err)
+	}
+	if trx.IsWithdrawal() || (trx.Type != "yoomoney_payment" && trx.Type != entity.TransactionAutoRecharge) || trx.Status != entity.TransactionSucceeded {
+		return entity.Refund{}, fmt.Errorf("%w: %s transaction in status %d", entity.ErrRefundNotAllowed, trx.Type, trx.Status)
+	}
+	// пополнения в другой валюте лежат на отдельном счёте журнала, их возврат пока не поддержан
+	if trx.Currency != "" && trx.Currency != entity.BaseCurrency {
+		return entity.Refund{}, fmt.Errorf("%w: %s top-up", entity.ErrRefundNotAllowed, trx.Currency)
+	}
+
+	amount = amount.Money()
+	if amount.Sign() == 0 {
+		refunds, err := uc.PaymentRepository.GetRefunds(entity.RefundTopUp, transactionID)
+		if err != nil {
+			return entity.Refund{}, err
+		}
+		amount = trx.Amount
+		for _, rf := range refunds {
+			if rf.Status != entity.RefundCanceled {
+				amount = amount.Sub(rf.Amount)
+			}
+		}
+	}
+	if amount.Sign() <= 0 {
+		return entity.Refund{}, entity.ErrRefundExceedsOriginal
+	}
+
+	rf, err := uc.PaymentRepository.CreateTopUpRefund(entity.Refund{
+		OriginalID: transactionID,
+		UserID:     trx.UserID,
+		Amount:     amount,
+		Reason:     reason,
+		CreatedBy:  adminID,
+	})
+	if err != nil {
+		return rf, err
+	}
+	uc.adjustBudget(rf.UserID, amount.Neg())
+
+	out, err := uc.Gateway.CreateRefund(gateway.RefundRequest{
+		PaymentID:      transactionID,
+		Amount:         gateway.Amount{Value: amount.String(), Currency: entity.BaseCurrency},
+		Description:    reason,
+		IdempotenceKey: repo.RefundReference(rf.ID),
+	})
+	if err != nil {
+		uc.logger.Errorw("refund gateway request failed",
+			"refund_id", rf.ID,
+			"transaction_id", transactionID,
+			"error", err)
+		rf, finishErr := uc.applyRefundStatus(rf, gateway.StatusCanceled, "")
+		if finishErr != nil {
+			return rf, finishErr
+		}
+		return rf, fmt.Errorf("refund gateway: %w", err)
+	}
+	return uc.applyRefundStatus(rf, out.Status, out.ID)
+}
+
+// applyRefundStatus переносит статус возврата из шлюза. Успешный возврат
+// попадает в историю транзакций, отменённый возвращает деньги на баланс
+func (uc *PaymentUsecase) applyRefundStatus(rf entity.Refund, gatewayStatus, gatewayID string) (entity.Refund, error) {
+	status := entity.RefundPending
+	switch gatewayStatus {
+	case gateway.StatusSucceeded:
+		status = entity.RefundSucceeded
+	case gateway.StatusCanceled:
+		status = entity.RefundCanceled
+	}
+
+	applied, err := uc.PaymentRepository.FinishRefund(rf.ID, status, gatewayID)
+	if err != nil || !applied {
+		return rf, err
+	}
+	rf.Status = status
+	if gatewayID != "" {
+		rf.GatewayID = gatewayID
+	}
+
+	switch status {
+	case entity.RefundCanceled:
+		uc.adjustBudget(rf.UserID, rf.Amount)
+	case entity.RefundSucceeded:
+		trx := entity.Transaction{
+			TransactionID: rf.GatewayID,
+			UserID:        rf.UserID,
+			Amount:        rf.Amount,
+			Type:          "refund_topup",
+			Status:        entity.TransactionSucceeded,
+		}
+		if err := uc.PaymentRepository.CreateTransaction(trx); err != nil {
+			uc.logger.Errorw("failed to save refund transaction",
+				"refund_id", rf.ID,
+				"error", err)
+		}
+	}
+
+	uc.logger.Infow("refund updated",
+		"refund_id", rf.ID,
+		"original_id", rf.OriginalID,
+		"status", status,
+		"amount", rf.Amount.String())
+	return rf, nil
+}
+
+// handleRefundNotification обрабатывает уведомление о возврате, который шлюз
+// провёл не сразу
+func (uc *PaymentUsecase) handleRefundNotification(gatewayID string) error {
+	rf, err := uc.PaymentRepository.GetRefundByGatewayID(gatewayID)
+	if errors.Is(err, entity.ErrRefundNotFound) {
+		uc.logger.Warnw("notification for unknown refund", "gateway_id", gatewayID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	statusStr, err := uc.fetchYooStatus(gateway.ObjectRefunds, gatewayID)
+	if err != nil {
+		return fmt.Errorf("verify notification: %w", err)
+	}
+	_, err = uc.applyRefundStatus(rf, statusStr, gatewayID)
+	return err
+}
+
+// ReverseImpressionCharge отменяет списание за показ, например при накрутке:
+// рекламодателю возвращается полная цена, с владельца слота снимается начисление
+func (uc *PaymentUsecase) ReverseImpressionCharge(adminID int, entryID int64, reason string) (entity.Refund, error) {
+	original, err := uc.PaymentRepository.GetLedgerEntry(entryID)
+	if errors.Is(err, repo.ErrLedgerEntryNotFound) {
+		return entity.Refund{}, entity.ErrRefundNotFound
+	}
+	if err != nil {
+		return entity.Refund{}, err
+	}
+	if original.Kind != entity.EntryImpressionCharge {
+		return entity.Refund{}, fmt.Errorf("%w: entry %d is %s", entity.ErrRefundNotAllowed, entryID, original.Kind)
+	}
+
+	rf, err := uc.PaymentRepository.ReverseImpressionCharge(original, reason, adminID)
+	if err != nil {
+		return rf, err
+	}
+
+	for _, p := range original.Postings {
+		if p.OwnerID == 0 {
+			continue
+		}
+		kopecks, err := p.Amount.Kopecks()
+		if err != nil {
+			continue
+		}
+		uc.adjustBudget(p.OwnerID, entity.DecimalFromKopecks(-kopecks))
+	}
+
+	uc.logger.Infow("impression charge reversed",
+		"refund_id", rf.ID,
+		"entry_id", entryID,
+		"advertiser_id", rf.UserID,
+		"publisher_id", rf.PublisherID,
+		"amount", rf.Amount.String())
+	return rf, nil
+}
+
+func (uc *PaymentUsecase) GetRefunds(kind entity.RefundKind, originalID string) ([]entity.Refund, error) {
+	return uc.PaymentRepository.GetRefunds(kind, originalID)
+}
+
+// parseEntryID разбирает ID проводки списания из запроса
+func parseEntryID(raw string) (int64, error) {
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: invalid ledger entry id %q", entity.ErrRefundNotAllowed, raw)
+	}
+	return id, nil
+}
+
+// Refund выполняет возврат нужного вида по ID исходной операции
+func (uc *PaymentUsecase) Refund(adminID int, kind entity.RefundKind, originalID string, amount entity.Decimal, reason string) (entity.Refund, error) {
+	switch kind {
+	case entity.RefundTopUp:
+		return uc.RefundTopUp(adminID, originalID, amount, reason)
+	case entity.RefundImpression:
+		entryID, err := parseEntryID(originalID)
+		if err != nil {
+			return entity.Refund{}, err
+		}
+		return uc.ReverseImpressionCharge(adminID, entryID, reason)
+	default:
+		return entity.Refund{}, fmt.Errorf("%w: unknown refund kind %q", entity.ErrRefundNotAllowed, kind)
+	}
+}
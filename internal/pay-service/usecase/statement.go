@@ -0,0 +1,166 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"retarget/internal/pay-service/entity"
+	"retarget/internal/pay-service/repo/storage"
+)
+
+const (
+	StatementGenerateInterval = time.Hour
+	statementPeriodLayout     = "2006-01"
+)
+
+var ErrInvalidStatementPeriod = errors.New("statement period must look like 2006-01")
+
+// GenerateStatement собирает выписку за календарный месяц, в который попадает
+// period, выкладывает её во всех форматах в хранилище и записывает в базу.
+// Повторная генерация за тот же месяц перезаписывает файлы
+func (uc *PaymentUsecase) GenerateStatement(userID int, period time.Time, now time.Time) (entity.StatementInfo, error) {
+	if uc.StatementStorage == nil || uc.statementRenderer == nil {
+		return entity.StatementInfo{}, errors.New("statement storage is not configured")
+	}
+
+	start, end := entity.StatementPeriod(period)
+	if end.After(now) {
+		return entity.StatementInfo{}, entity.ErrStatementPeriodOpen
+	}
+
+	opening, err := uc.PaymentRepository.GetLedgerBalanceAt(userID, start)
+	if err != nil {
+		return entity.StatementInfo{}, err
+	}
+	rows, err := uc.PaymentRepository.GetStatementRows(userID, start, end)
+	if err != nil {
+		return entity.StatementInfo{}, err
+	}
+	s, err := entity.BuildStatement(userID, start, end, opening, rows)
+	if err != nil {
+		return entity.StatementInfo{}, err
+	}
+	s.CreatedAt = now.UTC()
+
+	prefix := entity.StatementObjectPrefix(userID, start)
+	for _, format := range entity.StatementFormats {
+		data, err := uc.statementRenderer.Render(s, format)
+		if err != nil {
+			return entity.StatementInfo{}, err
+		}
+		if err := uc.StatementStorage.Upload(prefix+"."+string(format), format.ContentType(), data); err != nil {
+			return entity.StatementInfo{}, err
+		}
+	}
+
+	info, err := uc.PaymentRepository.SaveStatement(s, prefix)
+	if err != nil {
+		return entity.StatementInfo{}, err
+	}
+	uc.logger.Infow("statement generated",
+		"user_id", userID,
+		"statement_id", info.ID,
+		"period", start.Format(statementPeriodLayout))
+	return info, nil
+}
+
+// RequestStatement генерирует выписку по запросу пользователя; period в формате 2006-01
+func (uc *PaymentUsecase) RequestStatement(userID int, period string) (entity.StatementInfo, error) {
+	month, err := time.Parse(statementPeriodLayout, period)
+	if err != nil {
+		return entity.StatementInfo{}, ErrInvalidStatementPeriod
+	}
+	return uc.GenerateStatement(userID, month, time.Now())
+}
+
+// GenerateMonthlyStatements выпускает выписки за прошлый месяц всем, у кого их
+// ещё нет, и отправляет письмо о готовности. Ошибка по одному пользователю не
+// останавливает остальных
+func (uc *PaymentUsecase) GenerateMonthlyStatements(now time.Time) error {
+	currentStart, _ := entity.StatementPeriod(now)
+	previousStart := currentStart.AddDate(0, -1, 0)
+
+	owners, err := uc.PaymentRepository.GetStatementOwners(previousStart, currentStart)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range owners {
+		info, err := uc.GenerateStatement(userID, previousStart, now)
+		if err != nil {
+			uc.logger.Errorw("failed to generate statement",
+				"user_id", userID,
+				"period", previousStart.Format(statementPeriodLayout),
+				"error", err)
+			continue
+		}
+		uc.notifyStatementReady(info)
+	}
+	return nil
+}
+
+func (uc *PaymentUsecase) notifyStatementReady(info entity.StatementInfo) {
+	if uc.NoticeRepository == nil {
+		return
+	}
+	period := info.PeriodStart.Format(statementPeriodLayout)
+	if err := uc.NoticeRepository.SendStatementReadyEvent(info.UserID, info.ID, period); err != nil {
+		uc.logger.Errorw("failed to send statement notification",
+			"user_id", info.UserID,
+			"statement_id", info.ID,
+			"error", err)
+	}
+}
+
+// RunStatementGenerator периодически проверяет, не пора ли выпустить выписки
+// за прошедший месяц. Первый проход идёт сразу при старте
+func (uc *PaymentUsecase) RunStatementGenerator(ctx context.Context, interval time.Duration) {
+	if uc.StatementStorage == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := uc.GenerateMonthlyStatements(time.Now()); err != nil {
+			uc.logger.Errorw("statement generation failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (uc *PaymentUsecase) GetStatements(userID int) ([]entity.StatementInfo, error) {
+	return uc.PaymentRepository.GetStatements(userID)
+}
+
+// DownloadStatement отдаёт файл выписки. Чужие выписки доступны только
+// администраторам биллинга (billingAdmin — право сессии), остальным они не видны вовсе
+func (uc *PaymentUsecase) DownloadStatement(userID, statementID int, format entity.StatementFormat, billingAdmin bool) ([]byte, entity.StatementInfo, error) {
+	if uc.StatementStorage == nil {
+		return nil, entity.StatementInfo{}, errors.New("statement storage is not configured")
+	}
+
+	info, err := uc.PaymentRepository.GetStatement(statementID)
+	if err != nil {
+		return nil, entity.StatementInfo{}, err
+	}
+	if info.UserID != userID && !billingAdmin {
+		return nil, entity.StatementInfo{}, entity.ErrStatementNotFound
+	}
+
+	data, err := uc.StatementStorage.Download(info.ObjectName(format))
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, entity.StatementInfo{}, fmt.Errorf("%w: %s file is missing", entity.ErrStatementNotFound, format)
+	}
+	if err != nil {
+		return nil, entity.StatementInfo{}, err
+	}
+	return data, info, nil
+}
@@ -0,0 +1,225 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"retarget/internal/pay-service/entity"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// DefaultFontPath — шрифт с кириллицей; без него PDF собирается встроенным
+// Helvetica, а русский текст транслитерируется
+const DefaultFontPath = "/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf"
+
+const dateLayout = "02.01.2006"
+
+// Renderer собирает файлы выписки во всех поддерживаемых форматах
+type Renderer struct {
+	FontPath string
+}
+
+func NewRenderer(fontPath string) *Renderer {
+	if fontPath == "" {
+		fontPath = DefaultFontPath
+	}
+	return &Renderer{FontPath: fontPath}
+}
+
+func (r *Renderer) Render(s entity.Statement, format entity.StatementFormat) ([]byte, error) {
+	switch format {
+	case entity.StatementJSON:
+		return json.MarshalIndent(s, "", "  ")
+	case entity.StatementCSV:
+		return RenderCSV(s)
+	case entity.StatementPDF:
+		return RenderPDF(s, r.FontPath)
+	default:
+		return nil, fmt.Errorf("%w: %q", entity.ErrInvalidStatementFormat, format)
+	}
+}
+
+// summaryRows — итоговые строки выписки в том порядке, в котором их показываем
+func summaryRows(s entity.Statement) [][2]string {
+	return [][2]string{
+		{"Остаток на начало периода", s.OpeningBalance.String()},
+		{"Пополнения", s.TopUps.String()},
+		{"Промо-кредит", s.PromoCredits.String()},
+		{"Расход на показы", s.Spend.String()},
+		{"Доход от показов", s.Earnings.String()},
+		{"Выплаты", s.Payouts.String()},
+		{"Возвраты", s.Refunds.String()},
+		{"Обмен валют", s.Exchanges.String()},
+		{"Прочие движения", s.Other.String()},
+		{"Остаток на конец периода", s.ClosingBalance.String()},
+	}
+}
+
+func periodTitle(s entity.Statement) string {
+	// PeriodEnd не входит в период, поэтому показываем предыдущий день
+	return fmt.Sprintf("Выписка за %s – %s", s.PeriodStart.Format(dateLayout), s.PeriodEnd.AddDate(0, 0, -1).Format(dateLayout))
+}
+
+func original(line entity.StatementLine) (string, string) {
+	if line.OriginalAmount == nil {
+		return "", ""
+	}
+	return line.OriginalCurrency, line.OriginalAmount.String()
+}
+
+// RenderCSV выгружает выписку таблицей section,key,impressions,amount,original_currency,original_amount.
+// amount всегда в базовой валюте, кроме foreign_movement, где это движение по счёту в валюте key
+func RenderCSV(s entity.Statement) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	records := [][]string{{"section", "key", "impressions", "amount", "original_currency", "original_amount"}}
+	for _, row := range summaryRows(s) {
+		records = append(records, []string{"summary", row[0], "", row[1], "", ""})
+	}
+	for _, line := range s.SpendByBanner {
+		currency, amount := original(line)
+		records = append(records, []string{"spend_by_banner", strconv.FormatInt(line.BannerID, 10), strconv.Itoa(line.Impressions), line.Amount.String(), currency, amount})
+	}
+	for _, line := range s.EarningsBySlot {
+		records = append(records, []string{"earnings_by_slot", line.SlotLink, strconv.Itoa(line.Impressions), line.Amount.String(), "", ""})
+	}
+	for _, line := range s.ExchangeLines {
+		records = append(records, []string{"exchange", line.Currency, "", line.Amount.String(), line.Currency, line.OriginalAmount.String()})
+	}
+	for _, m := range s.ForeignMovements {
+		records = append(records, []string{"foreign_movement", m.Currency, "", m.Amount.String(), "", ""})
+	}
+	if err := w.WriteAll(records); err != nil {
+		return nil, fmt.Errorf("failed to write statement csv: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderPDF рисует выписку на одной или нескольких страницах A4
+func RenderPDF(s entity.Statement, fontPath string) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetCreationDate(s.CreatedAt)
+	pdf.SetModificationDate(s.CreatedAt)
+	pdf.SetCatalogSort(true)
+
+	text := func(str string) string { return str }
+	family := "Helvetica"
+	if font, err := os.ReadFile(fontPath); err == nil {
+		pdf.AddUTF8FontFromBytes("DejaVu", "", font)
+		family = "DejaVu"
+	} else {
+		text = transliterate
+	}
+	pdf.SetTitle(text(periodTitle(s)), true)
+
+	pdf.AddPage()
+	pdf.SetFont(family, "", 14)
+	pdf.CellFormat(0, 10, text(periodTitle(s)), "", 1, "L", false, 0, "")
+	pdf.SetFont(family, "", 10)
+	pdf.CellFormat(0, 6, text(fmt.Sprintf("Пользователь №%d", s.UserID)), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	for _, row := range summaryRows(s) {
+		pdf.CellFormat(120, 7, text(row[0]), "B", 0, "L", false, 0, "")
+		pdf.CellFormat(50, 7, row[1], "B", 1, "R", false, 0, "")
+	}
+
+	table := func(title, keyHeader string, lines []entity.StatementLine, key func(entity.StatementLine) string) {
+		if len(lines) == 0 {
+			return
+		}
+		pdf.Ln(6)
+		pdf.SetFont(family, "", 12)
+		pdf.CellFormat(0, 8, text(title), "", 1, "L", false, 0, "")
+		pdf.SetFont(family, "", 10)
+		pdf.CellFormat(90, 7, text(keyHeader), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(30, 7, text("Показы"), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(50, 7, text("Сумма"), "1", 1, "R", false, 0, "")
+		for _, line := range lines {
+			pdf.CellFormat(90, 7, text(key(line)), "1", 0, "L", false, 0, "")
+			pdf.CellFormat(30, 7, strconv.Itoa(line.Impressions), "1", 0, "R", false, 0, "")
+			pdf.CellFormat(50, 7, line.Amount.String(), "1", 1, "R", false, 0, "")
+		}
+	}
+	table("Расход по баннерам", "Баннер", s.SpendByBanner, func(l entity.StatementLine) string {
+		key := "—"
+		if l.BannerID != 0 {
+			key = "#" + strconv.FormatInt(l.BannerID, 10)
+		}
+		if currency, amount := original(l); currency != "" {
+			key += fmt.Sprintf(" (%s %s)", amount, currency)
+		}
+		return key
+	})
+	table("Доход по слотам", "Слот", s.EarningsBySlot, func(l entity.StatementLine) string {
+		if l.SlotLink == "" {
+			return "—"
+		}
+		return l.SlotLink
+	})
+
+	pairs := func(title string, rows [][2]string) {
+		if len(rows) == 0 {
+			return
+		}
+		pdf.Ln(6)
+		pdf.SetFont(family, "", 12)
+		pdf.CellFormat(0, 8, text(title), "", 1, "L", false, 0, "")
+		pdf.SetFont(family, "", 10)
+		for _, row := range rows {
+			pdf.CellFormat(120, 7, text(row[0]), "B", 0, "L", false, 0, "")
+			pdf.CellFormat(50, 7, row[1], "B", 1, "R", false, 0, "")
+		}
+	}
+	exchanges := make([][2]string, 0, len(s.ExchangeLines))
+	for _, line := range s.ExchangeLines {
+		exchanges = append(exchanges, [2]string{line.OriginalAmount.String() + " " + line.Currency, line.Amount.String()})
+	}
+	pairs("Обмен валют", exchanges)
+	movements := make([][2]string, 0, len(s.ForeignMovements))
+	for _, m := range s.ForeignMovements {
+		movements = append(movements, [2]string{m.Currency, m.Amount.String()})
+	}
+	pairs("Движения по счетам в других валютах", movements)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render statement pdf: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+var translitTable = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", '№': "No", '–': "-", '—': "-",
+}
+
+// transliterate оставляет только то, что умеют встроенные шрифты PDF
+func transliterate(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		lower := []rune(strings.ToLower(string(r)))[0]
+		repl, ok := translitTable[lower]
+		switch {
+		case !ok && r < 128:
+			b.WriteRune(r)
+		case !ok:
+			b.WriteRune('?')
+		case lower != r && repl != "":
+			b.WriteString(strings.ToUpper(repl[:1]) + repl[1:])
+		default:
+			b.WriteString(repl)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,58 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"retarget/internal/pay-service/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testStatement() entity.Statement {
+	start, end := entity.StatementPeriod(time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC))
+	s, _ := entity.BuildStatement(5, start, end, entity.DecimalFromKopecks(1000), []entity.StatementRow{
+		{Kind: entity.EntryImpressionCharge, Account: entity.AccountAdvertiser, BannerID: 7, CounterCurrency: "USD", Count: 3, Amount: entity.DecimalFromKopecks(-900), CounterAmount: entity.DecimalFromKopecks(10)},
+	})
+	s.CreatedAt = end
+	return s
+}
+
+func TestRenderCSV(t *testing.T) {
+	data, err := RenderCSV(testStatement())
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "section,key,impressions,amount,original_currency,original_amount\n")
+	assert.Contains(t, string(data), "summary,Остаток на конец периода,,1.00,,\n")
+	assert.Contains(t, string(data), "spend_by_banner,7,3,9.00,USD,0.10\n")
+}
+
+func TestRender_JSON(t *testing.T) {
+	data, err := NewRenderer("").Render(testStatement(), entity.StatementJSON)
+	assert.NoError(t, err)
+
+	var decoded entity.Statement
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "9.00", decoded.Spend.String())
+}
+
+func TestRenderPDF_WithoutFont(t *testing.T) {
+	data, err := RenderPDF(testStatement(), "/nonexistent/font.ttf")
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF-")))
+}
+
+func TestTransliterate(t *testing.T) {
+	assert.Equal(t, "Vyplaty No5 - ok", transliterate("Выплаты №5 – ok"))
+}
+
+func TestRenderPDF_WithUTF8Font(t *testing.T) {
+	if _, err := os.Stat(DefaultFontPath); err != nil {
+		t.Skip("DejaVuSans is not installed")
+	}
+	data, err := RenderPDF(testStatement(), DefaultFontPath)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF-")))
+}
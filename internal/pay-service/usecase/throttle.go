@@ -0,0 +1,81 @@
+package payment
+
+import (
+	"context"
+	"strconv"
+
+	"retarget/pkg/utils/throttle"
+)
+
+func throttleUserKey(userID int) string {
+	return "user:" + strconv.Itoa(userID)
+}
+
+func throttleIPKey(ip string) string {
+	return "ip:" + ip
+}
+
+// CheckThrottle отклоняет попытку action, пока аккаунт или адрес ждут окончания
+// задержки. Без лимитера и при недоступном Redis попытки не блокируются
+func (uc *PaymentUsecase) CheckThrottle(ctx context.Context, action string, userID int, ip string) error {
+	rule := uc.throttleRules[action]
+	if rule == nil {
+		return nil
+	}
+
+	err := rule.Check(ctx, throttleUserKey(userID))
+	if err == nil && ip != "" {
+		err = rule.Check(ctx, throttleIPKey(ip))
+	}
+	if err == nil {
+		return nil
+	}
+	if _, limited := throttle.IsThrottled(err); limited {
+		uc.logger.Warnw("too many failed attempts",
+			"action", action,
+			"user_id", userID,
+			"ip", ip)
+		return err
+	}
+
+	uc.logger.Errorw("throttle unavailable", "action", action, "error", err)
+	return nil
+}
+
+// ThrottleFailed засчитывает неудачную попытку action аккаунту и адресу
+func (uc *PaymentUsecase) ThrottleFailed(ctx context.Context, action string, userID int, ip string) {
+	rule := uc.throttleRules[action]
+	if rule == nil {
+		return
+	}
+
+	keys := []string{throttleUserKey(userID)}
+	if ip != "" {
+		keys = append(keys, throttleIPKey(ip))
+	}
+	for _, key := range keys {
+		result, err := rule.Fail(ctx, key)
+		if err != nil {
+			uc.logger.Errorw("failed to count failed attempt", "action", action, "error", err)
+			continue
+		}
+		if result.Locked {
+			uc.logger.Warnw("attempts locked",
+				"action", action,
+				"key", key,
+				"failures", result.Failures)
+		}
+	}
+}
+
+// ThrottleSucceeded забывает неудачи аккаунта. Неудачи адреса не сбрасываются,
+// как и при входе: иначе перебор чередовали бы с удачными попытками своего аккаунта
+func (uc *PaymentUsecase) ThrottleSucceeded(ctx context.Context, action string, userID int) {
+	rule := uc.throttleRules[action]
+	if rule == nil {
+		return
+	}
+	if err := rule.Reset(ctx, throttleUserKey(userID)); err != nil {
+		uc.logger.Errorw("failed to reset failed attempts", "action", action, "error", err)
+	}
+}
@@ -0,0 +1,158 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"retarget/internal/pay-service/entity"
+	"retarget/internal/pay-service/repo/gateway"
+)
+
+const (
+	PaymentSweepInterval = time.Minute
+	// pendingSweepAge — сколько ждём уведомления, прежде чем спросить статус сами
+	pendingSweepAge   = 5 * time.Minute
+	pendingSweepBatch = 100
+)
+
+var ErrUnknownNotification = errors.New("unsupported YooKassa notification")
+
+// HandleYooNotification обрабатывает уведомление YooKassa. Статус берётся не
+// из тела уведомления, а из повторного запроса объекта к API
+func (uc *PaymentUsecase) HandleYooNotification(n entity.YooNotification) error {
+	objectType := n.ObjectType()
+	if objectType == "" || n.Object.ID == "" {
+		return fmt.Errorf("%w: %s", ErrUnknownNotification, n.Event)
+	}
+	if objectType == gateway.ObjectRefunds {
+		return uc.handleRefundNotification(n.Object.ID)
+	}
+
+	trx, err := uc.PaymentRepository.GetTransactionByID(n.Object.ID, n.Object.ID)
+	if err != nil {
+		uc.logger.Warnw("notification for unknown transaction",
+			"event", n.Event,
+			"object_id", n.Object.ID,
+			"error", err)
+		return nil
+	}
+	if trx.YooObjectType() != objectType {
+		return fmt.Errorf("%w: %s for %s transaction", ErrUnknownNotification, n.Event, trx.Type)
+	}
+
+	statusStr, err := uc.fetchYooStatus(objectType, trx.TransactionID)
+	if err != nil {
+		return fmt.Errorf("verify notification: %w", err)
+	}
+	_, err = uc.SettleTransaction(*trx, mapYooStatus(statusStr))
+	return err
+}
+
+// SettleTransaction применяет итоговый статус транзакции: зачисляет успешное
+// пополнение или возвращает деньги отменённой выплаты. Повторный вызов ничего не меняет
+func (uc *PaymentUsecase) SettleTransaction(trx entity.Transaction, status int) (bool, error) {
+	if status != entity.TransactionSucceeded && status != entity.TransactionCanceled {
+		return false, nil
+	}
+
+	var (
+		delta entity.Decimal
+		entry *entity.LedgerEntry
+	)
+	amount := trx.Amount.Money()
+	foreign := trx.Currency != "" && trx.Currency != entity.BaseCurrency
+	switch {
+	case status == entity.TransactionSucceeded && !trx.IsWithdrawal() && foreign:
+		// пополнение в другой валюте остаётся на своём счёте, auth_user.balance не меняется
+		topUp := entity.TopUpEntry(trx.UserID, amount, trx.TransactionID).InCurrency(trx.Currency)
+		entry = &topUp
+	case status == entity.TransactionSucceeded && !trx.IsWithdrawal():
+		delta = amount
+		topUp := entity.TopUpEntry(trx.UserID, amount, trx.TransactionID)
+		entry = &topUp
+	case status == entity.TransactionCanceled && trx.IsWithdrawal():
+		delta = amount
+		reversal := entity.PayoutReversalEntry(trx.UserID, amount, trx.TransactionID)
+		entry = &reversal
+	}
+
+	applied, err := uc.PaymentRepository.SettleTransaction(trx.TransactionID, status, trx.UserID, delta, entry)
+	if err != nil || !applied {
+		return applied, err
+	}
+
+	uc.logger.Infow("transaction settled",
+		"transaction_id", trx.TransactionID,
+		"type", trx.Type,
+		"status", status,
+		"user_id", trx.UserID,
+		"delta", delta.String())
+	if entry != nil && delta.Sign() != 0 {
+		uc.adjustBudget(trx.UserID, delta)
+	}
+	if entry != nil && !trx.IsWithdrawal() && !foreign {
+		uc.afterTopUp(trx.UserID, delta)
+		uc.applyTopUpPromo(trx, delta)
+	}
+	if status == entity.TransactionCanceled && trx.IsWithdrawal() {
+		uc.markPayoutReturned(trx.TransactionID)
+	}
+	if status == entity.TransactionSucceeded && trx.Type == "yoomoney_payment" {
+		uc.activatePaymentMethod(trx)
+	}
+	if trx.Type == entity.TransactionAutoRecharge {
+		uc.settleAutoRecharge(trx, status)
+	}
+	return true, nil
+}
+
+// RunPaymentSweeper периодически досверяет транзакции, уведомления по
+// которым так и не пришли, повторяет выплаты с неизвестным исходом и
+// доводит до конца брошенные автопополнения
+func (uc *PaymentUsecase) RunPaymentSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := uc.SweepPendingTransactions(time.Now().Add(-pendingSweepAge)); err != nil {
+				uc.logger.Errorw("pending transactions sweep failed", "error", err)
+			}
+			if err := uc.RetryApprovedPayouts(time.Now().Add(-pendingSweepAge)); err != nil {
+				uc.logger.Errorw("approved payouts retry failed", "error", err)
+			}
+			if err := uc.SweepStaleAutoRecharges(time.Now().Add(-pendingSweepAge)); err != nil {
+				uc.logger.Errorw("stale auto-recharges sweep failed", "error", err)
+			}
+			uc.purgeIdempotencyKeys(time.Now())
+		}
+	}
+}
+
+func (uc *PaymentUsecase) SweepPendingTransactions(olderThan time.Time) error {
+	pending, err := uc.PaymentRepository.ClaimStalePendingTransactions(olderThan.UTC(), pendingSweepBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, trx := range pending {
+		statusStr, err := uc.fetchYooStatus(trx.YooObjectType(), trx.TransactionID)
+		if err != nil {
+			uc.logger.Warnw("failed to fetch transaction status",
+				"transaction_id", trx.TransactionID,
+				"error", err)
+			continue
+		}
+		if _, err := uc.SettleTransaction(trx, mapYooStatus(statusStr)); err != nil {
+			uc.logger.Errorw("failed to settle transaction",
+				"transaction_id", trx.TransactionID,
+				"error", err)
+		}
+	}
+	return nil
+}
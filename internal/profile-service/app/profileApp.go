@@ -14,8 +14,11 @@ import (
 	usecaseProfile "retarget/internal/profile-service/usecase/profile"
 
 	authenticate "retarget/pkg/middleware/auth"
+	protoPayment "retarget/pkg/proto/payment"
 
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 func Run(cfg *configs.Config, logger *zap.SugaredLogger) {
@@ -30,7 +33,14 @@ func Run(cfg *configs.Config, logger *zap.SugaredLogger) {
 		}
 	}()
 	avatarRepository := repoAvatar.NewAvatarRepository(cfg.Minio.EndPoint, cfg.Minio.AccessKeyID, cfg.Minio.SecretAccesKey, cfg.Minio.Token, false, "avatar", logger)
-	profileUsecase := usecaseProfile.NewProfileUsecase(profileRepository)
+	// настройки уведомлений о балансе хранит pay-service
+	connPayment, err := grpc.NewClient("ReTargetApiPayment:8054", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("did not connect: %v", err)
+	}
+	defer connPayment.Close()
+
+	profileUsecase := usecaseProfile.NewProfileUsecase(profileRepository, protoPayment.NewPaymentServiceClient(connPayment))
 	avatarUsecase := usecaseAvatar.NewAvatarUsecase(avatarRepository)
 
 	mux := profileAppHttp.SetupRoutes(authenticator, profileUsecase, avatarUsecase, logger)
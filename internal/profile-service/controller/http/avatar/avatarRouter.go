@@ -23,8 +23,10 @@ func SetupAvatarRoutes(authenticator *authenticate.Authenticator, avatarUsecase
 	muxRouter := mux.NewRouter()
 	avatarController := NewAvatarController(avatarUsecase, sugar)
 
-	muxRouter.Handle("/api/v1/avatar/download", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(avatarController.DownloadAvatarHandler))))
-	muxRouter.Handle("/api/v1/avatar/upload", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(avatarController.UploadAvatarHandler))))
+	profile := authenticate.Require(authenticate.PermProfile)
+
+	muxRouter.Handle("/api/v1/avatar/download", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(profile(http.HandlerFunc(avatarController.DownloadAvatarHandler)))))
+	muxRouter.Handle("/api/v1/avatar/upload", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(profile(http.HandlerFunc(avatarController.UploadAvatarHandler)))))
 
 	return muxRouter
 }
@@ -0,0 +1,21 @@
+package avatar
+
+import (
+	"net/http"
+	"testing"
+
+	usecaseAvatar "retarget/internal/profile-service/usecase/avatar"
+	authenticate "retarget/pkg/middleware/auth"
+	"retarget/pkg/middleware/auth/authtest"
+
+	"go.uber.org/zap"
+)
+
+func TestSetupAvatarRoutes_AccessMatrix(t *testing.T) {
+	router := SetupAvatarRoutes(authtest.Authenticator(t), &usecaseAvatar.AvatarUsecase{}, zap.NewNop().Sugar())
+
+	authtest.AssertRoutes(t, router, []authtest.Route{
+		{Method: http.MethodGet, Path: "/api/v1/avatar/download", Roles: authenticate.Roles},
+		{Method: http.MethodPut, Path: "/api/v1/avatar/upload", Roles: authenticate.Roles},
+	})
+}
@@ -24,7 +24,7 @@ func (c *AvatarController) DownloadAvatarHandler(w http.ResponseWriter, r *http.
 		//nolint:errcheck
 		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
 	}
-	userID := user.UserID
+	userID := user.Actor()
 
 	object, err := c.avatarUsecase.DownloadAvatar(userID, requestID)
 	if err != nil {
@@ -22,7 +22,7 @@ func (c *AvatarController) UploadAvatarHandler(w http.ResponseWriter, r *http.Re
 		//nolint:errcheck
 		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
 	}
-	userID := user.UserID
+	userID := user.Actor()
 
 	if r.ContentLength > (10 << 20) {
 		w.WriteHeader(http.StatusUnprocessableEntity)
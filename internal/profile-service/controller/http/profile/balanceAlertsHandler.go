@@ -0,0 +1,65 @@
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	entity "retarget/pkg/entity"
+)
+
+// BalanceAlertsHandler отдаёт (GET) или сохраняет (PUT) пороги уведомлений о низком балансе
+func (c *ProfileController) BalanceAlertsHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value(entity.СtxKeyRequestID{}).(string)
+	if r.Method != http.MethodGet && r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Method Not Allowed"))
+		return
+	}
+
+	user, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
+		return
+	}
+
+	var settings entity.BalanceAlertSettings
+	var err error
+	if r.Method == http.MethodGet {
+		settings, err = c.profileUsecase.GetBalanceAlerts(user.UserID, requestID)
+	} else {
+		var req entity.BalanceAlertSettings
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			//nolint:errcheck
+			json.NewEncoder(w).Encode(entity.NewResponse(true, "Invalid request body"))
+			return
+		}
+		settings, err = c.profileUsecase.PutBalanceAlerts(user.UserID, req, requestID)
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, entity.ErrInvalidBalanceAlerts) {
+			status = http.StatusUnprocessableEntity
+		}
+		w.WriteHeader(status)
+		//nolint:errcheck
+		json.NewEncoder(w).Encode(entity.NewResponse(true, err.Error()))
+		return
+	}
+
+	verdict := entity.NewResponse(false, "Sent")
+	response := struct {
+		Service *entity.ServiceResponse     `json:"service"`
+		Body    entity.BalanceAlertSettings `json:"body"`
+	}{
+		Service: &verdict.Service,
+		Body:    settings,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	//nolint:errcheck
+	json.NewEncoder(w).Encode(response)
+}
@@ -33,7 +33,7 @@ func (c *ProfileController) EditProfileHandler(w http.ResponseWriter, r *http.Re
 			return
 		}
 	}
-	userID := user.UserID
+	userID := user.Actor()
 
 	var profileRequest entityProfile.ProfileRequest
 	err := json.NewDecoder(r.Body).Decode(&profileRequest)
@@ -24,7 +24,7 @@ func (c *ProfileController) GetProfileHandler(w http.ResponseWriter, r *http.Req
 		//nolint:errcheck
 		json.NewEncoder(w).Encode(entity.NewResponse(true, "Error of authenticator"))
 	}
-	userID := user.UserID
+	userID := user.Actor()
 
 	profile, err := c.profileUsecase.GetProfile(userID, requestID)
 	if profile == nil {
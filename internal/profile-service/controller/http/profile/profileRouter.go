@@ -23,8 +23,12 @@ func SetupProfileRoutes(authenticator *authenticate.Authenticator, profileUsecas
 	muxRouter := mux.NewRouter()
 	profileController := NewProfileController(profileUsecase, sugar)
 
-	muxRouter.Handle("/api/v1/profile/my", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(profileController.GetProfileHandler))))
-	muxRouter.Handle("/api/v1/profile/edit", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(http.HandlerFunc(profileController.EditProfileHandler))))
+	profile := authenticate.Require(authenticate.PermProfile)
+
+	muxRouter.Handle("/api/v1/profile/my", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(profile(http.HandlerFunc(profileController.GetProfileHandler)))))
+	muxRouter.Handle("/api/v1/profile/edit", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(profile(http.HandlerFunc(profileController.EditProfileHandler)))))
+	// профиль личный, а пороги баланса — настройка аккаунта: в организации их меняют владелец и бухгалтерия
+	muxRouter.Handle("/api/v1/profile/balance-alerts", logger.LogMiddleware(authenticate.AuthMiddleware(authenticator)(authenticate.Require(authenticate.PermPayments)(authenticate.RequireOrgRole(authenticate.OrgRoleOwner, authenticate.OrgRoleBilling)(http.HandlerFunc(profileController.BalanceAlertsHandler))))))
 
 	return muxRouter
 }
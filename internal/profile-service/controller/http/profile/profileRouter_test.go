@@ -0,0 +1,22 @@
+package profile
+
+import (
+	"net/http"
+	"testing"
+
+	usecaseProfile "retarget/internal/profile-service/usecase/profile"
+	authenticate "retarget/pkg/middleware/auth"
+	"retarget/pkg/middleware/auth/authtest"
+
+	"go.uber.org/zap"
+)
+
+func TestSetupProfileRoutes_AccessMatrix(t *testing.T) {
+	router := SetupProfileRoutes(authtest.Authenticator(t), &usecaseProfile.ProfileUsecase{}, zap.NewNop().Sugar())
+
+	authtest.AssertRoutes(t, router, []authtest.Route{
+		{Method: http.MethodGet, Path: "/api/v1/profile/my", Roles: authenticate.Roles},
+		{Method: http.MethodPut, Path: "/api/v1/profile/edit", Roles: authenticate.Roles},
+		{Method: http.MethodGet, Path: "/api/v1/profile/balance-alerts", Roles: []int{authenticate.RoleAdvertiser, authenticate.RolePlatform}},
+	})
+}
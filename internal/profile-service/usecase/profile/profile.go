@@ -1,23 +1,36 @@
 package profile
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	entityProfile "retarget/internal/profile-service/entity/profile"
 	repoProfile "retarget/internal/profile-service/repo/profile"
+	"retarget/pkg/entity"
+	paymentpb "retarget/pkg/proto/payment"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// paymentTimeout — сколько ждать pay-service, который хранит настройки уведомлений о балансе
+const paymentTimeout = 5 * time.Second
+
 type ProfileUsecaseInterface interface {
 	GetProfile(userID int, requestID string) (*entityProfile.ProfileResponse, error)
 	PutProfile(userID int, username, description string, requestID string) error
+	GetBalanceAlerts(userID int, requestID string) (entity.BalanceAlertSettings, error)
+	PutBalanceAlerts(userID int, settings entity.BalanceAlertSettings, requestID string) (entity.BalanceAlertSettings, error)
 }
 
 type ProfileUsecase struct {
 	profileRepository *repoProfile.ProfileRepository
+	PaymentClient     paymentpb.PaymentServiceClient
 }
 
-func NewProfileUsecase(profileRepo *repoProfile.ProfileRepository) *ProfileUsecase {
-	return &ProfileUsecase{profileRepository: profileRepo}
+func NewProfileUsecase(profileRepo *repoProfile.ProfileRepository, paymentClient paymentpb.PaymentServiceClient) *ProfileUsecase {
+	return &ProfileUsecase{profileRepository: profileRepo, PaymentClient: paymentClient}
 }
 
 func (r *ProfileUsecase) PutProfile(userID int, username, description string, requestID string) error {
@@ -42,7 +55,7 @@ func (r *ProfileUsecase) GetProfile(userID int, requestID string) (*entityProfil
 		Username:    profile.Username,
 		Email:       profile.Email,
 		Description: profile.Description,
-		Balance:     *profile.Balance.Dec,
+		Balance:     profile.Balance,
 		Role:        profile.Role,
 	}
 	fmt.Println("юзкейс собрал респонс")
@@ -52,3 +65,63 @@ func (r *ProfileUsecase) GetProfile(userID int, requestID string) (*entityProfil
 	// }
 	return response, nil
 }
+
+// GetBalanceAlerts отдаёт пороги уведомлений о низком балансе из pay-service
+func (r *ProfileUsecase) GetBalanceAlerts(userID int, requestID string) (entity.BalanceAlertSettings, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), paymentTimeout)
+	defer cancel()
+
+	resp, err := r.PaymentClient.GetBalanceAlerts(ctx, &paymentpb.BalanceAlertsRequest{UserId: int32(userID)})
+	if err != nil {
+		return entity.BalanceAlertSettings{}, balanceAlertsError(err)
+	}
+	return balanceAlertsFromProto(resp)
+}
+
+// PutBalanceAlerts сохраняет пороги уведомлений о низком балансе в pay-service,
+// он же их проверяет
+func (r *ProfileUsecase) PutBalanceAlerts(userID int, settings entity.BalanceAlertSettings, requestID string) (entity.BalanceAlertSettings, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), paymentTimeout)
+	defer cancel()
+
+	thresholds := make([]string, 0, len(settings.Thresholds))
+	for _, t := range settings.Thresholds {
+		thresholds = append(thresholds, t.String())
+	}
+	resp, err := r.PaymentClient.SetBalanceAlerts(ctx, &paymentpb.BalanceAlerts{
+		UserId:     int32(userID),
+		Thresholds: thresholds,
+		AutoPause:  settings.AutoPause,
+		PauseFloor: settings.PauseFloor.String(),
+	})
+	if err != nil {
+		return entity.BalanceAlertSettings{}, balanceAlertsError(err)
+	}
+	return balanceAlertsFromProto(resp)
+}
+
+// balanceAlertsError возвращает отказ pay-service в проверке настроек как ErrInvalidBalanceAlerts
+func balanceAlertsError(err error) error {
+	if st, ok := status.FromError(err); ok && st.Code() == codes.InvalidArgument {
+		return fmt.Errorf("%w: %s", entity.ErrInvalidBalanceAlerts, st.Message())
+	}
+	return fmt.Errorf("payment service: %w", err)
+}
+
+func balanceAlertsFromProto(resp *paymentpb.BalanceAlerts) (entity.BalanceAlertSettings, error) {
+	settings := entity.BalanceAlertSettings{
+		Thresholds: make([]entity.Decimal, 0, len(resp.GetThresholds())),
+		AutoPause:  resp.GetAutoPause(),
+	}
+	for _, raw := range resp.GetThresholds() {
+		threshold := entity.Decimal{}
+		if err := threshold.ParseFromString(raw); err != nil {
+			return entity.BalanceAlertSettings{}, fmt.Errorf("payment service: %w", err)
+		}
+		settings.Thresholds = append(settings.Thresholds, threshold)
+	}
+	if err := settings.PauseFloor.ParseFromString(resp.GetPauseFloor()); err != nil {
+		return entity.BalanceAlertSettings{}, fmt.Errorf("payment service: %w", err)
+	}
+	return settings, nil
+}
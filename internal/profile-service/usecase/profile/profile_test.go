@@ -1,6 +1,7 @@
 package profile
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -8,11 +9,16 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gopkg.in/inf.v0"
 	"testing"
 
 	entityProfile "retarget/internal/profile-service/entity/profile"
 	repoProfile "retarget/internal/profile-service/repo/profile"
+	"retarget/pkg/entity"
+	paymentpb "retarget/pkg/proto/payment"
 )
 
 type MockProfileRepository struct {
@@ -71,7 +77,7 @@ func (p *ProfileUsecaseForTest) GetProfile(userID int, requestID string) (*entit
 		Username:    profile.Username,
 		Email:       profile.Email,
 		Description: profile.Description,
-		Balance:     *profile.Balance.Dec,
+		Balance:     profile.Balance,
 		Role:        profile.Role,
 	}
 
@@ -100,7 +106,7 @@ func decimalToInfDec(d decimal.Decimal) *inf.Dec {
 
 func TestNewProfileUsecase(t *testing.T) {
 	repo := &repoProfile.ProfileRepository{}
-	usecase := NewProfileUsecase(repo)
+	usecase := NewProfileUsecase(repo, nil)
 	assert.NotNil(t, usecase)
 }
 
@@ -134,7 +140,7 @@ func TestGetProfile(t *testing.T) {
 	assert.Equal(t, "testuser", profile.Username)
 	assert.Equal(t, "test@example.com", profile.Email)
 	assert.Equal(t, "Test description", profile.Description)
-	assert.Equal(t, *infDec, profile.Balance)
+	assert.Equal(t, 0, infDec.Cmp(profile.Balance.Dec))
 	assert.Equal(t, 1, profile.Role)
 
 	mockRepo.On("GetProfileByID", 2, requestID).Return(nil, entityProfile.ErrProfileNotFound).Once()
@@ -214,9 +220,9 @@ func TestGetProfile_NilBalanceField(t *testing.T) {
 
 	mockRepo.On("GetProfileByID", userID, requestID).Return(mockProfile, nil).Once()
 
-	assert.Panics(t, func() {
-		_, _ = testUsecase.GetProfile(userID, requestID)
-	})
+	profile, err := testUsecase.GetProfile(userID, requestID)
+	assert.NoError(t, err)
+	assert.Equal(t, "0", profile.Balance.String())
 
 	mockRepo.AssertExpectations(t)
 }
@@ -290,8 +296,54 @@ func TestGetProfile_ComplexProfile(t *testing.T) {
 	assert.Equal(t, "complex-user", profile.Username)
 	assert.Equal(t, "complex@example.com", profile.Email)
 	assert.Equal(t, mockProfile.Description, profile.Description)
-	assert.Equal(t, *infDec, profile.Balance)
+	assert.Equal(t, 0, infDec.Cmp(profile.Balance.Dec))
 	assert.Equal(t, 2, profile.Role)
 
 	mockRepo.AssertExpectations(t)
 }
+
+type balanceAlertsClient struct {
+	paymentpb.PaymentServiceClient
+	saved *paymentpb.BalanceAlerts
+	err   error
+}
+
+func (c *balanceAlertsClient) GetBalanceAlerts(ctx context.Context, in *paymentpb.BalanceAlertsRequest, opts ...grpc.CallOption) (*paymentpb.BalanceAlerts, error) {
+	return &paymentpb.BalanceAlerts{UserId: in.GetUserId(), Thresholds: []string{"100.00"}, AutoPause: true, PauseFloor: "10.00"}, nil
+}
+
+func (c *balanceAlertsClient) SetBalanceAlerts(ctx context.Context, in *paymentpb.BalanceAlerts, opts ...grpc.CallOption) (*paymentpb.BalanceAlerts, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.saved = in
+	return in, nil
+}
+
+// настройки уведомлений о балансе хранит и проверяет pay-service
+func TestBalanceAlerts_ThroughPaymentService(t *testing.T) {
+	client := &balanceAlertsClient{}
+	uc := NewProfileUsecase(nil, client)
+
+	settings, err := uc.GetBalanceAlerts(7, "req")
+	assert.NoError(t, err)
+	assert.Equal(t, "100.00", settings.Thresholds[0].String())
+	assert.Equal(t, "10.00", settings.PauseFloor.String())
+
+	_, err = uc.PutBalanceAlerts(7, entity.BalanceAlertSettings{
+		Thresholds: []entity.Decimal{entity.DecimalFromKopecks(30000)},
+		PauseFloor: entity.DecimalFromKopecks(0),
+	}, "req")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(7), client.saved.GetUserId())
+	assert.Equal(t, []string{"300.00"}, client.saved.GetThresholds())
+
+	client.err = status.Error(codes.InvalidArgument, "threshold must be positive")
+	_, err = uc.PutBalanceAlerts(7, entity.BalanceAlertSettings{}, "req")
+	assert.ErrorIs(t, err, entity.ErrInvalidBalanceAlerts)
+
+	client.err = status.Error(codes.Unavailable, "down")
+	_, err = uc.PutBalanceAlerts(7, entity.BalanceAlertSettings{}, "req")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, entity.ErrInvalidBalanceAlerts)
+}
@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// MaxBalanceAlertThresholds — сколько порогов уведомления может завести пользователь
+const MaxBalanceAlertThresholds = 5
+
+var ErrInvalidBalanceAlerts = errors.New("invalid balance alert settings")
+
+// BalanceAlertSettings — пороги уведомлений о низком балансе и автопауза
+// баннеров. Уведомление по порогу отправляется один раз, когда баланс
+// опускается до него, и снова становится возможным после пополнения выше порога
+type BalanceAlertSettings struct {
+	Thresholds []Decimal `json:"thresholds"`
+	AutoPause  bool      `json:"auto_pause"`
+	PauseFloor Decimal   `json:"pause_floor"` // баннеры останавливаются, когда баланс ниже
+}
+
+// DefaultBalanceAlertSettings действует, пока пользователь не сохранил свои настройки
+func DefaultBalanceAlertSettings() BalanceAlertSettings {
+	return BalanceAlertSettings{
+		Thresholds: []Decimal{DecimalFromKopecks(10000)},
+		AutoPause:  true,
+		PauseFloor: DecimalFromKopecks(1000),
+	}
+}
+
+// Normalize округляет суммы до копеек и сортирует пороги по убыванию
+func (s BalanceAlertSettings) Normalize() BalanceAlertSettings {
+	thresholds := make([]Decimal, 0, len(s.Thresholds))
+	for _, t := range s.Thresholds {
+		thresholds = append(thresholds, t.Money())
+	}
+	sort.Slice(thresholds, func(i, j int) bool {
+		return thresholds[i].Cmp(thresholds[j]) > 0
+	})
+	s.Thresholds = thresholds
+	s.PauseFloor = s.PauseFloor.Money()
+	return s
+}
+
+// Validate ожидает настройки после Normalize
+func (s BalanceAlertSettings) Validate() error {
+	if len(s.Thresholds) > MaxBalanceAlertThresholds {
+		return fmt.Errorf("%w: at most %d thresholds", ErrInvalidBalanceAlerts, MaxBalanceAlertThresholds)
+	}
+	for i, t := range s.Thresholds {
+		if t.Sign() <= 0 {
+			return fmt.Errorf("%w: threshold must be positive", ErrInvalidBalanceAlerts)
+		}
+		if i > 0 && t.Cmp(s.Thresholds[i-1]) == 0 {
+			return fmt.Errorf("%w: duplicate threshold %s", ErrInvalidBalanceAlerts, t)
+		}
+	}
+	if s.PauseFloor.Sign() < 0 {
+		return fmt.Errorf("%w: pause floor must not be negative", ErrInvalidBalanceAlerts)
+	}
+	return nil
+}
+
+// Crossed отдаёт пороги, до которых опустился баланс
+func (s BalanceAlertSettings) Crossed(balance Decimal) []Decimal {
+	crossed := make([]Decimal, 0, len(s.Thresholds))
+	for _, t := range s.Thresholds {
+		if balance.Cmp(t) <= 0 {
+			crossed = append(crossed, t)
+		}
+	}
+	return crossed
+}
+
+// ShouldPause сообщает, пора ли остановить баннеры пользователя
+func (s BalanceAlertSettings) ShouldPause(balance Decimal) bool {
+	return s.AutoPause && balance.Cmp(s.PauseFloor) < 0
+}
@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalanceAlertSettings_NormalizeValidate(t *testing.T) {
+	s := BalanceAlertSettings{
+		Thresholds: []Decimal{*NewDecWithoutErr("50"), *NewDecWithoutErr("500.004"), *NewDecWithoutErr("100")},
+		PauseFloor: *NewDecWithoutErr("5"),
+	}.Normalize()
+	assert.NoError(t, s.Validate())
+	assert.Equal(t, "500.00", s.Thresholds[0].String())
+	assert.Equal(t, "50.00", s.Thresholds[2].String())
+
+	dup := BalanceAlertSettings{Thresholds: []Decimal{DecimalFromKopecks(100), DecimalFromKopecks(100)}}.Normalize()
+	assert.ErrorIs(t, dup.Validate(), ErrInvalidBalanceAlerts)
+
+	negative := BalanceAlertSettings{Thresholds: []Decimal{DecimalFromKopecks(-100)}}.Normalize()
+	assert.ErrorIs(t, negative.Validate(), ErrInvalidBalanceAlerts)
+
+	many := BalanceAlertSettings{}
+	for i := int64(1); i <= MaxBalanceAlertThresholds+1; i++ {
+		many.Thresholds = append(many.Thresholds, DecimalFromKopecks(i*100))
+	}
+	assert.ErrorIs(t, many.Normalize().Validate(), ErrInvalidBalanceAlerts)
+}
+
+func TestBalanceAlertSettings_CrossedAndPause(t *testing.T) {
+	s := BalanceAlertSettings{
+		Thresholds: []Decimal{DecimalFromKopecks(50000), DecimalFromKopecks(10000)},
+		AutoPause:  true,
+		PauseFloor: DecimalFromKopecks(1000),
+	}
+
+	assert.Empty(t, s.Crossed(DecimalFromKopecks(60000)))
+	assert.Len(t, s.Crossed(DecimalFromKopecks(50000)), 1)
+	assert.Len(t, s.Crossed(DecimalFromKopecks(5000)), 2)
+
+	assert.False(t, s.ShouldPause(DecimalFromKopecks(1000)))
+	assert.True(t, s.ShouldPause(DecimalFromKopecks(999)))
+	s.AutoPause = false
+	assert.False(t, s.ShouldPause(DecimalFromKopecks(0)))
+
+	def := DefaultBalanceAlertSettings()
+	assert.Equal(t, "100.00", def.Thresholds[0].String())
+	assert.True(t, def.ShouldPause(DecimalFromKopecks(999)))
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// BaseCurrency — валюта расчётов: в ней хранится auth_user.balance и
+// сравниваются ставки на аукционе
+const BaseCurrency = "RUB"
+
+var ErrInvalidCurrency = errors.New("invalid currency code")
+
+// NormalizeCurrency приводит код валюты ISO 4217 к верхнему регистру,
+// пустой код означает базовую валюту
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return BaseCurrency, nil
+	}
+	if len(code) != 3 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCurrency, code)
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", fmt.Errorf("%w: %q", ErrInvalidCurrency, code)
+		}
+	}
+	return code, nil
+}
@@ -2,58 +2,137 @@ package entity
 
 import (
 	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
 	"gopkg.in/inf.v0"
 )
 
+// Деньги везде хранятся с точностью до копейки. Округление до MoneyScale
+// знаков делается только здесь и только по правилу MoneyRounding
+const MoneyScale inf.Scale = 2
+
+var MoneyRounding = inf.RoundHalfUp
+
+var ErrAmountOutOfRange = errors.New("amount out of range")
+
+// Decimal — общий денежный тип для HTTP, gRPC, событий Kafka, репозиториев
+// и колонок DECIMAL в Postgres. Нулевое значение равно нулю
 type Decimal struct {
 	*inf.Dec
 }
 
 func NewDec(s string) (*Decimal, error) {
-	d := new(inf.Dec)
-	if _, ok := d.SetString(s); !ok {
-		return nil, fmt.Errorf("invalid decimal format: %s", s)
+	d := new(Decimal)
+	if err := d.ParseFromString(s); err != nil {
+		return nil, err
 	}
-	return &Decimal{Dec: d}, nil
+	return d, nil
 }
 
+// NewDecWithoutErr разбирает заведомо корректную строку; на мусоре вернёт ноль
 func NewDecWithoutErr(s string) *Decimal {
+	d, err := NewDec(s)
+	if err != nil {
+		return &Decimal{Dec: inf.NewDec(0, 0)}
+	}
+	return d
+}
+
+func ParseDecimal(s string) (*inf.Dec, error) {
 	d := new(inf.Dec)
-	return &Decimal{Dec: d}
+	if _, ok := d.SetString(strings.TrimLeft(strings.TrimSpace(s), "+")); !ok {
+		return nil, fmt.Errorf("invalid decimal format: %s", s)
+	}
+	return d, nil
 }
 
-func (d *Decimal) Scan(value interface{}) error {
-	if value == nil {
-		d.Dec = inf.NewDec(0, 0)
-		return nil
+func (d *Decimal) ParseFromString(s string) error {
+	dec, err := ParseDecimal(s)
+	if err != nil {
+		return err
 	}
+	d.Dec = dec
+	return nil
+}
 
-	var s string
-	switch v := value.(type) {
-	case []byte:
-		s = string(v)
-	case string:
-		s = v
-	default:
-		return fmt.Errorf("unsupported type: %T", value)
+// DecimalFromKopecks собирает сумму из целого числа копеек
+func DecimalFromKopecks(kopecks int64) Decimal {
+	return Decimal{Dec: inf.NewDec(kopecks, MoneyScale)}
+}
+
+// Money округляет сумму до копеек
+func (d Decimal) Money() Decimal {
+	if d.Dec == nil {
+		return DecimalFromKopecks(0)
 	}
+	return Decimal{Dec: new(inf.Dec).Round(d.Dec, MoneyScale, MoneyRounding)}
+}
 
-	s = strings.TrimLeft(s, "+")
+// Kopecks переводит сумму в целое число копеек, округляя по денежным правилам
+func (d Decimal) Kopecks() (int64, error) {
+	if d.Dec == nil {
+		return 0, nil
+	}
+	value, ok := d.Money().Unscaled()
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrAmountOutOfRange, d.String())
+	}
+	return value, nil
+}
 
-	var err error
-	d.Dec, err = ParseDecimal(s)
-	return err
+func (d Decimal) dec() *inf.Dec {
+	if d.Dec == nil {
+		return inf.NewDec(0, 0)
+	}
+	return d.Dec
 }
 
-func ParseDecimal(s string) (*inf.Dec, error) {
-	d := new(inf.Dec)
-	if _, ok := d.SetString(s); !ok {
-		return nil, fmt.Errorf("invalid decimal format: %s", s)
+func (d Decimal) Add(other Decimal) Decimal {
+	return Decimal{Dec: new(inf.Dec).Add(d.dec(), other.dec())}
+}
+
+func (d Decimal) Sub(other Decimal) Decimal {
+	return Decimal{Dec: new(inf.Dec).Sub(d.dec(), other.dec())}
+}
+
+func (d Decimal) Neg() Decimal {
+	if d.Dec == nil {
+		return Decimal{}
+	}
+	return Decimal{Dec: new(inf.Dec).Neg(d.Dec)}
+}
+
+// Cmp сравнивает суммы как inf.Dec.Cmp; nil считается нулём
+func (d Decimal) Cmp(other Decimal) int {
+	return d.dec().Cmp(other.dec())
+}
+
+func (d Decimal) Sign() int {
+	return d.dec().Sign()
+}
+
+func (d Decimal) String() string {
+	return d.dec().String()
+}
+
+func (d *Decimal) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		d.Dec = inf.NewDec(0, 0)
+		return nil
+	case string:
+		return d.ParseFromString(v)
+	case []byte:
+		return d.ParseFromString(string(v))
+	case int64:
+		d.Dec = inf.NewDec(v, 0)
+		return nil
+	default:
+		return fmt.Errorf("cannot convert %T to Decimal", value)
 	}
-	return d, nil
 }
 
 func (d Decimal) Value() (driver.Value, error) {
@@ -63,21 +142,28 @@ func (d Decimal) Value() (driver.Value, error) {
 	return d.String(), nil
 }
 
+// UnmarshalJSON принимает и строку, и число; число разбирается по тексту,
+// без промежуточного float64
 func (d *Decimal) UnmarshalJSON(data []byte) error {
-	s := strings.Trim(string(data), `"`)
-	if s == "null" || s == "" {
+	s := strings.TrimSpace(string(data))
+	if s == "null" || s == `""` {
 		d.Dec = inf.NewDec(0, 0)
 		return nil
 	}
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("invalid decimal json: %s", string(data))
+		}
+		return d.ParseFromString(s)
+	}
 
-	var err error
-	d.Dec, err = ParseDecimal(s)
-	return err
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("invalid decimal json: %s", string(data))
+	}
+	return d.ParseFromString(n.String())
 }
 
 func (d Decimal) MarshalJSON() ([]byte, error) {
-	if d.Dec == nil {
-		return []byte(`"0"`), nil
-	}
 	return []byte(`"` + d.String() + `"`), nil
 }
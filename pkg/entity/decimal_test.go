@@ -49,6 +49,11 @@ func TestDecimal_Scan(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "4.56", d.String())
 
+	// int64: старые целочисленные колонки
+	err = d.Scan(int64(42))
+	assert.NoError(t, err)
+	assert.Equal(t, "42", d.String())
+
 	// wrong type
 	err = d.Scan(123)
 	assert.Error(t, err)
@@ -78,7 +83,13 @@ func TestDecimal_JSON(t *testing.T) {
 	var d4 Decimal
 	err = json.Unmarshal([]byte(`4.50`), &d4)
 	assert.NoError(t, err)
-	assert.Equal(t, "4.500000", d4.String()) // fmt.Sprintf("%f",4.50)
+	assert.Equal(t, "4.50", d4.String())
+
+	// число разбирается по тексту, без потерь на float64
+	var d6 Decimal
+	err = json.Unmarshal([]byte(`0.1000000000000000055511`), &d6)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.1000000000000000055511", d6.String())
 
 	// Unmarshal invalid
 	var d5 Decimal
@@ -86,3 +97,45 @@ func TestDecimal_JSON(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid decimal json")
 }
+
+func TestDecimal_Kopecks(t *testing.T) {
+	var d Decimal
+	assert.NoError(t, d.ParseFromString("12.345"))
+	k, err := d.Kopecks()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1235), k)
+
+	k, err = Decimal{}.Kopecks()
+	assert.NoError(t, err)
+	assert.Zero(t, k)
+
+	assert.Equal(t, "12.35", DecimalFromKopecks(1235).String())
+}
+
+func TestDecimal_MoneyRounding(t *testing.T) {
+	for raw, want := range map[string]string{
+		"0.005":   "0.01",
+		"0.004":   "0.00",
+		"-0.005":  "-0.01",
+		"2.675":   "2.68",
+		"100":     "100.00",
+		"+10.999": "11.00",
+	} {
+		d, err := NewDec(raw)
+		assert.NoError(t, err)
+		assert.Equal(t, want, d.Money().String(), raw)
+	}
+	assert.Equal(t, "0.00", Decimal{}.Money().String())
+}
+
+func TestDecimal_Arithmetic(t *testing.T) {
+	a := *NewDecWithoutErr("0.10")
+	b := *NewDecWithoutErr("0.20")
+	sum := a.Add(b)
+	assert.Equal(t, 0, sum.Cmp(*NewDecWithoutErr("0.3")))
+	assert.Equal(t, "-0.10", a.Sub(b).String())
+	assert.Equal(t, "-0.10", a.Neg().String())
+	assert.Equal(t, -1, Decimal{}.Cmp(a))
+	assert.Equal(t, 0, Decimal{}.Sign())
+	assert.Equal(t, "0", NewDecWithoutErr("bad").String())
+}
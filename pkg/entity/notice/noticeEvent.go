@@ -1,7 +1,13 @@
 package notice
 
+import "retarget/pkg/entity"
+
 type NoticeEvent struct {
-	UserID int     `json:"user_id"`
-	Type   int     `json:"type"` // ex. low_balance, etc.
-	Amount float64 `json:"amount,omitempty"`
+	UserID      int             `json:"user_id"`
+	Type        int             `json:"type"` // ex. low_balance, etc.
+	Amount      *entity.Decimal `json:"amount,omitempty"`
+	Threshold   *entity.Decimal `json:"threshold,omitempty"` // crossed threshold for low_balance
+	StatementID int             `json:"statement_id,omitempty"`
+	Period      string          `json:"period,omitempty"` // ex. 2025-04 for statements
+	Reason      string          `json:"reason,omitempty"` // why auto-recharge failed
 }
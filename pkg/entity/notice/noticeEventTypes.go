@@ -1,6 +1,8 @@
 package notice
 
 const (
-	LowBalance     int = iota // if balance user too low
-	TopUpedBalance            // if user top uped balance and his money became more then critical value
+	LowBalance         int = iota // if balance user too low
+	TopUpedBalance                // if user top uped balance and his money became more then critical value
+	StatementReady                // if monthly statement for user was generated
+	AutoRechargeFailed            // if auto-recharge charge failed or hit the monthly cap
 )
@@ -4,7 +4,24 @@ type userContextKey string
 
 const UserContextKey = userContextKey("user_context")
 
+// UserContext — от чьего имени выполняется запрос. UserID — аккаунт, которому
+// принадлежат баннеры, слоты и баланс: сам пользователь или организация, в
+// которую он переключил сессию
 type UserContext struct {
-	UserID int
-	Role   int
+	UserID    int
+	Role      int
+	Verified  bool   // почта подтверждена
+	TwoFactor bool   // сессия подтверждена вторым фактором
+	APIKeyID  int    // запрос по API-ключу; 0 — по cookie сессии
+	ActorID   int    // участник, действующий от имени организации UserID; 0 — от своего имени
+	OrgRole   string // роль ActorID в организации
+}
+
+// Actor — пользователь, который выполняет запрос. Личные данные (профиль,
+// пароль, сессии) относятся к нему, а не к организации
+func (u UserContext) Actor() int {
+	if u.ActorID != 0 {
+		return u.ActorID
+	}
+	return u.UserID
 }
@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Права API-ключей. Ключ без нужного права получает 403, даже если
+// пользователю действие доступно
+const (
+	ScopeBannersRead  = "banners:read"
+	ScopeBannersWrite = "banners:write"
+	ScopeSlotsRead    = "slots:read"
+	ScopeSlotsWrite   = "slots:write"
+	ScopeMetricsRead  = "metrics:read"
+	ScopePaymentsRead = "payments:read"
+)
+
+// Scopes — все права, которые можно выдать ключу
+var Scopes = []string{
+	ScopeBannersRead,
+	ScopeBannersWrite,
+	ScopeSlotsRead,
+	ScopeSlotsWrite,
+	ScopeMetricsRead,
+	ScopePaymentsRead,
+}
+
+func ValidScope(scope string) bool {
+	for _, s := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyPrefix отличает ключи от других токенов в заголовке Authorization
+const APIKeyPrefix = "rtk_"
+
+// APIKeyData — действующий ключ в Redis. Записывает auth-service, читают все сервисы
+type APIKeyData struct {
+	KeyID  int      `json:"key_id"`
+	UserID int      `json:"user_id"`
+	Role   int      `json:"role"`
+	Scopes []string `json:"scopes"`
+	// Expires — нулевое время у бессрочного ключа
+	Expires time.Time `json:"expires,omitempty"`
+}
+
+func (d APIKeyData) HasScope(scope string) bool {
+	for _, s := range d.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyHash — под этим хешем ключ хранится. У ключа достаточно энтропии, соль не нужна
+func APIKeyHash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeyRedisKey — ключ Redis с APIKeyData
+func APIKeyRedisKey(hash string) string {
+	return "api_key:" + hash
+}
+
+var errAPIKeyInvalid = errors.New("api key is invalid or revoked")
+
+// AuthenticateAPIKey проверяет ключ из заголовка Authorization
+func (a *Authenticator) AuthenticateAPIKey(key string) (APIKeyData, error) {
+	if !strings.HasPrefix(key, APIKeyPrefix) {
+		return APIKeyData{Role: -1}, errAPIKeyInvalid
+	}
+
+	val, err := a.redisClient.Get(context.Background(), APIKeyRedisKey(APIKeyHash(key))).Result()
+	if errors.Is(err, redis.Nil) {
+		return APIKeyData{Role: -1}, errAPIKeyInvalid
+	}
+	if err != nil {
+		return APIKeyData{Role: -1}, fmt.Errorf("error read from Redis: %w", err)
+	}
+
+	var data APIKeyData
+	if err := json.Unmarshal([]byte(val), &data); err != nil {
+		return APIKeyData{Role: -1}, fmt.Errorf("error decoding JSON: %w", err)
+	}
+	// Redis удаляет ключ по TTL, проверка страхует от отставших часов
+	if !data.Expires.IsZero() && time.Now().After(data.Expires) {
+		return APIKeyData{Role: -1}, errAPIKeyInvalid
+	}
+	return data, nil
+}
@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"retarget/pkg/entity"
+)
+
+func TestScopedAuthMiddleware(t *testing.T) {
+	a, s := setupAuthenticator(t)
+	data, _ := json.Marshal(APIKeyData{KeyID: 3, UserID: 7, Role: 1, Scopes: []string{ScopeBannersRead}})
+	s.Set(APIKeyRedisKey(APIKeyHash("rtk_good")), string(data))
+	expired, _ := json.Marshal(APIKeyData{KeyID: 4, UserID: 7, Role: 1, Scopes: []string{ScopeBannersRead}, Expires: time.Now().Add(-time.Minute)})
+	s.Set(APIKeyRedisKey(APIKeyHash("rtk_old")), string(expired))
+	session, _ := json.Marshal(SessionData{UserID: 8, Role: 2, Expires: time.Now().Add(time.Hour)})
+	s.Set(SessionKey(testSessionID), string(session))
+
+	var got entity.UserContext
+	handler := func(scope string) http.Handler {
+		return ScopedAuthMiddleware(a, scope)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r.Context().Value(entity.UserContextKey).(entity.UserContext)
+		}))
+	}
+	serve := func(scope string, prepare func(r *http.Request)) int {
+		got = entity.UserContext{}
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		prepare(r)
+		w := httptest.NewRecorder()
+		handler(scope).ServeHTTP(w, r)
+		return w.Code
+	}
+	bearer := func(key string) func(r *http.Request) {
+		return func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+key) }
+	}
+
+	if code := serve(ScopeBannersRead, bearer("rtk_good")); code != http.StatusOK || got.UserID != 7 || got.APIKeyID != 3 || !got.Verified || got.TwoFactor {
+		t.Errorf("expected key accepted, got %d %+v", code, got)
+	}
+	if code := serve(ScopeBannersWrite, bearer("rtk_good")); code != http.StatusForbidden {
+		t.Errorf("expected missing scope rejected, got %d", code)
+	}
+	for _, key := range []string{"rtk_unknown", "rtk_old", "not-a-key"} {
+		if code := serve(ScopeBannersRead, bearer(key)); code != http.StatusUnauthorized {
+			t.Errorf("expected %s rejected, got %d", key, code)
+		}
+	}
+	if code := serve(ScopeBannersRead, func(r *http.Request) { r.Header.Set("Authorization", "Basic eDp5") }); code != http.StatusUnauthorized {
+		t.Errorf("expected foreign scheme rejected, got %d", code)
+	}
+
+	// cookie сессии действует без проверки прав
+	if code := serve(ScopeBannersWrite, func(r *http.Request) { r.AddCookie(&http.Cookie{Name: "session_id", Value: testSessionID}) }); code != http.StatusOK || got.UserID != 8 || got.APIKeyID != 0 {
+		t.Errorf("expected session accepted, got %d %+v", code, got)
+	}
+}
+
+func TestAuthenticate_RejectsAPIKeyAsSession(t *testing.T) {
+	a, s := setupAuthenticator(t)
+	hash := APIKeyHash("rtk_good")
+	data, _ := json.Marshal(APIKeyData{KeyID: 3, UserID: 42, Role: 1, Scopes: []string{ScopeMetricsRead}})
+	s.Set(APIKeyRedisKey(hash), string(data))
+	// запись без префикса сессии — как ключи, созданные до него
+	s.Set(testSessionID, string(data))
+
+	for _, cookie := range []string{APIKeyRedisKey(hash), testSessionID} {
+		if session, err := a.Authenticate(cookie); err == nil || session.Role != -1 {
+			t.Errorf("cookie %q: expected rejection, got %+v", cookie, session)
+		}
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/auth/api-keys", nil)
+	r.AddCookie(&http.Cookie{Name: "session_id", Value: APIKeyRedisKey(hash)})
+	w := httptest.NewRecorder()
+	AuthMiddleware(a)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("api key record passed as a session")
+	})).ServeHTTP(w, r)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", w.Code)
+	}
+}
@@ -5,17 +5,59 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
 type SessionData struct {
 	UserID int `json:"user_id"`
 	Role   int `json:"role"`
+	// Unverified — почта ещё не подтверждена; в сессиях, созданных до
+	// подтверждения почты, поля нет, и они считаются подтверждёнными
+	Unverified bool `json:"unverified,omitempty"`
+	// TwoFactor — сессия открыта с вводом второго фактора (TOTP или кода восстановления)
+	TwoFactor bool `json:"two_factor,omitempty"`
+	// OrgID — организация, от имени которой действует сессия, OrgRole — роль в
+	// ней. Role у такой сессии — роль аккаунта организации
+	OrgID   int       `json:"org_id,omitempty"`
+	OrgRole string    `json:"org_role,omitempty"`
+	Expires time.Time `json:"expires"`
 }
 
+// LastSeenInterval — как часто Authenticate обновляет время последнего
+// обращения сессии: запись в Redis не чаще раза в интервал
+const LastSeenInterval = time.Minute
+
+// LastSeenKey — ключ с unix-временем последнего обращения сессии. Его же
+// читает auth-service при выводе списка сессий
+func LastSeenKey(sessionID string) string {
+	return "session_seen:" + sessionID
+}
+
+// SessionKey — ключ Redis с SessionData. В той же базе лежат API-ключи,
+// состояния OAuth и коды, префикс не даёт выдать их за сессию
+func SessionKey(sessionID string) string {
+	return "session:" + sessionID
+}
+
+// ValidSessionID — похоже ли значение cookie на идентификатор сессии:
+// auth-service выдаёт UUID в каноническом виде
+func ValidSessionID(sessionID string) bool {
+	if len(sessionID) != 36 {
+		return false
+	}
+	_, err := uuid.Parse(sessionID)
+	return err == nil
+}
+
+var errSessionNotFound = errors.New("session not found")
+
 type AuthenticatorInterface interface {
-	Authenticate(cookie string) (int, int, error)
+	Authenticate(cookie string) (SessionData, error)
+	AuthenticateAPIKey(key string) (APIKeyData, error)
 }
 
 type Authenticator struct {
@@ -37,20 +79,45 @@ func NewAuthenticator(endPoint string, password string, db int) (*Authenticator,
 	return &Authenticator{redisClient: redisClient}, nil
 }
 
-func (a *Authenticator) Authenticate(cookie string) (int, int, error) {
-	val, err := a.redisClient.Get(context.Background(), cookie).Result()
+func (a *Authenticator) Authenticate(cookie string) (SessionData, error) {
+	ctx := context.Background()
+	if !ValidSessionID(cookie) {
+		return SessionData{Role: -1}, errSessionNotFound
+	}
+
+	values, err := a.redisClient.MGet(ctx, SessionKey(cookie), LastSeenKey(cookie)).Result()
 	if err != nil {
-		if errors.Is(err, redis.Nil) {
-			return 0, -1, errors.New("session not found")
-		}
-		return 0, -1, fmt.Errorf("error read from Redis: %w", err)
+		return SessionData{Role: -1}, fmt.Errorf("error read from Redis: %w", err)
+	}
+	val, ok := values[0].(string)
+	if !ok {
+		return SessionData{Role: -1}, errSessionNotFound
 	}
 
 	var session SessionData
 	err = json.Unmarshal([]byte(val), &session)
 	if err != nil {
-		return 0, -1, fmt.Errorf("error decoding JSON: %w", err)
+		return SessionData{Role: -1}, fmt.Errorf("error decoding JSON: %w", err)
 	}
 
-	return session.UserID, session.Role, nil
+	a.touch(ctx, cookie, session, values[1])
+	return session, nil
+}
+
+// touch обновляет время последнего обращения, если записанное устарело
+// больше чем на LastSeenInterval. Ошибки не мешают аутентификации
+func (a *Authenticator) touch(ctx context.Context, cookie string, session SessionData, seen interface{}) {
+	now := time.Now()
+	if raw, ok := seen.(string); ok {
+		if unix, err := strconv.ParseInt(raw, 10, 64); err == nil && now.Sub(time.Unix(unix, 0)) < LastSeenInterval {
+			return
+		}
+	}
+
+	ttl := time.Until(session.Expires)
+	if session.Expires.IsZero() || ttl <= 0 {
+		return
+	}
+	//nolint:errcheck
+	a.redisClient.Set(ctx, LastSeenKey(cookie), now.Unix(), ttl)
 }
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func setupAuthenticator(t *testing.T) (*Authenticator, *miniredis.Miniredis) {
+	s, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("miniredis start failed: %v", err)
+	}
+	t.Cleanup(s.Close)
+	return &Authenticator{redisClient: redis.NewClient(&redis.Options{Addr: s.Addr()})}, s
+}
+
+// testSessionID — идентификатор сессии в формате, который выдаёт auth-service
+const testSessionID = "0f8fad5b-d9cb-469f-a165-70867728950e"
+
+func TestAuthenticate_TouchesLastSeen(t *testing.T) {
+	a, s := setupAuthenticator(t)
+	data, _ := json.Marshal(SessionData{UserID: 7, Role: 1, Expires: time.Now().Add(time.Hour)})
+	s.Set(SessionKey(testSessionID), string(data))
+
+	session, err := a.Authenticate(testSessionID)
+	if err != nil || session.UserID != 7 {
+		t.Fatalf("unexpected session %+v, err %v", session, err)
+	}
+	if !s.Exists(LastSeenKey(testSessionID)) {
+		t.Fatalf("expected last-seen written")
+	}
+	if ttl := s.TTL(LastSeenKey(testSessionID)); ttl <= 0 || ttl > time.Hour {
+		t.Errorf("expected last-seen to expire with the session, got %v", ttl)
+	}
+
+	// свежая отметка не переписывается
+	recent := strconv.FormatInt(time.Now().Add(-LastSeenInterval/2).Unix(), 10)
+	s.Set(LastSeenKey(testSessionID), recent)
+	if _, err := a.Authenticate(testSessionID); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.Get(LastSeenKey(testSessionID)); got != recent {
+		t.Errorf("expected recent last-seen kept, got %s", got)
+	}
+
+	stale := strconv.FormatInt(time.Now().Add(-2*LastSeenInterval).Unix(), 10)
+	s.Set(LastSeenKey(testSessionID), stale)
+	if _, err := a.Authenticate(testSessionID); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.Get(LastSeenKey(testSessionID)); got == stale {
+		t.Errorf("expected stale last-seen refreshed")
+	}
+}
+
+func TestAuthenticate_NotFound(t *testing.T) {
+	a, _ := setupAuthenticator(t)
+	if session, err := a.Authenticate("missing"); err == nil || session.Role != -1 {
+		t.Errorf("expected session not found, got %+v, %v", session, err)
+	}
+}
@@ -0,0 +1,89 @@
+// Package authtest проверяет в тестах роутеров, кого политика доступа
+// пропускает на маршрут
+package authtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	auth "retarget/pkg/middleware/auth"
+)
+
+// SessionID — cookie сессии роли role в Authenticator
+func SessionID(role int) string {
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", role)
+}
+
+// Authenticator поднимает miniredis с подтверждённой двухфакторной сессией
+// для каждой роли из auth.Roles
+func Authenticator(t *testing.T) *auth.Authenticator {
+	t.Helper()
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("miniredis start failed: %v", err)
+	}
+	t.Cleanup(mr.Close)
+
+	for _, role := range auth.Roles {
+		data, _ := json.Marshal(auth.SessionData{UserID: 100 + role, Role: role, TwoFactor: true, Expires: time.Now().Add(time.Hour)})
+		if err := mr.Set(auth.SessionKey(SessionID(role)), string(data)); err != nil {
+			t.Fatalf("session: %v", err)
+		}
+	}
+
+	a, err := auth.NewAuthenticator(mr.Addr(), "", 0)
+	if err != nil {
+		t.Fatalf("authenticator: %v", err)
+	}
+	return a
+}
+
+// Allowed отправляет запрос от имени роли role и сообщает, пропустила ли его
+// политика доступа. Роутеры в тестах собраны с пустыми usecase, и обработчик
+// может упасть — это значит, что запрос до него дошёл
+func Allowed(t *testing.T, h http.Handler, method, path string, role int) (allowed bool) {
+	t.Helper()
+	r := httptest.NewRequest(method, path, strings.NewReader("{}"))
+	r.AddCookie(&http.Cookie{Name: "session_id", Value: SessionID(role)})
+	w := httptest.NewRecorder()
+
+	defer func() {
+		if recover() != nil {
+			allowed = true
+		}
+	}()
+	h.ServeHTTP(w, r)
+
+	if w.Code == http.StatusUnauthorized {
+		t.Fatalf("%s %s: session of role %d was rejected: %s", method, path, role, w.Body.String())
+	}
+	return w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), "has no permission")
+}
+
+// Route — маршрут и роли, которых на него пускают
+type Route struct {
+	Method string
+	Path   string
+	Roles  []int
+}
+
+// AssertRoutes проверяет по каждому маршруту, что политика доступа пускает
+// ровно роли route.Roles
+func AssertRoutes(t *testing.T, h http.Handler, routes []Route) {
+	t.Helper()
+	for _, route := range routes {
+		for _, role := range auth.Roles {
+			if got, want := Allowed(t, h, route.Method, route.Path, role), slices.Contains(route.Roles, role); got != want {
+				t.Errorf("%s %s for role %d: expected allowed=%v, got %v", route.Method, route.Path, role, want, got)
+			}
+		}
+	}
+}
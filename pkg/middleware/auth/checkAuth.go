@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"retarget/pkg/entity"
+	"slices"
+	"strings"
 )
 
 func AuthMiddleware(authenticator AuthenticatorInterface) func(http.Handler) http.Handler {
@@ -22,7 +24,7 @@ func AuthMiddleware(authenticator AuthenticatorInterface) func(http.Handler) htt
 				return
 			}
 
-			userID, role, err := authenticator.Authenticate(cookie.Value)
+			session, err := authenticator.Authenticate(cookie.Value)
 			if err != nil {
 				w.WriteHeader(http.StatusUnauthorized)
 
@@ -36,8 +38,15 @@ func AuthMiddleware(authenticator AuthenticatorInterface) func(http.Handler) htt
 			}
 
 			userContext := entity.UserContext{
-				UserID: userID,
-				Role:   role,
+				UserID:    session.UserID,
+				Role:      session.Role,
+				Verified:  !session.Unverified,
+				TwoFactor: session.TwoFactor,
+			}
+			if session.OrgID != 0 {
+				userContext.UserID = session.OrgID
+				userContext.ActorID = session.UserID
+				userContext.OrgRole = session.OrgRole
 			}
 
 			ctx := context.WithValue(r.Context(), entity.UserContextKey, userContext)
@@ -47,3 +56,122 @@ func AuthMiddleware(authenticator AuthenticatorInterface) func(http.Handler) htt
 		})
 	}
 }
+
+// ScopedAuthMiddleware — AuthMiddleware, который кроме cookie сессии принимает
+// API-ключ из заголовка «Authorization: Bearer» с правом scope. Маршруты под
+// обычным AuthMiddleware ключами недоступны. Сессии в организации проходят,
+// если право scope есть у роли участника
+func ScopedAuthMiddleware(authenticator AuthenticatorInterface, scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		withSession := AuthMiddleware(authenticator)(requireOrgScope(scope, next))
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				withSession.ServeHTTP(w, r)
+				return
+			}
+
+			token, found := strings.CutPrefix(header, "Bearer ")
+			if !found {
+				w.WriteHeader(http.StatusUnauthorized)
+				//nolint:errcheck
+				json.NewEncoder(w).Encode(entity.NewResponse(true, "unsupported authorization scheme"))
+				return
+			}
+			key, err := authenticator.AuthenticateAPIKey(strings.TrimSpace(token))
+			if err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				//nolint:errcheck
+				json.NewEncoder(w).Encode(entity.NewResponse(true, err.Error()))
+				return
+			}
+			if !key.HasScope(scope) {
+				w.WriteHeader(http.StatusForbidden)
+				//nolint:errcheck
+				json.NewEncoder(w).Encode(entity.NewResponse(true, "api key has no scope "+scope))
+				return
+			}
+
+			// ключ выпускается только с подтверждённой почтой и второго фактора не заменяет
+			userContext := entity.UserContext{
+				UserID:   key.UserID,
+				Role:     key.Role,
+				Verified: true,
+				APIKeyID: key.KeyID,
+			}
+			ctx := context.WithValue(r.Context(), entity.UserContextKey, userContext)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireVerified пропускает только пользователей с подтверждённой почтой.
+// Ставится после AuthMiddleware
+func RequireVerified(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+		if !ok || !user.Verified {
+			w.WriteHeader(http.StatusForbidden)
+			//nolint:errcheck
+			json.NewEncoder(w).Encode(entity.NewResponse(true, "email is not verified"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireTwoFactor пропускает только сессии, открытые со вторым фактором.
+// Ставится после AuthMiddleware
+func RequireTwoFactor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+		if !ok || !user.TwoFactor {
+			w.WriteHeader(http.StatusForbidden)
+			//nolint:errcheck
+			json.NewEncoder(w).Encode(entity.NewResponse(true, "two-factor authentication is required"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireOrgRole пропускает сессии в организации только с одной из ролей roles.
+// Запросы от своего имени проходят. Ставится после AuthMiddleware
+func RequireOrgRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+			if !ok {
+				w.WriteHeader(http.StatusUnauthorized)
+				//nolint:errcheck
+				json.NewEncoder(w).Encode(entity.NewResponse(true, "user is not authenticated"))
+				return
+			}
+			if user.ActorID != 0 && !slices.Contains(roles, user.OrgRole) {
+				w.WriteHeader(http.StatusForbidden)
+				//nolint:errcheck
+				json.NewEncoder(w).Encode(entity.NewResponse(true, "not allowed for organization role "+user.OrgRole))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func requireOrgScope(scope string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+		if ok && user.ActorID != 0 && !OrgRoleHasScope(user.OrgRole, scope) {
+			w.WriteHeader(http.StatusForbidden)
+			//nolint:errcheck
+			json.NewEncoder(w).Encode(entity.NewResponse(true, "organization role "+user.OrgRole+" has no scope "+scope))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,31 @@
+package auth
+
+import "slices"
+
+// Роли участника организации
+const (
+	OrgRoleOwner   = "owner"   // всё, включая участников, ключи и вывод средств
+	OrgRoleManager = "manager" // баннеры и слоты
+	OrgRoleAnalyst = "analyst" // только чтение баннеров, слотов и статистики
+	OrgRoleBilling = "billing" // платежи и пополнение баланса
+)
+
+var OrgRoles = []string{OrgRoleOwner, OrgRoleManager, OrgRoleAnalyst, OrgRoleBilling}
+
+func ValidOrgRole(role string) bool {
+	return slices.Contains(OrgRoles, role)
+}
+
+// orgRoleScopes — права ролей на маршрутах ScopedAuthMiddleware. Владельцу доступно всё
+var orgRoleScopes = map[string][]string{
+	OrgRoleManager: {ScopeBannersRead, ScopeBannersWrite, ScopeSlotsRead, ScopeSlotsWrite, ScopeMetricsRead, ScopePaymentsRead},
+	OrgRoleAnalyst: {ScopeBannersRead, ScopeSlotsRead, ScopeMetricsRead},
+	OrgRoleBilling: {ScopePaymentsRead},
+}
+
+func OrgRoleHasScope(role, scope string) bool {
+	if role == OrgRoleOwner {
+		return true
+	}
+	return slices.Contains(orgRoleScopes[role], scope)
+}
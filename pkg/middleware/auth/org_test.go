@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"retarget/pkg/entity"
+
+	"github.com/google/uuid"
+)
+
+func TestOrgSession_ActsForOrganization(t *testing.T) {
+	a, s := setupAuthenticator(t)
+	// сессии по имени: owner, analyst, billing — в организации, personal — личная
+	sessionIDs := map[string]string{}
+	for sid, role := range map[string]string{"owner": OrgRoleOwner, "analyst": OrgRoleAnalyst, "billing": OrgRoleBilling} {
+		data, _ := json.Marshal(SessionData{UserID: 8, Role: 1, OrgID: 50, OrgRole: role, Expires: time.Now().Add(time.Hour)})
+		sessionIDs[sid] = uuid.NewString()
+		s.Set(SessionKey(sessionIDs[sid]), string(data))
+	}
+	personal, _ := json.Marshal(SessionData{UserID: 8, Role: 2, Expires: time.Now().Add(time.Hour)})
+	sessionIDs["personal"] = uuid.NewString()
+	s.Set(SessionKey(sessionIDs["personal"]), string(personal))
+
+	var got entity.UserContext
+	capture := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Context().Value(entity.UserContextKey).(entity.UserContext)
+	})
+	serve := func(h http.Handler, sid string) int {
+		got = entity.UserContext{}
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.AddCookie(&http.Cookie{Name: "session_id", Value: sessionIDs[sid]})
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	if code := serve(AuthMiddleware(a)(capture), "analyst"); code != http.StatusOK || got.UserID != 50 || got.ActorID != 8 || got.Actor() != 8 || got.OrgRole != OrgRoleAnalyst {
+		t.Errorf("expected organization context, got %d %+v", code, got)
+	}
+	if code := serve(AuthMiddleware(a)(capture), "personal"); code != http.StatusOK || got.UserID != 8 || got.ActorID != 0 || got.Actor() != 8 {
+		t.Errorf("expected personal context, got %d %+v", code, got)
+	}
+
+	cases := []struct {
+		sid   string
+		scope string
+		code  int
+	}{
+		{"owner", ScopeBannersWrite, http.StatusOK},
+		{"analyst", ScopeMetricsRead, http.StatusOK},
+		{"analyst", ScopeBannersWrite, http.StatusForbidden},
+		{"billing", ScopePaymentsRead, http.StatusOK},
+		{"billing", ScopeSlotsRead, http.StatusForbidden},
+		{"personal", ScopeSlotsWrite, http.StatusOK},
+	}
+	for _, c := range cases {
+		if code := serve(ScopedAuthMiddleware(a, c.scope)(capture), c.sid); code != c.code {
+			t.Errorf("%s on %s: expected %d, got %d", c.sid, c.scope, c.code, code)
+		}
+	}
+
+	onlyBilling := AuthMiddleware(a)(RequireOrgRole(OrgRoleOwner, OrgRoleBilling)(capture))
+	for sid, want := range map[string]int{"owner": http.StatusOK, "billing": http.StatusOK, "analyst": http.StatusForbidden, "personal": http.StatusOK} {
+		if code := serve(onlyBilling, sid); code != want {
+			t.Errorf("RequireOrgRole for %s: expected %d, got %d", sid, want, code)
+		}
+	}
+}
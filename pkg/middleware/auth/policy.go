@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"retarget/pkg/entity"
+)
+
+// Роли аккаунта (auth_user.role). Поддержку и администраторов назначают в
+// базе, при регистрации доступны только рекламодатель и площадка
+const (
+	RoleAdvertiser = 1
+	RolePlatform   = 2
+	RoleSupport    = 3 // читает отзывы пользователей
+	RoleAdmin      = 4 // отзывы и финансовые операции платформы
+)
+
+// Permission — право на группу маршрутов. Какое право нужно маршруту,
+// объявляет роутер сервиса через Require
+type Permission string
+
+const (
+	PermBanners    Permission = "banners"     // свои баннеры и их генерация
+	PermSlots      Permission = "slots"       // свои рекламные места
+	PermMetrics    Permission = "metrics"     // статистика своих баннеров и мест
+	PermPayments   Permission = "payments"    // свой баланс, платежи и выплаты
+	PermProfile    Permission = "profile"     // свой профиль и аватар
+	PermReviews    Permission = "reviews"     // опросы CSAT и свои отзывы
+	PermReviewsAll Permission = "reviews:all" // отзывы всех пользователей
+	// PermBillingAdmin — выплаты, возвраты, промокоды, курсы и ручные зачисления
+	PermBillingAdmin Permission = "billing:admin"
+)
+
+var rolePermissions = map[int][]Permission{
+	RoleAdvertiser: {PermBanners, PermMetrics, PermPayments, PermProfile, PermReviews},
+	RolePlatform:   {PermSlots, PermMetrics, PermPayments, PermProfile, PermReviews},
+	RoleSupport:    {PermProfile, PermReviews, PermReviewsAll},
+	RoleAdmin:      {PermProfile, PermReviews, PermReviewsAll, PermBillingAdmin},
+}
+
+// Roles — все роли аккаунта
+var Roles = []int{RoleAdvertiser, RolePlatform, RoleSupport, RoleAdmin}
+
+func RoleHasPermission(role int, perm Permission) bool {
+	return slices.Contains(rolePermissions[role], perm)
+}
+
+// Require пропускает только роли, у которых есть хотя бы одно из прав perms.
+// Ставится после AuthMiddleware или ScopedAuthMiddleware
+func Require(perms ...Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := r.Context().Value(entity.UserContextKey).(entity.UserContext)
+			if !ok {
+				w.WriteHeader(http.StatusUnauthorized)
+				//nolint:errcheck
+				json.NewEncoder(w).Encode(entity.NewResponse(true, "user is not authenticated"))
+				return
+			}
+			if !slices.ContainsFunc(perms, func(perm Permission) bool { return RoleHasPermission(user.Role, perm) }) {
+				w.WriteHeader(http.StatusForbidden)
+				//nolint:errcheck
+				json.NewEncoder(w).Encode(entity.NewResponse(true, fmt.Sprintf("role %d has no permission %s", user.Role, perms[0])))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"retarget/pkg/entity"
+)
+
+func TestRoleHasPermission_Matrix(t *testing.T) {
+	allowed := map[Permission][]int{
+		PermBanners:      {RoleAdvertiser},
+		PermSlots:        {RolePlatform},
+		PermMetrics:      {RoleAdvertiser, RolePlatform},
+		PermPayments:     {RoleAdvertiser, RolePlatform},
+		PermProfile:      {RoleAdvertiser, RolePlatform, RoleSupport, RoleAdmin},
+		PermReviews:      {RoleAdvertiser, RolePlatform, RoleSupport, RoleAdmin},
+		PermReviewsAll:   {RoleSupport, RoleAdmin},
+		PermBillingAdmin: {RoleAdmin},
+	}
+	for perm, roles := range allowed {
+		for _, role := range append(slices.Clone(Roles), 0) {
+			if want, got := slices.Contains(roles, role), RoleHasPermission(role, perm); got != want {
+				t.Errorf("role %d, permission %s: expected %v, got %v", role, perm, want, got)
+			}
+		}
+	}
+}
+
+func TestRequire(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	serve := func(h http.Handler, user *entity.UserContext) int {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if user != nil {
+			r = r.WithContext(context.WithValue(r.Context(), entity.UserContextKey, *user))
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	if code := serve(Require(PermReviewsAll)(ok), nil); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without user, got %d", code)
+	}
+	if code := serve(Require(PermReviewsAll)(ok), &entity.UserContext{UserID: 7, Role: RoleAdvertiser}); code != http.StatusForbidden {
+		t.Errorf("expected advertiser forbidden, got %d", code)
+	}
+	if code := serve(Require(PermReviewsAll)(ok), &entity.UserContext{UserID: 7, Role: RoleSupport}); code != http.StatusOK {
+		t.Errorf("expected support allowed, got %d", code)
+	}
+	either := Require(PermPayments, PermBillingAdmin)(ok)
+	for role, want := range map[int]int{RolePlatform: http.StatusOK, RoleAdmin: http.StatusOK, RoleSupport: http.StatusForbidden} {
+		if code := serve(either, &entity.UserContext{UserID: 7, Role: role}); code != want {
+			t.Errorf("role %d: expected %d, got %d", role, want, code)
+		}
+	}
+}
@@ -19,7 +19,10 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	PaymentService_RegUserActivity_FullMethodName = "/paymentpb.PaymentService/RegUserActivity"
+	PaymentService_RegUserActivity_FullMethodName  = "/paymentpb.PaymentService/RegUserActivity"
+	PaymentService_ReserveSpend_FullMethodName     = "/paymentpb.PaymentService/ReserveSpend"
+	PaymentService_GetBalanceAlerts_FullMethodName = "/paymentpb.PaymentService/GetBalanceAlerts"
+	PaymentService_SetBalanceAlerts_FullMethodName = "/paymentpb.PaymentService/SetBalanceAlerts"
 )
 
 // PaymentServiceClient is the client API for PaymentService service.
@@ -27,6 +30,10 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type PaymentServiceClient interface {
 	RegUserActivity(ctx context.Context, in *PaymentRequest, opts ...grpc.CallOption) (*PaymentResponse, error)
+	ReserveSpend(ctx context.Context, in *PaymentRequest, opts ...grpc.CallOption) (*ReserveResponse, error)
+	// настройки уведомлений хранит pay-service, profile-service их только показывает и меняет
+	GetBalanceAlerts(ctx context.Context, in *BalanceAlertsRequest, opts ...grpc.CallOption) (*BalanceAlerts, error)
+	SetBalanceAlerts(ctx context.Context, in *BalanceAlerts, opts ...grpc.CallOption) (*BalanceAlerts, error)
 }
 
 type paymentServiceClient struct {
@@ -47,11 +54,45 @@ func (c *paymentServiceClient) RegUserActivity(ctx context.Context, in *PaymentR
 	return out, nil
 }
 
+func (c *paymentServiceClient) ReserveSpend(ctx context.Context, in *PaymentRequest, opts ...grpc.CallOption) (*ReserveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReserveResponse)
+	err := c.cc.Invoke(ctx, PaymentService_ReserveSpend_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) GetBalanceAlerts(ctx context.Context, in *BalanceAlertsRequest, opts ...grpc.CallOption) (*BalanceAlerts, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BalanceAlerts)
+	err := c.cc.Invoke(ctx, PaymentService_GetBalanceAlerts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) SetBalanceAlerts(ctx context.Context, in *BalanceAlerts, opts ...grpc.CallOption) (*BalanceAlerts, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BalanceAlerts)
+	err := c.cc.Invoke(ctx, PaymentService_SetBalanceAlerts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PaymentServiceServer is the server API for PaymentService service.
 // All implementations must embed UnimplementedPaymentServiceServer
 // for forward compatibility.
 type PaymentServiceServer interface {
 	RegUserActivity(context.Context, *PaymentRequest) (*PaymentResponse, error)
+	ReserveSpend(context.Context, *PaymentRequest) (*ReserveResponse, error)
+	// настройки уведомлений хранит pay-service, profile-service их только показывает и меняет
+	GetBalanceAlerts(context.Context, *BalanceAlertsRequest) (*BalanceAlerts, error)
+	SetBalanceAlerts(context.Context, *BalanceAlerts) (*BalanceAlerts, error)
 	mustEmbedUnimplementedPaymentServiceServer()
 }
 
@@ -65,6 +106,15 @@ type UnimplementedPaymentServiceServer struct{}
 func (UnimplementedPaymentServiceServer) RegUserActivity(context.Context, *PaymentRequest) (*PaymentResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RegUserActivity not implemented")
 }
+func (UnimplementedPaymentServiceServer) ReserveSpend(context.Context, *PaymentRequest) (*ReserveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReserveSpend not implemented")
+}
+func (UnimplementedPaymentServiceServer) GetBalanceAlerts(context.Context, *BalanceAlertsRequest) (*BalanceAlerts, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalanceAlerts not implemented")
+}
+func (UnimplementedPaymentServiceServer) SetBalanceAlerts(context.Context, *BalanceAlerts) (*BalanceAlerts, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetBalanceAlerts not implemented")
+}
 func (UnimplementedPaymentServiceServer) mustEmbedUnimplementedPaymentServiceServer() {}
 func (UnimplementedPaymentServiceServer) testEmbeddedByValue()                        {}
 
@@ -104,6 +154,60 @@ func _PaymentService_RegUserActivity_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PaymentService_ReserveSpend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).ReserveSpend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_ReserveSpend_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).ReserveSpend(ctx, req.(*PaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_GetBalanceAlerts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalanceAlertsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).GetBalanceAlerts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_GetBalanceAlerts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).GetBalanceAlerts(ctx, req.(*BalanceAlertsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_SetBalanceAlerts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalanceAlerts)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).SetBalanceAlerts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_SetBalanceAlerts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).SetBalanceAlerts(ctx, req.(*BalanceAlerts))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PaymentService_ServiceDesc is the grpc.ServiceDesc for PaymentService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,6 +219,18 @@ var PaymentService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RegUserActivity",
 			Handler:    _PaymentService_RegUserActivity_Handler,
 		},
+		{
+			MethodName: "ReserveSpend",
+			Handler:    _PaymentService_ReserveSpend_Handler,
+		},
+		{
+			MethodName: "GetBalanceAlerts",
+			Handler:    _PaymentService_GetBalanceAlerts_Handler,
+		},
+		{
+			MethodName: "SetBalanceAlerts",
+			Handler:    _PaymentService_SetBalanceAlerts_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/proto/payment/payment.proto",
@@ -0,0 +1,32 @@
+package throttle
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ClientIP возвращает адрес клиента — ключ для правил по адресу. Заголовкам
+// прокси верим, только если запрос пришёл из внутренней сети (nginx на хосте,
+// docker-сеть): иначе адрес подделал бы сам клиент. nil — адрес не разобран
+func ClientIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !(ip.IsLoopback() || ip.IsPrivate()) {
+		return ip
+	}
+
+	if realIP := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); realIP != nil {
+		return realIP
+	}
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		hops := strings.Split(forwarded, ",")
+		if hop := net.ParseIP(strings.TrimSpace(hops[len(hops)-1])); hop != nil {
+			return hop
+		}
+	}
+	return ip
+}
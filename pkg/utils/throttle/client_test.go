@@ -0,0 +1,44 @@
+package throttle
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	cases := []struct {
+		name       string
+		remoteAddr string
+		realIP     string
+		forwarded  string
+		want       string
+	}{
+		{name: "direct", remoteAddr: "8.8.8.8:41000", want: "8.8.8.8"},
+		{name: "spoofed header from outside", remoteAddr: "8.8.8.8:41000", realIP: "185.71.76.5", forwarded: "185.71.76.5", want: "8.8.8.8"},
+		{name: "trusted proxy real ip", remoteAddr: "127.0.0.1:41000", realIP: "1.2.3.4", want: "1.2.3.4"},
+		{name: "trusted proxy last hop", remoteAddr: "172.18.0.1:41000", forwarded: "1.2.3.4, 185.71.76.5", want: "185.71.76.5"},
+		{name: "trusted proxy without headers", remoteAddr: "10.0.0.7:41000", want: "10.0.0.7"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.RemoteAddr = tc.remoteAddr
+			if tc.realIP != "" {
+				req.Header.Set("X-Real-IP", tc.realIP)
+			}
+			if tc.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tc.forwarded)
+			}
+			if got := ClientIP(req); got.String() != tc.want {
+				t.Errorf("ClientIP() = %v, want %s", got, tc.want)
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.RemoteAddr = "garbage"
+	if got := ClientIP(req); got != nil {
+		t.Errorf("ClientIP() = %v, want nil", got)
+	}
+}
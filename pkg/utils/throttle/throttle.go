@@ -0,0 +1,191 @@
+// Package throttle — ограничение неудачных попыток для чувствительных
+// эндпоинтов (вход, коды, выплаты). Счётчики живут в Redis, поэтому лимит
+// общий для всех реплик сервиса
+package throttle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Policy — правила для одного вида ключей (почта, IP и т.п.)
+type Policy struct {
+	// FreeAttempts неудач подряд проходят без задержки
+	FreeAttempts int
+	// BaseDelay — задержка после первой неудачи сверх FreeAttempts,
+	// каждая следующая удваивает её вплоть до MaxDelay (0 — без роста)
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// LockoutAfter неудач подряд блокируют ключ на LockoutFor; 0 — без блокировки
+	LockoutAfter int
+	LockoutFor   time.Duration
+	// Window — сколько помнится неудача без новых попыток
+	Window time.Duration
+}
+
+// Delay — задержка после failures неудач подряд
+func (p Policy) Delay(failures int) time.Duration {
+	over := failures - p.FreeAttempts
+	if over <= 0 || p.BaseDelay <= 0 {
+		return 0
+	}
+	delay := p.BaseDelay
+	for i := 1; i < over && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// Error — попытка отклонена до проверки. RetryAfter — когда можно повторить
+type Error struct {
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("слишком много неудачных попыток, доступ заблокирован на %s", e.RetryAfter.Round(time.Second))
+	}
+	return fmt.Sprintf("слишком много попыток, повторите через %s", e.RetryAfter.Round(time.Second))
+}
+
+// IsThrottled сообщает, что err — отказ лимитера, и возвращает его
+func IsThrottled(err error) (*Error, bool) {
+	var limited *Error
+	ok := errors.As(err, &limited)
+	return limited, ok
+}
+
+// Result — итог учёта неудачной попытки
+type Result struct {
+	Failures int
+	// Locked выставляется ровно один раз — на неудаче, которая заблокировала
+	// ключ, поэтому по нему удобно слать уведомление
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+const (
+	blockWait = "wait"
+	blockLock = "lock"
+)
+
+// Limiter — подключение к Redis, общее для всех правил сервиса
+type Limiter struct {
+	client *redis.Client
+}
+
+func NewLimiter(endPoint string, password string, db int) (*Limiter, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     endPoint,
+		Password: password,
+		DB:       db,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("error connect to Redis: %w", err)
+	}
+	return &Limiter{client: client}, nil
+}
+
+func (l *Limiter) CloseConnection() error {
+	if l.client != nil {
+		return l.client.Close()
+	}
+	return nil
+}
+
+// Rule — правило policy для ключей с префиксом prefix, например «login_email».
+// У nil-лимитера правило тоже nil
+func (l *Limiter) Rule(prefix string, policy Policy) *Rule {
+	if l == nil {
+		return nil
+	}
+	return &Rule{client: l.client, prefix: prefix, policy: policy}
+}
+
+type Rule struct {
+	client *redis.Client
+	prefix string
+	policy Policy
+}
+
+func (r *Rule) failuresKey(key string) string {
+	return fmt.Sprintf("throttle:%s:%s:failures", r.prefix, key)
+}
+
+func (r *Rule) blockKey(key string) string {
+	return fmt.Sprintf("throttle:%s:%s:block", r.prefix, key)
+}
+
+// Check отклоняет попытку *Error, если ключ ждёт окончания задержки или заблокирован
+func (r *Rule) Check(ctx context.Context, key string) error {
+	pipe := r.client.Pipeline()
+	state := pipe.Get(ctx, r.blockKey(key))
+	ttl := pipe.PTTL(ctx, r.blockKey(key))
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+
+	block, err := state.Result()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	retryAfter := ttl.Val()
+	if retryAfter <= 0 {
+		return nil
+	}
+	return &Error{Locked: block == blockLock, RetryAfter: retryAfter}
+}
+
+// Fail учитывает неудачную попытку и, если пора, ставит задержку или блокировку
+func (r *Rule) Fail(ctx context.Context, key string) (Result, error) {
+	failuresKey := r.failuresKey(key)
+
+	pipe := r.client.TxPipeline()
+	incr := pipe.Incr(ctx, failuresKey)
+	pipe.PExpire(ctx, failuresKey, r.policy.Window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return Result{}, err
+	}
+	failures := int(incr.Val())
+
+	if r.policy.LockoutAfter > 0 && failures >= r.policy.LockoutAfter {
+		// после блокировки счёт начинается заново. Параллельные неудачи сверх
+		// порога продлевают блокировку, но Locked не повторяют
+		_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, r.blockKey(key), blockLock, r.policy.LockoutFor)
+			pipe.Del(ctx, failuresKey)
+			return nil
+		})
+		if err != nil {
+			return Result{}, err
+		}
+		return Result{Failures: failures, Locked: failures == r.policy.LockoutAfter, RetryAfter: r.policy.LockoutFor}, nil
+	}
+
+	delay := r.policy.Delay(failures)
+	if delay > 0 {
+		if err := r.client.Set(ctx, r.blockKey(key), blockWait, delay).Err(); err != nil {
+			return Result{}, err
+		}
+	}
+	return Result{Failures: failures, RetryAfter: delay}, nil
+}
+
+// Reset забывает неудачи ключа после успешной попытки. Блокировку не снимает
+func (r *Rule) Reset(ctx context.Context, key string) error {
+	return r.client.Del(ctx, r.failuresKey(key)).Err()
+}
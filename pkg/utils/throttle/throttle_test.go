@@ -0,0 +1,85 @@
+package throttle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func setupRule(t *testing.T, policy Policy) (*Rule, *miniredis.Miniredis) {
+	s, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("miniredis start failed: %v", err)
+	}
+	t.Cleanup(s.Close)
+	limiter, err := NewLimiter(s.Addr(), "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return limiter.Rule("test", policy), s
+}
+
+func TestPolicyDelay(t *testing.T) {
+	p := Policy{FreeAttempts: 2, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	cases := map[int]time.Duration{1: 0, 2: 0, 3: time.Second, 4: 2 * time.Second, 5: 4 * time.Second, 6: 5 * time.Second, 40: 5 * time.Second}
+	for failures, want := range cases {
+		if got := p.Delay(failures); got != want {
+			t.Errorf("Delay(%d) = %v, want %v", failures, got, want)
+		}
+	}
+}
+
+func TestRule_BackoffAndLockout(t *testing.T) {
+	ctx := context.Background()
+	rule, s := setupRule(t, Policy{FreeAttempts: 1, BaseDelay: time.Second, MaxDelay: time.Minute, LockoutAfter: 3, LockoutFor: time.Hour, Window: time.Hour})
+
+	if res, err := rule.Fail(ctx, "k"); err != nil || res.RetryAfter != 0 {
+		t.Fatalf("expected free attempt, got %+v, %v", res, err)
+	}
+	if err := rule.Check(ctx, "k"); err != nil {
+		t.Fatalf("expected allowed, got %v", err)
+	}
+
+	if res, err := rule.Fail(ctx, "k"); err != nil || res.RetryAfter != time.Second || res.Locked {
+		t.Fatalf("expected backoff, got %+v, %v", res, err)
+	}
+	limited, ok := IsThrottled(rule.Check(ctx, "k"))
+	if !ok || limited.Locked {
+		t.Fatalf("expected delay, got %+v", limited)
+	}
+	s.FastForward(2 * time.Second)
+	if err := rule.Check(ctx, "k"); err != nil {
+		t.Fatalf("expected delay elapsed, got %v", err)
+	}
+
+	if res, err := rule.Fail(ctx, "k"); err != nil || !res.Locked {
+		t.Fatalf("expected lockout, got %+v, %v", res, err)
+	}
+	limited, ok = IsThrottled(rule.Check(ctx, "k"))
+	if !ok || !limited.Locked || limited.RetryAfter <= 0 {
+		t.Fatalf("expected locked, got %+v", limited)
+	}
+	if err := rule.Check(ctx, "other"); err != nil {
+		t.Errorf("expected other keys unaffected, got %v", err)
+	}
+
+	s.FastForward(time.Hour)
+	if err := rule.Check(ctx, "k"); err != nil {
+		t.Errorf("expected lockout to expire, got %v", err)
+	}
+}
+
+func TestRule_Reset(t *testing.T) {
+	ctx := context.Background()
+	rule, _ := setupRule(t, Policy{FreeAttempts: 1, BaseDelay: time.Second, MaxDelay: time.Minute, Window: time.Hour})
+
+	rule.Fail(ctx, "k")
+	if err := rule.Reset(ctx, "k"); err != nil {
+		t.Fatal(err)
+	}
+	if res, _ := rule.Fail(ctx, "k"); res.Failures != 1 || res.RetryAfter != 0 {
+		t.Errorf("expected counter reset, got %+v", res)
+	}
+}
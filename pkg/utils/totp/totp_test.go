@@ -0,0 +1,67 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Векторы из приложения D RFC 4226 для секрета "12345678901234567890"
+func TestHOTP_RFC4226(t *testing.T) {
+	key := []byte("12345678901234567890")
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for counter, code := range want {
+		if got := hotp(key, uint64(counter), 6); got != code {
+			t.Errorf("counter %d: expected %s, got %s", counter, code, got)
+		}
+	}
+}
+
+// Векторы SHA-1 из приложения B RFC 6238 (8 цифр)
+func TestTOTP_RFC6238(t *testing.T) {
+	key := []byte("12345678901234567890")
+	cases := map[int64]string{
+		59:          "94287082",
+		1111111109:  "07081804",
+		1111111111:  "14050471",
+		1234567890:  "89005924",
+		2000000000:  "69279037",
+		20000000000: "65353130",
+	}
+	for unix, code := range cases {
+		if got := hotp(key, uint64(Step(time.Unix(unix, 0))), 8); got != code {
+			t.Errorf("time %d: expected %s, got %s", unix, code, got)
+		}
+	}
+}
+
+func TestValidate_Skew(t *testing.T) {
+	secret := strings.TrimRight(base32.StdEncoding.EncodeToString([]byte("12345678901234567890")), "=")
+	now := time.Unix(1111111109, 0)
+
+	code, err := Code(secret, now.Add(-Period))
+	if err != nil {
+		t.Fatal(err)
+	}
+	step, ok := Validate(secret, code, now)
+	if !ok || step != Step(now)-1 {
+		t.Errorf("expected previous step accepted, got %d %v", step, ok)
+	}
+
+	old, _ := Code(secret, now.Add(-3*Period))
+	if _, ok := Validate(secret, old, now); ok {
+		t.Error("expected code three steps old rejected")
+	}
+	if _, ok := Validate(secret, "12345", now); ok {
+		t.Error("expected short code rejected")
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("ReTarget", "user@example.com", "JBSWY3DPEHPK3PXP")
+	if !strings.HasPrefix(uri, "otpauth://totp/ReTarget:user@example.com?") ||
+		!strings.Contains(uri, "secret=JBSWY3DPEHPK3PXP") || !strings.Contains(uri, "issuer=ReTarget") {
+		t.Errorf("unexpected uri %s", uri)
+	}
+}
@@ -18,7 +18,7 @@ func init() {
 		if !ok || decimal.Dec == nil {
 			return false
 		}
-		threshold := inf.NewDec(1, 1) // 0.1
+		threshold := entity.Decimal{Dec: inf.NewDec(1, 1)} // 0.1
 		return decimal.Cmp(threshold) == 1
 	})
 	if err != nil {